@@ -19,14 +19,19 @@ package baseimage
 import (
 	"fmt"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/build/base"
+	"sigs.k8s.io/kind/pkg/docker"
 )
 
 type flagpole struct {
-	Source string
-	Image  string
+	Source           string
+	Image            string
+	Archs            []string
+	Push             bool
+	InstallEmulation bool
 }
 
 // NewCommand returns a new cobra.Command for building the base image
@@ -51,14 +56,75 @@ func NewCommand() *cobra.Command {
 		base.DefaultImage,
 		"name:tag of the resulting image to be built",
 	)
+	cmd.Flags().StringSliceVar(
+		&flags.Archs, "arch",
+		nil,
+		fmt.Sprintf("architectures to build the image for, any of %v (default the host architecture), with more than one the image of each architecture is tagged with the architecture suffix", docker.SupportedArchs),
+	)
+	cmd.Flags().BoolVar(
+		&flags.Push, "push",
+		false,
+		"push the built images, and with more than one architecture a multi-arch manifest list named --image referencing them",
+	)
+	cmd.Flags().BoolVar(
+		&flags.InstallEmulation, "install-emulation",
+		false,
+		"install the QEMU emulation needed to build for the --arch architectures other than the host's first, by running the privileged "+docker.BinfmtImage+" container",
+	)
 	return cmd
 }
 
 func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	for _, arch := range flags.Archs {
+		if !docker.SupportedArch(arch) {
+			return fmt.Errorf("unsupported architecture %q, must be one of %v", arch, docker.SupportedArchs)
+		}
+	}
+	if foreign := docker.ForeignArchs(flags.Archs); flags.InstallEmulation && len(foreign) > 0 {
+		log.Infof("Installing QEMU emulation for %v", foreign)
+		if err := docker.InstallEmulation(foreign...); err != nil {
+			return fmt.Errorf("error installing QEMU emulation: %v", err)
+		}
+	}
+	if len(flags.Archs) <= 1 {
+		arch := ""
+		if len(flags.Archs) == 1 {
+			arch = flags.Archs[0]
+		}
+		if err := build(flags, flags.Image, arch); err != nil {
+			return err
+		}
+		if flags.Push {
+			return docker.Push(flags.Image)
+		}
+		return nil
+	}
+
+	// the image of each architecture is tagged separately, and referenced
+	// by the manifest list once pushed
+	images := []string{}
+	for _, arch := range flags.Archs {
+		image := docker.ArchImage(flags.Image, arch)
+		log.Infof("Building base image %s for %s", image, arch)
+		if err := build(flags, image, arch); err != nil {
+			return err
+		}
+		images = append(images, image)
+	}
+	if !flags.Push {
+		log.Infof("Built %v, the multi-arch manifest list %s is created only with --push", images, flags.Image)
+		return nil
+	}
+	return docker.PushMultiArch(flags.Image, images...)
+}
+
+// build builds the base image tagged image for arch
+func build(flags *flagpole, image, arch string) error {
 	// TODO(bentheelder): make this more configurable
 	ctx := base.NewBuildContext(
-		base.WithImage(flags.Image),
+		base.WithImage(image),
 		base.WithSourceDir(flags.Source),
+		base.WithArch(arch),
 	)
 	if err := ctx.Build(); err != nil {
 		return fmt.Errorf("build failed: %v", err)
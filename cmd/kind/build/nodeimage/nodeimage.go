@@ -18,10 +18,17 @@ package nodeimage
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/build/node"
+	"sigs.k8s.io/kind/pkg/docker"
 )
 
 type flagpole struct {
@@ -30,6 +37,17 @@ type flagpole struct {
 	Image     string
 	BaseImage string
 	KubeRoot  string
+	Archs     []string
+	Push      bool
+	CacheDir  string
+	NoCache   bool
+	// KubernetesVersion is the release or CI version to build from
+	KubernetesVersion string
+	ExtraImages       []string
+	Manifests         []string
+	InstallEmulation  bool
+	Reproducible      bool
+	SBOM              string
 }
 
 // NewCommand returns a new cobra.Command for building the node image
@@ -46,7 +64,7 @@ func NewCommand() *cobra.Command {
 	}
 	cmd.Flags().StringVar(
 		&flags.BuildType, "type",
-		"docker", "build type, one of [bazel, docker, apt]",
+		"docker", "build type, one of [bazel, docker, apt], bazel and docker build the --kube-root sources",
 	)
 	cmd.Flags().StringVar(
 		&flags.Image, "image",
@@ -56,24 +74,153 @@ func NewCommand() *cobra.Command {
 	cmd.Flags().StringVar(
 		&flags.KubeRoot, "kube-root",
 		"",
-		"Path to the Kubernetes source directory (if empty, the path is autodetected)",
+		"Path to the Kubernetes source directory (if empty, the path is autodetected from the GOPATH or the current directory)",
 	)
 	cmd.Flags().StringVar(
 		&flags.BaseImage, "base-image",
 		node.DefaultBaseImage,
-		"name:tag of the base image to use for the build",
+		"name:tag of the base image to use for the build, it is validated to provide systemd, a container runtime and the tools Kubernetes needs",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.Archs, "arch",
+		nil,
+		fmt.Sprintf("architectures to build the image for, any of %v (default the host architecture), with more than one the image of each architecture is tagged with the architecture suffix", docker.SupportedArchs),
+	)
+	cmd.Flags().BoolVar(
+		&flags.Push, "push",
+		false,
+		"push the built images, and with more than one architecture a multi-arch manifest list named --image referencing them",
+	)
+	cmd.Flags().StringVar(
+		&flags.KubernetesVersion, "kubernetes-version",
+		"",
+		"build from the published artifacts of a Kubernetes version rather than from source, e.g. v1.13.4, a release marker like stable-1.13 or a CI build like ci/latest",
+	)
+	cmd.Flags().BoolVar(
+		&flags.InstallEmulation, "install-emulation",
+		false,
+		"install the QEMU emulation needed to build for the --arch architectures other than the host's first, by running the privileged "+docker.BinfmtImage+" container",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.ExtraImages, "extra-image",
+		nil,
+		"image reference or image archive to build into the node image, for the clusters to come up with it preloaded, may be repeated",
+	)
+	cmd.Flags().StringSliceVar(
+		&flags.Manifests, "manifest",
+		nil,
+		"manifest file, directory or http(s) URL to build into the node image, applied in order when a cluster is created, may be repeated",
+	)
+	cmd.Flags().BoolVar(
+		&flags.Reproducible, "reproducible",
+		false,
+		"build reproducibly from pinned inputs, with the times in the image set to $SOURCE_DATE_EPOCH (default 0)",
+	)
+	cmd.Flags().StringVar(
+		&flags.SBOM, "sbom",
+		"",
+		"path to write the SBOM of the image to as JSON, with more than one architecture the path of each is suffixed with the architecture",
+	)
+	cmd.Flags().StringVar(
+		&flags.CacheDir, "cache-dir",
+		node.DefaultCacheDir(),
+		"directory caching the pulled images by Kubernetes version, the base setup and the built node image are cached as local "+node.CacheImageRepository+" images",
+	)
+	cmd.Flags().BoolVar(
+		&flags.NoCache, "no-cache",
+		false,
+		"build without the cache, neither using nor updating it",
 	)
 	return cmd
 }
 
 func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
-	// TODO(bentheelder): make this more configurable
-	ctx, err := node.NewBuildContext(
+	if flags.KubernetesVersion != "" && (cmd.Flags().Changed("type") || cmd.Flags().Changed("kube-root")) {
+		return fmt.Errorf("--kubernetes-version builds from the published artifacts and can not be used with --type or --kube-root")
+	}
+	for _, arch := range flags.Archs {
+		if !docker.SupportedArch(arch) {
+			return fmt.Errorf("unsupported architecture %q, must be one of %v", arch, docker.SupportedArchs)
+		}
+	}
+	if foreign := docker.ForeignArchs(flags.Archs); flags.InstallEmulation && len(foreign) > 0 {
+		log.Infof("Installing QEMU emulation for %v", foreign)
+		if err := docker.InstallEmulation(foreign...); err != nil {
+			return fmt.Errorf("error installing QEMU emulation: %v", err)
+		}
+	}
+	if len(flags.Archs) <= 1 {
+		arch := ""
+		if len(flags.Archs) == 1 {
+			arch = flags.Archs[0]
+		}
+		if err := build(flags, flags.Image, arch); err != nil {
+			return err
+		}
+		if flags.Push {
+			return docker.Push(flags.Image)
+		}
+		log.Infof("Built %s, create a cluster with it with: kind create cluster --image %s", flags.Image, flags.Image)
+		return nil
+	}
+
+	// the image of each architecture is tagged separately, and referenced
+	// by the manifest list once pushed
+	images := []string{}
+	for _, arch := range flags.Archs {
+		image := node.ArchImage(flags.Image, arch)
+		log.Infof("Building node image %s for %s", image, arch)
+		if err := build(flags, image, arch); err != nil {
+			return err
+		}
+		images = append(images, image)
+	}
+	if !flags.Push {
+		log.Infof("Built %v, the multi-arch manifest list %s is created only with --push", images, flags.Image)
+		return nil
+	}
+	return docker.PushMultiArch(flags.Image, images...)
+}
+
+// build builds the node image tagged image for arch
+func build(flags *flagpole, image, arch string) error {
+	kubeRoot := flags.KubeRoot
+	// the shell does not expand ~ in --kube-root=~/...
+	if strings.HasPrefix(kubeRoot, "~/") {
+		kubeRoot = filepath.Join(os.Getenv("HOME"), kubeRoot[2:])
+	}
+	cacheDir := flags.CacheDir
+	if flags.NoCache {
+		cacheDir = ""
+	}
+	options := []node.Option{
 		node.WithMode(flags.BuildType),
-		node.WithImage(flags.Image),
+		node.WithImage(image),
 		node.WithBaseImage(flags.BaseImage),
-		node.WithKuberoot(flags.KubeRoot),
-	)
+		node.WithKuberoot(kubeRoot),
+		node.WithArch(arch),
+		node.WithCacheDir(cacheDir),
+		node.WithKubernetesVersion(flags.KubernetesVersion),
+		node.WithExtraImages(flags.ExtraImages...),
+		node.WithManifests(flags.Manifests...),
+	}
+	if flags.Reproducible {
+		epoch, err := sourceDateEpoch()
+		if err != nil {
+			return err
+		}
+		options = append(options, node.WithReproducible(epoch))
+	}
+	if flags.SBOM != "" {
+		sbom := flags.SBOM
+		if len(flags.Archs) > 1 {
+			ext := filepath.Ext(sbom)
+			sbom = fmt.Sprintf("%s-%s%s", strings.TrimSuffix(sbom, ext), arch, ext)
+		}
+		options = append(options, node.WithSBOM(sbom))
+	}
+	// TODO(bentheelder): make this more configurable
+	ctx, err := node.NewBuildContext(options...)
 	if err != nil {
 		return fmt.Errorf("error creating build context: %v", err)
 	}
@@ -82,3 +229,17 @@ func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// sourceDateEpoch returns the time of the SOURCE_DATE_EPOCH environment
+// variable, in seconds since the Unix epoch, or the Unix epoch if unset
+func sourceDateEpoch() (time.Time, error) {
+	value := os.Getenv("SOURCE_DATE_EPOCH")
+	if value == "" {
+		return time.Unix(0, 0), nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %v", value, err)
+	}
+	return time.Unix(seconds, 0), nil
+}
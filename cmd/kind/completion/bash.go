@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const bashFunctions = `__kind_get_clusters()
+{
+    local clusters
+    if clusters=$(kind get clusters 2>/dev/null); then
+        COMPREPLY=( $( compgen -W "${clusters}" -- "$cur" ) )
+    fi
+}
+
+__custom_func()
+{
+    case ${last_command} in
+        %s)
+            __kind_get_clusters
+            return
+            ;;
+    esac
+}
+`
+
+// genBash writes the bash completion script of root to w, the completion of
+// the cluster names is hooked in the script generated by cobra
+func genBash(root *cobra.Command, w io.Writer) error {
+	withClusterArgs := []string{}
+	for _, cmd := range commands(root) {
+		if argsAreClusters(cmd) {
+			withClusterArgs = append(withClusterArgs, bashCommandName(cmd))
+		}
+	}
+	if len(withClusterArgs) == 0 {
+		// a case pattern can not be empty, nothing will match this one
+		withClusterArgs = append(withClusterArgs, "__kind_none")
+	}
+	root.BashCompletionFunction = fmt.Sprintf(bashFunctions, strings.Join(withClusterArgs, " | "))
+	return root.GenBashCompletion(w)
+}
+
+// bashCommandName returns the name of cmd in the script generated by cobra
+func bashCommandName(cmd *cobra.Command) string {
+	name := strings.Replace(cmd.CommandPath(), " ", "_", -1)
+	return strings.Replace(name, ":", "__", -1)
+}
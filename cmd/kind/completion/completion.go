@@ -0,0 +1,161 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package completion implements the `completion` command
+package completion
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// clusterNamesFunc is the shell function completing the names of the
+// existing clusters, it is also the marker for the flags and arguments taking
+// a cluster name
+const clusterNamesFunc = "__kind_get_clusters"
+
+// argsAnnotation is the command annotation marking the arguments as cluster
+// names
+const argsAnnotation = "kind_completion_args"
+
+// Shells are the shells completion scripts can be generated for
+var Shells = []string{"bash", "zsh", "fish"}
+
+// MarkClusterNameFlag marks the flag name of cmd as taking the name of an
+// existing cluster, for its values to be completed with the cluster names
+func MarkClusterNameFlag(cmd *cobra.Command, name string) {
+	if err := cobra.MarkFlagCustom(cmd.Flags(), name, clusterNamesFunc); err != nil {
+		panic(err)
+	}
+}
+
+// MarkClusterNameArgs marks the arguments of cmd as names of existing
+// clusters, for them to be completed with the cluster names
+func MarkClusterNameArgs(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[argsAnnotation] = clusterNamesFunc
+}
+
+// NewCommand returns a new cobra.Command for generating completion scripts
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "completion [bash|zsh|fish]",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: Shells,
+		Short:     "Outputs the shell completion script for kind",
+		Long: `Outputs the shell completion script for kind, completing the commands, the flags and the names of the existing clusters
+
+To load the completions in the current shell:
+
+  bash: source <(kind completion bash)
+  zsh:  source <(kind completion zsh)
+  fish: kind completion fish | source
+
+To load them in every new shell, write the script to the completions directory
+of the shell, e.g. kind completion fish > ~/.config/fish/completions/kind.fish`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(cmd.Root(), os.Stdout, args[0])
+		},
+	}
+	return cmd
+}
+
+func runE(root *cobra.Command, w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		return genBash(root, w)
+	case "zsh":
+		return genZsh(root, w)
+	case "fish":
+		return genFish(root, w)
+	}
+	return fmt.Errorf("unsupported shell %q, expected one of %v", shell, Shells)
+}
+
+// commands returns cmd and all of its available subcommands, depth first
+func commands(cmd *cobra.Command) []*cobra.Command {
+	all := []*cobra.Command{cmd}
+	for _, sub := range subcommands(cmd) {
+		all = append(all, commands(sub)...)
+	}
+	return all
+}
+
+// subcommands returns the available subcommands of cmd
+func subcommands(cmd *cobra.Command) []*cobra.Command {
+	subs := []*cobra.Command{}
+	for _, sub := range cmd.Commands() {
+		if sub.IsAvailableCommand() && sub.Name() != "help" {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+// flags returns the visible flags of cmd, including the inherited ones,
+// sorted by name
+func flags(cmd *cobra.Command) []*pflag.Flag {
+	all := []*pflag.Flag{}
+	add := func(flag *pflag.Flag) {
+		if !flag.Hidden {
+			all = append(all, flag)
+		}
+	}
+	cmd.LocalFlags().VisitAll(add)
+	cmd.InheritedFlags().VisitAll(add)
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// takesValue returns true if flag must be followed by a value
+func takesValue(flag *pflag.Flag) bool {
+	return flag.NoOptDefVal == ""
+}
+
+// completesClusters returns true if flag takes the name of an existing cluster
+func completesClusters(flag *pflag.Flag) bool {
+	values := flag.Annotations[cobra.BashCompCustom]
+	return len(values) == 1 && values[0] == clusterNamesFunc
+}
+
+// argsAreClusters returns true if the arguments of cmd are cluster names
+func argsAreClusters(cmd *cobra.Command) bool {
+	return cmd.Annotations[argsAnnotation] == clusterNamesFunc
+}
+
+// firstLine returns the first line of the description s
+func firstLine(s string) string {
+	return strings.SplitN(s, "\n", 2)[0]
+}
+
+// quote single quotes s for bash and zsh
+func quote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// fishQuote single quotes s for fish, where backslashes escape in quotes
+func fishQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return "'" + strings.Replace(s, "'", `\'`, -1) + "'"
+}
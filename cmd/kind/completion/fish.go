@@ -0,0 +1,91 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const fishHeader = `function __%[1]s_get_clusters
+    kind get clusters 2>/dev/null
+end
+
+# __%[1]s_command prints the path of the command being completed, the words
+# that are not flags nor commands, like flag values, are skipped
+function __%[1]s_command
+    set -l words (commandline -opc)
+    set -l cmd %[1]s
+    for word in $words[2..-1]
+        string match -q -- '-*' $word; and continue
+        contains -- "$cmd $word" $__%[1]s_commands; and set cmd "$cmd $word"
+    end
+    echo $cmd
+end
+
+complete -c %[1]s -e
+complete -c %[1]s -f
+`
+
+// genFish writes the fish completion script of root to w
+func genFish(root *cobra.Command, w io.Writer) error {
+	buf := &bytes.Buffer{}
+	name := root.Name()
+	paths := []string{}
+	for _, cmd := range commands(root)[1:] {
+		paths = append(paths, fishQuote(cmd.CommandPath()))
+	}
+	fmt.Fprintf(buf, "set -g __%s_commands %s\n\n", name, strings.Join(paths, " "))
+	fmt.Fprintf(buf, fishHeader, name)
+	for _, cmd := range commands(root) {
+		writeFishCommand(buf, cmd)
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// writeFishCommand writes the completions of the flags, subcommands and
+// arguments of cmd
+func writeFishCommand(buf *bytes.Buffer, cmd *cobra.Command) {
+	name := cmd.Root().Name()
+	prefix := fmt.Sprintf("complete -c %s -n %s", name, fishQuote(fmt.Sprintf("test (__%s_command) = %q", name, cmd.CommandPath())))
+	buf.WriteString("\n")
+	for _, flag := range flags(cmd) {
+		line := prefix + " -l " + flag.Name
+		if flag.Shorthand != "" {
+			line += " -s " + flag.Shorthand
+		}
+		if completesClusters(flag) {
+			line += fmt.Sprintf(" -x -a '(__%s_get_clusters)'", name)
+		} else if takesValue(flag) {
+			line += " -r -F"
+		}
+		buf.WriteString(line + " -d " + fishQuote(firstLine(flag.Usage)) + "\n")
+	}
+	for _, sub := range subcommands(cmd) {
+		fmt.Fprintf(buf, "%s -a %s -d %s\n", prefix, sub.Name(), fishQuote(firstLine(sub.Short)))
+	}
+	if argsAreClusters(cmd) {
+		fmt.Fprintf(buf, "%s -a '(__%s_get_clusters)'\n", prefix, name)
+	} else if len(subcommands(cmd)) == 0 {
+		fmt.Fprintf(buf, "%s -F\n", prefix)
+	}
+}
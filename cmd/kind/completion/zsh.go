@@ -0,0 +1,135 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const zshHeader = `
+__%[1]s_get_clusters()
+{
+    local -a clusters
+    clusters=(${(f)"$(kind get clusters 2>/dev/null)"})
+    compadd -a clusters
+}
+
+# __%[1]s_command prints the path of the command being completed, the words
+# that are not flags nor commands, like flag values, are skipped
+__%[1]s_command()
+{
+    local cmd=%[1]s word
+    for word in ${words[2,CURRENT-1]}; do
+        [[ $word == -* ]] && continue
+        (( ${__%[1]s_commands[(Ie)$cmd $word]} )) && cmd="$cmd $word"
+    done
+    print -r -- "$cmd"
+}
+
+_%[1]s()
+{
+    local cur=${words[CURRENT]} prev=${words[CURRENT-1]}
+    local -a commands flags
+    case "$(__%[1]s_command)" in
+`
+
+const zshFooter = `    esac
+}
+
+if [[ "$funcstack[1]" == "_%[1]s" ]]; then
+    _%[1]s "$@"
+else
+    compdef _%[1]s %[1]s
+fi
+`
+
+// genZsh writes the zsh completion script of root to w
+func genZsh(root *cobra.Command, w io.Writer) error {
+	buf := &bytes.Buffer{}
+	name := root.Name()
+	paths := []string{}
+	for _, cmd := range commands(root)[1:] {
+		paths = append(paths, quote(cmd.CommandPath()))
+	}
+	fmt.Fprintf(buf, "#compdef %s\n\n", name)
+	fmt.Fprintf(buf, "__%s_commands=(%s)\n", name, strings.Join(paths, " "))
+	fmt.Fprintf(buf, zshHeader, name)
+	for _, cmd := range commands(root) {
+		writeZshCommand(buf, cmd)
+	}
+	fmt.Fprintf(buf, zshFooter, name)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// writeZshCommand writes the case completing the flags and arguments of cmd
+func writeZshCommand(buf *bytes.Buffer, cmd *cobra.Command) {
+	fmt.Fprintf(buf, "        %s)\n", quote(cmd.CommandPath()))
+	clusterFlags, valueFlags := []string{}, []string{}
+	entries := []string{}
+	for _, flag := range flags(cmd) {
+		names := []string{"--" + flag.Name}
+		if flag.Shorthand != "" {
+			names = append(names, "-"+flag.Shorthand)
+		}
+		for _, name := range names {
+			entries = append(entries, quote(name+":"+firstLine(flag.Usage)))
+		}
+		if completesClusters(flag) {
+			clusterFlags = append(clusterFlags, names...)
+		} else if takesValue(flag) {
+			valueFlags = append(valueFlags, names...)
+		}
+	}
+	if len(clusterFlags)+len(valueFlags) > 0 {
+		buf.WriteString("            case $prev in\n")
+		if len(clusterFlags) > 0 {
+			fmt.Fprintf(buf, "                %s) __%s_get_clusters; return ;;\n", strings.Join(clusterFlags, "|"), cmd.Root().Name())
+		}
+		if len(valueFlags) > 0 {
+			fmt.Fprintf(buf, "                %s) _files; return ;;\n", strings.Join(valueFlags, "|"))
+		}
+		buf.WriteString("            esac\n")
+	}
+	if len(entries) > 0 {
+		fmt.Fprintf(buf, "            if [[ $cur == -* ]]; then\n")
+		fmt.Fprintf(buf, "                flags=(%s)\n", strings.Join(entries, " "))
+		fmt.Fprintf(buf, "                _describe -t flags 'flag' flags\n")
+		fmt.Fprintf(buf, "                return\n")
+		fmt.Fprintf(buf, "            fi\n")
+	}
+	subs := subcommands(cmd)
+	switch {
+	case len(subs) > 0:
+		entries := []string{}
+		for _, sub := range subs {
+			entries = append(entries, quote(sub.Name()+":"+firstLine(sub.Short)))
+		}
+		fmt.Fprintf(buf, "            commands=(%s)\n", strings.Join(entries, " "))
+		buf.WriteString("            _describe -t commands 'command' commands\n")
+	case argsAreClusters(cmd):
+		fmt.Fprintf(buf, "            __%s_get_clusters\n", cmd.Root().Name())
+	default:
+		buf.WriteString("            _files\n")
+	}
+	buf.WriteString("            ;;\n")
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config implements the `config` command
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster/config/encoding"
+)
+
+type flagpole struct {
+	File string
+}
+
+// NewCommand returns a new cobra.Command for converting a config file
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "config",
+		Short: "prints the config file converted to the latest config API version",
+		Long: fmt.Sprintf(
+			"prints the config file converted to the latest config API version (%s).\n"+
+				"Every document in the file is converted, defaults are not applied.",
+			encoding.LatestVersion,
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVarP(&flags.File, "file", "f", "", "path to the kind config file to convert")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	if flags.File == "" {
+		return fmt.Errorf("a config file is required, please set --file")
+	}
+	converted, err := encoding.Convert(flags.File)
+	if err != nil {
+		return fmt.Errorf("error converting config: %v", err)
+	}
+	_, err = os.Stdout.Write(converted)
+	return err
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cp implements the `cp` command
+package cp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+type flagpole struct {
+	Name string
+}
+
+// NewCommand returns a new cobra.Command for copying files to and from nodes
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.ExactArgs(2),
+		Use:   "cp [flags] NODE:SRC_PATH DEST_PATH | SRC_PATH NODE:DEST_PATH",
+		Short: "Copies files or directories between the host and a node of the kind cluster by --name",
+		Long: "Copies files or directories between the host and a node of the kind cluster by --name, like docker cp\n\n" +
+			"NODE is the node replica name e.g. worker2, the node container name, or a role e.g. worker for the first node with the role.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", "1", "the cluster context name")
+	completion.MarkClusterNameFlag(cmd, "name")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	srcNode, srcPath := splitNodePath(args[0])
+	destNode, destPath := splitNodePath(args[1])
+	if (srcNode == "") == (destNode == "") {
+		return fmt.Errorf("exactly one of the paths must be on a node, as NODE:PATH")
+	}
+	ctx := cluster.NewContext(flags.Name)
+	if srcNode != "" {
+		node, err := ctx.Node(srcNode)
+		if err != nil {
+			return err
+		}
+		if err := node.CopyFrom(srcPath, destPath); err != nil {
+			return fmt.Errorf("failed to copy %s from node %s: %v", srcPath, node, err)
+		}
+		return nil
+	}
+	node, err := ctx.Node(destNode)
+	if err != nil {
+		return err
+	}
+	if err := node.CopyTo(srcPath, destPath); err != nil {
+		return fmt.Errorf("failed to copy %s to node %s: %v", srcPath, node, err)
+	}
+	return nil
+}
+
+// splitNodePath splits NODE:PATH, the node is empty for host paths, which
+// may contain a colon after a slash, e.g. ./a:b
+func splitNodePath(arg string) (node, path string) {
+	i := strings.Index(arg, ":")
+	if i <= 0 || strings.Contains(arg[:i], "/") {
+		return "", arg
+	}
+	return arg[:i], arg[i+1:]
+}
@@ -19,13 +19,18 @@ package cluster
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/config"
 	"sigs.k8s.io/kind/pkg/cluster/config/encoding"
+	"sigs.k8s.io/kind/pkg/cluster/events"
 	"sigs.k8s.io/kind/pkg/util"
 )
 
@@ -35,6 +40,35 @@ type flagpole struct {
 	ImageName string
 	Retain    bool
 	Wait      time.Duration
+	TTL       time.Duration
+	// WithRegistry enables the local registry, as in the config
+	WithRegistry bool
+	// WithMetricsServer installs metrics-server, as in the config
+	WithMetricsServer bool
+	// WithDashboard installs the dashboard, as in the config
+	WithDashboard bool
+	// Offline creates the cluster without network access
+	Offline bool
+	// Bundle is the path to an image bundle to create the cluster from
+	Bundle string
+	// ExportLogsOnFailure is the parent directory for logs exported on failure
+	ExportLogsOnFailure string
+	// Arch is the architecture to pull the node images for, as in the config
+	Arch string
+	// MergeKubeConfig merges the cluster into the default kubeconfig
+	MergeKubeConfig bool
+	// Events is the path of a file to write the lifecycle events to
+	Events string
+	// Pool is the node pool to claim the nodes from
+	Pool string
+	// Timeouts override the timeouts of the config, as name=duration
+	Timeouts []string
+	// Preset is the name of the preset config to use instead of Config
+	Preset string
+	// PrintPreset is the name of a preset config to print
+	PrintPreset string
+	// ForceReplace deletes the stale cluster with the same name first
+	ForceReplace bool
 }
 
 // NewCommand returns a new cobra.Command for cluster creation
@@ -50,32 +84,57 @@ func NewCommand() *cobra.Command {
 	}
 	cmd.Flags().StringVar(&flags.Name, "name", "1", "cluster context name")
 	cmd.Flags().StringVar(&flags.Config, "config", "", "path to a kind config file")
+	cmd.Flags().StringVar(&flags.Preset, "preset", "", fmt.Sprintf("name of a preset config to use instead of --config, one of %v", encoding.PresetNames()))
+	cmd.Flags().StringVar(&flags.PrintPreset, "print-preset", "", "print the YAML of the named preset config, e.g. to start a config file from it, without creating a cluster")
 	cmd.Flags().StringVar(&flags.ImageName, "image", "", "node docker image to use for booting the cluster")
 	cmd.Flags().BoolVar(&flags.Retain, "retain", false, "retain nodes for debugging when cluster creation fails")
-	cmd.Flags().DurationVar(&flags.Wait, "wait", time.Duration(0), "Wait for control plane node to be ready (default 0s)")
+	cmd.Flags().DurationVar(&flags.Wait, "wait", time.Duration(0), "Wait for the API server, the nodes and CoreDNS to be ready, failing with diagnostics on timeout (default 0s)")
+	cmd.Flags().StringVar(&flags.ExportLogsOnFailure, "export-logs-on-failure", "", "retain nodes and export their logs to a timestamped directory under this directory when cluster creation fails")
+	cmd.Flags().DurationVar(&flags.TTL, "ttl", time.Duration(0), "Allow 'kind gc' to delete the cluster after this duration (default 0s, never)")
+	cmd.Flags().StringVar(&flags.Arch, "arch", "", "architecture to pull the node images for, one of [amd64, arm64, ppc64le, s390x], defaults to the host architecture")
+	cmd.Flags().BoolVar(&flags.MergeKubeConfig, "merge-kubeconfig", false, "merge the cluster into the kubeconfig at $KUBECONFIG, or ~/.kube/config, and switch to its context")
+	cmd.Flags().BoolVar(&flags.WithRegistry, "with-registry", false, "run a local registry the nodes pull images from, published on the host at 127.0.0.1:5000 unless configured otherwise")
+	cmd.Flags().BoolVar(&flags.WithMetricsServer, "with-metrics-server", false, "install metrics-server, for kubectl top and the horizontal pod autoscaler")
+	cmd.Flags().BoolVar(&flags.WithDashboard, "with-dashboard", false, "install the Kubernetes dashboard, along with an admin-user service account to log into it with")
+	cmd.Flags().BoolVar(&flags.Offline, "offline", false, "create the cluster without network access, failing if a required image is not present locally")
+	cmd.Flags().StringVar(&flags.Bundle, "bundle", "", "path to an image bundle exported with 'kind export bundle' to load the images from, implies --offline")
+	cmd.Flags().StringVar(&flags.Events, "events", "", "write the cluster lifecycle events as JSON, one object per line, to this file, e.g. /dev/stderr")
+	cmd.Flags().StringVar(&flags.Pool, "pool", "", "claim the nodes from this node pool when possible instead of creating them, see 'kind pool create'")
+	cmd.Flags().BoolVar(&flags.ForceReplace, "force-replace", false, "delete the existing cluster with the same name first, even if protected, along with the containers and network taking its names")
+	cmd.Flags().StringSliceVar(&flags.Timeouts, "timeout", nil, "override a provisioning timeout of the config, as name=duration, e.g. nodeBoot=2m, one of [nodeBoot, kubeadmInit, nodeRegistration, cniRollout, ready, pollInterval], may be repeated")
 	return cmd
 }
 
 func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
-	// load the config
-	cfg, err := encoding.Load(flags.Config)
-	if err != nil {
-		return fmt.Errorf("error loading config: %v", err)
+	if flags.PrintPreset != "" {
+		preset, err := encoding.Preset(flags.PrintPreset)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(preset)
+		return err
 	}
 
-	// validate the config
-	err = cfg.Validate()
+	// load the config, or the preset config
+	if flags.Preset != "" && flags.Config != "" {
+		return fmt.Errorf("--preset and --config are mutually exclusive")
+	}
+	cfg, err := encoding.Load(flags.Config)
+	if flags.Preset != "" {
+		cfg, err = encoding.LoadPreset(flags.Preset)
+	}
 	if err != nil {
-		log.Error("Invalid configuration!")
-		configErrors := err.(*util.Errors)
-		for _, problem := range configErrors.Errors() {
-			log.Error(problem)
-		}
-		return fmt.Errorf("aborting due to invalid configuration")
+		return fmt.Errorf("error loading config: %v", err)
 	}
 
 	// create a cluster context and create the cluster
-	ctx := cluster.NewContext(flags.Name)
+	// the name in the config is used unless --name is explicitly set
+	name := flags.Name
+	if cfg.Name != "" && !cmd.Flags().Changed("name") {
+		name = cfg.Name
+	}
+	ctx := cluster.NewContext(name)
+	ctx.SetNodePool(flags.Pool)
 	if flags.ImageName != "" {
 		// Apply image override to all the Nodes defined in Config
 		// TODO(fabrizio pandini): this should be reconsidered when implementing
@@ -83,16 +142,105 @@ func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
 		for i := range cfg.Nodes {
 			cfg.Nodes[i].Image = flags.ImageName
 		}
+	}
+
+	if flags.WithRegistry {
+		cfg.Registry.Enabled = true
+	}
+	if flags.WithMetricsServer {
+		cfg.MetricsServer = true
+	}
+	if flags.WithDashboard {
+		cfg.Dashboard = true
+	}
+	if flags.Arch != "" {
+		cfg.Arch = flags.Arch
+	}
+	if err := setTimeouts(&cfg.Timeouts, flags.Timeouts); err != nil {
+		return err
+	}
+
+	// validate the config, reporting all of the problems at once
+	if err := cfg.Validate(); err != nil {
+		log.Error("Invalid configuration!")
+		if configErrors, ok := err.(util.Errors); ok {
+			for _, problem := range configErrors.Errors() {
+				log.Error(problem)
+			}
+		} else {
+			log.Error(err)
+		}
+		return fmt.Errorf("aborting due to invalid configuration")
+	}
 
-		err := cfg.Validate()
+	if flags.Events != "" {
+		f, err := os.Create(flags.Events)
 		if err != nil {
-			log.Errorf("Invalid flags, configuration failed validation: %v", err)
-			return fmt.Errorf("aborting due to invalid configuration")
+			return fmt.Errorf("failed to open the events file: %v", err)
+		}
+		defer f.Close()
+		ctx.SetEventHandler(events.JSONWriter(f))
+	}
+
+	if flags.ForceReplace {
+		if err := ctx.DeleteStale(cfg); err != nil {
+			return fmt.Errorf("failed to delete the stale cluster: %v", err)
 		}
 	}
-	if err = ctx.Create(cfg, flags.Retain, flags.Wait); err != nil {
+
+	// nodes must be retained to export their logs on failure
+	retain := flags.Retain || flags.ExportLogsOnFailure != ""
+	if flags.Offline || flags.Bundle != "" {
+		err = ctx.CreateOffline(cfg, flags.Bundle, retain, flags.Wait, flags.TTL)
+	} else {
+		err = ctx.Create(cfg, retain, flags.Wait, flags.TTL)
+	}
+	if err != nil {
+		if flags.ExportLogsOnFailure != "" {
+			exportLogs(ctx, flags.ExportLogsOnFailure)
+		}
 		return fmt.Errorf("failed to create cluster: %v", err)
 	}
 
+	if flags.MergeKubeConfig {
+		path := cluster.DefaultMergeKubeConfigPath()
+		if err := ctx.MergeKubeConfig(path); err != nil {
+			return fmt.Errorf("failed to merge the kubeconfig: %v", err)
+		}
+		fmt.Printf("\nMerged the cluster into %s, switched to context %q\n", path, ctx.ClusterName())
+	}
+
 	return nil
 }
+
+// setTimeouts sets the timeouts given as name=duration, named as in the
+// config, the durations are checked by config validation
+func setTimeouts(timeouts *config.Timeouts, values []string) error {
+	fields := map[string]*string{
+		"nodeBoot":         &timeouts.NodeBoot,
+		"kubeadmInit":      &timeouts.KubeadmInit,
+		"nodeRegistration": &timeouts.NodeRegistration,
+		"cniRollout":       &timeouts.CNIRollout,
+		"ready":            &timeouts.Ready,
+		"pollInterval":     &timeouts.PollInterval,
+	}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		field, ok := fields[parts[0]]
+		if len(parts) != 2 || !ok {
+			return fmt.Errorf("invalid --timeout %q, must be name=duration with name one of [nodeBoot, kubeadmInit, nodeRegistration, cniRollout, ready, pollInterval]", value)
+		}
+		*field = parts[1]
+	}
+	return nil
+}
+
+// exportLogs collects the cluster logs into a new timestamped directory under
+// parentDir, errors are logged as we are already handling a failure
+func exportLogs(ctx *cluster.Context, parentDir string) {
+	dir := filepath.Join(parentDir, fmt.Sprintf("%s-%s", ctx.ClusterName(), time.Now().Format("20060102-150405")))
+	if err := ctx.CollectLogs(dir); err != nil {
+		log.Errorf("Failed to export logs: %v", err)
+	}
+	fmt.Printf("Exported logs to: %s\nNodes have been retained for debugging, delete them with: kind delete cluster --name=%s\n", dir, ctx.Name())
+}
@@ -18,28 +18,167 @@ limitations under the License.
 package create
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	createcluster "sigs.k8s.io/kind/cmd/kind/create/cluster"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/config/encoding"
 )
 
+type flagpole struct {
+	Filename string
+	Parallel bool
+	Retain   bool
+	Wait     time.Duration
+}
+
 // NewCommand returns a new cobra.Command for cluster creation
 func NewCommand() *cobra.Command {
+	flags := &flagpole{}
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Creates one of [cluster]",
-		Long:  "Creates one of local Kubernetes cluster (cluster)",
-		RunE:  run,
+		Long: "Creates one of local Kubernetes cluster (cluster)\n\n" +
+			"With -f, creates all of the clusters defined in a multi-document config file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
 	}
+	cmd.Flags().StringVarP(&flags.Filename, "filename", "f", "", "path to a kind config file with one document per cluster")
+	cmd.Flags().BoolVar(&flags.Parallel, "parallel", false, "create the clusters defined in --filename in parallel")
+	cmd.Flags().BoolVar(&flags.Retain, "retain", false, "retain nodes for debugging when cluster creation fails")
+	cmd.Flags().DurationVar(&flags.Wait, "wait", time.Duration(0), "Wait for the API server, the nodes and CoreDNS to be ready, failing with diagnostics on timeout (default 0s)")
 	cmd.AddCommand(createcluster.NewCommand())
 	return cmd
 }
 
-func run(cmd *cobra.Command, args []string) error {
-	fmt.Println("You likely want `kind create cluster`, please migrate!")
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	if flags.Filename == "" {
+		fmt.Println("You likely want `kind create cluster`, please migrate!")
+		fmt.Println()
+		cmd.Usage()
+		return nil
+	}
+
+	// load and validate all the configs before creating anything
+	configs, err := encoding.LoadAll(flags.Filename)
+	if err != nil {
+		return fmt.Errorf("error loading config: %v", err)
+	}
+	names := map[string]bool{}
+	for i, cfg := range configs {
+		ctx := cluster.NewContext(cfg.Name)
+		if names[ctx.Name()] {
+			return fmt.Errorf("cluster %q is defined more than once", ctx.Name())
+		}
+		names[ctx.Name()] = true
+		if err := ctx.Validate(); err != nil {
+			return fmt.Errorf("invalid config document %d: %v", i+1, err)
+		}
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid config document %d: %v", i+1, err)
+		}
+	}
+
+	// create the clusters, collecting the result for each of them
+	errs := make([]error, len(configs))
+	if flags.Parallel {
+		// each cluster gets its own logger and status writer, both writing
+		// whole lines prefixed with the cluster name through one lock
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for i, cfg := range configs {
+			ctx := cluster.NewContext(cfg.Name)
+			prefix := fmt.Sprintf("[%s] ", ctx.Name())
+			logger := logrus.New()
+			logger.SetLevel(logrus.GetLevel())
+			logger.Formatter = logrus.StandardLogger().Formatter
+			logOut := &prefixWriter{mu: &mu, out: logrus.StandardLogger().Out, prefix: prefix}
+			logger.SetOutput(logOut)
+			ctx.SetLogger(logger)
+			statusOut := &prefixWriter{mu: &mu, out: os.Stdout, prefix: prefix}
+			ctx.SetStatusWriter(statusOut)
+			wg.Add(1)
+			go func(i int, ctx *cluster.Context, cfg *config.Config) {
+				defer wg.Done()
+				errs[i] = ctx.Create(cfg, flags.Retain, flags.Wait, 0)
+				logOut.Flush()
+				statusOut.Flush()
+			}(i, ctx, cfg)
+		}
+		wg.Wait()
+	} else {
+		for i, cfg := range configs {
+			errs[i] = cluster.NewContext(cfg.Name).Create(cfg, flags.Retain, flags.Wait, 0)
+		}
+	}
+
+	// report the aggregate status
+	failed := 0
 	fmt.Println()
-	cmd.Usage()
+	for i, cfg := range configs {
+		name := cluster.NewContext(cfg.Name).Name()
+		if errs[i] != nil {
+			failed++
+			fmt.Printf(" ✗ %s: %v\n", name, errs[i])
+		} else {
+			fmt.Printf(" ✓ %s\n", name)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to create %d of %d clusters", failed, len(configs))
+	}
 	return nil
 }
+
+// prefixWriter writes whole lines to out, prefixed with prefix, holding mu
+// so that the lines of several writers sharing it are not interleaved.
+// It is not a terminal, so no spinner is shown for the clusters created
+// in parallel.
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+var _ io.Writer = &prefixWriter{}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := w.buf[:i+1]
+		w.buf = w.buf[i+1:]
+		if _, err := fmt.Fprintf(w.out, "%s%s", w.prefix, line); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// Flush writes the remaining partial line, if any, terminating it
+func (w *prefixWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := w.buf
+	w.buf = nil
+	_, err := fmt.Fprintf(w.out, "%s%s\n", w.prefix, line)
+	return err
+}
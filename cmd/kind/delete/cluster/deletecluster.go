@@ -22,12 +22,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"sigs.k8s.io/kind/cmd/kind/completion"
 	"sigs.k8s.io/kind/pkg/cluster"
 )
 
 type flagpole struct {
 	Name   string
 	Retain bool
+	Force  bool
 }
 
 // NewCommand returns a new cobra.Command for cluster creation
@@ -43,12 +45,18 @@ func NewCommand() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&flags.Name, "name", "1", "the cluster name")
+	cmd.Flags().BoolVar(&flags.Force, "force", false, "delete the cluster even if it is protected or owned by someone else")
+	completion.MarkClusterNameFlag(cmd, "name")
 	return cmd
 }
 
 func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
 	ctx := cluster.NewContext(flags.Name)
-	if err := ctx.Delete(); err != nil {
+	deleteCluster := ctx.Delete
+	if flags.Force {
+		deleteCluster = ctx.ForceDelete
+	}
+	if err := deleteCluster(); err != nil {
 		return fmt.Errorf("failed to delete cluster: %v", err)
 	}
 	return nil
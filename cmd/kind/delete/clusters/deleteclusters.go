@@ -0,0 +1,71 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusters implements the `delete clusters` command
+package clusters
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+type flagpole struct {
+	Owner string
+	Force bool
+}
+
+// NewCommand returns a new cobra.Command for deleting the clusters of an owner
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "clusters",
+		Short: "Deletes the clusters of an owner",
+		Long: "Deletes all the clusters owned by --owner, e.g. the clusters of a CI job sharing the host with other jobs\n\n" +
+			"The owner of the clusters created is $" + cluster.OwnerEnv + " if set, and the current user otherwise.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Owner, "owner", "", "the owner of the clusters to delete")
+	cmd.Flags().BoolVar(&flags.Force, "force", false, "delete the clusters even if they are protected")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	if flags.Owner == "" {
+		return fmt.Errorf("the owner of the clusters is required, please set --owner")
+	}
+	clusters, err := cluster.ListOwnedBy(flags.Owner)
+	if err != nil {
+		return fmt.Errorf("failed to list clusters: %v", err)
+	}
+	for i := range clusters {
+		ctx := &clusters[i]
+		deleteCluster := ctx.Delete
+		if flags.Force {
+			deleteCluster = ctx.ForceDelete
+		}
+		if err := deleteCluster(); err != nil {
+			return fmt.Errorf("failed to delete cluster %q: %v", ctx.Name(), err)
+		}
+		fmt.Printf("Deleted cluster %q\n", ctx.Name())
+	}
+	return nil
+}
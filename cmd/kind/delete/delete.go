@@ -23,6 +23,7 @@ import (
 	"github.com/spf13/cobra"
 
 	deletecluster "sigs.k8s.io/kind/cmd/kind/delete/cluster"
+	deleteclusters "sigs.k8s.io/kind/cmd/kind/delete/clusters"
 )
 
 // NewCommand returns a new cobra.Command for cluster creation
@@ -30,11 +31,12 @@ func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		// TODO(bentheelder): more detailed usage
 		Use:   "delete",
-		Short: "Deletes one of [cluster]",
-		Long:  "Deletes one of [cluster]",
+		Short: "Deletes one of [cluster, clusters]",
+		Long:  "Deletes one of [cluster, clusters]",
 		RunE:  run,
 	}
 	cmd.AddCommand(deletecluster.NewCommand())
+	cmd.AddCommand(deleteclusters.NewCommand())
 	return cmd
 }
 
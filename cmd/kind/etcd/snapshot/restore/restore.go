@@ -0,0 +1,59 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package restore implements the `etcd snapshot restore` command
+package restore
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+type flagpole struct {
+	Name string
+}
+
+// NewCommand returns a new cobra.Command for restoring an etcd snapshot
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Use:   "restore <file>",
+		Args:  cobra.ExactArgs(1),
+		Short: "restores the etcd snapshot in <file> to the cluster",
+		Long: "restores the etcd snapshot in <file> to the cluster\n\n" +
+			"The data of all the etcd members is replaced with the snapshot, which\n" +
+			"may have been saved from another cluster, e.g. to restore to a fresh cluster.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", cluster.DefaultName, "the cluster context name")
+	completion.MarkClusterNameFlag(cmd, "name")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	ctx := cluster.NewContext(flags.Name)
+	if err := ctx.RestoreEtcdSnapshot(args[0]); err != nil {
+		return fmt.Errorf("failed to restore etcd snapshot: %v", err)
+	}
+	fmt.Println("Restored etcd snapshot from: " + args[0])
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package save implements the `etcd snapshot save` command
+package save
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+type flagpole struct {
+	Name string
+}
+
+// NewCommand returns a new cobra.Command for saving an etcd snapshot
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Use:   "save <file>",
+		Args:  cobra.ExactArgs(1),
+		Short: "saves a snapshot of the cluster etcd data to <file>",
+		Long: "saves a snapshot of the cluster etcd data to <file>\n\n" +
+			"The snapshot is taken with etcdctl on the control-plane for stacked etcd,\n" +
+			"or on the external etcd nodes for external etcd.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", cluster.DefaultName, "the cluster context name")
+	completion.MarkClusterNameFlag(cmd, "name")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	ctx := cluster.NewContext(flags.Name)
+	if err := ctx.SaveEtcdSnapshot(args[0]); err != nil {
+		return fmt.Errorf("failed to save etcd snapshot: %v", err)
+	}
+	fmt.Println("Saved etcd snapshot to: " + args[0])
+	return nil
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshot implements the `etcd snapshot` command
+package snapshot
+
+import (
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/etcd/snapshot/restore"
+	"sigs.k8s.io/kind/cmd/kind/etcd/snapshot/save"
+)
+
+// NewCommand returns a new cobra.Command for etcd snapshot
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Manages etcd snapshots with one of [save, restore]",
+		Long:  "Manages etcd snapshots with one of [save, restore]",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	// add subcommands
+	cmd.AddCommand(save.NewCommand())
+	cmd.AddCommand(restore.NewCommand())
+	return cmd
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package exec implements the `exec` command
+package exec
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/exec"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+type flagpole struct {
+	Name  string
+	Node  string
+	Role  string
+	Index int
+	TTY   bool
+}
+
+// NewCommand returns a new cobra.Command for running a command on a node
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.MinimumNArgs(1),
+		Use:   "exec [flags] -- COMMAND [ARG...]",
+		Short: "Runs a command on a node of the kind cluster by --name",
+		Long: "Runs a command on a node of the kind cluster by --name, with the stdin, stdout and stderr of kind\n\n" +
+			"The node is selected by --node, its replica name e.g. worker2, or by --role and --index,\n" +
+			"defaulting to the first control plane node. kind exits with the exit code of the command.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", "1", "the cluster context name")
+	cmd.Flags().StringVar(&flags.Node, "node", "", "the node replica name, e.g. worker2, or node container name")
+	cmd.Flags().StringVar(&flags.Role, "role", string(config.ControlPlaneRole), "the role of the node, if --node is not set")
+	cmd.Flags().IntVar(&flags.Index, "index", 1, "the 1-based index of the node among the nodes with --role")
+	cmd.Flags().BoolVarP(&flags.TTY, "tty", "t", false, "allocate a TTY, defaults to true if stdin and stdout are terminals")
+	completion.MarkClusterNameFlag(cmd, "name")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	node, err := selectNode(flags)
+	if err != nil {
+		return err
+	}
+	tty := flags.TTY
+	if !cmd.Flags().Changed("tty") {
+		tty = logutil.IsTerminal(os.Stdin) && logutil.IsTerminal(os.Stdout)
+	}
+	c := node.InteractiveCommand(tty, args[0], args[1:]...)
+	c.SetStdin(os.Stdin)
+	exec.InheritOutput(c)
+	// the error is returned as is, so that kind exits with the exit code
+	return c.Run()
+}
+
+func selectNode(flags *flagpole) (*nodes.Node, error) {
+	ctx := cluster.NewContext(flags.Name)
+	if flags.Node != "" {
+		return ctx.Node(flags.Node)
+	}
+	role := config.NodeRole(flags.Role)
+	switch role {
+	case config.ControlPlaneRole, config.WorkerRole, config.ExternalEtcdRole, config.ExternalLoadBalancerRole:
+	default:
+		return nil, fmt.Errorf("invalid role %q", flags.Role)
+	}
+	return ctx.NodeByRole(role, flags.Index)
+}
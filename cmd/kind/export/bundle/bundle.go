@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle implements the `export bundle` command
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/config/encoding"
+)
+
+type flagpole struct {
+	Config    string
+	ImageName string
+}
+
+// NewCommand returns a new cobra.Command for exporting an image bundle
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Use:   "bundle <output-file>",
+		Args:  cobra.ExactArgs(1),
+		Short: "exports the images required for creating a cluster to an image bundle",
+		Long: "exports the images required for creating a cluster to an image bundle\n\n" +
+			"The bundle is used to create the cluster without network access with 'kind create cluster --bundle'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Config, "config", "", "path to the kind config file of the cluster")
+	cmd.Flags().StringVar(&flags.ImageName, "image", "", "node docker image of the cluster")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	cfg, err := encoding.Load(flags.Config)
+	if err != nil {
+		return fmt.Errorf("error loading config: %v", err)
+	}
+	if flags.ImageName != "" {
+		for i := range cfg.Nodes {
+			cfg.Nodes[i].Image = flags.ImageName
+		}
+	}
+	if err := cluster.ExportBundle(cfg, args[0]); err != nil {
+		return fmt.Errorf("failed to export bundle: %v", err)
+	}
+	fmt.Println("Exported bundle to: " + args[0])
+	return nil
+}
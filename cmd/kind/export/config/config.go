@@ -0,0 +1,75 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config implements the `export config` command
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/config/encoding"
+)
+
+type flagpole struct {
+	Name   string
+	Output string
+}
+
+// NewCommand returns a new cobra.Command for exporting the config of a cluster
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "config",
+		Short: "exports the config of a running cluster",
+		Long: "exports the config of a running cluster, reconstructed from its node containers: the node roles, images,\n" +
+			"mounts, port mappings and devices, and the networking.\n" +
+			"Settings not recorded on the node containers, e.g. kubeadm config patches, are not exported.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", cluster.DefaultName, "the cluster context name")
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "", "path to write the config file to, defaults to stdout")
+	completion.MarkClusterNameFlag(cmd, "name")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	ctx := cluster.NewContext(flags.Name)
+	cfg, err := ctx.ExportConfig()
+	if err != nil {
+		return fmt.Errorf("failed to export config: %v", err)
+	}
+	encoded, err := encoding.Encode(cfg)
+	if err != nil {
+		return fmt.Errorf("error encoding config: %v", err)
+	}
+	if flags.Output == "" {
+		_, err = os.Stdout.Write(encoded)
+		return err
+	}
+	if err := ioutil.WriteFile(flags.Output, encoded, 0644); err != nil {
+		return fmt.Errorf("error writing config: %v", err)
+	}
+	return nil
+}
@@ -20,7 +20,10 @@ package export
 import (
 	"github.com/spf13/cobra"
 
+	"sigs.k8s.io/kind/cmd/kind/export/bundle"
+	"sigs.k8s.io/kind/cmd/kind/export/config"
 	"sigs.k8s.io/kind/cmd/kind/export/logs"
+	"sigs.k8s.io/kind/cmd/kind/export/snapshot"
 )
 
 // NewCommand returns a new cobra.Command for export
@@ -28,13 +31,16 @@ func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		// TODO(bentheelder): more detailed usage
 		Use:   "export",
-		Short: "exports one of [logs]",
-		Long:  "exports one of [logs]",
+		Short: "exports one of [bundle, config, logs, snapshot]",
+		Long:  "exports one of [bundle, config, logs, snapshot]",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
 	}
 	// add subcommands
+	cmd.AddCommand(bundle.NewCommand())
+	cmd.AddCommand(config.NewCommand())
 	cmd.AddCommand(logs.NewCommand())
+	cmd.AddCommand(snapshot.NewCommand())
 	return cmd
 }
@@ -19,10 +19,14 @@ package logs
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"sigs.k8s.io/kind/cmd/kind/completion"
 	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/logs"
 	"sigs.k8s.io/kind/pkg/fs"
 )
 
@@ -38,20 +42,23 @@ func NewCommand() *cobra.Command {
 		Use:   "logs [output-dir]",
 		Args:  cobra.MaximumNArgs(1),
 		Short: "exports logs to to a tempdir or [output-dir] if specified",
-		Long:  "exports logs to to a tempdir or [output-dir] if specified",
+		Long: "exports logs to to a tempdir or [output-dir] if specified\n\n" +
+			"If [output-dir] ends with .tar.gz or .tgz, the logs are written to a gzipped tarball at that path instead.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runE(flags, cmd, args)
 		},
 	}
 	// TODO(bentheelder): this default should be a constant somewhere
 	cmd.Flags().StringVar(&flags.Name, "name", "1", "the cluster context name")
+	completion.MarkClusterNameFlag(cmd, "name")
 	return cmd
 }
 
 func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
 	// get the optional directory argument, or create a tempdir
-	var dir string
-	if len(args) == 0 {
+	// the logs are collected to a tempdir first if archived
+	var dir, archive string
+	if len(args) == 0 || isArchive(args[0]) {
 		t, err := fs.TempDir("", "")
 		if err != nil {
 			return err
@@ -60,10 +67,29 @@ func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
 	} else {
 		dir = args[0]
 	}
+	if len(args) > 0 && isArchive(args[0]) {
+		archive = args[0]
+		defer os.RemoveAll(dir)
+	}
 	context := cluster.NewContext(flags.Name)
-	if err := context.CollectLogs(dir); err != nil {
-		return err
+	collectErr := context.CollectLogs(dir)
+	// the logs collected despite errors are still archived, like they are
+	// left in the output directory
+	if archive != "" {
+		if err := logs.Archive(dir, archive); err != nil {
+			return fmt.Errorf("failed to archive logs: %v", err)
+		}
+		dir = archive
+	}
+	if collectErr != nil {
+		return collectErr
 	}
 	fmt.Println("Exported logs to: " + dir)
 	return nil
 }
+
+// isArchive returns true if the logs should be exported to a gzipped tarball
+// at path
+func isArchive(path string) bool {
+	return strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz")
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshot implements the `export snapshot` command
+package snapshot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/fs"
+)
+
+type flagpole struct {
+	Name string
+	Wait time.Duration
+}
+
+// NewCommand returns a new cobra.Command for exporting a cluster snapshot
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Use:   "snapshot [output-dir]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "exports a cluster snapshot to a tempdir or [output-dir] if specified",
+		Long: "exports a cluster snapshot to a tempdir or [output-dir] if specified\n\n" +
+			"The cluster is paused while the snapshot is taken, and resumed afterwards.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", cluster.DefaultName, "the cluster context name")
+	cmd.Flags().DurationVar(&flags.Wait, "wait", time.Minute*5, "maximum time to wait for the control plane to be ready after resuming")
+	completion.MarkClusterNameFlag(cmd, "name")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	// get the optional directory argument, or create a tempdir
+	var dir string
+	if len(args) == 0 {
+		t, err := fs.TempDir("", "")
+		if err != nil {
+			return err
+		}
+		dir = t
+	} else {
+		dir = args[0]
+	}
+	ctx := cluster.NewContext(flags.Name)
+	if err := ctx.ExportSnapshot(dir, flags.Wait); err != nil {
+		return fmt.Errorf("failed to export snapshot: %v", err)
+	}
+	fmt.Println("Exported snapshot to: " + dir)
+	return nil
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fault implements the `fault` command
+package fault
+
+import (
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/fault/inject"
+	"sigs.k8s.io/kind/cmd/kind/fault/network"
+	"sigs.k8s.io/kind/cmd/kind/fault/revert"
+)
+
+// NewCommand returns a new cobra.Command for fault
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fault",
+		Short: "Injects or reverts node failures, one of [inject, network, revert]",
+		Long:  "Injects failures into the nodes of a cluster, e.g. to test the resilience of controllers, and reverts them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	// add subcommands
+	cmd.AddCommand(inject.NewCommand())
+	cmd.AddCommand(network.NewCommand())
+	cmd.AddCommand(revert.NewCommand())
+	return cmd
+}
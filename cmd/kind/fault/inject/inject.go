@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package inject implements the `fault inject` command
+package inject
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+type flagpole struct {
+	Node   string
+	Action string
+}
+
+// NewCommand returns a new cobra.Command for injecting a node failure
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "inject",
+		Short: "Injects a failure into a node",
+		Long: "Injects the failure named by --action into the node container named by --node, until it is reverted with `kind fault revert`:\n\n" +
+			"  stop           stops the node container, as if the node lost power\n" +
+			"  pause          freezes the processes of the node container, as if the node hung\n" +
+			"  partition      drops all the traffic of the node on the docker network\n" +
+			"  disk-pressure  fills the disk of the node until the kubelet evicts pods",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Node, "node", "", "the node (container) name, e.g. kind-1-worker2")
+	cmd.Flags().StringVar(&flags.Action, "action", "", fmt.Sprintf("the failure, one of %v", cluster.Faults))
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	if flags.Node == "" {
+		return fmt.Errorf("--node is required")
+	}
+	if flags.Action == "" {
+		return fmt.Errorf("--action is required")
+	}
+	ctx, err := cluster.ContextForNode(flags.Node)
+	if err != nil {
+		return err
+	}
+	if err := ctx.InjectFault(flags.Node, cluster.Fault(flags.Action)); err != nil {
+		return fmt.Errorf("failed to inject fault: %v", err)
+	}
+	return nil
+}
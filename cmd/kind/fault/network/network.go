@@ -0,0 +1,77 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package network implements the `fault network` command
+package network
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+type flagpole struct {
+	Name   string
+	Config string
+	Revert bool
+}
+
+// NewCommand returns a new cobra.Command for applying network faults
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "network",
+		Short: "Degrades the network between the nodes of a cluster",
+		Long: "Applies the latency, loss and partitions between the nodes of the cluster described by the YAML file --config,\n" +
+			"replacing the ones applied before, or reverts them with --revert",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", cluster.DefaultName, "the cluster context name")
+	cmd.Flags().StringVar(&flags.Config, "config", "", "path to the network faults file")
+	cmd.Flags().BoolVar(&flags.Revert, "revert", false, "revert the network faults applied to the cluster")
+	completion.MarkClusterNameFlag(cmd, "name")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	ctx := cluster.NewContext(flags.Name)
+	if flags.Revert {
+		if flags.Config != "" {
+			return fmt.Errorf("--config and --revert are mutually exclusive")
+		}
+		if err := ctx.RevertNetworkFaults(); err != nil {
+			return fmt.Errorf("failed to revert network faults: %v", err)
+		}
+		return nil
+	}
+	if flags.Config == "" {
+		return fmt.Errorf("--config or --revert is required")
+	}
+	faults, err := cluster.LoadNetworkFaults(flags.Config)
+	if err != nil {
+		return err
+	}
+	if err := ctx.ApplyNetworkFaults(faults); err != nil {
+		return fmt.Errorf("failed to apply network faults: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gc implements the `gc` command
+package gc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+// NewCommand returns a new cobra.Command for garbage collecting clusters
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Deletes expired clusters",
+		Long:  "Deletes all clusters created with --ttl that have expired",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(cmd, args)
+		},
+	}
+	return cmd
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	deleted, err := cluster.GarbageCollect(time.Now())
+	for _, c := range deleted {
+		fmt.Printf("Deleted expired cluster: %s\n", c.Name())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to garbage collect clusters: %v", err)
+	}
+	return nil
+}
@@ -19,34 +19,66 @@ package clusters
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
+	"sigs.k8s.io/kind/cmd/kind/get/output"
 	"sigs.k8s.io/kind/pkg/cluster"
 )
 
+type flagpole struct {
+	Output string
+	Owner  string
+}
+
 // NewCommand returns a new cobra.Command for getting the list of clusters
 func NewCommand() *cobra.Command {
+	flags := &flagpole{}
 	cmd := &cobra.Command{
+		Args: cobra.NoArgs,
 		// TODO(bentheelder): more detailed usage
 		Use:   "clusters",
 		Short: "lists existing kind clusters by their name",
-		Long:  "lists existing kind clusters by their name",
+		Long: "lists existing kind clusters by their name, discovered from the labels of their node containers\n\n" +
+			"With -o json or -o yaml the clusters are listed with their owner and their nodes, as in 'kind get nodes'",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runE(cmd, args)
+			return runE(flags, cmd, args)
 		},
 	}
+	output.AddFlag(cmd, &flags.Output)
+	cmd.Flags().StringVar(&flags.Owner, "owner", "", "list only the clusters owned by this owner, e.g. $"+cluster.OwnerEnv)
 	return cmd
 }
 
-func runE(cmd *cobra.Command, args []string) error {
-	clusters, err := cluster.List()
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	// the names are listed without inspecting the nodes
+	if flags.Output == "name" {
+		clusters, err := cluster.List()
+		if flags.Owner != "" {
+			clusters, err = cluster.ListOwnedBy(flags.Owner)
+		}
+		if err != nil {
+			return errors.Wrap(err, "error listing clusters")
+		}
+		for _, cluster := range clusters {
+			fmt.Println(cluster.Name())
+		}
+		return nil
+	}
+	infos, err := cluster.ListInfo()
 	if err != nil {
 		return errors.Wrap(err, "error listing clusters")
 	}
-	for _, cluster := range clusters {
-		fmt.Println(cluster.Name())
+	owned := []cluster.ClusterInfo{}
+	names := []string{}
+	for _, info := range infos {
+		if flags.Owner != "" && info.Owner != flags.Owner {
+			continue
+		}
+		owned = append(owned, info)
+		names = append(names, info.Name)
 	}
-	return nil
+	return output.Print(os.Stdout, flags.Output, owned, names)
 }
@@ -0,0 +1,91 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config implements the `config` command
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/config/encoding"
+)
+
+type flagpole struct {
+	Name      string
+	Config    string
+	ImageName string
+}
+
+// NewCommand returns a new cobra.Command for getting the effective config
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "config",
+		Short: "prints the effective config of the kind cluster by --name",
+		Long: "prints the fully resolved config of the kind cluster by --name, including the node names,\n" +
+			"provisioning order and images.\n\n" +
+			"If --config or --image are set, the config that create cluster would use for them is resolved,\n" +
+			"otherwise the config of the running cluster is inspected from its nodes.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", "1", "the cluster context name")
+	cmd.Flags().StringVar(&flags.Config, "config", "", "path to a kind config file to resolve")
+	cmd.Flags().StringVar(&flags.ImageName, "image", "", "node docker image to resolve the config with")
+	completion.MarkClusterNameFlag(cmd, "name")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	name := flags.Name
+	var cfg *config.Config
+	if flags.Config != "" || flags.ImageName != "" {
+		// resolve the config like create cluster does
+		var err error
+		cfg, err = encoding.Load(flags.Config)
+		if err != nil {
+			return fmt.Errorf("error loading config: %v", err)
+		}
+		if cfg.Name != "" && !cmd.Flags().Changed("name") {
+			name = cfg.Name
+		}
+		if flags.ImageName != "" {
+			for i := range cfg.Nodes {
+				cfg.Nodes[i].Image = flags.ImageName
+			}
+		}
+	}
+
+	effective, err := cluster.NewContext(name).EffectiveConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the config: %v", err)
+	}
+	out, err := yaml.Marshal(effective)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(out)
+	return err
+}
@@ -21,7 +21,10 @@ import (
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/cmd/kind/get/clusters"
+	"sigs.k8s.io/kind/cmd/kind/get/config"
+	"sigs.k8s.io/kind/cmd/kind/get/kubeconfig"
 	"sigs.k8s.io/kind/cmd/kind/get/kubeconfigpath"
+	"sigs.k8s.io/kind/cmd/kind/get/nodes"
 )
 
 // NewCommand returns a new cobra.Command for get
@@ -29,14 +32,17 @@ func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		// TODO(bentheelder): more detailed usage
 		Use:   "get",
-		Short: "Gets one of [clusters, kubeconfig-path]",
-		Long:  "Gets one of [clusters, kubeconfig-path]",
+		Short: "Gets one of [clusters, config, kubeconfig, kubeconfig-path, nodes]",
+		Long:  "Gets one of [clusters, config, kubeconfig, kubeconfig-path, nodes]",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
 	}
 	// add subcommands
 	cmd.AddCommand(clusters.NewCommand())
+	cmd.AddCommand(config.NewCommand())
+	cmd.AddCommand(kubeconfig.NewCommand())
 	cmd.AddCommand(kubeconfigpath.NewCommand())
+	cmd.AddCommand(nodes.NewCommand())
 	return cmd
 }
@@ -26,7 +26,8 @@ import (
 )
 
 type flagpole struct {
-	Name string
+	Name     string
+	Internal bool
 }
 
 // NewCommand returns a new cobra.Command for getting the kubeconfig path
@@ -47,11 +48,21 @@ func NewCommand() *cobra.Command {
 		"1",
 		"the cluster context name",
 	)
+	cmd.Flags().BoolVar(
+		&flags.Internal,
+		"internal",
+		false,
+		"print the path of the kubeconfig reaching the API server inside the docker network",
+	)
 	return cmd
 }
 
 func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
 	ctx := cluster.NewContext(flags.Name)
+	if flags.Internal {
+		fmt.Println(ctx.InternalKubeConfigPath())
+		return nil
+	}
 	fmt.Println(ctx.KubeConfigPath())
 	return nil
 }
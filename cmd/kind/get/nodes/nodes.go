@@ -0,0 +1,84 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodes implements the `nodes` command
+package nodes
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/cmd/kind/get/output"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+type flagpole struct {
+	Name        string
+	AllClusters bool
+	Output      string
+}
+
+// NewCommand returns a new cobra.Command for getting the list of nodes
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "nodes",
+		Short: "lists the nodes of the kind cluster by --name",
+		Long: "lists the node containers of the kind cluster by --name, or of all the clusters\n\n" +
+			"With -o json or -o yaml the nodes are listed with their replica name, role, image, IP addresses, status\n" +
+			"the host CPUs and NUMA nodes they run on, and their provenance: replica index, config hash,\n" +
+			"kind version and node image digest",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", "1", "the cluster context name")
+	cmd.Flags().BoolVarP(&flags.AllClusters, "all-clusters", "A", false, "list the nodes of all the clusters")
+	output.AddFlag(cmd, &flags.Output)
+	completion.MarkClusterNameFlag(cmd, "name")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	var nodes []cluster.NodeInfo
+	if flags.AllClusters {
+		infos, err := cluster.ListInfo()
+		if err != nil {
+			return errors.Wrap(err, "error listing clusters")
+		}
+		for _, info := range infos {
+			nodes = append(nodes, info.Nodes...)
+		}
+	} else {
+		var err error
+		nodes, err = cluster.NewContext(flags.Name).NodeInfo()
+		if err != nil {
+			return errors.Wrap(err, "error listing nodes")
+		}
+	}
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	if nodes == nil {
+		nodes = []cluster.NodeInfo{}
+	}
+	return output.Print(os.Stdout, flags.Output, nodes, names)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package output implements the --output flag of the get commands
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// Formats are the supported output formats
+var Formats = []string{"name", "json", "yaml"}
+
+// AddFlag adds the --output, -o flag to cmd, binding it to format
+func AddFlag(cmd *cobra.Command, format *string) {
+	cmd.Flags().StringVarP(format, "output", "o", "name", fmt.Sprintf("output format, one of %v", Formats))
+}
+
+// Print writes v to w in format, names are printed one per line for the name
+// format
+func Print(w io.Writer, format string, v interface{}, names []string) error {
+	switch format {
+	case "name":
+		for _, name := range names {
+			fmt.Fprintln(w, name)
+		}
+		return nil
+	case "json":
+		encoded, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(encoded))
+		return err
+	case "yaml":
+		encoded, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(encoded)
+		return err
+	}
+	return fmt.Errorf("unknown output format %q, expected one of %v", format, Formats)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package importcmd implements the `import` command
+// NOTE: the package cannot be named import, as that is a Go keyword
+package importcmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/import/snapshot"
+)
+
+// NewCommand returns a new cobra.Command for import
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "imports one of [snapshot]",
+		Long:  "imports one of [snapshot], restoring a cluster exported with `kind export snapshot`",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	// add subcommands
+	cmd.AddCommand(snapshot.NewCommand())
+	return cmd
+}
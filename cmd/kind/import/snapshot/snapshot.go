@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshot implements the `import snapshot` command
+package snapshot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+type flagpole struct {
+	Wait time.Duration
+}
+
+// NewCommand returns a new cobra.Command for importing a cluster snapshot
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Use:   "snapshot <snapshot-dir>",
+		Args:  cobra.ExactArgs(1),
+		Short: "restores a cluster from a snapshot created by `kind export snapshot`",
+		Long: "restores a cluster from a snapshot created by `kind export snapshot`\n\n" +
+			"The cluster is restored with its original name, which must not be in use.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().DurationVar(&flags.Wait, "wait", time.Minute*5, "maximum time to wait for the control plane to be ready")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	ctx, err := cluster.ImportSnapshot(args[0], flags.Wait)
+	if err != nil {
+		return fmt.Errorf("failed to import snapshot: %v", err)
+	}
+	fmt.Printf(
+		"Cluster restored. You can now use the cluster with:\n\nexport KUBECONFIG=\"$(kind get kubeconfig-path --name=%q)\"\nkubectl cluster-info\n",
+		ctx.Name(),
+	)
+	return nil
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config implements the `init config` command
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/config/encoding"
+	"sigs.k8s.io/kind/pkg/util"
+)
+
+type flagpole struct {
+	ControlPlanes     int32
+	Workers           int32
+	KubernetesVersion string
+	Image             string
+	IPFamily          string
+	PodSubnet         string
+	ServiceSubnet     string
+	Mounts            []string
+	Interactive       bool
+	Output            string
+}
+
+// NewCommand returns a new cobra.Command for generating a config file
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "config",
+		Short: "generates a cluster config file",
+		Long: fmt.Sprintf(
+			"generates a cluster config file of the latest config API version (%s) from the flags,\n"+
+				"or from the answers to prompts with --interactive, the flags being the default answers.\n"+
+				"The config is validated before it is written.",
+			encoding.LatestVersion,
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().Int32Var(&flags.ControlPlanes, "control-planes", 1, "the number of control-plane nodes, an external load balancer is added for more than one")
+	cmd.Flags().Int32Var(&flags.Workers, "workers", 0, "the number of worker nodes")
+	cmd.Flags().StringVar(&flags.KubernetesVersion, "kubernetes-version", "", "the Kubernetes version of the nodes, e.g. v1.13.0, selecting the kindest/node image of that version")
+	cmd.Flags().StringVar(&flags.Image, "image", "", "the node docker image to use, instead of the one of --kubernetes-version")
+	cmd.Flags().StringVar(&flags.IPFamily, "ip-family", "", "the IP family of the cluster, one of ipv4, ipv6 or dual")
+	cmd.Flags().StringVar(&flags.PodSubnet, "pod-subnet", "", "the CIDR range of the pod IPs")
+	cmd.Flags().StringVar(&flags.ServiceSubnet, "service-subnet", "", "the CIDR range of the service virtual IPs")
+	cmd.Flags().StringArrayVar(&flags.Mounts, "mount", nil, "a host path mounted into every node, as hostPath:containerPath[:ro], may be repeated")
+	cmd.Flags().BoolVarP(&flags.Interactive, "interactive", "i", false, "prompt for the settings of the config")
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "", "path to write the config file to, defaults to stdout")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	if flags.Interactive {
+		if err := prompt(flags, bufio.NewReader(os.Stdin), os.Stderr); err != nil {
+			return err
+		}
+	}
+	cfg, err := newConfig(flags)
+	if err != nil {
+		return err
+	}
+
+	// validate the config as it will be loaded, that is with the defaults
+	// applied, reporting all of the problems at once
+	encoded, err := encoding.Encode(cfg)
+	if err != nil {
+		return fmt.Errorf("error encoding config: %v", err)
+	}
+	decoded, err := encoding.Decode(encoded)
+	if err != nil {
+		return fmt.Errorf("error decoding config: %v", err)
+	}
+	if err := decoded.Validate(); err != nil {
+		log.Error("Invalid configuration!")
+		if configErrors, ok := err.(util.Errors); ok {
+			for _, problem := range configErrors.Errors() {
+				log.Error(problem)
+			}
+		} else {
+			log.Error(err)
+		}
+		return fmt.Errorf("aborting due to invalid configuration")
+	}
+
+	if flags.Output == "" {
+		_, err = os.Stdout.Write(encoded)
+		return err
+	}
+	if err := ioutil.WriteFile(flags.Output, encoded, 0644); err != nil {
+		return fmt.Errorf("error writing config: %v", err)
+	}
+	return nil
+}
+
+// prompt asks for each of the settings in turn on out, reading the answers
+// from in, the current values of flags are the default answers
+func prompt(flags *flagpole, in *bufio.Reader, out io.Writer) error {
+	ask := func(question, value string) (string, error) {
+		if value != "" {
+			fmt.Fprintf(out, "%s [%s]: ", question, value)
+		} else {
+			fmt.Fprintf(out, "%s: ", question)
+		}
+		answer, err := in.ReadString('\n')
+		if err != nil && (err != io.EOF || answer == "") {
+			return "", fmt.Errorf("error reading answer: %v", err)
+		}
+		if answer = strings.TrimSpace(answer); answer != "" {
+			return answer, nil
+		}
+		return value, nil
+	}
+	askCount := func(question string, value *int32) error {
+		answer, err := ask(question, strconv.Itoa(int(*value)))
+		if err != nil {
+			return err
+		}
+		count, err := strconv.ParseInt(answer, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid answer %q, expected a number", answer)
+		}
+		*value = int32(count)
+		return nil
+	}
+
+	var err error
+	if err = askCount("Number of control-plane nodes", &flags.ControlPlanes); err != nil {
+		return err
+	}
+	if err = askCount("Number of worker nodes", &flags.Workers); err != nil {
+		return err
+	}
+	if flags.Image == "" {
+		if flags.KubernetesVersion, err = ask("Kubernetes version, empty for the default", flags.KubernetesVersion); err != nil {
+			return err
+		}
+	}
+	if flags.IPFamily, err = ask("IP family (ipv4, ipv6 or dual), empty for the default", flags.IPFamily); err != nil {
+		return err
+	}
+	if flags.PodSubnet, err = ask("Pod subnet, empty for the default", flags.PodSubnet); err != nil {
+		return err
+	}
+	if flags.ServiceSubnet, err = ask("Service subnet, empty for the default", flags.ServiceSubnet); err != nil {
+		return err
+	}
+	mounts, err := ask("Host paths mounted into the nodes, as comma separated hostPath:containerPath[:ro]", strings.Join(flags.Mounts, ","))
+	if err != nil {
+		return err
+	}
+	flags.Mounts = nil
+	for _, mount := range strings.Split(mounts, ",") {
+		if mount = strings.TrimSpace(mount); mount != "" {
+			flags.Mounts = append(flags.Mounts, mount)
+		}
+	}
+	return nil
+}
+
+// newConfig returns the config described by flags
+func newConfig(flags *flagpole) (*config.Config, error) {
+	if flags.ControlPlanes < 1 {
+		return nil, fmt.Errorf("at least one control-plane node is required")
+	}
+	if flags.Workers < 0 {
+		return nil, fmt.Errorf("the number of worker nodes can not be negative")
+	}
+	if flags.Image != "" && flags.KubernetesVersion != "" {
+		return nil, fmt.Errorf("--image and --kubernetes-version are mutually exclusive")
+	}
+	image := flags.Image
+	if flags.KubernetesVersion != "" {
+		image = "kindest/node:" + flags.KubernetesVersion
+	}
+	mounts := []config.Mount{}
+	for _, value := range flags.Mounts {
+		mount, err := parseMount(value)
+		if err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, mount)
+	}
+
+	cfg := &config.Config{}
+	cfg.Networking.IPFamily = config.IPFamily(flags.IPFamily)
+	cfg.Networking.PodSubnet = flags.PodSubnet
+	cfg.Networking.ServiceSubnet = flags.ServiceSubnet
+	// multiple control-plane nodes are reached through a load balancer
+	if flags.ControlPlanes > 1 {
+		cfg.Nodes = append(cfg.Nodes, config.Node{
+			Role: config.ExternalLoadBalancerRole,
+		})
+	}
+	cfg.Nodes = append(cfg.Nodes, newNode(config.ControlPlaneRole, flags.ControlPlanes, image, mounts))
+	if flags.Workers > 0 {
+		cfg.Nodes = append(cfg.Nodes, newNode(config.WorkerRole, flags.Workers, image, mounts))
+	}
+	return cfg, nil
+}
+
+// newNode returns a node with role, replicated replicas times
+func newNode(role config.NodeRole, replicas int32, image string, mounts []config.Mount) config.Node {
+	node := config.Node{
+		Role:        role,
+		Image:       image,
+		ExtraMounts: mounts,
+	}
+	if replicas > 1 {
+		node.Replicas = &replicas
+	}
+	return node
+}
+
+// parseMount parses a mount flag, hostPath:containerPath[:ro]
+func parseMount(value string) (config.Mount, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) == 3 && parts[2] == "ro" {
+		return config.Mount{HostPath: parts[0], ContainerPath: parts[1], ReadOnly: true}, nil
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return config.Mount{}, fmt.Errorf("invalid mount %q, expected hostPath:containerPath[:ro]", value)
+	}
+	return config.Mount{HostPath: parts[0], ContainerPath: parts[1]}, nil
+}
@@ -19,26 +19,49 @@ package kind
 
 import (
 	"os"
+	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kind/cmd/kind/build"
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/cmd/kind/convert"
+	"sigs.k8s.io/kind/cmd/kind/cp"
 	"sigs.k8s.io/kind/cmd/kind/create"
 	"sigs.k8s.io/kind/cmd/kind/delete"
+	"sigs.k8s.io/kind/cmd/kind/etcd"
+	"sigs.k8s.io/kind/cmd/kind/exec"
 	"sigs.k8s.io/kind/cmd/kind/export"
+	"sigs.k8s.io/kind/cmd/kind/fault"
+	"sigs.k8s.io/kind/cmd/kind/gc"
 	"sigs.k8s.io/kind/cmd/kind/get"
+	importcmd "sigs.k8s.io/kind/cmd/kind/import"
+	initcmd "sigs.k8s.io/kind/cmd/kind/init"
+	"sigs.k8s.io/kind/cmd/kind/load"
+	"sigs.k8s.io/kind/cmd/kind/pause"
+	"sigs.k8s.io/kind/cmd/kind/pool"
+	"sigs.k8s.io/kind/cmd/kind/preflight"
+	"sigs.k8s.io/kind/cmd/kind/protect"
+	"sigs.k8s.io/kind/cmd/kind/recreate"
+	"sigs.k8s.io/kind/cmd/kind/replace"
+	"sigs.k8s.io/kind/cmd/kind/resume"
+	"sigs.k8s.io/kind/cmd/kind/status"
+	"sigs.k8s.io/kind/cmd/kind/unprotect"
 	"sigs.k8s.io/kind/cmd/kind/version"
 	logutil "sigs.k8s.io/kind/pkg/log"
+	"sigs.k8s.io/kind/pkg/tracing"
 )
 
 const defaultLevel = logrus.WarnLevel
 
 // Flags for the kind command
 type Flags struct {
-	LogLevel string
+	LogLevel  string
+	LogFormat string
 }
 
 // NewCommand returns a new cobra.Command implementing the root command for kind
@@ -60,14 +83,39 @@ func NewCommand() *cobra.Command {
 		&flags.LogLevel,
 		"loglevel",
 		defaultLevel.String(),
-		"logrus log level "+logutil.LevelsString(),
+		"logrus log level "+logutil.LevelsString()+", debug also logs every command run and its output",
+	)
+	cmd.PersistentFlags().StringVar(
+		&flags.LogFormat,
+		"log-format",
+		"text",
+		"log format [text, json], json writes one object per line to stderr",
 	)
 	// add all top level subcommands
 	cmd.AddCommand(build.NewCommand())
+	cmd.AddCommand(completion.NewCommand())
+	cmd.AddCommand(convert.NewCommand())
+	cmd.AddCommand(cp.NewCommand())
 	cmd.AddCommand(create.NewCommand())
 	cmd.AddCommand(delete.NewCommand())
+	cmd.AddCommand(etcd.NewCommand())
+	cmd.AddCommand(exec.NewCommand())
 	cmd.AddCommand(export.NewCommand())
+	cmd.AddCommand(fault.NewCommand())
+	cmd.AddCommand(gc.NewCommand())
 	cmd.AddCommand(get.NewCommand())
+	cmd.AddCommand(importcmd.NewCommand())
+	cmd.AddCommand(initcmd.NewCommand())
+	cmd.AddCommand(load.NewCommand())
+	cmd.AddCommand(pause.NewCommand())
+	cmd.AddCommand(pool.NewCommand())
+	cmd.AddCommand(preflight.NewCommand())
+	cmd.AddCommand(protect.NewCommand())
+	cmd.AddCommand(recreate.NewCommand())
+	cmd.AddCommand(replace.NewCommand())
+	cmd.AddCommand(resume.NewCommand())
+	cmd.AddCommand(status.NewCommand())
+	cmd.AddCommand(unprotect.NewCommand())
 	cmd.AddCommand(version.NewCommand())
 	return cmd
 }
@@ -81,6 +129,16 @@ func runE(flags *Flags, cmd *cobra.Command, args []string) error {
 		level = parsed
 	}
 	log.SetLevel(level)
+	switch flags.LogFormat {
+	case "text":
+	case "json":
+		// json logs are meant for machines, keep them apart from the
+		// human friendly status output on stdout
+		log.SetOutput(os.Stderr)
+		log.SetFormatter(&log.JSONFormatter{})
+	default:
+		return errors.Errorf("invalid log format '%s', expected one of [text, json]", flags.LogFormat)
+	}
 	return nil
 }
 
@@ -103,8 +161,26 @@ func Main() {
 		// the logger output with our logutil.StatusFriendlyWriter
 		ForceColors: logutil.IsTerminal(log.StandardLogger().Out),
 	})
-	if err := Run(); err != nil {
+	// the steps are traced if an OTLP endpoint is configured
+	tracer, err := tracing.FromEnv()
+	if err != nil {
+		log.Warnf("Tracing is disabled: %v", err)
+	}
+	tracing.SetDefault(tracer)
+	span := tracer.StartSpan("kind", map[string]string{
+		"kind.args": strings.Join(os.Args[1:], " "),
+	})
+	err = Run()
+	span.Finish(err)
+	if err := tracer.Flush(); err != nil {
+		log.Warnf("Failed to export the traces: %v", err)
+	}
+	if err != nil {
 		os.Stderr.WriteString(err.Error() + "\n")
+		// e.g. kind exec exits with the exit code of the command it runs
+		if exitErr, ok := err.(interface{ ExitCode() int }); ok && exitErr.ExitCode() > 0 {
+			os.Exit(exitErr.ExitCode())
+		}
 		os.Exit(-1)
 	}
 }
@@ -0,0 +1,59 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dockerimage implements the `load docker-image` command
+package dockerimage
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+type flagpole struct {
+	Name  string
+	Nodes []string
+}
+
+// NewCommand returns a new cobra.Command for loading docker images into nodes
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Use:   "docker-image <IMAGE> [IMAGE...]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "loads docker images from host into nodes",
+		Long: "Saves the docker images from the local docker daemon and loads them into docker on " +
+			"the control-plane and worker nodes of the cluster",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", "1", "the cluster context name")
+	cmd.Flags().StringSliceVar(&flags.Nodes, "nodes", nil, "comma separated list of the node (container) names to load the images into, e.g. kind-1-worker,kind-1-worker2, defaults to all the nodes")
+	completion.MarkClusterNameFlag(cmd, "name")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	ctx := cluster.NewContext(flags.Name)
+	if err := ctx.LoadDockerImages(args, flags.Nodes...); err != nil {
+		return fmt.Errorf("failed to load images: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package imagearchive implements the `load image-archive` command
+package imagearchive
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+type flagpole struct {
+	Name  string
+	Nodes []string
+}
+
+// NewCommand returns a new cobra.Command for loading image archives into nodes
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Use:   "image-archive <ARCHIVE>",
+		Args:  cobra.ExactArgs(1),
+		Short: "loads an image archive from host into nodes",
+		Long: "Loads the images in a docker image archive, as written by 'docker save', or in an OCI image " +
+			"layout directory or tarball, into docker on the control-plane and worker nodes of the cluster",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", "1", "the cluster context name")
+	cmd.Flags().StringSliceVar(&flags.Nodes, "nodes", nil, "comma separated list of the node (container) names to load the images into, e.g. kind-1-worker,kind-1-worker2, defaults to all the nodes")
+	completion.MarkClusterNameFlag(cmd, "name")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	ctx := cluster.NewContext(flags.Name)
+	if err := ctx.LoadImageArchive(args[0], flags.Nodes...); err != nil {
+		return fmt.Errorf("failed to load image archive: %v", err)
+	}
+	return nil
+}
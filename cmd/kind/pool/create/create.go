@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package create implements the `pool create` command
+package create
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/version"
+)
+
+type flagpole struct {
+	Name  string
+	Image string
+	Size  int
+}
+
+// NewCommand returns a new cobra.Command for creating node pool nodes
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "create",
+		Short: "Adds booted but unjoined nodes to a node pool",
+		Long: "Adds booted but unjoined nodes to a node pool, creating the pool if needed\n\n" +
+			"The nodes are claimed by the clusters created with 'kind create cluster --pool',\n" +
+			"for the nodes running the same image without extra mounts, port mappings or\n" +
+			"other node or network settings.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", cluster.DefaultPool, "the node pool name")
+	cmd.Flags().StringVar(&flags.Image, "image", version.DefaultNodeImage, "node docker image of the pool nodes")
+	cmd.Flags().IntVar(&flags.Size, "size", 1, "number of nodes to add to the pool")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	if flags.Size < 1 {
+		return fmt.Errorf("invalid --size %d, at least one node must be created", flags.Size)
+	}
+	if err := cluster.CreatePool(flags.Name, flags.Image, flags.Size); err != nil {
+		return fmt.Errorf("failed to create pool nodes: %v", err)
+	}
+	fmt.Printf("Added %d nodes to pool %q, claim them with: kind create cluster --pool=%s\n", flags.Size, flags.Name, flags.Name)
+	return nil
+}
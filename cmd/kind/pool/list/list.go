@@ -0,0 +1,55 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package list implements the `pool list` command
+package list
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+// NewCommand returns a new cobra.Command for listing the node pools
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "list",
+		Short: "Lists the node pools with their unclaimed nodes",
+		Long:  "Lists the node pools with the number of unclaimed nodes and their names",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(cmd, args)
+		},
+	}
+	return cmd
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	pools, names, err := cluster.ListPools()
+	if err != nil {
+		return errors.Wrap(err, "error listing pools")
+	}
+	for _, name := range names {
+		fmt.Printf("%s\t%d\n", name, len(pools[name]))
+		for _, node := range pools[name] {
+			fmt.Printf("  %s\n", node.String())
+		}
+	}
+	return nil
+}
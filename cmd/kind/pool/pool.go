@@ -0,0 +1,45 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pool implements the `pool` command
+package pool
+
+import (
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/pool/create"
+	"sigs.k8s.io/kind/cmd/kind/pool/delete"
+	"sigs.k8s.io/kind/cmd/kind/pool/list"
+)
+
+// NewCommand returns a new cobra.Command for node pools
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pool",
+		Short: "Manages pools of pre-provisioned nodes with one of [create, delete, list]",
+		Long: "Manages pools of pre-provisioned nodes with one of [create, delete, list]\n\n" +
+			"Clusters created with 'kind create cluster --pool' claim their nodes from the pool,\n" +
+			"skipping the creation and boot of the node containers.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	// add subcommands
+	cmd.AddCommand(create.NewCommand())
+	cmd.AddCommand(delete.NewCommand())
+	cmd.AddCommand(list.NewCommand())
+	return cmd
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package preflight implements the `preflight` command
+package preflight
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/config/encoding"
+	"sigs.k8s.io/kind/pkg/util"
+)
+
+type flagpole struct {
+	Config    string
+	ImageName string
+}
+
+// NewCommand returns a new cobra.Command for checking host prerequisites
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Use:   "preflight",
+		Short: "Checks the host prerequisites of creating a cluster",
+		Long: "Checks the host prerequisites of creating the cluster configured by --config: " +
+			"the provider runtime, cgroups, inotify limits, memory, disk space, kernel modules and host ports\n\n" +
+			"Exits non-zero if any of the checks failed",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Config, "config", "", "path to a kind config file")
+	cmd.Flags().StringVar(&flags.ImageName, "image", "", "node docker image to use for booting the cluster")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	cfg, err := encoding.Load(flags.Config)
+	if err != nil {
+		return fmt.Errorf("error loading config: %v", err)
+	}
+	if flags.ImageName != "" {
+		for i := range cfg.Nodes {
+			cfg.Nodes[i].Image = flags.ImageName
+		}
+	}
+
+	checks, err := cluster.Preflight(cfg)
+	if err != nil {
+		if configErrors, ok := err.(util.Errors); ok {
+			log.Error("Invalid configuration!")
+			for _, problem := range configErrors.Errors() {
+				log.Error(problem)
+			}
+			return fmt.Errorf("aborting due to invalid configuration")
+		}
+		return err
+	}
+	printChecks(checks)
+	if !cluster.PreflightPassed(checks) {
+		return fmt.Errorf("preflight checks failed")
+	}
+	return nil
+}
+
+func printChecks(checks []cluster.PreflightCheck) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tSTATUS\tDETAILS")
+	for _, check := range checks {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", check.Name, check.Status, check.Message)
+	}
+	w.Flush()
+
+	remediations := false
+	for _, check := range checks {
+		if check.Remediation == "" {
+			continue
+		}
+		if !remediations {
+			fmt.Println()
+			fmt.Println("To remediate:")
+			remediations = true
+		}
+		fmt.Printf("  %s: %s\n", check.Name, check.Remediation)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cluster implements the `protect cluster` command
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+// NewCommand returns a new cobra.Command for protecting a cluster
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster <name>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Protects a cluster from deletion",
+		Long:  "Marks a cluster as protected, so that `kind delete cluster` refuses to delete it without --force",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(cmd, args)
+		},
+	}
+	completion.MarkClusterNameArgs(cmd)
+	return cmd
+}
+
+func runE(cmd *cobra.Command, args []string) error {
+	ctx := cluster.NewContext(args[0])
+	if err := ctx.Protect(); err != nil {
+		return fmt.Errorf("failed to protect cluster: %v", err)
+	}
+	return nil
+}
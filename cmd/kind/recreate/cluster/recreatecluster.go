@@ -0,0 +1,82 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cluster implements the `recreate cluster` command
+package cluster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cluster"
+	"sigs.k8s.io/kind/pkg/cluster/config/encoding"
+)
+
+type flagpole struct {
+	Name        string
+	Config      string
+	ImageName   string
+	KeepVolumes bool
+	Wait        time.Duration
+}
+
+// NewCommand returns a new cobra.Command for recreating a cluster
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Recreates a local Kubernetes cluster",
+		Long:  "Deletes the 'nodes' of a local Kubernetes cluster and creates them again, optionally keeping their data volumes",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", "1", "cluster context name")
+	cmd.Flags().StringVar(&flags.Config, "config", "", "path to a kind config file")
+	cmd.Flags().StringVar(&flags.ImageName, "image", "", "node docker image to use for booting the cluster")
+	cmd.Flags().BoolVar(&flags.KeepVolumes, "keep-volumes", false, "keep the node data volumes, re-attaching them to the new nodes")
+	cmd.Flags().DurationVar(&flags.Wait, "wait", time.Duration(0), "Wait for the API server, the nodes and CoreDNS to be ready, failing with diagnostics on timeout (default 0s)")
+	completion.MarkClusterNameFlag(cmd, "name")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	// load the config
+	cfg, err := encoding.Load(flags.Config)
+	if err != nil {
+		return fmt.Errorf("error loading config: %v", err)
+	}
+	if flags.ImageName != "" {
+		// Apply image override to all the Nodes defined in Config
+		for i := range cfg.Nodes {
+			cfg.Nodes[i].Image = flags.ImageName
+		}
+	}
+
+	// the name in the config is used unless --name is explicitly set
+	name := flags.Name
+	if cfg.Name != "" && !cmd.Flags().Changed("name") {
+		name = cfg.Name
+	}
+	ctx := cluster.NewContext(name)
+	if err := ctx.Recreate(cfg, flags.KeepVolumes, flags.Wait); err != nil {
+		return fmt.Errorf("failed to recreate cluster: %v", err)
+	}
+	return nil
+}
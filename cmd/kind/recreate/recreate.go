@@ -0,0 +1,39 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package recreate implements the `recreate` command
+package recreate
+
+import (
+	"github.com/spf13/cobra"
+
+	recreatecluster "sigs.k8s.io/kind/cmd/kind/recreate/cluster"
+)
+
+// NewCommand returns a new cobra.Command for recreate
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recreate",
+		Short: "Recreates one of [cluster]",
+		Long:  "Recreates one of [cluster], deleting its node containers and creating them again from the same config",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	// add subcommands
+	cmd.AddCommand(recreatecluster.NewCommand())
+	return cmd
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package node implements the `replace node` command
+package node
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+type flagpole struct {
+	Name string
+}
+
+// NewCommand returns a new cobra.Command for replacing a node
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Use:   "node",
+		Short: "Replaces a broken node with a fresh one",
+		Long: "Deletes the node container named by --name, creates a fresh one with the same name and role, " +
+			"and joins it to the cluster again",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", "", "the node (container) name, e.g. kind-1-worker2")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	if flags.Name == "" {
+		return fmt.Errorf("--name is required")
+	}
+	ctx, err := cluster.ContextForNode(flags.Name)
+	if err != nil {
+		return err
+	}
+	if err := ctx.ReplaceNode(flags.Name); err != nil {
+		return fmt.Errorf("failed to replace node: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package replace implements the `replace` command
+package replace
+
+import (
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/replace/node"
+)
+
+// NewCommand returns a new cobra.Command for replace
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replace",
+		Short: "Replaces one of [node]",
+		Long:  "Replaces one of [node], recreating a broken node container and joining it to the cluster again",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+	// add subcommands
+	cmd.AddCommand(node.NewCommand())
+	return cmd
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package status implements the `status` command
+package status
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/cmd/kind/completion"
+	"sigs.k8s.io/kind/pkg/cluster"
+)
+
+type flagpole struct {
+	Name string
+}
+
+// NewCommand returns a new cobra.Command for checking cluster health
+func NewCommand() *cobra.Command {
+	flags := &flagpole{}
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Reports the health of clusters",
+		Long: "Reports the health of all clusters, or only the cluster named by --name\n\n" +
+			"Exits non-zero if any of the reported clusters is unhealthy",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runE(flags, cmd, args)
+		},
+	}
+	cmd.Flags().StringVar(&flags.Name, "name", "", "the cluster context name, defaults to all clusters")
+	completion.MarkClusterNameFlag(cmd, "name")
+	return cmd
+}
+
+func runE(flags *flagpole, cmd *cobra.Command, args []string) error {
+	var contexts []cluster.Context
+	if flags.Name != "" {
+		contexts = []cluster.Context{*cluster.NewContext(flags.Name)}
+	} else {
+		var err error
+		contexts, err = cluster.List()
+		if err != nil {
+			return err
+		}
+	}
+
+	unhealthy := []string{}
+	for _, ctx := range contexts {
+		status, err := ctx.Status()
+		if err != nil {
+			return fmt.Errorf("failed to get status for cluster %q: %v", ctx.Name(), err)
+		}
+		printStatus(status)
+		if !status.Healthy() {
+			unhealthy = append(unhealthy, status.Name)
+		}
+	}
+	if len(unhealthy) > 0 {
+		return fmt.Errorf("unhealthy clusters: %s", strings.Join(unhealthy, ", "))
+	}
+	return nil
+}
+
+func printStatus(status *cluster.Status) {
+	fmt.Printf("Cluster %s: %s\n", status.Name, healthString(status.Healthy()))
+	if len(status.Nodes) == 0 {
+		fmt.Println("  no nodes found")
+		fmt.Println()
+		return
+	}
+	fmt.Printf("  API server reachable: %t\n", status.APIServerReachable)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  NODE\tROLE\tSTATE\tKUBELET\tPENDING TASKS")
+	for _, node := range status.Nodes {
+		kubelet := "-"
+		if node.KubeletHealthy {
+			kubelet = "healthy"
+		} else if node.State == "running" {
+			kubelet = "unhealthy"
+		}
+		pending := "none"
+		if len(node.PendingTasks) > 0 {
+			pending = strings.Join(node.PendingTasks, "; ")
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", node.Name, node.Role, node.State, kubelet, pending)
+	}
+	w.Flush()
+	fmt.Println()
+}
+
+func healthString(healthy bool) string {
+	if healthy {
+		return "healthy"
+	}
+	return "unhealthy"
+}
@@ -18,23 +18,60 @@ limitations under the License.
 package version
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kind/pkg/version"
 )
 
 // Version is the kind CLI version
-const Version = "0.1.0-alpha"
+const Version = version.Version
+
+type flagpole struct {
+	Output string
+}
 
 // NewCommand returns a new cobra.Command for version
 func NewCommand() *cobra.Command {
+	flags := &flagpole{}
 	cmd := &cobra.Command{
-		// TODO(bentheelder): more detailed usage
 		Use:   "version",
 		Short: "prints the kind CLI version",
-		Long:  "prints the kind CLI version",
+		Long: "prints the kind CLI version, git commit, default node image and supported config API versions\n\n" +
+			"With -o json the same information is printed as one JSON object for scripts to check compatibility",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			println(Version)
-			return nil
+			return runE(flags)
 		},
 	}
+	cmd.Flags().StringVarP(&flags.Output, "output", "o", "", "output format, empty for text or json")
 	return cmd
 }
+
+func runE(flags *flagpole) error {
+	info := version.Get()
+	switch flags.Output {
+	case "":
+		commit := info.GitCommit
+		if commit == "" {
+			commit = "unknown"
+		}
+		fmt.Printf("kind version: %s\n", info.Version)
+		fmt.Printf("git commit: %s\n", commit)
+		fmt.Printf("default node image: %s\n", info.DefaultNodeImage)
+		fmt.Printf("config API versions: %s\n", strings.Join(info.ConfigAPIVersions, ", "))
+		fmt.Printf("go version: %s %s\n", info.GoVersion, info.Platform)
+		return nil
+	case "json":
+		encoded, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(os.Stdout, string(encoded))
+		return err
+	}
+	return fmt.Errorf("unknown output format %q, expected json or empty", flags.Output)
+}
@@ -24,6 +24,7 @@ REPO_ROOT=$(git rev-parse --show-toplevel)
 cd "${REPO_ROOT}"
 
 OUT="${REPO_ROOT}/_output/bin"
+GIT_COMMIT=$(git rev-parse HEAD)
 mkdir -p "${OUT}"
 
 CLEAN="false"
@@ -50,7 +51,7 @@ build() {
     local out_path
     out_path="${OUT}/kind-${GOOS}-${GOARCH}"
     echo "${out_path}"
-    go build -o "${out_path}" sigs.k8s.io/kind
+    go build -ldflags "-X sigs.k8s.io/kind/pkg/version.GitCommit=${GIT_COMMIT}" -o "${out_path}" sigs.k8s.io/kind
 }
 
 # TODO(bentheelder): support more platforms
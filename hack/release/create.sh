@@ -29,11 +29,11 @@ fi
 REPO_ROOT=$(git rev-parse --show-toplevel)
 cd "${REPO_ROOT}"
 
-VERSION_FILE="./cmd/kind/version/version.go"
+VERSION_FILE="./pkg/version/version.go"
 
 # update version in go code to $1
 set_version() {
-    sed -i "s/Version = .*/Version = \"${1}\"/" "${VERSION_FILE}"
+    sed -i "s/^const Version = .*/const Version = \"${1}\"/" "${VERSION_FILE}"
     echo "Updated ${VERSION_FILE} for ${1}"
 }
 
@@ -21,11 +21,11 @@ import (
 	"os"
 	"path/filepath"
 
-	log "github.com/sirupsen/logrus"
-
 	"sigs.k8s.io/kind/pkg/build/base/sources"
+	"sigs.k8s.io/kind/pkg/docker"
 	"sigs.k8s.io/kind/pkg/exec"
 	"sigs.k8s.io/kind/pkg/fs"
+	logutil "sigs.k8s.io/kind/pkg/log"
 )
 
 // DefaultImage is the default name:tag of the built base image
@@ -37,9 +37,9 @@ type BuildContext struct {
 	// option fields
 	sourceDir string
 	image     string
+	arch      string
 	// non option fields
 	goCmd string // TODO(bentheelder): should be an option possibly
-	arch  string // TODO(bentheelder): should be an option
 }
 
 // Option is BuildContext configuration option supplied to NewBuildContext
@@ -59,17 +59,28 @@ func WithImage(image string) Option {
 	}
 }
 
+// WithArch configures a NewBuildContext to build the image for the linux
+// platform of arch, e.g. "arm64", rather than for the host architecture
+func WithArch(arch string) Option {
+	return func(b *BuildContext) {
+		b.arch = arch
+	}
+}
+
 // NewBuildContext creates a new BuildContext with
 // default configuration
 func NewBuildContext(options ...Option) *BuildContext {
 	ctx := &BuildContext{
 		image: DefaultImage,
 		goCmd: "go",
-		arch:  "amd64",
 	}
 	for _, option := range options {
 		option(ctx)
 	}
+	// build for the host architecture by default
+	if ctx.arch == "" {
+		ctx.arch = docker.HostArch()
+	}
 	return ctx
 }
 
@@ -97,12 +108,12 @@ func (c *BuildContext) Build() (err error) {
 	} else {
 		err = fs.Copy(c.sourceDir, buildDir)
 		if err != nil {
-			log.Errorf("failed to copy sources to build dir %v", err)
+			logutil.Errorf("failed to copy sources to build dir %v", err)
 			return err
 		}
 	}
 
-	log.Infof("Building base image in: %s", buildDir)
+	logutil.Infof("Building base image in: %s", buildDir)
 
 	// build the entrypoint binary first
 	if err := c.buildEntrypoint(buildDir); err != nil {
@@ -120,30 +131,31 @@ func (c *BuildContext) buildEntrypoint(dir string) error {
 	entrypointDest := filepath.Join(dir, "entrypoint", "entrypoint")
 
 	cmd := exec.Command(c.goCmd, "build", "-o", entrypointDest, entrypointSrc)
-	// TODO(bentheelder): we may need to map between docker image arch and GOARCH
+	// the image architectures are named as GOARCH, see docker.NormalizeArch
 	cmd.SetEnv("GOOS=linux", "GOARCH="+c.arch)
 
 	// actually build
-	log.Info("Building entrypoint binary ...")
+	logutil.Infof("Building entrypoint binary ...")
 	exec.InheritOutput(cmd)
 	if err := cmd.Run(); err != nil {
-		log.Errorf("Entrypoint build Failed! %v", err)
+		logutil.Errorf("Entrypoint build Failed! %v", err)
 		return err
 	}
-	log.Info("Entrypoint build completed.")
+	logutil.Infof("Entrypoint build completed.")
 	return nil
 }
 
 func (c *BuildContext) buildImage(dir string) error {
 	// build the image, tagged as tagImageAs, using the our tempdir as the context
-	cmd := exec.Command("docker", "build", "-t", c.image, dir)
-	log.Info("Starting Docker build ...")
+	args := append([]string{"build", "-t", c.image}, docker.PlatformArgs(c.arch)...)
+	cmd := docker.Command(append(args, dir)...)
+	logutil.Infof("Starting Docker build ...")
 	exec.InheritOutput(cmd)
 	err := cmd.Run()
 	if err != nil {
-		log.Errorf("Docker build Failed! %v", err)
+		logutil.Errorf("Docker build Failed! %v", err)
 		return err
 	}
-	log.Info("Docker build completed.")
+	logutil.Infof("Docker build completed.")
 	return nil
 }
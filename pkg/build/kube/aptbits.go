@@ -20,7 +20,7 @@ import (
 	"fmt"
 	"strings"
 
-	log "github.com/sirupsen/logrus"
+	logutil "sigs.k8s.io/kind/pkg/log"
 )
 
 // AptBits implements Bits for the official upstream debian packages
@@ -33,8 +33,10 @@ func init() {
 	RegisterNamedBits("apt", NewAptBits)
 }
 
-// NewAptBits returns a new Bits backed by the upstream debian packages
-func NewAptBits(kubeRoot string) (bits Bits, err error) {
+// NewAptBits returns a new Bits backed by the upstream debian packages, the
+// packages are installed for the architecture of the build container, so
+// arch is not used
+func NewAptBits(kubeRoot, arch string) (bits Bits, err error) {
 	return &AptBits{}, nil
 }
 
@@ -57,33 +59,33 @@ func (b *AptBits) Install(install InstallContext) error {
 deb http://apt.kubernetes.io/ kubernetes-xenial main
 EOF`
 	if err := install.Run("/bin/sh", "-c", addKey); err != nil {
-		log.Errorf("Adding Kubernetes apt key failed! %v", err)
+		logutil.Errorf("Adding Kubernetes apt key failed! %v", err)
 		return err
 	}
 	if err := install.Run("/bin/sh", "-c", addSources); err != nil {
-		log.Errorf("Adding Kubernetes apt repository failed! %v", err)
+		logutil.Errorf("Adding Kubernetes apt repository failed! %v", err)
 		return err
 	}
 	// install packages
 	if err := install.Run("/bin/sh", "-c", `clean-install kubelet kubeadm kubectl`); err != nil {
-		log.Errorf("Installing Kubernetes packages failed! %v", err)
+		logutil.Errorf("Installing Kubernetes packages failed! %v", err)
 		return err
 	}
 	// get version for version file
 	lines, err := install.CombinedOutputLines("/bin/sh", "-c", `kubelet --version`)
 	if err != nil {
-		log.Errorf("Failed to get Kubernetes version! %v", err)
+		logutil.Errorf("Failed to get Kubernetes version! %v", err)
 		return err
 	}
 	// the output should be one line of the form `Kubernetes ${VERSION}`
 	if len(lines) != 1 {
-		log.Errorf("Failed to parse Kubernetes version with unexpected output: %v", lines)
+		logutil.Errorf("Failed to parse Kubernetes version with unexpected output: %v", lines)
 		return fmt.Errorf("failed to parse Kubernetes version")
 	}
 	// write version file
 	version := strings.SplitN(lines[0], " ", 2)[1]
 	if err := install.Run("/bin/sh", "-c", fmt.Sprintf(`echo "%s" >> /kind/version`, version)); err != nil {
-		log.Errorf("Failed to get Kubernetes version! %v", err)
+		logutil.Errorf("Failed to get Kubernetes version! %v", err)
 		return err
 	}
 	return nil
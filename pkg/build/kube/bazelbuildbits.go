@@ -21,13 +21,14 @@ import (
 	"path"
 	"path/filepath"
 
-	log "github.com/sirupsen/logrus"
 	"sigs.k8s.io/kind/pkg/exec"
+	logutil "sigs.k8s.io/kind/pkg/log"
 )
 
 // BazelBuildBits implements Bits for a local Bazel build
 type BazelBuildBits struct {
 	kubeRoot string
+	arch     string
 }
 
 var _ Bits = &BazelBuildBits{}
@@ -37,15 +38,20 @@ func init() {
 }
 
 // NewBazelBuildBits returns a new Bits backed by bazel build,
-// given kubeRoot, the path to the kubernetes source directory
-func NewBazelBuildBits(kubeRoot string) (bits Bits, err error) {
+// given kubeRoot, the path to the kubernetes source directory, and arch, the
+// architecture to build for
+func NewBazelBuildBits(kubeRoot, arch string) (bits Bits, err error) {
 	return &BazelBuildBits{
 		kubeRoot: kubeRoot,
+		arch:     arch,
 	}, nil
 }
 
 // Build implements Bits.Build
 func (b *BazelBuildBits) Build() error {
+	if err := requireTool("bazel", "bazel"); err != nil {
+		return err
+	}
 	// TODO(bentheelder): support other modes of building
 	// cd to k8s source
 	cwd, err := os.Getwd()
@@ -59,9 +65,8 @@ func (b *BazelBuildBits) Build() error {
 	// build artifacts
 	cmd := exec.Command(
 		"bazel", "build",
-		// TODO(bentheelder): we assume linux amd64, but we could select
-		// this based on Arch etc. throughout, this flag supports GOOS/GOARCH
-		"--platforms=@io_bazel_rules_go//go/toolchain:linux_amd64",
+		// this flag supports GOOS/GOARCH
+		"--platforms=@io_bazel_rules_go//go/toolchain:linux_"+b.arch,
 		// we want the debian packages
 		"//build/debs:debs",
 		// and the docker images
@@ -106,7 +111,7 @@ func (b *BazelBuildBits) Install(install InstallContext) error {
 	// install debians
 	debs := path.Join(base, "debs", "*.deb")
 	if err := install.Run("/bin/sh", "-c", "dpkg -i "+debs); err != nil {
-		log.Errorf("Debian install failed! %v", err)
+		logutil.Errorf("Debian install failed! %v", err)
 		return err
 	}
 
@@ -115,13 +120,13 @@ func (b *BazelBuildBits) Install(install InstallContext) error {
 		"rm -rf /kind/bits/debs/*.deb"+
 			" /var/cache/debconf/* /var/lib/apt/lists/* /var/log/*kg",
 	); err != nil {
-		log.Errorf("Debian cleanup failed! %v", err)
+		logutil.Errorf("Debian cleanup failed! %v", err)
 		return err
 	}
 
 	// enable kubelet service
 	if err := install.Run("systemctl", "enable", "kubelet.service"); err != nil {
-		log.Errorf("Enabling kubelet.service failed! %v", err)
+		logutil.Errorf("Enabling kubelet.service failed! %v", err)
 		return err
 	}
 
@@ -18,6 +18,8 @@ package kube
 
 import (
 	"fmt"
+	osexec "os/exec"
+	"sort"
 	"sync"
 )
 
@@ -51,24 +53,25 @@ type InstallContext interface {
 	CombinedOutputLines(string, ...string) ([]string, error)
 }
 
-// NewNamedBits returns a new Bits by named implementation
+// NewNamedBits returns a new Bits by named implementation, building
+// Kubernetes for the linux platform of arch, e.g. "amd64"
 // currently this includes:
-// "apt" -> NewAptBits(kubeRoot)
-// "bazel" -> NewBazelBuildBits(kubeRoot)
-// "docker" or "make" -> NewDockerBuildBits(kubeRoot)
-func NewNamedBits(name string, kubeRoot string) (bits Bits, err error) {
+// "apt" -> NewAptBits(kubeRoot, arch)
+// "bazel" -> NewBazelBuildBits(kubeRoot, arch)
+// "docker" or "make" -> NewDockerBuildBits(kubeRoot, arch)
+func NewNamedBits(name string, kubeRoot, arch string) (bits Bits, err error) {
 	bitsImpls.Lock()
 	fn, ok := bitsImpls.impls[name]
 	bitsImpls.Unlock()
 	if !ok {
-		return nil, fmt.Errorf("no Bits implementation with name: %s", name)
+		return nil, fmt.Errorf("no Bits implementation with name: %s, expected one of %v", name, NamedBits())
 	}
-	return fn(kubeRoot)
+	return fn(kubeRoot, arch)
 }
 
 // RegisterNamedBits registers a new named Bits implementation for use from
 // NewNamedBits
-func RegisterNamedBits(name string, fn func(string) (Bits, error)) {
+func RegisterNamedBits(name string, fn func(kubeRoot, arch string) (Bits, error)) {
 	bitsImpls.Lock()
 	bitsImpls.impls[name] = fn
 	bitsImpls.Unlock()
@@ -84,10 +87,31 @@ func NamedBitsRegistered(name string) bool {
 	return ok
 }
 
+// NamedBits returns the sorted names in the registry backing NewNamedBits
+func NamedBits() []string {
+	bitsImpls.Lock()
+	names := []string{}
+	for name := range bitsImpls.impls {
+		names = append(names, name)
+	}
+	bitsImpls.Unlock()
+	sort.Strings(names)
+	return names
+}
+
+// requireTool returns an error if the tool needed by the build is not
+// installed
+func requireTool(tool, build string) error {
+	if _, err := osexec.LookPath(tool); err != nil {
+		return fmt.Errorf("%s is required to build kubernetes with the %s build but was not found in PATH", tool, build)
+	}
+	return nil
+}
+
 // internal registry of named bits implementations
 var bitsImpls = struct {
-	impls map[string]func(string) (Bits, error)
+	impls map[string]func(string, string) (Bits, error)
 	sync.Mutex
 }{
-	impls: map[string]func(string) (Bits, error){},
+	impls: map[string]func(string, string) (Bits, error){},
 }
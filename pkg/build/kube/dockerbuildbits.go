@@ -17,6 +17,7 @@ limitations under the License.
 package kube
 
 import (
+	"fmt"
 	"os"
 	"path"
 	"path/filepath"
@@ -27,11 +28,10 @@ import (
 	"sigs.k8s.io/kind/pkg/exec"
 )
 
-// TODO(bentheelder): plumb through arch
-
 // DockerBuildBits implements Bits for a local docker-ized make / bash build
 type DockerBuildBits struct {
 	kubeRoot string
+	arch     string
 }
 
 var _ Bits = &DockerBuildBits{}
@@ -42,15 +42,23 @@ func init() {
 }
 
 // NewDockerBuildBits returns a new Bits backed by the docker-ized build,
-// given kubeRoot, the path to the kubernetes source directory
-func NewDockerBuildBits(kubeRoot string) (bits Bits, err error) {
+// given kubeRoot, the path to the kubernetes source directory, and arch, the
+// architecture to build for
+func NewDockerBuildBits(kubeRoot, arch string) (bits Bits, err error) {
 	return &DockerBuildBits{
 		kubeRoot: kubeRoot,
+		arch:     arch,
 	}, nil
 }
 
 // Build implements Bits.Build
 func (b *DockerBuildBits) Build() error {
+	// build/run.sh builds in a container, make runs on the host
+	for _, tool := range []string{"docker", "make"} {
+		if err := requireTool(tool, "docker"); err != nil {
+			return err
+		}
+	}
 	// cd to k8s source
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -97,7 +105,7 @@ func (b *DockerBuildBits) build() error {
 		"build/run.sh",
 		"make", "all",
 		"WHAT="+strings.Join(what, " "),
-		"KUBE_BUILD_PLATFORMS=linux/amd64",
+		"KUBE_BUILD_PLATFORMS=linux/"+b.arch,
 		// ensure the build isn't especially noisy..
 		"KUBE_VERBOSE=0",
 	)
@@ -113,6 +121,7 @@ func (b *DockerBuildBits) build() error {
 		// we don't want to build these images as we don't use them...
 		"KUBE_BUILD_HYPERKUBE=n",
 		"KUBE_BUILD_CONFORMANCE=n",
+		"KUBE_BUILD_PLATFORMS=linux/"+b.arch,
 	)
 	cmd.SetEnv(os.Environ()...)
 	exec.InheritOutput(cmd)
@@ -141,7 +150,7 @@ func (b *DockerBuildBits) buildBash() error {
 	}
 	cmd := exec.Command(
 		"build/run.sh", "make", "all",
-		"WHAT="+strings.Join(what, " "), "KUBE_BUILD_PLATFORMS=linux/amd64",
+		"WHAT="+strings.Join(what, " "), "KUBE_BUILD_PLATFORMS=linux/"+b.arch,
 	)
 	// ensure the build isn't especially noisy..., inheret existing env
 	cmd.SetEnv(
@@ -157,7 +166,7 @@ func (b *DockerBuildBits) buildBash() error {
 
 	// mimic `make quick-release` internals, clear previous images
 	if err := os.RemoveAll(filepath.Join(
-		".", "_output", "release-images", "amd64",
+		".", "_output", "release-images", b.arch,
 	)); err != nil {
 		return errors.Wrap(err, "failed to remove old release-images")
 	}
@@ -168,7 +177,7 @@ func (b *DockerBuildBits) buildBash() error {
 		"source hack/lib/version.sh;",
 		"source build/lib/release.sh;",
 		"kube::version::get_version_vars;",
-		`kube::release::create_docker_images_for_server "${LOCAL_OUTPUT_ROOT}/dockerized/bin/linux/amd64" "amd64"`,
+		fmt.Sprintf(`kube::release::create_docker_images_for_server "${LOCAL_OUTPUT_ROOT}/dockerized/bin/linux/%[1]s" "%[1]s"`, b.arch),
 	}
 	cmd = exec.Command("bash", "-c", strings.Join(buildImages, " "))
 	cmd.SetEnv(
@@ -187,10 +196,10 @@ func (b *DockerBuildBits) buildBash() error {
 // Paths implements Bits.Paths
 func (b *DockerBuildBits) Paths() map[string]string {
 	binDir := filepath.Join(b.kubeRoot,
-		"_output", "dockerized", "bin", "linux", "amd64",
+		"_output", "dockerized", "bin", "linux", b.arch,
 	)
 	imageDir := filepath.Join(b.kubeRoot,
-		"_output", "release-images", "amd64",
+		"_output", "release-images", b.arch,
 	)
 	return map[string]string{
 		// binaries (hyperkube)
@@ -213,6 +222,12 @@ func (b *DockerBuildBits) Paths() map[string]string {
 
 // Install implements Bits.Install
 func (b *DockerBuildBits) Install(install InstallContext) error {
+	return installBinaries(install)
+}
+
+// installBinaries installs the binaries populated to bin/ and the kubelet
+// service populated to systemd/
+func installBinaries(install InstallContext) error {
 	kindBinDir := path.Join(install.BasePath(), "bin")
 
 	// symlink the kubernetes binaries into $PATH
@@ -0,0 +1,221 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/util/version"
+
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// ReleaseURL is the base URL of the published Kubernetes release and CI
+// build artifacts
+var ReleaseURL = "https://dl.k8s.io"
+
+// the binaries and images downloaded from the release artifacts
+var (
+	releaseBinaries = []string{"kubeadm", "kubelet", "kubectl"}
+	releaseImages   = []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "kube-proxy"}
+)
+
+// kubeletService and kubeadmDropin are the kubelet service files shipped in
+// the Kubernetes packages, the release artifacts do not include them
+const kubeletService = `[Unit]
+Description=kubelet: The Kubernetes Node Agent
+Documentation=https://kubernetes.io/docs/home/
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+ExecStart=/usr/bin/kubelet
+Restart=always
+StartLimitInterval=0
+RestartSec=10
+
+[Install]
+WantedBy=multi-user.target
+`
+
+const kubeadmDropin = `[Service]
+Environment="KUBELET_KUBECONFIG_ARGS=--bootstrap-kubeconfig=/etc/kubernetes/bootstrap-kubelet.conf --kubeconfig=/etc/kubernetes/kubelet.conf"
+Environment="KUBELET_CONFIG_ARGS=--config=/var/lib/kubelet/config.yaml"
+# This is a file that "kubeadm init" and "kubeadm join" generates at runtime, populating the KUBELET_KUBEADM_ARGS variable dynamically
+EnvironmentFile=-/var/lib/kubelet/kubeadm-flags.env
+# This is a file that the user can use for overrides of the kubelet args as a last resort
+EnvironmentFile=-/etc/default/kubelet
+ExecStart=
+ExecStart=/usr/bin/kubelet $KUBELET_KUBECONFIG_ARGS $KUBELET_CONFIG_ARGS $KUBELET_KUBEADM_ARGS $KUBELET_EXTRA_ARGS
+`
+
+// ReleaseBits implements Bits for the published binaries and images of a
+// Kubernetes release or CI build, no Kubernetes source is needed
+type ReleaseBits struct {
+	version string
+	arch    string
+	dir     string
+	// set by Build
+	artifactDir string
+}
+
+var _ Bits = &ReleaseBits{}
+
+// NewReleaseBits returns a new Bits downloading the artifacts of version for
+// arch into dir, reusing the artifacts already downloaded there.
+// version is a release version like v1.13.0, a release marker like stable or
+// latest-1.13, or a CI build version or marker prefixed with ci/ like
+// ci/latest, see ResolveVersion
+func NewReleaseBits(version, arch, dir string) (bits Bits, err error) {
+	return &ReleaseBits{
+		version: version,
+		arch:    arch,
+		dir:     dir,
+	}, nil
+}
+
+// ResolveVersion returns the version and the bucket of ReleaseURL, release
+// or ci, of the Kubernetes version or version marker, e.g. stable-1.13 is
+// resolved with https://dl.k8s.io/release/stable-1.13.txt
+func ResolveVersion(ver string) (resolved, bucket string, err error) {
+	bucket, marker := "release", ver
+	if strings.HasPrefix(ver, "ci/") {
+		bucket, marker = "ci", strings.TrimPrefix(ver, "ci/")
+	}
+	if strings.HasPrefix(marker, "v") {
+		if _, err := version.ParseSemantic(marker); err != nil {
+			return "", "", errors.Wrapf(err, "invalid Kubernetes version %q", ver)
+		}
+		return marker, bucket, nil
+	}
+	url := fmt.Sprintf("%s/%s/%s.txt", ReleaseURL, bucket, marker)
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to resolve Kubernetes version %q", ver)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("failed to resolve Kubernetes version %q: %s returned %s", ver, url, resp.Status)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to resolve Kubernetes version %q", ver)
+	}
+	resolved = strings.TrimSpace(string(body))
+	if _, err := version.ParseSemantic(resolved); err != nil {
+		return "", "", errors.Wrapf(err, "invalid Kubernetes version %q for %q", resolved, ver)
+	}
+	return resolved, bucket, nil
+}
+
+// Build implements Bits.Build, downloading the artifacts
+func (b *ReleaseBits) Build() error {
+	resolved, bucket, err := ResolveVersion(b.version)
+	if err != nil {
+		return err
+	}
+	logutil.Infof("Using the %s artifacts of Kubernetes %s", bucket, resolved)
+	b.artifactDir = filepath.Join(b.dir, bucket, resolved, b.arch)
+	baseURL := fmt.Sprintf("%s/%s/%s/bin/linux/%s", ReleaseURL, bucket, resolved, b.arch)
+	files := map[string]os.FileMode{}
+	for _, binary := range releaseBinaries {
+		files[binary] = 0755
+	}
+	for _, image := range releaseImages {
+		files[image+".tar"] = 0644
+	}
+	for file, mode := range files {
+		if err := download(baseURL+"/"+file, filepath.Join(b.artifactDir, file), mode); err != nil {
+			return err
+		}
+	}
+	// the version and the kubelet service files are not downloaded
+	for file, contents := range map[string]string{
+		"version":         resolved,
+		"kubelet.service": kubeletService,
+		"10-kubeadm.conf": kubeadmDropin,
+	} {
+		if err := ioutil.WriteFile(filepath.Join(b.artifactDir, file), []byte(contents), 0644); err != nil {
+			return errors.Wrap(err, "failed to write build artifact")
+		}
+	}
+	return nil
+}
+
+// download downloads url to dest unless it already exists
+func download(url, dest string, mode os.FileMode) error {
+	if _, err := os.Stat(dest); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return errors.Wrap(err, "failed to make download dir")
+	}
+	logutil.Infof("Downloading %s ...", url)
+	resp, err := http.Get(url)
+	if err != nil {
+		return errors.Wrapf(err, "failed to download %s", url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	// download to a temporary file so that an interrupted download is not
+	// mistaken for a complete one
+	tmp := dest + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	_, err = io.Copy(f, resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to download %s", url)
+	}
+	return os.Rename(tmp, dest)
+}
+
+// Paths implements Bits.Paths
+func (b *ReleaseBits) Paths() map[string]string {
+	paths := map[string]string{
+		filepath.Join(b.artifactDir, "version"):         "version",
+		filepath.Join(b.artifactDir, "kubelet.service"): "systemd/kubelet.service",
+		filepath.Join(b.artifactDir, "10-kubeadm.conf"): "systemd/10-kubeadm.conf",
+	}
+	for _, binary := range releaseBinaries {
+		paths[filepath.Join(b.artifactDir, binary)] = "bin/" + binary
+	}
+	for _, image := range releaseImages {
+		paths[filepath.Join(b.artifactDir, image+".tar")] = "images/" + image + ".tar"
+	}
+	return paths
+}
+
+// Install implements Bits.Install
+func (b *ReleaseBits) Install(install InstallContext) error {
+	return installBinaries(install)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// serveReleases serves the release and CI markers and artifacts
+func serveReleases() func() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/release/stable-1.13.txt":
+			w.Write([]byte("v1.13.4\n"))
+		case "/ci/latest.txt":
+			w.Write([]byte("v1.14.0-beta.1.42+0123456789abcd\n"))
+		case "/release/broken.txt":
+			w.Write([]byte("<html>"))
+		default:
+			if strings.HasPrefix(r.URL.Path, "/release/v1.13.4/bin/linux/amd64/") {
+				w.Write([]byte(r.URL.Path))
+				return
+			}
+			http.NotFound(w, r)
+		}
+	}))
+	original := ReleaseURL
+	ReleaseURL = server.URL
+	return func() {
+		ReleaseURL = original
+		server.Close()
+	}
+}
+
+func TestResolveVersion(t *testing.T) {
+	defer serveReleases()()
+	cases := []struct {
+		Version  string
+		Resolved string
+		Bucket   string
+		Error    bool
+	}{
+		{Version: "v1.13.0", Resolved: "v1.13.0", Bucket: "release"},
+		{Version: "stable-1.13", Resolved: "v1.13.4", Bucket: "release"},
+		{Version: "ci/latest", Resolved: "v1.14.0-beta.1.42+0123456789abcd", Bucket: "ci"},
+		{Version: "ci/v1.14.0-beta.1.42+0123456789abcd", Resolved: "v1.14.0-beta.1.42+0123456789abcd", Bucket: "ci"},
+		{Version: "v1.13", Error: true},
+		{Version: "missing", Error: true},
+		{Version: "broken", Error: true},
+	}
+	for _, tc := range cases {
+		resolved, bucket, err := ResolveVersion(tc.Version)
+		if tc.Error {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %s", tc.Version, resolved)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.Version, err)
+			continue
+		}
+		if resolved != tc.Resolved || bucket != tc.Bucket {
+			t.Errorf("%s: expected %s in %s, got %s in %s", tc.Version, tc.Resolved, tc.Bucket, resolved, bucket)
+		}
+	}
+}
+
+func TestReleaseBitsBuild(t *testing.T) {
+	defer serveReleases()()
+	dir, err := ioutil.TempDir("", "kind-release-bits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bits, err := NewReleaseBits("stable-1.13", "amd64", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bits.Build(); err != nil {
+		t.Fatal(err)
+	}
+	for src, dest := range bits.Paths() {
+		contents, err := ioutil.ReadFile(src)
+		if err != nil {
+			t.Errorf("expected %s for %s: %v", src, dest, err)
+			continue
+		}
+		if dest == "bin/kubeadm" && string(contents) != "/release/v1.13.4/bin/linux/amd64/kubeadm" {
+			t.Errorf("unexpected kubeadm contents %q", contents)
+		}
+		if dest == "version" && string(contents) != "v1.13.4" {
+			t.Errorf("unexpected version %q", contents)
+		}
+	}
+}
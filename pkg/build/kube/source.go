@@ -19,25 +19,49 @@ package kube
 import (
 	"fmt"
 	"go/build"
+	"os"
+	"path/filepath"
 )
 
 // ImportPath is the canonical import path for the kubernetes root package
 // this is used by FindSource
 const ImportPath = "k8s.io/kubernetes"
 
-// FindSource attempts to locate a kubernetes checkout using go's build package
+// FindSource attempts to locate a kubernetes checkout using go's build package,
+// falling back to the current directory if it is a kubernetes checkout
 func FindSource() (root string, err error) {
 	// look up the source the way go build would
 	pkg, err := build.Default.Import(ImportPath, ".", build.FindOnly)
 	if err == nil && maybeKubeDir(pkg.Dir) {
 		return pkg.Dir, nil
 	}
+	// otherwise we may be building from within the checkout, e.g. with modules
+	if cwd, err := os.Getwd(); err == nil && maybeKubeDir(cwd) {
+		return cwd, nil
+	}
 	return "", fmt.Errorf("could not find kubernetes source")
 }
 
+// CheckSource returns an error if root is not a kubernetes source directory
+// the node image can be built from
+func CheckSource(root string) error {
+	if root == "" {
+		return fmt.Errorf("no kubernetes source directory")
+	}
+	// these are used by both the docker and the bazel builds
+	for _, required := range []string{
+		filepath.Join("cmd", "kubeadm"),
+		filepath.Join("hack", "print-workspace-status.sh"),
+	} {
+		if _, err := os.Stat(filepath.Join(root, required)); err != nil {
+			return fmt.Errorf("%s is not a kubernetes source directory, %s not found", root, required)
+		}
+	}
+	return nil
+}
+
 // maybeKubeDir returns true if the dir looks plausibly like a kubernetes
 // source directory
 func maybeKubeDir(dir string) bool {
-	// TODO(bentheelder): consider adding other sanity checks
-	return dir != ""
+	return CheckSource(dir) == nil
 }
@@ -0,0 +1,63 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kind-kube-root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := CheckSource(dir); err == nil {
+		t.Error("expected an empty directory not to be a kubernetes source directory")
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "cmd", "kubeadm"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "hack"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "hack", "print-workspace-status.sh"), nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckSource(dir); err != nil {
+		t.Errorf("expected a kubernetes source directory, got %v", err)
+	}
+	if err := CheckSource(""); err == nil {
+		t.Error("expected an error for no directory")
+	}
+}
+
+func TestNamedBits(t *testing.T) {
+	names := NamedBits()
+	expected := []string{"apt", "bazel", "docker", "make"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, names)
+		}
+	}
+}
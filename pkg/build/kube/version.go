@@ -24,9 +24,9 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 
 	"sigs.k8s.io/kind/pkg/exec"
+	logutil "sigs.k8s.io/kind/pkg/log"
 )
 
 // buildVersionFile creates a file for the kubernetes git version in
@@ -61,7 +61,7 @@ func buildVersionFile(kubeRoot string) error {
 	for _, line := range output {
 		parts := strings.SplitN(line, " ", 2)
 		if len(parts) != 2 {
-			log.Errorf("Could not parse kubernetes version, output: %s", strings.Join(output, "\n"))
+			logutil.Errorf("Could not parse kubernetes version, output: %s", strings.Join(output, "\n"))
 			return fmt.Errorf("could not parse kubernetes version")
 		}
 		if parts[0] == "gitVersion" {
@@ -76,7 +76,7 @@ func buildVersionFile(kubeRoot string) error {
 		}
 	}
 	if !wroteVersion {
-		log.Errorf("Could not obtain kubernetes version, output: %s", strings.Join(output, "\n"))
+		logutil.Errorf("Could not obtain kubernetes version, output: %s", strings.Join(output, "\n"))
 		return fmt.Errorf("could not obtain kubernetes version")
 	}
 	return nil
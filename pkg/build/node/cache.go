@@ -0,0 +1,158 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/docker"
+	"sigs.k8s.io/kind/pkg/fs"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// CacheImageRepository is the local repository of the cached image layers
+const CacheImageRepository = "kind-build-cache"
+
+// cacheVersion is part of every cache key, it must be changed when the
+// cached steps change to invalidate the existing cache
+const cacheVersion = "1"
+
+// DefaultCacheDir returns the default directory of the build cache,
+// $XDG_CACHE_HOME/kind/build or ~/.cache/kind/build
+func DefaultCacheDir() string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "kind", "build")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".cache", "kind", "build")
+}
+
+// buildCache caches the steps of the node image build, the image layers are
+// cached as local images of CacheImageRepository and the pulled images as
+// archives in dir
+type buildCache struct {
+	dir string
+}
+
+// cacheKey returns the cache key identifying parts
+func cacheKey(parts ...string) string {
+	h := sha256.New()
+	io.WriteString(h, cacheVersion)
+	for _, part := range parts {
+		io.WriteString(h, "\x00"+part)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:20]
+}
+
+// layer returns the image caching the layer of step by key
+func (c *buildCache) layer(step, key string) string {
+	return fmt.Sprintf("%s:%s-%s", CacheImageRepository, step, key)
+}
+
+// pull writes the archive of image for arch to dest, pulling and saving it
+// only if it is not cached for kubeVersion yet
+func (c *buildCache) pull(image, arch, kubeVersion, dest string) error {
+	cached := filepath.Join(c.dir, "images", kubeVersion, arch, archiveName(image))
+	if _, err := os.Stat(cached); err == nil {
+		logutil.Infof("Using the cached image: %s", image)
+		return linkOrCopy(cached, dest)
+	}
+	if err := docker.PullForArch(image, arch, 2); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cached), 0755); err != nil {
+		return errors.Wrap(err, "failed to make the image cache dir")
+	}
+	// save to a temporary file first so that an interrupted save is not cached
+	tmp := cached + ".tmp"
+	if err := docker.Save(image, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, cached); err != nil {
+		return errors.Wrap(err, "failed to cache image archive")
+	}
+	return linkOrCopy(cached, dest)
+}
+
+// archiveName returns the file name of the cached archive of image
+func archiveName(image string) string {
+	return strings.NewReplacer("/", "_", ":", "_", "@", "_").Replace(image) + ".tar"
+}
+
+// linkOrCopy hard links src to dst, falling back to copying it when they are
+// not on the same filesystem
+func linkOrCopy(src, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	return fs.CopyFile(src, dst)
+}
+
+// hashPaths returns the hash of the contents of the files and directories in
+// paths, a map of source path to destination path as returned by
+// kube.Bits.Paths
+func hashPaths(paths map[string]string) (string, error) {
+	sources := []string{}
+	for src := range paths {
+		sources = append(sources, src)
+	}
+	sort.Strings(sources)
+	h := sha256.New()
+	for _, src := range sources {
+		io.WriteString(h, "\x00"+paths[src])
+		err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(src, path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "\x00%s\x00%s\x00", rel, info.Mode())
+			if !info.Mode().IsRegular() {
+				if info.Mode()&os.ModeSymlink != 0 {
+					target, err := os.Readlink(path)
+					if err != nil {
+						return err
+					}
+					io.WriteString(h, target)
+				}
+				return nil
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(h, f)
+			return err
+		})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to hash build artifacts")
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
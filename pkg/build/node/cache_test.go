@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheKey(t *testing.T) {
+	if cacheKey("a", "b") == cacheKey("ab") {
+		t.Error("expected the parts to be delimited in the key")
+	}
+	if cacheKey("a", "b") != cacheKey("a", "b") {
+		t.Error("expected the key to be stable")
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	name := archiveName("k8s.gcr.io/pause:3.1")
+	if name != "k8s.gcr.io_pause_3.1.tar" {
+		t.Errorf("unexpected archive name %q", name)
+	}
+}
+
+func TestHashPaths(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kind-hash-paths")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bin := filepath.Join(dir, "bin")
+	if err := os.MkdirAll(bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	kubelet := filepath.Join(bin, "kubelet")
+	if err := ioutil.WriteFile(kubelet, []byte("v1"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	paths := map[string]string{bin: "bin"}
+
+	before, err := hashPaths(paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := hashPaths(paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before != again {
+		t.Error("expected the hash of the same contents to be stable")
+	}
+	if err := ioutil.WriteFile(kubelet, []byte("v2"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	after, err := hashPaths(paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before == after {
+		t.Error("expected the hash to change with the contents")
+	}
+	moved, err := hashPaths(map[string]string{bin: "usr/bin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved == after {
+		t.Error("expected the hash to change with the destination")
+	}
+}
+
+func TestDefaultCacheDir(t *testing.T) {
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME"))
+	os.Setenv("XDG_CACHE_HOME", "/cache")
+	if dir := DefaultCacheDir(); dir != "/cache/kind/build" {
+		t.Errorf("unexpected cache dir %q", dir)
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+	"sigs.k8s.io/kind/pkg/docker"
+	"sigs.k8s.io/kind/pkg/fs"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// isURL returns true if the manifest is to be downloaded
+func isURL(manifest string) bool {
+	return strings.HasPrefix(manifest, "http://") || strings.HasPrefix(manifest, "https://")
+}
+
+// isArchive returns true if the extra image is an image archive rather than
+// an image reference
+func isArchive(image string) bool {
+	info, err := os.Stat(image)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// manifestFiles returns the manifest files of the manifests, in order, the
+// manifests in directories are sorted by file name and URLs are kept as is
+func manifestFiles(manifests []string) ([]string, error) {
+	files := []string{}
+	for _, manifest := range manifests {
+		if isURL(manifest) {
+			files = append(files, manifest)
+			continue
+		}
+		info, err := os.Stat(manifest)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read manifest")
+		}
+		if !info.IsDir() {
+			files = append(files, manifest)
+			continue
+		}
+		entries, err := ioutil.ReadDir(manifest)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read manifests")
+		}
+		names := []string{}
+		for _, entry := range entries {
+			switch filepath.Ext(entry.Name()) {
+			case ".yaml", ".yml", ".json":
+				names = append(names, entry.Name())
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			files = append(files, filepath.Join(manifest, name))
+		}
+	}
+	return files, nil
+}
+
+// extrasKey returns the part of the cache key of the node image identifying
+// the extra images and manifests
+func (c *BuildContext) extrasKey() (string, error) {
+	parts := []string{}
+	local := map[string]string{}
+	for _, image := range c.extraImages {
+		if isArchive(image) {
+			local[image] = "images/" + image
+			continue
+		}
+		// the key changes when a tag refers to another image
+		_, _ = docker.PullIfNotPresentForArch(image, c.arch, 2)
+		id, err := docker.Inspect(image, "{{.Id}}")
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to inspect extra image %s", image)
+		}
+		parts = append(parts, image+"@"+strings.Join(id, ""))
+	}
+	files, err := manifestFiles(c.manifests)
+	if err != nil {
+		return "", err
+	}
+	for i, file := range files {
+		if isURL(file) {
+			parts = append(parts, file)
+			continue
+		}
+		local[file] = fmt.Sprintf("manifests/%d", i)
+	}
+	hash, err := hashPaths(local)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(append(parts, hash), "\x00"), nil
+}
+
+// addExtraImages writes the extra images to imagesDir, and returns their
+// paths in the build container
+func (c *BuildContext) addExtraImages(imagesDir string) ([]string, error) {
+	paths := []string{}
+	for i, image := range c.extraImages {
+		name := fmt.Sprintf("extra-%d.tar", i)
+		dest := filepath.Join(imagesDir, name)
+		if isArchive(image) {
+			logutil.Infof("Adding image archive: %s", image)
+			if err := fs.CopyFile(image, dest); err != nil {
+				return nil, errors.Wrap(err, "failed to copy extra image archive")
+			}
+		} else {
+			logutil.Infof("Adding image: %s", image)
+			if _, err := docker.PullIfNotPresentForArch(image, c.arch, 2); err != nil {
+				return nil, errors.Wrapf(err, "failed to pull extra image %s", image)
+			}
+			if err := docker.Save(image, dest); err != nil {
+				return nil, errors.Wrapf(err, "failed to save extra image %s", image)
+			}
+		}
+		paths = append(paths, path.Join("/build/bits/images", name))
+	}
+	return paths, nil
+}
+
+// addManifests writes the manifests to dir and copies them to
+// consts.NodeImageManifestsDir in the build container, prefixed with their
+// index so that they are applied in order
+func (c *BuildContext) addManifests(dir, containerID string) error {
+	files, err := manifestFiles(c.manifests)
+	if err != nil {
+		return err
+	}
+	manifestsDir := filepath.Join(dir, "manifests")
+	if err := os.MkdirAll(manifestsDir, 0755); err != nil {
+		return errors.Wrap(err, "failed to make manifests dir")
+	}
+	for i, file := range files {
+		name := strings.TrimSuffix(path.Base(file), path.Ext(file))
+		dest := filepath.Join(manifestsDir, fmt.Sprintf("%02d-%s.yaml", i, name))
+		logutil.Infof("Adding manifest: %s", file)
+		if isURL(file) {
+			err = downloadManifest(file, dest)
+		} else {
+			err = fs.CopyFile(file, dest)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to add manifest %s", file)
+		}
+	}
+	if err := docker.Command("exec", containerID, "mkdir", "-p", consts.NodeImageManifestsDir).Run(); err != nil {
+		return errors.Wrap(err, "failed to make manifests dir")
+	}
+	return docker.Command("exec", containerID, "/bin/sh", "-c", "cp /build/manifests/* "+consts.NodeImageManifestsDir).Run()
+}
+
+// downloadManifest downloads the manifest at url to dest
+func downloadManifest(url, dest string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestManifestFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kind-manifests")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"b-csi.yaml", "a-cni.yml", "README.md", "c-operator.json"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	single := filepath.Join(dir, "README.md")
+
+	files, err := manifestFiles([]string{single, dir, "https://example.com/operator.yaml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		single,
+		filepath.Join(dir, "a-cni.yml"),
+		filepath.Join(dir, "b-csi.yaml"),
+		filepath.Join(dir, "c-operator.json"),
+		"https://example.com/operator.yaml",
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+
+	if _, err := manifestFiles([]string{filepath.Join(dir, "missing.yaml")}); err == nil {
+		t.Error("expected an error for a missing manifest")
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -30,12 +31,13 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 
 	"sigs.k8s.io/kind/pkg/build/kube"
+	"sigs.k8s.io/kind/pkg/cluster/consts"
 	"sigs.k8s.io/kind/pkg/docker"
 	"sigs.k8s.io/kind/pkg/exec"
 	"sigs.k8s.io/kind/pkg/fs"
+	logutil "sigs.k8s.io/kind/pkg/log"
 )
 
 // DefaultImage is the default name:tag for the built image
@@ -79,6 +81,73 @@ func WithKuberoot(root string) Option {
 	}
 }
 
+// WithArch configures a NewBuildContext to build the image for the linux
+// platform of arch, e.g. "arm64", rather than for the host architecture,
+// the base image must be available for arch
+func WithArch(arch string) Option {
+	return func(b *BuildContext) {
+		b.arch = arch
+	}
+}
+
+// WithKubernetesVersion configures a NewBuildContext to build the image from
+// the published artifacts of version rather than from the Kubernetes source,
+// see kube.NewReleaseBits for the supported versions
+func WithKubernetesVersion(version string) Option {
+	return func(b *BuildContext) {
+		b.kubernetesVersion = version
+	}
+}
+
+// WithExtraImages configures a NewBuildContext to build images into the node
+// image, for the clusters to come up with them preloaded, each is either the
+// path of an image archive or an image reference to pull
+func WithExtraImages(images ...string) Option {
+	return func(b *BuildContext) {
+		b.extraImages = append(b.extraImages, images...)
+	}
+}
+
+// WithManifests configures a NewBuildContext to build manifests into the
+// node image, for the clusters to apply when created, each is either a file,
+// a directory of .yaml, .yml and .json files, or an http(s) URL
+func WithManifests(manifests ...string) Option {
+	return func(b *BuildContext) {
+		b.manifests = append(b.manifests, manifests...)
+	}
+}
+
+// WithCacheDir configures a NewBuildContext to cache the build steps in dir,
+// see DefaultCacheDir, an empty dir disables the cache
+func WithCacheDir(dir string) Option {
+	return func(b *BuildContext) {
+		b.cacheDir = dir
+	}
+}
+
+// WithReproducible configures a NewBuildContext to build the image
+// reproducibly from pinned inputs, with the times in the image set to epoch,
+// see SOURCE_DATE_EPOCH https://reproducible-builds.org/specs/source-date-epoch/
+func WithReproducible(epoch time.Time) Option {
+	return func(b *BuildContext) {
+		b.sourceDateEpoch = &epoch
+	}
+}
+
+// WithSBOM configures a NewBuildContext to write the SBOM of the built image
+// to path, see SBOM
+func WithSBOM(path string) Option {
+	return func(b *BuildContext) {
+		b.sbomPath = path
+	}
+}
+
+// ArchImage returns the name of the image built for arch when building for
+// more than one architecture, see docker.ArchImage
+func ArchImage(image, arch string) string {
+	return docker.ArchImage(image, arch)
+}
+
 // BuildContext is used to build the kind node image, and contains
 // build configuration
 type BuildContext struct {
@@ -86,10 +155,22 @@ type BuildContext struct {
 	mode      string
 	image     string
 	baseImage string
+	arch      string
+	cacheDir  string
+	// the release or CI version to build from instead of the source
+	kubernetesVersion string
+	// the images and manifests built into the image
+	extraImages []string
+	manifests   []string
+	// the source date epoch of reproducible builds, if enabled
+	sourceDateEpoch *time.Time
+	sbomPath        string
 	// non-option fields
-	arch     string // TODO(bentheelder): this should be an option
 	kubeRoot string
 	bits     kube.Bits
+	cache    *buildCache
+	// the temporary directory of the downloaded release artifacts, if any
+	releaseDir string
 }
 
 // NewBuildContext creates a new BuildContext with default configuration,
@@ -100,12 +181,30 @@ func NewBuildContext(options ...Option) (ctx *BuildContext, err error) {
 		mode:      DefaultMode,
 		image:     DefaultImage,
 		baseImage: DefaultBaseImage,
-		arch:      "amd64",
+		cacheDir:  DefaultCacheDir(),
 	}
 	// apply user options
 	for _, option := range options {
 		option(ctx)
 	}
+	// build for the host architecture by default
+	if ctx.arch == "" {
+		ctx.arch = docker.HostArch()
+	}
+	if ctx.cacheDir != "" {
+		ctx.cache = &buildCache{dir: ctx.cacheDir}
+	}
+	if ctx.sourceDateEpoch != nil {
+		if err := ctx.checkReproducible(); err != nil {
+			return nil, err
+		}
+	}
+	if ctx.kubernetesVersion != "" {
+		return ctx, ctx.initReleaseBits()
+	}
+	if !kube.NamedBitsRegistered(ctx.mode) {
+		return nil, fmt.Errorf("unknown build type %q, expected one of %v", ctx.mode, kube.NamedBits())
+	}
 	if ctx.kubeRoot == "" {
 		// lookup kuberoot unless mode == "apt",
 		// apt should not fail on finding kube root as it does not use it
@@ -117,9 +216,13 @@ func NewBuildContext(options ...Option) (ctx *BuildContext, err error) {
 			}
 		}
 		ctx.kubeRoot = kubeRoot
+	} else if ctx.mode != "apt" {
+		if err := kube.CheckSource(ctx.kubeRoot); err != nil {
+			return nil, err
+		}
 	}
 	// initialize bits
-	bits, err := kube.NewNamedBits(ctx.mode, ctx.kubeRoot)
+	bits, err := kube.NewNamedBits(ctx.mode, ctx.kubeRoot, ctx.arch)
 	if err != nil {
 		return nil, err
 	}
@@ -127,16 +230,69 @@ func NewBuildContext(options ...Option) (ctx *BuildContext, err error) {
 	return ctx, nil
 }
 
+// initReleaseBits initializes the bits downloading the release artifacts,
+// to the cache if enabled so that they are downloaded once
+func (c *BuildContext) initReleaseBits() (err error) {
+	c.mode = "release"
+	dir := ""
+	if c.cache != nil {
+		dir = filepath.Join(c.cache.dir, "releases")
+	} else {
+		c.releaseDir, err = fs.TempDir("", "kind-node-release")
+		if err != nil {
+			return err
+		}
+		dir = c.releaseDir
+	}
+	c.bits, err = kube.NewReleaseBits(c.kubernetesVersion, c.arch, dir)
+	return err
+}
+
 // Build builds the cluster node image, the sourcedir must be set on
 // the BuildContext
 func (c *BuildContext) Build() (err error) {
+	if c.releaseDir != "" {
+		defer os.RemoveAll(c.releaseDir)
+	}
+	// the Kubernetes build stamps the binaries with the source date epoch
+	if c.sourceDateEpoch != nil {
+		os.Setenv("SOURCE_DATE_EPOCH", fmt.Sprintf("%d", c.sourceDateEpoch.Unix()))
+	}
 	// ensure kubernetes build is up to date first
-	log.Infof("Starting to build Kubernetes")
+	logutil.Infof("Starting to build Kubernetes")
 	if err = c.bits.Build(); err != nil {
-		log.Errorf("Failed to build Kubernetes: %v", err)
+		logutil.Errorf("Failed to build Kubernetes: %v", err)
 		return errors.Wrap(err, "failed to build kubernetes")
 	}
-	log.Infof("Finished building Kubernetes")
+	logutil.Infof("Finished building Kubernetes")
+
+	// the node image is cached by the base image and the built artifacts,
+	// apt installs the latest packages at build time so it is not cached
+	cached := ""
+	if c.cache != nil && c.mode != "apt" {
+		bitsHash, err := hashPaths(c.bits.Paths())
+		if err != nil {
+			return err
+		}
+		// the key is computed from the ID of the base image
+		_, _ = docker.PullIfNotPresentForArch(c.baseImage, c.arch, 4)
+		extrasKey, err := c.extrasKey()
+		if err != nil {
+			return err
+		}
+		keyParts := []string{c.baseImageID(), c.arch, c.mode, bitsHash, extrasKey}
+		if c.sourceDateEpoch != nil {
+			keyParts = append(keyParts, fmt.Sprintf("reproducible-%d", c.sourceDateEpoch.Unix()))
+		}
+		cached = c.cache.layer("node", cacheKey(keyParts...))
+		if docker.ImageExists(cached) {
+			logutil.Infof("Using the cached node image %s, the Kubernetes build did not change", cached)
+			if err := docker.Tag(cached, c.image); err != nil {
+				return err
+			}
+			return c.writeSBOM()
+		}
+	}
 
 	// create tempdir to build the image in
 	buildDir, err := fs.TempDir("", "kind-node-image")
@@ -145,7 +301,7 @@ func (c *BuildContext) Build() (err error) {
 	}
 	defer os.RemoveAll(buildDir)
 
-	log.Infof("Building node image in: %s", buildDir)
+	logutil.Infof("Building node image in: %s", buildDir)
 
 	// populate the kubernetes artifacts first
 	if err := c.populateBits(buildDir); err != nil {
@@ -153,7 +309,15 @@ func (c *BuildContext) Build() (err error) {
 	}
 
 	// then the perform the actual docker image build
-	return c.buildImage(buildDir)
+	if err := c.buildImage(buildDir); err != nil {
+		return err
+	}
+	if cached != "" {
+		if err := docker.Tag(c.image, cached); err != nil {
+			logutil.Warnf("Failed to cache the node image: %v", err)
+		}
+	}
+	return c.writeSBOM()
 }
 
 func (c *BuildContext) populateBits(buildDir string) error {
@@ -180,18 +344,46 @@ var imageRegex = regexp.MustCompile(`images/[^/]+\.tar`)
 
 // returns a set of image tags that will be sideloaded
 func (c *BuildContext) getBuiltImages() (sets.String, error) {
-	bitPaths := c.bits.Paths()
+	archives, err := c.getBuiltImageArchives()
+	if err != nil {
+		return nil, err
+	}
 	images := sets.NewString()
+	for image := range archives {
+		images.Insert(image)
+	}
+	return images, nil
+}
+
+// returns a map of the image tags that will be sideloaded to the path of
+// their archive in the bits
+func (c *BuildContext) getBuiltImageArchives() (map[string]string, error) {
+	bitPaths := c.bits.Paths()
+	archives := map[string]string{}
 	for src, dest := range bitPaths {
 		if imageRegex.MatchString(dest) {
 			tags, err := docker.GetArchiveTags(src)
 			if err != nil {
 				return nil, err
 			}
-			images.Insert(tags...)
+			for _, tag := range tags {
+				archives[tag] = dest
+			}
 		}
 	}
-	return images, nil
+	return archives, nil
+}
+
+// imageKey returns the name and tag of image without the registry and the
+// architecture suffix, e.g. kube-apiserver:v1.13.0 for
+// gcr.io/k8s-staging-ci-images/kube-apiserver-amd64:v1.13.0
+func imageKey(image, arch string) string {
+	name, tag := image, ""
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		name, tag = image[:i], image[i:]
+	}
+	name = name[strings.LastIndex(name, "/")+1:]
+	return strings.TrimSuffix(name, "-"+arch) + tag
 }
 
 // BuildContainerLabelKey is applied to each build container
@@ -214,8 +406,7 @@ func (ic *installContext) BasePath() string {
 }
 
 func (ic *installContext) Run(command string, args ...string) error {
-	cmd := exec.Command(
-		"docker",
+	cmd := docker.Command(
 		append(
 			[]string{"exec", ic.containerID, command},
 			args...,
@@ -226,8 +417,7 @@ func (ic *installContext) Run(command string, args ...string) error {
 }
 
 func (ic *installContext) CombinedOutputLines(command string, args ...string) ([]string, error) {
-	cmd := exec.Command(
-		"docker",
+	cmd := docker.Command(
 		append(
 			[]string{"exec", ic.containerID, command},
 			args...,
@@ -238,7 +428,7 @@ func (ic *installContext) CombinedOutputLines(command string, args ...string) ([
 
 func (c *BuildContext) buildImage(dir string) error {
 	// build the image, tagged as tagImageAs, using the our tempdir as the context
-	log.Info("Starting image build ...")
+	logutil.Infof("Starting image build ...")
 	// create build container
 	// NOTE: we are using docker run + docker commit so we can install
 	// debians without permanently copying them into the image.
@@ -249,17 +439,17 @@ func (c *BuildContext) buildImage(dir string) error {
 	// ensure we will delete it
 	if containerID != "" {
 		defer func() {
-			exec.Command("docker", "rm", "-f", "-v", containerID).Run()
+			docker.Command("rm", "-f", "-v", containerID).Run()
 		}()
 	}
 	if err != nil {
-		log.Errorf("Image build Failed! %v", err)
+		logutil.Errorf("Image build Failed! %v", err)
 		return err
 	}
 
 	// helper we will use to run "build steps"
 	execInBuild := func(command ...string) error {
-		cmd := exec.Command("docker",
+		cmd := docker.Command(
 			append(
 				[]string{"exec", containerID},
 				command...,
@@ -269,15 +459,9 @@ func (c *BuildContext) buildImage(dir string) error {
 		return cmd.Run()
 	}
 
-	// make artifacts directory
-	if err = execInBuild("mkdir", "/kind/"); err != nil {
-		log.Errorf("Image build Failed! %v", err)
-		return err
-	}
-
 	// copy artifacts in
 	if err = execInBuild("rsync", "-r", "/build/bits/", "/kind/"); err != nil {
-		log.Errorf("Image build Failed! %v", err)
+		logutil.Errorf("Image build Failed! %v", err)
 		return err
 	}
 
@@ -287,7 +471,7 @@ func (c *BuildContext) buildImage(dir string) error {
 		containerID: containerID,
 	}
 	if err = c.bits.Install(ic); err != nil {
-		log.Errorf("Image build Failed! %v", err)
+		logutil.Errorf("Image build Failed! %v", err)
 		return err
 	}
 
@@ -295,25 +479,49 @@ func (c *BuildContext) buildImage(dir string) error {
 	if err = execInBuild("/bin/sh", "-c",
 		`echo "KUBELET_EXTRA_ARGS=--fail-swap-on=false" >> /etc/default/kubelet`,
 	); err != nil {
-		log.Errorf("Image build Failed! %v", err)
+		logutil.Errorf("Image build Failed! %v", err)
 		return err
 	}
 
 	// pre-pull images that were not part of the build
 	if err = c.prePullImages(dir, containerID); err != nil {
-		log.Errorf("Image build Failed! %v", err)
+		logutil.Errorf("Image build Failed! %v", err)
 		return err
 	}
 
+	// build in the extra manifests, labeling the image for the clusters to
+	// apply them
+	commitArgs := []string{"commit"}
+	if len(c.manifests) > 0 {
+		if err = c.addManifests(dir, containerID); err != nil {
+			logutil.Errorf("Image build Failed! %v", err)
+			return err
+		}
+		commitArgs = append(commitArgs, "--change", fmt.Sprintf("LABEL %s=true", consts.NodeImageManifestsKey))
+	}
+
+	if c.sourceDateEpoch != nil {
+		if err = c.clampTimes(containerID); err != nil {
+			logutil.Errorf("Image build Failed! %v", err)
+			return err
+		}
+	}
+
 	// Save the image changes to a new image
-	cmd := exec.Command("docker", "commit", containerID, c.image)
+	cmd := docker.Command(append(commitArgs, containerID, c.image)...)
 	exec.InheritOutput(cmd)
 	if err = cmd.Run(); err != nil {
-		log.Errorf("Image build Failed! %v", err)
+		logutil.Errorf("Image build Failed! %v", err)
 		return err
 	}
+	if c.sourceDateEpoch != nil {
+		if err = c.normalizeImage(dir); err != nil {
+			logutil.Errorf("Image build Failed! %v", err)
+			return err
+		}
+	}
 
-	log.Info("Image build completed.")
+	logutil.Infof("Image build completed.")
 	return nil
 }
 
@@ -322,13 +530,22 @@ func (c *BuildContext) prePullImages(dir, containerID string) error {
 	// first get the images we actually built
 	builtImages, err := c.getBuiltImages()
 	if err != nil {
-		log.Errorf("Image build Failed! %v", err)
+		logutil.Errorf("Image build Failed! %v", err)
+		return err
+	}
+	builtArchives, err := c.getBuiltImageArchives()
+	if err != nil {
+		logutil.Errorf("Image build Failed! %v", err)
 		return err
 	}
+	builtKeys := map[string]string{}
+	for image, dest := range builtArchives {
+		builtKeys[imageKey(image, c.arch)] = dest
+	}
 
 	// helpers to run things in the build container
 	execInBuild := func(command ...string) error {
-		cmd := exec.Command("docker",
+		cmd := docker.Command(
 			append(
 				[]string{"exec", containerID},
 				command...,
@@ -338,7 +555,7 @@ func (c *BuildContext) prePullImages(dir, containerID string) error {
 		return cmd.Run()
 	}
 	combinedOutputLinesInBuild := func(command ...string) ([]string, error) {
-		cmd := exec.Command("docker",
+		cmd := docker.Command(
 			append(
 				[]string{"exec", containerID},
 				command...,
@@ -351,11 +568,11 @@ func (c *BuildContext) prePullImages(dir, containerID string) error {
 	// we need this to ask kubeadm what images we need
 	rawVersion, err := combinedOutputLinesInBuild("cat", "/kind/version")
 	if err != nil {
-		log.Errorf("Image build Failed! %v", err)
+		logutil.Errorf("Image build Failed! %v", err)
 		return err
 	}
 	if len(rawVersion) != 1 {
-		log.Errorf("Image build Failed! %v", err)
+		logutil.Errorf("Image build Failed! %v", err)
 		return fmt.Errorf("invalid kubernetes version file")
 	}
 
@@ -392,26 +609,39 @@ func (c *BuildContext) prePullImages(dir, containerID string) error {
 
 	movePulled := []string{"mv"}
 	for i, image := range requiredImages {
-		if !builtImages.Has(image) {
-			fmt.Printf("Pulling: %s\n", image)
-			err := docker.Pull(image, 2)
-			if err != nil {
-				return err
+		if builtImages.Has(image) {
+			continue
+		}
+		// the image may be built under another name, e.g. release and CI
+		// builds publish images with the architecture suffix or from another
+		// registry, tag it as kubeadm expects instead of pulling it
+		if dest, ok := builtKeys[imageKey(image, c.arch)]; ok {
+			logutil.Infof("Tagging the built image %s as %s", dest, image)
+			if err := docker.AddArchiveTags(path.Join(dir, "bits", dest), image); err != nil {
+				return errors.Wrap(err, "failed to tag built image")
 			}
-			// TODO(bentheelder): generate a friendlier name
-			pullName := fmt.Sprintf("%d.tar", i)
-			pullTo := path.Join(imagesDir, pullName)
-			err = docker.Save(image, pullTo)
-			if err != nil {
+			if err := execInBuild("cp", path.Join("/build/bits", dest), path.Join("/kind", dest)); err != nil {
 				return err
 			}
-			movePulled = append(movePulled, fmt.Sprintf("/build/bits/images/%s", pullName))
+			continue
+		}
+		// TODO(bentheelder): generate a friendlier name
+		pullName := fmt.Sprintf("%d.tar", i)
+		pullTo := path.Join(imagesDir, pullName)
+		if err := c.pullImage(image, rawVersion[0], pullTo); err != nil {
+			return err
 		}
+		movePulled = append(movePulled, fmt.Sprintf("/build/bits/images/%s", pullName))
+	}
+	extraImages, err := c.addExtraImages(imagesDir)
+	if err != nil {
+		return err
 	}
+	movePulled = append(movePulled, extraImages...)
 
 	// Create the /kind/images directory inside the container.
 	if err = execInBuild("mkdir", "-p", DockerImageArchives); err != nil {
-		log.Errorf("Image build Failed! %v", err)
+		logutil.Errorf("Image build Failed! %v", err)
 		return err
 	}
 	movePulled = append(movePulled, DockerImageArchives)
@@ -421,26 +651,118 @@ func (c *BuildContext) prePullImages(dir, containerID string) error {
 	// make sure we own the tarballs
 	// TODO(bentheelder): someday we might need a different user ...
 	if err = execInBuild("chown", "-R", "root", DockerImageArchives); err != nil {
-		log.Errorf("Image build Failed! %v", err)
+		logutil.Errorf("Image build Failed! %v", err)
 		return err
 	}
 	return nil
 }
 
+// pullImage writes the archive of image to dest, from the cache if enabled
+func (c *BuildContext) pullImage(image, kubeVersion, dest string) error {
+	if c.cache != nil {
+		return c.cache.pull(image, c.arch, kubeVersion, dest)
+	}
+	fmt.Printf("Pulling: %s\n", image)
+	if err := docker.PullForArch(image, c.arch, 2); err != nil {
+		return err
+	}
+	return docker.Save(image, dest)
+}
+
+// setupBase performs the build steps on the base image that do not depend on
+// the Kubernetes build, they are cached as a layer
+func setupBase(containerID string) error {
+	// make artifacts directory
+	return docker.Command("exec", containerID, "mkdir", "/kind/").Run()
+}
+
+// baseImageID returns the ID of the base image, so that the cache keys change
+// when a custom base image is rebuilt with the same tag, or its name if it
+// is not present locally
+func (c *BuildContext) baseImageID() string {
+	lines, err := docker.Inspect(c.baseImage, "{{.Id}}")
+	if err != nil || len(lines) != 1 {
+		return c.baseImage
+	}
+	return strings.Trim(lines[0], "'")
+}
+
+// baseLayer returns the image with the base setup done, building and caching
+// it if necessary
+func (c *BuildContext) baseLayer() (string, error) {
+	cached := c.cache.layer("base", cacheKey(c.baseImageID(), c.arch))
+	if docker.ImageExists(cached) {
+		logutil.Infof("Using the cached base layer %s", cached)
+		return cached, nil
+	}
+	if err := ValidateBaseImage(c.baseImage, c.arch); err != nil {
+		return "", err
+	}
+	id, err := c.runBuildContainer(c.baseImage, "")
+	if id != "" {
+		defer func() {
+			docker.Command("rm", "-f", "-v", id).Run()
+		}()
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := setupBase(id); err != nil {
+		return "", errors.Wrap(err, "failed to set up the base layer")
+	}
+	if err := docker.Commit(id, cached); err != nil {
+		return "", errors.Wrap(err, "failed to cache the base layer")
+	}
+	return cached, nil
+}
+
 func (c *BuildContext) createBuildContainer(buildDir string) (id string, err error) {
 	// attempt to explicitly pull the image if it doesn't exist locally
 	// we don't care if this errors, we'll still try to run which also pulls
-	_, _ = docker.PullIfNotPresent(c.baseImage, 4)
+	_, _ = docker.PullIfNotPresentForArch(c.baseImage, c.arch, 4)
+	if err := docker.CheckEmulation(c.baseImage, c.arch); err != nil {
+		return "", err
+	}
+	// the cached base layer is committed with the time it was built, so
+	// reproducible builds set up the base in the build container instead
+	if c.cache == nil || c.sourceDateEpoch != nil {
+		if err := ValidateBaseImage(c.baseImage, c.arch); err != nil {
+			return "", err
+		}
+		id, err = c.runBuildContainer(c.baseImage, buildDir)
+		if err != nil {
+			return id, err
+		}
+		if err := setupBase(id); err != nil {
+			logutil.Errorf("Image build Failed! %v", err)
+			return id, err
+		}
+		return id, nil
+	}
+	image, err := c.baseLayer()
+	if err != nil {
+		return "", err
+	}
+	return c.runBuildContainer(image, buildDir)
+}
+
+// runBuildContainer runs a build container from image, mounting buildDir
+// at /build if set
+func (c *BuildContext) runBuildContainer(image, buildDir string) (id string, err error) {
+	args := []string{
+		"-d", // make the client exit while the container continues to run
+		// label the container to make them easier to track
+		"--label", fmt.Sprintf("%s=%s", BuildContainerLabelKey, time.Now().Format(time.RFC3339Nano)),
+	}
+	if buildDir != "" {
+		args = append(args, "-v", fmt.Sprintf("%s:/build", buildDir))
+	}
 	id, err = docker.Run(
-		c.baseImage,
-		[]string{
-			"-d", // make the client exit while the container continues to run
-			// label the container to make them easier to track
-			"--label", fmt.Sprintf("%s=%s", BuildContainerLabelKey, time.Now().Format(time.RFC3339Nano)),
-			"-v", fmt.Sprintf("%s:/build", buildDir),
+		image,
+		append(append(args,
 			// the container should hang forever so we can exec in it
 			"--entrypoint=sleep",
-		},
+		), docker.PlatformArgs(c.arch)...),
 		[]string{
 			"infinity", // sleep infinitely to keep the container around
 		},
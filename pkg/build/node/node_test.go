@@ -0,0 +1,36 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"testing"
+)
+
+func TestImageKey(t *testing.T) {
+	cases := map[string]string{
+		"k8s.gcr.io/kube-apiserver:v1.13.0":                            "kube-apiserver:v1.13.0",
+		"k8s.gcr.io/kube-apiserver-amd64:v1.13.0":                      "kube-apiserver:v1.13.0",
+		"gcr.io/k8s-staging-ci-images/kube-proxy-amd64:v1.14.0-beta_1": "kube-proxy:v1.14.0-beta_1",
+		"localhost:5000/kube-scheduler":                                "kube-scheduler",
+		"kube-controller-manager-arm64:v1.13.0":                        "kube-controller-manager-arm64:v1.13.0",
+	}
+	for image, expected := range cases {
+		if key := imageKey(image, "amd64"); key != expected {
+			t.Errorf("%s: expected %s, got %s", image, expected, key)
+		}
+	}
+}
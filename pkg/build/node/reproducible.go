@@ -0,0 +1,123 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"sigs.k8s.io/kind/pkg/docker"
+	"sigs.k8s.io/kind/pkg/exec"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// checkReproducible returns an error if the inputs of the build are not
+// pinned, so that building again would not build the same image
+func (c *BuildContext) checkReproducible() error {
+	if c.kubernetesVersion != "" {
+		// release markers and ci/latest resolve to new builds over time
+		if _, err := version.ParseSemantic(strings.TrimPrefix(c.kubernetesVersion, "ci/")); err != nil {
+			return fmt.Errorf("reproducible builds require an exact Kubernetes version, not %q", c.kubernetesVersion)
+		}
+	} else if c.mode == "apt" {
+		return fmt.Errorf("apt builds install the latest packages and can not be reproducible, build from an exact Kubernetes version instead")
+	}
+	for _, image := range c.extraImages {
+		if !isArchive(image) && !pinned(image) {
+			return fmt.Errorf("reproducible builds require the extra image %q to be pinned by digest", image)
+		}
+	}
+	for _, manifest := range c.manifests {
+		if isURL(manifest) {
+			logutil.Warnf("The manifest %s is downloaded at build time, the SBOM records its digest", manifest)
+		}
+	}
+	if !pinned(c.baseImage) {
+		logutil.Warnf("The base image %s is not pinned by digest, the SBOM records the digest it was built from", c.baseImage)
+	}
+	return nil
+}
+
+// pinned returns true if image is referenced by digest
+func pinned(image string) bool {
+	return strings.Contains(image, "@sha256:")
+}
+
+// clampTimes sets the modification times of the files the build added or
+// changed in the build container to the source date epoch. docker commits
+// the layer walking the files in lexical order, so with the times clamped
+// the layer only depends on the contents of the files
+func (c *BuildContext) clampTimes(containerID string) error {
+	lines, err := exec.CombinedOutputLines(docker.Command("diff", containerID))
+	if err != nil {
+		return err
+	}
+	paths := changedPaths(lines)
+	if len(paths) == 0 {
+		return nil
+	}
+	cmd := docker.Command(
+		"exec", "-i", containerID,
+		"xargs", "-0", "-r",
+		"touch", "--no-dereference", fmt.Sprintf("--date=@%d", c.sourceDateEpoch.Unix()),
+	)
+	cmd.SetStdin(strings.NewReader(strings.Join(paths, "\x00")))
+	return exec.RunLoggingOutputOnFail(cmd)
+}
+
+// changedPaths parses the paths added or changed from `docker diff` output,
+// lines of the form "<A|C|D> <path>"
+func changedPaths(lines []string) []string {
+	paths := []string{}
+	for _, line := range lines {
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) != 2 || parts[0] == "D" {
+			continue
+		}
+		paths = append(paths, parts[1])
+	}
+	return paths
+}
+
+// normalizeImage sets the created times of the built image to the source
+// date epoch, and drops the build container from its config, by saving and
+// loading it again, see docker.NormalizeArchive
+func (c *BuildContext) normalizeImage(dir string) error {
+	committed, err := docker.ImageID(c.image)
+	if err != nil {
+		return err
+	}
+	archive := filepath.Join(dir, "node-image.tar")
+	defer os.Remove(archive)
+	if err := docker.Save(c.image, archive); err != nil {
+		return err
+	}
+	if err := docker.NormalizeArchive(archive, *c.sourceDateEpoch); err != nil {
+		return err
+	}
+	if err := docker.Load(archive); err != nil {
+		return err
+	}
+	// loading moved the tag to the normalized image, the committed one is
+	// no longer needed
+	_ = docker.Command("rmi", committed).Run()
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParsePackages(t *testing.T) {
+	lines := []string{
+		"bash\t4.4-5\tamd64",
+		"tzdata\t2018e-0ubuntu0.18.04\tall",
+		"not a package",
+	}
+	expected := []SBOMPackage{
+		{Name: "bash", Version: "4.4-5", Arch: "amd64"},
+		{Name: "tzdata", Version: "2018e-0ubuntu0.18.04", Arch: "all"},
+	}
+	if packages := parsePackages(lines); !reflect.DeepEqual(packages, expected) {
+		t.Errorf("expected %v, got %v", expected, packages)
+	}
+}
+
+func TestParseSHA256Sums(t *testing.T) {
+	lines := []string{
+		"0f1e  /kind/bin/kubeadm",
+		"a2b3  /kind/images/with  spaces.tar",
+	}
+	expected := []SBOMFile{
+		{Path: "/kind/bin/kubeadm", SHA256: "0f1e"},
+		{Path: "/kind/images/with  spaces.tar", SHA256: "a2b3"},
+	}
+	if files := parseSHA256Sums(lines); !reflect.DeepEqual(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+}
+
+func TestChangedPaths(t *testing.T) {
+	lines := []string{
+		"C /etc",
+		"A /etc/default/kubelet",
+		"D /var/log/removed.log",
+		"A /kind/with space",
+	}
+	expected := []string{"/etc", "/etc/default/kubelet", "/kind/with space"}
+	if paths := changedPaths(lines); !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected %v, got %v", expected, paths)
+	}
+}
+
+func TestCheckReproducible(t *testing.T) {
+	archive, err := ioutil.TempFile("", "app.tar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive.Close()
+	defer os.Remove(archive.Name())
+	cases := []struct {
+		Name    string
+		Context BuildContext
+		Error   bool
+	}{
+		{Name: "source", Context: BuildContext{mode: "docker"}},
+		{Name: "apt", Context: BuildContext{mode: "apt"}, Error: true},
+		{Name: "exact version", Context: BuildContext{kubernetesVersion: "v1.13.4"}},
+		{Name: "ci version", Context: BuildContext{kubernetesVersion: "ci/v1.14.0-alpha.0.1+0123456789abcd"}},
+		{Name: "release marker", Context: BuildContext{kubernetesVersion: "stable-1.13"}, Error: true},
+		{Name: "pinned extra image", Context: BuildContext{mode: "docker", extraImages: []string{"example.com/app@sha256:0123"}}},
+		{Name: "extra image archive", Context: BuildContext{mode: "docker", extraImages: []string{archive.Name()}}},
+		{Name: "extra image tag", Context: BuildContext{mode: "docker", extraImages: []string{"example.com/app:v1"}}, Error: true},
+	}
+	for _, tc := range cases {
+		tc.Context.baseImage = "example.com/base@sha256:0123"
+		err := tc.Context.checkReproducible()
+		if tc.Error && err == nil {
+			t.Errorf("%s: expected an error", tc.Name)
+		} else if !tc.Error && err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.Name, err)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/docker"
+	"sigs.k8s.io/kind/pkg/exec"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// SBOM is the software bill of materials of a node image, recording what was
+// built into it for review
+type SBOM struct {
+	// Image is the name of the node image and ImageID its ID, the digest of
+	// its config, which reproducible builds of the same inputs share
+	Image   string `json:"image"`
+	ImageID string `json:"imageID"`
+	Arch    string `json:"arch"`
+	// BaseImage is the base image the node image was built from, BaseImageID
+	// its ID and BaseImageDigests its registry digests
+	BaseImage        string   `json:"baseImage"`
+	BaseImageID      string   `json:"baseImageID"`
+	BaseImageDigests []string `json:"baseImageDigests,omitempty"`
+	// KubernetesVersion is the version of Kubernetes in the image and
+	// BuildType how it was built, see kube.Bits
+	KubernetesVersion string `json:"kubernetesVersion"`
+	BuildType         string `json:"buildType"`
+	// SourceDateEpoch is set for reproducible builds
+	SourceDateEpoch *int64 `json:"sourceDateEpoch,omitempty"`
+	// Packages are the debian packages installed in the image
+	Packages []SBOMPackage `json:"packages"`
+	// Images are the image archives the nodes load when they boot
+	Images []SBOMImage `json:"images"`
+	// Files are the files kind installed under /kind, including the images
+	Files []SBOMFile `json:"files"`
+}
+
+// SBOMPackage is a debian package installed in the node image
+type SBOMPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Arch    string `json:"arch"`
+}
+
+// SBOMImage is an image archive built into the node image
+type SBOMImage struct {
+	File     string   `json:"file"`
+	ID       string   `json:"id"`
+	RepoTags []string `json:"repoTags"`
+}
+
+// SBOMFile is a file built into the node image
+type SBOMFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// writeSBOM writes the SBOM of the built image if enabled, from a container
+// of the image
+func (c *BuildContext) writeSBOM() error {
+	if c.sbomPath == "" {
+		return nil
+	}
+	id, err := c.runBuildContainer(c.image, "")
+	if id != "" {
+		defer func() {
+			docker.Command("rm", "-f", "-v", id).Run()
+		}()
+	}
+	if err != nil {
+		return err
+	}
+	sbom, err := c.collectSBOM(id)
+	if err != nil {
+		return errors.Wrap(err, "failed to collect the SBOM")
+	}
+	contents, err := json.MarshalIndent(sbom, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(c.sbomPath, append(contents, '\n'), 0644); err != nil {
+		return err
+	}
+	logutil.Infof("Wrote the SBOM of %s to %s", c.image, c.sbomPath)
+	return nil
+}
+
+// collectSBOM collects the SBOM of the image of the container containerID
+func (c *BuildContext) collectSBOM(containerID string) (*SBOM, error) {
+	sbom := &SBOM{
+		Image:       c.image,
+		Arch:        c.arch,
+		BaseImage:   c.baseImage,
+		BaseImageID: c.baseImageID(),
+		BuildType:   c.mode,
+	}
+	if c.sourceDateEpoch != nil {
+		epoch := c.sourceDateEpoch.Unix()
+		sbom.SourceDateEpoch = &epoch
+	}
+	var err error
+	if sbom.ImageID, err = docker.ImageID(c.image); err != nil {
+		return nil, err
+	}
+	if lines, err := docker.Inspect(c.baseImage, "{{range .RepoDigests}}{{.}} {{end}}"); err == nil && len(lines) == 1 {
+		sbom.BaseImageDigests = strings.Fields(strings.Trim(lines[0], "'"))
+	}
+
+	inContainer := func(command ...string) ([]string, error) {
+		return exec.CombinedOutputLines(docker.Command(
+			append([]string{"exec", containerID}, command...)...,
+		))
+	}
+	version, err := inContainer("cat", "/kind/version")
+	if err != nil {
+		return nil, err
+	}
+	if len(version) != 1 {
+		return nil, fmt.Errorf("invalid kubernetes version file")
+	}
+	sbom.KubernetesVersion = version[0]
+	packages, err := inContainer("dpkg-query", "-W", "-f", `${Package}\t${Version}\t${Architecture}\n`)
+	if err != nil {
+		return nil, err
+	}
+	sbom.Packages = parsePackages(packages)
+	sums, err := inContainer("/bin/sh", "-c", "find /kind -type f -print0 | sort -z | xargs -0 -r sha256sum")
+	if err != nil {
+		return nil, err
+	}
+	sbom.Files = parseSHA256Sums(sums)
+	sbom.Images = []SBOMImage{}
+	for _, file := range sbom.Files {
+		if path.Dir(file.Path) != DockerImageArchives || !strings.HasSuffix(file.Path, ".tar") {
+			continue
+		}
+		// the archive is still listed with its digest in Files if it is not
+		// a docker image archive
+		manifest, err := inContainer("tar", "-xOf", file.Path, "manifest.json")
+		if err != nil {
+			logutil.Warnf("Failed to read the images of %s for the SBOM: %v", file.Path, err)
+			continue
+		}
+		images, err := docker.ParseArchiveManifest([]byte(strings.Join(manifest, "\n")))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the manifest of %s", file.Path)
+		}
+		for _, image := range images {
+			sbom.Images = append(sbom.Images, SBOMImage{
+				File:     file.Path,
+				ID:       image.ID,
+				RepoTags: image.RepoTags,
+			})
+		}
+	}
+	return sbom, nil
+}
+
+// parsePackages parses the tab separated name, version and architecture of
+// the packages listed by dpkg-query
+func parsePackages(lines []string) []SBOMPackage {
+	packages := []SBOMPackage{}
+	for _, line := range lines {
+		parts := strings.Split(line, "\t")
+		if len(parts) != 3 {
+			continue
+		}
+		packages = append(packages, SBOMPackage{Name: parts[0], Version: parts[1], Arch: parts[2]})
+	}
+	return packages
+}
+
+// parseSHA256Sums parses the digests and paths of sha256sum output, lines of
+// the form "<hex>  <path>"
+func parseSHA256Sums(lines []string) []SBOMFile {
+	files := []SBOMFile{}
+	for _, line := range lines {
+		parts := strings.SplitN(line, "  ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		files = append(files, SBOMFile{Path: parts[1], SHA256: parts[0]})
+	}
+	return files
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/docker"
+	"sigs.k8s.io/kind/pkg/exec"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// baseImageRequirement is something a base image must contain for the node
+// image built from it to work
+type baseImageRequirement struct {
+	// Name identifies the requirement in errors
+	Name string
+	// Check is a shell condition true if the requirement is met
+	Check string
+}
+
+// baseImageRequirements are the requirements of the base image, see
+// images/base/Dockerfile for how the default base image meets them
+var baseImageRequirements = []baseImageRequirement{
+	{Name: "systemd (/sbin/init and systemctl)", Check: "[ -x /sbin/init ] && command -v systemctl"},
+	{Name: "a container runtime (dockerd or containerd)", Check: "command -v dockerd || command -v containerd"},
+	{Name: "mount and umount (util-linux)", Check: "command -v mount && command -v umount"},
+	{Name: "iptables", Check: "command -v iptables"},
+	{Name: "ip (iproute2)", Check: "command -v ip"},
+	{Name: "conntrack", Check: "command -v conntrack"},
+	{Name: "socat", Check: "command -v socat"},
+	{Name: "ethtool", Check: "command -v ethtool"},
+	{Name: "ebtables", Check: "command -v ebtables"},
+	{Name: "bash", Check: "command -v bash"},
+	{Name: "rsync", Check: "command -v rsync"},
+	{Name: "CNI plugins in /opt/cni/bin", Check: "[ -d /opt/cni/bin ]"},
+}
+
+// baseImageCheckPrefix prefixes the requirements not met in the output of
+// the base image check
+const baseImageCheckPrefix = "missing: "
+
+// baseImageCheckScript returns the shell script printing the requirements
+// not met, one per line
+func baseImageCheckScript(requirements []baseImageRequirement) string {
+	lines := []string{}
+	for _, r := range requirements {
+		lines = append(lines, fmt.Sprintf("{ %s; } >/dev/null 2>&1 || echo '%s%s'", r.Check, baseImageCheckPrefix, r.Name))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseBaseImageCheck returns the requirements not met from the output of
+// the base image check script
+func parseBaseImageCheck(lines []string) []string {
+	missing := []string{}
+	for _, line := range lines {
+		if strings.HasPrefix(line, baseImageCheckPrefix) {
+			missing = append(missing, strings.TrimPrefix(line, baseImageCheckPrefix))
+		}
+	}
+	return missing
+}
+
+// BaseImageError is returned when a base image does not meet the
+// requirements of the node image
+type BaseImageError struct {
+	Image string
+	// Missing are the requirements not met
+	Missing []string
+}
+
+func (e *BaseImageError) Error() string {
+	return fmt.Sprintf(
+		"base image %s can not be used to build a node image, it is missing:\n  - %s\nsee images/base/Dockerfile for how the default base image provides them",
+		e.Image, strings.Join(e.Missing, "\n  - "),
+	)
+}
+
+// ValidateBaseImage returns a BaseImageError if the base image for arch
+// does not meet the requirements of the node image, it must be present locally
+func ValidateBaseImage(image, arch string) error {
+	logutil.Infof("Validating base image %s", image)
+	args := append([]string{"run", "--rm", "--entrypoint=/bin/sh"}, docker.PlatformArgs(arch)...)
+	args = append(args, image, "-c", baseImageCheckScript(baseImageRequirements))
+	lines, err := exec.CombinedOutputLines(docker.Command(args...))
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate base image %s, it must contain /bin/sh: %s", image, strings.Join(lines, "\n"))
+	}
+	if missing := parseBaseImageCheck(lines); len(missing) > 0 {
+		return &BaseImageError{Image: image, Missing: missing}
+	}
+	// the node containers are stopped gracefully by systemd with this signal
+	signal, err := docker.Inspect(image, "{{.Config.StopSignal}}")
+	if err == nil && (len(signal) != 1 || strings.Trim(signal[0], "'") != "SIGRTMIN+3") {
+		logutil.Warnf("Base image %s should set STOPSIGNAL SIGRTMIN+3 for systemd to stop the nodes gracefully", image)
+	}
+	return nil
+}
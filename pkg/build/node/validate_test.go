@@ -0,0 +1,50 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBaseImageCheckScript(t *testing.T) {
+	requirements := []baseImageRequirement{
+		{Name: "present", Check: "true"},
+		{Name: "absent", Check: "command -v kind-no-such-command"},
+		{Name: "any of", Check: "false || true"},
+	}
+	output, err := exec.Command("/bin/sh", "-c", baseImageCheckScript(requirements)).CombinedOutput()
+	if err != nil {
+		t.Fatalf("unexpected error: %v: %s", err, output)
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	missing := parseBaseImageCheck(lines)
+	if !reflect.DeepEqual(missing, []string{"absent"}) {
+		t.Errorf("expected only absent to be missing, got %v", missing)
+	}
+}
+
+func TestBaseImageError(t *testing.T) {
+	err := &BaseImageError{Image: "example.com/base:fips", Missing: []string{"socat", "ebtables"}}
+	for _, expected := range []string{"example.com/base:fips", "  - socat\n  - ebtables"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q in %q", expected, err.Error())
+		}
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
 )
 
 // action define a set of tasks to be executed on a `kind` cluster.
@@ -59,6 +61,9 @@ type plannedTask struct {
 	// PlannedTask should respects the given order of actions and tasks
 	actionIndex int
 	taskIndex   int
+
+	// name of the action the task belongs to
+	actionName string
 }
 
 // executionPlan contain an ordered list of Planned Tasks
@@ -124,6 +129,7 @@ func newExecutionPlan(derived *derivedConfigData, actionNames []string) (executi
 					Task:        t,
 					actionIndex: i,
 					taskIndex:   j,
+					actionName:  name,
 				}
 				plan = append(plan, taskContext)
 			}
@@ -171,6 +177,12 @@ func (p *plannedTask) ExecutionOrder() string {
 	)
 }
 
+// Key returns a string identifying the task within the action it belongs to,
+// this is used for recording the tasks completed on each node
+func (p *plannedTask) Key() string {
+	return fmt.Sprintf("%s/%d", p.actionName, p.taskIndex)
+}
+
 // Swap two elements of the ExecutionPlan.
 // It is required for making ExecutionPlan sortable.
 func (t executionPlan) Swap(i, j int) {
@@ -211,13 +223,34 @@ func selectWorkerNodes(cfg *derivedConfigData) replicaList {
 	return cfg.Workers()
 }
 
-// selectExternalEtcdNode is a NodeSelector that returns the node with
-//external-etcd role, if defined
-func selectExternalEtcdNode(cfg *derivedConfigData) replicaList {
-	if cfg.ExternalEtcd() != nil {
-		return replicaList{cfg.ExternalEtcd()}
+// selectLinuxWorkerNodes is a NodeSelector that returns all the nodes with
+// Worker role running Linux, if any
+func selectLinuxWorkerNodes(cfg *derivedConfigData) replicaList {
+	return cfg.LinuxWorkers()
+}
+
+// selectWindowsWorkerNodes is a NodeSelector that returns all the nodes with
+// Worker role running Windows, if any
+func selectWindowsWorkerNodes(cfg *derivedConfigData) replicaList {
+	return cfg.WindowsWorkers()
+}
+
+// selectKubernetesNodes is a NodeSelector that returns all the nodes
+// with control-plane or worker role running Linux, i.e. the nodes running
+// kubeadm from the Linux node image, Windows workers are joined separately
+func selectKubernetesNodes(cfg *derivedConfigData) replicaList {
+	nodes := replicaList{}
+	nodes = append(nodes, cfg.ControlPlanes()...)
+	return append(nodes, cfg.LinuxWorkers()...)
+}
+
+// selectExternalEtcdNodes is a NodeSelector that returns all the nodes with
+// external-etcd role, if the etcd topology is external
+func selectExternalEtcdNodes(cfg *derivedConfigData) replicaList {
+	if cfg.EtcdTopology() != config.ExternalEtcdTopology {
+		return nil
 	}
-	return nil
+	return cfg.ExternalEtcd()
 }
 
 // selectExternalLoadBalancerNode is a NodeSelector that returns the node
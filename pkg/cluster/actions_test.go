@@ -221,6 +221,26 @@ func TestNewExecutionPlan(t *testing.T) {
 				"action2 - task 2/workers on worker2",
 			},
 		},
+		{
+			TestName: "Nodes with different images are planned by provisioning order",
+			Actions:  []string{"action0", "action2"},
+			Nodes: []*config.Node{
+				{Role: config.WorkerRole, Image: "kindest/node:v1.11.5"},
+				{Role: config.ControlPlaneRole, Image: "kindest/node:v1.12.3"},
+				{Role: config.WorkerRole, Image: "kindest/node:v1.12.3"},
+			},
+			ExpextedPlan: []string{
+				"action0 - task 0/all on control-plane",
+				"action2 - task 0/all on control-plane",
+				"action2 - task 1/control-planes on control-plane",
+				"action0 - task 0/all on worker1",
+				"action2 - task 0/all on worker1",
+				"action2 - task 2/workers on worker1",
+				"action0 - task 0/all on worker2",
+				"action2 - task 0/all on worker2",
+				"action2 - task 2/workers on worker2",
+			},
+		},
 	}
 
 	for _, c := range cases {
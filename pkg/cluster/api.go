@@ -0,0 +1,231 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/config/encoding"
+	"sigs.k8s.io/kind/pkg/cluster/events"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// Provider is the entry point for programs embedding kind, e.g. test
+// frameworks, it creates, deletes and lists clusters like the kind CLI.
+// The zero value is not usable, use NewProvider
+type Provider struct {
+	logger       logutil.Logger
+	eventHandler events.Handler
+}
+
+// ProviderOption configures a Provider, see NewProvider
+type ProviderOption func(*Provider)
+
+// ProviderWithLogger sets the logger of the cluster operations, see
+// Context.SetLogger
+func ProviderWithLogger(logger logutil.Logger) ProviderOption {
+	return func(p *Provider) {
+		p.logger = logger
+	}
+}
+
+// ProviderWithEventHandler sets the handler called with the events of the
+// cluster lifecycle, see Context.SetEventHandler
+func ProviderWithEventHandler(handler events.Handler) ProviderOption {
+	return func(p *Provider) {
+		p.eventHandler = handler
+	}
+}
+
+// NewProvider returns a new Provider configured with options
+func NewProvider(options ...ProviderOption) *Provider {
+	p := &Provider{}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// Context returns the Context of the cluster named name, configured like the
+// provider, for the operations the Provider does not cover
+func (p *Provider) Context(name string) *Context {
+	c := NewContext(name)
+	c.SetLogger(p.logger)
+	c.SetEventHandler(p.eventHandler)
+	return c
+}
+
+// createOptions are the options of Provider.Create
+type createOptions struct {
+	config     *config.Config
+	configPath string
+	nodeImage  string
+	retain     bool
+	wait       time.Duration
+	ttl        time.Duration
+	offline    bool
+	bundlePath string
+	nodePool   string
+	replace    bool
+}
+
+// CreateOption configures Provider.Create
+type CreateOption func(*createOptions)
+
+// CreateWithConfig creates the cluster from cfg, which is not modified.
+// The default config is used unless this or CreateWithConfigFile is set
+func CreateWithConfig(cfg *config.Config) CreateOption {
+	return func(o *createOptions) {
+		o.config = cfg
+		o.configPath = ""
+	}
+}
+
+// CreateWithConfigFile creates the cluster from the config file at path
+func CreateWithConfigFile(path string) CreateOption {
+	return func(o *createOptions) {
+		o.config = nil
+		o.configPath = path
+	}
+}
+
+// CreateWithNodeImage overrides the image of all the nodes
+func CreateWithNodeImage(image string) CreateOption {
+	return func(o *createOptions) {
+		o.nodeImage = image
+	}
+}
+
+// CreateWithRetain keeps the nodes if the creation fails, for debugging
+func CreateWithRetain(retain bool) CreateOption {
+	return func(o *createOptions) {
+		o.retain = retain
+	}
+}
+
+// CreateWithWaitForReady waits up to wait for the API server, the nodes and
+// CoreDNS to be ready, failing with a ClusterNotReadyError otherwise
+func CreateWithWaitForReady(wait time.Duration) CreateOption {
+	return func(o *createOptions) {
+		o.wait = wait
+	}
+}
+
+// CreateWithTTL allows GarbageCollect to delete the cluster after ttl
+func CreateWithTTL(ttl time.Duration) CreateOption {
+	return func(o *createOptions) {
+		o.ttl = ttl
+	}
+}
+
+// CreateWithOffline creates the cluster without network access, loading the
+// images of the bundle at bundlePath first if not empty, see CreateOffline
+func CreateWithOffline(bundlePath string) CreateOption {
+	return func(o *createOptions) {
+		o.offline = true
+		o.bundlePath = bundlePath
+	}
+}
+
+// CreateWithNodePool claims the nodes from the node pool named pool when
+// possible instead of creating them, see CreatePool
+func CreateWithNodePool(pool string) CreateOption {
+	return func(o *createOptions) {
+		o.nodePool = pool
+	}
+}
+
+// CreateWithForceReplace deletes the stale cluster with the same name first,
+// along with the docker objects taking its names, see Context.DeleteStale
+func CreateWithForceReplace(replace bool) CreateOption {
+	return func(o *createOptions) {
+		o.replace = replace
+	}
+}
+
+// Create creates the cluster named name, if name is empty the name in the
+// config is used, or DefaultName
+func (p *Provider) Create(name string, options ...CreateOption) error {
+	o := &createOptions{}
+	for _, option := range options {
+		option(o)
+	}
+
+	var cfg *config.Config
+	if o.config != nil {
+		cfg = o.config.DeepCopy()
+	} else {
+		var err error
+		if cfg, err = encoding.Load(o.configPath); err != nil {
+			return errors.Wrap(err, "error loading config")
+		}
+	}
+	if o.nodeImage != "" {
+		for i := range cfg.Nodes {
+			cfg.Nodes[i].Image = o.nodeImage
+		}
+	}
+	if name == "" {
+		name = cfg.Name
+	}
+
+	c := p.Context(name)
+	c.SetNodePool(o.nodePool)
+	if o.replace {
+		if err := c.DeleteStale(cfg); err != nil {
+			return errors.Wrap(err, "failed to delete the stale cluster")
+		}
+	}
+	if o.offline {
+		return c.CreateOffline(cfg, o.bundlePath, o.retain, o.wait, o.ttl)
+	}
+	return c.Create(cfg, o.retain, o.wait, o.ttl)
+}
+
+// Delete deletes the cluster named name
+func (p *Provider) Delete(name string) error {
+	return p.Context(name).Delete()
+}
+
+// ListClusters returns the names of the clusters for which node containers
+// exist, sorted
+func (p *Provider) ListClusters() ([]string, error) {
+	clusters, err := List()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(clusters))
+	for i := range clusters {
+		names = append(names, clusters[i].Name())
+	}
+	return names, nil
+}
+
+// ListNodes returns the description of the nodes of the cluster named name,
+// in provisioning order
+func (p *Provider) ListNodes(name string) ([]NodeInfo, error) {
+	return p.Context(name).NodeInfo()
+}
+
+// KubeConfig returns the kubeconfig of the cluster named name, if internal
+// is set the API server is reached at its address in the docker network
+func (p *Provider) KubeConfig(name string, internal bool) ([]byte, error) {
+	return p.Context(name).KubeConfig(internal)
+}
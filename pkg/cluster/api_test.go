@@ -0,0 +1,75 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestCreateOptions(t *testing.T) {
+	cfg := &config.Config{}
+	o := &createOptions{}
+	for _, option := range []CreateOption{
+		CreateWithConfigFile("kind.yaml"),
+		CreateWithConfig(cfg),
+		CreateWithNodeImage("kindest/node:latest"),
+		CreateWithRetain(true),
+		CreateWithWaitForReady(time.Minute),
+		CreateWithTTL(time.Hour),
+		CreateWithOffline("bundle.tar.gz"),
+	} {
+		option(o)
+	}
+	expected := createOptions{
+		config:     cfg,
+		nodeImage:  "kindest/node:latest",
+		retain:     true,
+		wait:       time.Minute,
+		ttl:        time.Hour,
+		offline:    true,
+		bundlePath: "bundle.tar.gz",
+	}
+	if *o != expected {
+		t.Errorf("expected options %+v but got %+v", expected, *o)
+	}
+
+	// the last config option wins
+	CreateWithConfigFile("other.yaml")(o)
+	if o.config != nil || o.configPath != "other.yaml" {
+		t.Errorf("expected the config file to replace the config, got %+v", *o)
+	}
+}
+
+func ExampleProvider() {
+	provider := NewProvider()
+	if err := provider.Create("test", CreateWithWaitForReady(time.Minute)); err != nil {
+		fmt.Printf("failed to create the cluster: %v\n", err)
+		return
+	}
+	defer provider.Delete("test")
+
+	kubeConfig, err := provider.KubeConfig("test", false)
+	if err != nil {
+		fmt.Printf("failed to get the kubeconfig: %v\n", err)
+		return
+	}
+	fmt.Printf("%s\n", kubeConfig)
+}
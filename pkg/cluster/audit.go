@@ -0,0 +1,63 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+)
+
+// auditPolicy returns the API server audit policy of audit, either inline or
+// read from the policy file, or nil if audit logging is disabled
+func auditPolicy(audit *config.Audit) ([]byte, error) {
+	if !audit.Enabled() {
+		return nil, nil
+	}
+	if audit.Policy != "" {
+		return []byte(audit.Policy), nil
+	}
+	policy, err := ioutil.ReadFile(audit.PolicyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the audit policy file")
+	}
+	if err := config.ValidateAuditPolicy(policy); err != nil {
+		return nil, errors.Wrapf(err, "invalid audit policy file %s", audit.PolicyFile)
+	}
+	return policy, nil
+}
+
+// writeAuditPolicy writes the API server audit policy of the cluster, if
+// any, to the control-plane node, where it is mounted into the API server
+// along with the audit log directory, see kubeadm.ConfigData.Audit
+func (ec *execContext) writeAuditPolicy(configNode *nodeReplica) error {
+	policy, err := auditPolicy(&ec.config.Audit)
+	if err != nil || policy == nil {
+		return err
+	}
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return errors.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+	if err := node.WriteFile(kubeadm.AuditPolicyFile, policy); err != nil {
+		return errors.Wrap(err, "failed to write the audit policy to the node")
+	}
+	return nil
+}
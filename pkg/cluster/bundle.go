@@ -0,0 +1,334 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"archive/tar"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/docker"
+	"sigs.k8s.io/kind/pkg/exec"
+	"sigs.k8s.io/kind/pkg/fs"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// the files of image bundles, see ExportBundle
+const (
+	bundleManifestFile    = "bundle.json"
+	bundleImagesFile      = "images.tar"
+	bundleCNIManifestFile = "default-cni.yaml"
+)
+
+// bundleManifest describes the contents of an image bundle
+type bundleManifest struct {
+	// HostImages are the images of the node containers and the other
+	// containers kind runs, e.g. the local registry
+	HostImages []string `json:"hostImages"`
+	// NodeImages are the images to load into the nodes, other than the ones
+	// part of the node image
+	NodeImages []string `json:"nodeImages,omitempty"`
+	// CNIManifest is true if the bundle contains the default CNI manifest
+	CNIManifest bool `json:"cniManifest,omitempty"`
+}
+
+// bundle is an image bundle loaded for creating a cluster offline
+type bundle struct {
+	manifest    bundleManifest
+	cniManifest []byte
+}
+
+// requiredImages returns the images required for creating a cluster for cfg,
+// the images of the containers run on the host, and the images run in the
+// nodes other than the ones part of the node image, which does not include
+// the default CNI images
+func requiredImages(cfg *config.Config, derived *derivedConfigData) (hostImages, nodeImages []string) {
+	hosts := map[string]bool{}
+	for _, replica := range derived.AllReplicas() {
+		hosts[replica.Image] = true
+	}
+	if cfg.Registry.Enabled {
+		hosts[registryImage] = true
+	}
+	nodes := map[string]bool{}
+	for _, image := range cfg.PreloadImages {
+		nodes[image] = true
+	}
+	if cfg.Ingress.Controller == config.NginxIngressController {
+		nodes[nginxIngressImage] = true
+	}
+	if !cfg.Storage.Disabled {
+		nodes[localPathProvisionerImage] = true
+		nodes[localPathHelperImage] = true
+	}
+	if len(derived.FakeNodes()) > 0 {
+		nodes[kwokImage] = true
+	}
+	if cfg.VolumeSnapshots {
+		nodes[snapshotControllerImage] = true
+	}
+	if cfg.MetricsServer {
+		nodes[metricsServerImage] = true
+	}
+	if cfg.Dashboard {
+		nodes[dashboardImage] = true
+		nodes[dashboardMetricsScraperImage] = true
+	}
+	return sortedSet(hosts), sortedSet(nodes)
+}
+
+// sortedSet returns the members of the set in order
+func sortedSet(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ExportBundle writes an image bundle to dest, a tarball containing all the
+// images required for creating a cluster for cfg without network access, and
+// the default CNI manifest, which is rendered for the networking settings of
+// cfg. The images are pulled if not present locally.
+// See CreateOffline
+func ExportBundle(cfg *config.Config, dest string) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	derived, err := deriveInfo(cfg)
+	if err != nil {
+		return err
+	}
+	manifest := bundleManifest{}
+	manifest.HostImages, manifest.NodeImages = requiredImages(cfg, derived)
+
+	dir, err := fs.TempDir("", "kind-bundle")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	// the default CNI images are only known from its manifest
+	if installsDefaultCNI(cfg) {
+		cniManifest, err := downloadCNIManifest(cfg, derived.BootStrapControlPlane().Image)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, bundleCNIManifestFile), cniManifest, 0644); err != nil {
+			return err
+		}
+		manifest.CNIManifest = true
+		manifest.NodeImages = append(manifest.NodeImages, manifestImages(cniManifest)...)
+	}
+
+	images := append(append([]string{}, manifest.HostImages...), manifest.NodeImages...)
+	for _, image := range images {
+		if _, err := docker.PullIfNotPresent(image, 4); err != nil {
+			return &ImagePullError{Image: image, Err: err}
+		}
+	}
+	if err := docker.SaveImages(filepath.Join(dir, bundleImagesFile), images...); err != nil {
+		return errors.Wrap(err, "failed to save images")
+	}
+
+	contents, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, bundleManifestFile), contents, 0644); err != nil {
+		return err
+	}
+	files := []string{bundleManifestFile, bundleImagesFile}
+	if manifest.CNIManifest {
+		files = append(files, bundleCNIManifestFile)
+	}
+	return writeTar(dest, dir, files)
+}
+
+// downloadCNIManifest downloads the default CNI manifest for cfg and the
+// Kubernetes version of the given node image
+func downloadCNIManifest(cfg *config.Config, nodeImage string) ([]byte, error) {
+	if _, err := docker.PullIfNotPresent(nodeImage, 4); err != nil {
+		return nil, &ImagePullError{Image: nodeImage, Err: err}
+	}
+	lines, err := exec.CombinedOutputLines(docker.Command(
+		"run", "--rm", "--entrypoint=cat", nodeImage, "/kind/version",
+	))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the Kubernetes version of image %s", nodeImage)
+	}
+	if len(lines) != 1 {
+		return nil, fmt.Errorf("file should only be one line, got %d lines", len(lines))
+	}
+
+	// the manifest is rendered for the kubectl version output like when
+	// installing the CNI on the node
+	version := fmt.Sprintf("Server Version: version.Info{GitVersion:%q}", lines[0])
+	u := "https://cloud.weave.works/k8s/net?k8s-version=" + url.QueryEscape(base64.StdEncoding.EncodeToString([]byte(version))) + weaveParams(cfg)
+	resp, err := http.Get(u)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download the default CNI manifest")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download the default CNI manifest: %s", resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// manifestImageRE matches the container images of Kubernetes manifests
+var manifestImageRE = regexp.MustCompile(`(?m)^\s*-?\s*image:\s*['"]?([^'"\s]+)`)
+
+// manifestImages returns the container images of a Kubernetes manifest
+func manifestImages(manifest []byte) []string {
+	images := map[string]bool{}
+	for _, match := range manifestImageRE.FindAllSubmatch(manifest, -1) {
+		images[string(match[1])] = true
+	}
+	return sortedSet(images)
+}
+
+// writeTar writes the given files of dir to a tarball at dest
+func writeTar(dest, dir string, files []string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	tw := tar.NewWriter(out)
+	for _, name := range files {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0644,
+			Size: info.Size(),
+		}); err != nil {
+			f.Close()
+			return err
+		}
+		_, err = io.Copy(tw, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// loadBundle loads the images of the image bundle at path into the local
+// docker daemon, and returns the bundle
+func loadBundle(path string) (*bundle, error) {
+	dir, err := fs.TempDir("", "kind-bundle")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	if err := docker.ExtractArchive(path, dir); err != nil {
+		return nil, errors.Wrapf(err, "failed to extract bundle %s", path)
+	}
+
+	b := &bundle{}
+	contents, err := ioutil.ReadFile(filepath.Join(dir, bundleManifestFile))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s is not a bundle", path)
+	}
+	if err := json.Unmarshal(contents, &b.manifest); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse bundle %s", path)
+	}
+	if b.manifest.CNIManifest {
+		if b.cniManifest, err = ioutil.ReadFile(filepath.Join(dir, bundleCNIManifestFile)); err != nil {
+			return nil, err
+		}
+	}
+	logutil.Infof("Loading images from bundle %s ...", path)
+	if err := docker.Load(filepath.Join(dir, bundleImagesFile)); err != nil {
+		return nil, errors.Wrap(err, "failed to load bundle images")
+	}
+	return b, nil
+}
+
+// CreateOffline creates a cluster like Create, without network access. The
+// images of the image bundle at bundlePath, if not empty, are loaded first,
+// see ExportBundle, and all the images required for cfg must be present
+// locally. The default CNI network plugin is installed only if the bundle
+// contains its manifest
+func (c *Context) CreateOffline(cfg *config.Config, bundlePath string, retain bool, wait, ttl time.Duration) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	derived, err := validateForCreate(cfg)
+	if err != nil {
+		return err
+	}
+	if err := selectProvider(cfg, derived); err != nil {
+		return err
+	}
+	b := &bundle{}
+	if bundlePath != "" {
+		if b, err = loadBundle(bundlePath); err != nil {
+			return err
+		}
+	}
+
+	// the images for the nodes are loaded from the host like preloadImages
+	hostImages, nodeImages := requiredImages(cfg, derived)
+	nodeImages = append(nodeImages, b.manifest.NodeImages...)
+	missing := []string{}
+	for _, image := range append(hostImages, nodeImages...) {
+		if !docker.ImageExists(image) {
+			missing = append(missing, image)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("images required for creating the cluster offline are not present locally: %v", missing)
+	}
+	if installsDefaultCNI(cfg) && b.cniManifest == nil {
+		return fmt.Errorf("the default CNI network plugin can not be installed offline without a bundle, disable it with networking.disableDefaultCNI")
+	}
+	cfg.PreloadImages = uniqueNonEmpty(append(cfg.PreloadImages, nodeImages...))
+
+	var expiry time.Time
+	if ttl > 0 {
+		expiry = time.Now().Add(ttl)
+	}
+	return c.create(cfg, retain, false, wait, expiry, b.cniManifest)
+}
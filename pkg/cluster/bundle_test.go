@@ -0,0 +1,47 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestManifestImages(t *testing.T) {
+	manifest := []byte(`apiVersion: apps/v1
+kind: DaemonSet
+spec:
+  template:
+    spec:
+      containers:
+        - name: weave
+          image: 'docker.io/weaveworks/weave-kube:2.5.2'
+        - image: "docker.io/weaveworks/weave-npc:2.5.2"
+          name: weave-npc
+      initContainers:
+      - name: init
+        image: docker.io/weaveworks/weave-kube:2.5.2
+        imagePullPolicy: IfNotPresent
+`)
+	expected := []string{
+		"docker.io/weaveworks/weave-kube:2.5.2",
+		"docker.io/weaveworks/weave-npc:2.5.2",
+	}
+	if images := manifestImages(manifest); !reflect.DeepEqual(images, expected) {
+		t.Errorf("expected images %v, got %v", expected, images)
+	}
+}
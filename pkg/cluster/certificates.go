@@ -0,0 +1,88 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+)
+
+// clusterCA returns the PEM encoded custom cluster CA certificate and key of
+// certificates, or nil if kubeadm generates the CA
+func clusterCA(certificates *config.Certificates) (cert, key []byte, err error) {
+	if certificates.CACertFile == "" {
+		return nil, nil, nil
+	}
+	cert, err = ioutil.ReadFile(certificates.CACertFile)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read the CA certificate file")
+	}
+	key, err = ioutil.ReadFile(certificates.CAKeyFile)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read the CA key file")
+	}
+	if err := validateCA(cert, key); err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid CA %s", certificates.CACertFile)
+	}
+	return cert, key, nil
+}
+
+// validateCA returns an error unless the PEM encoded cert and key are a
+// matching CA certificate and key pair
+func validateCA(cert, key []byte) error {
+	pair, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return err
+	}
+	ca, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return err
+	}
+	if !ca.IsCA {
+		return errors.New("the certificate is not a CA certificate")
+	}
+	return nil
+}
+
+// writeClusterCA writes the custom cluster CA of the cluster, if any, to the
+// control-plane node, where kubeadm signs the cluster certificates with it
+func (ec *execContext) writeClusterCA(configNode *nodeReplica) error {
+	cert, key, err := clusterCA(&ec.config.Certificates)
+	if err != nil || cert == nil {
+		return err
+	}
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return errors.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+	if err := node.WriteFile(kubeadm.CACertFile, cert); err != nil {
+		return errors.Wrap(err, "failed to write the CA certificate to the node")
+	}
+	if err := node.WriteFile(kubeadm.CAKeyFile, key); err != nil {
+		return errors.Wrap(err, "failed to write the CA key to the node")
+	}
+	if err := node.Command("chmod", "0600", kubeadm.CAKeyFile).Run(); err != nil {
+		return errors.Wrap(err, "failed to restrict the permissions of the CA key")
+	}
+	return nil
+}
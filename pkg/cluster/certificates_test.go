@@ -0,0 +1,103 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// newTestCertificate returns a PEM encoded self-signed certificate and key
+func newTestCertificate(t *testing.T, isCA bool) (cert, key []byte) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kind-test"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestValidateCA(t *testing.T) {
+	caCert, caKey := newTestCertificate(t, true)
+	leafCert, leafKey := newTestCertificate(t, false)
+	_, otherKey := newTestCertificate(t, true)
+
+	cases := []struct {
+		Name        string
+		Cert, Key   []byte
+		ExpectError bool
+	}{
+		{
+			Name: "CA",
+			Cert: caCert,
+			Key:  caKey,
+		},
+		{
+			Name:        "not a CA",
+			Cert:        leafCert,
+			Key:         leafKey,
+			ExpectError: true,
+		},
+		{
+			Name:        "mismatched key",
+			Cert:        caCert,
+			Key:         otherKey,
+			ExpectError: true,
+		},
+		{
+			Name:        "not PEM",
+			Cert:        []byte("not a certificate"),
+			Key:         caKey,
+			ExpectError: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := validateCA(tc.Cert, tc.Key)
+			if err != nil && !tc.ExpectError {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if err == nil && tc.ExpectError {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
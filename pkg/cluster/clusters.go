@@ -17,8 +17,11 @@ limitations under the License.
 package cluster
 
 import (
+	"sort"
+
 	"github.com/pkg/errors"
 
+	"sigs.k8s.io/kind/pkg/cluster/config"
 	"sigs.k8s.io/kind/pkg/cluster/nodes"
 )
 
@@ -28,9 +31,141 @@ func List() ([]Context, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "could not list clusters, failed to list nodes")
 	}
-	clusters := []Context{}
+	names := []string{}
 	for name := range n {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	clusters := []Context{}
+	for _, name := range names {
 		clusters = append(clusters, *NewContext(name))
 	}
 	return clusters, nil
 }
+
+// ClusterInfo describes a cluster and its nodes, see ListInfo
+type ClusterInfo struct {
+	// Name is the cluster context name
+	Name string `json:"name"`
+	// Owner is the owner identity of the cluster, if any, see Context.Owner
+	Owner string `json:"owner,omitempty"`
+	// Nodes describes the nodes, in provisioning order
+	Nodes []NodeInfo `json:"nodes"`
+}
+
+// NodeInfo describes a node of a cluster, see Context.NodeInfo
+type NodeInfo struct {
+	// Name is the node container name
+	Name string `json:"name"`
+	// Cluster is the cluster context name
+	Cluster string `json:"cluster"`
+	// Replica is the node replica name, e.g. control-plane1
+	Replica string `json:"replica"`
+	// Role is the node role
+	Role config.NodeRole `json:"role"`
+	// Image is the image the node container was created from
+	Image string `json:"image"`
+	// IP is the IPv4 address of the node, if running
+	IP string `json:"ip,omitempty"`
+	// IPv6 is the IPv6 address of the node, if running on an IPv6 network
+	IPv6 string `json:"ipv6,omitempty"`
+	// Status is the node container state, e.g. "running" or "exited"
+	Status string `json:"status"`
+	// CPUs and NUMANodes are the host CPUs and NUMA nodes the node runs on,
+	// e.g. "0-3", if running, for Linux Kubernetes nodes
+	CPUs      string `json:"cpus,omitempty"`
+	NUMANodes string `json:"numaNodes,omitempty"`
+	// ReplicaIndex, ConfigHash, KindVersion and ImageDigest are the
+	// provenance of the node, if recorded, see Provenance
+	ReplicaIndex int    `json:"replicaIndex,omitempty"`
+	ConfigHash   string `json:"configHash,omitempty"`
+	KindVersion  string `json:"kindVersion,omitempty"`
+	ImageDigest  string `json:"imageDigest,omitempty"`
+}
+
+// ListInfo returns the description of the clusters for which node
+// containers exist, sorted by name
+func ListInfo() ([]ClusterInfo, error) {
+	clusters, err := List()
+	if err != nil {
+		return nil, err
+	}
+	infos := []ClusterInfo{}
+	for i := range clusters {
+		nodes, err := clusters[i].NodeInfo()
+		if err != nil {
+			return nil, err
+		}
+		owner, err := clusters[i].Owner()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, ClusterInfo{
+			Name:  clusters[i].Name(),
+			Owner: owner,
+			Nodes: nodes,
+		})
+	}
+	return infos, nil
+}
+
+// NodeInfo returns the description of the nodes of the cluster, in
+// provisioning order
+func (c *Context) NodeInfo() ([]NodeInfo, error) {
+	n, err := c.ListNodes()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+	if err := sortNodesByProvisioningOrder(n); err != nil {
+		return nil, err
+	}
+	infos := []NodeInfo{}
+	for i := range n {
+		node := &n[i]
+		role, err := node.Role()
+		if err != nil {
+			return nil, err
+		}
+		image, err := node.Image()
+		if err != nil {
+			return nil, err
+		}
+		state, err := node.State()
+		if err != nil {
+			return nil, err
+		}
+		provenance, err := NodeProvenance(node)
+		if err != nil {
+			return nil, err
+		}
+		info := NodeInfo{
+			Name:         node.String(),
+			Cluster:      c.name,
+			Replica:      c.replicaName(node),
+			Role:         config.NodeRole(role),
+			Image:        image,
+			Status:       state,
+			ReplicaIndex: provenance.ReplicaIndex,
+			ConfigHash:   provenance.ConfigHash,
+			KindVersion:  provenance.KindVersion,
+			ImageDigest:  provenance.ImageDigest,
+		}
+		// only running nodes have addresses
+		if state == "running" {
+			if info.IP, err = node.IP(); err != nil {
+				return nil, err
+			}
+			if info.IPv6, err = node.IPv6(); err != nil {
+				return nil, err
+			}
+			isKubernetesNode := info.Role == config.ControlPlaneRole || info.Role == config.WorkerRole
+			if isKubernetesNode && !node.IsWindows() {
+				if info.CPUs, info.NUMANodes, _, err = node.CPUs(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encoding
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/config/v1alpha3"
+)
+
+// LatestVersion is the latest `kind` config API version, to which Convert
+// upgrades the config documents
+var LatestVersion = v1alpha3.SchemeGroupVersion
+
+// Convert reads the file at path and converts each of the `kind` Config
+// documents it contains from any of the API versions defined in scheme to
+// LatestVersion, returning the resulting YAML documents.
+// Defaults are not applied, so fields left unset in the original documents
+// are left unset in the converted ones and keep following the kind defaults
+func Convert(path string) ([]byte, error) {
+	// environment variables and templates are not expanded, to preserve them
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	documents, err := splitDocuments(path, contents)
+	if err != nil {
+		return nil, err
+	}
+	var buff bytes.Buffer
+	for i, document := range documents {
+		converted, err := convert(document)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid config document %d", i+1)
+		}
+		if i > 0 {
+			buff.WriteString("---\n")
+		}
+		buff.Write(converted)
+	}
+	return buff.Bytes(), nil
+}
+
+// convert converts a single `kind` Config document to LatestVersion
+func convert(document []byte) ([]byte, error) {
+	// decode the document without defaulting it
+	obj, _, err := Codecs.UniversalDeserializer().Decode(document, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding failure")
+	}
+
+	// convert through the internal config, which all the versions convert to
+	cfg := &config.Config{}
+	if err := Scheme.Convert(obj, cfg, nil); err != nil {
+		return nil, errors.Wrap(err, "conversion failure")
+	}
+	return Encode(cfg)
+}
+
+// Encode returns the `kind` Config as a YAML document of LatestVersion,
+// leaving out the fields that are not set
+func Encode(cfg *config.Config) ([]byte, error) {
+	latest := &v1alpha3.Config{}
+	if err := Scheme.Convert(cfg, latest, nil); err != nil {
+		return nil, errors.Wrap(err, "conversion failure")
+	}
+
+	// encode to YAML, leaving out the fields that are not set
+	object, err := pruned(latest)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding failure")
+	}
+	encoded, err := yaml.Marshal(object)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding failure")
+	}
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, "kind: Config\napiVersion: %s\n", LatestVersion)
+	if len(object) > 0 {
+		buff.Write(encoded)
+	}
+	return buff.Bytes(), nil
+}
+
+// pruned returns obj as a map, without type meta and with the empty
+// objects and null values removed
+func pruned(obj interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	delete(object, "apiVersion")
+	delete(object, "kind")
+	prune(object)
+	return object, nil
+}
+
+// prune recursively removes the empty objects and null values from object
+func prune(object map[string]interface{}) {
+	for key, value := range object {
+		if value == nil {
+			delete(object, key)
+			continue
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			prune(v)
+			if len(v) == 0 {
+				delete(object, key)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					prune(m)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encoding
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestConvert(t *testing.T) {
+	cases := []struct {
+		TestName    string
+		Path        string
+		ExpectError bool
+	}{
+		{
+			TestName: "v1alpha1 minimal",
+			Path:     "./testdata/v1alpha1/valid-minimal.yaml",
+		},
+		{
+			TestName: "v1alpha1 with lifecyclehooks",
+			Path:     "./testdata/v1alpha1/valid-with-lifecyclehooks.yaml",
+		},
+		{
+			TestName: "v1alpha1 with patches",
+			Path:     "./testdata/v1alpha1/valid-with-patches.yaml",
+		},
+		{
+			TestName: "v1alpha2 full HA",
+			Path:     "./testdata/v1alpha2/valid-full-ha.yaml",
+		},
+		{
+			TestName: "v1alpha3 cluster settings",
+			Path:     "./testdata/v1alpha3/valid-cluster-settings.yaml",
+		},
+		{
+			TestName: "v1alpha3 multiple clusters",
+			Path:     "./testdata/v1alpha3/valid-multiple-clusters.yaml",
+		},
+		{
+			TestName:    "Invalid apiversion",
+			Path:        "./testdata/invalid-apiversion.yaml",
+			ExpectError: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.TestName, func(t *testing.T) {
+			converted, err := Convert(c.Path)
+			if err != nil {
+				if !c.ExpectError {
+					t.Fatalf("unexpected error while converting config: %v", err)
+				}
+				return
+			}
+			if c.ExpectError {
+				t.Fatalf("unexpected lack or error while converting config")
+			}
+			if !strings.Contains(string(converted), "apiVersion: "+LatestVersion.String()+"\n") {
+				t.Errorf("expected the config to be converted to %s, got:\n%s", LatestVersion, converted)
+			}
+
+			// the converted config should load exactly as the original one
+			f, err := ioutil.TempFile("", "kind-config")
+			if err != nil {
+				t.Fatalf("unexpected error creating a temporary file: %v", err)
+			}
+			defer os.Remove(f.Name())
+			if _, err := f.Write(converted); err != nil {
+				t.Fatalf("unexpected error writing the converted config: %v", err)
+			}
+			f.Close()
+			expected, err := LoadAll(c.Path)
+			if err != nil {
+				t.Fatalf("unexpected error while Loading config: %v", err)
+			}
+			actual, err := LoadAll(f.Name())
+			if err != nil {
+				t.Fatalf("unexpected error while Loading the converted config: %v\n%s", err, converted)
+			}
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("expected the converted config to load as %v, got %v", expected, actual)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	replicas := int32(3)
+	cfg := &config.Config{
+		Networking: config.Networking{
+			IPFamily: config.IPv6Family,
+		},
+		Nodes: []config.Node{
+			{
+				Role: config.ExternalLoadBalancerRole,
+			},
+			{
+				Role:     config.ControlPlaneRole,
+				Replicas: &replicas,
+				Image:    "kindest/node:v1.13.0",
+				ExtraMounts: []config.Mount{
+					{HostPath: "/data", ContainerPath: "/data", ReadOnly: true},
+				},
+			},
+		},
+	}
+	encoded, err := Encode(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error while encoding config: %v", err)
+	}
+	if !strings.HasPrefix(string(encoded), "kind: Config\napiVersion: "+LatestVersion.String()+"\n") {
+		t.Errorf("expected the config to be encoded as %s, got:\n%s", LatestVersion, encoded)
+	}
+
+	// the encoded config should decode to the original one, with the defaults
+	decoded, err := Decode(encoded)
+	if err != nil {
+		t.Fatalf("unexpected error while decoding config: %v\n%s", err, encoded)
+	}
+	if decoded.Networking.IPFamily != config.IPv6Family {
+		t.Errorf("expected ipFamily %s, got %s", config.IPv6Family, decoded.Networking.IPFamily)
+	}
+	if len(decoded.Nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %d:\n%s", len(decoded.Nodes), encoded)
+	}
+	node := decoded.Nodes[1]
+	if node.Role != config.ControlPlaneRole || node.Replicas == nil || *node.Replicas != replicas {
+		t.Errorf("expected %d control-plane replicas, got %+v", replicas, node)
+	}
+	if !reflect.DeepEqual(node.ExtraMounts, cfg.Nodes[1].ExtraMounts) {
+		t.Errorf("expected mounts %v, got %v", cfg.Nodes[1].ExtraMounts, node.ExtraMounts)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encoding
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// TemplateExtension is the file extension that opts config files in to
+// being rendered as Go templates before they are loaded
+const TemplateExtension = ".tmpl"
+
+// variableRegexp matches ${VAR} and ${VAR:-default} references, and $${
+// which escapes a literal ${
+var variableRegexp = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expand renders the contents of the config file at path as a Go template,
+// if path has the TemplateExtension, and then expands the environment
+// variable references in it
+func expand(path string, contents []byte) ([]byte, error) {
+	if filepath.Ext(path) == TemplateExtension {
+		rendered, err := render(path, contents)
+		if err != nil {
+			return nil, err
+		}
+		contents = rendered
+	}
+	return expandVariables(contents)
+}
+
+// expandVariables replaces each ${VAR} in contents with the value of the
+// environment variable VAR, and each ${VAR:-default} with the value of VAR or
+// default if VAR is unset or empty. It is an error to reference an unset
+// variable without a default, so mistakes in CI are not silently ignored
+func expandVariables(contents []byte) ([]byte, error) {
+	undefined := []string{}
+	expanded := variableRegexp.ReplaceAllFunc(contents, func(match []byte) []byte {
+		groups := variableRegexp.FindSubmatch(match)
+		if groups[1] == nil {
+			return []byte("${")
+		}
+		name := string(groups[1])
+		if value := os.Getenv(name); value != "" {
+			return []byte(value)
+		}
+		if groups[2] != nil {
+			return groups[3]
+		}
+		if _, set := os.LookupEnv(name); !set {
+			undefined = append(undefined, name)
+		}
+		return nil
+	})
+	if len(undefined) > 0 {
+		return nil, errors.Errorf("undefined environment variables: %s", strings.Join(undefined, ", "))
+	}
+	return expanded, nil
+}
+
+// render executes contents as a Go template with templateFuncs
+func render(path string, contents []byte) ([]byte, error) {
+	tmpl, err := template.New(filepath.Base(path)).
+		Funcs(templateFuncs).
+		Option("missingkey=error").
+		Parse(string(contents))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse config template")
+	}
+	var buff bytes.Buffer
+	if err := tmpl.Execute(&buff, nil); err != nil {
+		return nil, errors.Wrap(err, "failed to render config template")
+	}
+	return buff.Bytes(), nil
+}
+
+// templateFuncs are the functions available to config templates
+var templateFuncs = template.FuncMap{
+	// env returns the value of the environment variable, or "" if unset
+	"env": os.Getenv,
+	// default returns value, or def if value is empty
+	"default": func(def, value string) string {
+		if value == "" {
+			return def
+		}
+		return value
+	},
+	// required returns value, or fails rendering with message if it is empty
+	"required": func(message, value string) (string, error) {
+		if value == "" {
+			return "", errors.New(message)
+		}
+		return value, nil
+	},
+	// quote returns value as a double quoted string, safe to use in YAML
+	"quote": strconv.Quote,
+	// split splits value into a list on sep, for use with range
+	"split": func(sep, value string) []string {
+		if value == "" {
+			return []string{}
+		}
+		return strings.Split(value, sep)
+	},
+	// atoi converts value to an int, e.g. to use it with until
+	"atoi": strconv.Atoi,
+	// until returns the list of ints from 0 to n-1, for use with range
+	"until": func(n int) []int {
+		list := make([]int, 0, n)
+		for i := 0; i < n; i++ {
+			list = append(list, i)
+		}
+		return list
+	},
+}
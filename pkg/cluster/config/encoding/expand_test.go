@@ -0,0 +1,117 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encoding
+
+import (
+	"os"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	os.Setenv("KIND_TEST_IMAGE", "kindest/node:test")
+	os.Setenv("KIND_TEST_EMPTY", "")
+	os.Setenv("KIND_TEST_WORKERS", "2")
+	defer os.Unsetenv("KIND_TEST_IMAGE")
+	defer os.Unsetenv("KIND_TEST_EMPTY")
+	defer os.Unsetenv("KIND_TEST_WORKERS")
+
+	cases := []struct {
+		TestName    string
+		Path        string
+		Contents    string
+		Expected    string
+		ExpectError bool
+	}{
+		{
+			TestName: "no variables",
+			Path:     "config.yaml",
+			Contents: "image: kindest/node:latest",
+			Expected: "image: kindest/node:latest",
+		},
+		{
+			TestName: "variables",
+			Path:     "config.yaml",
+			Contents: "image: ${KIND_TEST_IMAGE}\nhostPath: ${KIND_TEST_EMPTY}",
+			Expected: "image: kindest/node:test\nhostPath: ",
+		},
+		{
+			TestName: "variables with defaults",
+			Path:     "config.yaml",
+			Contents: "image: ${KIND_TEST_IMAGE:-other}\nhostPort: ${KIND_TEST_UNSET:-8080}\nhostPath: ${KIND_TEST_EMPTY:-/data}",
+			Expected: "image: kindest/node:test\nhostPort: 8080\nhostPath: /data",
+		},
+		{
+			TestName: "escaped and unbraced variables",
+			Path:     "config.yaml",
+			Contents: "command: echo $${HOME} $HOME",
+			Expected: "command: echo ${HOME} $HOME",
+		},
+		{
+			TestName:    "undefined variable",
+			Path:        "config.yaml",
+			Contents:    "image: ${KIND_TEST_UNSET}",
+			ExpectError: true,
+		},
+		{
+			TestName: "templates are opt-in",
+			Path:     "config.yaml",
+			Contents: "name: {{ env \"KIND_TEST_IMAGE\" }}",
+			Expected: "name: {{ env \"KIND_TEST_IMAGE\" }}",
+		},
+		{
+			TestName: "template",
+			Path:     "config.yaml.tmpl",
+			Contents: "image: {{ env \"KIND_TEST_UNSET\" | default \"kindest/node:latest\" | quote }}\nnodes:\n{{- range until (atoi (env \"KIND_TEST_WORKERS\")) }}\n- role: worker\n{{- end }}",
+			Expected: "image: \"kindest/node:latest\"\nnodes:\n- role: worker\n- role: worker",
+		},
+		{
+			TestName: "template and variables",
+			Path:     "config.yaml.tmpl",
+			Contents: "{{ if env \"KIND_TEST_IMAGE\" }}image: ${KIND_TEST_IMAGE}{{ end }}",
+			Expected: "image: kindest/node:test",
+		},
+		{
+			TestName:    "template with required value",
+			Path:        "config.yaml.tmpl",
+			Contents:    "image: {{ env \"KIND_TEST_UNSET\" | required \"KIND_TEST_UNSET must be set\" }}",
+			ExpectError: true,
+		},
+		{
+			TestName:    "invalid template",
+			Path:        "config.yaml.tmpl",
+			Contents:    "image: {{ env }",
+			ExpectError: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.TestName, func(t *testing.T) {
+			expanded, err := expand(c.Path, []byte(c.Contents))
+			if err != nil {
+				if !c.ExpectError {
+					t.Fatalf("unexpected error while expanding config: %v", err)
+				}
+				return
+			}
+			if c.ExpectError {
+				t.Fatalf("unexpected lack or error while expanding config")
+			}
+			if string(expanded) != c.Expected {
+				t.Errorf("expected %q but got %q", c.Expected, string(expanded))
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encoding
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+// presets are the configs of the named presets, for the common cluster
+// topologies, see LoadPreset
+var presets = map[string]string{
+	"single-node": `# a single control-plane node, also running the workloads
+kind: Config
+apiVersion: kind.sigs.k8s.io/v1alpha3
+nodes:
+- role: control-plane
+`,
+	"multi-node": `# a control-plane node and two worker nodes
+kind: Config
+apiVersion: kind.sigs.k8s.io/v1alpha3
+nodes:
+- role: control-plane
+- role: worker
+  replicas: 2
+`,
+	"ha": `# three control-plane nodes behind a load balancer, and three worker nodes
+kind: Config
+apiVersion: kind.sigs.k8s.io/v1alpha3
+nodes:
+- role: external-load-balancer
+- role: control-plane
+  replicas: 3
+- role: worker
+  replicas: 3
+`,
+	"ipv6": `# a control-plane node and a worker node, with IPv6 networking
+kind: Config
+apiVersion: kind.sigs.k8s.io/v1alpha3
+networking:
+  ipFamily: ipv6
+nodes:
+- role: control-plane
+- role: worker
+`,
+	"ingress-ready": `# a control-plane node running ingress-nginx, published on the ports 80
+# and 443 of the host, and a worker node
+kind: Config
+apiVersion: kind.sigs.k8s.io/v1alpha3
+ingress:
+  controller: nginx
+nodes:
+- role: control-plane
+  ingressReady: true
+- role: worker
+`,
+}
+
+// PresetNames returns the names of the presets, sorted
+func PresetNames() []string {
+	names := []string{}
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Preset returns the YAML config of the named preset
+func Preset(name string) ([]byte, error) {
+	preset, ok := presets[name]
+	if !ok {
+		return nil, errors.Errorf("unknown preset %q, expected one of %v", name, PresetNames())
+	}
+	return []byte(preset), nil
+}
+
+// LoadPreset converts the named preset into a `kind` Config, like Load
+func LoadPreset(name string) (*config.Config, error) {
+	contents, err := Preset(name)
+	if err != nil {
+		return nil, err
+	}
+	return decode(contents)
+}
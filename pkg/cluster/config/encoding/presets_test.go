@@ -0,0 +1,38 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encoding
+
+import (
+	"testing"
+)
+
+func TestLoadPreset(t *testing.T) {
+	for _, name := range PresetNames() {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := LoadPreset(name)
+			if err != nil {
+				t.Fatalf("unexpected error loading preset: %v", err)
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("invalid preset: %v", err)
+			}
+		})
+	}
+	if _, err := LoadPreset("unknown"); err == nil {
+		t.Error("expected an error for an unknown preset")
+	}
+}
@@ -17,6 +17,9 @@ limitations under the License.
 package encoding
 
 import (
+	"bufio"
+	"bytes"
+	"io"
 	"io/ioutil"
 
 	"github.com/pkg/errors"
@@ -24,10 +27,12 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
 
 	"sigs.k8s.io/kind/pkg/cluster/config"
 	"sigs.k8s.io/kind/pkg/cluster/config/v1alpha1"
 	"sigs.k8s.io/kind/pkg/cluster/config/v1alpha2"
+	"sigs.k8s.io/kind/pkg/cluster/config/v1alpha3"
 )
 
 // Scheme is the runtime.Scheme to which all `kind` config API versions and types are registered.
@@ -45,26 +50,113 @@ func AddToScheme(scheme *runtime.Scheme) {
 	utilruntime.Must(config.AddToScheme(scheme))
 	utilruntime.Must(v1alpha1.AddToScheme(scheme))
 	utilruntime.Must(v1alpha2.AddToScheme(scheme))
-	utilruntime.Must(scheme.SetVersionPriority(v1alpha2.SchemeGroupVersion))
+	utilruntime.Must(v1alpha3.AddToScheme(scheme))
+	utilruntime.Must(scheme.SetVersionPriority(v1alpha3.SchemeGroupVersion, v1alpha2.SchemeGroupVersion))
 }
 
 // Load reads the file at path and attempts to convert into a `kind` Config; the file
 // can be one of the different API versions defined in scheme.
 // If path == "" then the default config is returned
 func Load(path string) (*config.Config, error) {
-	var latestPublicConfig = &v1alpha2.Config{}
+	if path == "" {
+		return decode(nil)
+	}
+
+	contents, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decode(contents)
+}
+
+// Decode converts contents into a `kind` Config like Load, contents can be
+// one of the different API versions defined in scheme.
+// If contents is empty then the default config is returned
+func Decode(contents []byte) (*config.Config, error) {
+	return decode(contents)
+}
+
+// readFile reads in the file at path, expanding the environment variables and
+// rendering the template it contains if any
+func readFile(path string) ([]byte, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return expand(path, contents)
+}
 
-	if path != "" {
-		// read in file
-		contents, err := ioutil.ReadFile(path)
+// LoadAll reads the file at path and attempts to convert each of the YAML
+// documents it contains into a `kind` Config, like Load. This allows defining
+// multiple clusters in a single file.
+func LoadAll(path string) ([]*config.Config, error) {
+	contents, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	documents, err := splitDocuments(path, contents)
+	if err != nil {
+		return nil, err
+	}
+	configs := []*config.Config{}
+	for i, document := range documents {
+		cfg, err := decode(document)
 		if err != nil {
-			return nil, err
+			return nil, errors.Wrapf(err, "invalid config document %d", i+1)
 		}
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
+
+// splitDocuments returns the non empty YAML documents in contents,
+// which were read from the file at path
+func splitDocuments(path string, contents []byte) ([][]byte, error) {
+	documents := [][]byte{}
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(contents)))
+	for {
+		document, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read yaml document")
+		}
+		// skip empty documents, e.g. after a leading or trailing separator
+		if isEmptyDocument(document) {
+			continue
+		}
+		documents = append(documents, document)
+	}
+	if len(documents) == 0 {
+		return nil, errors.Errorf("no config documents found in %s", path)
+	}
+	return documents, nil
+}
+
+// isEmptyDocument returns true if the YAML document contains only
+// whitespace and comments
+func isEmptyDocument(document []byte) bool {
+	for _, line := range bytes.Split(document, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 && !bytes.HasPrefix(line, []byte("#")) {
+			return false
+		}
+	}
+	return true
+}
+
+// decode attempts to convert contents into a `kind` Config; contents
+// can be one of the different API versions defined in scheme.
+// If contents is empty then the default config is returned
+func decode(contents []byte) (*config.Config, error) {
+	var latestPublicConfig = &v1alpha3.Config{}
 
+	if len(contents) > 0 {
 		// decode data into a internal api Config object because
 		// to leverage on conversion functions for all the api versions
 		var cfg = &config.Config{}
-		err = runtime.DecodeInto(Codecs.UniversalDecoder(), contents, cfg)
+		err := runtime.DecodeInto(Codecs.UniversalDecoder(), contents, cfg)
 		if err != nil {
 			return nil, errors.Wrap(err, "decoding failure")
 		}
@@ -17,7 +17,10 @@ limitations under the License.
 package encoding
 
 import (
+	"reflect"
 	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
 )
 
 func TestLoadCurrent(t *testing.T) {
@@ -63,6 +66,18 @@ func TestLoadCurrent(t *testing.T) {
 			ExpectReplicas: []string{"etcd", "lb", "control-plane1", "control-plane2", "control-plane3", "worker1", "worker2"},
 			ExpectError:    false,
 		},
+		{
+			TestName:       "v1alpha3 minimal",
+			Path:           "./testdata/v1alpha3/valid-minimal.yaml",
+			ExpectReplicas: []string{"control-plane"},
+			ExpectError:    false,
+		},
+		{
+			TestName:       "v1alpha3 cluster settings",
+			Path:           "./testdata/v1alpha3/valid-cluster-settings.yaml",
+			ExpectReplicas: []string{"control-plane", "worker"},
+			ExpectError:    false,
+		},
 		{
 			TestName:    "invalid path",
 			Path:        "./testdata/not-a-file.bogus",
@@ -105,3 +120,104 @@ func TestLoadCurrent(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadAll(t *testing.T) {
+	cases := []struct {
+		TestName    string
+		Path        string
+		ExpectNames []string
+		ExpectError bool
+	}{
+		{
+			TestName:    "v1alpha2 minimal",
+			Path:        "./testdata/v1alpha2/valid-minimal.yaml",
+			ExpectNames: []string{""},
+			ExpectError: false,
+		},
+		{
+			TestName:    "v1alpha3 multiple clusters",
+			Path:        "./testdata/v1alpha3/valid-multiple-clusters.yaml",
+			ExpectNames: []string{"foo", "bar"},
+			ExpectError: false,
+		},
+		{
+			TestName:    "invalid path",
+			Path:        "./testdata/not-a-file.bogus",
+			ExpectError: true,
+		},
+		{
+			TestName:    "Invalid kind",
+			Path:        "./testdata/invalid-kind.yaml",
+			ExpectError: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.TestName, func(t *testing.T) {
+			configs, err := LoadAll(c.Path)
+
+			// the error can be:
+			// - nil, in which case we should expect no errors or fail
+			if err != nil {
+				if !c.ExpectError {
+					t.Fatalf("unexpected error while Loading config: %v", err)
+				}
+				return
+			}
+			// - not nil, in which case we should expect errors or fail
+			if c.ExpectError {
+				t.Fatalf("unexpected lack or error while Loading config")
+			}
+
+			names := []string{}
+			for _, cfg := range configs {
+				names = append(names, cfg.Name)
+			}
+			if !reflect.DeepEqual(names, c.ExpectNames) {
+				t.Errorf("expected cluster names %v, got %v", c.ExpectNames, names)
+			}
+		})
+	}
+}
+
+func TestLoadClusterSettings(t *testing.T) {
+	cfg, err := Load("./testdata/v1alpha3/valid-cluster-settings.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error while Loading config: %v", err)
+	}
+	if cfg.Name != "settings" {
+		t.Errorf("expected cluster name %q, got %q", "settings", cfg.Name)
+	}
+	if cfg.Networking.IPFamily != config.IPv6Family || cfg.Networking.PodSubnet != "fd00:10:244::/56" {
+		t.Errorf("expected the IPv6 networking settings, got %+v", cfg.Networking)
+	}
+	if !cfg.FeatureGates["EphemeralContainers"] {
+		t.Errorf("expected the EphemeralContainers feature gate, got %v", cfg.FeatureGates)
+	}
+	if len(cfg.Nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %d", len(cfg.Nodes))
+	}
+	expectedMounts := []config.Mount{{HostPath: "/tmp/data", ContainerPath: "/data", ReadOnly: true}}
+	if !reflect.DeepEqual(cfg.Nodes[0].ExtraMounts, expectedMounts) {
+		t.Errorf("expected mounts %v, got %v", expectedMounts, cfg.Nodes[0].ExtraMounts)
+	}
+	if cfg.Nodes[1].Labels["tier"] != "frontend" {
+		t.Errorf("expected the worker labels, got %v", cfg.Nodes[1].Labels)
+	}
+}
+
+func TestLoadV1Alpha1Patches(t *testing.T) {
+	cfg, err := Load("./testdata/v1alpha1/valid-with-patches.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error while Loading config: %v", err)
+	}
+	// the patches are applied once, the cluster and the node patches are
+	// both applied to the node
+	patches := len(cfg.KubeadmConfigPatches) + len(cfg.Nodes[0].KubeadmConfigPatches)
+	if patches != 1 {
+		t.Errorf("expected 1 kubeadm config patch, got %d", patches)
+	}
+	patchesJSON6902 := len(cfg.KubeadmConfigPatchesJSON6902) + len(cfg.Nodes[0].KubeadmConfigPatchesJSON6902)
+	if patchesJSON6902 != 1 {
+		t.Errorf("expected 1 kubeadm config JSON 6902 patch, got %d", patchesJSON6902)
+	}
+}
@@ -34,6 +34,40 @@ func Funcs(codecs runtimeserializer.CodecFactory) []interface{} {
 func fuzzConfig(obj *config.Config, c fuzz.Continue) {
 	c.FuzzNoCustom(obj)
 
+	// Pinning values for fields that do not exist in all the API versions,
+	// their v1alpha3 conversion is tested by TestRoundTripV1Alpha3
+	obj.Name = ""
+	obj.Provider = ""
+	obj.Arch = ""
+	obj.LoadBalancer = config.LoadBalancer{}
+	obj.Etcd = config.Etcd{}
+	obj.Networking = config.Networking{}
+	obj.Proxy = config.Proxy{}
+	obj.Ingress = config.Ingress{}
+	obj.MetricsServer = false
+	obj.Dashboard = false
+	obj.Storage = config.Storage{}
+	obj.VolumeSnapshots = false
+	obj.Registry = config.Registry{}
+	obj.ImageRegistries = nil
+	obj.DockerDaemonConfigPatches = nil
+	obj.PreloadImages = nil
+	obj.ImageStore = config.ImageStore{}
+	obj.Tmpfs = config.Tmpfs{}
+	obj.Timeouts = config.Timeouts{}
+	obj.ReadinessChecks = nil
+	obj.Addons = nil
+	obj.KubeadmConfigPatches = nil
+	obj.KubeadmConfigPatchesJSON6902 = nil
+	obj.FeatureGates = nil
+	obj.RuntimeConfig = nil
+	obj.EnableAdmissionPlugins = nil
+	obj.DisableAdmissionPlugins = nil
+	obj.Audit = config.Audit{}
+	obj.Encryption = config.Encryption{}
+	obj.Certificates = config.Certificates{}
+	obj.OIDC = config.OIDC{}
+
 	// Pinning values for fields that get defaults if fuzz value is empty string or nil
 	obj.Nodes = []config.Node{{
 		Image: "foo:bar",
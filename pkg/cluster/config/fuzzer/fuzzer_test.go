@@ -17,12 +17,58 @@ limitations under the License.
 package fuzzer
 
 import (
+	"encoding/json"
 	"testing"
 
+	fuzz "github.com/google/gofuzz"
+
 	"k8s.io/apimachinery/pkg/api/apitesting/roundtrip"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/diff"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
 	"sigs.k8s.io/kind/pkg/cluster/config/encoding"
+	"sigs.k8s.io/kind/pkg/cluster/config/v1alpha3"
 )
 
 func TestRoundTripTypes(t *testing.T) {
 	roundtrip.RoundTripTestForAPIGroup(t, encoding.AddToScheme, Funcs)
 }
+
+// TestRoundTripV1Alpha3 checks the conversion of all the settings between
+// the internal config and v1alpha3, the fuzzer of TestRoundTripTypes pins
+// the settings that the older API versions can not represent
+func TestRoundTripV1Alpha3(t *testing.T) {
+	scheme := runtime.NewScheme()
+	encoding.AddToScheme(scheme)
+	f := fuzz.New().NilChance(.2).NumElements(1, 3)
+	for i := 0; i < 200; i++ {
+		original := &config.Config{}
+		f.Fuzz(original)
+		// the type meta is set by the encoding, not converted
+		original.TypeMeta = metav1.TypeMeta{}
+
+		external := &v1alpha3.Config{}
+		if err := scheme.Convert(original, external, nil); err != nil {
+			t.Fatalf("failed to convert to v1alpha3: %v", err)
+		}
+		b, err := json.Marshal(external)
+		if err != nil {
+			t.Fatalf("failed to encode v1alpha3 config: %v", err)
+		}
+		decoded := &v1alpha3.Config{}
+		if err := json.Unmarshal(b, decoded); err != nil {
+			t.Fatalf("failed to decode v1alpha3 config: %v", err)
+		}
+		roundTripped := &config.Config{}
+		if err := scheme.Convert(decoded, roundTripped, nil); err != nil {
+			t.Fatalf("failed to convert from v1alpha3: %v", err)
+		}
+
+		if !apiequality.Semantic.DeepEqual(original, roundTripped) {
+			t.Fatalf("config changed in the v1alpha3 roundtrip:\n%s", diff.ObjectReflectDiff(original, roundTripped))
+		}
+	}
+}
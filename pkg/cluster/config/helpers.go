@@ -36,3 +36,28 @@ func (n *Node) IsExternalEtcd() bool {
 func (n *Node) IsExternalLoadBalancer() bool {
 	return n.Role == ExternalLoadBalancerRole
 }
+
+// IsFake returns true if the node is a fake node simulated by kwok
+func (n *Node) IsFake() bool {
+	return n.Role == FakeRole
+}
+
+// IsWindows returns true if the node runs Windows
+func (n *Node) IsWindows() bool {
+	return n.OS == WindowsOS
+}
+
+// Enabled returns true if the API server audit logging is enabled
+func (a *Audit) Enabled() bool {
+	return a.Policy != "" || a.PolicyFile != ""
+}
+
+// Enabled returns true if the encryption at rest is enabled
+func (e *Encryption) Enabled() bool {
+	return e.Provider != "" || e.Config != ""
+}
+
+// Enabled returns true if the OpenID Connect authentication is enabled
+func (o *OIDC) Enabled() bool {
+	return o.IssuerURL != ""
+}
@@ -28,10 +28,604 @@ type Config struct {
 	// TypeMeta representing the type of the object and its API schema version.
 	metav1.TypeMeta
 
+	// Name is the cluster context name, this is optional and only used
+	// when a name is not otherwise specified, e.g. via `--name`
+	Name string
+
+	// Provider is the container runtime the nodes are run with, one of
+	// docker, podman or nerdctl
+	// Defaults to the KIND_EXPERIMENTAL_PROVIDER environment variable if set,
+	// and otherwise to the runtime available on the host, docker first
+	Provider Provider
+
+	// Arch is the architecture the node images are pulled for, one of amd64,
+	// arm64, ppc64le or s390x, node images for another architecture than
+	// the host run under emulation
+	// Defaults to the architecture of the host running the nodes
+	Arch string
+
 	// Nodes constains the list of nodes defined in the `kind` Config
 	Nodes []Node `json:"nodes,"`
+
+	// LoadBalancer configures the external load balancer, if any
+	LoadBalancer LoadBalancer
+
+	// Etcd configures the etcd cluster backing the API server
+	Etcd Etcd
+
+	// Networking configures the cluster networking
+	Networking Networking
+
+	// Proxy configures the HTTP proxies used by the nodes, e.g. to pull images
+	// Defaults to the proxy environment variables of the host
+	Proxy Proxy
+
+	// Ingress configures the ingress controller installed in the cluster, if any
+	Ingress Ingress
+
+	// MetricsServer installs metrics-server, which serves the resource
+	// metrics of kubectl top and of the horizontal pod autoscaler
+	MetricsServer bool
+
+	// Dashboard installs the Kubernetes dashboard, along with an admin-user
+	// service account to log into it with
+	Dashboard bool
+
+	// Storage configures the default storage class of the cluster and its
+	// provisioner
+	Storage Storage
+
+	// VolumeSnapshots installs the VolumeSnapshot CRDs and the snapshot
+	// controller of the CSI external-snapshotter, which the CSI drivers
+	// supporting snapshots require
+	VolumeSnapshots bool
+
+	// Registry configures the local registry of the cluster, if any
+	Registry Registry
+
+	// ImageRegistries configures how the nodes pull images from registries,
+	// e.g. mirrors and credentials
+	ImageRegistries []ImageRegistry
+	// DockerDaemonConfigPatches are applied to the docker daemon config of
+	// every node, /etc/docker/daemon.json, as JSON merge patches, after
+	// the imageRegistries settings
+	// https://tools.ietf.org/html/rfc7386
+	// These should be inline yaml or json blob-strings
+	DockerDaemonConfigPatches []string
+
+	// PreloadImages are pulled and saved on the host once, and loaded into
+	// every control-plane and worker node while provisioning the cluster
+	PreloadImages []string
+
+	// ImageStore configures docker volumes backing the image store of the
+	// nodes, so that the images pulled by a cluster are reused by the next
+	// cluster created with the same volumes
+	ImageStore ImageStore
+
+	// Tmpfs configures the node data kept in memory, e.g. to speed up IO
+	// heavy test suites
+	Tmpfs Tmpfs
+
+	// KubeadmConfigPatches are applied to the generated kubeadm config of
+	// every node as strategic merge patches to `kustomize build` internally,
+	// before the patches of the node
+	// https://github.com/kubernetes/community/blob/master/contributors/devel/strategic-merge-patch.md
+	// This should be an inline yaml blob-string
+	KubeadmConfigPatches []string
+	// KubeadmConfigPatchesJSON6902 are applied to the generated kubeadm config
+	// of every node as patchesJson6902 to `kustomize build`, before the
+	// patches of the node
+	KubeadmConfigPatchesJSON6902 []kustomize.PatchJSON6902
+
+	// FeatureGates are the Kubernetes feature gates enabled or disabled
+	// for the API server, controller manager, scheduler, kubelet and kube-proxy
+	FeatureGates map[string]bool
+	// RuntimeConfig are the API server --runtime-config values, e.g.
+	// to enable alpha APIs
+	RuntimeConfig map[string]string
+	// EnableAdmissionPlugins and DisableAdmissionPlugins are the admission
+	// plugins enabled and disabled in the API server, in addition to its
+	// default plugins and NodeRestriction, which kubeadm enables
+	EnableAdmissionPlugins  []string
+	DisableAdmissionPlugins []string
+
+	// Audit configures the API server audit logging, if any
+	Audit Audit
+
+	// Encryption configures the encryption at rest of the API resources in
+	// etcd, e.g. secrets, if any
+	Encryption Encryption
+
+	// Certificates configures the cluster certificates, e.g. a custom CA
+	Certificates Certificates
+
+	// OIDC configures the API server OpenID Connect authentication, if any
+	OIDC OIDC
+
+	// Timeouts configures how long the cluster creation waits for each of
+	// the provisioning phases, e.g. longer on slow CI machines
+	Timeouts Timeouts
+
+	// ReadinessChecks are extra conditions the cluster is not ready without,
+	// checked after the standard readiness checks when the cluster is waited
+	// for, e.g. the rollout of an operator
+	ReadinessChecks []ReadinessCheck
+
+	// Addons are manifests and Helm charts installed in order once the
+	// cluster is created and, if it is waited for, ready, see Addon
+	Addons []Addon
+}
+
+// Audit configures the API server audit logging, the audit log is written to
+// /var/log/kubernetes/audit/audit.log on the control-plane nodes, and is
+// exported along with the other logs by `kind export logs`
+type Audit struct {
+	// Policy is an inline audit policy, an audit.k8s.io Policy yaml
+	// blob-string, audit logging is enabled if either Policy or PolicyFile
+	// is set
+	Policy string
+	// PolicyFile is the path of an audit policy file on the host, relative
+	// to the current directory
+	PolicyFile string
+}
+
+// Encryption configures the encryption at rest of the API resources in
+// etcd, with an EncryptionConfiguration written to the control-plane nodes
+// and passed to the API server, this is enabled if either Provider or Config
+// is set
+type Encryption struct {
+	// Provider is the encryption provider of the generated config, one of
+	// aescbc, aesgcm, secretbox or kms, a random key is generated for the
+	// aescbc, aesgcm and secretbox providers
+	Provider EncryptionProvider
+	// Resources are the resources encrypted by the generated config
+	// Defaults to secrets
+	Resources []string
+	// KMSPluginName is the name of the KMS plugin of the kms provider, which
+	// should listen on unix:///var/run/kmsplugin/socket.sock on the
+	// control-plane nodes
+	KMSPluginName string
+	// Config is an inline EncryptionConfiguration yaml blob-string, used
+	// as is instead of generating one, e.g. with several keys for testing
+	// key rotation
+	Config string
 }
 
+// Certificates configures the cluster certificates
+type Certificates struct {
+	// CACertFile and CAKeyFile are the paths on the host of a custom cluster
+	// CA certificate and key in PEM format, these are written to the
+	// control-plane nodes before kubeadm init, which then signs the cluster
+	// certificates with them rather than with a generated CA
+	CACertFile string
+	CAKeyFile  string
+	// SigningDuration is the validity of the certificates signed by the
+	// controller manager, e.g. the rotated kubelet certificates, as a
+	// duration like "1h"
+	// Defaults to the controller manager default, one year
+	SigningDuration string
+}
+
+// OIDC configures the API server OpenID Connect token authentication, the
+// kubeconfig written by kind has an additional context authenticating with
+// the ID tokens of the issuer, see the kubelogin kubectl plugin
+type OIDC struct {
+	// IssuerURL is the https URL of the OpenID issuer, OpenID Connect
+	// authentication is enabled if set
+	IssuerURL string
+	// ClientID is the client ID the ID tokens must be issued for
+	ClientID string
+	// ClientSecret is the client secret the kubeconfig context gets the ID
+	// tokens with, if the client is not public
+	ClientSecret string
+	// CAFile is the path on the host of the CA certificate of the issuer in
+	// PEM format, the node CA certificates are used if not set
+	CAFile string
+	// UsernameClaim is the claim of the user name, UsernamePrefix is
+	// prepended to it
+	// Defaults to the API server default, the "sub" claim
+	UsernameClaim  string
+	UsernamePrefix string
+	// GroupsClaim is the claim of the user groups, GroupsPrefix is
+	// prepended to them
+	GroupsClaim  string
+	GroupsPrefix string
+	// RequiredClaims are the claims the ID tokens must have, with their
+	// values
+	RequiredClaims map[string]string
+	// ExtraScopes are the scopes the kubeconfig context requests along
+	// with "openid", e.g. "email" or "groups"
+	ExtraScopes []string
+}
+
+// Timeouts contains how long the cluster creation waits for each of the
+// provisioning phases, and how often it checks while waiting, as durations,
+// e.g. "2m" or "500ms"
+type Timeouts struct {
+	// NodeBoot is how long to wait for a node container to boot, until
+	// docker is ready on the node
+	// Defaults to 30s
+	NodeBoot string
+	// KubeadmInit is how long kubeadm init may run on the bootstrap control
+	// plane, until the control plane is up
+	// Defaults to no limit
+	KubeadmInit string
+	// NodeRegistration is how long to wait for a joined node to be
+	// registered, before applying its labels and taints
+	// Defaults to 1m
+	NodeRegistration string
+	// CNIRollout is how long to wait for the default CNI network plugin to
+	// be rolled out once applied, before the other nodes join
+	// Defaults to not waiting
+	CNIRollout string
+	// Ready is how long to wait for the cluster to be ready once created, as
+	// with the --wait flag, which takes precedence
+	// Defaults to not waiting
+	Ready string
+	// PollInterval is the interval between the checks while waiting for the
+	// nodes and the cluster
+	// Defaults to 1s
+	PollInterval string
+}
+
+// ReadinessCheck is a condition of Kubernetes resources the cluster is not
+// ready without, checked with kubectl on the bootstrap control plane, either
+// a status condition, as in `kubectl wait --for=condition=<condition>`, or a
+// rollout, as in `kubectl rollout status`
+type ReadinessCheck struct {
+	// Resource is the resource checked, as in kubectl, either a single
+	// resource, e.g. "crd/foos.example.com" or "daemonset/my-operator", or a
+	// resource type along with Selector, e.g. "pods"
+	Resource string
+	// Namespace is the namespace of the namespaced resources
+	// Defaults to the default namespace
+	Namespace string
+	// Selector is the label selector of the resources of the resource type
+	// checked, all of them must meet the condition and at least one exist
+	Selector string
+	// Condition is the status condition type that must be True, e.g.
+	// "Established" or "Available"
+	Condition string
+	// Rollout requires the rollout of the deployment, daemonset or
+	// statefulset Resource to be complete instead of a condition
+	Rollout bool
+}
+
+// Addon is a set of manifests or a Helm chart installed once the cluster is
+// created, the addons are installed in order, each one after the Wait checks
+// of the previous one are met
+type Addon struct {
+	// Name identifies the addon, it must be a DNS-1123 label and is the
+	// default Helm release name of Chart
+	Name string
+	// Manifests are inline manifests, yaml blob-strings
+	Manifests []string
+	// Files are the paths of manifest files on the host, relative to the
+	// current directory
+	Files []string
+	// URLs are the URLs of manifests, fetched from the bootstrap control
+	// plane node
+	URLs []string
+	// Chart is a Helm chart, installed with the helm binary of the host
+	// instead of manifests
+	Chart *Chart
+	// Wait are the readiness checks of the addon, waited for after it is
+	// installed and before the next addon is
+	Wait []ReadinessCheck
+	// WaitTimeout is how long to wait for the Wait checks, as a duration,
+	// e.g. "2m"
+	// Defaults to 5m
+	WaitTimeout string
+}
+
+// Chart is a Helm chart installed as an addon, as with
+// `helm upgrade --install`
+type Chart struct {
+	// Name is the chart, either the name of a chart of Repo, a chart
+	// reference, e.g. "oci://registry.example.com/charts/foo", or the path of
+	// a chart on the host
+	Name string
+	// Repo is the URL of the chart repository of Name, if any
+	Repo string
+	// Version is the chart version
+	// Defaults to the latest version
+	Version string
+	// Release is the release name
+	// Defaults to the addon name
+	Release string
+	// Namespace is the namespace of the release, created if missing
+	// Defaults to the default namespace
+	Namespace string
+	// Values are the inline values of the release, a yaml blob-string
+	Values string
+}
+
+// EncryptionProvider is the encryption provider of the API resources
+type EncryptionProvider string
+
+const (
+	// AESCBCEncryptionProvider is the aescbc encryption provider
+	AESCBCEncryptionProvider EncryptionProvider = "aescbc"
+	// AESGCMEncryptionProvider is the aesgcm encryption provider
+	AESGCMEncryptionProvider EncryptionProvider = "aesgcm"
+	// SecretboxEncryptionProvider is the secretbox encryption provider
+	SecretboxEncryptionProvider EncryptionProvider = "secretbox"
+	// KMSEncryptionProvider is the kms encryption provider, using a KMS plugin
+	KMSEncryptionProvider EncryptionProvider = "kms"
+)
+
+// Networking contains settings for the cluster networking
+type Networking struct {
+	// APIServerAddress is the host address the API server is published on
+	// Defaults to all the host interfaces
+	APIServerAddress string
+	// APIServerPort is the host port the API server is published on
+	// Defaults to a random port
+	APIServerPort int32
+	// APIServerCertSANs are additional host names and IP addresses of the
+	// API server certificate, the kubeconfig written by kind contains a
+	// context for each of them, e.g. to reach the API server from other hosts
+	APIServerCertSANs []string
+	// PodSubnet is the CIDR range of the pod IPs, for dual-stack clusters
+	// this may be a comma separated IPv4 and IPv6 range
+	// Defaults to the range of the CNI network plugin, fd00:10:244::/64 for
+	// ipv6 and 10.244.0.0/16,fd00:10:244::/64 for dual
+	PodSubnet string
+	// ServiceSubnet is the CIDR range of the service virtual IPs
+	// Defaults to the kubeadm default, 10.96.0.0/12, fd00:10:96::/112 for ipv6
+	ServiceSubnet string
+	// IPFamily is the IP family of the cluster, either ipv4, ipv6 or dual
+	// for dual-stack IPv4 and IPv6
+	// Defaults to ipv4
+	IPFamily IPFamily
+	// DisableDefaultCNI skips installing the default CNI network plugin,
+	// so that another one can be installed, the nodes are not Ready until then
+	DisableDefaultCNI bool
+	// KubeProxyMode is the kube-proxy mode, either iptables, ipvs or nftables,
+	// or none to not run kube-proxy, e.g. if the CNI network plugin replaces it
+	// Defaults to the kube-proxy default, iptables
+	KubeProxyMode KubeProxyMode
+	// DockerNetwork configures the docker network the nodes are attached to
+	DockerNetwork DockerNetwork
+	// DNS configures the DNS resolver of the node containers, overriding the
+	// DNS config docker derives from the host
+	DNS DNS
+	// MTU is the MTU of the docker network and thus of the node interfaces,
+	// the MTU of the default CNI network plugin is lowered accordingly, e.g.
+	// for hosts connected through a VPN
+	// Defaults to the docker default, 1500
+	MTU int32
+}
+
+// DockerNetwork contains settings for the docker network of the cluster
+type DockerNetwork struct {
+	// Name is the name of the docker network, an existing network is used
+	// as is, otherwise the network is created along with the cluster and
+	// deleted with it
+	// Defaults to kind-<cluster name>
+	Name string
+	// Subnet is the IPv4 subnet of the network
+	// Defaults to a subnet allocated by docker
+	Subnet string
+	// Gateway is the IPv4 gateway of the network, in Subnet
+	// Defaults to the gateway allocated by docker, the first address in Subnet
+	Gateway string
+	// IPv6Subnet is the IPv6 subnet of the network of IPv6 and dual-stack
+	// clusters
+	// Defaults to a unique local /64 subnet derived from the cluster name
+	IPv6Subnet string
+}
+
+// Proxy contains the HTTP proxy settings of the nodes, these are set as the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of the node
+// containers and of the services in the nodes
+type Proxy struct {
+	// HTTPProxy is the proxy URL for HTTP requests
+	HTTPProxy string
+	// HTTPSProxy is the proxy URL for HTTPS requests
+	HTTPSProxy string
+	// NoProxy is a comma separated list of hosts, domains and CIDR ranges
+	// reached without a proxy, in addition to the nodes and cluster subnets
+	NoProxy string
+}
+
+// DNS contains the DNS resolver settings of the node containers, as in
+// resolv.conf, names are resolved by the docker embedded DNS server which
+// forwards the queries to the nameservers
+type DNS struct {
+	// Nameservers are the IP addresses of the DNS servers
+	// Defaults to the DNS servers of the host
+	Nameservers []string
+	// Search are the DNS search domains
+	// Defaults to the search domains of the host
+	Search []string
+	// Options are the resolver options, e.g. ndots:2
+	// Defaults to the resolver options of the host
+	Options []string
+}
+
+// Storage configures the default storage class of the cluster, whose
+// persistent volumes are provisioned in a directory of the nodes by the
+// local-path-provisioner
+type Storage struct {
+	// Disabled disables the default storage class and its provisioner
+	Disabled bool
+	// ClassName is the name of the default storage class
+	// Defaults to "standard"
+	ClassName string
+	// NodePath is the directory of the nodes the volumes are provisioned in,
+	// in a <namespace>/<claim name> directory per volume
+	// Defaults to /var/local-path-provisioner
+	NodePath string
+	// HostPath is a directory of the host mounted at NodePath on the
+	// control-plane and worker nodes, so that the volumes persist across
+	// cluster recreation, the claims of the same namespace and name finding
+	// their data again. Relative paths are resolved against the current
+	// working directory
+	HostPath string
+}
+
+// Ingress contains the ingress controller settings of the cluster
+type Ingress struct {
+	// Controller is the ingress controller installed in the cluster, the
+	// controller runs on the node with ingressReady set
+	// Defaults to none
+	Controller IngressController
+}
+
+// IngressController defines the possible ingress controllers
+type IngressController string
+
+const (
+	// NginxIngressController is the ingress-nginx controller
+	NginxIngressController IngressController = "nginx"
+)
+
+// Provider defines the possible container runtimes the nodes are run with
+type Provider string
+
+const (
+	// DockerProvider runs the nodes with docker
+	DockerProvider Provider = "docker"
+	// PodmanProvider runs the nodes with podman, rootful or rootless
+	PodmanProvider Provider = "podman"
+	// NerdctlProvider runs the nodes with containerd, driven by nerdctl
+	NerdctlProvider Provider = "nerdctl"
+)
+
+// Registry contains the settings of the local registry of the cluster, a
+// registry container on the docker network of the cluster the nodes can pull
+// images from, published on the host so that images can be pushed to it
+type Registry struct {
+	// Enabled runs the local registry
+	Enabled bool
+	// HostPort is the port the registry is published on at the host loopback
+	// address
+	// Defaults to 5000
+	HostPort int32
+}
+
+// Tmpfs configures the node data kept in tmpfs mounts of the node
+// containers, in memory, trading memory for IO speed. The data is lost when
+// the node containers are stopped, so the cluster does not survive them being
+// restarted
+type Tmpfs struct {
+	// Etcd keeps the etcd data of the control-plane and external etcd
+	// nodes, /var/lib/etcd, in a tmpfs, so that etcd does not wait on the
+	// disk to sync its writes
+	Etcd bool
+	// EtcdSize is the size limit of the etcd tmpfs, as a Kubernetes
+	// quantity, e.g. "512Mi"
+	// Defaults to half of the host memory
+	EtcdSize string
+	// ContainerRuntime keeps the data directory of the container runtime of
+	// the control-plane and worker nodes, /var/lib/docker, with the images
+	// and the containers of the pods, in a tmpfs
+	ContainerRuntime bool
+	// ContainerRuntimeSize is the size limit of the container runtime tmpfs,
+	// as a Kubernetes quantity, e.g. "4Gi"
+	// Defaults to half of the host memory
+	ContainerRuntimeSize string
+}
+
+// ImageStore contains the settings of the image store of the nodes, the
+// docker data directory /var/lib/docker
+type ImageStore struct {
+	// Volume is the prefix of the names of the docker volumes, the control
+	// plane and worker nodes use the volume named by the prefix and the name
+	// of the node within the cluster, e.g. <volume>-control-plane or
+	// <volume>-worker2. A volume backs one running node at a time, use the
+	// cluster name for a store per cluster. The volumes are kept when the
+	// cluster is deleted.
+	// Defaults to a new anonymous volume per node
+	Volume string
+}
+
+// ImageRegistry contains the settings of an image registry the nodes pull
+// images from
+type ImageRegistry struct {
+	// Host is the registry host, with the port if any, as in image
+	// references, e.g. registry.example.com:5000, or docker.io for Docker Hub
+	Host string
+	// Mirrors are the URLs of the registry mirrors the nodes try first, the
+	// docker daemon of the nodes only supports mirrors of Docker Hub
+	Mirrors []string
+	// Insecure allows pulling from the registry over plain HTTP, or over
+	// HTTPS without verifying the registry certificate
+	Insecure bool
+	// Username and Password are the credentials the kubelet pulls images
+	// from the registry with, if set
+	Username string
+	Password string
+}
+
+// DockerHubRegistryHost is the ImageRegistry host of Docker Hub
+const DockerHubRegistryHost = "docker.io"
+
+// Etcd contains settings for the etcd cluster backing the API server
+type Etcd struct {
+	// Topology is either stacked, with an etcd member on each control-plane
+	// node, or external, with an etcd member on each external-etcd node
+	// Defaults to external if there are external-etcd nodes, stacked otherwise
+	Topology EtcdTopology
+}
+
+// EtcdTopology defines the possible etcd topologies
+type EtcdTopology string
+
+const (
+	// StackedEtcdTopology runs etcd on the control-plane nodes
+	StackedEtcdTopology EtcdTopology = "stacked"
+	// ExternalEtcdTopology runs etcd on the external-etcd nodes
+	ExternalEtcdTopology EtcdTopology = "external"
+)
+
+// IPFamily defines the possible IP families of the cluster
+type IPFamily string
+
+const (
+	// IPv4Family is an IPv4 only cluster
+	IPv4Family IPFamily = "ipv4"
+	// IPv6Family is an IPv6 only cluster
+	IPv6Family IPFamily = "ipv6"
+	// DualStackFamily is a dual-stack IPv4 and IPv6 cluster
+	DualStackFamily IPFamily = "dual"
+)
+
+// KubeProxyMode defines the possible kube-proxy modes
+type KubeProxyMode string
+
+const (
+	// IPTablesProxyMode is the kube-proxy iptables mode
+	IPTablesProxyMode KubeProxyMode = "iptables"
+	// IPVSProxyMode is the kube-proxy ipvs mode
+	IPVSProxyMode KubeProxyMode = "ipvs"
+	// NFTablesProxyMode is the kube-proxy nftables mode
+	NFTablesProxyMode KubeProxyMode = "nftables"
+	// NoneProxyMode disables kube-proxy
+	NoneProxyMode KubeProxyMode = "none"
+)
+
+// LoadBalancer contains settings for the external load balancer for the
+// API server, hosted by the node with external-load-balancer role
+type LoadBalancer struct {
+	// Type is the load balancer implementation
+	// Defaults to "haproxy"
+	Type LoadBalancerType
+}
+
+// LoadBalancerType defines the possible implementations of the external load balancer
+type LoadBalancerType string
+
+const (
+	// HAProxyLoadBalancer identifies the haproxy load balancer implementation
+	HAProxyLoadBalancer LoadBalancerType = "haproxy"
+	// NginxLoadBalancer identifies the nginx load balancer implementation
+	NginxLoadBalancer LoadBalancerType = "nginx"
+	// EnvoyLoadBalancer identifies the envoy load balancer implementation
+	EnvoyLoadBalancer LoadBalancerType = "envoy"
+)
+
 // Node contains settings for a node in the `kind` Config.
 // A node in kind config represent a container that will be provisioned with all the components
 // required for the assigned role in the Kubernetes cluster.
@@ -54,8 +648,211 @@ type Node struct {
 	// KubeadmConfigPatchesJSON6902 are applied to the generated kubeadm config
 	// as patchesJson6902 to `kustomize build`
 	KubeadmConfigPatchesJSON6902 []kustomize.PatchJSON6902
+	// KubeletExtraArgs are additional kubelet flags of the node, without the
+	// leading dashes, e.g. "max-pods", these take precedence over the flags
+	// set by kind
+	KubeletExtraArgs map[string]string
+	// KubeletConfigPatches are applied to the KubeletConfiguration of the
+	// node as strategic merge patches, once written by kubeadm, e.g. to
+	// tune maxPods or the eviction thresholds of the node only
+	// This should be an inline yaml blob-string
+	KubeletConfigPatches []string
+	// ExtraMounts describes additional mount points for the node container,
+	// these may be used e.g. to mount source trees or datasets from the host
+	ExtraMounts []Mount
+	// ExtraPortMappings describes additional port mappings for the node
+	// container, these may be used e.g. to reach NodePort services from the host
+	ExtraPortMappings []PortMapping
+	// Labels are the Kubernetes labels of the node, these are set by the
+	// kubelet when registering the node, e.g. topology labels
+	Labels map[string]string
+	// Taints are the Kubernetes taints of the node, these are set by the
+	// kubelet when registering the node
+	Taints []Taint
+	// IngressReady labels the node with ingress-ready=true and publishes the
+	// ports 80 and 443 of the node on the host, so that an ingress controller
+	// running on the node is reachable from the host
+	IngressReady bool
+	// IPAddress is the static IPv4 address of the node container in the
+	// docker network of the cluster, so that it does not change when the
+	// node is restarted, this must be in networking.dockerNetwork.subnet
+	// Defaults to an address allocated by docker
+	IPAddress string
+	// IPv6Address is the static IPv6 address of the node container, this
+	// must be in networking.dockerNetwork.ipv6Subnet
+	// Defaults to an address allocated by docker
+	IPv6Address string
+	// OS is the operating system of the node, Windows nodes run a Windows
+	// node image in a Windows container and can only be workers
+	// Defaults to "linux"
+	OS NodeOS
+	// Resources are the resource limits of the node container, the
+	// kubelet reserves the rest of the host resources so that the node
+	// allocatable matches the limits
+	Resources NodeResources
+	// Sysctls are the kernel parameters set in the node container, these
+	// must be namespaced, e.g. "net.core.somaxconn", pods are also allowed
+	// to set them on the node if they are not safe sysctls
+	Sysctls map[string]string
+	// ExtraDevices are host devices exposed in the node container, e.g.
+	// loop devices or /dev/fuse for testing storage systems on the node
+	ExtraDevices []Device
+	// CPUManager configures the CPU and Topology managers of the kubelet of
+	// the node, for testing the resource management features of Kubernetes
+	CPUManager CPUManager
+}
+
+// NodeResources are the resource limits of a node container, in the format
+// of the Kubernetes resource quantities, e.g. "1.5" cpus or "2Gi" of memory,
+// and the host GPUs passed through to it
+type NodeResources struct {
+	// CPUs is the number of CPUs the node can use, e.g. "2" or "500m"
+	// Defaults to no limit
+	CPUs string
+	// Memory is the memory limit of the node, e.g. "4Gi"
+	// Defaults to no limit
+	Memory string
+	// PIDs is the maximum number of processes in the node container, this
+	// is not reflected in the node allocatable
+	// Defaults to no limit
+	PIDs int64
+	// GPUs are the host GPUs passed through to the node container, as in the
+	// docker --gpus flag, either "all", a number of GPUs, e.g. "2", or a
+	// list of devices, e.g. "device=0,1", this requires the NVIDIA container
+	// toolkit on the host
+	// Defaults to no GPUs
+	GPUs string
+	// CPUSet are the host CPUs the node container runs on, as a list, e.g.
+	// "0-3,8", as in the docker --cpuset-cpus flag, the CPU manager of the
+	// kubelet only assigns these CPUs to pods
+	// Defaults to all the host CPUs
+	CPUSet string
+	// NUMANodes are the host NUMA nodes the node container allocates memory
+	// on, as a list, e.g. "0", as in the docker --cpuset-mems flag
+	// Defaults to all the host NUMA nodes
+	NUMANodes string
 }
 
+// CPUManager configures the CPU manager and the Topology manager of the
+// kubelet of a node
+type CPUManager struct {
+	// Policy is the CPU manager policy, with the static policy the containers
+	// of Guaranteed pods requesting whole CPUs get exclusive CPUs
+	// Defaults to the "none" policy of the kubelet
+	Policy CPUManagerPolicy
+	// ReservedCPUs are the CPUs of the node reserved for the system and the
+	// kubelet, as a list, e.g. "0,1", these are never exclusive, this is
+	// only supported with the static policy
+	// Defaults to the first CPU of the node with the static policy
+	ReservedCPUs string
+	// TopologyManagerPolicy is the policy aligning the CPUs and the devices
+	// assigned to containers on NUMA nodes
+	// Defaults to the "none" policy of the kubelet
+	TopologyManagerPolicy TopologyManagerPolicy
+}
+
+// Device is a host device exposed in a node container, as with the docker
+// --device flag
+type Device struct {
+	// HostPath is the path of the device on the host, e.g. "/dev/fuse"
+	HostPath string
+	// ContainerPath is the path of the device in the node container
+	// Defaults to HostPath
+	ContainerPath string
+	// CgroupPermissions are the device cgroup permissions of the node
+	// container on the device, a combination of r (read), w (write) and m
+	// (mknod)
+	// Defaults to "rwm"
+	CgroupPermissions string
+}
+
+// Taint specifies a Kubernetes taint of the node.
+// This is a simplified version of the kubernetes v1.Taint
+type Taint struct {
+	// Key is the taint key
+	Key string
+	// Value is the taint value, this is optional
+	Value string
+	// Effect is the taint effect, one of NoSchedule, PreferNoSchedule or NoExecute
+	Effect TaintEffect
+}
+
+// TaintEffect defines the possible taint effects,
+// these match the kubernetes v1.TaintEffect values
+type TaintEffect string
+
+const (
+	// TaintEffectNoSchedule means new pods are not scheduled on the node
+	// unless they tolerate the taint
+	TaintEffectNoSchedule TaintEffect = "NoSchedule"
+	// TaintEffectPreferNoSchedule means new pods are preferably not scheduled
+	// on the node unless they tolerate the taint
+	TaintEffectPreferNoSchedule TaintEffect = "PreferNoSchedule"
+	// TaintEffectNoExecute means pods are evicted from the node unless they
+	// tolerate the taint
+	TaintEffectNoExecute TaintEffect = "NoExecute"
+)
+
+// PortMapping specifies a host port mapped into the node container
+type PortMapping struct {
+	// ContainerPort is the port in the container to map to
+	ContainerPort int32
+	// HostPort is the port on the host to map from
+	// Defaults to a random free port if zero
+	HostPort int32
+	// ListenAddress is the host address to bind to
+	// Defaults to all the host addresses
+	ListenAddress string
+	// Protocol is the protocol of the port, one of TCP, UDP or SCTP
+	// Defaults to "TCP"
+	Protocol PortMappingProtocol
+}
+
+// PortMappingProtocol defines the possible protocols of port mappings,
+// these match the kubernetes v1.Protocol values
+type PortMappingProtocol string
+
+const (
+	// PortMappingProtocolTCP specifies TCP protocol
+	PortMappingProtocolTCP PortMappingProtocol = "TCP"
+	// PortMappingProtocolUDP specifies UDP protocol
+	PortMappingProtocolUDP PortMappingProtocol = "UDP"
+	// PortMappingProtocolSCTP specifies SCTP protocol
+	PortMappingProtocolSCTP PortMappingProtocol = "SCTP"
+)
+
+// Mount specifies a host path to mount into the node container.
+// This is a simplified version of the kubernetes v1.VolumeMount
+type Mount struct {
+	// ContainerPath is the path of the mount within the container
+	ContainerPath string
+	// HostPath is the path of the mount on the host, relative paths are
+	// resolved against the current working directory
+	HostPath string
+	// ReadOnly makes the mount read-only if set
+	ReadOnly bool
+	// Propagation is the mount propagation mode
+	// Defaults to "None"
+	Propagation MountPropagation
+}
+
+// MountPropagation defines the possible mount propagation modes,
+// these match the kubernetes v1.MountPropagationMode values
+type MountPropagation string
+
+const (
+	// MountPropagationNone means the mount does not receive or propagate
+	// any mounts (private)
+	MountPropagationNone MountPropagation = "None"
+	// MountPropagationHostToContainer means the mount receives all the mounts
+	// created on the host below the host path (rslave)
+	MountPropagationHostToContainer MountPropagation = "HostToContainer"
+	// MountPropagationBidirectional means the mount receives the mounts from
+	// the host and propagates the mounts created in the container back to the
+	// host (rshared)
+	MountPropagationBidirectional MountPropagation = "Bidirectional"
+)
+
 // NodeRole defines possible role for nodes in a Kubernetes cluster managed by `kind`
 type NodeRole string
 
@@ -72,4 +869,50 @@ const (
 	// in HA configurations.
 	// Please note that `kind` nodes hosting external load balancer are not kubernetes nodes
 	ExternalLoadBalancerRole NodeRole = "external-load-balancer"
+	// FakeRole identifies a fake Kubernetes node, simulated by kwok in the
+	// control plane for testing the scheduler and the controllers at scale.
+	// Please note that `kind` does not create containers for fake nodes, they
+	// only support labels, taints, and cpus and memory resources as capacity
+	FakeRole NodeRole = "fake"
+)
+
+// NodeOS is the operating system of a node
+type NodeOS string
+
+const (
+	// LinuxOS is the default node operating system
+	LinuxOS NodeOS = "linux"
+	// WindowsOS identifies a Windows worker node, which runs a Windows node
+	// image providing the kubelet, kubeadm and a container runtime
+	WindowsOS NodeOS = "windows"
+)
+
+// CPUManagerPolicy is the CPU manager policy of the kubelet of a node
+type CPUManagerPolicy string
+
+const (
+	// NoneCPUManagerPolicy is the default CPU manager policy, the containers
+	// share the CPUs of the node
+	NoneCPUManagerPolicy CPUManagerPolicy = "none"
+	// StaticCPUManagerPolicy assigns exclusive CPUs to the containers of
+	// Guaranteed pods requesting whole CPUs
+	StaticCPUManagerPolicy CPUManagerPolicy = "static"
+)
+
+// TopologyManagerPolicy is the Topology manager policy of the kubelet of a
+// node
+type TopologyManagerPolicy string
+
+const (
+	// NoneTopologyManagerPolicy is the default Topology manager policy, the
+	// resources are assigned without aligning them
+	NoneTopologyManagerPolicy TopologyManagerPolicy = "none"
+	// BestEffortTopologyManagerPolicy prefers resources aligned on NUMA nodes
+	BestEffortTopologyManagerPolicy TopologyManagerPolicy = "best-effort"
+	// RestrictedTopologyManagerPolicy rejects pods whose resources can not be
+	// aligned on their preferred NUMA nodes
+	RestrictedTopologyManagerPolicy TopologyManagerPolicy = "restricted"
+	// SingleNUMANodeTopologyManagerPolicy rejects pods whose resources can
+	// not be aligned on a single NUMA node
+	SingleNUMANodeTopologyManagerPolicy TopologyManagerPolicy = "single-numa-node"
 )
@@ -40,6 +40,11 @@ func Convert_v1alpha1_Config_To_config_Config(in *Config, out *config.Config, s
 
 	out.Nodes = []config.Node{node}
 
+	// the v1alpha1 patches are kept on the node only, the cluster patches
+	// and the node patches are both applied to the node
+	out.KubeadmConfigPatches = nil
+	out.KubeadmConfigPatchesJSON6902 = nil
+
 	return nil
 }
 
@@ -62,6 +67,7 @@ func Convert_config_Config_To_v1alpha1_Config(in *config.Config, out *Config, s
 		return fmt.Errorf("invalid conversion. `kind` config without a control-plane Node cannot be converted to v1alpha1 config format %v", node)
 	}
 
+	// the v1alpha1 patches are taken from the node only, see above
 	out.Image = node.Image
 	out.KubeadmConfigPatches = *(*[]string)(unsafe.Pointer(&node.KubeadmConfigPatches))
 	out.KubeadmConfigPatchesJSON6902 = *(*[]kustomize.PatchJSON6902)(unsafe.Pointer(&node.KubeadmConfigPatchesJSON6902))
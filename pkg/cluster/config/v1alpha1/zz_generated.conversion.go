@@ -21,9 +21,12 @@ limitations under the License.
 package v1alpha1
 
 import (
+	unsafe "unsafe"
+
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	config "sigs.k8s.io/kind/pkg/cluster/config"
+	kustomize "sigs.k8s.io/kind/pkg/kustomize"
 )
 
 func init() {
@@ -58,13 +61,44 @@ func RegisterConversions(s *runtime.Scheme) error {
 
 func autoConvert_v1alpha1_Config_To_config_Config(in *Config, out *config.Config, s conversion.Scope) error {
 	// WARNING: in.Image requires manual conversion: does not exist in peer-type
-	// WARNING: in.KubeadmConfigPatches requires manual conversion: does not exist in peer-type
-	// WARNING: in.KubeadmConfigPatchesJSON6902 requires manual conversion: does not exist in peer-type
+	out.KubeadmConfigPatches = *(*[]string)(unsafe.Pointer(&in.KubeadmConfigPatches))
+	out.KubeadmConfigPatchesJSON6902 = *(*[]kustomize.PatchJSON6902)(unsafe.Pointer(&in.KubeadmConfigPatchesJSON6902))
 	// WARNING: in.ControlPlane requires manual conversion: does not exist in peer-type
 	return nil
 }
 
 func autoConvert_config_Config_To_v1alpha1_Config(in *config.Config, out *Config, s conversion.Scope) error {
+	// WARNING: in.Name requires manual conversion: does not exist in peer-type
+	// WARNING: in.Provider requires manual conversion: does not exist in peer-type
+	// WARNING: in.Arch requires manual conversion: does not exist in peer-type
 	// WARNING: in.Nodes requires manual conversion: does not exist in peer-type
+	// WARNING: in.LoadBalancer requires manual conversion: does not exist in peer-type
+	// WARNING: in.Etcd requires manual conversion: does not exist in peer-type
+	// WARNING: in.Networking requires manual conversion: does not exist in peer-type
+	// WARNING: in.Proxy requires manual conversion: does not exist in peer-type
+	// WARNING: in.Ingress requires manual conversion: does not exist in peer-type
+	// WARNING: in.MetricsServer requires manual conversion: does not exist in peer-type
+	// WARNING: in.Dashboard requires manual conversion: does not exist in peer-type
+	// WARNING: in.Storage requires manual conversion: does not exist in peer-type
+	// WARNING: in.VolumeSnapshots requires manual conversion: does not exist in peer-type
+	// WARNING: in.Registry requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageRegistries requires manual conversion: does not exist in peer-type
+	// WARNING: in.DockerDaemonConfigPatches requires manual conversion: does not exist in peer-type
+	// WARNING: in.PreloadImages requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageStore requires manual conversion: does not exist in peer-type
+	// WARNING: in.Tmpfs requires manual conversion: does not exist in peer-type
+	out.KubeadmConfigPatches = *(*[]string)(unsafe.Pointer(&in.KubeadmConfigPatches))
+	out.KubeadmConfigPatchesJSON6902 = *(*[]kustomize.PatchJSON6902)(unsafe.Pointer(&in.KubeadmConfigPatchesJSON6902))
+	// WARNING: in.FeatureGates requires manual conversion: does not exist in peer-type
+	// WARNING: in.RuntimeConfig requires manual conversion: does not exist in peer-type
+	// WARNING: in.EnableAdmissionPlugins requires manual conversion: does not exist in peer-type
+	// WARNING: in.DisableAdmissionPlugins requires manual conversion: does not exist in peer-type
+	// WARNING: in.Audit requires manual conversion: does not exist in peer-type
+	// WARNING: in.Encryption requires manual conversion: does not exist in peer-type
+	// WARNING: in.Certificates requires manual conversion: does not exist in peer-type
+	// WARNING: in.OIDC requires manual conversion: does not exist in peer-type
+	// WARNING: in.Timeouts requires manual conversion: does not exist in peer-type
+	// WARNING: in.ReadinessChecks requires manual conversion: does not exist in peer-type
+	// WARNING: in.Addons requires manual conversion: does not exist in peer-type
 	return nil
 }
@@ -0,0 +1,34 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	conversion "k8s.io/apimachinery/pkg/conversion"
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+// convertion from internal config to v1alpha2 Config is used only by the fuzzer roundtrip test;
+// the settings introduced by v1alpha3 do not exist in v1alpha2, so the fuzzer is configured
+// in order to leave them unset
+
+func Convert_config_Config_To_v1alpha2_Config(in *config.Config, out *Config, s conversion.Scope) error {
+	return autoConvert_config_Config_To_v1alpha2_Config(in, out, s)
+}
+
+func Convert_config_Node_To_v1alpha2_Node(in *config.Node, out *Node, s conversion.Scope) error {
+	return autoConvert_config_Node_To_v1alpha2_Node(in, out, s)
+}
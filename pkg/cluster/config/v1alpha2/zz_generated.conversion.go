@@ -56,11 +56,31 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*config.Config)(nil), (*Config)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_Config_To_v1alpha2_Config(a.(*config.Config), b.(*Config), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*config.Node)(nil), (*Node)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_Node_To_v1alpha2_Node(a.(*config.Node), b.(*Node), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
 func autoConvert_v1alpha2_Config_To_config_Config(in *Config, out *config.Config, s conversion.Scope) error {
-	out.Nodes = *(*[]config.Node)(unsafe.Pointer(&in.Nodes))
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]config.Node, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_Node_To_config_Node(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Nodes = nil
+	}
 	return nil
 }
 
@@ -70,15 +90,51 @@ func Convert_v1alpha2_Config_To_config_Config(in *Config, out *config.Config, s
 }
 
 func autoConvert_config_Config_To_v1alpha2_Config(in *config.Config, out *Config, s conversion.Scope) error {
-	out.Nodes = *(*[]Node)(unsafe.Pointer(&in.Nodes))
+	// WARNING: in.Name requires manual conversion: does not exist in peer-type
+	// WARNING: in.Provider requires manual conversion: does not exist in peer-type
+	// WARNING: in.Arch requires manual conversion: does not exist in peer-type
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]Node, len(*in))
+		for i := range *in {
+			if err := Convert_config_Node_To_v1alpha2_Node(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Nodes = nil
+	}
+	// WARNING: in.LoadBalancer requires manual conversion: does not exist in peer-type
+	// WARNING: in.Etcd requires manual conversion: does not exist in peer-type
+	// WARNING: in.Networking requires manual conversion: does not exist in peer-type
+	// WARNING: in.Proxy requires manual conversion: does not exist in peer-type
+	// WARNING: in.Ingress requires manual conversion: does not exist in peer-type
+	// WARNING: in.MetricsServer requires manual conversion: does not exist in peer-type
+	// WARNING: in.Dashboard requires manual conversion: does not exist in peer-type
+	// WARNING: in.Storage requires manual conversion: does not exist in peer-type
+	// WARNING: in.VolumeSnapshots requires manual conversion: does not exist in peer-type
+	// WARNING: in.Registry requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageRegistries requires manual conversion: does not exist in peer-type
+	// WARNING: in.DockerDaemonConfigPatches requires manual conversion: does not exist in peer-type
+	// WARNING: in.PreloadImages requires manual conversion: does not exist in peer-type
+	// WARNING: in.ImageStore requires manual conversion: does not exist in peer-type
+	// WARNING: in.Tmpfs requires manual conversion: does not exist in peer-type
+	// WARNING: in.KubeadmConfigPatches requires manual conversion: does not exist in peer-type
+	// WARNING: in.KubeadmConfigPatchesJSON6902 requires manual conversion: does not exist in peer-type
+	// WARNING: in.FeatureGates requires manual conversion: does not exist in peer-type
+	// WARNING: in.RuntimeConfig requires manual conversion: does not exist in peer-type
+	// WARNING: in.EnableAdmissionPlugins requires manual conversion: does not exist in peer-type
+	// WARNING: in.DisableAdmissionPlugins requires manual conversion: does not exist in peer-type
+	// WARNING: in.Audit requires manual conversion: does not exist in peer-type
+	// WARNING: in.Encryption requires manual conversion: does not exist in peer-type
+	// WARNING: in.Certificates requires manual conversion: does not exist in peer-type
+	// WARNING: in.OIDC requires manual conversion: does not exist in peer-type
+	// WARNING: in.Timeouts requires manual conversion: does not exist in peer-type
+	// WARNING: in.ReadinessChecks requires manual conversion: does not exist in peer-type
+	// WARNING: in.Addons requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_config_Config_To_v1alpha2_Config is an autogenerated conversion function.
-func Convert_config_Config_To_v1alpha2_Config(in *config.Config, out *Config, s conversion.Scope) error {
-	return autoConvert_config_Config_To_v1alpha2_Config(in, out, s)
-}
-
 func autoConvert_v1alpha2_Node_To_config_Node(in *Node, out *config.Node, s conversion.Scope) error {
 	out.Replicas = (*int32)(unsafe.Pointer(in.Replicas))
 	out.Role = config.NodeRole(in.Role)
@@ -99,10 +155,19 @@ func autoConvert_config_Node_To_v1alpha2_Node(in *config.Node, out *Node, s conv
 	out.Image = in.Image
 	out.KubeadmConfigPatches = *(*[]string)(unsafe.Pointer(&in.KubeadmConfigPatches))
 	out.KubeadmConfigPatchesJSON6902 = *(*[]kustomize.PatchJSON6902)(unsafe.Pointer(&in.KubeadmConfigPatchesJSON6902))
+	// WARNING: in.KubeletExtraArgs requires manual conversion: does not exist in peer-type
+	// WARNING: in.KubeletConfigPatches requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExtraMounts requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExtraPortMappings requires manual conversion: does not exist in peer-type
+	// WARNING: in.Labels requires manual conversion: does not exist in peer-type
+	// WARNING: in.Taints requires manual conversion: does not exist in peer-type
+	// WARNING: in.IngressReady requires manual conversion: does not exist in peer-type
+	// WARNING: in.IPAddress requires manual conversion: does not exist in peer-type
+	// WARNING: in.IPv6Address requires manual conversion: does not exist in peer-type
+	// WARNING: in.OS requires manual conversion: does not exist in peer-type
+	// WARNING: in.Resources requires manual conversion: does not exist in peer-type
+	// WARNING: in.Sysctls requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExtraDevices requires manual conversion: does not exist in peer-type
+	// WARNING: in.CPUManager requires manual conversion: does not exist in peer-type
 	return nil
 }
-
-// Convert_config_Node_To_v1alpha2_Node is an autogenerated conversion function.
-func Convert_config_Node_To_v1alpha2_Node(in *config.Node, out *Node, s conversion.Scope) error {
-	return autoConvert_config_Node_To_v1alpha2_Node(in, out, s)
-}
@@ -18,32 +18,600 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
 	"sigs.k8s.io/kind/pkg/util"
 )
 
-// Validate returns a ConfigErrors with an entry for each problem
-// with the config, or nil if there are none
+// ingressReadyRole is a pseudo role used for counting the ingress-ready
+// replicas along with the replicas of each role
+const ingressReadyRole NodeRole = "ingress-ready"
+
+// IngressPorts are the ports of the ingress-ready node published on the host
+var IngressPorts = []int32{80, 443}
+
+// DefaultRegistryHostPort is the host port of the local registry, unless set
+const DefaultRegistryHostPort int32 = 5000
+
+// reservedContainerPaths are the paths in the node containers that are
+// mounted by kind itself, and thus can not be used by extraMounts
+var reservedContainerPaths = []string{
+	"/lib/modules",
+	"/run",
+	"/tmp",
+	"/var/lib/kind-data",
+}
+
+// Validate returns a util.Errors with an entry for each problem
+// with the config, or nil if there are none.
+// Each entry references the offending field with a JSON path like
+// nodes[1].extraPortMappings[0].hostPort
 func (c *Config) Validate() error {
-	errs := []error{}
+	return toErrors(c.validate())
+}
+
+// Validate returns a util.Errors with an entry for each problem
+// with the Node, or nil if there are none
+func (n *Node) Validate() error {
+	return toErrors(n.validate(nil))
+}
+
+func (c *Config) validate() field.ErrorList {
+	errs := field.ErrorList{}
+
+	// the load balancer type should be one of the expected values, if set
+	switch c.LoadBalancer.Type {
+	case "",
+		HAProxyLoadBalancer,
+		NginxLoadBalancer,
+		EnvoyLoadBalancer:
+	default:
+		errs = append(errs, field.NotSupported(
+			field.NewPath("loadBalancer", "type"), c.LoadBalancer.Type,
+			[]string{string(HAProxyLoadBalancer), string(NginxLoadBalancer), string(EnvoyLoadBalancer)},
+		))
+	}
+
+	// the provider should be one of the expected values, if set
+	switch c.Provider {
+	case "", DockerProvider, PodmanProvider, NerdctlProvider:
+	default:
+		errs = append(errs, field.NotSupported(
+			field.NewPath("provider"), c.Provider,
+			[]string{string(DockerProvider), string(PodmanProvider), string(NerdctlProvider)},
+		))
+	}
+
+	// the architecture should be one of the expected values, if set
+	switch c.Arch {
+	case "", "amd64", "arm64", "ppc64le", "s390x":
+	default:
+		errs = append(errs, field.NotSupported(
+			field.NewPath("arch"), c.Arch,
+			[]string{"amd64", "arm64", "ppc64le", "s390x"},
+		))
+	}
+
+	// count the replicas for each role, and the ingress-ready replicas
+	replicas := map[NodeRole]int{}
+	for _, n := range c.Nodes {
+		count := 1
+		if n.Replicas != nil {
+			count = int(*n.Replicas)
+		}
+		replicas[n.Role] += count
+		if n.IngressReady {
+			replicas[ingressReadyRole] += count
+		}
+	}
+
+	// there should be at least one control plane, and a load balancer
+	// in front of them if there is more than one
+	nodesPath := field.NewPath("nodes")
+	if replicas[ControlPlaneRole] == 0 {
+		errs = append(errs, field.Required(nodesPath, fmt.Sprintf("at least one node with role %q is required", ControlPlaneRole)))
+	}
+	if replicas[ControlPlaneRole] > 1 && replicas[ExternalLoadBalancerRole] == 0 {
+		errs = append(errs, field.Required(nodesPath, fmt.Sprintf("a node with role %q is required when there is more than one node with role %q", ExternalLoadBalancerRole, ControlPlaneRole)))
+	}
+	if replicas[ExternalLoadBalancerRole] > 1 {
+		errs = append(errs, field.Invalid(nodesPath, replicas[ExternalLoadBalancerRole], fmt.Sprintf("at most one node with role %q is allowed", ExternalLoadBalancerRole)))
+	}
+
+	// the etcd topology should be one of the expected values, if set,
+	// and match the external-etcd nodes
+	topologyPath := field.NewPath("etcd", "topology")
+	switch c.Etcd.Topology {
+	case "":
+	case StackedEtcdTopology:
+		if replicas[ExternalEtcdRole] > 0 {
+			errs = append(errs, field.Invalid(topologyPath, c.Etcd.Topology, fmt.Sprintf("nodes with role %q are not allowed", ExternalEtcdRole)))
+		}
+	case ExternalEtcdTopology:
+		if replicas[ExternalEtcdRole] == 0 {
+			errs = append(errs, field.Invalid(topologyPath, c.Etcd.Topology, fmt.Sprintf("at least one node with role %q is required", ExternalEtcdRole)))
+		}
+	default:
+		errs = append(errs, field.NotSupported(
+			topologyPath, c.Etcd.Topology,
+			[]string{string(StackedEtcdTopology), string(ExternalEtcdTopology)},
+		))
+	}
+
+	errs = append(errs, c.Networking.validate(field.NewPath("networking"))...)
+	errs = append(errs, c.Proxy.validate(field.NewPath("proxy"))...)
+	errs = append(errs, c.Registry.validate(field.NewPath("registry"))...)
+	registryHosts := map[string]bool{}
+	for i := range c.ImageRegistries {
+		fldPath := field.NewPath("imageRegistries").Index(i)
+		errs = append(errs, c.ImageRegistries[i].validate(fldPath)...)
+		if registryHosts[c.ImageRegistries[i].Host] {
+			errs = append(errs, field.Duplicate(fldPath.Child("host"), c.ImageRegistries[i].Host))
+		}
+		registryHosts[c.ImageRegistries[i].Host] = true
+	}
+	preloadImages := map[string]bool{}
+	for i, image := range c.PreloadImages {
+		fldPath := field.NewPath("preloadImages").Index(i)
+		if image == "" || strings.ContainsAny(image, ", \t\n") {
+			errs = append(errs, field.Invalid(fldPath, image, "must be an image reference"))
+		}
+		if preloadImages[image] {
+			errs = append(errs, field.Duplicate(fldPath, image))
+		}
+		preloadImages[image] = true
+	}
+	if volume := c.ImageStore.Volume; volume != "" && !volumeNameRE.MatchString(volume) {
+		errs = append(errs, field.Invalid(field.NewPath("imageStore", "volume"), volume, "must be a docker volume name, matching "+volumeNameRE.String()))
+	}
+	for i, patch := range c.DockerDaemonConfigPatches {
+		if err := yaml.Unmarshal([]byte(patch), &map[string]interface{}{}); err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("dockerDaemonConfigPatches").Index(i), patch, "must be a yaml or json object"))
+		}
+	}
+
+	// the ingress controller runs on the ingress-ready node, whose ports 80
+	// and 443 are published on the host, so there can be only one
+	controllerPath := field.NewPath("ingress", "controller")
+	switch c.Ingress.Controller {
+	case "":
+	case NginxIngressController:
+		if replicas[ingressReadyRole] == 0 {
+			errs = append(errs, field.Invalid(controllerPath, c.Ingress.Controller, "requires a node with ingressReady set"))
+		}
+	default:
+		errs = append(errs, field.NotSupported(controllerPath, c.Ingress.Controller, []string{string(NginxIngressController)}))
+	}
+	if replicas[ingressReadyRole] > 1 {
+		errs = append(errs, field.Invalid(nodesPath, replicas[ingressReadyRole], "at most one node with ingressReady set is allowed"))
+	}
+
+	// the feature gates and runtime config are passed as flags
+	for _, name := range sortedKeys(c.FeatureGates) {
+		if !isValidFlagKey(name) {
+			errs = append(errs, field.Invalid(field.NewPath("featureGates").Key(name), name, "must be a feature gate name"))
+		}
+	}
+	for _, key := range sortedKeys(c.RuntimeConfig) {
+		fldPath := field.NewPath("runtimeConfig").Key(key)
+		if !isValidFlagKey(key) {
+			errs = append(errs, field.Invalid(fldPath, key, "must be an API group/version or resource"))
+		}
+		if value := c.RuntimeConfig[key]; strings.ContainsAny(value, ",= \t\n\"") {
+			errs = append(errs, field.Invalid(fldPath, value, "must not contain commas, equal signs, quotes or whitespace"))
+		}
+	}
+
+	// an admission plugin is either enabled or disabled
+	enabledPlugins := map[string]bool{}
+	for i, plugin := range c.EnableAdmissionPlugins {
+		if !isValidFlagKey(plugin) {
+			errs = append(errs, field.Invalid(field.NewPath("enableAdmissionPlugins").Index(i), plugin, "must be an admission plugin name"))
+		}
+		enabledPlugins[plugin] = true
+	}
+	for i, plugin := range c.DisableAdmissionPlugins {
+		fldPath := field.NewPath("disableAdmissionPlugins").Index(i)
+		if !isValidFlagKey(plugin) {
+			errs = append(errs, field.Invalid(fldPath, plugin, "must be an admission plugin name"))
+		}
+		if enabledPlugins[plugin] {
+			errs = append(errs, field.Invalid(fldPath, plugin, "must not also be in enableAdmissionPlugins"))
+		}
+	}
+
+	errs = append(errs, c.Audit.validate(field.NewPath("audit"))...)
+	errs = append(errs, c.Encryption.validate(field.NewPath("encryption"))...)
+	errs = append(errs, c.Certificates.validate(field.NewPath("certificates"))...)
+	errs = append(errs, c.OIDC.validate(field.NewPath("oidc"))...)
+	errs = append(errs, c.Storage.validate(field.NewPath("storage"))...)
+	errs = append(errs, c.Tmpfs.validate(field.NewPath("tmpfs"))...)
+	if c.Tmpfs.ContainerRuntime && c.ImageStore.Volume != "" {
+		errs = append(errs, field.Forbidden(field.NewPath("tmpfs", "containerRuntime"), "the image store volume also backs the container runtime data directory"))
+	}
+	errs = append(errs, c.Timeouts.validate(field.NewPath("timeouts"))...)
+	for i := range c.ReadinessChecks {
+		errs = append(errs, c.ReadinessChecks[i].validate(field.NewPath("readinessChecks").Index(i))...)
+	}
+	addonNames := map[string]bool{}
+	for i := range c.Addons {
+		addonPath := field.NewPath("addons").Index(i)
+		errs = append(errs, c.Addons[i].validate(addonPath)...)
+		if addonNames[c.Addons[i].Name] {
+			errs = append(errs, field.Duplicate(addonPath.Child("name"), c.Addons[i].Name))
+		}
+		addonNames[c.Addons[i].Name] = true
+	}
 
 	// All nodes in the config should be valid
+	for i := range c.Nodes {
+		errs = append(errs, c.Nodes[i].validate(nodesPath.Index(i))...)
+		// Windows containers can only be run by docker
+		if c.Nodes[i].IsWindows() && c.Provider != "" && c.Provider != DockerProvider {
+			errs = append(errs, field.Forbidden(nodesPath.Index(i).Child("os"), fmt.Sprintf("not supported with provider %q", c.Provider)))
+		}
+		// podman does not support the --gpus flag
+		if c.Nodes[i].Resources.GPUs != "" && c.Provider == PodmanProvider {
+			errs = append(errs, field.Forbidden(nodesPath.Index(i).Child("resources", "gpus"), fmt.Sprintf("not supported with provider %q", c.Provider)))
+		}
+	}
+
+	errs = append(errs, c.validateNodeAddresses(nodesPath)...)
+
+	// host ports can be mapped only once, including the API server port
+	hostPorts := map[string]bool{}
+	if c.Networking.APIServerPort != 0 {
+		hostPorts[fmt.Sprintf("%s/%d/%s", c.Networking.APIServerAddress, c.Networking.APIServerPort, PortMappingProtocolTCP)] = true
+	}
+	if c.Registry.Enabled {
+		hostPort := c.Registry.HostPort
+		if hostPort == 0 {
+			hostPort = DefaultRegistryHostPort
+		}
+		hostPorts[fmt.Sprintf("127.0.0.1/%d/%s", hostPort, PortMappingProtocolTCP)] = true
+	}
+	for _, n := range c.Nodes {
+		if n.IngressReady {
+			for _, port := range IngressPorts {
+				hostPorts[fmt.Sprintf("/%d/%s", port, PortMappingProtocolTCP)] = true
+			}
+		}
+	}
 	for i, n := range c.Nodes {
-		if err := n.Validate(); err != nil {
-			errs = append(errs, fmt.Errorf("please fix invalid configuration for node %d: \n%v", i, err))
+		for j, pm := range n.ExtraPortMappings {
+			if pm.HostPort == 0 {
+				continue
+			}
+			protocol := pm.Protocol
+			if protocol == "" {
+				protocol = PortMappingProtocolTCP
+			}
+			hostPort := fmt.Sprintf("%s/%d/%s", pm.ListenAddress, pm.HostPort, protocol)
+			if hostPorts[hostPort] {
+				errs = append(errs, field.Duplicate(nodesPath.Index(i).Child("extraPortMappings").Index(j).Child("hostPort"), pm.HostPort))
+			}
+			hostPorts[hostPort] = true
 		}
 	}
 
-	if len(errs) > 0 {
-		return util.NewErrors(errs)
+	return errs
+}
+
+func (n *Networking) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if n.APIServerAddress != "" && net.ParseIP(n.APIServerAddress) == nil {
+		errs = append(errs, field.Invalid(fldPath.Child("apiServerAddress"), n.APIServerAddress, "must be an IP address"))
 	}
-	return nil
+	if n.APIServerPort < 0 || n.APIServerPort > 65535 {
+		errs = append(errs, field.Invalid(fldPath.Child("apiServerPort"), n.APIServerPort, "must be between 0 and 65535"))
+	}
+	for i, san := range n.APIServerCertSANs {
+		if net.ParseIP(san) != nil {
+			continue
+		}
+		// wildcard SANs match any single label
+		if msgs := validation.IsDNS1123Subdomain(strings.TrimPrefix(san, "*.")); len(msgs) > 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("apiServerCertSANs").Index(i), san, "must be an IP address or a DNS name"))
+		}
+	}
+	// IPv6 requires an MTU of at least 1280
+	minMTU := int32(576)
+	if n.IPFamily == IPv6Family || n.IPFamily == DualStackFamily {
+		minMTU = 1280
+	}
+	if n.MTU != 0 && (n.MTU < minMTU || n.MTU > 65535) {
+		errs = append(errs, field.Invalid(fldPath.Child("mtu"), n.MTU, fmt.Sprintf("must be between %d and 65535", minMTU)))
+	}
+	switch n.IPFamily {
+	case "", IPv4Family, IPv6Family, DualStackFamily:
+	default:
+		errs = append(errs, field.NotSupported(
+			fldPath.Child("ipFamily"), n.IPFamily,
+			[]string{string(IPv4Family), string(IPv6Family), string(DualStackFamily)},
+		))
+	}
+	switch n.KubeProxyMode {
+	case "", IPTablesProxyMode, IPVSProxyMode, NFTablesProxyMode, NoneProxyMode:
+	default:
+		errs = append(errs, field.NotSupported(
+			fldPath.Child("kubeProxyMode"), n.KubeProxyMode,
+			[]string{string(IPTablesProxyMode), string(IPVSProxyMode), string(NFTablesProxyMode), string(NoneProxyMode)},
+		))
+	}
+	podSubnets, podErrs := n.validateSubnets(fldPath.Child("podSubnet"), n.PodSubnet)
+	errs = append(errs, podErrs...)
+	serviceSubnets, serviceErrs := n.validateSubnets(fldPath.Child("serviceSubnet"), n.ServiceSubnet)
+	errs = append(errs, serviceErrs...)
+	for _, podSubnet := range podSubnets {
+		for _, serviceSubnet := range serviceSubnets {
+			if overlaps(podSubnet, serviceSubnet) {
+				errs = append(errs, field.Invalid(fldPath.Child("serviceSubnet"), n.ServiceSubnet, "must not overlap with podSubnet"))
+			}
+		}
+	}
+	errs = append(errs, n.DockerNetwork.validate(fldPath.Child("dockerNetwork"), n.IPFamily, append(podSubnets, serviceSubnets...))...)
+	errs = append(errs, n.DNS.validate(fldPath.Child("dns"))...)
+	return errs
 }
 
-// Validate returns a ConfigErrors with an entry for each problem
-// with the Node, or nil if there are none
-func (n *Node) Validate() error {
-	errs := []error{}
+// dockerNetworkNameRE matches valid docker network names
+var dockerNetworkNameRE = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// volumeNameRE matches valid docker volume names
+var volumeNameRE = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// validate validates the docker network settings, the network subnets must
+// not overlap with the cluster subnets
+func (d *DockerNetwork) validate(fldPath *field.Path, ipFamily IPFamily, clusterSubnets []*net.IPNet) field.ErrorList {
+	errs := field.ErrorList{}
+	switch {
+	case d.Name == "":
+	case d.Name == "host" || d.Name == "none":
+		errs = append(errs, field.Invalid(fldPath.Child("name"), d.Name, "nodes can not be attached to the host or none networks"))
+	case !dockerNetworkNameRE.MatchString(d.Name):
+		errs = append(errs, field.Invalid(fldPath.Child("name"), d.Name, fmt.Sprintf("must match %s", dockerNetworkNameRE.String())))
+	}
+
+	validateSubnet := func(name, value string, ipv6 bool) *net.IPNet {
+		if value == "" {
+			return nil
+		}
+		_, ipNet, err := net.ParseCIDR(value)
+		if err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child(name), value, "must be a CIDR range"))
+			return nil
+		}
+		if (ipNet.IP.To4() == nil) != ipv6 {
+			family := "IPv4"
+			if ipv6 {
+				family = "IPv6"
+			}
+			errs = append(errs, field.Invalid(fldPath.Child(name), value, fmt.Sprintf("must be an %s CIDR range", family)))
+			return nil
+		}
+		for _, clusterSubnet := range clusterSubnets {
+			if overlaps(ipNet, clusterSubnet) {
+				errs = append(errs, field.Invalid(fldPath.Child(name), value, "must not overlap with podSubnet or serviceSubnet"))
+				break
+			}
+		}
+		return ipNet
+	}
+	subnet := validateSubnet("subnet", d.Subnet, false)
+	if d.IPv6Subnet != "" && ipFamily != IPv6Family && ipFamily != DualStackFamily {
+		errs = append(errs, field.Invalid(fldPath.Child("ipv6Subnet"), d.IPv6Subnet, "only allowed if ipFamily is ipv6 or dual"))
+	} else {
+		validateSubnet("ipv6Subnet", d.IPv6Subnet, true)
+	}
+
+	if d.Gateway != "" {
+		gateway := net.ParseIP(d.Gateway)
+		switch {
+		case d.Subnet == "":
+			errs = append(errs, field.Invalid(fldPath.Child("gateway"), d.Gateway, "requires subnet"))
+		case gateway == nil:
+			errs = append(errs, field.Invalid(fldPath.Child("gateway"), d.Gateway, "must be an IP address"))
+		case subnet != nil && !subnet.Contains(gateway):
+			errs = append(errs, field.Invalid(fldPath.Child("gateway"), d.Gateway, "must be in subnet"))
+		}
+	}
+	return errs
+}
+
+// validateNodeAddresses validates the static node addresses against the
+// docker network, docker only supports static addresses in networks with
+// a configured subnet
+func (c *Config) validateNodeAddresses(nodesPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	network := &c.Networking.DockerNetwork
+	_, subnet, _ := net.ParseCIDR(network.Subnet)
+	_, ipv6Subnet, _ := net.ParseCIDR(network.IPv6Subnet)
+	addresses := map[string]bool{}
+	validateAddress := func(fldPath *field.Path, value string, subnet *net.IPNet, subnetName string) {
+		ip := net.ParseIP(value)
+		if ip == nil || (ip.To4() == nil) != (subnetName == "ipv6Subnet") {
+			// invalid addresses are reported by Node.validate
+			return
+		}
+		switch {
+		case subnet == nil:
+			errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("requires networking.dockerNetwork.%s", subnetName)))
+		case !subnet.Contains(ip):
+			errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("must be in networking.dockerNetwork.%s", subnetName)))
+		case ip.Equal(net.ParseIP(network.Gateway)):
+			errs = append(errs, field.Invalid(fldPath, value, "must not be the networking.dockerNetwork.gateway"))
+		}
+		if addresses[ip.String()] {
+			errs = append(errs, field.Duplicate(fldPath, value))
+		}
+		addresses[ip.String()] = true
+	}
+	for i := range c.Nodes {
+		n := &c.Nodes[i]
+		if n.IPAddress != "" {
+			validateAddress(nodesPath.Index(i).Child("ipAddress"), n.IPAddress, subnet, "subnet")
+		}
+		if n.IPv6Address == "" {
+			continue
+		}
+		if c.Networking.IPFamily != IPv6Family && c.Networking.IPFamily != DualStackFamily {
+			errs = append(errs, field.Invalid(nodesPath.Index(i).Child("ipv6Address"), n.IPv6Address, "only allowed if networking.ipFamily is ipv6 or dual"))
+			continue
+		}
+		validateAddress(nodesPath.Index(i).Child("ipv6Address"), n.IPv6Address, ipv6Subnet, "ipv6Subnet")
+	}
+	return errs
+}
+
+func (d *DNS) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	for i, nameserver := range d.Nameservers {
+		if net.ParseIP(nameserver) == nil {
+			errs = append(errs, field.Invalid(fldPath.Child("nameservers").Index(i), nameserver, "must be an IP address"))
+		}
+	}
+	for i, search := range d.Search {
+		if msgs := validation.IsDNS1123Subdomain(strings.TrimSuffix(search, ".")); len(msgs) > 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("search").Index(i), search, strings.Join(msgs, "; ")))
+		}
+	}
+	for i, option := range d.Options {
+		if option == "" || strings.ContainsAny(option, " \t\n\"") {
+			errs = append(errs, field.Invalid(fldPath.Child("options").Index(i), option, "must be a resolver option, e.g. ndots:2"))
+		}
+	}
+	return errs
+}
+
+// overlaps returns true if the subnets overlap
+func overlaps(a, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// validateSubnets validates a subnet of the cluster IP family, or a comma
+// separated IPv4 and IPv6 subnet for dual-stack clusters, returning the
+// valid subnets
+func (n *Networking) validateSubnets(fldPath *field.Path, value string) ([]*net.IPNet, field.ErrorList) {
+	if value == "" {
+		return nil, nil
+	}
+	cidrs := strings.Split(value, ",")
+	if len(cidrs) > 1 && n.IPFamily != DualStackFamily {
+		return nil, field.ErrorList{field.Invalid(fldPath, value, "must be a single CIDR range unless ipFamily is dual")}
+	}
+	errs := field.ErrorList{}
+	subnets := []*net.IPNet{}
+	families := map[bool]bool{}
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			errs = append(errs, field.Invalid(fldPath, value, "must be a CIDR range"))
+			continue
+		}
+		ipv6 := ipNet.IP.To4() == nil
+		switch {
+		case families[ipv6]:
+			errs = append(errs, field.Invalid(fldPath, value, "must be at most one IPv4 and one IPv6 CIDR range"))
+		case ipv6 && (n.IPFamily == "" || n.IPFamily == IPv4Family):
+			errs = append(errs, field.Invalid(fldPath, value, "must be an IPv4 CIDR range unless ipFamily is ipv6 or dual"))
+		case !ipv6 && n.IPFamily == IPv6Family:
+			errs = append(errs, field.Invalid(fldPath, value, "must be an IPv6 CIDR range for ipFamily ipv6"))
+		}
+		families[ipv6] = true
+		subnets = append(subnets, ipNet)
+	}
+	return subnets, errs
+}
+
+func (p *Proxy) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	for _, proxy := range []struct{ name, value string }{
+		{"httpProxy", p.HTTPProxy},
+		{"httpsProxy", p.HTTPSProxy},
+	} {
+		if proxy.value == "" {
+			continue
+		}
+		if u, err := url.Parse(proxy.value); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, field.Invalid(fldPath.Child(proxy.name), proxy.value, "must be a URL, e.g. http://proxy.example.com:3128"))
+		}
+	}
+	if strings.ContainsAny(p.NoProxy, " \t\n\"") {
+		errs = append(errs, field.Invalid(fldPath.Child("noProxy"), p.NoProxy, "must not contain quotes or whitespace"))
+	}
+	return errs
+}
+
+func (r *Registry) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if r.HostPort < 0 || r.HostPort > 65535 {
+		errs = append(errs, field.Invalid(fldPath.Child("hostPort"), r.HostPort, "must be between 0 and 65535"))
+	}
+	if r.HostPort != 0 && !r.Enabled {
+		errs = append(errs, field.Invalid(fldPath.Child("hostPort"), r.HostPort, "requires the registry to be enabled"))
+	}
+	return errs
+}
+
+func (r *ImageRegistry) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if !isValidRegistryHost(r.Host) {
+		errs = append(errs, field.Invalid(fldPath.Child("host"), r.Host, "must be a host name or IP address with an optional port, e.g. registry.example.com:5000"))
+	}
+	if len(r.Mirrors) > 0 && r.Host != DockerHubRegistryHost {
+		errs = append(errs, field.Forbidden(fldPath.Child("mirrors"), fmt.Sprintf("the docker daemon of the nodes only supports mirrors of %s", DockerHubRegistryHost)))
+	}
+	for i, mirror := range r.Mirrors {
+		if u, err := url.Parse(mirror); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, field.Invalid(fldPath.Child("mirrors").Index(i), mirror, "must be an http or https URL, e.g. https://mirror.example.com"))
+		}
+	}
+	if r.Username != "" && r.Password == "" {
+		errs = append(errs, field.Required(fldPath.Child("password"), "required with username"))
+	}
+	if r.Password != "" && r.Username == "" {
+		errs = append(errs, field.Required(fldPath.Child("username"), "required with password"))
+	}
+	return errs
+}
+
+// isValidRegistryHost returns true if host is a host name or IP address,
+// with an optional port, as in the registry part of image references
+func isValidRegistryHost(host string) bool {
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		if len(validation.IsValidPortNum(portNumber(port))) != 0 {
+			return false
+		}
+		host = h
+	}
+	return net.ParseIP(host) != nil || len(validation.IsDNS1123Subdomain(host)) == 0
+}
+
+// portNumber returns the port number in port, or 0 if it is not a number
+func portNumber(port string) int {
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func (n *Node) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
 
 	// validate node role should be one of the expected values
 	switch n.Role {
@@ -51,23 +619,810 @@ func (n *Node) Validate() error {
 		WorkerRole,
 		ExternalEtcdRole,
 		ExternalLoadBalancerRole:
+	case FakeRole:
+		errs = append(errs, n.validateFake(fldPath)...)
+	case "":
+		errs = append(errs, field.Required(fldPath.Child("role"), ""))
 	default:
-		errs = append(errs, fmt.Errorf("role is a required field"))
+		errs = append(errs, field.NotSupported(
+			fldPath.Child("role"), n.Role,
+			[]string{string(ControlPlaneRole), string(WorkerRole), string(ExternalEtcdRole), string(ExternalLoadBalancerRole), string(FakeRole)},
+		))
+	}
+
+	// the node OS should be one of the expected values, if set, Windows
+	// nodes can only join the cluster as workers
+	switch n.OS {
+	case "", LinuxOS:
+	case WindowsOS:
+		if !n.IsWorker() {
+			errs = append(errs, field.Forbidden(fldPath.Child("os"), fmt.Sprintf("only supported for nodes with role %q", WorkerRole)))
+		}
+		if n.IngressReady {
+			errs = append(errs, field.Forbidden(fldPath.Child("ingressReady"), fmt.Sprintf("not supported for nodes with os %q", WindowsOS)))
+		}
+		if len(n.ExtraMounts) > 0 {
+			errs = append(errs, field.Forbidden(fldPath.Child("extraMounts"), fmt.Sprintf("not supported for nodes with os %q", WindowsOS)))
+		}
+		if n.Resources != (NodeResources{}) {
+			errs = append(errs, field.Forbidden(fldPath.Child("resources"), fmt.Sprintf("not supported for nodes with os %q", WindowsOS)))
+		}
+		if len(n.Sysctls) > 0 {
+			errs = append(errs, field.Forbidden(fldPath.Child("sysctls"), fmt.Sprintf("not supported for nodes with os %q", WindowsOS)))
+		}
+		if len(n.ExtraDevices) > 0 {
+			errs = append(errs, field.Forbidden(fldPath.Child("extraDevices"), fmt.Sprintf("not supported for nodes with os %q", WindowsOS)))
+		}
+		if n.CPUManager != (CPUManager{}) {
+			errs = append(errs, field.Forbidden(fldPath.Child("cpuManager"), fmt.Sprintf("not supported for nodes with os %q", WindowsOS)))
+		}
+		if len(n.KubeletExtraArgs) > 0 {
+			errs = append(errs, field.Forbidden(fldPath.Child("kubeletExtraArgs"), fmt.Sprintf("not supported for nodes with os %q", WindowsOS)))
+		}
+		if len(n.KubeletConfigPatches) > 0 {
+			errs = append(errs, field.Forbidden(fldPath.Child("kubeletConfigPatches"), fmt.Sprintf("not supported for nodes with os %q", WindowsOS)))
+		}
+	default:
+		errs = append(errs, field.NotSupported(
+			fldPath.Child("os"), n.OS,
+			[]string{string(LinuxOS), string(WindowsOS)},
+		))
 	}
 
 	// image should be defined
 	if n.Image == "" {
-		errs = append(errs, fmt.Errorf("image is a required field"))
+		errs = append(errs, field.Required(fldPath.Child("image"), ""))
 	}
 
 	// replicas >= 0
 	if n.Replicas != nil && int32(*n.Replicas) < 0 {
-		errs = append(errs, fmt.Errorf("replicas number should not be a negative number"))
+		errs = append(errs, field.Invalid(fldPath.Child("replicas"), *n.Replicas, "must not be negative"))
+	}
+
+	// a static address can only be assigned to a single replica
+	for _, address := range []struct {
+		name, value string
+		ipv6        bool
+	}{
+		{"ipAddress", n.IPAddress, false},
+		{"ipv6Address", n.IPv6Address, true},
+	} {
+		if address.value == "" {
+			continue
+		}
+		ip := net.ParseIP(address.value)
+		switch {
+		case ip == nil:
+			errs = append(errs, field.Invalid(fldPath.Child(address.name), address.value, "must be an IP address"))
+		case (ip.To4() == nil) != address.ipv6:
+			family := "IPv4"
+			if address.ipv6 {
+				family = "IPv6"
+			}
+			errs = append(errs, field.Invalid(fldPath.Child(address.name), address.value, fmt.Sprintf("must be an %s address", family)))
+		}
+		if n.Replicas != nil && *n.Replicas > 1 {
+			errs = append(errs, field.Invalid(fldPath.Child(address.name), address.value, "can not be assigned to more than one replica"))
+		}
+	}
+
+	// the load balancer node does not run the node image
+	mountsPath := fldPath.Child("extraMounts")
+	if n.IsExternalLoadBalancer() && len(n.ExtraMounts) > 0 {
+		errs = append(errs, field.Forbidden(mountsPath, fmt.Sprintf("not supported for nodes with role %q", ExternalLoadBalancerRole)))
+	}
+	// each container path can be mounted only once, and not over kind's own mounts
+	containerPaths := map[string]bool{}
+	for i := range n.ExtraMounts {
+		m := &n.ExtraMounts[i]
+		errs = append(errs, m.validate(mountsPath.Index(i))...)
+		containerPath := path.Clean(m.ContainerPath)
+		if containerPaths[containerPath] {
+			errs = append(errs, field.Duplicate(mountsPath.Index(i).Child("containerPath"), m.ContainerPath))
+		}
+		containerPaths[containerPath] = true
+		for _, reserved := range reservedContainerPaths {
+			if containerPath == reserved {
+				errs = append(errs, field.Forbidden(mountsPath.Index(i).Child("containerPath"), fmt.Sprintf("%s is mounted by kind", reserved)))
+			}
+		}
+	}
+
+	// the host ports of the replicas would conflict
+	portsPath := fldPath.Child("extraPortMappings")
+	if n.IsExternalLoadBalancer() && len(n.ExtraPortMappings) > 0 {
+		errs = append(errs, field.Forbidden(portsPath, fmt.Sprintf("not supported for nodes with role %q", ExternalLoadBalancerRole)))
+	}
+	for i := range n.ExtraPortMappings {
+		pm := &n.ExtraPortMappings[i]
+		errs = append(errs, pm.validate(portsPath.Index(i))...)
+		if n.Replicas != nil && *n.Replicas > 1 && pm.HostPort != 0 {
+			errs = append(errs, field.Invalid(portsPath.Index(i).Child("hostPort"), pm.HostPort, "can not be mapped for more than one replica"))
+		}
+		if n.IsControlPlane() && pm.ContainerPort == kubeadm.APIServerPort {
+			errs = append(errs, field.Forbidden(portsPath.Index(i).Child("containerPort"), "the API server port is mapped by kind"))
+		}
+	}
+
+	// labels and taints are applied only to Kubernetes nodes
+	if n.IsExternalEtcd() || n.IsExternalLoadBalancer() {
+		if n.IngressReady {
+			errs = append(errs, field.Forbidden(fldPath.Child("ingressReady"), fmt.Sprintf("not supported for nodes with role %q", n.Role)))
+		}
+		if len(n.Labels) > 0 {
+			errs = append(errs, field.Forbidden(fldPath.Child("labels"), fmt.Sprintf("not supported for nodes with role %q", n.Role)))
+		}
+		if len(n.Taints) > 0 {
+			errs = append(errs, field.Forbidden(fldPath.Child("taints"), fmt.Sprintf("not supported for nodes with role %q", n.Role)))
+		}
+	}
+	for _, key := range sortedKeys(n.Labels) {
+		labelPath := fldPath.Child("labels").Key(key)
+		if msgs := validation.IsQualifiedName(key); len(msgs) > 0 {
+			errs = append(errs, field.Invalid(labelPath, key, strings.Join(msgs, "; ")))
+		}
+		if msgs := validation.IsValidLabelValue(n.Labels[key]); len(msgs) > 0 {
+			errs = append(errs, field.Invalid(labelPath, n.Labels[key], strings.Join(msgs, "; ")))
+		}
+	}
+	for i := range n.Taints {
+		errs = append(errs, n.Taints[i].validate(fldPath.Child("taints").Index(i))...)
+	}
+
+	// resource limits are applied only to Kubernetes nodes
+	resourcesPath := fldPath.Child("resources")
+	if (n.IsExternalEtcd() || n.IsExternalLoadBalancer()) && n.Resources != (NodeResources{}) {
+		errs = append(errs, field.Forbidden(resourcesPath, fmt.Sprintf("not supported for nodes with role %q", n.Role)))
+	}
+	errs = append(errs, n.Resources.validate(resourcesPath)...)
+
+	// the kubelet is configured only in Kubernetes nodes, with flag names
+	// rather than command line arguments
+	argsPath := fldPath.Child("kubeletExtraArgs")
+	if n.IsExternalEtcd() || n.IsExternalLoadBalancer() {
+		if len(n.KubeletExtraArgs) > 0 {
+			errs = append(errs, field.Forbidden(argsPath, fmt.Sprintf("not supported for nodes with role %q", n.Role)))
+		}
+		if len(n.KubeletConfigPatches) > 0 {
+			errs = append(errs, field.Forbidden(fldPath.Child("kubeletConfigPatches"), fmt.Sprintf("not supported for nodes with role %q", n.Role)))
+		}
+	}
+	for _, flag := range sortedKeys(n.KubeletExtraArgs) {
+		if !flagNameRE.MatchString(flag) {
+			errs = append(errs, field.Invalid(argsPath.Key(flag), flag, "must be a flag name without the leading dashes, e.g. max-pods"))
+		}
+	}
+
+	// sysctls are set only in Kubernetes nodes, and only the namespaced
+	// sysctls can be set in a container
+	sysctlsPath := fldPath.Child("sysctls")
+	if (n.IsExternalEtcd() || n.IsExternalLoadBalancer()) && len(n.Sysctls) > 0 {
+		errs = append(errs, field.Forbidden(sysctlsPath, fmt.Sprintf("not supported for nodes with role %q", n.Role)))
+	}
+	for _, key := range sortedKeys(n.Sysctls) {
+		sysctlPath := sysctlsPath.Key(key)
+		switch {
+		case !sysctlNameRE.MatchString(key):
+			errs = append(errs, field.Invalid(sysctlPath, key, "must be a sysctl name, e.g. net.core.somaxconn"))
+		case !isNamespacedSysctl(key):
+			errs = append(errs, field.Forbidden(sysctlPath, "not a namespaced sysctl, this must be set on the host"))
+		}
+		if n.Sysctls[key] == "" {
+			errs = append(errs, field.Required(sysctlPath, ""))
+		}
+	}
+
+	// devices are exposed only in Kubernetes nodes
+	devicesPath := fldPath.Child("extraDevices")
+	if (n.IsExternalEtcd() || n.IsExternalLoadBalancer()) && len(n.ExtraDevices) > 0 {
+		errs = append(errs, field.Forbidden(devicesPath, fmt.Sprintf("not supported for nodes with role %q", n.Role)))
+	}
+	devicePaths := map[string]bool{}
+	for i := range n.ExtraDevices {
+		d := &n.ExtraDevices[i]
+		errs = append(errs, d.validate(devicesPath.Index(i))...)
+		containerPath := d.ContainerPath
+		if containerPath == "" {
+			containerPath = d.HostPath
+		}
+		containerPath = path.Clean(containerPath)
+		if devicePaths[containerPath] {
+			errs = append(errs, field.Duplicate(devicesPath.Index(i).Child("containerPath"), containerPath))
+		}
+		devicePaths[containerPath] = true
+	}
+
+	// the CPU manager is configured only in Kubernetes nodes
+	cpuManagerPath := fldPath.Child("cpuManager")
+	if (n.IsExternalEtcd() || n.IsExternalLoadBalancer()) && n.CPUManager != (CPUManager{}) {
+		errs = append(errs, field.Forbidden(cpuManagerPath, fmt.Sprintf("not supported for nodes with role %q", n.Role)))
+	}
+	errs = append(errs, n.CPUManager.validate(cpuManagerPath)...)
+
+	return errs
+}
+
+// cgroupPermissionsRE matches the device cgroup permissions, a combination
+// of r, w and m
+var cgroupPermissionsRE = regexp.MustCompile(`^(r?w?m?|r?m?w?|w?r?m?|w?m?r?|m?r?w?|m?w?r?)$`)
+
+func (d *Device) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	// the paths are separated by colons in the docker --device flag
+	if !path.IsAbs(d.HostPath) || strings.Contains(d.HostPath, ":") {
+		errs = append(errs, field.Invalid(fldPath.Child("hostPath"), d.HostPath, "must be an absolute device path, e.g. /dev/fuse"))
+	}
+	if d.ContainerPath != "" && (!path.IsAbs(d.ContainerPath) || strings.Contains(d.ContainerPath, ":")) {
+		errs = append(errs, field.Invalid(fldPath.Child("containerPath"), d.ContainerPath, "must be an absolute path"))
+	}
+	if !cgroupPermissionsRE.MatchString(d.CgroupPermissions) {
+		errs = append(errs, field.Invalid(fldPath.Child("cgroupPermissions"), d.CgroupPermissions, "must be a combination of r, w and m, e.g. rwm"))
+	}
+	return errs
+}
+
+// flagNameRE matches command line flag names without the leading dashes
+var flagNameRE = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// sysctlNameRE matches sysctl names in the dotted format, e.g. net.core.somaxconn
+var sysctlNameRE = regexp.MustCompile(`^[a-z0-9]([-_a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-_a-z0-9]*[a-z0-9])?)*$`)
+
+// isNamespacedSysctl returns true if the sysctl name is in a kernel
+// namespace, and thus can be set in a container, as validated by docker
+func isNamespacedSysctl(name string) bool {
+	if name == "kernel.sem" {
+		return true
+	}
+	for _, prefix := range []string{"net.", "kernel.shm", "kernel.msg", "fs.mqueue."} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// gpusRE matches the values of the docker --gpus flag supported by kind:
+// all the GPUs, a number of GPUs, or a list of device indexes or UUIDs
+var gpusRE = regexp.MustCompile(`^(all|[1-9][0-9]*|device=[a-zA-Z0-9-]+(,[a-zA-Z0-9-]+)*)$`)
+
+func (r *NodeResources) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	// the limits should be positive quantities, if set
+	for _, limit := range []struct {
+		name, value string
+	}{
+		{"cpus", r.CPUs},
+		{"memory", r.Memory},
+	} {
+		if limit.value == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(limit.value)
+		if err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child(limit.name), limit.value, "must be a resource quantity, e.g. \"2\" or \"4Gi\""))
+			continue
+		}
+		if q.Sign() <= 0 {
+			errs = append(errs, field.Invalid(fldPath.Child(limit.name), limit.value, "must be positive"))
+		}
+	}
+
+	// pids >= 0
+	if r.PIDs < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("pids"), r.PIDs, "must not be negative"))
+	}
+
+	// gpus should be in the format of the docker --gpus flag, if set
+	if r.GPUs != "" && !gpusRE.MatchString(r.GPUs) {
+		errs = append(errs, field.Invalid(fldPath.Child("gpus"), r.GPUs, "must be \"all\", a number of GPUs or a list of devices, e.g. \"device=0,1\""))
+	}
+
+	// cpuSet and numaNodes should be lists, if set
+	for _, list := range []struct {
+		name, value string
+	}{
+		{"cpuSet", r.CPUSet},
+		{"numaNodes", r.NUMANodes},
+	} {
+		if list.value != "" && !cpuListRE.MatchString(list.value) {
+			errs = append(errs, field.Invalid(fldPath.Child(list.name), list.value, "must be a list of numbers or ranges, e.g. \"0-3,8\""))
+		}
+	}
+
+	return errs
+}
+
+// validateFake validates that the fake node sets only the fields supported by
+// fake nodes, which have no container, the image is ignored
+func (n *Node) validateFake(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if n.OS != "" && n.OS != LinuxOS {
+		errs = append(errs, field.Forbidden(fldPath.Child("os"), fmt.Sprintf("not supported for nodes with role %q", FakeRole)))
+	}
+	if n.Resources.PIDs != 0 || n.Resources.GPUs != "" || n.Resources.CPUSet != "" || n.Resources.NUMANodes != "" {
+		errs = append(errs, field.Forbidden(fldPath.Child("resources"), fmt.Sprintf("only cpus and memory are supported for nodes with role %q", FakeRole)))
+	}
+	unsupported := n.DeepCopy()
+	unsupported.Role = ""
+	unsupported.Replicas = nil
+	unsupported.Image = ""
+	unsupported.OS = ""
+	unsupported.Labels = nil
+	unsupported.Taints = nil
+	unsupported.Resources = NodeResources{}
+	if !reflect.DeepEqual(unsupported, &Node{}) {
+		errs = append(errs, field.Forbidden(fldPath, fmt.Sprintf("only replicas, labels, taints and resources are supported for nodes with role %q", FakeRole)))
+	}
+	return errs
+}
+
+// cpuListRE matches the lists of CPUs or NUMA nodes in the format of the
+// cpuset cgroup, e.g. "0-3,8"
+var cpuListRE = regexp.MustCompile(`^[0-9]+(-[0-9]+)?(,[0-9]+(-[0-9]+)?)*$`)
+
+func (m *CPUManager) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	switch m.Policy {
+	case "", NoneCPUManagerPolicy, StaticCPUManagerPolicy:
+	default:
+		errs = append(errs, field.NotSupported(fldPath.Child("policy"), m.Policy, []string{string(NoneCPUManagerPolicy), string(StaticCPUManagerPolicy)}))
+	}
+
+	// the reserved CPUs are the CPUs the static policy never assigns
+	if m.ReservedCPUs != "" {
+		if m.Policy != StaticCPUManagerPolicy {
+			errs = append(errs, field.Forbidden(fldPath.Child("reservedCPUs"), fmt.Sprintf("only supported with policy %q", StaticCPUManagerPolicy)))
+		} else if !cpuListRE.MatchString(m.ReservedCPUs) {
+			errs = append(errs, field.Invalid(fldPath.Child("reservedCPUs"), m.ReservedCPUs, "must be a list of CPUs or ranges, e.g. \"0,1\""))
+		}
+	}
+
+	switch m.TopologyManagerPolicy {
+	case "", NoneTopologyManagerPolicy, BestEffortTopologyManagerPolicy, RestrictedTopologyManagerPolicy, SingleNUMANodeTopologyManagerPolicy:
+	default:
+		errs = append(errs, field.NotSupported(fldPath.Child("topologyManagerPolicy"), m.TopologyManagerPolicy, []string{
+			string(NoneTopologyManagerPolicy),
+			string(BestEffortTopologyManagerPolicy),
+			string(RestrictedTopologyManagerPolicy),
+			string(SingleNUMANodeTopologyManagerPolicy),
+		}))
+	}
+
+	return errs
+}
+
+func (m *Mount) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if m.HostPath == "" {
+		errs = append(errs, field.Required(fldPath.Child("hostPath"), ""))
+	}
+	if !path.IsAbs(m.ContainerPath) {
+		errs = append(errs, field.Invalid(fldPath.Child("containerPath"), m.ContainerPath, "must be an absolute path"))
+	}
+	switch m.Propagation {
+	case "",
+		MountPropagationNone,
+		MountPropagationHostToContainer,
+		MountPropagationBidirectional:
+	default:
+		errs = append(errs, field.NotSupported(
+			fldPath.Child("propagation"), m.Propagation,
+			[]string{string(MountPropagationNone), string(MountPropagationHostToContainer), string(MountPropagationBidirectional)},
+		))
+	}
+	return errs
+}
+
+func (pm *PortMapping) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if pm.ContainerPort < 1 || pm.ContainerPort > 65535 {
+		errs = append(errs, field.Invalid(fldPath.Child("containerPort"), pm.ContainerPort, "must be between 1 and 65535"))
+	}
+	if pm.HostPort < 0 || pm.HostPort > 65535 {
+		errs = append(errs, field.Invalid(fldPath.Child("hostPort"), pm.HostPort, "must be between 0 and 65535"))
+	}
+	if pm.ListenAddress != "" && net.ParseIP(pm.ListenAddress) == nil {
+		errs = append(errs, field.Invalid(fldPath.Child("listenAddress"), pm.ListenAddress, "must be an IP address"))
+	}
+	switch pm.Protocol {
+	case "",
+		PortMappingProtocolTCP,
+		PortMappingProtocolUDP,
+		PortMappingProtocolSCTP:
+	default:
+		errs = append(errs, field.NotSupported(
+			fldPath.Child("protocol"), pm.Protocol,
+			[]string{string(PortMappingProtocolTCP), string(PortMappingProtocolUDP), string(PortMappingProtocolSCTP)},
+		))
+	}
+	return errs
+}
+
+func (t *Taint) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if msgs := validation.IsQualifiedName(t.Key); len(msgs) > 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("key"), t.Key, strings.Join(msgs, "; ")))
+	}
+	if msgs := validation.IsValidLabelValue(t.Value); len(msgs) > 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("value"), t.Value, strings.Join(msgs, "; ")))
+	}
+	switch t.Effect {
+	case TaintEffectNoSchedule,
+		TaintEffectPreferNoSchedule,
+		TaintEffectNoExecute:
+	default:
+		errs = append(errs, field.NotSupported(
+			fldPath.Child("effect"), t.Effect,
+			[]string{string(TaintEffectNoSchedule), string(TaintEffectPreferNoSchedule), string(TaintEffectNoExecute)},
+		))
+	}
+	return errs
+}
+
+// toErrors converts a field.ErrorList to util.Errors, returning nil if
+// the list is empty
+func toErrors(list field.ErrorList) error {
+	if len(list) == 0 {
+		return nil
+	}
+	errs := make([]error, 0, len(list))
+	for _, err := range list {
+		errs = append(errs, err)
+	}
+	return util.NewErrors(errs)
+}
+
+// isValidFlagKey returns true if key can be used as a key in comma separated
+// key=value flags, such as --feature-gates and --runtime-config
+func isValidFlagKey(key string) bool {
+	return key != "" && !strings.ContainsAny(key, ",= \t\n\"")
+}
+
+// sortedKeys returns the keys of m sorted, m must be a map with string keys
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (t *Tmpfs) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	for _, size := range []struct {
+		name    string
+		value   string
+		enabled bool
+	}{
+		{"etcdSize", t.EtcdSize, t.Etcd},
+		{"containerRuntimeSize", t.ContainerRuntimeSize, t.ContainerRuntime},
+	} {
+		if size.value == "" {
+			continue
+		}
+		if !size.enabled {
+			errs = append(errs, field.Forbidden(fldPath.Child(size.name), "only applies to a tmpfs that is enabled"))
+			continue
+		}
+		if q, err := resource.ParseQuantity(size.value); err != nil || q.Sign() <= 0 {
+			errs = append(errs, field.Invalid(fldPath.Child(size.name), size.value, "must be a positive quantity, e.g. \"512Mi\""))
+		}
 	}
+	return errs
+}
+
+func (s *Storage) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	if s.Disabled {
+		if s.ClassName != "" || s.NodePath != "" || s.HostPath != "" {
+			errs = append(errs, field.Forbidden(fldPath, "the storage settings do not apply if disabled"))
+		}
+		return errs
+	}
+	if s.ClassName != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(s.ClassName) {
+			errs = append(errs, field.Invalid(fldPath.Child("className"), s.ClassName, msg))
+		}
+	}
+	if s.NodePath != "" {
+		nodePath := path.Clean(s.NodePath)
+		if !path.IsAbs(nodePath) || nodePath == "/" || strings.ContainsAny(nodePath, "\"\n") {
+			errs = append(errs, field.Invalid(fldPath.Child("nodePath"), s.NodePath, "must be an absolute directory path"))
+		}
+		for _, reserved := range reservedContainerPaths {
+			if nodePath == reserved {
+				errs = append(errs, field.Forbidden(fldPath.Child("nodePath"), fmt.Sprintf("%s is mounted by kind", reserved)))
+			}
+		}
+	}
+
+	return errs
+}
+
+func (a *Audit) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
 
-	if len(errs) > 0 {
-		return util.NewErrors(errs)
+	// the policy is either inline or a file
+	if a.Policy != "" && a.PolicyFile != "" {
+		errs = append(errs, field.Forbidden(fldPath.Child("policyFile"), "may not be set along with policy"))
 	}
 
+	// the inline policy should be an audit policy, the file is read and
+	// checked when creating the cluster
+	if a.Policy != "" {
+		if err := ValidateAuditPolicy([]byte(a.Policy)); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("policy"), a.Policy, err.Error()))
+		}
+	}
+
+	return errs
+}
+
+// ValidateAuditPolicy returns an error unless policy is an audit.k8s.io
+// Policy yaml or json document
+func ValidateAuditPolicy(policy []byte) error {
+	meta := struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+	}{}
+	if err := yaml.Unmarshal(policy, &meta); err != nil {
+		return fmt.Errorf("must be a yaml or json object")
+	}
+	if !strings.HasPrefix(meta.APIVersion, "audit.k8s.io/") || meta.Kind != "Policy" {
+		return fmt.Errorf("must be an audit.k8s.io Policy")
+	}
 	return nil
 }
+
+func (e *Encryption) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	// the config is either generated or inline
+	if e.Config != "" {
+		for _, f := range []struct {
+			name string
+			set  bool
+		}{
+			{"provider", e.Provider != ""},
+			{"resources", len(e.Resources) > 0},
+			{"kmsPluginName", e.KMSPluginName != ""},
+		} {
+			if f.set {
+				errs = append(errs, field.Forbidden(fldPath.Child(f.name), "may not be set along with config"))
+			}
+		}
+		meta := struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+		}{}
+		if err := yaml.Unmarshal([]byte(e.Config), &meta); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("config"), e.Config, "must be a yaml or json object"))
+		} else if meta.Kind != "EncryptionConfiguration" && meta.Kind != "EncryptionConfig" {
+			errs = append(errs, field.Invalid(fldPath.Child("config"), e.Config, "must be an EncryptionConfiguration"))
+		}
+		return errs
+	}
+
+	// the provider should be one of the expected values, if set
+	switch e.Provider {
+	case "":
+		if len(e.Resources) > 0 {
+			errs = append(errs, field.Forbidden(fldPath.Child("resources"), "requires provider"))
+		}
+	case AESCBCEncryptionProvider, AESGCMEncryptionProvider, SecretboxEncryptionProvider, KMSEncryptionProvider:
+	default:
+		errs = append(errs, field.NotSupported(
+			fldPath.Child("provider"), e.Provider,
+			[]string{string(AESCBCEncryptionProvider), string(AESGCMEncryptionProvider), string(SecretboxEncryptionProvider), string(KMSEncryptionProvider)},
+		))
+	}
+
+	// the kms provider requires the name of the plugin
+	kmsPath := fldPath.Child("kmsPluginName")
+	if e.Provider == KMSEncryptionProvider && e.KMSPluginName == "" {
+		errs = append(errs, field.Required(kmsPath, fmt.Sprintf("required for provider %q", KMSEncryptionProvider)))
+	}
+	if e.Provider != KMSEncryptionProvider && e.KMSPluginName != "" {
+		errs = append(errs, field.Forbidden(kmsPath, fmt.Sprintf("only supported for provider %q", KMSEncryptionProvider)))
+	}
+
+	// each resource should be a resource name, e.g. secrets or deployments.apps
+	resources := map[string]bool{}
+	for i, name := range e.Resources {
+		if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("resources").Index(i), name, strings.Join(msgs, "; ")))
+		}
+		if resources[name] {
+			errs = append(errs, field.Duplicate(fldPath.Child("resources").Index(i), name))
+		}
+		resources[name] = true
+	}
+
+	return errs
+}
+
+func (c *Certificates) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	// the CA certificate and key are set together, the files are read and
+	// checked when creating the cluster
+	if c.CACertFile != "" && c.CAKeyFile == "" {
+		errs = append(errs, field.Required(fldPath.Child("caKeyFile"), "required along with caCertFile"))
+	}
+	if c.CAKeyFile != "" && c.CACertFile == "" {
+		errs = append(errs, field.Required(fldPath.Child("caCertFile"), "required along with caKeyFile"))
+	}
+
+	// the signing duration should be positive, if set
+	if c.SigningDuration != "" {
+		d, err := time.ParseDuration(c.SigningDuration)
+		if err != nil || d <= 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("signingDuration"), c.SigningDuration, "must be a positive duration, e.g. \"1h\""))
+		}
+	}
+
+	return errs
+}
+
+func (t *Timeouts) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	for _, timeout := range []struct {
+		name  string
+		value string
+	}{
+		{"nodeBoot", t.NodeBoot},
+		{"kubeadmInit", t.KubeadmInit},
+		{"nodeRegistration", t.NodeRegistration},
+		{"cniRollout", t.CNIRollout},
+		{"ready", t.Ready},
+		{"pollInterval", t.PollInterval},
+	} {
+		if timeout.value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(timeout.value); err != nil || d <= 0 {
+			errs = append(errs, field.Invalid(fldPath.Child(timeout.name), timeout.value, "must be a positive duration, e.g. \"2m\""))
+		}
+	}
+	return errs
+}
+
+func (a *Addon) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	for _, msg := range validation.IsDNS1123Label(a.Name) {
+		errs = append(errs, field.Invalid(fldPath.Child("name"), a.Name, msg))
+	}
+
+	// either manifests or a chart are installed
+	hasManifests := len(a.Manifests) > 0 || len(a.Files) > 0 || len(a.URLs) > 0
+	if hasManifests == (a.Chart != nil) {
+		errs = append(errs, field.Invalid(fldPath, a.Name, "exactly one of chart and manifests, files or urls must be set"))
+	}
+	for j, url := range a.URLs {
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			errs = append(errs, field.Invalid(fldPath.Child("urls").Index(j), url, "must be an http or https URL"))
+		}
+	}
+	if a.Chart != nil {
+		chartPath := fldPath.Child("chart")
+		if a.Chart.Name == "" {
+			errs = append(errs, field.Required(chartPath.Child("name"), "the chart is required"))
+		}
+		if a.Chart.Release != "" {
+			for _, msg := range validation.IsDNS1123Label(a.Chart.Release) {
+				errs = append(errs, field.Invalid(chartPath.Child("release"), a.Chart.Release, msg))
+			}
+		}
+		if a.Chart.Namespace != "" {
+			for _, msg := range validation.IsDNS1123Label(a.Chart.Namespace) {
+				errs = append(errs, field.Invalid(chartPath.Child("namespace"), a.Chart.Namespace, msg))
+			}
+		}
+	}
+
+	for j := range a.Wait {
+		errs = append(errs, a.Wait[j].validate(fldPath.Child("wait").Index(j))...)
+	}
+	if a.WaitTimeout != "" {
+		if d, err := time.ParseDuration(a.WaitTimeout); err != nil || d <= 0 {
+			errs = append(errs, field.Invalid(fldPath.Child("waitTimeout"), a.WaitTimeout, "must be a positive duration, e.g. \"2m\""))
+		}
+	}
+
+	return errs
+}
+
+// rolloutResourceTypes are the resource types kubectl rollout status supports
+var rolloutResourceTypes = []string{
+	"deployment", "deployments", "deploy",
+	"daemonset", "daemonsets", "ds",
+	"statefulset", "statefulsets", "sts",
+}
+
+func (r *ReadinessCheck) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	parts := strings.SplitN(r.Resource, "/", 2)
+	switch {
+	case r.Resource == "" || strings.ContainsAny(r.Resource, " \t\n"):
+		errs = append(errs, field.Invalid(fldPath.Child("resource"), r.Resource, "must be a resource type or type/name, e.g. \"daemonset/my-operator\""))
+	case len(parts) == 2 && (parts[0] == "" || parts[1] == ""):
+		errs = append(errs, field.Invalid(fldPath.Child("resource"), r.Resource, "must be a resource type or type/name, e.g. \"daemonset/my-operator\""))
+	case len(parts) == 2 && r.Selector != "":
+		errs = append(errs, field.Invalid(fldPath.Child("selector"), r.Selector, "only applies to a resource type, not to a single resource"))
+	}
+
+	// either a condition or a rollout is checked
+	if (r.Condition == "") == !r.Rollout {
+		errs = append(errs, field.Invalid(fldPath.Child("condition"), r.Condition, "exactly one of condition and rollout must be set"))
+	}
+	if strings.ContainsAny(r.Condition, " \t\n\"") {
+		errs = append(errs, field.Invalid(fldPath.Child("condition"), r.Condition, "must be a condition type, e.g. \"Available\""))
+	}
+	if r.Rollout {
+		supported := false
+		for _, resourceType := range rolloutResourceTypes {
+			supported = supported || strings.ToLower(parts[0]) == resourceType
+		}
+		if !supported || len(parts) != 2 {
+			errs = append(errs, field.Invalid(fldPath.Child("resource"), r.Resource, "rollout requires a single deployment, daemonset or statefulset, e.g. \"daemonset/my-operator\""))
+		}
+	}
+
+	return errs
+}
+
+func (o *OIDC) validate(fldPath *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	// the other fields only apply along with an issuer
+	if !o.Enabled() {
+		if !reflect.DeepEqual(*o, OIDC{}) {
+			errs = append(errs, field.Required(fldPath.Child("issuerURL"), "required to configure OpenID Connect"))
+		}
+		return errs
+	}
+
+	// the API server only accepts https issuers, without a query or fragment
+	if u, err := url.Parse(o.IssuerURL); err != nil || u.Scheme != "https" || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		errs = append(errs, field.Invalid(fldPath.Child("issuerURL"), o.IssuerURL, "must be an https URL without a query or fragment"))
+	}
+	if o.ClientID == "" {
+		errs = append(errs, field.Required(fldPath.Child("clientID"), "required along with issuerURL"))
+	}
+
+	// the values are API server and kubelogin flags
+	for name, value := range map[string]string{
+		"clientID":       o.ClientID,
+		"clientSecret":   o.ClientSecret,
+		"usernameClaim":  o.UsernameClaim,
+		"usernamePrefix": o.UsernamePrefix,
+		"groupsClaim":    o.GroupsClaim,
+		"groupsPrefix":   o.GroupsPrefix,
+	} {
+		if strings.ContainsAny(value, " \t\n\"") {
+			errs = append(errs, field.Invalid(fldPath.Child(name), value, "must not contain quotes or whitespace"))
+		}
+	}
+	for _, claim := range sortedKeys(o.RequiredClaims) {
+		claimPath := fldPath.Child("requiredClaims").Key(claim)
+		if !isValidFlagKey(claim) {
+			errs = append(errs, field.Invalid(claimPath, claim, "must not be empty or contain commas, equal signs, quotes or whitespace"))
+		}
+		if value := o.RequiredClaims[claim]; strings.ContainsAny(value, ",= \t\n\"") {
+			errs = append(errs, field.Invalid(claimPath, value, "must not contain commas, equal signs, quotes or whitespace"))
+		}
+	}
+	for i, scope := range o.ExtraScopes {
+		if !isValidFlagKey(scope) || scope == "openid" {
+			errs = append(errs, field.Invalid(fldPath.Child("extraScopes").Index(i), scope, "must be a scope other than \"openid\", without commas, equal signs, quotes or whitespace"))
+		}
+	}
+
+	return errs
+}
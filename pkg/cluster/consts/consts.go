@@ -22,3 +22,92 @@ const ClusterLabelKey = "io.k8s.sigs.kind.cluster"
 
 // ClusterRoleKey is applied to each "node" docker container for categorization of nodes by role
 const ClusterRoleKey = "io.k8s.sigs.kind.role"
+
+// ClusterExpiryKey is applied to each "node" docker container of clusters
+// created with a TTL, the value is the RFC3339 time after which the cluster
+// may be garbage collected
+const ClusterExpiryKey = "io.k8s.sigs.kind.expiry"
+
+// ReplicaIndexKey is applied to each "node" docker container, the value is
+// the index of the node among the nodes with the same role, starting at 1
+const ReplicaIndexKey = "io.k8s.sigs.kind.replica"
+
+// ConfigHashKey is applied to each "node" docker container, the value is the
+// SHA-256 digest of the config the cluster was created from, encoded in the
+// latest config API version
+const ConfigHashKey = "io.k8s.sigs.kind.config-hash"
+
+// KindVersionKey is applied to each "node" docker container, the value is
+// the version of kind that created the node
+const KindVersionKey = "io.k8s.sigs.kind.version"
+
+// NodeImageDigestKey is applied to each "node" docker container, the value
+// is the digest of the image the node was created from, see
+// docker.ImageDigest
+const NodeImageDigestKey = "io.k8s.sigs.kind.image-digest"
+
+// OwnerKey is applied to each "node" docker container of clusters created
+// with an owner identity, the value is the owner, e.g. a user or a CI job ID
+const OwnerKey = "io.k8s.sigs.kind.owner"
+
+// LoadBalancerTypeKey is applied to the external load balancer "node" docker
+// container, the value is the load balancer implementation type
+const LoadBalancerTypeKey = "io.k8s.sigs.kind.loadbalancer"
+
+// IPFamilyKey is applied to each "node" docker container of IPv6 and
+// dual-stack clusters, the value is the IP family of the cluster
+const IPFamilyKey = "io.k8s.sigs.kind.ip-family"
+
+// DefaultCNIKey is applied to each "node" docker container of clusters
+// created without the default CNI network plugin, the value is "false"
+const DefaultCNIKey = "io.k8s.sigs.kind.default-cni"
+
+// APIServerCertSANsKey is applied to each "node" docker container of clusters
+// with additional API server certificate SANs, the value is the comma
+// separated list of SANs
+const APIServerCertSANsKey = "io.k8s.sigs.kind.apiserver-cert-sans"
+
+// IngressReadyKey is applied to the ingress-ready "node" docker container,
+// the value is "true"
+const IngressReadyKey = "io.k8s.sigs.kind.ingress-ready"
+
+// RegistryKey is applied to each "node" docker container of clusters with a
+// local registry, the value is the address of the registry in the docker
+// network of the cluster
+const RegistryKey = "io.k8s.sigs.kind.registry"
+
+// RegistryClusterKey is applied to the local registry docker container, the
+// value is the cluster name. The registry is not a "node", it does not have
+// the cluster label
+const RegistryClusterKey = "io.k8s.sigs.kind.registry-cluster"
+
+// PreloadImagesKey is applied to each "node" docker container of clusters
+// with images to preload, the value is the comma separated list of images
+const PreloadImagesKey = "io.k8s.sigs.kind.preload-images"
+
+// ImageStoreKey is applied to each "node" docker container of clusters with
+// an image store, the value is the prefix of the volume names, see
+// config.ImageStore, and with the value true to the image store volumes
+const ImageStoreKey = "io.k8s.sigs.kind.image-store"
+
+// TmpfsKey is applied to each "node" docker container of clusters keeping
+// node data in tmpfs, the value is the comma separated list of the data kept
+// in tmpfs, see config.Tmpfs
+const TmpfsKey = "io.k8s.sigs.kind.tmpfs"
+
+// NodeOSKey is applied to the Windows "node" docker containers, the value is
+// "windows". Nodes without this label run Linux
+const NodeOSKey = "io.k8s.sigs.kind.os"
+
+// NodeImageManifestsKey is applied to the node images built with manifests
+// to apply to the clusters, the value is "true"
+const NodeImageManifestsKey = "io.k8s.sigs.kind.manifests"
+
+// NodeImageManifestsDir is the directory of the manifests built into the node
+// images, they are applied in the order of their file names
+const NodeImageManifestsDir = "/kind/manifests/extra"
+
+// PoolKey is applied to the "node" docker containers created for node pools,
+// and to their volumes, the value is the name of the pool. The label is kept
+// when a cluster claims the node, the claim is recorded in the node instead
+const PoolKey = "io.k8s.sigs.kind.pool"
@@ -18,25 +18,33 @@ package cluster
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
-	log "github.com/sirupsen/logrus"
-
 	"sigs.k8s.io/kind/pkg/cluster/config"
 	"sigs.k8s.io/kind/pkg/cluster/consts"
+	"sigs.k8s.io/kind/pkg/cluster/events"
 	"sigs.k8s.io/kind/pkg/cluster/logs"
 	"sigs.k8s.io/kind/pkg/cluster/nodes"
 	"sigs.k8s.io/kind/pkg/docker"
+	"sigs.k8s.io/kind/pkg/fs"
 	logutil "sigs.k8s.io/kind/pkg/log"
+	"sigs.k8s.io/kind/pkg/tracing"
+	"sigs.k8s.io/kind/pkg/util"
 )
 
 // Context is used to create / manipulate kubernetes-in-docker clusters
 type Context struct {
 	name             string
+	logger           logutil.Logger
+	statusWriter     io.Writer
+	eventHandler     events.Handler
+	nodePool         string // the node pool to claim nodes from, see SetNodePool
 	ControlPlaneMeta *ControlPlaneMeta
 }
 
@@ -47,7 +55,11 @@ type createContext struct {
 	config           *config.Config
 	derived          *derivedConfigData
 	retain           bool          // if we should retain nodes after failing to create.
-	waitForReady     time.Duration // Wait for the control plane node to be ready.
+	keepVolumes      bool          // if we should keep the node data volumes when deleting nodes after failing to create.
+	waitForReady     time.Duration // Wait for the cluster to be ready, see waitForReady
+	expiry           time.Time     // Time after which the cluster may be garbage collected, if not zero
+	cniManifest      []byte        // The default CNI manifest to install instead of downloading it, if not nil
+	timeouts         timeouts      // How long to wait for each provisioning phase, see config.Timeouts
 	ControlPlaneMeta *ControlPlaneMeta
 }
 
@@ -64,15 +76,68 @@ type execContext struct {
 	config  *config.Config
 	derived *derivedConfigData
 	// nodes contains the list of actual nodes (a node is a container implementing a config node)
-	nodes        map[string]*nodes.Node
-	waitForReady time.Duration // Wait for the control plane node to be ready
+	nodes map[string]*nodes.Node
+	// tempDir is the host directory for files shared by the tasks, created
+	// on first use and removed after the execution, see TempDir
+	tempDir string
+	// preloadImagesArchive contains the preloadImages, see preloadArchive
+	preloadImagesArchive string
+	preloadImages        []docker.ArchiveImage
 }
 
-// similar to valid docker container names, but since we will prefix
-// and suffix this name, we can relax it a little
-// see NewContext() for usage
-// https://godoc.org/github.com/docker/docker/daemon/names#pkg-constants
-var validNameRE = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+// cluster names are DNS labels, as the node container names derived from
+// them are the hostnames and the Kubernetes node names of the nodes
+// see Context.Validate() for usage
+// https://tools.ietf.org/html/rfc1123#section-2
+var validNameRE = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// maxNameLength is the maximum length of the cluster names, so that the
+// node container names, e.g. kind-<name>-control-plane10, fit the 63
+// characters of a hostname. The Kubernetes node names are DNS subdomains,
+// which may be longer, but they are also the hostnames of the nodes
+const maxNameLength = 63 - len("kind--control-plane10")
+
+// createActions returns the actions executed by Create for the config, in order
+// By default `kind` executes all the actions required to get a fully working
+// Kubernetes cluster, the CNI network plugin is not installed if disabled,
+// and the fake nodes and the optional components only if configured
+func createActions(cfg *config.Config) []string {
+	actions := []string{}
+	if len(cfg.PreloadImages) > 0 {
+		actions = append(actions, "preload")
+	}
+	actions = append(actions, "etcd", "loadbalancer", "config", "init")
+	if installsDefaultCNI(cfg) {
+		actions = append(actions, "cni")
+	}
+	actions = append(actions, "join")
+	if hasFakeNodes(cfg) {
+		actions = append(actions, "fake-nodes")
+	}
+	if cfg.VolumeSnapshots {
+		actions = append(actions, "volume-snapshots")
+	}
+	if cfg.Ingress.Controller != "" {
+		actions = append(actions, "ingress")
+	}
+	if cfg.MetricsServer {
+		actions = append(actions, "metrics-server")
+	}
+	if cfg.Dashboard {
+		actions = append(actions, "dashboard")
+	}
+	return actions
+}
+
+// hasFakeNodes returns true if the config has nodes with fake role
+func hasFakeNodes(cfg *config.Config) bool {
+	for i := range cfg.Nodes {
+		if cfg.Nodes[i].IsFake() {
+			return true
+		}
+	}
+	return false
+}
 
 // DefaultName is the default Context name
 // TODO(bentheelder): consider removing automatic prefixing in favor
@@ -103,6 +168,12 @@ func (c *Context) Validate() error {
 			c.name, validNameRE.String(),
 		)
 	}
+	if len(c.name) > maxNameLength {
+		return fmt.Errorf(
+			"'%s' is not a valid cluster name, cluster names must be at most %d characters long",
+			c.name, maxNameLength,
+		)
+	}
 	return nil
 }
 
@@ -117,12 +188,71 @@ func (c *Context) Name() string {
 	return c.name
 }
 
+// SetLogger sets the logger used by the context, library consumers may use
+// this to inject their own logger, if not set logutil.Default() is used
+func (c *Context) SetLogger(logger logutil.Logger) {
+	c.logger = logger
+}
+
+// Logger returns the logger used by the context
+func (c *Context) Logger() logutil.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return logutil.Default()
+}
+
+// SetStatusWriter sets the writer the progress of creating the cluster is
+// reported to, if not set os.Stdout is used. The progress is only shown with
+// a spinner if the writer is a terminal
+func (c *Context) SetStatusWriter(w io.Writer) {
+	c.statusWriter = w
+}
+
+// newStatus returns a status reporting to the status writer of the context,
+// wrapping the logger of the context so that the two do not garble each other
+func (c *Context) newStatus() *logutil.Status {
+	status := logutil.NewStatus(c.statusOut())
+	status.MaybeWrapLogger(c.Logger())
+	return status
+}
+
+// statusOut returns the status writer of the context, os.Stdout if not set
+func (c *Context) statusOut() io.Writer {
+	if c.statusWriter == nil {
+		return os.Stdout
+	}
+	return c.statusWriter
+}
+
+// SetEventHandler sets the handler called with the events of the cluster
+// lifecycle, e.g. events.Channel(ch), if nil no events are emitted
+func (c *Context) SetEventHandler(handler events.Handler) {
+	c.eventHandler = handler
+}
+
+// emit calls the event handler, if any, with e
+func (c *Context) emit(e events.Event) {
+	if c.eventHandler == nil {
+		return
+	}
+	e.Time = time.Now()
+	e.Cluster = c.name
+	c.eventHandler(e)
+}
+
 // ClusterName returns the Kubernetes cluster name based on the context name
 // currently this is .Name prefixed with "kind-"
 func (c *Context) ClusterName() string {
 	return fmt.Sprintf("kind-%s", c.name)
 }
 
+// nodeContainerName returns the name of the container implementing the
+// node replica with the given name
+func (c *Context) nodeContainerName(replicaName string) string {
+	return fmt.Sprintf("kind-%s-%s", c.name, replicaName)
+}
+
 // KubeConfigPath returns the path to where the Kubeconfig would be placed
 // by kind based on the configuration.
 func (c *Context) KubeConfigPath() string {
@@ -135,48 +265,117 @@ func (c *Context) KubeConfigPath() string {
 	return filepath.Join(configDir, fileName)
 }
 
+// InternalKubeConfigPath returns the path to where the internal kubeconfig,
+// reaching the API server inside the docker network, would be placed by kind
+// next to the kubeconfig at KubeConfigPath
+func (c *Context) InternalKubeConfigPath() string {
+	return c.KubeConfigPath() + "-internal"
+}
+
 // Create provisions and starts a kubernetes-in-docker cluster
-func (c *Context) Create(cfg *config.Config, retain bool, wait time.Duration) error {
+// If ttl > 0 the cluster will be deleted by GarbageCollect once it expires
+func (c *Context) Create(cfg *config.Config, retain bool, wait, ttl time.Duration) error {
+	var expiry time.Time
+	if ttl > 0 {
+		expiry = time.Now().Add(ttl)
+	}
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	return c.create(cfg, retain, false, wait, expiry, nil)
+}
+
+// validateForCreate validates cfg and derives the info necessary for creation
+func validateForCreate(cfg *config.Config) (*derivedConfigData, error) {
 	// validate config first
 	if err := cfg.Validate(); err != nil {
-		return err
+		return nil, err
 	}
 
 	// derive info necessary for creation
 	derived, err := deriveInfo(cfg)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	// validate node configuration
 	if err := derived.Validate(); err != nil {
-		return err
+		return nil, err
+	}
+	// the audit policy and CA files are read when provisioning the
+	// control-plane
+	if _, err := auditPolicy(&cfg.Audit); err != nil {
+		return nil, err
+	}
+	if _, _, err := clusterCA(&cfg.Certificates); err != nil {
+		return nil, err
+	}
+	if _, err := oidcCA(&cfg.OIDC); err != nil {
+		return nil, err
 	}
 	// TODO(fabrizio pandini): this check is temporary / WIP
 	// kind v1alpha config fully supports multi nodes, but the cluster creation logic implemented in
 	// pkg/cluster/contex.go does it only partially (yet).
-	// As soon a external load-balancer and external etcd is implemented in pkg/cluster, this should go away
-	if derived.ExternalLoadBalancer() != nil {
-		return fmt.Errorf("multi node support is still a work in progress, currently external load balancer node is not supported")
-	}
+	// As soon as joining secondary control planes is implemented in pkg/cluster, this should go away
 	if derived.SecondaryControlPlanes() != nil {
-		return fmt.Errorf("multi node support is still a work in progress, currently only single control-plane node are supported")
+		return nil, fmt.Errorf("multi node support is still a work in progress, currently only single control-plane node are supported")
+	}
+	return derived, nil
+}
+
+// create implements Create, CreateOffline and Recreate
+func (c *Context) create(cfg *config.Config, retain, keepVolumes bool, wait time.Duration, expiry time.Time, cniManifest []byte) (err error) {
+	derived, err := validateForCreate(cfg)
+	if err != nil {
+		return err
 	}
-	if derived.ExternalEtcd() != nil {
-		return fmt.Errorf("multi node support is still a work in progress, currently external etcd node is not supported")
+	if err := selectProvider(cfg, derived); err != nil {
+		return err
+	}
+	// the cluster is waited for as configured, unless requested otherwise
+	timeouts := newTimeouts(&cfg.Timeouts)
+	if wait <= 0 {
+		wait = timeouts.Ready
 	}
 
-	fmt.Printf("Creating cluster '%s' ...\n", c.ClusterName())
+	// the node containers are named after the cluster
+	existing, err := c.ListNodes()
+	if err != nil {
+		return fmt.Errorf("error listing nodes: %v", err)
+	}
+	if len(existing) > 0 {
+		return &ClusterExistsError{Name: c.Name()}
+	}
+	// as are the docker objects of other clusters or not created by kind
+	containers, networks, err := c.nameCollisions(cfg, derived)
+	if err != nil {
+		return err
+	}
+	if len(containers) > 0 || len(networks) > 0 {
+		return &NameCollisionError{Name: c.Name(), Containers: containers, Networks: networks}
+	}
+
+	fmt.Fprintf(c.statusOut(), "Creating cluster '%s' ...\n", c.ClusterName())
+	c.emit(events.Event{
+		Type:    events.ClusterCreateStarted,
+		Message: fmt.Sprintf("Creating cluster '%s'", c.ClusterName()),
+	})
+	span := tracing.Default().StartSpan("create cluster", map[string]string{"kind.cluster": c.name})
+	defer func() { span.Finish(err) }()
 
 	// init the create context and logging
 	cc := &createContext{
-		Context: c,
-		config:  cfg,
-		derived: derived,
-		retain:  retain,
+		Context:      c,
+		config:       cfg,
+		derived:      derived,
+		retain:       retain,
+		keepVolumes:  keepVolumes,
+		expiry:       expiry,
+		cniManifest:  cniManifest,
+		waitForReady: wait,
+		timeouts:     timeouts,
 	}
 
-	cc.status = logutil.NewStatus(os.Stdout)
-	cc.status.MaybeWrapLogrus(log.StandardLogger())
+	cc.status = cc.newStatus()
 
 	defer cc.status.End(false)
 
@@ -188,9 +387,21 @@ func (c *Context) Create(cfg *config.Config, retain bool, wait time.Duration) er
 	nodeList, err := cc.provisionNodes()
 	if err != nil {
 		// In case of errors nodes are deleted (except if retain is explicitly set)
-		log.Error(err)
+		c.Logger().Errorf("%v", err)
+		c.emitCreateFailed(err)
 		if !cc.retain {
-			cc.Delete()
+			cc.deleteNodes(cc.keepVolumes)
+		}
+		return err
+	}
+
+	// the node images can be set per node, ensure the Kubernetes versions
+	// are compatible before provisioning Kubernetes
+	if err := validateVersionSkew(cc.derived, nodeList); err != nil {
+		c.Logger().Errorf("%v", err)
+		c.emitCreateFailed(err)
+		if !cc.retain {
+			cc.deleteNodes(cc.keepVolumes)
 		}
 		return err
 	}
@@ -198,27 +409,60 @@ func (c *Context) Create(cfg *config.Config, retain bool, wait time.Duration) er
 	cc.status.End(true)
 
 	// After creating node containers the Kubernetes provisioning is executed
-	// By default `kind` executes all the actions required to get a fully working
-	// Kubernetes cluster; please note that the list of actions automatically
-	// adapt to the topology defined in config
+	// please note that the list of actions automatically adapt to the
+	// topology defined in config
 	// TODO(fabrizio pandini): make the list of executed actions configurable from CLI
-	err = c.exec(cc.config, cc.derived, nodeList, []string{"config", "init", "join"}, wait)
+	actions := createActions(cc.config)
+	if imageHasManifests(cc.derived.BootStrapControlPlane().Image) {
+		actions = withManifestsAction(actions)
+	}
+	err = c.exec(cc.config, cc.derived, nodeList, actions)
 	if err != nil {
 		// In case of errors nodes are deleted (except if retain is explicitly set)
-		log.Error(err)
+		c.Logger().Errorf("%v", err)
+		c.emitCreateFailed(err)
+		if !cc.retain {
+			cc.deleteNodes(cc.keepVolumes)
+		}
+		return err
+	}
+
+	// wait for the cluster to be ready, if requested, then install the addons
+	err = cc.waitForCluster(nodeList)
+	if err == nil && len(cc.config.Addons) > 0 {
+		err = c.exec(cc.config, cc.derived, nodeList, []string{"addons"})
+	}
+	if err != nil {
+		c.Logger().Errorf("%v", err)
+		c.emitCreateFailed(err)
 		if !cc.retain {
-			cc.Delete()
+			cc.deleteNodes(cc.keepVolumes)
 		}
 		return err
 	}
 
-	fmt.Printf(
+	fmt.Fprintf(c.statusOut(),
 		"Cluster creation complete. You can now use the cluster with:\n\nexport KUBECONFIG=\"$(kind get kubeconfig-path --name=%q)\"\nkubectl cluster-info\n",
 		cc.Name(),
 	)
+	c.emit(events.Event{
+		Type:    events.ClusterReady,
+		Message: fmt.Sprintf("Cluster '%s' is ready", c.ClusterName()),
+	})
+	if !installsDefaultCNI(cc.config) {
+		fmt.Fprintln(c.statusOut(), "\nNo CNI network plugin was installed, the nodes are not Ready until one is.")
+	}
 	return nil
 }
 
+// emitCreateFailed emits a ClusterCreateFailed event for err
+func (c *Context) emitCreateFailed(err error) {
+	c.emit(events.Event{
+		Type:  events.ClusterCreateFailed,
+		Error: err.Error(),
+	})
+}
+
 // TODO(bentheelder): fix this after multi-node changes (!)
 
 // ControlPlaneMeta tracks various outputs that are relevant to the control plane created with Kind.
@@ -246,13 +490,126 @@ func (cc *createContext) EnsureNodeImages() {
 		}
 		cc.status.Start(fmt.Sprintf("Ensuring node image (%s) 🖼", image))
 
-		// attempt to explicitly pull the image if it doesn't exist locally
+		// attempt to explicitly pull the image if it doesn't exist locally,
+		// for the configured architecture if any
 		// we don't care if this errors, we'll still try to run which also pulls
-		_, _ = docker.PullIfNotPresent(configNode.Image, 4)
+		_, _ = docker.PullIfNotPresentForArch(configNode.Image, cc.config.Arch, 4)
 
 		// marks the images as already pulled
 		images[configNode.Image] = true
 	}
+
+	// images for another architecture than the host run under emulation,
+	// e.g. amd64 images on Apple Silicon, if the host supports it at all
+	hostArch := docker.HostArch()
+	for _, image := range sortedSet(images) {
+		imageArch, err := docker.ImageArch(image)
+		if err != nil || imageArch == hostArch {
+			continue
+		}
+		cc.Logger().Warnf(
+			"Image %s is for %s, but the host is %s: the nodes run under emulation, which is slow and may fail. Use a multi-arch node image, or build one for %s with 'kind build node-image --arch %s'",
+			image, imageArch, hostArch, hostArch, hostArch,
+		)
+	}
+}
+
+// apiServerPort returns the host port to publish the API server of configNode
+// on, this is the configured port for the node the API server is reached
+// through, the external load balancer if any or the bootstrap control plane,
+// and 0 for a random port otherwise
+func (cc *createContext) apiServerPort(configNode *nodeReplica) int32 {
+	apiServer := cc.derived.ExternalLoadBalancer()
+	if apiServer == nil {
+		apiServer = cc.derived.BootStrapControlPlane()
+	}
+	if configNode != apiServer {
+		return 0
+	}
+	return cc.config.Networking.APIServerPort
+}
+
+// nodePortMappings returns the port mappings of the node container, the
+// extra port mappings of the node, and the ingress ports for the
+// ingress-ready node unless already mapped
+func nodePortMappings(configNode *nodeReplica) []config.PortMapping {
+	if !configNode.IngressReady {
+		return configNode.ExtraPortMappings
+	}
+	portMappings := append([]config.PortMapping{}, configNode.ExtraPortMappings...)
+	for _, port := range config.IngressPorts {
+		mapped := false
+		for _, pm := range configNode.ExtraPortMappings {
+			mapped = mapped || pm.ContainerPort == port
+		}
+		if !mapped {
+			portMappings = append(portMappings, config.PortMapping{ContainerPort: port, HostPort: port})
+		}
+	}
+	return portMappings
+}
+
+// configLabels returns the docker object labels recording the config
+// settings that are not otherwise recorded on the node containers, so that
+// they are available when operating on the cluster later, see
+// deriveInfoFromNodes
+func configLabels(cfg *config.Config) []string {
+	labels := []string{}
+	if ipFamily := cfg.Networking.IPFamily; ipFamily != "" && ipFamily != config.IPv4Family {
+		labels = append(labels, ipFamilyLabel(ipFamily))
+	}
+	if cfg.Networking.DisableDefaultCNI {
+		labels = append(labels, fmt.Sprintf("%s=false", consts.DefaultCNIKey))
+	}
+	if len(cfg.Networking.APIServerCertSANs) > 0 {
+		labels = append(labels, fmt.Sprintf("%s=%s", consts.APIServerCertSANsKey, strings.Join(cfg.Networking.APIServerCertSANs, ",")))
+	}
+	if len(cfg.PreloadImages) > 0 {
+		labels = append(labels, fmt.Sprintf("%s=%s", consts.PreloadImagesKey, strings.Join(cfg.PreloadImages, ",")))
+	}
+	if cfg.ImageStore.Volume != "" {
+		labels = append(labels, fmt.Sprintf("%s=%s", consts.ImageStoreKey, cfg.ImageStore.Volume))
+	}
+	if tmpfs := tmpfsData(&cfg.Tmpfs); len(tmpfs) > 0 {
+		labels = append(labels, fmt.Sprintf("%s=%s", consts.TmpfsKey, strings.Join(tmpfs, ",")))
+	}
+	return labels
+}
+
+// imageStoreVolume returns the name of the image store volume of the node
+// replica, or "" if the cluster has no image store, see config.ImageStore
+func imageStoreVolume(cfg *config.Config, replicaName string) string {
+	if cfg.ImageStore.Volume == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s-%s", cfg.ImageStore.Volume, replicaName)
+}
+
+// nodeOptions returns the options of the container of the control-plane or
+// worker node implementing configNode, see nodes.NodeOptions
+func nodeOptions(cfg *config.Config, configNode *nodeReplica, env, extraLabels []string) nodes.NodeOptions {
+	return nodes.NodeOptions{
+		ExtraMounts:       nodeMounts(cfg, configNode),
+		ExtraPortMappings: nodePortMappings(configNode),
+		Resources:         configNode.Resources,
+		Sysctls:           configNode.Sysctls,
+		ExtraDevices:      configNode.ExtraDevices,
+		Tmpfs:             nodeTmpfs(cfg, configNode),
+		ImageStore:        imageStoreVolume(cfg, configNode.Name),
+		DataPath:          nodeDataPath(cfg, configNode),
+		Env:               env,
+		ExtraLabels:       extraLabels,
+	}
+}
+
+// splitLabelList returns the list recorded in a label value as comma
+// separated values, e.g. the consts.APIServerCertSANsKey label, see
+// configLabels
+func splitLabelList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
 }
 
 // provisionNodes takes care of creating all the containers
@@ -260,55 +617,236 @@ func (cc *createContext) EnsureNodeImages() {
 func (cc *createContext) provisionNodes() (nodeList map[string]*nodes.Node, err error) {
 	nodeList = map[string]*nodes.Node{}
 
-	// For all the nodes defined in the `kind` config
-	for _, configNode := range cc.derived.AllReplicas() {
+	// labels applied to all the nodes, in addition to the cluster label
+	extraLabels := []string{}
+	if !cc.expiry.IsZero() {
+		extraLabels = append(extraLabels, expiryLabel(cc.expiry))
+	}
+	if owner := DefaultOwner(); owner != "" {
+		extraLabels = append(extraLabels, ownerLabel(owner))
+	}
+	extraLabels = append(extraLabels, configLabels(cc.config)...)
 
-		cc.status.Start(fmt.Sprintf("[%s] Creating node container 📦", configNode.Name))
-		// create the node into a container (docker run, but it is paused, see createNode)
-		var name = fmt.Sprintf("kind-%s-%s", cc.name, configNode.Name)
-		var node *nodes.Node
+	// the nodes are attached to the cluster network
+	network, err := cc.ensureNetwork(&cc.config.Networking)
+	if err != nil {
+		return nodeList, err
+	}
 
-		switch configNode.Role {
-		case config.ControlPlaneRole:
-			node, err = nodes.CreateControlPlaneNode(name, configNode.Image, cc.ClusterLabel())
-		case config.WorkerRole:
-			node, err = nodes.CreateWorkerNode(name, configNode.Image, cc.ClusterLabel())
-		}
-		if err != nil {
+	// the local registry is attached to the cluster network, if enabled
+	localRegistry := ""
+	if cc.config.Registry.Enabled {
+		cc.status.Start("Starting local registry 🗄")
+		if err := cc.ensureRegistry(&cc.config.Registry, network); err != nil {
 			return nodeList, err
 		}
-		nodeList[configNode.Name] = node
-
-		cc.status.Start(fmt.Sprintf("[%s] Fixing mounts 🗻", configNode.Name))
-		// we need to change a few mounts once we have the container
-		// we'd do this ahead of time if we could, but --privileged implies things
-		// that don't seem to be configurable, and we need that flag
-		if err := node.FixMounts(); err != nil {
-			// TODO(bentheelder): logging here
-			return nodeList, err
+		extraLabels = append(extraLabels, cc.registryLabel())
+		localRegistry = cc.registryAddress()
+	}
+
+	// the nodes pull images from the configured registries
+	registryConfig, err := newImageRegistryConfig(cc.config, localRegistry)
+	if err != nil {
+		return nodeList, err
+	}
+
+	// the proxies are set in the environment of the nodes, if any
+	env, err := cc.proxyEnv(cc.config, cc.derived, network)
+	if err != nil {
+		return nodeList, err
+	}
+
+	// the nodes are provisioned concurrently, see provisionParallelism
+	replicas := cc.derived.AllReplicas()
+
+	// the provenance of the nodes is recorded in their labels, the image
+	// digest is left out if the image is missing, the node creation fails then
+	configHash, err := ConfigHash(cc.config)
+	if err != nil {
+		return nodeList, err
+	}
+	imageDigests := map[string]string{}
+	for _, replica := range replicas {
+		if _, ok := imageDigests[replica.Image]; !ok {
+			imageDigests[replica.Image], _ = docker.ImageDigest(replica.Image)
 		}
+	}
 
-		cc.status.Start(fmt.Sprintf("[%s] Starting systemd 🖥", configNode.Name))
-		// signal the node container entrypoint to continue booting into systemd
-		if err := node.SignalStart(); err != nil {
-			// TODO(bentheelder): logging here
-			return nodeList, err
+	cc.status.Start(fmt.Sprintf("Preparing nodes %s", strings.TrimSpace(strings.Repeat("📦 ", len(replicas)))))
+	span := tracing.Default().StartSpan("provision nodes", nil)
+	defer func() { span.Finish(err) }()
+
+	provisioned := make([]*nodes.Node, len(replicas))
+	errs := make([]error, len(replicas))
+	sem := make(chan struct{}, provisionParallelism)
+	var wg sync.WaitGroup
+	for i := range replicas {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			labels := append(append([]string{}, extraLabels...), provenanceLabels(configHash, imageDigests[replicas[i].Image], cc.derived, replicas[i])...)
+			provisioned[i], errs[i] = cc.provisionNode(replicas[i], network, registryConfig, env, labels)
+		}(i)
+	}
+	wg.Wait()
+
+	// the nodes created before a failure are returned to be deleted, the
+	// events are emitted from this goroutine, see events.Handler
+	failed := []error{}
+	for i, configNode := range replicas {
+		if provisioned[i] != nil {
+			nodeList[configNode.Name] = provisioned[i]
+		}
+		if errs[i] != nil {
+			failed = append(failed, errs[i])
+			continue
 		}
+		cc.emitNodeProvisioned(configNode)
+	}
+	if len(failed) == 1 {
+		return nodeList, failed[0]
+	} else if len(failed) > 1 {
+		return nodeList, util.Flatten(failed)
+	}
+	return nodeList, nil
+}
+
+// provisionParallelism is the maximum number of nodes provisionNodes
+// creates and boots at the same time, the kubeadm actions are executed
+// afterwards in the order they require
+const provisionParallelism = 8
 
-		cc.status.Start(fmt.Sprintf("[%s] Waiting for docker to be ready 🐋", configNode.Name))
-		// wait for docker to be ready
-		if !node.WaitForDocker(time.Now().Add(time.Second * 30)) {
-			// TODO(bentheelder): logging here
-			return nodeList, fmt.Errorf("timed out waiting for docker to be ready on node")
+// provisionNode creates the container implementing configNode and boots it,
+// or claims it from the node pool, the node is returned if it was created
+// even if provisioning it failed afterwards, so that it can be deleted.
+// This is safe to call concurrently for different nodes
+func (cc *createContext) provisionNode(configNode *nodeReplica, network string, registryConfig *imageRegistryConfig, env, extraLabels []string) (node *nodes.Node, err error) {
+	span := tracing.Default().StartLeafSpan("provision node", map[string]string{
+		"kind.node": configNode.Name,
+		"kind.role": string(configNode.Role),
+	})
+	defer func() { span.Finish(err) }()
+
+	// create the node into a container (docker run, but it is paused, see createNode)
+	cc.Logger().Debugf("[%s] Creating node container", configNode.Name)
+	var name = cc.nodeContainerName(configNode.Name)
+	replicaLabels := extraLabels
+	if configNode.IngressReady {
+		replicaLabels = append([]string{fmt.Sprintf("%s=true", consts.IngressReadyKey)}, extraLabels...)
+	}
+
+	// claim a booted node from the node pool if possible, only the
+	// kubeadm actions remain to be executed on it
+	if cc.nodePool != "" {
+		node, err = cc.claimPoolNode(configNode, name, network, registryConfig, env, replicaLabels)
+		if err != nil || node != nil {
+			return node, err
 		}
+	}
 
-		// load the docker image artifacts into the docker daemon
-		cc.status.Start(fmt.Sprintf("[%s] Pre-loading images 🐋", configNode.Name))
-		node.LoadImages()
+	nodeNetwork := nodes.Network{
+		Name: network,
+		IP:   configNode.IPAddress,
+		IPv6: configNode.IPv6Address,
+		DNS:  cc.config.Networking.DNS,
+	}
 
+	switch configNode.Role {
+	case config.ControlPlaneRole:
+		node, err = nodes.CreateControlPlaneNode(name, configNode.Image, cc.ClusterLabel(), nodeNetwork, cc.config.Networking.APIServerAddress, cc.apiServerPort(configNode), nodeOptions(cc.config, configNode, env, replicaLabels))
+	case config.WorkerRole:
+		if configNode.IsWindows() {
+			node, err = nodes.CreateWindowsWorkerNode(name, configNode.Image, cc.ClusterLabel(), nodeNetwork, configNode.ExtraPortMappings, env, extraLabels...)
+			break
+		}
+		node, err = nodes.CreateWorkerNode(name, configNode.Image, cc.ClusterLabel(), nodeNetwork, nodeOptions(cc.config, configNode, env, replicaLabels))
+	case config.ExternalEtcdRole:
+		node, err = nodes.CreateExternalEtcdNode(name, configNode.Image, cc.ClusterLabel(), nodeNetwork, configNode.ExtraMounts, configNode.ExtraPortMappings, nodeTmpfs(cc.config, configNode), env, extraLabels...)
+	case config.ExternalLoadBalancerRole:
+		lbLabels := append([]string{
+			fmt.Sprintf("%s=%s", consts.LoadBalancerTypeKey, cc.config.LoadBalancer.Type),
+		}, extraLabels...)
+		node, err = nodes.CreateExternalLoadBalancerNode(name, configNode.Image, cc.ClusterLabel(), nodeNetwork, cc.config.Networking.APIServerAddress, cc.apiServerPort(configNode), lbLabels...)
+	}
+	if err != nil {
+		// the node image is pulled ahead by EnsureNodeImages, ignoring
+		// errors, if it is still missing the pull failed
+		if !docker.ImageExists(configNode.Image) {
+			return node, &ImagePullError{Image: configNode.Image, Err: err}
+		}
+		return node, err
 	}
 
-	return nodeList, nil
+	// the load balancer node does not run the node image, it is started
+	// once configured by the "loadbalancer" action
+	if configNode.Role == config.ExternalLoadBalancerRole {
+		return node, nil
+	}
+	// the Windows node image runs the container runtime right away, and
+	// is configured by the node image rather than by kind
+	if configNode.IsWindows() {
+		return node, nil
+	}
+
+	cc.Logger().Debugf("[%s] Fixing mounts", configNode.Name)
+	// we need to change a few mounts once we have the container
+	// we'd do this ahead of time if we could, but --privileged implies things
+	// that don't seem to be configurable, and we need that flag
+	if err := node.FixMounts(); err != nil {
+		// TODO(bentheelder): logging here
+		return node, err
+	}
+	if err := node.FixDNS(); err != nil {
+		return node, fmt.Errorf("failed to fix DNS: %v", err)
+	}
+	if err := node.ConfigureProxy(); err != nil {
+		return node, fmt.Errorf("failed to configure proxy: %v", err)
+	}
+	if err := registryConfig.Write(node); err != nil {
+		return node, err
+	}
+	if cc.cniManifest != nil && configNode.Role != config.ExternalEtcdRole {
+		if err := node.WriteFile(defaultCNIManifestPath, cc.cniManifest); err != nil {
+			return node, fmt.Errorf("failed to write the default CNI manifest: %v", err)
+		}
+	}
+
+	cc.Logger().Debugf("[%s] Starting systemd", configNode.Name)
+	// signal the node container entrypoint to continue booting into systemd
+	if err := node.SignalStart(); err != nil {
+		// TODO(bentheelder): logging here
+		return node, err
+	}
+
+	cc.Logger().Debugf("[%s] Waiting for docker to be ready", configNode.Name)
+	// wait for docker to be ready
+	if !node.WaitForDocker(time.Now().Add(cc.timeouts.NodeBoot)) {
+		// TODO(bentheelder): logging here
+		return node, &NodeNotReadyError{Node: node.String(), Condition: "docker to be ready"}
+	}
+
+	// the image store of a previous cluster also holds its containers
+	if cc.config.ImageStore.Volume != "" {
+		if err := node.RemoveContainers(); err != nil {
+			return node, fmt.Errorf("failed to remove the containers of the previous cluster from the image store: %v", err)
+		}
+	}
+
+	// load the docker image artifacts into the docker daemon
+	cc.Logger().Debugf("[%s] Pre-loading images", configNode.Name)
+	node.LoadImages()
+	return node, nil
+}
+
+// emitNodeProvisioned emits a NodeProvisioned event for configNode
+func (cc *createContext) emitNodeProvisioned(configNode *nodeReplica) {
+	cc.emit(events.Event{
+		Type:    events.NodeProvisioned,
+		Node:    configNode.Name,
+		Message: fmt.Sprintf("Node %s (%s) is running", configNode.Name, configNode.Role),
+	})
 }
 
 // TODO(bentheelder): refactor this
@@ -316,7 +854,9 @@ func (cc *createContext) provisionNodes() (nodeList map[string]*nodes.Node, err
 // Actions are repetitive, high level abstractions/workflows composed
 // by one or more lower level tasks, that automatically adapt to the
 // current cluster topology
-func (c *Context) exec(cfg *config.Config, derived *derivedConfigData, nodeList map[string]*nodes.Node, actions []string, wait time.Duration) error {
+// If onlyNodes is not empty, planned tasks are executed only on the node
+// replicas with the given names.
+func (c *Context) exec(cfg *config.Config, derived *derivedConfigData, nodeList map[string]*nodes.Node, actions []string, onlyNodes ...string) error {
 	// validate config first
 	if err := cfg.Validate(); err != nil {
 		return err
@@ -324,17 +864,16 @@ func (c *Context) exec(cfg *config.Config, derived *derivedConfigData, nodeList
 
 	// init the exec context and logging
 	ec := &execContext{
-		Context:      c,
-		config:       cfg,
-		derived:      derived,
-		nodes:        nodeList,
-		waitForReady: wait,
+		Context: c,
+		config:  cfg,
+		derived: derived,
+		nodes:   nodeList,
 	}
 
-	ec.status = logutil.NewStatus(os.Stdout)
-	ec.status.MaybeWrapLogrus(log.StandardLogger())
+	ec.status = ec.newStatus()
 
 	defer ec.status.End(false)
+	defer ec.removeTempDir()
 
 	// Create an ExecutionPlan that applies the given actions to the topology defined
 	// in the config
@@ -343,40 +882,165 @@ func (c *Context) exec(cfg *config.Config, derived *derivedConfigData, nodeList
 		return err
 	}
 
+	// the tasks are traced within a span for each action on each node, the
+	// tasks of an action are contiguous in the plan for a node
+	tracer := tracing.Default()
+	var actionSpan *tracing.Span
+	defer func() { actionSpan.Finish(nil) }()
+
 	// Executes all the selected action
-	// TODO(fabrizio pandini): add a flag to a filter PlannedTask by node
-	// (e.g. execute only on this node) or by other criteria tbd
+	// TODO(fabrizio pandini): add a flag to a filter PlannedTask by other
+	// criteria tbd
+	var lastAction, lastNode string
 	for _, plannedTask := range executionPlan {
+		if !plannedFor(plannedTask.Node, onlyNodes) {
+			continue
+		}
+		if plannedTask.actionName != lastAction || plannedTask.Node.Name != lastNode {
+			actionSpan.Finish(nil)
+			actionSpan = tracer.StartSpan("action "+plannedTask.actionName, map[string]string{
+				"kind.action": plannedTask.actionName,
+				"kind.node":   plannedTask.Node.Name,
+			})
+			lastAction, lastNode = plannedTask.actionName, plannedTask.Node.Name
+		}
+		taskSpan := tracer.StartSpan(plannedTask.Task.Description, map[string]string{
+			"kind.action": plannedTask.actionName,
+			"kind.task":   plannedTask.Key(),
+			"kind.node":   plannedTask.Node.Name,
+		})
 		ec.status.Start(fmt.Sprintf("[%s] %s", plannedTask.Node.Name, plannedTask.Task.Description))
+		c.emit(events.Event{
+			Type:    events.TaskStarted,
+			Node:    plannedTask.Node.Name,
+			Task:    plannedTask.Key(),
+			Message: plannedTask.Task.Description,
+		})
 
 		err := plannedTask.Task.Run(ec, plannedTask.Node)
+		taskSpan.Finish(err)
 		if err != nil {
+			actionSpan.Finish(err)
+			actionSpan = nil
 			// in case of error, the execution plan is halted
-			log.Error(err)
+			c.Logger().Errorf("%v", err)
+			c.emit(events.Event{
+				Type:    events.TaskFailed,
+				Node:    plannedTask.Node.Name,
+				Task:    plannedTask.Key(),
+				Message: plannedTask.Task.Description,
+				Error:   err.Error(),
+			})
 			return err
 		}
+		c.emit(events.Event{
+			Type:    events.TaskCompleted,
+			Node:    plannedTask.Node.Name,
+			Task:    plannedTask.Key(),
+			Message: plannedTask.Task.Description,
+		})
+
+		// record the task completion on the node, so that it can be checked
+		// later e.g. by Status
+		if node, ok := ec.NodeFor(plannedTask.Node); ok {
+			if err := node.RecordCompletedTask(plannedTask.Key()); err != nil {
+				c.Logger().Warnf("Failed to record completed task on node %s: %v", plannedTask.Node.Name, err)
+			}
+		}
 	}
 	ec.status.End(true)
 
 	return nil
 }
 
+// TempDir returns the host directory for files shared by the tasks of the
+// execution, creating it if needed, it is removed after the execution
+func (ec *execContext) TempDir() (string, error) {
+	if ec.tempDir != "" {
+		return ec.tempDir, nil
+	}
+	dir, err := fs.TempDir("", "kind-exec")
+	if err != nil {
+		return "", err
+	}
+	ec.tempDir = dir
+	return dir, nil
+}
+
+// removeTempDir removes the directory returned by TempDir, if any
+func (ec *execContext) removeTempDir() {
+	if ec.tempDir == "" {
+		return
+	}
+	if err := os.RemoveAll(ec.tempDir); err != nil {
+		ec.Logger().Warnf("Failed to remove %s: %v", ec.tempDir, err)
+	}
+}
+
+// plannedFor returns true if onlyNodes is empty or contains the node replica name
+func plannedFor(node *nodeReplica, onlyNodes []string) bool {
+	if len(onlyNodes) == 0 {
+		return true
+	}
+	for _, name := range onlyNodes {
+		if node.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (ec *execContext) NodeFor(configNode *nodeReplica) (node *nodes.Node, ok bool) {
 	node, ok = ec.nodes[configNode.Name]
 	return
 }
 
 // Delete tears down a kubernetes-in-docker cluster
+// Protected clusters (see Protect) are not deleted, nor are the clusters of
+// other owners if OwnerEnv is set (see Owner), use ForceDelete instead
 func (c *Context) Delete() error {
+	protected, err := c.IsProtected()
+	if err != nil {
+		return err
+	}
+	if protected {
+		return fmt.Errorf("cluster %q is protected, unprotect it or force the deletion", c.Name())
+	}
+	if err := c.checkOwner(); err != nil {
+		return err
+	}
+	return c.delete()
+}
+
+// ForceDelete tears down a kubernetes-in-docker cluster, even if protected or
+// owned by someone else
+func (c *Context) ForceDelete() error {
+	return c.delete()
+}
+
+func (c *Context) delete() error {
+	// remove the cluster from the kubeconfig it may have been merged into,
+	// this needs the kind kubeconfig file, which is removed with the nodes
+	if err := c.UnmergeKubeConfig(DefaultMergeKubeConfigPath()); err != nil {
+		c.Logger().Warnf("Failed to remove the cluster from %s: %v", DefaultMergeKubeConfigPath(), err)
+	}
+	return c.deleteNodes(false)
+}
+
+// deleteNodes deletes the cluster nodes and network, and unless keepVolumes
+// is set also the node data volumes (see nodes.DataVolumeName)
+func (c *Context) deleteNodes(keepVolumes bool) error {
 	n, err := c.ListNodes()
 	if err != nil {
 		return fmt.Errorf("error listing nodes: %v", err)
 	}
 
-	// try to remove the kind kube config file generated by "kind create cluster"
-	err = os.Remove(c.KubeConfigPath())
-	if err != nil && !os.IsNotExist(err) {
-		log.Warningf("Tried to remove %s but received error: %s\n", c.KubeConfigPath(), err)
+	// try to remove the kind kube config files generated by "kind create cluster"
+	for _, path := range []string{c.KubeConfigPath(), c.InternalKubeConfigPath()} {
+		err = os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			c.Logger().Warnf("Tried to remove %s but received error: %s\n", path, err)
+		}
 	}
 
 	// check if $KUBECONFIG is set and let the user know to unset if so
@@ -386,7 +1050,23 @@ func (c *Context) Delete() error {
 		fmt.Printf("$KUBECONFIG is still set to use %s even though that file has been deleted, remember to unset it\n", c.KubeConfigPath())
 	}
 
-	return nodes.Delete(n...)
+	if err := nodes.Delete(n...); err != nil {
+		return err
+	}
+	if err := c.deleteRegistry(); err != nil {
+		return err
+	}
+	if err := c.deleteNetworks(); err != nil {
+		return err
+	}
+	if keepVolumes {
+		return nil
+	}
+	if err := nodes.DeleteVolumes("label=" + c.ClusterLabel()); err != nil {
+		return err
+	}
+	// the data volumes of claimed pool nodes are labeled with their pool
+	return nodes.DeleteVolumes("label="+consts.PoolKey, "dangling=true")
 }
 
 // ListNodes returns the list of container IDs for the "nodes" in the cluster
@@ -16,7 +16,14 @@ limitations under the License.
 
 package cluster
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/events"
+)
 
 func TestContextValidate(t *testing.T) {
 	cases := []struct {
@@ -54,6 +61,31 @@ func TestContextValidate(t *testing.T) {
 			Name:        "😬",
 			ExpectError: true,
 		},
+		{
+			TestName:    "Invalid name - uppercase is not DNS-safe",
+			Name:        "Foo",
+			ExpectError: true,
+		},
+		{
+			TestName:    "Invalid name - underscores are not DNS-safe",
+			Name:        "foo_bar",
+			ExpectError: true,
+		},
+		{
+			TestName:    "Invalid name - leading dash",
+			Name:        "-foo",
+			ExpectError: true,
+		},
+		{
+			TestName:    "Longest valid name",
+			Name:        strings.Repeat("a", maxNameLength),
+			ExpectError: false,
+		},
+		{
+			TestName:    "Invalid name - too long for the node container names",
+			Name:        strings.Repeat("a", maxNameLength+1),
+			ExpectError: true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -69,3 +101,120 @@ func TestContextValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateActions(t *testing.T) {
+	cases := []struct {
+		TestName      string
+		Networking    config.Networking
+		Ingress       config.Ingress
+		Preload       []string
+		Nodes         []config.Node
+		Snapshots     bool
+		MetricsServer bool
+		Dashboard     bool
+		ExpectCNI     bool
+	}{
+		{
+			TestName:  "default",
+			ExpectCNI: true,
+		},
+		{
+			TestName:   "default CNI disabled",
+			Networking: config.Networking{DisableDefaultCNI: true},
+			ExpectCNI:  false,
+		},
+		{
+			TestName:   "IPv6",
+			Networking: config.Networking{IPFamily: config.IPv6Family},
+			ExpectCNI:  false,
+		},
+		{
+			TestName:  "ingress",
+			Ingress:   config.Ingress{Controller: config.NginxIngressController},
+			ExpectCNI: true,
+		},
+		{
+			TestName:  "preload images",
+			Preload:   []string{"nginx:1.17"},
+			ExpectCNI: true,
+		},
+		{
+			TestName:  "fake nodes",
+			Nodes:     []config.Node{{Role: config.ControlPlaneRole}, {Role: config.FakeRole}},
+			ExpectCNI: true,
+		},
+		{
+			TestName:  "volume snapshots",
+			Snapshots: true,
+			ExpectCNI: true,
+		},
+		{
+			TestName:      "metrics-server and dashboard",
+			MetricsServer: true,
+			Dashboard:     true,
+			ExpectCNI:     true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.TestName, func(t *testing.T) {
+			actions := createActions(&config.Config{
+				Networking:      tc.Networking,
+				Ingress:         tc.Ingress,
+				PreloadImages:   tc.Preload,
+				Nodes:           tc.Nodes,
+				VolumeSnapshots: tc.Snapshots,
+				MetricsServer:   tc.MetricsServer,
+				Dashboard:       tc.Dashboard,
+			})
+			expected := []string{"etcd", "loadbalancer", "config", "init", "join"}
+			if tc.ExpectCNI {
+				expected = []string{"etcd", "loadbalancer", "config", "init", "cni", "join"}
+			}
+			if len(tc.Nodes) > 0 {
+				expected = append(expected, "fake-nodes")
+			}
+			if tc.Snapshots {
+				expected = append(expected, "volume-snapshots")
+			}
+			if tc.Ingress.Controller != "" {
+				expected = append(expected, "ingress")
+			}
+			if tc.MetricsServer {
+				expected = append(expected, "metrics-server")
+			}
+			if tc.Dashboard {
+				expected = append(expected, "dashboard")
+			}
+			if len(tc.Preload) > 0 {
+				expected = append([]string{"preload"}, expected...)
+			}
+			if !reflect.DeepEqual(actions, expected) {
+				t.Errorf("expected actions %v but got %v", expected, actions)
+			}
+			// all the actions should be registered
+			if _, err := newExecutionPlan(&derivedConfigData{}, actions); err != nil {
+				t.Errorf("unexpected error planning actions: %v", err)
+			}
+		})
+	}
+}
+
+func TestContextEmit(t *testing.T) {
+	c := NewContext("emit")
+	// no handler is set, this must not panic
+	c.emit(events.Event{Type: events.ClusterCreateStarted})
+
+	received := []events.Event{}
+	c.SetEventHandler(func(e events.Event) {
+		received = append(received, e)
+	})
+	c.emit(events.Event{Type: events.NodeProvisioned, Node: "worker1"})
+	if len(received) != 1 {
+		t.Fatalf("expected 1 event but got %d", len(received))
+	}
+	e := received[0]
+	if e.Type != events.NodeProvisioned || e.Node != "worker1" || e.Cluster != "emit" || e.Time.IsZero() {
+		t.Errorf("unexpected event %+v", e)
+	}
+}
@@ -19,8 +19,12 @@ package cluster
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+	"sigs.k8s.io/kind/pkg/cluster/loadbalancer"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
 	"sigs.k8s.io/kind/pkg/util"
 
 	"github.com/pkg/errors"
@@ -38,12 +42,17 @@ type derivedConfigData struct {
 	controlPlanes replicaList
 	// workers contains the subset of node replicas with worker role, if any
 	workers replicaList
-	// externalEtcd contains the node replica with external-etcd role, if defined
-	// TODO(fabriziopandini): eventually in future we would like to support
-	// external etcd clusters with more than one member
-	externalEtcd *nodeReplica
+	// externalEtcd contains the subset of node replicas with external-etcd role, if any,
+	// each replica hosts a member of the external etcd cluster
+	externalEtcd replicaList
 	// externalLoadBalancer contains the node replica with external-load-balancer role, if defined
 	externalLoadBalancer *nodeReplica
+	// fakeNodes contains the node replicas with fake role, if any, these
+	// are not in allReplicas because they have no container
+	fakeNodes replicaList
+	// etcdTopology is the etcd topology, either set in the config
+	// or inferred from the external etcd nodes
+	etcdTopology config.EtcdTopology
 }
 
 // nodeReplica defines a `kind` config Node that is geneated by creating a replicas for a node
@@ -84,7 +93,12 @@ func (d *derivedConfigData) Validate() error {
 // ProvisioningOrder returns the provisioning order for nodes, that
 // should be defined according to the assigned NodeRole
 func (n *nodeReplica) ProvisioningOrder() int {
-	switch n.Role {
+	return provisioningOrder(n.Role)
+}
+
+// provisioningOrder returns the provisioning order for the given NodeRole
+func provisioningOrder(role config.NodeRole) int {
+	switch role {
 	// External dependencies should be provisioned first; we are defining an arbitrary
 	// precedence between etcd and load balancer in order to get predictable/repeatable results
 	case config.ExternalEtcdRole:
@@ -134,9 +148,204 @@ func deriveInfo(c *config.Config) (*derivedConfigData, error) {
 		}
 	}
 
+	d.etcdTopology = c.Etcd.Topology
+	d.inferEtcdTopology()
+
+	// the load balancer node runs the image of the load balancer implementation
+	if d.externalLoadBalancer != nil {
+		lb, err := loadbalancer.Get(c.LoadBalancer.Type)
+		if err != nil {
+			return nil, err
+		}
+		d.externalLoadBalancer.Image = lb.Image()
+	}
+
 	return d, nil
 }
 
+// replicaIndex returns the index of replica among the replicas with the same
+// role, starting at 1
+func (d *derivedConfigData) replicaIndex(replica *nodeReplica) int {
+	var replicas replicaList
+	switch {
+	case replica.IsControlPlane():
+		replicas = d.controlPlanes
+	case replica.IsWorker():
+		replicas = d.workers
+	case replica.IsExternalEtcd():
+		replicas = d.externalEtcd
+	}
+	for i, r := range replicas {
+		if r.Name == replica.Name {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+// deriveInfoFromNodes populates DerivedConfig info starting from the
+// node containers of an existing cluster, returning also the config
+// and the map of node handles by replica name, as used by execContext.
+// This is useful for executing actions against clusters created earlier,
+// for which the original config is no longer available.
+func (c *Context) deriveInfoFromNodes(n []nodes.Node) (*config.Config, *derivedConfigData, map[string]*nodes.Node, error) {
+	cfg := &config.Config{}
+	d := &derivedConfigData{}
+	nodeList := map[string]*nodes.Node{}
+
+	prefix := c.nodeContainerName("")
+	for i := range n {
+		node := &n[i]
+		role, err := node.Role()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		image, err := node.Image()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		extraMounts, err := node.ExtraMounts()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		extraPortMappings, err := node.ExtraPortMappings()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		extraDevices, err := node.ExtraDevices()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		ip, ipv6, err := node.StaticAddresses()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		ingressReady, err := node.Label(consts.IngressReadyKey)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		nodeOS, err := node.OS()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		replica := &nodeReplica{
+			Node: config.Node{
+				Role:              config.NodeRole(role),
+				Image:             image,
+				ExtraMounts:       extraMounts,
+				ExtraPortMappings: extraPortMappings,
+				ExtraDevices:      extraDevices,
+				IPAddress:         ip,
+				IPv6Address:       ipv6,
+				IngressReady:      ingressReady == "true",
+			},
+			Name: strings.TrimPrefix(node.String(), prefix),
+		}
+		if nodeOS == string(config.WindowsOS) {
+			replica.OS = config.WindowsOS
+		}
+		cfg.Nodes = append(cfg.Nodes, replica.Node)
+		nodeList[replica.Name] = node
+
+		// the config labels are the same for all the nodes, see configLabels
+		ipFamily, err := node.Label(consts.IPFamilyKey)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if ipFamily != "" {
+			cfg.Networking.IPFamily = config.IPFamily(ipFamily)
+		}
+		defaultCNI, err := node.Label(consts.DefaultCNIKey)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if defaultCNI == "false" {
+			cfg.Networking.DisableDefaultCNI = true
+		}
+		certSANs, err := node.Label(consts.APIServerCertSANsKey)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		cfg.Networking.APIServerCertSANs = splitLabelList(certSANs)
+		preloadImages, err := node.Label(consts.PreloadImagesKey)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		cfg.PreloadImages = splitLabelList(preloadImages)
+		if cfg.ImageStore.Volume, err = node.Label(consts.ImageStoreKey); err != nil {
+			return nil, nil, nil, err
+		}
+		registry, err := node.Label(consts.RegistryKey)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		cfg.Registry.Enabled = registry != ""
+		network, err := node.Network()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		cfg.Networking.DockerNetwork.Name = network
+		dns, err := node.DNS()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		cfg.Networking.DNS = dns
+
+		// adds the replica to the list of nodes, respecting roles
+		d.allReplicas = append(d.allReplicas, replica)
+		switch {
+		case replica.IsControlPlane():
+			d.controlPlanes = append(d.controlPlanes, replica)
+		case replica.IsWorker():
+			d.workers = append(d.workers, replica)
+		case replica.IsExternalEtcd():
+			d.externalEtcd = append(d.externalEtcd, replica)
+		case replica.IsExternalLoadBalancer():
+			d.externalLoadBalancer = replica
+			lbType, err := node.Label(consts.LoadBalancerTypeKey)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			cfg.LoadBalancer.Type = config.LoadBalancerType(lbType)
+		}
+	}
+
+	// ensure all the lists of nodes are ordered, in particular so that
+	// the bootstrap control plane is the first one
+	sort.Sort(d.allReplicas)
+	sort.Sort(d.controlPlanes)
+	sort.Sort(d.workers)
+	sort.Sort(d.externalEtcd)
+	d.inferEtcdTopology()
+
+	// the API server is published on the external load balancer if any,
+	// or the bootstrap control plane otherwise
+	if apiServer := d.ExternalLoadBalancer(); apiServer != nil || d.BootStrapControlPlane() != nil {
+		if apiServer == nil {
+			apiServer = d.BootStrapControlPlane()
+		}
+		address, port, err := nodeList[apiServer.Name].APIServerBinding()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		cfg.Networking.APIServerAddress = address
+		cfg.Networking.APIServerPort = port
+	}
+
+	// the MTU is recorded only in the docker network
+	if network := cfg.Networking.DockerNetwork.Name; network != "" {
+		mtu, err := networkMTU(network)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if mtu != defaultMTU {
+			cfg.Networking.MTU = mtu
+		}
+	}
+
+	return cfg, d, nodeList, nil
+}
+
 // Add a Node to the `kind` cluster, generating requested node replicas
 // and assigning a unique node name to each replica.
 func (d *derivedConfigData) Add(node *config.Node) error {
@@ -160,6 +369,13 @@ func (d *derivedConfigData) Add(node *config.Node) error {
 	// adds replica to the config unpdating derivedConfigData
 	for _, replica := range replicas {
 
+		// fake nodes are simulated in the control plane, without container
+		if replica.IsFake() {
+			replica.Name = "fake"
+			d.fakeNodes = append(d.fakeNodes, replica)
+			continue
+		}
+
 		// adds the replica to the list of nodes
 		d.allReplicas = append(d.allReplicas, replica)
 
@@ -179,15 +395,12 @@ func (d *derivedConfigData) Add(node *config.Node) error {
 			d.workers = append(d.workers, replica)
 		}
 
-		// node with external etcd role
+		// list of nodes with external etcd role
 		if replica.IsExternalEtcd() {
-			if d.externalEtcd != nil {
-				return errors.Errorf("invalid config. there are two nodes with role %q", config.ExternalEtcdRole)
-			}
 			// assign selected name for etcd node
 			replica.Name = "etcd"
 			// stores the node in derivedConfigData
-			d.externalEtcd = replica
+			d.externalEtcd = append(d.externalEtcd, replica)
 		}
 
 		// node with external load balancer role
@@ -217,6 +430,20 @@ func (d *derivedConfigData) Add(node *config.Node) error {
 		}
 	}
 
+	// if more than one external etcd node exists, fixes names to get a progressive index
+	if len(d.externalEtcd) > 1 {
+		for i, n := range d.externalEtcd {
+			n.Name = fmt.Sprintf("%s%d", "etcd", i+1)
+		}
+	}
+
+	// if more than one fake node exists, fixes names to get a progressive index
+	if len(d.fakeNodes) > 1 {
+		for i, n := range d.fakeNodes {
+			n.Name = fmt.Sprintf("%s%d", "fake", i+1)
+		}
+	}
+
 	// ensure the list of nodes is ordered.
 	// the ordering is key for getting a consistent and predictable behaviour
 	// when provisioning nodes and when executing actions on nodes
@@ -225,6 +452,18 @@ func (d *derivedConfigData) Add(node *config.Node) error {
 	return nil
 }
 
+// inferEtcdTopology sets the etcd topology from the external etcd nodes,
+// if not set explicitly
+func (d *derivedConfigData) inferEtcdTopology() {
+	if d.etcdTopology != "" {
+		return
+	}
+	d.etcdTopology = config.StackedEtcdTopology
+	if len(d.externalEtcd) > 0 {
+		d.etcdTopology = config.ExternalEtcdTopology
+	}
+}
+
 // AllReplicas returns all the node replicas defined in the `kind` Config.
 func (d *derivedConfigData) AllReplicas() replicaList {
 	return d.allReplicas
@@ -253,16 +492,50 @@ func (d *derivedConfigData) SecondaryControlPlanes() replicaList {
 	return d.controlPlanes[1:]
 }
 
+// FakeNodes returns all the nodes with fake role, if any, these have no
+// container
+func (d *derivedConfigData) FakeNodes() replicaList {
+	return d.fakeNodes
+}
+
 // Workers returns all the nodes with Worker role, if any
 func (d *derivedConfigData) Workers() replicaList {
 	return d.workers
 }
 
-// ExternalEtcd returns the node with external-etcd role, if defined
-func (d *derivedConfigData) ExternalEtcd() *nodeReplica {
+// LinuxWorkers returns all the nodes with Worker role running Linux, if any
+func (d *derivedConfigData) LinuxWorkers() replicaList {
+	workers := replicaList{}
+	for _, worker := range d.workers {
+		if !worker.IsWindows() {
+			workers = append(workers, worker)
+		}
+	}
+	return workers
+}
+
+// WindowsWorkers returns all the nodes with Worker role running Windows, if any
+func (d *derivedConfigData) WindowsWorkers() replicaList {
+	workers := replicaList{}
+	for _, worker := range d.workers {
+		if worker.IsWindows() {
+			workers = append(workers, worker)
+		}
+	}
+	return workers
+}
+
+// ExternalEtcd returns all the nodes with external-etcd role, if any
+func (d *derivedConfigData) ExternalEtcd() replicaList {
 	return d.externalEtcd
 }
 
+// EtcdTopology returns the etcd topology, this determines if etcd runs
+// on the control-plane nodes or on the external etcd nodes
+func (d *derivedConfigData) EtcdTopology() config.EtcdTopology {
+	return d.etcdTopology
+}
+
 // ExternalLoadBalancer returns the node with external-load-balancer role, if defined
 func (d *derivedConfigData) ExternalLoadBalancer() *nodeReplica {
 	return d.externalLoadBalancer
@@ -32,8 +32,9 @@ func TestDeriveInfo(t *testing.T) {
 		ExpectBootStrapControlPlane  *string
 		ExpectSecondaryControlPlanes []string
 		ExpectWorkers                []string
-		ExpectEtcd                   *string
+		ExpectEtcd                   []string
 		ExpectLoadBalancer           *string
+		ExpectFake                   []string
 		ExpectError                  bool
 	}{
 		{
@@ -85,7 +86,7 @@ func TestDeriveInfo(t *testing.T) {
 			ExpectBootStrapControlPlane:  utilpointer.StringPtr("control-plane1"),
 			ExpectSecondaryControlPlanes: []string{"control-plane2", "control-plane3"},
 			ExpectWorkers:                []string{"worker1", "worker2"},
-			ExpectEtcd:                   utilpointer.StringPtr("etcd"),
+			ExpectEtcd:                   []string{"etcd"},
 			ExpectLoadBalancer:           utilpointer.StringPtr("lb"),
 			ExpectError:                  false,
 		},
@@ -102,17 +103,33 @@ func TestDeriveInfo(t *testing.T) {
 			ExpectBootStrapControlPlane:  utilpointer.StringPtr("control-plane1"),
 			ExpectSecondaryControlPlanes: []string{"control-plane2", "control-plane3"},
 			ExpectWorkers:                []string{"worker1", "worker2"},
-			ExpectEtcd:                   utilpointer.StringPtr("etcd"),
+			ExpectEtcd:                   []string{"etcd"},
 			ExpectLoadBalancer:           utilpointer.StringPtr("lb"),
 			ExpectError:                  false,
 		},
 		{
-			TestName: "Fails because two etcds Nodes are added",
+			TestName: "External etcd Nodes get a progressive index",
 			Nodes: []config.Node{
-				{Role: config.ExternalEtcdRole},
-				{Role: config.ExternalEtcdRole},
+				{Role: config.ControlPlaneRole},
+				{Role: config.ExternalEtcdRole, Replicas: utilpointer.Int32Ptr(3)},
 			},
-			ExpectError: true,
+			ExpectReplicas:              []string{"etcd1", "etcd2", "etcd3", "control-plane"},
+			ExpectControlPlanes:         []string{"control-plane"},
+			ExpectBootStrapControlPlane: utilpointer.StringPtr("control-plane"),
+			ExpectEtcd:                  []string{"etcd1", "etcd2", "etcd3"},
+			ExpectError:                 false,
+		},
+		{
+			TestName: "Fake Nodes get a progressive index and no container",
+			Nodes: []config.Node{
+				{Role: config.FakeRole, Replicas: utilpointer.Int32Ptr(2)},
+				{Role: config.ControlPlaneRole},
+			},
+			ExpectReplicas:              []string{"control-plane"},
+			ExpectControlPlanes:         []string{"control-plane"},
+			ExpectBootStrapControlPlane: utilpointer.StringPtr("control-plane"),
+			ExpectFake:                  []string{"fake1", "fake2"},
+			ExpectError:                 false,
 		},
 		{
 			TestName: "Fails because two load balancer Nodes are added",
@@ -152,8 +169,9 @@ func TestDeriveInfo(t *testing.T) {
 			checkNode(t, derived.BootStrapControlPlane(), c.ExpectBootStrapControlPlane)
 			checkReplicaList(t, derived.SecondaryControlPlanes(), c.ExpectSecondaryControlPlanes)
 			checkReplicaList(t, derived.Workers(), c.ExpectWorkers)
-			checkNode(t, derived.ExternalEtcd(), c.ExpectEtcd)
+			checkReplicaList(t, derived.ExternalEtcd(), c.ExpectEtcd)
 			checkNode(t, derived.ExternalLoadBalancer(), c.ExpectLoadBalancer)
+			checkReplicaList(t, derived.FakeNodes(), c.ExpectFake)
 		})
 	}
 }
@@ -184,3 +202,52 @@ func checkReplicaList(t *testing.T, list replicaList, names []string) {
 		}
 	}
 }
+
+func TestEtcdTopology(t *testing.T) {
+	cases := []struct {
+		TestName       string
+		Config         config.Config
+		ExpectTopology config.EtcdTopology
+		ExpectEtcd     []string
+	}{
+		{
+			TestName:       "Stacked etcd is inferred without external etcd nodes",
+			Config:         config.Config{Nodes: []config.Node{{Role: config.ControlPlaneRole}}},
+			ExpectTopology: config.StackedEtcdTopology,
+		},
+		{
+			TestName: "External etcd is inferred with external etcd nodes",
+			Config: config.Config{Nodes: []config.Node{
+				{Role: config.ControlPlaneRole},
+				{Role: config.ExternalEtcdRole},
+			}},
+			ExpectTopology: config.ExternalEtcdTopology,
+			ExpectEtcd:     []string{"etcd"},
+		},
+		{
+			TestName: "Explicit topology is respected",
+			Config: config.Config{
+				Nodes: []config.Node{
+					{Role: config.ControlPlaneRole},
+					{Role: config.ExternalEtcdRole},
+				},
+				Etcd: config.Etcd{Topology: config.StackedEtcdTopology},
+			},
+			ExpectTopology: config.StackedEtcdTopology,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.TestName, func(t *testing.T) {
+			derived, err := deriveInfo(&c.Config)
+			if err != nil {
+				t.Fatalf("unexpected error while Deriving infos: %v", err)
+			}
+			if derived.EtcdTopology() != c.ExpectTopology {
+				t.Errorf("expected etcd topology %q, saw %q", c.ExpectTopology, derived.EtcdTopology())
+			}
+			// external etcd tasks are only planned for the external topology
+			checkReplicaList(t, selectExternalEtcdNodes(derived), c.ExpectEtcd)
+		})
+	}
+}
@@ -15,4 +15,8 @@ limitations under the License.
 */
 
 // Package cluster implements kind kubernetes-in-docker cluster management
+//
+// Programs embedding kind, e.g. test frameworks, should use Provider, which
+// creates, deletes and lists clusters like the kind CLI without shelling out
+// to it. Context exposes the other cluster operations.
 package cluster
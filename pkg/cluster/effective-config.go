@@ -0,0 +1,130 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"reflect"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/config/v1alpha3"
+	"sigs.k8s.io/kind/pkg/kustomize"
+)
+
+// EffectiveConfig is the fully resolved configuration of a cluster, after
+// defaulting and derivation, see Context.EffectiveConfig.
+// The fields use the names of the latest config API version
+type EffectiveConfig struct {
+	// Name is the cluster context name
+	Name string `json:"name"`
+	// EtcdTopology is the etcd topology, either set or inferred
+	EtcdTopology config.EtcdTopology `json:"etcdTopology"`
+	// LoadBalancerType is the type of the external load balancer, if any
+	LoadBalancerType config.LoadBalancerType `json:"loadBalancerType,omitempty"`
+	// Networking is the cluster networking config, if any
+	Networking *v1alpha3.Networking `json:"networking,omitempty"`
+	// BootstrapControlPlane is the name of the node where kubeadm init runs
+	BootstrapControlPlane string `json:"bootstrapControlPlane,omitempty"`
+	// KubeadmConfigPatches are the cluster-wide kubeadm config patches
+	KubeadmConfigPatches []string `json:"kubeadmConfigPatches,omitempty"`
+	// KubeadmConfigPatchesJSON6902 are the cluster-wide kubeadm config JSON patches
+	KubeadmConfigPatchesJSON6902 []kustomize.PatchJSON6902 `json:"kubeadmConfigPatchesJson6902,omitempty"`
+	// FeatureGates are the cluster-wide feature gates
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// RuntimeConfig is the cluster-wide API server runtime config
+	RuntimeConfig map[string]string `json:"runtimeConfig,omitempty"`
+	// Nodes are the node replicas, in provisioning order
+	Nodes []EffectiveNode `json:"nodes"`
+}
+
+// EffectiveNode is the resolved configuration of a node replica
+type EffectiveNode struct {
+	// Name is the node container name
+	Name string `json:"name"`
+	// ProvisioningOrder is the order in which the node is provisioned,
+	// nodes with the same order are provisioned by name
+	ProvisioningOrder int `json:"provisioningOrder"`
+	// Node is the node replica config
+	v1alpha3.Node
+}
+
+// EffectiveConfig returns the fully resolved configuration kind uses for
+// creating the cluster from cfg, including the node replica names and images.
+// If cfg is nil, the configuration of the existing cluster is returned
+// instead, as far as it can be inspected from its node containers
+func (c *Context) EffectiveConfig(cfg *config.Config) (*EffectiveConfig, error) {
+	var derived *derivedConfigData
+	if cfg != nil {
+		var err error
+		derived, err = validateForCreate(cfg)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		n, err := c.ListNodes()
+		if err != nil {
+			return nil, fmt.Errorf("error listing nodes: %v", err)
+		}
+		if len(n) == 0 {
+			return nil, fmt.Errorf("no nodes found for cluster %q", c.Name())
+		}
+		cfg, derived, _, err = c.deriveInfoFromNodes(n)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.effectiveConfig(cfg, derived)
+}
+
+// effectiveConfig implements EffectiveConfig for the derived info of cfg
+func (c *Context) effectiveConfig(cfg *config.Config, derived *derivedConfigData) (*EffectiveConfig, error) {
+	effective := &EffectiveConfig{
+		Name:                         c.Name(),
+		EtcdTopology:                 derived.EtcdTopology(),
+		KubeadmConfigPatches:         cfg.KubeadmConfigPatches,
+		KubeadmConfigPatchesJSON6902: cfg.KubeadmConfigPatchesJSON6902,
+		FeatureGates:                 cfg.FeatureGates,
+		RuntimeConfig:                cfg.RuntimeConfig,
+		Nodes:                        []EffectiveNode{},
+	}
+	if derived.ExternalLoadBalancer() != nil {
+		effective.LoadBalancerType = cfg.LoadBalancer.Type
+		if effective.LoadBalancerType == "" {
+			effective.LoadBalancerType = config.HAProxyLoadBalancer
+		}
+	}
+	if !reflect.DeepEqual(cfg.Networking, config.Networking{}) {
+		effective.Networking = &v1alpha3.Networking{}
+		if err := v1alpha3.Convert_config_Networking_To_v1alpha3_Networking(&cfg.Networking, effective.Networking, nil); err != nil {
+			return nil, err
+		}
+	}
+	if node := derived.BootStrapControlPlane(); node != nil {
+		effective.BootstrapControlPlane = c.nodeContainerName(node.Name)
+	}
+	for _, replica := range derived.AllReplicas() {
+		node := EffectiveNode{
+			Name:              c.nodeContainerName(replica.Name),
+			ProvisioningOrder: replica.ProvisioningOrder(),
+		}
+		if err := v1alpha3.Convert_config_Node_To_v1alpha3_Node(&replica.Node, &node.Node, nil); err != nil {
+			return nil, err
+		}
+		effective.Nodes = append(effective.Nodes, node)
+	}
+	return effective, nil
+}
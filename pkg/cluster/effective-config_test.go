@@ -0,0 +1,75 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	utilpointer "k8s.io/utils/pointer"
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestEffectiveConfig(t *testing.T) {
+	cfg := &config.Config{
+		Nodes: []config.Node{
+			{Role: config.WorkerRole, Image: "worker:latest", Replicas: utilpointer.Int32Ptr(2)},
+			{Role: config.ControlPlaneRole, Image: "node:latest", Labels: map[string]string{"tier": "cp"}},
+			{Role: config.ExternalLoadBalancerRole, Image: "node:latest"},
+		},
+		FeatureGates: map[string]bool{"CSIBlockVolume": true},
+	}
+	derived, err := deriveInfo(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error deriving info: %v", err)
+	}
+	effective, err := NewContext("test").effectiveConfig(cfg, derived)
+	if err != nil {
+		t.Fatalf("unexpected error resolving the config: %v", err)
+	}
+
+	if effective.Name != "test" || effective.EtcdTopology != config.StackedEtcdTopology ||
+		effective.LoadBalancerType != config.HAProxyLoadBalancer ||
+		effective.BootstrapControlPlane != "kind-test-control-plane" ||
+		!effective.FeatureGates["CSIBlockVolume"] {
+		t.Errorf("unexpected cluster settings in %+v", effective)
+	}
+
+	names := []string{}
+	images := []string{}
+	orders := []int{}
+	for _, node := range effective.Nodes {
+		names = append(names, node.Name)
+		images = append(images, node.Image)
+		orders = append(orders, node.ProvisioningOrder)
+	}
+	expectNames := []string{"kind-test-lb", "kind-test-control-plane", "kind-test-worker1", "kind-test-worker2"}
+	if !reflect.DeepEqual(names, expectNames) {
+		t.Errorf("expected nodes %v, got %v", expectNames, names)
+	}
+	// the load balancer image is derived from the load balancer type
+	if images[0] == "node:latest" || images[1] != "node:latest" || images[2] != "worker:latest" {
+		t.Errorf("unexpected node images %v", images)
+	}
+	expectOrders := []int{2, 3, 4, 4}
+	if !reflect.DeepEqual(orders, expectOrders) {
+		t.Errorf("expected provisioning orders %v, got %v", expectOrders, orders)
+	}
+	if effective.Nodes[1].Labels["tier"] != "cp" || effective.Nodes[2].Replicas != nil {
+		t.Errorf("unexpected node settings in %+v", effective.Nodes)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+)
+
+// encryptionKeySize is the size in bytes of the generated encryption keys,
+// as required by the aescbc, aesgcm and secretbox providers
+const encryptionKeySize = 32
+
+// kmsPluginEndpoint is the endpoint of the KMS plugin of the kms provider
+const kmsPluginEndpoint = "unix://" + kubeadm.KMSPluginDir + "/socket.sock"
+
+// encryptionConfig returns the API server encryption provider config of
+// encryption for the kubernetes version, either inline or generated with a
+// random key. The generated config falls back to the identity provider, so
+// that the resources written before enabling the encryption remain readable
+func encryptionConfig(encryption *config.Encryption, kubeVersion string) ([]byte, error) {
+	if encryption.Config != "" {
+		return []byte(encryption.Config), nil
+	}
+
+	// the encryption config API is experimental in older versions
+	ver, err := version.ParseGeneric(kubeVersion)
+	if err != nil {
+		return nil, err
+	}
+	apiVersion, kind := "apiserver.config.k8s.io/v1", "EncryptionConfiguration"
+	if !ver.AtLeast(version.MustParseSemantic("v1.13.0")) {
+		apiVersion, kind = "v1", "EncryptionConfig"
+	}
+
+	var provider map[string]interface{}
+	switch encryption.Provider {
+	case config.KMSEncryptionProvider:
+		provider = map[string]interface{}{
+			"name":      encryption.KMSPluginName,
+			"endpoint":  kmsPluginEndpoint,
+			"cachesize": 1000,
+		}
+	default:
+		key := make([]byte, encryptionKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, errors.Wrap(err, "failed to generate the encryption key")
+		}
+		provider = map[string]interface{}{
+			"keys": []map[string]string{{
+				"name":   "key1",
+				"secret": base64.StdEncoding.EncodeToString(key),
+			}},
+		}
+	}
+
+	resources := encryption.Resources
+	if len(resources) == 0 {
+		resources = []string{"secrets"}
+	}
+	return yaml.Marshal(map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"resources": []map[string]interface{}{{
+			"resources": resources,
+			"providers": []map[string]interface{}{
+				{string(encryption.Provider): provider},
+				{"identity": map[string]interface{}{}},
+			},
+		}},
+	})
+}
+
+// writeEncryptionConfig writes the API server encryption provider config of
+// the cluster, if any, to the control-plane node, where it is mounted into
+// the API server, see kubeadm.ConfigData.Encryption. An existing config is
+// kept, so that the resources encrypted with its keys remain readable
+func (ec *execContext) writeEncryptionConfig(configNode *nodeReplica, kubeVersion string) error {
+	if !ec.config.Encryption.Enabled() {
+		return nil
+	}
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return errors.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+	if node.Command("test", "-f", kubeadm.EncryptionConfigFile).Run() == nil {
+		return nil
+	}
+	encryptionConfig, err := encryptionConfig(&ec.config.Encryption, kubeVersion)
+	if err != nil {
+		return err
+	}
+	if err := node.WriteFile(kubeadm.EncryptionConfigFile, encryptionConfig); err != nil {
+		return errors.Wrap(err, "failed to write the encryption config to the node")
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestEncryptionConfig(t *testing.T) {
+	cases := []struct {
+		Name               string
+		Encryption         config.Encryption
+		KubeVersion        string
+		ExpectedAPIVersion string
+		ExpectedResources  []string
+		ExpectedProvider   string
+	}{
+		{
+			Name:               "aescbc",
+			Encryption:         config.Encryption{Provider: config.AESCBCEncryptionProvider},
+			KubeVersion:        "v1.14.1",
+			ExpectedAPIVersion: "apiserver.config.k8s.io/v1",
+			ExpectedResources:  []string{"secrets"},
+			ExpectedProvider:   "aescbc",
+		},
+		{
+			Name:               "secretbox with resources on an older version",
+			Encryption:         config.Encryption{Provider: config.SecretboxEncryptionProvider, Resources: []string{"secrets", "configmaps"}},
+			KubeVersion:        "v1.12.3",
+			ExpectedAPIVersion: "v1",
+			ExpectedResources:  []string{"secrets", "configmaps"},
+			ExpectedProvider:   "secretbox",
+		},
+		{
+			Name:               "kms",
+			Encryption:         config.Encryption{Provider: config.KMSEncryptionProvider, KMSPluginName: "mock"},
+			KubeVersion:        "v1.14.1",
+			ExpectedAPIVersion: "apiserver.config.k8s.io/v1",
+			ExpectedResources:  []string{"secrets"},
+			ExpectedProvider:   "kms",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			data, err := encryptionConfig(&tc.Encryption, tc.KubeVersion)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			parsed := struct {
+				APIVersion string `json:"apiVersion"`
+				Resources  []struct {
+					Resources []string `json:"resources"`
+					Providers []map[string]struct {
+						Name     string `json:"name"`
+						Endpoint string `json:"endpoint"`
+						Keys     []struct {
+							Secret string `json:"secret"`
+						} `json:"keys"`
+					} `json:"providers"`
+				} `json:"resources"`
+			}{}
+			if err := yaml.Unmarshal(data, &parsed); err != nil {
+				t.Fatalf("failed to parse the config: %v\n%s", err, data)
+			}
+			if parsed.APIVersion != tc.ExpectedAPIVersion {
+				t.Errorf("expected apiVersion %q but got %q", tc.ExpectedAPIVersion, parsed.APIVersion)
+			}
+			if len(parsed.Resources) != 1 || len(parsed.Resources[0].Providers) != 2 {
+				t.Fatalf("expected one resources entry with two providers, got:\n%s", data)
+			}
+			resources := parsed.Resources[0]
+			if len(resources.Resources) != len(tc.ExpectedResources) {
+				t.Errorf("expected resources %v but got %v", tc.ExpectedResources, resources.Resources)
+			}
+			provider, ok := resources.Providers[0][tc.ExpectedProvider]
+			if !ok {
+				t.Fatalf("expected the %s provider first, got:\n%s", tc.ExpectedProvider, data)
+			}
+			if _, ok := resources.Providers[1]["identity"]; !ok {
+				t.Errorf("expected the identity provider last, got:\n%s", data)
+			}
+			if tc.ExpectedProvider == "kms" {
+				if provider.Name != tc.Encryption.KMSPluginName || provider.Endpoint != kmsPluginEndpoint {
+					t.Errorf("unexpected kms provider: %+v", provider)
+				}
+				return
+			}
+			if len(provider.Keys) != 1 {
+				t.Fatalf("expected one key, got:\n%s", data)
+			}
+			key, err := base64.StdEncoding.DecodeString(provider.Keys[0].Secret)
+			if err != nil || len(key) != encryptionKeySize {
+				t.Errorf("expected a %d bytes base64 key, got %q", encryptionKeySize, provider.Keys[0].Secret)
+			}
+		})
+	}
+}
+
+func TestEncryptionConfigInline(t *testing.T) {
+	inline := "apiVersion: apiserver.config.k8s.io/v1\nkind: EncryptionConfiguration\n"
+	data, err := encryptionConfig(&config.Encryption{Config: inline}, "v1.14.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != inline {
+		t.Errorf("expected the inline config to be used as is, got:\n%s", data)
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The errors for the common failure modes of the cluster operations, use Is
+// to check for them, and As to get the typed errors with the details
+var (
+	// ErrClusterExists is the cause of creating a cluster whose nodes
+	// already exist, see ClusterExistsError
+	ErrClusterExists = stderrors.New("cluster already exists")
+	// ErrNameCollision is the cause of creating a cluster whose node
+	// container or network names are taken, see NameCollisionError
+	ErrNameCollision = stderrors.New("cluster name collision")
+	// ErrNodeNotReady is the cause of a node not becoming ready in time, see
+	// NodeNotReadyError and ClusterNotReadyError
+	ErrNodeNotReady = stderrors.New("node not ready")
+	// ErrImagePull is the cause of failing to pull an image, see
+	// ImagePullError
+	ErrImagePull = stderrors.New("failed to pull image")
+	// ErrKubeadmFailed is the cause of kubeadm failing on a node, see
+	// KubeadmError
+	ErrKubeadmFailed = stderrors.New("kubeadm failed")
+)
+
+// ClusterExistsError is returned when creating a cluster whose nodes already
+// exist
+type ClusterExistsError struct {
+	// Name is the cluster context name
+	Name string
+}
+
+func (e *ClusterExistsError) Error() string {
+	return fmt.Sprintf("cluster %q already exists", e.Name)
+}
+
+// Is returns true for ErrClusterExists
+func (e *ClusterExistsError) Is(target error) bool {
+	return target == ErrClusterExists
+}
+
+// NameCollisionError is returned when creating a cluster whose node
+// container or network names are taken by docker objects of other clusters,
+// or not created by kind, e.g. left over by an interrupted deletion
+type NameCollisionError struct {
+	// Name is the cluster context name
+	Name string
+	// Containers are the names of the colliding containers, if any
+	Containers []string
+	// Networks are the names of the colliding networks, if any
+	Networks []string
+}
+
+func (e *NameCollisionError) Error() string {
+	taken := []string{}
+	if len(e.Containers) > 0 {
+		taken = append(taken, "containers "+strings.Join(e.Containers, ", "))
+	}
+	if len(e.Networks) > 0 {
+		taken = append(taken, "networks "+strings.Join(e.Networks, ", "))
+	}
+	return fmt.Sprintf("the names of cluster %q are taken by the %s", e.Name, strings.Join(taken, " and the "))
+}
+
+// Is returns true for ErrNameCollision
+func (e *NameCollisionError) Is(target error) bool {
+	return target == ErrNameCollision
+}
+
+// NodeNotReadyError is returned when a node does not become ready in time
+type NodeNotReadyError struct {
+	// Node is the node container name
+	Node string
+	// Condition is what the node did not become ready for, e.g.
+	// "docker to be ready"
+	Condition string
+}
+
+func (e *NodeNotReadyError) Error() string {
+	return fmt.Sprintf("timed out waiting for %s on node %s", e.Condition, e.Node)
+}
+
+// Is returns true for ErrNodeNotReady
+func (e *NodeNotReadyError) Is(target error) bool {
+	return target == ErrNodeNotReady
+}
+
+// ImagePullError is returned when an image can not be pulled
+type ImagePullError struct {
+	// Image is the image that could not be pulled
+	Image string
+	// Err is the error pulling the image
+	Err error
+}
+
+func (e *ImagePullError) Error() string {
+	return fmt.Sprintf("failed to pull image %s: %v", e.Image, e.Err)
+}
+
+// Is returns true for ErrImagePull
+func (e *ImagePullError) Is(target error) bool {
+	return target == ErrImagePull
+}
+
+// Unwrap returns the error pulling the image
+func (e *ImagePullError) Unwrap() error {
+	return e.Err
+}
+
+// KubeadmError is returned when kubeadm fails on a node
+type KubeadmError struct {
+	// Node is the node container name
+	Node string
+	// Args are the kubeadm arguments, e.g. init --config=...
+	Args []string
+	// Output is the combined stdout and stderr of kubeadm
+	Output string
+	// Err is the error running kubeadm
+	Err error
+}
+
+func (e *KubeadmError) Error() string {
+	return fmt.Sprintf("kubeadm %s failed on node %s: %v", strings.Join(e.Args, " "), e.Node, e.Err)
+}
+
+// Is returns true for ErrKubeadmFailed
+func (e *KubeadmError) Is(target error) bool {
+	return target == ErrKubeadmFailed
+}
+
+// Unwrap returns the error running kubeadm
+func (e *KubeadmError) Unwrap() error {
+	return e.Err
+}
+
+// ClusterNotReadyError is returned when the cluster does not become ready
+// within the wait of Create
+type ClusterNotReadyError struct {
+	// Wait is how long the cluster was waited for
+	Wait time.Duration
+	// Pending are the readiness conditions that were not met, e.g.
+	// "node kind-1-worker is not Ready"
+	Pending []string
+	// Diagnostics is the state of the nodes and of the system pods
+	Diagnostics string
+}
+
+func (e *ClusterNotReadyError) Error() string {
+	return fmt.Sprintf("timed out after %s waiting for the cluster to be ready: %s\n\n%s",
+		e.Wait, strings.Join(e.Pending, ", "), e.Diagnostics)
+}
+
+// Is returns true for ErrNodeNotReady
+func (e *ClusterNotReadyError) Is(target error) bool {
+	return target == ErrNodeNotReady
+}
+
+// Is returns true if err or any error it wraps is target, like errors.Is
+// but following the errors wrapped with github.com/pkg/errors too, which
+// kind uses and errors.Is does not follow
+func Is(err, target error) bool {
+	for ; err != nil; err = next(err) {
+		if stderrors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error in err or the errors it wraps that matches
+// target, like errors.As but following the errors wrapped with
+// github.com/pkg/errors too, e.g.
+//
+//	var kubeadmErr *cluster.KubeadmError
+//	if cluster.As(err, &kubeadmErr) {
+//		fmt.Println(kubeadmErr.Output)
+//	}
+func As(err error, target interface{}) bool {
+	for ; err != nil; err = next(err) {
+		if stderrors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// next returns the error wrapped by err, if any, either as in errors.Unwrap
+// or with github.com/pkg/errors
+func next(err error) error {
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	case interface{ Cause() error }:
+		return e.Cause()
+	}
+	return nil
+}
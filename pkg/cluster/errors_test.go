@@ -0,0 +1,95 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestErrorsIsAs(t *testing.T) {
+	kubeadmErr := &KubeadmError{
+		Node:   "kind-1-control-plane",
+		Args:   []string{"init"},
+		Output: "[preflight] failed",
+		Err:    stderrors.New("exit status 1"),
+	}
+	cases := []struct {
+		TestName string
+		Err      error
+		Target   error
+		Expected bool
+	}{
+		{
+			TestName: "Wrapped with pkg/errors",
+			Err:      errors.Wrap(errors.Wrap(kubeadmErr, "failed to init node"), "failed to create cluster"),
+			Target:   ErrKubeadmFailed,
+			Expected: true,
+		},
+		{
+			TestName: "Wrapped with fmt",
+			Err:      fmt.Errorf("failed to create cluster: %w", errors.Wrap(&ClusterExistsError{Name: "1"}, "import")),
+			Target:   ErrClusterExists,
+			Expected: true,
+		},
+		{
+			TestName: "Name collision",
+			Err:      errors.Wrap(&NameCollisionError{Name: "1", Containers: []string{"kind-1-control-plane"}}, "failed"),
+			Target:   ErrNameCollision,
+			Expected: true,
+		},
+		{
+			TestName: "Other failure mode",
+			Err:      errors.Wrap(&NodeNotReadyError{Node: "kind-1-worker", Condition: "docker to be ready"}, "failed"),
+			Target:   ErrImagePull,
+			Expected: false,
+		},
+		{
+			TestName: "Cause of a typed error",
+			Err:      &ImagePullError{Image: "kindest/node", Err: errors.Wrap(kubeadmErr, "pull")},
+			Target:   ErrKubeadmFailed,
+			Expected: true,
+		},
+		{
+			TestName: "Cluster not ready",
+			Err:      errors.Wrap(&ClusterNotReadyError{Pending: []string{"node kind-1-worker is not Ready"}}, "failed"),
+			Target:   ErrNodeNotReady,
+			Expected: true,
+		},
+		{
+			TestName: "Nil error",
+			Err:      nil,
+			Target:   ErrNodeNotReady,
+			Expected: false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.TestName, func(t2 *testing.T) {
+			if result := Is(tc.Err, tc.Target); result != tc.Expected {
+				t2.Errorf("expected Is to be %t for %v", tc.Expected, tc.Err)
+			}
+		})
+	}
+
+	var target *KubeadmError
+	if !As(errors.Wrap(kubeadmErr, "failed to init node"), &target) || target.Output != "[preflight] failed" {
+		t.Errorf("expected As to find the KubeadmError")
+	}
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/etcd"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/exec"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+const (
+	// etcdSavedSnapshotPath is the path on the etcd node snapshots are saved
+	// to, this is in the etcd data directory so that it is available both to
+	// the etcd container and the node
+	etcdSavedSnapshotPath = "/var/lib/etcd/kind-snapshot.db"
+	// etcdRestoredSnapshotPath is the path on the etcd nodes snapshots are
+	// restored from
+	etcdRestoredSnapshotPath = "/var/lib/etcd-snapshot.db"
+	// etcdRestoredDataDir is the path on the etcd nodes snapshots are restored
+	// to, before replacing the etcd data directory
+	etcdRestoredDataDir = "/var/lib/etcd-restored"
+	// stackedEtcdManifest is the etcd static pod manifest on the control-plane
+	// nodes, and stackedEtcdManifestBackup is where it is moved to stop etcd
+	stackedEtcdManifest       = "/etc/kubernetes/manifests/etcd.yaml"
+	stackedEtcdManifestBackup = "/kind/etcd.yaml"
+)
+
+// SaveEtcdSnapshot saves a snapshot of the cluster etcd data to the file dest
+// on the host, using etcdctl on the first etcd member, either on the
+// control-plane (stacked etcd) or on the external etcd nodes
+func (c *Context) SaveEtcdSnapshot(dest string) error {
+	etcdNodes, topology, err := c.etcdNodes()
+	if err != nil {
+		return err
+	}
+	node := etcdNodes[0]
+
+	container, err := etcdContainer(node, topology)
+	if err != nil {
+		return err
+	}
+	if err := etcdctl(node, container, "snapshot", "save", etcdSavedSnapshotPath).Run(); err != nil {
+		return errors.Wrap(err, "failed to save etcd snapshot")
+	}
+	defer func() {
+		if err := node.Command("rm", "-f", etcdSavedSnapshotPath).Run(); err != nil {
+			c.Logger().Warnf("Failed to remove etcd snapshot from node %s: %v", node.String(), err)
+		}
+	}()
+
+	if err := node.CopyFrom(etcdSavedSnapshotPath, dest); err != nil {
+		return errors.Wrap(err, "failed to copy etcd snapshot from node")
+	}
+	return nil
+}
+
+// RestoreEtcdSnapshot restores the etcd snapshot in the file source on the
+// host, as saved by SaveEtcdSnapshot, replacing the data of all the etcd
+// members of the cluster. The cluster does not need to be the one the
+// snapshot was saved from, e.g. it can be restored to a fresh cluster.
+func (c *Context) RestoreEtcdSnapshot(source string) error {
+	if _, err := os.Stat(source); err != nil {
+		return err
+	}
+	etcdNodes, topology, err := c.etcdNodes()
+	if err != nil {
+		return err
+	}
+
+	status := logutil.NewStatus(os.Stdout)
+	status.MaybeWrapLogger(c.Logger())
+	defer status.End(false)
+
+	// get the current members, the restored cluster has the same members
+	status.Start("Getting etcd members 🗄")
+	container, err := etcdContainer(etcdNodes[0], topology)
+	if err != nil {
+		return err
+	}
+	peerURLs, err := etcdMemberPeerURLs(etcdNodes[0], container)
+	if err != nil {
+		return err
+	}
+	initialCluster := []string{}
+	for _, node := range etcdNodes {
+		peerURL, ok := peerURLs[node.String()]
+		if !ok {
+			return fmt.Errorf("no etcd member found for node %s", node.String())
+		}
+		initialCluster = append(initialCluster, fmt.Sprintf("%s=%s", node.String(), peerURL))
+	}
+
+	for _, node := range etcdNodes {
+		status.Start(fmt.Sprintf("[%s] Copying etcd snapshot to node 💾", node.String()))
+		if err := node.CopyTo(source, etcdRestoredSnapshotPath); err != nil {
+			return errors.Wrap(err, "failed to copy etcd snapshot to node")
+		}
+	}
+
+	// all the members must be stopped before restoring any of them
+	for _, node := range etcdNodes {
+		status.Start(fmt.Sprintf("[%s] Stopping etcd ⏸", node.String()))
+		if err := stopEtcd(node, topology); err != nil {
+			return err
+		}
+	}
+
+	for _, node := range etcdNodes {
+		status.Start(fmt.Sprintf("[%s] Restoring etcd snapshot 🗄", node.String()))
+		if err := restoreEtcd(node, peerURLs[node.String()], strings.Join(initialCluster, ",")); err != nil {
+			return err
+		}
+	}
+
+	for _, node := range etcdNodes {
+		status.Start(fmt.Sprintf("[%s] Starting etcd ▶", node.String()))
+		if err := startEtcd(node, topology); err != nil {
+			return err
+		}
+	}
+
+	for _, node := range etcdNodes {
+		status.Start(fmt.Sprintf("[%s] Waiting for etcd to be healthy 🗄", node.String()))
+		if !waitForEtcd(node, topology, time.Now().Add(2*time.Minute)) {
+			return fmt.Errorf("timed out waiting for etcd to be healthy on node %s", node.String())
+		}
+	}
+	status.End(true)
+
+	return nil
+}
+
+// etcdNodes returns the nodes hosting the cluster etcd members, and the etcd
+// topology, which determines how etcd runs on the nodes
+func (c *Context) etcdNodes() ([]*nodes.Node, config.EtcdTopology, error) {
+	n, err := c.ListNodes()
+	if err != nil {
+		return nil, "", fmt.Errorf("error listing nodes: %v", err)
+	}
+	_, derived, nodeList, err := c.deriveInfoFromNodes(n)
+	if err != nil {
+		return nil, "", err
+	}
+
+	replicas := derived.ControlPlanes()
+	if derived.EtcdTopology() == config.ExternalEtcdTopology {
+		replicas = derived.ExternalEtcd()
+	}
+	if len(replicas) == 0 {
+		return nil, "", fmt.Errorf("no etcd nodes found for cluster %q", c.Name())
+	}
+	etcdNodes := []*nodes.Node{}
+	for _, replica := range replicas {
+		etcdNodes = append(etcdNodes, nodeList[replica.Name])
+	}
+	return etcdNodes, derived.EtcdTopology(), nil
+}
+
+// etcdContainer returns the ID of the etcd container running on the node,
+// this is either the etcd static pod container (stacked etcd) or the
+// container started by the "etcd" action (external etcd)
+func etcdContainer(node *nodes.Node, topology config.EtcdTopology) (string, error) {
+	filter := "label=io.kubernetes.container.name=etcd"
+	if topology == config.ExternalEtcdTopology {
+		filter = fmt.Sprintf("name=^/%s$", externalEtcdContainerName)
+	}
+	lines, err := exec.CombinedOutputLines(node.Command("docker", "ps", "-q", "--filter", filter))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list containers on node")
+	}
+	if len(lines) == 0 {
+		return "", fmt.Errorf("etcd is not running on node %s", node.String())
+	}
+	return lines[0], nil
+}
+
+// etcdctl returns a command running etcdctl against the local etcd member
+// in the etcd container on the node
+func etcdctl(node *nodes.Node, container string, args ...string) exec.Cmd {
+	return node.Command("docker", append([]string{
+		"exec", "-e", "ETCDCTL_API=3", container,
+		"etcdctl",
+		fmt.Sprintf("--endpoints=https://127.0.0.1:%d", etcd.ClientPort),
+		"--cacert=" + etcd.CertsDir + "/ca.crt",
+		"--cert=" + etcd.CertsDir + "/server.crt",
+		"--key=" + etcd.CertsDir + "/server.key",
+	}, args...)...)
+}
+
+// etcdMemberPeerURLs returns the peer URL of each etcd member by member name
+func etcdMemberPeerURLs(node *nodes.Node, container string) (map[string]string, error) {
+	lines, err := exec.CombinedOutputLines(etcdctl(node, container, "member", "list"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list etcd members")
+	}
+	// each line is: ID, status, name, peer URLs, client URLs
+	peerURLs := map[string]string{}
+	for _, line := range lines {
+		parts := strings.Split(line, ", ")
+		if len(parts) < 4 {
+			return nil, fmt.Errorf("invalid output when listing etcd members: %s", line)
+		}
+		peerURLs[parts[2]] = parts[3]
+	}
+	return peerURLs, nil
+}
+
+// stopEtcd stops the etcd member on the node
+func stopEtcd(node *nodes.Node, topology config.EtcdTopology) error {
+	if topology == config.ExternalEtcdTopology {
+		if err := node.Command("docker", "stop", externalEtcdContainerName).Run(); err != nil {
+			return errors.Wrap(err, "failed to stop etcd")
+		}
+		return nil
+	}
+	// the kubelet stops the static pod once the manifest is removed
+	if err := node.Command("mv", stackedEtcdManifest, stackedEtcdManifestBackup).Run(); err != nil {
+		return errors.Wrap(err, "failed to stop etcd")
+	}
+	until := time.Now().Add(time.Minute)
+	for until.After(time.Now()) {
+		if _, err := etcdContainer(node, topology); err != nil {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return fmt.Errorf("timed out waiting for etcd to stop on node %s", node.String())
+}
+
+// startEtcd starts the etcd member on the node stopped by stopEtcd
+func startEtcd(node *nodes.Node, topology config.EtcdTopology) error {
+	cmd := node.Command("mv", stackedEtcdManifestBackup, stackedEtcdManifest)
+	if topology == config.ExternalEtcdTopology {
+		cmd = node.Command("docker", "start", externalEtcdContainerName)
+	}
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, "failed to start etcd")
+	}
+	return nil
+}
+
+// restoreEtcd restores the snapshot copied to the node into a new data
+// directory for the member, using etcdctl from the etcd image, and then
+// replaces the member data directory with it
+func restoreEtcd(node *nodes.Node, peerURL, initialCluster string) error {
+	image, err := etcdImage(node)
+	if err != nil {
+		return err
+	}
+	if err := node.Command(
+		"docker", "run", "--rm",
+		"-e", "ETCDCTL_API=3",
+		"-v", "/var/lib:/var/lib",
+		image,
+		"etcdctl", "snapshot", "restore", etcdRestoredSnapshotPath,
+		"--name="+node.String(),
+		"--initial-cluster="+initialCluster,
+		"--initial-advertise-peer-urls="+peerURL,
+		"--data-dir="+etcdRestoredDataDir,
+	).Run(); err != nil {
+		return errors.Wrap(err, "failed to restore etcd snapshot")
+	}
+	if err := node.Command(
+		"/bin/sh", "-c",
+		// the data directory itself is kept, as it may be a tmpfs mount, see
+		// config.Tmpfs
+		fmt.Sprintf("rm -rf %[1]s/member && mv %[2]s/member %[1]s/member && rm -rf %[2]s %[3]s", etcd.DataDir, etcdRestoredDataDir, etcdRestoredSnapshotPath),
+	).Run(); err != nil {
+		return errors.Wrap(err, "failed to replace etcd data")
+	}
+	return nil
+}
+
+// waitForEtcd waits for the etcd member on the node to report healthy
+func waitForEtcd(node *nodes.Node, topology config.EtcdTopology, until time.Time) bool {
+	for until.After(time.Now()) {
+		if container, err := etcdContainer(node, topology); err == nil {
+			if etcdctl(node, container, "endpoint", "health").Run() == nil {
+				return true
+			}
+		}
+		time.Sleep(time.Second)
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"crypto/x509"
+	"net"
+	"testing"
+)
+
+func TestNewCert(t *testing.T) {
+	ca, err := NewCA("etcd-ca")
+	if err != nil {
+		t.Fatalf("unexpected error creating CA: %v", err)
+	}
+	// the CA must survive encoding, as it is stored on the nodes
+	ca, err = ParseCertKeyPair(ca.EncodeCert(), ca.EncodeKey())
+	if err != nil {
+		t.Fatalf("unexpected error parsing CA: %v", err)
+	}
+
+	usages := []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	cert, err := ca.NewCert("etcd1", usages, []string{"kind-1-etcd1"}, []net.IP{net.ParseIP("172.17.0.2")})
+	if err != nil {
+		t.Fatalf("unexpected error creating certificate: %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	for _, name := range []string{"kind-1-etcd1", "172.17.0.2"} {
+		if _, err := cert.Cert.Verify(x509.VerifyOptions{
+			DNSName:   name,
+			Roots:     roots,
+			KeyUsages: usages,
+		}); err != nil {
+			t.Errorf("expected certificate to be valid for %q: %v", name, err)
+		}
+	}
+}
+
+func TestInitialCluster(t *testing.T) {
+	members := []Member{
+		{Name: "kind-1-etcd1", IP: "172.17.0.2"},
+		{Name: "kind-1-etcd2", IP: "172.17.0.3"},
+	}
+	expected := "kind-1-etcd1=https://172.17.0.2:2380,kind-1-etcd2=https://172.17.0.3:2380"
+	if actual := InitialCluster(members); actual != expected {
+		t.Errorf("expected %q, saw %q", expected, actual)
+	}
+}
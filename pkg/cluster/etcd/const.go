@@ -0,0 +1,33 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+// ClientPort is the port etcd members serve clients on
+const ClientPort = 2379
+
+// PeerPort is the port etcd members serve peers on
+const PeerPort = 2380
+
+// CertsDir is the directory the etcd certificates are written to on the nodes,
+// this matches the directory used by kubeadm for stacked etcd
+const CertsDir = "/etc/kubernetes/pki/etcd"
+
+// DataDir is the etcd data directory on the external etcd nodes
+const DataDir = "/var/lib/etcd"
+
+// ClusterToken is the initial cluster token for external etcd clusters
+const ClusterToken = "kind-etcd-cluster"
@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Member is a member of an external etcd cluster
+type Member struct {
+	// Name is the etcd member name, this is also the node hostname
+	Name string
+	// IP is the address the member serves clients and peers on
+	IP string
+}
+
+// ClientURL returns the URL the member serves clients on
+func (m Member) ClientURL() string {
+	return "https://" + net.JoinHostPort(m.IP, strconv.Itoa(ClientPort))
+}
+
+// PeerURL returns the URL the member serves peers on
+func (m Member) PeerURL() string {
+	return "https://" + net.JoinHostPort(m.IP, strconv.Itoa(PeerPort))
+}
+
+// Endpoints returns the client URLs of all the members
+func Endpoints(members []Member) []string {
+	endpoints := []string{}
+	for _, m := range members {
+		endpoints = append(endpoints, m.ClientURL())
+	}
+	return endpoints
+}
+
+// InitialCluster returns the value of the etcd --initial-cluster flag
+// for a cluster of the given members
+func InitialCluster(members []Member) string {
+	peers := []string{}
+	for _, m := range members {
+		peers = append(peers, fmt.Sprintf("%s=%s", m.Name, m.PeerURL()))
+	}
+	return strings.Join(peers, ",")
+}
+
+// Args returns the etcd arguments for running member m in a cluster of the
+// given members, using the certificates in CertsDir
+func Args(m Member, members []Member) []string {
+	return []string{
+		"--name=" + m.Name,
+		"--data-dir=" + DataDir,
+		fmt.Sprintf("--listen-client-urls=%s,https://127.0.0.1:%d", m.ClientURL(), ClientPort),
+		"--advertise-client-urls=" + m.ClientURL(),
+		"--listen-peer-urls=" + m.PeerURL(),
+		"--initial-advertise-peer-urls=" + m.PeerURL(),
+		"--initial-cluster=" + InitialCluster(members),
+		"--initial-cluster-state=new",
+		"--initial-cluster-token=" + ClusterToken,
+		"--client-cert-auth=true",
+		"--trusted-ca-file=" + CertsDir + "/ca.crt",
+		"--cert-file=" + CertsDir + "/server.crt",
+		"--key-file=" + CertsDir + "/server.key",
+		"--peer-client-cert-auth=true",
+		"--peer-trusted-ca-file=" + CertsDir + "/ca.crt",
+		"--peer-cert-file=" + CertsDir + "/peer.crt",
+		"--peer-key-file=" + CertsDir + "/peer.key",
+	}
+}
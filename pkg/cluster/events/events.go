@@ -0,0 +1,86 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Type is the type of an Event
+type Type string
+
+const (
+	// ClusterCreateStarted is emitted once the config is validated, before
+	// anything is created
+	ClusterCreateStarted Type = "ClusterCreateStarted"
+	// NodeProvisioned is emitted once the container of a node is running,
+	// before Kubernetes is provisioned on it
+	NodeProvisioned Type = "NodeProvisioned"
+	// TaskStarted is emitted before a task is run on a node
+	TaskStarted Type = "TaskStarted"
+	// TaskCompleted is emitted after a task succeeded on a node
+	TaskCompleted Type = "TaskCompleted"
+	// TaskFailed is emitted after a task failed on a node, no further tasks
+	// are run
+	TaskFailed Type = "TaskFailed"
+	// ClusterReady is emitted once the cluster is created
+	ClusterReady Type = "ClusterReady"
+	// ClusterCreateFailed is emitted if the cluster could not be created
+	ClusterCreateFailed Type = "ClusterCreateFailed"
+)
+
+// Event is an event of the cluster lifecycle
+type Event struct {
+	Type    Type      `json:"type"`
+	Time    time.Time `json:"time"`
+	Cluster string    `json:"cluster"`
+	// Node is the name of the node replica, e.g. control-plane1, if any
+	Node string `json:"node,omitempty"`
+	// Task identifies the task within its action, e.g. init/0, for task events
+	Task string `json:"task,omitempty"`
+	// Message is a human readable description of the event
+	Message string `json:"message,omitempty"`
+	// Error is the error for failure events
+	Error string `json:"error,omitempty"`
+}
+
+// Handler is called with each Event, in order, from the goroutine creating
+// the cluster. Handlers should not block for long as they delay the creation
+type Handler func(Event)
+
+// Channel returns a Handler sending the events to ch, the caller must keep
+// receiving from ch until the cluster operation returns
+func Channel(ch chan<- Event) Handler {
+	return func(e Event) {
+		ch <- e
+	}
+}
+
+// JSONWriter returns a Handler writing the events to w as JSON, one object
+// per line. Errors writing the events are ignored
+func JSONWriter(w io.Writer) Handler {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(w)
+	return func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		encoder.Encode(e)
+	}
+}
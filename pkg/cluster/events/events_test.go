@@ -0,0 +1,58 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestJSONWriter(t *testing.T) {
+	buff := &bytes.Buffer{}
+	handler := JSONWriter(buff)
+	sent := []Event{
+		{Type: ClusterCreateStarted, Time: time.Unix(0, 0).UTC(), Cluster: "1"},
+		{Type: TaskFailed, Time: time.Unix(1, 0).UTC(), Cluster: "1", Node: "control-plane1", Task: "init/0", Error: "boom"},
+	}
+	for _, e := range sent {
+		handler(e)
+	}
+
+	decoder := json.NewDecoder(buff)
+	for i, expected := range sent {
+		var e Event
+		if err := decoder.Decode(&e); err != nil {
+			t.Fatalf("failed to decode event %d: %v", i, err)
+		}
+		if e != expected {
+			t.Errorf("expected event %d to be %+v but got %+v", i, expected, e)
+		}
+	}
+	if decoder.More() {
+		t.Errorf("unexpected trailing output %q", buff.String())
+	}
+}
+
+func TestChannel(t *testing.T) {
+	ch := make(chan Event, 1)
+	Channel(ch)(Event{Type: ClusterReady, Cluster: "1"})
+	if e := <-ch; e.Type != ClusterReady || e.Cluster != "1" {
+		t.Errorf("unexpected event %+v", e)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"reflect"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+// ExportConfig reconstructs the `kind` Config of the existing cluster from
+// its node containers, as far as it can be inspected from them: the node
+// roles, images, mounts, port mappings and devices, and the networking.
+// This allows capturing clusters created ad-hoc and creating them again.
+// Settings that are not recorded on the node containers, e.g. the kubeadm
+// config patches or the fake nodes, are not part of the exported config
+func (c *Context) ExportConfig() (*config.Config, error) {
+	n, err := c.ListNodes()
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %v", err)
+	}
+	if len(n) == 0 {
+		return nil, fmt.Errorf("no nodes found for cluster %q", c.Name())
+	}
+	cfg, derived, _, err := c.deriveInfoFromNodes(n)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Name = c.Name()
+	cfg.Nodes = exportedNodes(derived.AllReplicas())
+	return cfg, nil
+}
+
+// exportedNodes returns the config nodes of the node replicas, in
+// provisioning order, with the consecutive identical replicas collapsed into
+// a node with replicas
+func exportedNodes(replicas replicaList) []config.Node {
+	nodes := []config.Node{}
+	for _, replica := range replicas {
+		node := *replica.Node.DeepCopy()
+		// the load balancer image follows the load balancer type
+		if node.Role == config.ExternalLoadBalancerRole {
+			node.Image = ""
+		}
+		if last := len(nodes) - 1; last >= 0 && sameNode(&nodes[last], &node) {
+			count := int32(2)
+			if nodes[last].Replicas != nil {
+				count = *nodes[last].Replicas + 1
+			}
+			nodes[last].Replicas = &count
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// sameNode returns true if the nodes a and b are configured the same,
+// regardless of their replicas
+func sameNode(a, b *config.Node) bool {
+	a, b = a.DeepCopy(), b.DeepCopy()
+	a.Replicas, b.Replicas = nil, nil
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	utilpointer "k8s.io/utils/pointer"
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestExportedNodes(t *testing.T) {
+	cases := []struct {
+		TestName string
+		Nodes    []config.Node
+		Expected []config.Node
+	}{
+		{
+			TestName: "Single node",
+			Nodes: []config.Node{
+				{Role: config.ControlPlaneRole, Image: "node:a"},
+			},
+			Expected: []config.Node{
+				{Role: config.ControlPlaneRole, Image: "node:a"},
+			},
+		},
+		{
+			TestName: "Identical replicas are collapsed, the load balancer image is left out",
+			Nodes: []config.Node{
+				{Role: config.ExternalLoadBalancerRole},
+				{Role: config.ControlPlaneRole, Image: "node:a"},
+				{Role: config.ControlPlaneRole, Image: "node:a"},
+				{Role: config.WorkerRole, Image: "node:a", Replicas: utilpointer.Int32Ptr(2)},
+			},
+			Expected: []config.Node{
+				{Role: config.ExternalLoadBalancerRole},
+				{Role: config.ControlPlaneRole, Image: "node:a", Replicas: utilpointer.Int32Ptr(2)},
+				{Role: config.WorkerRole, Image: "node:a", Replicas: utilpointer.Int32Ptr(2)},
+			},
+		},
+		{
+			TestName: "Different replicas are kept apart",
+			Nodes: []config.Node{
+				{Role: config.ControlPlaneRole, Image: "node:a"},
+				{Role: config.WorkerRole, Image: "node:a"},
+				{Role: config.WorkerRole, Image: "node:b"},
+				{
+					Role:  config.WorkerRole,
+					Image: "node:b",
+					ExtraMounts: []config.Mount{
+						{HostPath: "/data", ContainerPath: "/data"},
+					},
+				},
+			},
+			Expected: []config.Node{
+				{Role: config.ControlPlaneRole, Image: "node:a"},
+				{Role: config.WorkerRole, Image: "node:a"},
+				{Role: config.WorkerRole, Image: "node:b"},
+				{
+					Role:  config.WorkerRole,
+					Image: "node:b",
+					ExtraMounts: []config.Mount{
+						{HostPath: "/data", ContainerPath: "/data"},
+					},
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.TestName, func(t *testing.T) {
+			derived, err := deriveInfo(&config.Config{Nodes: c.Nodes})
+			if err != nil {
+				t.Fatalf("unexpected error while deriving info: %v", err)
+			}
+			actual := exportedNodes(derived.AllReplicas())
+			if !reflect.DeepEqual(actual, c.Expected) {
+				t.Errorf("expected nodes %+v, got %+v", c.Expected, actual)
+			}
+		})
+	}
+}
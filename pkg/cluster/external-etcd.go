@@ -0,0 +1,262 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/etcd"
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// externalEtcdAction implements action for running an external etcd cluster,
+// with a member on each external-etcd node, and for configuring the
+// control-plane nodes as clients of the etcd cluster
+type externalEtcdAction struct{}
+
+func init() {
+	registerAction("etcd", newExternalEtcdAction)
+}
+
+// newExternalEtcdAction returns a new externalEtcdAction
+func newExternalEtcdAction() action {
+	return &externalEtcdAction{}
+}
+
+// Tasks returns the list of action tasks
+func (b *externalEtcdAction) Tasks() []task {
+	return []task{
+		{
+			// Starts an etcd member on each external-etcd node
+			Description: "Starting external etcd member 🗄",
+			TargetNodes: selectExternalEtcdNodes,
+			Run:         runExternalEtcdMember,
+		},
+		{
+			// Provides the etcd client certificates to the control-plane nodes
+			Description: "Creating external etcd client certificates 🔑",
+			TargetNodes: selectControlPlaneNodesWithExternalEtcd,
+			Run:         runExternalEtcdClientCerts,
+		},
+	}
+}
+
+// selectControlPlaneNodesWithExternalEtcd is a NodeSelector that returns all
+// the nodes with control-plane role if the etcd topology is external
+func selectControlPlaneNodesWithExternalEtcd(cfg *derivedConfigData) replicaList {
+	if cfg.EtcdTopology() != config.ExternalEtcdTopology {
+		return nil
+	}
+	return cfg.ControlPlanes()
+}
+
+// runExternalEtcdMember creates the certificates for the etcd member on the
+// node and runs etcd in a container on the node, using the etcd image
+// pre-loaded in the node image.
+// The etcd CA is created on the first external-etcd node, all the other
+// certificates are signed by it.
+func runExternalEtcdMember(ec *execContext, configNode *nodeReplica) error {
+	// get the target node for this task
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+
+	members, err := ec.externalEtcdMembers()
+	if err != nil {
+		return err
+	}
+	var member etcd.Member
+	for _, m := range members {
+		if m.Name == node.String() {
+			member = m
+		}
+	}
+
+	// get the CA, creating it on the first node
+	var ca *etcd.CertKeyPair
+	if configNode == ec.derived.ExternalEtcd()[0] {
+		ca, err = etcd.NewCA("etcd-ca")
+		if err != nil {
+			return err
+		}
+		if err := writeCertKeyPair(ec, configNode, ca, path.Join(etcd.CertsDir, "ca")); err != nil {
+			return err
+		}
+	} else {
+		ca, err = ec.externalEtcdCA()
+		if err != nil {
+			return err
+		}
+	}
+
+	// create the member certificates, etcd uses both server and peer
+	// certificates also as client certificates
+	usages := []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	dnsNames := []string{member.Name, "localhost"}
+	ips := []net.IP{net.ParseIP(member.IP), net.ParseIP("127.0.0.1")}
+	for _, name := range []string{"server", "peer"} {
+		cert, err := ca.NewCert(fmt.Sprintf("%s-%s", member.Name, name), usages, dnsNames, ips)
+		if err != nil {
+			return err
+		}
+		if err := writeCertKeyPair(ec, configNode, cert, path.Join(etcd.CertsDir, name)); err != nil {
+			return err
+		}
+	}
+
+	// find the etcd image pre-loaded on the node
+	image, err := etcdImage(node)
+	if err != nil {
+		return err
+	}
+
+	// run etcd on the node, the container is restarted along with the node
+	args := []string{
+		"run", "-d",
+		"--name=" + externalEtcdContainerName,
+		"--net=host",
+		"--restart=always",
+		"-v", fmt.Sprintf("%[1]s:%[1]s:ro", etcd.CertsDir),
+		"-v", fmt.Sprintf("%[1]s:%[1]s", etcd.DataDir),
+		image,
+		"etcd",
+	}
+	args = append(args, etcd.Args(member, members)...)
+	if err := node.Command("docker", args...).Run(); err != nil {
+		return errors.Wrap(err, "failed to start etcd")
+	}
+	return nil
+}
+
+// externalEtcdContainerName is the name of the etcd container running on
+// the external etcd nodes
+const externalEtcdContainerName = "etcd"
+
+// runExternalEtcdClientCerts writes the etcd CA certificate and the API
+// server etcd client certificate to the control-plane node, where they are
+// expected by the kubeadm config (see kubeadm.ConfigData)
+func runExternalEtcdClientCerts(ec *execContext, configNode *nodeReplica) error {
+	ca, err := ec.externalEtcdCA()
+	if err != nil {
+		return err
+	}
+
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+	if err := node.WriteFile(kubeadm.ExternalEtcdCAFile, ca.EncodeCert()); err != nil {
+		return errors.Wrap(err, "failed to write etcd CA certificate")
+	}
+
+	cert, err := ca.NewCert("kube-apiserver-etcd-client", []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, nil, nil)
+	if err != nil {
+		return err
+	}
+	if err := node.WriteFile(kubeadm.ExternalEtcdCertFile, cert.EncodeCert()); err != nil {
+		return errors.Wrap(err, "failed to write etcd client certificate")
+	}
+	if err := node.WriteFile(kubeadm.ExternalEtcdKeyFile, cert.EncodeKey()); err != nil {
+		return errors.Wrap(err, "failed to write etcd client key")
+	}
+	return nil
+}
+
+// externalEtcdMembers returns the members of the external etcd cluster,
+// one for each external-etcd node, if the etcd topology is external
+func (ec *execContext) externalEtcdMembers() ([]etcd.Member, error) {
+	members := []etcd.Member{}
+	if ec.derived.EtcdTopology() != config.ExternalEtcdTopology {
+		return members, nil
+	}
+	for _, configNode := range ec.derived.ExternalEtcd() {
+		node, ok := ec.NodeFor(configNode)
+		if !ok {
+			return nil, fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+		}
+		ip, err := nodeIP(node, ec.config.Networking.IPFamily)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get IP for node %s", configNode.Name)
+		}
+		members = append(members, etcd.Member{Name: node.String(), IP: ip})
+	}
+	return members, nil
+}
+
+// externalEtcdCA returns the etcd CA created on the first external-etcd node
+func (ec *execContext) externalEtcdCA() (*etcd.CertKeyPair, error) {
+	configNode := ec.derived.ExternalEtcd()[0]
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return nil, fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+	cert, err := node.ReadFile(path.Join(etcd.CertsDir, "ca.crt"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get etcd CA")
+	}
+	key, err := node.ReadFile(path.Join(etcd.CertsDir, "ca.key"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get etcd CA")
+	}
+	return etcd.ParseCertKeyPair(cert, key)
+}
+
+// writeCertKeyPair writes the certificate and key to <pathPrefix>.crt
+// and <pathPrefix>.key on the node
+func writeCertKeyPair(ec *execContext, configNode *nodeReplica, pair *etcd.CertKeyPair, pathPrefix string) error {
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+	if err := node.WriteFile(pathPrefix+".crt", pair.EncodeCert()); err != nil {
+		return errors.Wrapf(err, "failed to write %s.crt", pathPrefix)
+	}
+	if err := node.WriteFile(pathPrefix+".key", pair.EncodeKey()); err != nil {
+		return errors.Wrapf(err, "failed to write %s.key", pathPrefix)
+	}
+	return nil
+}
+
+// etcdImage returns the etcd image pre-loaded on the node, this is one of
+// the images required by kubeadm, e.g. k8s.gcr.io/etcd:3.2.24
+func etcdImage(node *nodes.Node) (string, error) {
+	lines, err := exec.CombinedOutputLines(
+		node.Command("docker", "images", "--format", "{{.Repository}}:{{.Tag}}"),
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list images on node")
+	}
+	for _, image := range lines {
+		repository := image[:strings.LastIndex(image, ":")]
+		// the repository is e.g. k8s.gcr.io/etcd or k8s.gcr.io/etcd-amd64
+		name := path.Base(repository)
+		if name == "etcd" || strings.HasPrefix(name, "etcd-") {
+			return image, nil
+		}
+	}
+	return "", fmt.Errorf("no etcd image found on node %s", node.String())
+}
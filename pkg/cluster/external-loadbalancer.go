@@ -0,0 +1,206 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+	"sigs.k8s.io/kind/pkg/cluster/loadbalancer"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+)
+
+// externalLoadBalancerAction implements action for (re)configuring the
+// external load balancer with the current control-plane nodes as backends
+type externalLoadBalancerAction struct{}
+
+func init() {
+	registerAction("loadbalancer", newExternalLoadBalancerAction)
+}
+
+// newExternalLoadBalancerAction returns a new externalLoadBalancerAction
+func newExternalLoadBalancerAction() action {
+	return &externalLoadBalancerAction{}
+}
+
+// Tasks returns the list of action tasks
+func (b *externalLoadBalancerAction) Tasks() []task {
+	return []task{
+		{
+			// Configures the load balancer on the ExternalLoadBalancerNode
+			Description: "Configuring the external load balancer ⚖",
+			TargetNodes: selectExternalLoadBalancerNode,
+			Run:         runExternalLoadBalancer,
+		},
+	}
+}
+
+// runExternalLoadBalancer writes the load balancer config for the current
+// control-plane nodes to the node, then starts the load balancer, or
+// hot-reloads it if it is already running and the config changed
+func runExternalLoadBalancer(ec *execContext, configNode *nodeReplica) error {
+	// get the target node for this task
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+
+	lb, err := loadbalancer.Get(ec.config.LoadBalancer.Type)
+	if err != nil {
+		return err
+	}
+
+	// collect the backends, all the API servers
+	data := &loadbalancer.ConfigData{
+		ControlPlanePort: kubeadm.APIServerPort,
+		IPv6:             ec.config.Networking.IPFamily == config.IPv6Family,
+	}
+	for _, controlPlane := range ec.derived.ControlPlanes() {
+		controlPlaneNode, ok := ec.NodeFor(controlPlane)
+		if !ok {
+			return fmt.Errorf("unable to get the handle for operating on node: %s", controlPlane.Name)
+		}
+		ip, err := nodeIP(controlPlaneNode, ec.config.Networking.IPFamily)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get IP for node %s", controlPlane.Name)
+		}
+		data.BackendServers = append(data.BackendServers, loadbalancer.Backend{
+			Name: controlPlaneNode.String(),
+			IP:   ip,
+			Port: kubeadm.APIServerPort,
+		})
+	}
+
+	contents, err := lb.Config(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate load balancer config")
+	}
+
+	state, err := node.State()
+	if err != nil {
+		return err
+	}
+	running := state == "running"
+
+	// if the load balancer is running with the same config, there is nothing to do
+	if running {
+		current, err := node.ReadFile(lb.ConfigPath())
+		if err == nil && string(current) == contents {
+			return lb.HealthCheck(node)
+		}
+	}
+
+	// copy the config to the node, this works also for a node container
+	// that has not been started yet
+	f, err := ioutil.TempFile("", "")
+	if err != nil {
+		return errors.Wrap(err, "failed to create load balancer config")
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(contents); err != nil {
+		f.Close()
+		return errors.Wrap(err, "failed to create load balancer config")
+	}
+	f.Close()
+	if err := node.CopyTo(f.Name(), lb.ConfigPath()); err != nil {
+		return errors.Wrap(err, "failed to copy load balancer config to node")
+	}
+
+	// start or reload the load balancer
+	if running {
+		err = lb.Reload(node)
+	} else {
+		err = nodes.Start(*node)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to start load balancer")
+	}
+
+	// wait for the load balancer to serve
+	until := time.Now().Add(30 * time.Second)
+	for {
+		err = lb.HealthCheck(node)
+		if err == nil || time.Now().After(until) {
+			return err
+		}
+		time.Sleep(newTimeouts(&ec.config.Timeouts).PollInterval)
+	}
+}
+
+// apiServerNode returns the node the API server is published on, this
+// is the external load balancer if any, or the bootstrap control-plane
+func apiServerNode(derived *derivedConfigData, nodeList map[string]*nodes.Node) (*nodes.Node, error) {
+	replica := derived.ExternalLoadBalancer()
+	if replica == nil {
+		replica = derived.BootStrapControlPlane()
+	}
+	if replica == nil {
+		return nil, fmt.Errorf("no node publishing the API server")
+	}
+	node, ok := nodeList[replica.Name]
+	if !ok {
+		return nil, fmt.Errorf("unable to get the handle for operating on node: %s", replica.Name)
+	}
+	return node, nil
+}
+
+// controlPlaneEndpoint returns the address of the API server inside the
+// docker network, this is the external load balancer if any, or the
+// bootstrap control-plane
+func (ec *execContext) controlPlaneEndpoint() (string, error) {
+	node, err := apiServerNode(ec.derived, ec.nodes)
+	if err != nil {
+		return "", err
+	}
+	ip, err := nodeIP(node, ec.config.Networking.IPFamily)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get IP for node")
+	}
+	return net.JoinHostPort(ip, strconv.Itoa(kubeadm.APIServerPort)), nil
+}
+
+// ReconfigureLoadBalancer regenerates the backends of the external load
+// balancer of the cluster, if any, from the current control-plane nodes and
+// hot-reloads it if they changed. This is done automatically when the
+// addresses of the control-plane nodes may have changed, on Resume, on
+// ImportSnapshot and when reverting a stop fault, and should be called after
+// changing the control-plane nodes by other means.
+// TODO: reconfigure the load balancer when control-plane nodes are added or
+// replaced, once secondary control-plane nodes are supported, see
+// validateForCreate
+func (c *Context) ReconfigureLoadBalancer() error {
+	n, err := c.ListNodes()
+	if err != nil {
+		return fmt.Errorf("error listing nodes: %v", err)
+	}
+	cfg, derived, nodeList, err := c.deriveInfoFromNodes(n)
+	if err != nil {
+		return err
+	}
+	if derived.ExternalLoadBalancer() == nil {
+		return nil
+	}
+	return c.exec(cfg, derived, nodeList, []string{"loadbalancer"})
+}
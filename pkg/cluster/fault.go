@@ -0,0 +1,244 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/exec"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// Fault is a failure injected into a node of a cluster, for testing the
+// resilience of controllers, see Context.InjectFault
+type Fault string
+
+const (
+	// StopFault stops the node container, as if the node lost power
+	StopFault Fault = "stop"
+	// PauseFault freezes the processes of the node container, as if the
+	// node hung
+	PauseFault Fault = "pause"
+	// PartitionFault drops all the traffic of the node container on the
+	// docker network, as if the node was unplugged from the network
+	PartitionFault Fault = "partition"
+	// DiskPressureFault fills the disk of the node until the kubelet reports
+	// the DiskPressure condition and evicts pods
+	DiskPressureFault Fault = "disk-pressure"
+)
+
+// Faults are all the faults which can be injected into nodes
+var Faults = []Fault{StopFault, PauseFault, PartitionFault, DiskPressureFault}
+
+// partitionChain is the iptables chain of the node dropping its traffic
+// while partitioned
+const partitionChain = "KIND-FAULT-PARTITION"
+
+// diskPressureFile is the file of the node filling its disk
+const diskPressureFile = "/var/kind-fault-disk-pressure"
+
+// diskPressureAvailable is the fraction of the disk of the node left
+// available while filled, below the default nodefs.available<10% eviction
+// threshold of the kubelet
+const diskPressureAvailable = 0.05
+
+// InjectFault injects the fault into the node of the cluster named name,
+// until it is reverted with RevertFault
+func (c *Context) InjectFault(name string, fault Fault) error {
+	node, err := c.faultNode(name, fault)
+	if err != nil {
+		return err
+	}
+
+	status := logutil.NewStatus(os.Stdout)
+	status.MaybeWrapLogger(c.Logger())
+	defer status.End(false)
+
+	switch fault {
+	case StopFault:
+		status.Start(fmt.Sprintf("[%s] Stopping node container ⏹", name))
+		if err := nodes.Stop(*node); err != nil {
+			return errors.Wrapf(err, "failed to stop node %s", name)
+		}
+	case PauseFault:
+		status.Start(fmt.Sprintf("[%s] Freezing node container ⏸", name))
+		if err := nodes.Pause(*node); err != nil {
+			return errors.Wrapf(err, "failed to pause node %s", name)
+		}
+	case PartitionFault:
+		status.Start(fmt.Sprintf("[%s] Partitioning node from the network ✂", name))
+		if err := node.Command("/bin/sh", "-c", partitionScript).Run(); err != nil {
+			return errors.Wrapf(err, "failed to partition node %s, it may already be partitioned", name)
+		}
+	case DiskPressureFault:
+		status.Start(fmt.Sprintf("[%s] Filling node disk 💽", name))
+		df, err := exec.CombinedOutputLines(node.Command("df", "-B1", "--output=size,avail", "/var/lib/kubelet"))
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the disk usage of node %s", name)
+		}
+		size, err := diskFillSize(df)
+		if err != nil {
+			return errors.Wrapf(err, "invalid disk usage of node %s", name)
+		}
+		if size > 0 {
+			if err := node.Command("fallocate", "-l", strconv.FormatInt(size, 10), diskPressureFile).Run(); err != nil {
+				return errors.Wrapf(err, "failed to fill the disk of node %s", name)
+			}
+		}
+	}
+	status.End(true)
+	return nil
+}
+
+// RevertFault reverts the fault injected into the node of the cluster named
+// name with InjectFault, restarting it after a stop fault
+func (c *Context) RevertFault(name string, fault Fault) error {
+	node, err := c.faultNode(name, fault)
+	if err != nil {
+		return err
+	}
+
+	status := logutil.NewStatus(os.Stdout)
+	status.MaybeWrapLogger(c.Logger())
+	defer status.End(false)
+
+	switch fault {
+	case StopFault:
+		status.Start(fmt.Sprintf("[%s] Starting node container ▶", name))
+		if err := nodes.Start(*node); err != nil {
+			return errors.Wrapf(err, "failed to start node %s", name)
+		}
+		if err := bootNode(status, node); err != nil {
+			return err
+		}
+		status.End(true)
+		// the node IP may have changed, as on Resume
+		if err := c.ReconfigureLoadBalancer(); err != nil {
+			return errors.Wrap(err, "failed to reconfigure the external load balancer")
+		}
+		return c.writeInternalKubeConfig()
+	case PauseFault:
+		status.Start(fmt.Sprintf("[%s] Unfreezing node container ▶", name))
+		if err := nodes.Unpause(*node); err != nil {
+			return errors.Wrapf(err, "failed to unpause node %s", name)
+		}
+	case PartitionFault:
+		status.Start(fmt.Sprintf("[%s] Reconnecting node to the network 🔌", name))
+		if err := node.Command("/bin/sh", "-c", unpartitionScript).Run(); err != nil {
+			return errors.Wrapf(err, "failed to reconnect node %s", name)
+		}
+	case DiskPressureFault:
+		status.Start(fmt.Sprintf("[%s] Freeing node disk 💽", name))
+		if err := node.Command("rm", "-f", diskPressureFile).Run(); err != nil {
+			return errors.Wrapf(err, "failed to free the disk of node %s", name)
+		}
+	}
+	status.End(true)
+	return nil
+}
+
+// faultNode returns the node of the cluster named name, if the fault can be
+// injected into it, the partition and disk pressure faults are injected by
+// commands on the node, which need the node image
+func (c *Context) faultNode(name string, fault Fault) (*nodes.Node, error) {
+	known := false
+	for _, f := range Faults {
+		known = known || f == fault
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown fault %q, expected one of %v", fault, Faults)
+	}
+
+	n, err := c.ListNodes()
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %v", err)
+	}
+	for i := range n {
+		node := &n[i]
+		if node.String() != name {
+			continue
+		}
+		if fault == PartitionFault || fault == DiskPressureFault {
+			role, err := node.Role()
+			if err != nil {
+				return nil, err
+			}
+			if config.NodeRole(role) == config.ExternalLoadBalancerRole || node.IsWindows() {
+				return nil, fmt.Errorf("fault %q is not supported for node %s", fault, name)
+			}
+		}
+		return node, nil
+	}
+	return nil, fmt.Errorf("no node named %q found in cluster %q", name, c.Name())
+}
+
+// partitionScript drops the traffic of the node on eth0, the interface of
+// the node on the docker network, for both IPv4 and IPv6, from a dedicated
+// chain so that unpartitionScript can remove the rules
+const partitionScript = `set -e
+for ipt in iptables ip6tables; do
+  $ipt -N ` + partitionChain + `
+  $ipt -A ` + partitionChain + ` -i eth0 -j DROP
+  $ipt -A ` + partitionChain + ` -o eth0 -j DROP
+  for chain in INPUT OUTPUT FORWARD; do
+    $ipt -I $chain -j ` + partitionChain + `
+  done
+done
+`
+
+// unpartitionScript removes the rules of partitionScript, if any
+const unpartitionScript = `for ipt in iptables ip6tables; do
+  for chain in INPUT OUTPUT FORWARD; do
+    while $ipt -D $chain -j ` + partitionChain + ` 2>/dev/null; do :; done
+  done
+  $ipt -F ` + partitionChain + ` 2>/dev/null && $ipt -X ` + partitionChain + `
+done
+true
+`
+
+// diskFillSize returns the number of bytes to allocate on the disk of a node
+// for it to be under disk pressure, from the output of
+// `df -B1 --output=size,avail`
+func diskFillSize(df []string) (int64, error) {
+	if len(df) != 2 {
+		return 0, fmt.Errorf("unexpected df output: %v", df)
+	}
+	fields := strings.Fields(df[1])
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("unexpected df output: %v", df)
+	}
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid disk size")
+	}
+	avail, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid available disk space")
+	}
+	fill := avail - int64(float64(size)*diskPressureAvailable)
+	if fill < 0 {
+		return 0, nil
+	}
+	return fill, nil
+}
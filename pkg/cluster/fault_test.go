@@ -0,0 +1,63 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+)
+
+func TestDiskFillSize(t *testing.T) {
+	cases := []struct {
+		Name     string
+		DF       []string
+		Expected int64
+		Error    bool
+	}{
+		{
+			Name:     "free disk",
+			DF:       []string{"    1B-blocks         Avail", "1000000 600000"},
+			Expected: 550000,
+		},
+		{
+			Name:     "disk under pressure",
+			DF:       []string{"    1B-blocks         Avail", "1000000 40000"},
+			Expected: 0,
+		},
+		{
+			Name:  "invalid output",
+			DF:    []string{"df: /var/lib/kubelet: No such file or directory"},
+			Error: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			size, err := diskFillSize(tc.DF)
+			if tc.Error {
+				if err == nil {
+					t.Errorf("expected an error but got %d", size)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if size != tc.Expected {
+				t.Errorf("expected %d but got %d", tc.Expected, size)
+			}
+		})
+	}
+}
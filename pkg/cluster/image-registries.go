@@ -0,0 +1,187 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+)
+
+// dockerDaemonConfigPath is the config of the docker daemon of the nodes
+const dockerDaemonConfigPath = "/etc/docker/daemon.json"
+
+// kubeletDockerConfigPath is the docker credentials config the kubelet pulls
+// images with
+const kubeletDockerConfigPath = "/var/lib/kubelet/config.json"
+
+// dockerHubAuthKey is the docker credentials config key of Docker Hub
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// imageRegistryConfig contains the node files configuring how images are
+// pulled, the contents are nil for the files that are not needed
+type imageRegistryConfig struct {
+	DockerDaemonConfig  []byte
+	KubeletDockerConfig []byte
+}
+
+// newImageRegistryConfig returns the image registry config of the nodes for
+// cfg, including the local registry at localRegistry if not empty
+func newImageRegistryConfig(cfg *config.Config, localRegistry string) (*imageRegistryConfig, error) {
+	daemonConfig, err := dockerDaemonConfig(cfg, localRegistry)
+	if err != nil {
+		return nil, err
+	}
+	kubeletConfig, err := kubeletDockerConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &imageRegistryConfig{
+		DockerDaemonConfig:  daemonConfig,
+		KubeletDockerConfig: kubeletConfig,
+	}, nil
+}
+
+// dockerDaemonConfig returns the docker daemon config with the mirrors and
+// insecure registries of cfg, patched with the docker daemon config patches,
+// or nil if there is nothing to configure
+func dockerDaemonConfig(cfg *config.Config, localRegistry string) ([]byte, error) {
+	mirrors := []string{}
+	insecure := []string{}
+	if localRegistry != "" {
+		mirrors = append(mirrors, "http://"+localRegistry)
+		insecure = append(insecure, localRegistry)
+	}
+	for _, registry := range cfg.ImageRegistries {
+		mirrors = append(mirrors, registry.Mirrors...)
+		if registry.Insecure {
+			insecure = append(insecure, registry.Host)
+		}
+	}
+	if len(mirrors) == 0 && len(insecure) == 0 && len(cfg.DockerDaemonConfigPatches) == 0 {
+		return nil, nil
+	}
+
+	daemonConfig := map[string]interface{}{}
+	if len(mirrors) > 0 {
+		daemonConfig["registry-mirrors"] = mirrors
+	}
+	if len(insecure) > 0 {
+		daemonConfig["insecure-registries"] = insecure
+	}
+	contents, err := json.Marshal(daemonConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode docker daemon config")
+	}
+	for i, patch := range cfg.DockerDaemonConfigPatches {
+		jsonPatch, err := yaml.YAMLToJSON([]byte(patch))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse docker daemon config patch %d", i)
+		}
+		contents, err = jsonpatch.MergePatch(contents, jsonPatch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to apply docker daemon config patch %d", i)
+		}
+	}
+
+	// indent the config for readability on the nodes
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, contents, "", "  "); err != nil {
+		return nil, errors.Wrap(err, "failed to encode docker daemon config")
+	}
+	return indented.Bytes(), nil
+}
+
+// kubeletDockerConfig returns the docker credentials config with the
+// credentials of the registries of cfg, or nil if there are none
+func kubeletDockerConfig(cfg *config.Config) ([]byte, error) {
+	auths := map[string]map[string]string{}
+	for _, registry := range cfg.ImageRegistries {
+		if registry.Username == "" {
+			continue
+		}
+		key := registry.Host
+		if key == config.DockerHubRegistryHost {
+			key = dockerHubAuthKey
+		}
+		auths[key] = map[string]string{
+			"auth": base64.StdEncoding.EncodeToString([]byte(registry.Username + ":" + registry.Password)),
+		}
+	}
+	if len(auths) == 0 {
+		return nil, nil
+	}
+	contents, err := json.MarshalIndent(map[string]interface{}{"auths": auths}, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode kubelet docker config")
+	}
+	return contents, nil
+}
+
+// Write writes the image registry config files to the node. This must be
+// called before SignalStart, the files are part of the node container
+// filesystem and persist when the node is restarted
+func (r *imageRegistryConfig) Write(node *nodes.Node) error {
+	for _, file := range []struct {
+		path     string
+		contents []byte
+	}{
+		{dockerDaemonConfigPath, r.DockerDaemonConfig},
+		{kubeletDockerConfigPath, r.KubeletDockerConfig},
+	} {
+		if file.contents == nil {
+			continue
+		}
+		if err := node.WriteFile(file.path, file.contents); err != nil {
+			return errors.Wrapf(err, "failed to write %s to node %s", file.path, node.String())
+		}
+	}
+	return nil
+}
+
+// readImageRegistryConfig reads the image registry config files of an
+// existing node, e.g. to configure a replacement node the same way
+func readImageRegistryConfig(node *nodes.Node) (*imageRegistryConfig, error) {
+	r := &imageRegistryConfig{}
+	for _, file := range []struct {
+		path     string
+		contents *[]byte
+	}{
+		{dockerDaemonConfigPath, &r.DockerDaemonConfig},
+		{kubeletDockerConfigPath, &r.KubeletDockerConfig},
+	} {
+		var buff bytes.Buffer
+		// the files do not exist if there is nothing to configure
+		cmd := node.Command("/bin/sh", "-c", fmt.Sprintf("if [ -f %[1]s ]; then cat %[1]s; fi", file.path))
+		cmd.SetStdout(&buff)
+		if err := cmd.Run(); err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s from node %s", file.path, node.String())
+		}
+		if buff.Len() > 0 {
+			*file.contents = buff.Bytes()
+		}
+	}
+	return r, nil
+}
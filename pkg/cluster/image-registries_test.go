@@ -0,0 +1,107 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestNewImageRegistryConfig(t *testing.T) {
+	cases := []struct {
+		TestName            string
+		Config              config.Config
+		LocalRegistry       string
+		ExpectDaemonConfig  string
+		ExpectKubeletConfig string
+	}{
+		{
+			TestName: "no registries",
+		},
+		{
+			TestName: "mirrors and insecure registries",
+			Config: config.Config{
+				ImageRegistries: []config.ImageRegistry{
+					{Host: config.DockerHubRegistryHost, Mirrors: []string{"https://mirror.example.com"}},
+					{Host: "registry.example.com:5000", Insecure: true},
+				},
+			},
+			LocalRegistry: "kind-1-registry:5000",
+			ExpectDaemonConfig: `{
+  "insecure-registries": [
+    "kind-1-registry:5000",
+    "registry.example.com:5000"
+  ],
+  "registry-mirrors": [
+    "http://kind-1-registry:5000",
+    "https://mirror.example.com"
+  ]
+}`,
+		},
+		{
+			TestName: "patches",
+			Config: config.Config{
+				ImageRegistries: []config.ImageRegistry{
+					{Host: "registry.example.com", Insecure: true},
+				},
+				DockerDaemonConfigPatches: []string{
+					"max-concurrent-downloads: 10\n",
+					`{"insecure-registries": null, "log-level": "debug"}`,
+				},
+			},
+			ExpectDaemonConfig: `{
+  "log-level": "debug",
+  "max-concurrent-downloads": 10
+}`,
+		},
+		{
+			TestName: "credentials",
+			Config: config.Config{
+				ImageRegistries: []config.ImageRegistry{
+					{Host: config.DockerHubRegistryHost, Username: "user", Password: "password"},
+					{Host: "registry.example.com", Username: "robot", Password: "token"},
+				},
+			},
+			ExpectKubeletConfig: `{
+  "auths": {
+    "https://index.docker.io/v1/": {
+      "auth": "dXNlcjpwYXNzd29yZA=="
+    },
+    "registry.example.com": {
+      "auth": "cm9ib3Q6dG9rZW4="
+    }
+  }
+}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.TestName, func(t *testing.T) {
+			r, err := newImageRegistryConfig(&tc.Config, tc.LocalRegistry)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(r.DockerDaemonConfig) != tc.ExpectDaemonConfig {
+				t.Errorf("expected docker daemon config %q but got %q", tc.ExpectDaemonConfig, r.DockerDaemonConfig)
+			}
+			if string(r.KubeletDockerConfig) != tc.ExpectKubeletConfig {
+				t.Errorf("expected kubelet docker config %q but got %q", tc.ExpectKubeletConfig, r.KubeletDockerConfig)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	osexec "os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// addonsDir is the directory of the addon manifests on the bootstrap control
+// plane node, with a subdirectory per addon
+const addonsDir = "/kind/addons"
+
+// defaultAddonWaitTimeout is how long to wait for the Wait checks of an addon
+// by default, see config.Addon
+const defaultAddonWaitTimeout = 5 * time.Minute
+
+// installAddonsAction implements action for installing the addons of the
+// config, see config.Addon
+type installAddonsAction struct{}
+
+func init() {
+	registerAction("addons", newInstallAddonsAction)
+}
+
+// newInstallAddonsAction returns a new installAddonsAction
+func newInstallAddonsAction() action {
+	return &installAddonsAction{}
+}
+
+// Tasks returns the list of action tasks
+func (b *installAddonsAction) Tasks() []task {
+	return []task{
+		{
+			// Install the addons from the BootstrapControlPlaneNode
+			Description: "Installing addons 🧩",
+			TargetNodes: selectBootstrapControlPlaneNode,
+			Run:         runInstallAddons,
+		},
+	}
+}
+
+// runInstallAddons installs the addons in order, waiting for each one to be
+// ready before installing the next one
+func runInstallAddons(ec *execContext, configNode *nodeReplica) error {
+	// get the target node for this task
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+
+	pollInterval := newTimeouts(&ec.config.Timeouts).PollInterval
+	for i := range ec.config.Addons {
+		addon := &ec.config.Addons[i]
+		ec.Logger().Infof("Installing addon %s", addon.Name)
+		var err error
+		if addon.Chart != nil {
+			err = ec.installAddonChart(addon)
+		} else {
+			err = applyAddonManifests(node, addon)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to install addon %s", addon.Name)
+		}
+		if err := waitForAddon(node, addon, pollInterval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyAddonManifests applies the manifests of the addon, the inline ones
+// first, then the files and the URLs, in order
+func applyAddonManifests(node *nodes.Node, addon *config.Addon) error {
+	args := []string{"--kubeconfig=/etc/kubernetes/admin.conf", "apply"}
+	dir := path.Join(addonsDir, addon.Name)
+	for i, manifest := range addon.Manifests {
+		nodePath := path.Join(dir, fmt.Sprintf("manifest-%d.yaml", i))
+		if err := node.WriteFile(nodePath, []byte(manifest)); err != nil {
+			return errors.Wrap(err, "failed to write the manifest to the node")
+		}
+		args = append(args, "-f", nodePath)
+	}
+	for i, file := range addon.Files {
+		manifest, err := ioutil.ReadFile(file)
+		if err != nil {
+			return errors.Wrap(err, "failed to read the manifest file")
+		}
+		nodePath := path.Join(dir, fmt.Sprintf("file-%d.yaml", i))
+		if err := node.WriteFile(nodePath, manifest); err != nil {
+			return errors.Wrapf(err, "failed to write %s to the node", file)
+		}
+		args = append(args, "-f", nodePath)
+	}
+	for _, url := range addon.URLs {
+		args = append(args, "-f", url)
+	}
+	return exec.RunLoggingOutputOnFail(node.Command("kubectl", args...))
+}
+
+// installAddonChart installs or upgrades the Helm release of the addon chart
+// with the helm binary of the host, against the kubeconfig of the cluster
+func (ec *execContext) installAddonChart(addon *config.Addon) error {
+	if _, err := osexec.LookPath("helm"); err != nil {
+		return errors.New("helm is required on the host to install charts, see https://helm.sh/docs/intro/install/")
+	}
+	args, err := ec.helmInstallArgs(addon)
+	if err != nil {
+		return err
+	}
+	return exec.RunLoggingOutputOnFail(exec.Command("helm", args...))
+}
+
+// helmInstallArgs returns the helm arguments installing the addon chart,
+// writing its values to the temporary directory
+func (ec *execContext) helmInstallArgs(addon *config.Addon) ([]string, error) {
+	chart := addon.Chart
+	release := chart.Release
+	if release == "" {
+		release = addon.Name
+	}
+	namespace := chart.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	args := []string{
+		"upgrade", "--install", release, chart.Name,
+		"--kubeconfig", ec.KubeConfigPath(),
+		"--namespace", namespace, "--create-namespace",
+	}
+	if chart.Repo != "" {
+		args = append(args, "--repo", chart.Repo)
+	}
+	if chart.Version != "" {
+		args = append(args, "--version", chart.Version)
+	}
+	if chart.Values != "" {
+		dir, err := ec.TempDir()
+		if err != nil {
+			return nil, err
+		}
+		values := filepath.Join(dir, addon.Name+"-values.yaml")
+		if err := ioutil.WriteFile(values, []byte(chart.Values), 0600); err != nil {
+			return nil, errors.Wrap(err, "failed to write the chart values")
+		}
+		args = append(args, "--values", values)
+	}
+	return args, nil
+}
+
+// waitForAddon waits up to the wait timeout of the addon for its Wait checks
+// to be met
+func waitForAddon(node *nodes.Node, addon *config.Addon, pollInterval time.Duration) error {
+	if len(addon.Wait) == 0 {
+		return nil
+	}
+	timeout := defaultAddonWaitTimeout
+	if addon.WaitTimeout != "" {
+		// validated, see config.Addon
+		timeout, _ = time.ParseDuration(addon.WaitTimeout)
+	}
+	var pending []string
+	for until := time.Now().Add(timeout); ; time.Sleep(pollInterval) {
+		pending = []string{}
+		for i := range addon.Wait {
+			pending = append(pending, readinessCheckPending(node, &addon.Wait[i])...)
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		if time.Now().After(until) {
+			break
+		}
+	}
+	return errors.Errorf("timed out after %s waiting for addon %s to be ready: %s", timeout, addon.Name, strings.Join(pending, ", "))
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestHelmInstallArgs(t *testing.T) {
+	ec := &execContext{Context: NewContext("addons")}
+	defer ec.removeTempDir()
+
+	args, err := ec.helmInstallArgs(&config.Addon{
+		Name:  "ingress",
+		Chart: &config.Chart{Name: "ingress-nginx"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"upgrade", "--install", "ingress", "ingress-nginx",
+		"--kubeconfig", ec.KubeConfigPath(),
+		"--namespace", "default", "--create-namespace",
+	}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("expected %v, got %v", expected, args)
+	}
+
+	args, err = ec.helmInstallArgs(&config.Addon{
+		Name: "ingress",
+		Chart: &config.Chart{
+			Name:      "ingress-nginx",
+			Repo:      "https://kubernetes.github.io/ingress-nginx",
+			Version:   "4.0.1",
+			Release:   "nginx",
+			Namespace: "ingress-nginx",
+			Values:    "controller:\n  replicaCount: 1\n",
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = []string{
+		"upgrade", "--install", "nginx", "ingress-nginx",
+		"--kubeconfig", ec.KubeConfigPath(),
+		"--namespace", "ingress-nginx", "--create-namespace",
+		"--repo", "https://kubernetes.github.io/ingress-nginx",
+		"--version", "4.0.1",
+		"--values", filepath.Join(ec.tempDir, "ingress-values.yaml"),
+	}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("expected %v, got %v", expected, args)
+	}
+	values, err := ioutil.ReadFile(expected[len(expected)-1])
+	if err != nil || string(values) != "controller:\n  replicaCount: 1\n" {
+		t.Errorf("expected the values to be written, got %q, %v", values, err)
+	}
+}
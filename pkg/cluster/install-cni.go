@@ -0,0 +1,140 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+// installCNIAction implements action for installing the default CNI
+// network plugin
+type installCNIAction struct{}
+
+func init() {
+	registerAction("cni", newInstallCNIAction)
+}
+
+// newInstallCNIAction returns a new installCNIAction
+func newInstallCNIAction() action {
+	return &installCNIAction{}
+}
+
+// Tasks returns the list of action tasks
+func (b *installCNIAction) Tasks() []task {
+	return []task{
+		{
+			// Install the CNI network plugin from the BootstrapControlPlaneNode
+			Description: "Installing CNI 🔌",
+			TargetNodes: selectBootstrapControlPlaneNode,
+			Run:         runInstallCNI,
+		},
+	}
+}
+
+// defaultCNIPodSubnet is the default pod subnet of the default CNI network
+// plugin, used unless the pod subnet is configured
+const defaultCNIPodSubnet = "10.32.0.0/12"
+
+// weaveMTUOverhead is the difference between the MTU of the node interfaces
+// and the MTU of the default CNI network plugin, as the overlay network
+// encapsulates the pod traffic, this matches the weave default MTU of 1376
+// for the default MTU of 1500
+const weaveMTUOverhead = defaultMTU - 1376
+
+// installsDefaultCNI returns true if the default CNI network plugin is
+// installed for the cluster, this is not the case if disabled in the config,
+// or for IPv6 and dual-stack clusters, as it does not support IPv6
+func installsDefaultCNI(cfg *config.Config) bool {
+	if cfg.Networking.DisableDefaultCNI {
+		return false
+	}
+	return cfg.Networking.IPFamily == "" || cfg.Networking.IPFamily == config.IPv4Family
+}
+
+// defaultCNIManifestPath is the default CNI network plugin manifest on the
+// nodes of offline clusters
+const defaultCNIManifestPath = "/kind/manifests/default-cni.yaml"
+
+// linuxNodeSelectorPatch restricts the pods of a workload to the Linux
+// nodes, for clusters with Windows nodes
+const linuxNodeSelectorPatch = `{"spec":{"template":{"spec":{"nodeSelector":{"kubernetes.io/os":"linux"}}}}}`
+
+// weaveParams returns the weave manifest URL parameters for the config,
+// allocating the pod IPs from the configured pod subnet if any
+func weaveParams(cfg *config.Config) string {
+	params := ""
+	if podSubnet := cfg.Networking.PodSubnet; podSubnet != "" {
+		params = "&env.IPALLOC_RANGE=" + podSubnet
+	}
+	if mtu := cfg.Networking.MTU; mtu != 0 {
+		params += fmt.Sprintf("&env.WEAVE_MTU=%d", mtu-weaveMTUOverhead)
+	}
+	return params
+}
+
+// runInstallCNI installs the default CNI network plugin, allocating the pod
+// IPs from the configured pod subnet if any
+func runInstallCNI(ec *execContext, configNode *nodeReplica) error {
+	// get the target node for this task
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+
+	// TODO(bentheelder): support other overlay networks
+	// the manifest is written to the node for offline clusters, see
+	// ExportBundle, and downloaded otherwise
+	if err := node.Command(
+		"/bin/sh", "-c",
+		`if [ -f `+defaultCNIManifestPath+` ]; then `+
+			`kubectl apply --kubeconfig=/etc/kubernetes/admin.conf -f `+defaultCNIManifestPath+`; `+
+			`else `+
+			`kubectl apply --kubeconfig=/etc/kubernetes/admin.conf -f "https://cloud.weave.works/k8s/net?k8s-version=$(kubectl version --kubeconfig=/etc/kubernetes/admin.conf | base64 | tr -d '\n')`+weaveParams(ec.config)+`"; `+
+			`fi`,
+	).Run(); err != nil {
+		return errors.Wrap(err, "failed to apply overlay network")
+	}
+
+	// the default CNI network plugin runs only on Linux, the Windows node
+	// image configures the network of the Windows nodes
+	if len(ec.derived.WindowsWorkers()) > 0 {
+		if err := node.Command(
+			"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+			"-n", "kube-system", "patch", "daemonset", "weave-net",
+			"-p", linuxNodeSelectorPatch,
+		).Run(); err != nil {
+			return errors.Wrap(err, "failed to restrict overlay network to Linux nodes")
+		}
+	}
+
+	// wait for the overlay network to run on all the nodes, if configured
+	if rollout := newTimeouts(&ec.config.Timeouts).CNIRollout; rollout > 0 {
+		if err := node.Command(
+			"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+			"-n", "kube-system", "rollout", "status", "daemonset", "weave-net",
+			"--timeout="+rollout.String(),
+		).Run(); err != nil {
+			return errors.Wrapf(err, "overlay network not rolled out within %s", rollout)
+		}
+	}
+
+	return nil
+}
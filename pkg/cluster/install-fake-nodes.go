@@ -0,0 +1,261 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// installFakeNodesAction implements action for simulating the fake nodes
+// with kwok
+type installFakeNodesAction struct{}
+
+func init() {
+	registerAction("fake-nodes", newInstallFakeNodesAction)
+}
+
+// newInstallFakeNodesAction returns a new installFakeNodesAction
+func newInstallFakeNodesAction() action {
+	return &installFakeNodesAction{}
+}
+
+// Tasks returns the list of action tasks
+func (b *installFakeNodesAction) Tasks() []task {
+	return []task{
+		{
+			// Install kwok and the fake nodes from the BootstrapControlPlaneNode
+			Description: "Creating fake nodes 👻",
+			TargetNodes: selectBootstrapControlPlaneNode,
+			Run:         runInstallFakeNodes,
+		},
+	}
+}
+
+// runInstallFakeNodes applies the kwok manifest, then creates the Node
+// objects of the fake nodes, which kwok keeps ready
+func runInstallFakeNodes(ec *execContext, configNode *nodeReplica) error {
+	// get the target node for this task
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+
+	cmd := node.Command(
+		"kubectl",
+		"--kubeconfig=/etc/kubernetes/admin.conf", "apply", "-f", "-",
+	)
+	cmd.SetStdin(strings.NewReader(kwokManifest))
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, "failed to apply kwok manifest")
+	}
+
+	names := []string{}
+	for _, replica := range ec.derived.FakeNodes() {
+		names = append(names, ec.nodeContainerName(replica.Name))
+	}
+	manifest, err := fakeNodesManifest(ec.derived.FakeNodes(), names)
+	if err != nil {
+		return err
+	}
+	cmd = node.Command(
+		"kubectl",
+		"--kubeconfig=/etc/kubernetes/admin.conf", "apply", "-f", "-",
+	)
+	cmd.SetStdin(strings.NewReader(manifest))
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, "failed to create fake nodes")
+	}
+	return nil
+}
+
+// fakeNodeKey is the label and annotation of the fake nodes, kwok manages
+// the nodes annotated with it, and the taint keeping pods off the fake nodes
+// unless they tolerate it
+const fakeNodeKey = "kwok.x-k8s.io/node"
+
+// fakeNodeCapacity is the default capacity of the fake nodes
+var fakeNodeCapacity = map[string]string{
+	"cpu":    "32",
+	"memory": "256Gi",
+	"pods":   "110",
+}
+
+// fakeNodesManifest returns the Node objects of the fake node replicas,
+// named by names, with their labels, taints and resources as capacity
+func fakeNodesManifest(replicas replicaList, names []string) (string, error) {
+	items := []interface{}{}
+	for i, replica := range replicas {
+		labels := map[string]string{
+			"kubernetes.io/hostname": names[i],
+			"kubernetes.io/os":       "linux",
+			"type":                   "kwok",
+			fakeNodeKey:              "fake",
+		}
+		for key, value := range replica.Labels {
+			labels[key] = value
+		}
+		taints := []map[string]string{
+			{"key": fakeNodeKey, "value": "fake", "effect": "NoSchedule"},
+		}
+		for _, taint := range replica.Taints {
+			t := map[string]string{"key": taint.Key, "effect": string(taint.Effect)}
+			if taint.Value != "" {
+				t["value"] = taint.Value
+			}
+			taints = append(taints, t)
+		}
+		capacity := map[string]string{}
+		for resource, quantity := range fakeNodeCapacity {
+			capacity[resource] = quantity
+		}
+		if replica.Resources.CPUs != "" {
+			capacity["cpu"] = replica.Resources.CPUs
+		}
+		if replica.Resources.Memory != "" {
+			capacity["memory"] = replica.Resources.Memory
+		}
+		items = append(items, map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Node",
+			"metadata": map[string]interface{}{
+				"name":   names[i],
+				"labels": labels,
+				"annotations": map[string]string{
+					fakeNodeKey:                    "fake",
+					"node.alpha.kubernetes.io/ttl": "0",
+				},
+			},
+			"spec": map[string]interface{}{
+				"taints": taints,
+			},
+			"status": map[string]interface{}{
+				"capacity":    capacity,
+				"allocatable": capacity,
+			},
+		})
+	}
+	manifest, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      items,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate fake nodes")
+	}
+	return string(manifest), nil
+}
+
+// kwokImage is the image of kwok
+const kwokImage = "registry.k8s.io/kwok/kwok:v0.1.1"
+
+// kwokManifest is the kwok controller, managing the nodes annotated with
+// fakeNodeKey and their pods, it runs on the control plane, never on the
+// fake nodes
+const kwokManifest = `---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: kwok-controller
+  namespace: kube-system
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: kwok-controller
+rules:
+- apiGroups: [""]
+  resources: ["nodes"]
+  verbs: ["get", "list", "watch", "patch", "update"]
+- apiGroups: [""]
+  resources: ["nodes/status"]
+  verbs: ["patch", "update"]
+- apiGroups: [""]
+  resources: ["pods"]
+  verbs: ["get", "list", "watch", "patch", "update", "delete"]
+- apiGroups: [""]
+  resources: ["pods/status"]
+  verbs: ["patch", "update"]
+- apiGroups: ["coordination.k8s.io"]
+  resources: ["leases"]
+  verbs: ["get", "list", "watch", "create", "patch", "update", "delete"]
+- apiGroups: [""]
+  resources: ["events"]
+  verbs: ["create", "patch", "update"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: kwok-controller
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: kwok-controller
+subjects:
+- kind: ServiceAccount
+  name: kwok-controller
+  namespace: kube-system
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: kwok-controller
+  namespace: kube-system
+  labels:
+    app: kwok-controller
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: kwok-controller
+  template:
+    metadata:
+      labels:
+        app: kwok-controller
+    spec:
+      serviceAccountName: kwok-controller
+      affinity:
+        nodeAffinity:
+          requiredDuringSchedulingIgnoredDuringExecution:
+            nodeSelectorTerms:
+            - matchExpressions:
+              - key: ` + fakeNodeKey + `
+                operator: DoesNotExist
+      tolerations:
+      - key: node-role.kubernetes.io/master
+        effect: NoSchedule
+      - key: node-role.kubernetes.io/control-plane
+        effect: NoSchedule
+      containers:
+      - name: kwok-controller
+        image: ` + kwokImage + `
+        imagePullPolicy: IfNotPresent
+        args:
+        - --manage-all-nodes=false
+        - --manage-nodes-with-annotation-selector=` + fakeNodeKey + `=fake
+        - --disregard-status-with-annotation-selector=kwok.x-k8s.io/status=custom
+        - --cidr=10.0.0.1/24
+        - --node-ip=$(POD_IP)
+        env:
+        - name: POD_IP
+          valueFrom:
+            fieldRef:
+              fieldPath: status.podIP
+`
@@ -0,0 +1,101 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestFakeNodesManifest(t *testing.T) {
+	replicas := replicaList{
+		{Name: "fake1", Node: config.Node{Role: config.FakeRole}},
+		{Name: "fake2", Node: config.Node{
+			Role:      config.FakeRole,
+			Labels:    map[string]string{"topology.kubernetes.io/zone": "b"},
+			Taints:    []config.Taint{{Key: "dedicated", Effect: config.TaintEffectNoExecute}},
+			Resources: config.NodeResources{CPUs: "4", Memory: "16Gi"},
+		}},
+	}
+	manifest, err := fakeNodesManifest(replicas, []string{"kind-1-fake1", "kind-1-fake2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := `apiVersion: v1
+items:
+- apiVersion: v1
+  kind: Node
+  metadata:
+    annotations:
+      kwok.x-k8s.io/node: fake
+      node.alpha.kubernetes.io/ttl: "0"
+    labels:
+      kubernetes.io/hostname: kind-1-fake1
+      kubernetes.io/os: linux
+      kwok.x-k8s.io/node: fake
+      type: kwok
+    name: kind-1-fake1
+  spec:
+    taints:
+    - effect: NoSchedule
+      key: kwok.x-k8s.io/node
+      value: fake
+  status:
+    allocatable:
+      cpu: "32"
+      memory: 256Gi
+      pods: "110"
+    capacity:
+      cpu: "32"
+      memory: 256Gi
+      pods: "110"
+- apiVersion: v1
+  kind: Node
+  metadata:
+    annotations:
+      kwok.x-k8s.io/node: fake
+      node.alpha.kubernetes.io/ttl: "0"
+    labels:
+      kubernetes.io/hostname: kind-1-fake2
+      kubernetes.io/os: linux
+      kwok.x-k8s.io/node: fake
+      topology.kubernetes.io/zone: b
+      type: kwok
+    name: kind-1-fake2
+  spec:
+    taints:
+    - effect: NoSchedule
+      key: kwok.x-k8s.io/node
+      value: fake
+    - effect: NoExecute
+      key: dedicated
+  status:
+    allocatable:
+      cpu: "4"
+      memory: 16Gi
+      pods: "110"
+    capacity:
+      cpu: "4"
+      memory: 16Gi
+      pods: "110"
+kind: List
+`
+	if manifest != expected {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expected, manifest)
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+	"sigs.k8s.io/kind/pkg/docker"
+)
+
+// applyManifestsAction implements action for applying the manifests built
+// into the node image, see kind build node-image --manifest
+type applyManifestsAction struct{}
+
+func init() {
+	registerAction("manifests", newApplyManifestsAction)
+}
+
+// newApplyManifestsAction returns a new applyManifestsAction
+func newApplyManifestsAction() action {
+	return &applyManifestsAction{}
+}
+
+// Tasks returns the list of action tasks
+func (b *applyManifestsAction) Tasks() []task {
+	return []task{
+		{
+			// Apply the manifests from the BootstrapControlPlaneNode
+			Description: "Applying node image manifests 📜",
+			TargetNodes: selectBootstrapControlPlaneNode,
+			Run:         runApplyManifests,
+		},
+	}
+}
+
+// imageHasManifests returns true if the node image was built with manifests
+// to apply, see consts.NodeImageManifestsKey
+func imageHasManifests(image string) bool {
+	lines, err := docker.Inspect(image, fmt.Sprintf("{{index .Config.Labels %q}}", consts.NodeImageManifestsKey))
+	return err == nil && len(lines) == 1 && strings.Trim(lines[0], "'") == "true"
+}
+
+// withManifestsAction returns the create actions with the manifests action
+// after the control plane is initialized and the CNI network plugin installed,
+// so that the manifests may install another CNI network plugin, and before the
+// other nodes join
+func withManifestsAction(actions []string) []string {
+	res := []string{}
+	added := false
+	for _, action := range actions {
+		if action == "join" && !added {
+			res = append(res, "manifests")
+			added = true
+		}
+		res = append(res, action)
+	}
+	if !added {
+		res = append(res, "manifests")
+	}
+	return res
+}
+
+// runApplyManifests applies the manifests built into the node image, in the
+// order of their file names
+func runApplyManifests(ec *execContext, configNode *nodeReplica) error {
+	// get the target node for this task
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+
+	if err := node.Command(
+		"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+		"apply", "-f", consts.NodeImageManifestsDir,
+	).Run(); err != nil {
+		return errors.Wrap(err, "failed to apply the node image manifests")
+	}
+	return nil
+}
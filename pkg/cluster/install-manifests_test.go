@@ -0,0 +1,43 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithManifestsAction(t *testing.T) {
+	cases := []struct {
+		Actions  []string
+		Expected []string
+	}{
+		{
+			Actions:  []string{"config", "init", "cni", "join", "ingress"},
+			Expected: []string{"config", "init", "cni", "manifests", "join", "ingress"},
+		},
+		{
+			Actions:  []string{"config", "init"},
+			Expected: []string{"config", "init", "manifests"},
+		},
+	}
+	for _, tc := range cases {
+		if actions := withManifestsAction(tc.Actions); !reflect.DeepEqual(actions, tc.Expected) {
+			t.Errorf("expected %v, got %v", tc.Expected, actions)
+		}
+	}
+}
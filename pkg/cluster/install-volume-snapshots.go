@@ -0,0 +1,487 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// installVolumeSnapshotsAction implements action for installing the
+// VolumeSnapshot CRDs and the snapshot controller
+type installVolumeSnapshotsAction struct{}
+
+func init() {
+	registerAction("volume-snapshots", newInstallVolumeSnapshotsAction)
+}
+
+// newInstallVolumeSnapshotsAction returns a new installVolumeSnapshotsAction
+func newInstallVolumeSnapshotsAction() action {
+	return &installVolumeSnapshotsAction{}
+}
+
+// Tasks returns the list of action tasks
+func (b *installVolumeSnapshotsAction) Tasks() []task {
+	return []task{
+		{
+			// Install the snapshot controller from the BootstrapControlPlaneNode
+			Description: "Installing the volume snapshot controller 📸",
+			TargetNodes: selectBootstrapControlPlaneNode,
+			Run:         runInstallVolumeSnapshots,
+		},
+	}
+}
+
+// runInstallVolumeSnapshots applies the VolumeSnapshot CRDs, waits for them
+// to be established, then applies the snapshot controller
+func runInstallVolumeSnapshots(ec *execContext, configNode *nodeReplica) error {
+	// get the target node for this task
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+
+	cmd := node.Command(
+		"kubectl",
+		"--kubeconfig=/etc/kubernetes/admin.conf", "apply", "-f", "-",
+	)
+	cmd.SetStdin(strings.NewReader(volumeSnapshotCRDsManifest))
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, "failed to apply the VolumeSnapshot CRDs")
+	}
+	if err := node.Command(
+		"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+		"wait", "--for=condition=Established", "--timeout=1m",
+		"crd/volumesnapshotclasses.snapshot.storage.k8s.io",
+		"crd/volumesnapshotcontents.snapshot.storage.k8s.io",
+		"crd/volumesnapshots.snapshot.storage.k8s.io",
+	).Run(); err != nil {
+		return errors.Wrap(err, "failed to wait for the VolumeSnapshot CRDs")
+	}
+
+	cmd = node.Command(
+		"kubectl",
+		"--kubeconfig=/etc/kubernetes/admin.conf", "apply", "-f", "-",
+	)
+	cmd.SetStdin(strings.NewReader(snapshotControllerManifest))
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, "failed to apply the snapshot controller manifest")
+	}
+	return nil
+}
+
+// snapshotControllerImage is the image of the snapshot controller of the CSI
+// external-snapshotter
+const snapshotControllerImage = "registry.k8s.io/sig-storage/snapshot-controller:v6.2.1"
+
+// volumeSnapshotCRDsManifest is the snapshot.storage.k8s.io/v1 CRDs of the
+// CSI external-snapshotter
+const volumeSnapshotCRDsManifest = `---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: volumesnapshotclasses.snapshot.storage.k8s.io
+  annotations:
+    api-approved.kubernetes.io: "https://github.com/kubernetes-csi/external-snapshotter/pull/814"
+spec:
+  group: snapshot.storage.k8s.io
+  names:
+    kind: VolumeSnapshotClass
+    listKind: VolumeSnapshotClassList
+    plural: volumesnapshotclasses
+    shortNames:
+      - vsclass
+      - vsclasses
+    singular: volumesnapshotclass
+  scope: Cluster
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      subresources: {}
+      additionalPrinterColumns:
+        - jsonPath: .driver
+          name: Driver
+          type: string
+        - jsonPath: .deletionPolicy
+          name: DeletionPolicy
+          type: string
+        - jsonPath: .metadata.creationTimestamp
+          name: Age
+          type: date
+      schema:
+        openAPIV3Schema:
+          type: object
+          required:
+            - deletionPolicy
+            - driver
+          properties:
+            apiVersion:
+              type: string
+            kind:
+              type: string
+            metadata:
+              type: object
+            deletionPolicy:
+              type: string
+              enum:
+                - Delete
+                - Retain
+            driver:
+              type: string
+            parameters:
+              type: object
+              additionalProperties:
+                type: string
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: volumesnapshotcontents.snapshot.storage.k8s.io
+  annotations:
+    api-approved.kubernetes.io: "https://github.com/kubernetes-csi/external-snapshotter/pull/814"
+spec:
+  group: snapshot.storage.k8s.io
+  names:
+    kind: VolumeSnapshotContent
+    listKind: VolumeSnapshotContentList
+    plural: volumesnapshotcontents
+    shortNames:
+      - vsc
+      - vscs
+    singular: volumesnapshotcontent
+  scope: Cluster
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      subresources:
+        status: {}
+      additionalPrinterColumns:
+        - jsonPath: .status.readyToUse
+          name: ReadyToUse
+          type: boolean
+        - jsonPath: .status.restoreSize
+          name: RestoreSize
+          type: integer
+        - jsonPath: .spec.deletionPolicy
+          name: DeletionPolicy
+          type: string
+        - jsonPath: .spec.driver
+          name: Driver
+          type: string
+        - jsonPath: .spec.volumeSnapshotClassName
+          name: VolumeSnapshotClass
+          type: string
+        - jsonPath: .spec.volumeSnapshotRef.name
+          name: VolumeSnapshot
+          type: string
+        - jsonPath: .spec.volumeSnapshotRef.namespace
+          name: VolumeSnapshotNamespace
+          type: string
+        - jsonPath: .metadata.creationTimestamp
+          name: Age
+          type: date
+      schema:
+        openAPIV3Schema:
+          type: object
+          required:
+            - spec
+          properties:
+            apiVersion:
+              type: string
+            kind:
+              type: string
+            metadata:
+              type: object
+            spec:
+              type: object
+              required:
+                - deletionPolicy
+                - driver
+                - source
+                - volumeSnapshotRef
+              properties:
+                deletionPolicy:
+                  type: string
+                  enum:
+                    - Delete
+                    - Retain
+                driver:
+                  type: string
+                source:
+                  type: object
+                  oneOf:
+                    - required: ["snapshotHandle"]
+                    - required: ["volumeHandle"]
+                  properties:
+                    snapshotHandle:
+                      type: string
+                    volumeHandle:
+                      type: string
+                sourceVolumeMode:
+                  type: string
+                volumeSnapshotClassName:
+                  type: string
+                volumeSnapshotRef:
+                  type: object
+                  x-kubernetes-map-type: atomic
+                  properties:
+                    apiVersion:
+                      type: string
+                    fieldPath:
+                      type: string
+                    kind:
+                      type: string
+                    name:
+                      type: string
+                    namespace:
+                      type: string
+                    resourceVersion:
+                      type: string
+                    uid:
+                      type: string
+            status:
+              type: object
+              properties:
+                creationTime:
+                  type: integer
+                  format: int64
+                error:
+                  type: object
+                  properties:
+                    message:
+                      type: string
+                    time:
+                      type: string
+                      format: date-time
+                readyToUse:
+                  type: boolean
+                restoreSize:
+                  type: integer
+                  format: int64
+                  minimum: 0
+                snapshotHandle:
+                  type: string
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: volumesnapshots.snapshot.storage.k8s.io
+  annotations:
+    api-approved.kubernetes.io: "https://github.com/kubernetes-csi/external-snapshotter/pull/814"
+spec:
+  group: snapshot.storage.k8s.io
+  names:
+    kind: VolumeSnapshot
+    listKind: VolumeSnapshotList
+    plural: volumesnapshots
+    shortNames:
+      - vs
+    singular: volumesnapshot
+  scope: Namespaced
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      subresources:
+        status: {}
+      additionalPrinterColumns:
+        - jsonPath: .status.readyToUse
+          name: ReadyToUse
+          type: boolean
+        - jsonPath: .spec.source.persistentVolumeClaimName
+          name: SourcePVC
+          type: string
+        - jsonPath: .spec.source.volumeSnapshotContentName
+          name: SourceSnapshotContent
+          type: string
+        - jsonPath: .status.restoreSize
+          name: RestoreSize
+          type: string
+        - jsonPath: .spec.volumeSnapshotClassName
+          name: SnapshotClass
+          type: string
+        - jsonPath: .status.boundVolumeSnapshotContentName
+          name: SnapshotContent
+          type: string
+        - jsonPath: .status.creationTime
+          name: CreationTime
+          type: date
+        - jsonPath: .metadata.creationTimestamp
+          name: Age
+          type: date
+      schema:
+        openAPIV3Schema:
+          type: object
+          required:
+            - spec
+          properties:
+            apiVersion:
+              type: string
+            kind:
+              type: string
+            metadata:
+              type: object
+            spec:
+              type: object
+              required:
+                - source
+              properties:
+                source:
+                  type: object
+                  oneOf:
+                    - required: ["persistentVolumeClaimName"]
+                    - required: ["volumeSnapshotContentName"]
+                  properties:
+                    persistentVolumeClaimName:
+                      type: string
+                    volumeSnapshotContentName:
+                      type: string
+                volumeSnapshotClassName:
+                  type: string
+            status:
+              type: object
+              properties:
+                boundVolumeSnapshotContentName:
+                  type: string
+                creationTime:
+                  type: string
+                  format: date-time
+                error:
+                  type: object
+                  properties:
+                    message:
+                      type: string
+                    time:
+                      type: string
+                      format: date-time
+                readyToUse:
+                  type: boolean
+                restoreSize:
+                  type: string
+                  pattern: ^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$
+                  x-kubernetes-int-or-string: true
+`
+
+// snapshotControllerManifest is the snapshot controller of the CSI
+// external-snapshotter, in the kube-system namespace
+const snapshotControllerManifest = `---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: snapshot-controller
+  namespace: kube-system
+---
+kind: ClusterRole
+apiVersion: rbac.authorization.k8s.io/v1
+metadata:
+  name: snapshot-controller-runner
+rules:
+  - apiGroups: [""]
+    resources: ["persistentvolumes"]
+    verbs: ["get", "list", "watch"]
+  - apiGroups: [""]
+    resources: ["persistentvolumeclaims"]
+    verbs: ["get", "list", "watch", "update"]
+  - apiGroups: [""]
+    resources: ["events"]
+    verbs: ["list", "watch", "create", "update", "patch"]
+  - apiGroups: ["snapshot.storage.k8s.io"]
+    resources: ["volumesnapshotclasses"]
+    verbs: ["get", "list", "watch"]
+  - apiGroups: ["snapshot.storage.k8s.io"]
+    resources: ["volumesnapshotcontents"]
+    verbs: ["create", "get", "list", "watch", "update", "delete", "patch"]
+  - apiGroups: ["snapshot.storage.k8s.io"]
+    resources: ["volumesnapshotcontents/status"]
+    verbs: ["patch"]
+  - apiGroups: ["snapshot.storage.k8s.io"]
+    resources: ["volumesnapshots"]
+    verbs: ["get", "list", "watch", "update", "patch", "delete"]
+  - apiGroups: ["snapshot.storage.k8s.io"]
+    resources: ["volumesnapshots/status"]
+    verbs: ["update", "patch"]
+---
+kind: ClusterRoleBinding
+apiVersion: rbac.authorization.k8s.io/v1
+metadata:
+  name: snapshot-controller-role
+subjects:
+  - kind: ServiceAccount
+    name: snapshot-controller
+    namespace: kube-system
+roleRef:
+  kind: ClusterRole
+  name: snapshot-controller-runner
+  apiGroup: rbac.authorization.k8s.io
+---
+kind: Role
+apiVersion: rbac.authorization.k8s.io/v1
+metadata:
+  name: snapshot-controller-leaderelection
+  namespace: kube-system
+rules:
+  - apiGroups: ["coordination.k8s.io"]
+    resources: ["leases"]
+    verbs: ["get", "watch", "list", "delete", "update", "create"]
+---
+kind: RoleBinding
+apiVersion: rbac.authorization.k8s.io/v1
+metadata:
+  name: snapshot-controller-leaderelection
+  namespace: kube-system
+subjects:
+  - kind: ServiceAccount
+    name: snapshot-controller
+    namespace: kube-system
+roleRef:
+  kind: Role
+  name: snapshot-controller-leaderelection
+  apiGroup: rbac.authorization.k8s.io
+---
+kind: Deployment
+apiVersion: apps/v1
+metadata:
+  name: snapshot-controller
+  namespace: kube-system
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: snapshot-controller
+  template:
+    metadata:
+      labels:
+        app: snapshot-controller
+    spec:
+      serviceAccountName: snapshot-controller
+      containers:
+        - name: snapshot-controller
+          image: ` + snapshotControllerImage + `
+          imagePullPolicy: IfNotPresent
+          args:
+            - --v=5
+            - --leader-election=true
+      nodeSelector:
+        kubernetes.io/os: linux
+      tolerations:
+        - key: node-role.kubernetes.io/master
+          effect: NoSchedule
+`
@@ -19,18 +19,26 @@ package cluster
 import (
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 
 	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/etcd"
 	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+	"sigs.k8s.io/kind/pkg/docker"
 	"sigs.k8s.io/kind/pkg/kustomize"
+	logutil "sigs.k8s.io/kind/pkg/log"
 )
 
+// kubeadmConfigPath is the path of the kubeadm config file on the nodes
+const kubeadmConfigPath = "/kind/kubeadm.conf"
+
 // kubeadmConfigAction implements action for creating the kubadm config
-// and deployng it on the bootrap control-plane node.
+// and deployng it on the control-plane and worker nodes.
 type kubeadmConfigAction struct{}
 
 func init() {
@@ -46,9 +54,10 @@ func newKubeadmConfigAction() action {
 func (b *kubeadmConfigAction) Tasks() []task {
 	return []task{
 		{
-			// Creates the kubeadm config file on the BootstrapControlPlaneNode
+			// Creates the kubeadm config file on the control-plane and worker
+			// nodes, this is used by both kubeadm init and kubeadm join
 			Description: "Creating the kubeadm config file ⛵",
-			TargetNodes: selectBootstrapControlPlaneNode,
+			TargetNodes: selectKubernetesNodes,
 			Run:         runKubeadmConfig,
 		},
 	}
@@ -70,17 +79,79 @@ func runKubeadmConfig(ec *execContext, configNode *nodeReplica) error {
 		return errors.Wrap(err, "failed to get kubernetes version from node: %v")
 	}
 
+	// the API server is reached through the external load balancer if any,
+	// or the bootstrap control-plane otherwise
+	joinEndpoint, err := ec.controlPlaneEndpoint()
+	if err != nil {
+		return err
+	}
+	controlPlaneEndpoint := ""
+	if ec.derived.ExternalLoadBalancer() != nil {
+		controlPlaneEndpoint = joinEndpoint
+	}
+
+	// get the endpoints of the external etcd cluster, if any
+	members, err := ec.externalEtcdMembers()
+	if err != nil {
+		return err
+	}
+
+	// the IPv4 address of the node is selected by default, so the IPv6
+	// address is set explicitly for IPv6 clusters
+	nodeAddress := ""
+	if ec.config.Networking.IPFamily == config.IPv6Family {
+		nodeAddress, err = nodeIP(node, ec.config.Networking.IPFamily)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the kubelet reserves the host resources beyond the node limits, if any
+	systemReserved, err := nodeSystemReserved(node, configNode.Resources)
+	if err != nil {
+		return err
+	}
+	reservedCPUs, err := nodeReservedCPUs(node, configNode.CPUManager)
+	if err != nil {
+		return err
+	}
+
 	// create kubeadm config file writing a local temp file
 	kubeadmConfig, err := createKubeadmConfig(
 		ec.config,
-		ec.derived,
+		configNode,
 		kubeadm.ConfigData{
-			ClusterName:       ec.name,
-			KubernetesVersion: kubeVersion,
-			APIBindPort:       kubeadm.APIServerPort,
-			Token:             kubeadm.Token,
-			// TODO(fabriziopandini): when external load-balancer will be
-			//		implemented also controlPlaneAddress should be added
+			ClusterName:             ec.name,
+			KubernetesVersion:       kubeVersion,
+			APIBindPort:             kubeadm.APIServerPort,
+			APIServerAddress:        certSANAddress(ec.config.Networking.APIServerAddress),
+			APIServerCertSANs:       ec.config.Networking.APIServerCertSANs,
+			PodSubnet:               ec.config.Networking.PodSubnet,
+			ServiceSubnet:           ec.config.Networking.ServiceSubnet,
+			IPFamily:                string(ec.config.Networking.IPFamily),
+			KubeProxyMode:           string(ec.config.Networking.KubeProxyMode),
+			NodeAddress:             nodeAddress,
+			Token:                   kubeadm.Token,
+			JoinEndpoint:            joinEndpoint,
+			NodeLabels:              nodeLabels(configNode),
+			NodeTaints:              nodeTaints(configNode),
+			SystemReserved:          systemReserved,
+			AllowedUnsafeSysctls:    unsafeSysctls(configNode),
+			CPUManagerPolicy:        string(configNode.CPUManager.Policy),
+			ReservedCPUs:            reservedCPUs,
+			TopologyManagerPolicy:   string(configNode.CPUManager.TopologyManagerPolicy),
+			KubeletExtraArgs:        configNode.KubeletExtraArgs,
+			Audit:                   ec.config.Audit.Enabled(),
+			Encryption:              ec.config.Encryption.Enabled(),
+			OIDCArgs:                oidcArgs(&ec.config.OIDC),
+			ClusterSigningDuration:  ec.config.Certificates.SigningDuration,
+			FeatureGates:            ec.config.FeatureGates,
+			RuntimeConfig:           ec.config.RuntimeConfig,
+			EnableAdmissionPlugins:  ec.config.EnableAdmissionPlugins,
+			DisableAdmissionPlugins: ec.config.DisableAdmissionPlugins,
+			ControlPlaneEndpoint:    controlPlaneEndpoint,
+			ExternalEtcdEndpoints:   etcd.Endpoints(members),
+			Rootless:                docker.CurrentProvider().Rootless(),
 		},
 	)
 	if err != nil {
@@ -92,18 +163,48 @@ func runKubeadmConfig(ec *execContext, configNode *nodeReplica) error {
 	defer os.Remove(kubeadmConfig)
 
 	// copy the config to the node
-	if err := node.CopyTo(kubeadmConfig, "/kind/kubeadm.conf"); err != nil {
+	if err := node.CopyTo(kubeadmConfig, kubeadmConfigPath); err != nil {
 		// TODO(bentheelder): logging here
 		return errors.Wrap(err, "failed to copy kubeadm config to node")
 	}
 
+	// the API server of the control-plane mounts the audit policy, the
+	// encryption config and the OIDC CA, if any, and kubeadm init uses the
+	// custom CA, if any
+	if configNode.IsControlPlane() {
+		if err := ec.writeClusterCA(configNode); err != nil {
+			return err
+		}
+		if err := ec.writeOIDCCA(configNode); err != nil {
+			return err
+		}
+		if err := ec.writeAuditPolicy(configNode); err != nil {
+			return err
+		}
+		if err := ec.writeEncryptionConfig(configNode, kubeVersion); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-// createKubeadmConfig creates the kubeadm config file for the cluster
-// by running data through the template and writing it to a temp file
+// certSANAddress returns the API server address to add to the API server
+// certificate SANs, if any, this is the remote docker host for the
+// unspecified address, or none as localhost is used to reach the API server
+// then
+func certSANAddress(apiServerAddress string) string {
+	if ip := net.ParseIP(apiServerAddress); ip == nil || ip.IsUnspecified() {
+		return docker.CurrentProvider().RemoteHost()
+	}
+	return apiServerAddress
+}
+
+// createKubeadmConfig creates the kubeadm config file for the node
+// by running data through the template, applying the cluster and then the
+// node patches, and writing it to a temp file
 // the config file path is returned, this file should be removed later
-func createKubeadmConfig(cfg *config.Config, derived *derivedConfigData, data kubeadm.ConfigData) (path string, err error) {
+func createKubeadmConfig(cfg *config.Config, configNode *nodeReplica, data kubeadm.ConfigData) (path string, err error) {
 	// create kubeadm config file
 	f, err := ioutil.TempFile("", "")
 	if err != nil {
@@ -116,20 +217,19 @@ func createKubeadmConfig(cfg *config.Config, derived *derivedConfigData, data ku
 		os.Remove(path)
 		return "", err
 	}
-	// apply patches
-	// TODO(bentheelder): this does not respect per node patches at all
-	// either make patches cluster wide, or change this
-	patchedConfig, err := kustomize.Build(
-		[]string{config},
-		derived.BootStrapControlPlane().KubeadmConfigPatches,
-		derived.BootStrapControlPlane().KubeadmConfigPatchesJSON6902,
-	)
+	// apply patches, the node patches are applied last so that they take
+	// precedence over the cluster patches
+	patches := append([]string{}, cfg.KubeadmConfigPatches...)
+	patches = append(patches, configNode.KubeadmConfigPatches...)
+	patchesJSON6902 := append([]kustomize.PatchJSON6902{}, cfg.KubeadmConfigPatchesJSON6902...)
+	patchesJSON6902 = append(patchesJSON6902, configNode.KubeadmConfigPatchesJSON6902...)
+	patchedConfig, err := kustomize.Build([]string{config}, patches, patchesJSON6902)
 	if err != nil {
 		os.Remove(path)
 		return "", err
 	}
 	// write to the file
-	log.Infof("Using KubeadmConfig:\n\n%s\n", patchedConfig)
+	logutil.Infof("Using KubeadmConfig for node %s:\n\n%s\n", configNode.Name, patchedConfig)
 	_, err = f.WriteString(patchedConfig)
 	if err != nil {
 		os.Remove(path)
@@ -137,3 +237,60 @@ func createKubeadmConfig(cfg *config.Config, derived *derivedConfigData, data ku
 	}
 	return path, nil
 }
+
+// nodeLabels returns the labels of the node in the format of the
+// kubelet --node-labels flag, sorted for predictable results
+func nodeLabels(configNode *nodeReplica) string {
+	labels := []string{}
+	for key, value := range configNode.Labels {
+		labels = append(labels, fmt.Sprintf("%s=%s", key, value))
+	}
+	if configNode.IngressReady {
+		labels = append(labels, ingressReadyLabel+"=true")
+	}
+	sort.Strings(labels)
+	return strings.Join(labels, ",")
+}
+
+// safeSysctls are the sysctls pods are always allowed to set, see
+// https://kubernetes.io/docs/tasks/administer-cluster/sysctl-cluster/
+var safeSysctls = map[string]bool{
+	"kernel.shm_rmid_forced":              true,
+	"net.ipv4.ip_local_port_range":        true,
+	"net.ipv4.tcp_syncookies":             true,
+	"net.ipv4.ping_group_range":           true,
+	"net.ipv4.ip_unprivileged_port_start": true,
+}
+
+// unsafeSysctls returns the sysctls of the node that are not safe in the
+// format of the kubelet --allowed-unsafe-sysctls flag, sorted for
+// predictable results, so that pods are allowed to set them as well
+func unsafeSysctls(configNode *nodeReplica) string {
+	sysctls := []string{}
+	for name := range configNode.Sysctls {
+		if !safeSysctls[name] {
+			sysctls = append(sysctls, name)
+		}
+	}
+	sort.Strings(sysctls)
+	return strings.Join(sysctls, ",")
+}
+
+// nodeTaints returns the taints of the node in the format of the
+// kubelet --register-with-taints flag
+func nodeTaints(configNode *nodeReplica) string {
+	taints := []string{}
+	for _, taint := range configNode.Taints {
+		taints = append(taints, taintString(taint))
+	}
+	return strings.Join(taints, ",")
+}
+
+// taintString returns the taint in the <key>=<value>:<effect> format used by
+// the kubelet and kubectl, the value is omitted if empty
+func taintString(taint config.Taint) string {
+	if taint.Value == "" {
+		return fmt.Sprintf("%s:%s", taint.Key, taint.Effect)
+	}
+	return fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestUnsafeSysctls(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Sysctls  map[string]string
+		Expected string
+	}{
+		{
+			Name:     "no sysctls",
+			Expected: "",
+		},
+		{
+			Name: "safe sysctls",
+			Sysctls: map[string]string{
+				"net.ipv4.ip_local_port_range": "1024 65535",
+			},
+			Expected: "",
+		},
+		{
+			Name: "unsafe sysctls",
+			Sysctls: map[string]string{
+				"net.ipv4.tcp_syncookies": "1",
+				"net.core.somaxconn":      "65535",
+				"kernel.shmmax":           "68719476736",
+			},
+			Expected: "kernel.shmmax,net.core.somaxconn",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			configNode := &nodeReplica{Node: config.Node{Sysctls: tc.Sysctls}}
+			if result := unsafeSysctls(configNode); result != tc.Expected {
+				t.Errorf("expected %q but got %q", tc.Expected, result)
+			}
+		})
+	}
+}
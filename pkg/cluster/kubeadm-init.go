@@ -17,21 +17,22 @@ limitations under the License.
 package cluster
 
 import (
+	"bytes"
 	"fmt"
-	"strings"
 	"time"
 
-	log "github.com/sirupsen/logrus"
-
 	"github.com/pkg/errors"
 
+	"sigs.k8s.io/kind/pkg/cluster/config"
 	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
 	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/kustomize"
+	logutil "sigs.k8s.io/kind/pkg/log"
 )
 
 // kubeadmInitAction implements action for executing the kubadm init
-// and a set of default post init operations like e.g. install the
-// CNI network plugin.
+// and a set of default post init operations like e.g. adding the
+// default storage class.
 type kubeadmInitAction struct{}
 
 func init() {
@@ -64,18 +65,50 @@ func runKubeadmInit(ec *execContext, configNode *nodeReplica) error {
 		return fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
 	}
 
-	// run kubeadm
-	if err := node.Command(
+	args := []string{
 		// init because this is the control plane node
-		"kubeadm", "init",
+		"init",
 		// preflight errors are expected, in particular for swap being enabled
 		// TODO(bentheelder): limit the set of acceptable errors
 		"--ignore-preflight-errors=all",
 		// specify our generated config file
-		"--config=/kind/kubeadm.conf",
-	).Run(); err != nil {
+		"--config=" + kubeadmConfigPath,
+	}
+
+	// kube-proxy is not installed if disabled, older versions can not skip
+	// the kube-proxy addon, which is deleted once installed instead
+	deleteKubeProxy := false
+	if ec.config.Networking.KubeProxyMode == config.NoneProxyMode {
+		kubeVersion, err := node.KubeVersion()
+		if err != nil {
+			return errors.Wrap(err, "failed to get kubernetes version from node")
+		}
+		skipPhases, err := kubeadm.SupportsSkipPhases(kubeVersion)
+		if err != nil {
+			return err
+		}
+		if skipPhases {
+			args = append(args, "--skip-phases=addon/kube-proxy")
+		} else {
+			deleteKubeProxy = true
+		}
+	}
+
+	// run kubeadm
+	if err := runKubeadm(node, "/var/log/kubeadm-init.log", newTimeouts(&ec.config.Timeouts).KubeadmInit, args...); err != nil {
 		return errors.Wrap(err, "failed to init node with kubeadm")
 	}
+	if err := applyKubeletConfigPatches(node, configNode); err != nil {
+		return err
+	}
+	if deleteKubeProxy {
+		if err := node.Command(
+			"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+			"delete", "--namespace=kube-system", "daemonset", "kube-proxy",
+		).Run(); err != nil {
+			return errors.Wrap(err, "failed to delete kube-proxy")
+		}
+	}
 
 	// copies the kubeconfig files locally in order to make the cluster
 	// usable with kubectl.
@@ -83,26 +116,26 @@ func runKubeadmInit(ec *execContext, configNode *nodeReplica) error {
 	// must be modified in order to use the random host port reserved
 	// for the API server and exposed by the node
 
-	// retrives the random host where the API server is exposed
-	// TODO(fabrizio pandini): when external load-balancer will be
-	//      implemented this should be modified accordingly
-	hostPort, err := node.Ports(kubeadm.APIServerPort)
+	// retrives the random host where the API server is exposed, this is
+	// the external load balancer port if any
+	apiServer, err := apiServerNode(ec.derived, ec.nodes)
+	if err != nil {
+		return err
+	}
+	hostPort, err := apiServer.Ports(kubeadm.APIServerPort)
 	if err != nil {
 		return errors.Wrap(err, "failed to get kubeconfig from node")
 	}
 
 	kubeConfigPath := ec.KubeConfigPath()
-	if err := node.WriteKubeConfig(kubeConfigPath, hostPort); err != nil {
+	if err := node.WriteKubeConfig(kubeConfigPath, ec.config.Networking.APIServerAddress, hostPort, ec.config.Networking.APIServerCertSANs...); err != nil {
 		return errors.Wrap(err, "failed to get kubeconfig from node")
 	}
-
-	// install the CNI network plugin
-	// TODO(bentheelder): support other overlay networks
-	if err := node.Command(
-		"/bin/sh", "-c",
-		`kubectl apply --kubeconfig=/etc/kubernetes/admin.conf -f "https://cloud.weave.works/k8s/net?k8s-version=$(kubectl version --kubeconfig=/etc/kubernetes/admin.conf | base64 | tr -d '\n')"`,
-	).Run(); err != nil {
-		return errors.Wrap(err, "failed to apply overlay network")
+	if err := addOIDCContext(kubeConfigPath, &ec.config.OIDC); err != nil {
+		return errors.Wrap(err, "failed to add the OIDC context to the kubeconfig")
+	}
+	if err := ec.writeInternalKubeConfig(); err != nil {
+		return err
 	}
 
 	// if we are only provisioning one node, remove the master taint
@@ -116,28 +149,69 @@ func runKubeadmInit(ec *execContext, configNode *nodeReplica) error {
 		}
 	}
 
-	// add the default storage class
-	if err := addDefaultStorageClass(node); err != nil {
-		return errors.Wrap(err, "failed to add default storage class")
+	// install the default storage class and its provisioner
+	if err := installStorage(node, &ec.config.Storage); err != nil {
+		return errors.Wrap(err, "failed to install the default storage class")
 	}
 
-	// Wait for the control plane node to reach Ready status.
-	isReady := nodes.WaitForReady(node, time.Now().Add(ec.waitForReady))
-	if ec.waitForReady > 0 {
-		if !isReady {
-			log.Warn("timed out waiting for control plane to be ready")
+	return nil
+}
+
+// runKubeadm runs kubeadm with args on the node, recording the output to
+// logPath on the node so that it is collected along with the other node logs.
+// kubeadm is killed after timeout, if not 0
+func runKubeadm(node *nodes.Node, logPath string, timeout time.Duration, args ...string) error {
+	var buff bytes.Buffer
+	cmd := node.Command("kubeadm", args...)
+	if timeout > 0 {
+		cmd = node.Command("timeout", append([]string{fmt.Sprintf("%gs", timeout.Seconds()), "kubeadm"}, args...)...)
+	}
+	cmd.SetStdout(&buff)
+	cmd.SetStderr(&buff)
+	start := time.Now()
+	err := cmd.Run()
+	if err != nil && timeout > 0 && time.Since(start) >= timeout {
+		err = errors.Wrapf(err, "timed out after %s", timeout)
+	}
+	logutil.Debugf("kubeadm output:\n%s", buff.String())
+	if writeErr := node.WriteFile(logPath, buff.Bytes()); writeErr != nil {
+		logutil.Warnf("Failed to record kubeadm output on node %s: %v", node.String(), writeErr)
+	}
+	if err != nil {
+		return &KubeadmError{
+			Node:   node.String(),
+			Args:   args,
+			Output: buff.String(),
+			Err:    err,
 		}
 	}
-
 	return nil
 }
 
-func addDefaultStorageClass(controlPlane *nodes.Node) error {
-	in := strings.NewReader(defaultStorageClassManifest)
-	cmd := controlPlane.Command(
-		"kubectl",
-		"--kubeconfig=/etc/kubernetes/admin.conf", "apply", "-f", "-",
-	)
-	cmd.SetStdin(in)
-	return cmd.Run()
+// kubeletConfigPath is the kubelet config file written by kubeadm on the nodes
+const kubeletConfigPath = "/var/lib/kubelet/config.yaml"
+
+// applyKubeletConfigPatches applies the kubelet config patches of the node,
+// if any, to the kubelet config written by kubeadm, which is the same for
+// all the nodes, and restarts the kubelet with the patched config
+func applyKubeletConfigPatches(node *nodes.Node, configNode *nodeReplica) error {
+	if len(configNode.KubeletConfigPatches) == 0 {
+		return nil
+	}
+	kubeletConfig, err := node.ReadFile(kubeletConfigPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the kubelet config of node %s", node)
+	}
+	patched, err := kustomize.Build([]string{string(kubeletConfig)}, configNode.KubeletConfigPatches, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to patch the kubelet config of node %s", node)
+	}
+	logutil.Debugf("Using KubeletConfiguration for node %s:\n\n%s\n", configNode.Name, patched)
+	if err := node.WriteFile(kubeletConfigPath, []byte(patched)); err != nil {
+		return errors.Wrapf(err, "failed to write the kubelet config of node %s", node)
+	}
+	if err := node.Command("systemctl", "restart", "kubelet").Run(); err != nil {
+		return errors.Wrapf(err, "failed to restart the kubelet of node %s", node)
+	}
+	return nil
 }
@@ -17,11 +17,16 @@ limitations under the License.
 package cluster
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
 
 	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
 )
 
 // kubeadmJoinAction implements action for joining nodes
@@ -45,28 +50,106 @@ func (b *kubeadmJoinAction) Tasks() []task {
 		{
 			// Run kubeadm join on the WorkeNodes
 			Description: "Joining worker node to Kubernetes ☸",
-			TargetNodes: selectWorkerNodes,
+			TargetNodes: selectLinuxWorkerNodes,
 			Run:         runKubeadmJoin,
 		},
+		{
+			// Run kubeadm join on the Windows WorkerNodes
+			Description: "Joining Windows worker node to Kubernetes ☸",
+			TargetNodes: selectWindowsWorkerNodes,
+			Run:         runKubeadmJoinWindows,
+		},
 	}
 }
 
 // runKubeadmJoin executes kubadm join
 func runKubeadmJoin(ec *execContext, configNode *nodeReplica) error {
-	// before running join, it should be retrived
+	// before running join, the address of the API server should be retrived,
+	// this is the external load balancer if any, or the bootstrap control-plane
+	controlPlaneEndpoint, err := ec.controlPlaneEndpoint()
+	if err != nil {
+		return err
+	}
 
-	// gets the node where
-	// TODO(fabrizio pandini): when external load-balancer will be
-	//      implemented this should be modified accordingly
-	controlPlaneHandle, ok := ec.NodeFor(ec.derived.BootStrapControlPlane())
+	// get the target node for this task
+	node, ok := ec.NodeFor(configNode)
 	if !ok {
-		return fmt.Errorf("unable to get the handle for operating on node: %s", ec.derived.BootStrapControlPlane().Name)
+		return fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+
+	// the node may be older than the control-plane, in which case kubeadm
+	// join needs the kubelet config for the node version
+	if err := ec.ensureKubeletConfig(node); err != nil {
+		return err
 	}
 
-	// gets the IP of the bootstrap master node
-	controlPlaneIP, err := controlPlaneHandle.IP()
+	// TODO(fabrizio pandini): might be we want to run pre-kubeadm hooks on workers too
+
+	// the kubeadm config can configure join only for recent versions,
+	// older versions are configured with flags
+	kubeVersion, err := node.KubeVersion()
+	if err != nil {
+		return errors.Wrap(err, "failed to get kubernetes version from node")
+	}
+	useConfig, err := kubeadm.SupportsJoinConfig(kubeVersion)
 	if err != nil {
-		return errors.Wrap(err, "failed to get IP for node")
+		return err
+	}
+	args := []string{
+		"join",
+		// specify our generated config file, this includes the discovery
+		// settings below and the kubeadm config patches
+		"--config=" + kubeadmConfigPath,
+	}
+	if !useConfig {
+		args = []string{
+			"join",
+			// the control plane address uses the docker ip and a well know APIServerPort that
+			// are accessible only inside the docker network
+			controlPlaneEndpoint,
+			// uses a well known token and skipping ca certification for automating TLS bootstrap process
+			"--token", kubeadm.Token,
+			"--discovery-token-unsafe-skip-ca-verification",
+		}
+	}
+	// preflight errors are expected, in particular for swap being enabled
+	// TODO(bentheelder): limit the set of acceptable errors
+	args = append(args, "--ignore-preflight-errors=all")
+
+	// run kubeadm
+	if err := runKubeadm(node, "/var/log/kubeadm-join.log", 0, args...); err != nil {
+		return errors.Wrap(err, "failed to join node with kubeadm")
+	}
+	if err := applyKubeletConfigPatches(node, configNode); err != nil {
+		return err
+	}
+
+	// older versions are joined with flags, so the labels and taints are not
+	// set by the kubelet, and are applied once the node is registered instead
+	if !useConfig {
+		if err := ec.applyLabelsAndTaints(node, configNode); err != nil {
+			return err
+		}
+	}
+
+	// TODO(fabrizio pandini): might be we want to run post-kubeadm hooks on workers too
+
+	// TODO(fabrizio pandini): might be we want to run post-setup hooks on workers too
+
+	return nil
+}
+
+// windowsCRISocket is the containerd named pipe in the Windows node image
+const windowsCRISocket = "npipe:////./pipe/containerd-containerd"
+
+// runKubeadmJoinWindows executes kubeadm join on a Windows worker node. The
+// generated kubeadm config is written only to the Linux nodes, so Windows
+// nodes are joined with flags and the labels and taints are applied once
+// the node is registered
+func runKubeadmJoinWindows(ec *execContext, configNode *nodeReplica) error {
+	controlPlaneEndpoint, err := ec.controlPlaneEndpoint()
+	if err != nil {
+		return err
 	}
 
 	// get the target node for this task
@@ -75,27 +158,131 @@ func runKubeadmJoin(ec *execContext, configNode *nodeReplica) error {
 		return fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
 	}
 
-	// TODO(fabrizio pandini): might be we want to run pre-kubeadm hooks on workers too
+	// the node may be older than the control-plane, in which case kubeadm
+	// join needs the kubelet config for the node version
+	if err := ec.ensureKubeletConfig(node); err != nil {
+		return err
+	}
 
-	// run kubeadm
-	if err := node.Command(
-		"kubeadm", "join",
-		// the control plane address uses the docker ip and a well know APIServerPort that
-		// are accessible only inside the docker network
-		fmt.Sprintf("%s:%d", controlPlaneIP, kubeadm.APIServerPort),
+	var buff bytes.Buffer
+	cmd := node.Command(
+		"kubeadm.exe", "join",
+		controlPlaneEndpoint,
 		// uses a well known token and skipping ca certification for automating TLS bootstrap process
 		"--token", kubeadm.Token,
 		"--discovery-token-unsafe-skip-ca-verification",
-		// preflight errors are expected, in particular for swap being enabled
-		// TODO(bentheelder): limit the set of acceptable errors
+		"--cri-socket", windowsCRISocket,
+		// preflight errors are expected, as for the Linux nodes
 		"--ignore-preflight-errors=all",
+	)
+	cmd.SetStdout(&buff)
+	cmd.SetStderr(&buff)
+	err = cmd.Run()
+	ec.Logger().Debugf("kubeadm output:\n%s", buff.String())
+	if err != nil {
+		return errors.Wrap(&KubeadmError{
+			Node:   node.String(),
+			Args:   []string{"join"},
+			Output: buff.String(),
+			Err:    err,
+		}, "failed to join Windows node with kubeadm")
+	}
+
+	return ec.applyLabelsAndTaints(node, configNode)
+}
+
+// ensureKubeletConfig ensures the kubelet config for the Kubernetes version
+// of node exists in the cluster. kubeadm join reads the kubelet config from
+// the kubelet-config-<major>.<minor> ConfigMap for the version of the node,
+// but kubeadm init only creates the one for the control-plane version,
+// so for older nodes it is copied from the control-plane one
+func (ec *execContext) ensureKubeletConfig(node *nodes.Node) error {
+	controlPlane, ok := ec.NodeFor(ec.derived.BootStrapControlPlane())
+	if !ok {
+		return fmt.Errorf("unable to get the handle for operating on node: %s", ec.derived.BootStrapControlPlane().Name)
+	}
+	controlPlaneVersion, err := kubeletConfigVersion(controlPlane)
+	if err != nil {
+		return err
+	}
+	nodeVersion, err := kubeletConfigVersion(node)
+	if err != nil {
+		return err
+	}
+	if nodeVersion == controlPlaneVersion {
+		return nil
+	}
+
+	// copy the ConfigMap along with the RBAC rules allowing to read it,
+	// removing the fields identifying the original objects
+	from, to := "kubelet-config-"+controlPlaneVersion, "kubelet-config-"+nodeVersion
+	if err := controlPlane.Command(
+		"/bin/sh", "-c",
+		fmt.Sprintf(
+			"kubectl --kubeconfig=/etc/kubernetes/admin.conf -n kube-system get -o yaml configmap/%[1]s role/kubeadm:%[1]s rolebinding/kubeadm:%[1]s"+
+				" | sed -e 's/%[1]s/%[2]s/g' -e '/resourceVersion:/d' -e '/uid:/d' -e '/selfLink:/d' -e '/creationTimestamp:/d'"+
+				" | kubectl --kubeconfig=/etc/kubernetes/admin.conf apply -f -",
+			from, to,
+		),
 	).Run(); err != nil {
-		return errors.Wrap(err, "failed to join node with kubeadm")
+		return errors.Wrapf(err, "failed to create %s", to)
 	}
+	return nil
+}
 
-	// TODO(fabrizio pandini): might be we want to run post-kubeadm hooks on workers too
+// kubeletConfigVersion returns the <major>.<minor> Kubernetes version of
+// the node, as used in the kubelet config ConfigMap name
+func kubeletConfigVersion(node *nodes.Node) (string, error) {
+	kubeVersion, err := node.KubeVersion()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get kubernetes version from node %s", node.String())
+	}
+	v, err := version.ParseGeneric(kubeVersion)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid kubernetes version for node %s", node.String())
+	}
+	return fmt.Sprintf("%d.%d", v.Major(), v.Minor()), nil
+}
 
-	// TODO(fabrizio pandini): might be we want to run post-setup hooks on workers too
+// applyLabelsAndTaints applies the labels and taints of the node with kubectl
+// on the bootstrap control plane, once the node is registered
+func (ec *execContext) applyLabelsAndTaints(node *nodes.Node, configNode *nodeReplica) error {
+	if len(configNode.Labels) == 0 && !configNode.IngressReady && len(configNode.Taints) == 0 {
+		return nil
+	}
+	controlPlane, ok := ec.NodeFor(ec.derived.BootStrapControlPlane())
+	if !ok {
+		return fmt.Errorf("unable to get the handle for operating on node: %s", ec.derived.BootStrapControlPlane().Name)
+	}
+
+	// wait for the node to be registered
+	registered := false
+	timeouts := newTimeouts(&ec.config.Timeouts)
+	for until := time.Now().Add(timeouts.NodeRegistration); until.After(time.Now()); time.Sleep(timeouts.PollInterval) {
+		if controlPlane.Command(
+			"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf", "get", "node", node.String(),
+		).Run() == nil {
+			registered = true
+			break
+		}
+	}
+	if !registered {
+		return &NodeNotReadyError{Node: node.String(), Condition: "the node to be registered"}
+	}
 
+	if len(configNode.Labels) > 0 || configNode.IngressReady {
+		args := []string{"--kubeconfig=/etc/kubernetes/admin.conf", "label", "node", node.String(), "--overwrite"}
+		args = append(args, strings.Split(nodeLabels(configNode), ",")...)
+		if err := controlPlane.Command("kubectl", args...).Run(); err != nil {
+			return errors.Wrap(err, "failed to label node")
+		}
+	}
+	if len(configNode.Taints) > 0 {
+		args := []string{"--kubeconfig=/etc/kubernetes/admin.conf", "taint", "node", node.String(), "--overwrite"}
+		args = append(args, strings.Split(nodeTaints(configNode), ",")...)
+		if err := controlPlane.Command("kubectl", args...).Run(); err != nil {
+			return errors.Wrap(err, "failed to taint node")
+		}
+	}
 	return nil
 }
@@ -18,6 +18,8 @@ package kubeadm
 
 import (
 	"bytes"
+	"fmt"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -32,8 +34,78 @@ type ConfigData struct {
 	KubernetesVersion string
 	// The API Server port
 	APIBindPort int
+	// APIServerAddress is the host address the API server is published on,
+	// if set this is added to the API server certificate SANs
+	APIServerAddress string
+	// APIServerCertSANs are additional API server certificate SANs
+	APIServerCertSANs []string
+	// PodSubnet and ServiceSubnet are the CIDR ranges of the pod and
+	// service IPs, the kubeadm defaults are used if not set
+	PodSubnet     string
+	ServiceSubnet string
+	// IPFamily is the IP family of the cluster, either "ipv4", "ipv6" or
+	// "dual" for dual-stack, IPv4 is assumed if empty
+	IPFamily string
+	// KubeProxyMode is the kube-proxy mode, the kube-proxy default is used
+	// if empty, and kube-proxy is not configured if "none"
+	KubeProxyMode string
+	// NodeAddress is the IP address of the node in the docker network, if
+	// set this is the API server advertise address and the kubelet node IP
+	// instead of the addresses detected by kubeadm and the kubelet
+	NodeAddress string
+	// ControlPlaneEndpoint is the address of the external load balancer
+	// for the API server, if any
+	ControlPlaneEndpoint string
 	// The Token for TLS bootstrap
 	Token string
+	// JoinEndpoint is the address of the API server used by kubeadm join
+	// for discovery, either the external load balancer or the bootstrap
+	// control plane
+	JoinEndpoint string
+	// NodeLabels and NodeTaints are the values of the kubelet --node-labels
+	// and --register-with-taints flags for the node, if any
+	NodeLabels string
+	NodeTaints string
+	// SystemReserved is the value of the kubelet --system-reserved flag for
+	// the node, if any, this is used to limit the node allocatable
+	SystemReserved string
+	// AllowedUnsafeSysctls is the value of the kubelet
+	// --allowed-unsafe-sysctls flag for the node, if any
+	AllowedUnsafeSysctls string
+	// CPUManagerPolicy, ReservedCPUs and TopologyManagerPolicy are the
+	// values of the kubelet --cpu-manager-policy, --reserved-cpus and
+	// --topology-manager-policy flags for the node, if any
+	CPUManagerPolicy      string
+	ReservedCPUs          string
+	TopologyManagerPolicy string
+	// KubeletExtraArgs are additional kubelet flags of the node, these take
+	// precedence over the flags above
+	KubeletExtraArgs map[string]string
+	// FeatureGates are the Kubernetes feature gates for all the components
+	FeatureGates map[string]bool
+	// RuntimeConfig are the API server --runtime-config values
+	RuntimeConfig map[string]string
+	// EnableAdmissionPlugins and DisableAdmissionPlugins are the admission
+	// plugins enabled and disabled in the API server, if any
+	EnableAdmissionPlugins  []string
+	DisableAdmissionPlugins []string
+	// ExternalEtcdEndpoints are the client URLs of the external etcd
+	// members, if empty kubeadm runs etcd on the control plane
+	ExternalEtcdEndpoints []string
+	// Audit is true if the API server audit logging is enabled, with the
+	// policy at AuditPolicyFile and the log in AuditLogDir
+	Audit bool
+	// Encryption is true if the encryption at rest is enabled, with the
+	// config at EncryptionConfigFile
+	Encryption bool
+	// OIDCArgs are the API server OpenID Connect flags, if any
+	OIDCArgs map[string]string
+	// ClusterSigningDuration is the validity of the certificates signed by
+	// the controller manager, the controller manager default if empty
+	ClusterSigningDuration string
+	// Rootless is true if the nodes are run by a rootless runtime, in a user
+	// namespace where the components can not set some of the host sysctls
+	Rootless bool
 	// DerivedConfigData is populated by Derive()
 	// These auto-generated fields are available to Config templates,
 	// but not meant to be set by hand
@@ -45,13 +117,150 @@ type ConfigData struct {
 type DerivedConfigData struct {
 	// DockerStableTag is automatically derived from KubernetesVersion
 	DockerStableTag string
+	// FeatureGatesFlag and RuntimeConfigFlag are derived from FeatureGates
+	// and RuntimeConfig in the format of the component flags
+	FeatureGatesFlag  string
+	RuntimeConfigFlag string
+	// EnableAdmissionPluginsFlag and DisableAdmissionPluginsFlag are
+	// derived from EnableAdmissionPlugins and DisableAdmissionPlugins in the
+	// format of the API server flags, the enabled plugins include
+	// NodeRestriction, which kubeadm enables by default, unless disabled
+	EnableAdmissionPluginsFlag  string
+	DisableAdmissionPluginsFlag string
+	// IPv6 and DualStack are derived from IPFamily
+	IPv6      bool
+	DualStack bool
+	// KubeProxyModeConfig is derived from KubeProxyMode, this is the mode
+	// in the kube-proxy configuration, empty if kube-proxy is disabled
+	KubeProxyModeConfig string
+	// ExternalEtcdCAFile, ExternalEtcdCertFile and ExternalEtcdKeyFile
+	// are the paths of the external etcd client certificates
+	ExternalEtcdCAFile   string
+	ExternalEtcdCertFile string
+	ExternalEtcdKeyFile  string
+	// EncryptionProviderConfigFlag is the name of the API server flag of the
+	// encryption provider config, which is experimental in older versions,
+	// derived from KubernetesVersion
+	EncryptionProviderConfigFlag string
+	// ClusterSigningDurationFlag is the name of the controller manager flag
+	// of ClusterSigningDuration, which is experimental in older versions,
+	// derived from KubernetesVersion
+	ClusterSigningDurationFlag string
+	// KubeletArgs are the kubelet flags of the node registration, derived
+	// from NodeAddress, NodeLabels, NodeTaints, SystemReserved,
+	// AllowedUnsafeSysctls, the CPU manager flags and KubeletExtraArgs
+	KubeletArgs map[string]string
 }
 
-// Derive automatically derives DockerStableTag if not specified
+// Derive automatically derives DockerStableTag, the feature gates and
+// runtime config flags, and the external etcd client certificate paths
+// if not specified, as well as the IP family settings and subnets, and the
+// kubelet flags of the node registration
 func (c *ConfigData) Derive() {
 	if c.DockerStableTag == "" {
 		c.DockerStableTag = strings.Replace(c.KubernetesVersion, "+", "_", -1)
 	}
+	if c.KubeProxyModeConfig == "" && c.KubeProxyMode != "none" {
+		c.KubeProxyModeConfig = c.KubeProxyMode
+	}
+	c.IPv6 = c.IPFamily == "ipv6"
+	c.DualStack = c.IPFamily == "dual"
+	if c.IPv6 {
+		if c.PodSubnet == "" {
+			c.PodSubnet = IPv6PodSubnet
+		}
+		if c.ServiceSubnet == "" {
+			c.ServiceSubnet = IPv6ServiceSubnet
+		}
+	}
+	if c.DualStack {
+		if c.PodSubnet == "" {
+			c.PodSubnet = DualStackPodSubnet
+		}
+		// dual-stack is an alpha feature, enable it unless explicitly set
+		if _, ok := c.FeatureGates["IPv6DualStack"]; !ok {
+			featureGates := map[string]bool{"IPv6DualStack": true}
+			for name, enabled := range c.FeatureGates {
+				featureGates[name] = enabled
+			}
+			c.FeatureGates = featureGates
+		}
+	}
+	if c.FeatureGatesFlag == "" {
+		featureGates := []string{}
+		for name, enabled := range c.FeatureGates {
+			featureGates = append(featureGates, fmt.Sprintf("%s=%t", name, enabled))
+		}
+		sort.Strings(featureGates)
+		c.FeatureGatesFlag = strings.Join(featureGates, ",")
+	}
+	if c.RuntimeConfigFlag == "" {
+		runtimeConfig := []string{}
+		for key, value := range c.RuntimeConfig {
+			runtimeConfig = append(runtimeConfig, fmt.Sprintf("%s=%s", key, value))
+		}
+		sort.Strings(runtimeConfig)
+		c.RuntimeConfigFlag = strings.Join(runtimeConfig, ",")
+	}
+	if c.EnableAdmissionPluginsFlag == "" && len(c.EnableAdmissionPlugins) > 0 {
+		// the flag overrides the kubeadm default, which enables NodeRestriction
+		plugins := []string{}
+		skip := map[string]bool{}
+		for _, plugin := range c.DisableAdmissionPlugins {
+			skip[plugin] = true
+		}
+		for _, plugin := range append([]string{"NodeRestriction"}, c.EnableAdmissionPlugins...) {
+			if !skip[plugin] {
+				plugins = append(plugins, plugin)
+				skip[plugin] = true
+			}
+		}
+		c.EnableAdmissionPluginsFlag = strings.Join(plugins, ",")
+	}
+	if c.DisableAdmissionPluginsFlag == "" {
+		c.DisableAdmissionPluginsFlag = strings.Join(c.DisableAdmissionPlugins, ",")
+	}
+	if c.ExternalEtcdCAFile == "" {
+		c.ExternalEtcdCAFile = ExternalEtcdCAFile
+	}
+	if c.ExternalEtcdCertFile == "" {
+		c.ExternalEtcdCertFile = ExternalEtcdCertFile
+	}
+	if c.ExternalEtcdKeyFile == "" {
+		c.ExternalEtcdKeyFile = ExternalEtcdKeyFile
+	}
+	if c.EncryptionProviderConfigFlag == "" {
+		c.EncryptionProviderConfigFlag = "encryption-provider-config"
+		if ver, err := version.ParseGeneric(c.KubernetesVersion); err == nil && !ver.AtLeast(version.MustParseSemantic("v1.13.0")) {
+			c.EncryptionProviderConfigFlag = "experimental-encryption-provider-config"
+		}
+	}
+	if c.ClusterSigningDurationFlag == "" {
+		c.ClusterSigningDurationFlag = "cluster-signing-duration"
+		if ver, err := version.ParseGeneric(c.KubernetesVersion); err == nil && !ver.AtLeast(version.MustParseSemantic("v1.19.0")) {
+			c.ClusterSigningDurationFlag = "experimental-cluster-signing-duration"
+		}
+	}
+	if c.KubeletArgs == nil {
+		c.KubeletArgs = map[string]string{}
+		for flag, value := range map[string]string{
+			"node-ip":                 c.NodeAddress,
+			"node-labels":             c.NodeLabels,
+			"register-with-taints":    c.NodeTaints,
+			"system-reserved":         c.SystemReserved,
+			"allowed-unsafe-sysctls":  c.AllowedUnsafeSysctls,
+			"cpu-manager-policy":      c.CPUManagerPolicy,
+			"reserved-cpus":           c.ReservedCPUs,
+			"topology-manager-policy": c.TopologyManagerPolicy,
+		} {
+			if value != "" {
+				c.KubeletArgs[flag] = value
+			}
+		}
+		for flag, value := range c.KubeletExtraArgs {
+			c.KubeletArgs[flag] = value
+		}
+	}
 }
 
 // See docs for these APIs at:
@@ -59,6 +268,126 @@ func (c *ConfigData) Derive() {
 // EG:
 // https://godoc.org/k8s.io/kubernetes/cmd/kubeadm/app/apis/kubeadm/v1beta1
 
+// nodeRegistrationTemplate is the nodeRegistration of the kubeadm init and
+// join configurations, this is shared by all the config templates
+const nodeRegistrationTemplate = `{{- if .KubeletArgs }}
+nodeRegistration:
+  kubeletExtraArgs:
+{{- range $flag, $value := .KubeletArgs }}
+    {{ $flag }}: "{{ $value }}"
+{{- end }}
+{{- end }}`
+
+// extraVolumesTemplateAlpha is the API server extra volumes of the audit
+// policy and log, and of the encryption config and KMS plugin socket, this is
+// shared by the alpha config templates
+const extraVolumesTemplateAlpha = `{{- if .Audit }}
+- name: audit-policy
+  mountPath: "` + AuditPolicyFile + `"
+  hostPath: "` + AuditPolicyFile + `"
+  pathType: File
+- name: audit-log
+  mountPath: "` + AuditLogDir + `"
+  hostPath: "` + AuditLogDir + `"
+  writable: true
+  pathType: DirectoryOrCreate
+{{- end }}
+{{- if .Encryption }}
+- name: encryption-config
+  mountPath: "` + EncryptionConfigFile + `"
+  hostPath: "` + EncryptionConfigFile + `"
+  pathType: File
+- name: kms-plugin
+  mountPath: "` + KMSPluginDir + `"
+  hostPath: "` + KMSPluginDir + `"
+  writable: true
+  pathType: DirectoryOrCreate
+{{- end }}`
+
+// extraArgsTemplateAlpha is the control plane components extra args for the
+// feature gates, runtime config, admission plugins, audit logging, encryption
+// at rest, OpenID Connect and signing duration, this is shared by the alpha
+// config templates
+const extraArgsTemplateAlpha = `{{- if or .FeatureGatesFlag .RuntimeConfigFlag .EnableAdmissionPluginsFlag .DisableAdmissionPluginsFlag .Audit .Encryption .OIDCArgs }}
+apiServerExtraArgs:
+{{- if .FeatureGatesFlag }}
+  feature-gates: "{{ .FeatureGatesFlag }}"
+{{- end }}
+{{- if .RuntimeConfigFlag }}
+  runtime-config: "{{ .RuntimeConfigFlag }}"
+{{- end }}
+{{- if .EnableAdmissionPluginsFlag }}
+  enable-admission-plugins: "{{ .EnableAdmissionPluginsFlag }}"
+{{- end }}
+{{- if .DisableAdmissionPluginsFlag }}
+  disable-admission-plugins: "{{ .DisableAdmissionPluginsFlag }}"
+{{- end }}
+{{- if .Audit }}
+  audit-policy-file: "` + AuditPolicyFile + `"
+  audit-log-path: "` + AuditLogDir + `/audit.log"
+  audit-log-maxage: "7"
+  audit-log-maxbackup: "3"
+  audit-log-maxsize: "100"
+{{- end }}
+{{- if .Encryption }}
+  {{ .EncryptionProviderConfigFlag }}: "` + EncryptionConfigFile + `"
+{{- end }}
+{{- range $flag, $value := .OIDCArgs }}
+  {{ $flag }}: "{{ $value }}"
+{{- end }}
+{{- end }}
+{{- if or .FeatureGatesFlag .ClusterSigningDuration }}
+controllerManagerExtraArgs:
+{{- if .FeatureGatesFlag }}
+  feature-gates: "{{ .FeatureGatesFlag }}"
+{{- end }}
+{{- if .ClusterSigningDuration }}
+  {{ .ClusterSigningDurationFlag }}: "{{ .ClusterSigningDuration }}"
+{{- end }}
+{{- end }}
+{{- if .FeatureGatesFlag }}
+schedulerExtraArgs:
+  feature-gates: "{{ .FeatureGatesFlag }}"
+{{- end }}`
+
+// networkingTemplate is the networking of the cluster configuration,
+// this is shared by all the config templates
+const networkingTemplate = `{{- if or .PodSubnet .ServiceSubnet }}
+networking:
+{{- if .PodSubnet }}
+  podSubnet: "{{ .PodSubnet }}"
+{{- end }}
+{{- if .ServiceSubnet }}
+  serviceSubnet: "{{ .ServiceSubnet }}"
+{{- end }}
+{{- end }}`
+
+// kubeProxyModeTemplate is the mode of the kube-proxy configuration, this is
+// shared by the config templates with a KubeProxyConfiguration
+const kubeProxyModeTemplate = `{{- if .KubeProxyModeConfig }}
+mode: "{{ .KubeProxyModeConfig }}"
+{{- end }}`
+
+// kubeProxyConntrackTemplate is the conntrack of the kube-proxy
+// configuration, kube-proxy skips setting the conntrack sysctls it can not set
+// in the user namespace of rootless runtimes, this is shared by the config
+// templates with a KubeProxyConfiguration
+const kubeProxyConntrackTemplate = `{{- if .Rootless }}
+conntrack:
+  maxPerCore: 0
+  tcpEstablishedTimeout: 0s
+  tcpCloseWaitTimeout: 0s
+{{- end }}`
+
+// featureGatesTemplate is the featureGates of the kubelet and kube-proxy
+// configurations, this is shared by all the config templates
+const featureGatesTemplate = `{{- if .FeatureGates }}
+featureGates:
+{{- range $name, $enabled := .FeatureGates }}
+  {{ $name }}: {{ $enabled }}
+{{- end }}
+{{- end }}`
+
 // ConfigTemplateAlphaV1orV2 is the kubadm config template for API versions
 // v1alpha1 and v1alpha2
 const ConfigTemplateAlphaV1orV2 = `# config generated by kind
@@ -66,6 +395,7 @@ apiVersion: kubeadm.k8s.io/v1alpha2
 kind: MasterConfiguration
 kubernetesVersion: {{.KubernetesVersion}}
 clusterName: "{{.ClusterName}}"
+` + networkingTemplate + `
 # we use a well know token for TLS bootstrap
 bootstrapTokens:
 - token: "{{ .Token }}"
@@ -73,6 +403,9 @@ bootstrapTokens:
 # from the host machine such port will be accessible via a random local port instead.
 api:
   bindPort: {{.APIBindPort}}
+{{- if .ControlPlaneEndpoint }}
+  controlPlaneEndpoint: "{{ .ControlPlaneEndpoint }}"
+{{- end }}
 # we need nsswitch.conf so we use /etc/hosts
 # https://github.com/kubernetes/kubernetes/issues/69195
 apiServerExtraVolumes:
@@ -81,10 +414,50 @@ apiServerExtraVolumes:
   hostPath: /etc/nsswitch.conf
   writeable: false
   pathType: FileOrCreate
+` + extraVolumesTemplateAlpha + `
 # on docker for mac we have to expose the api server via port forward,
 # so we need to ensure the cert is valid for localhost so we can talk
 # to the cluster after rewriting the kubeconfig to point to localhost
-apiServerCertSANs: [localhost]
+apiServerCertSANs: [localhost{{ if .APIServerAddress }}, "{{ .APIServerAddress }}"{{ end }}{{ range .APIServerCertSANs }}, "{{ . }}"{{ end }}]
+` + extraArgsTemplateAlpha + `
+{{- if .FeatureGates }}
+kubeletConfiguration:
+  baseConfig:
+    featureGates:
+{{- range $name, $enabled := .FeatureGates }}
+      {{ $name }}: {{ $enabled }}
+{{- end }}
+{{- end }}
+{{- if or .FeatureGates .KubeProxyModeConfig .Rootless }}
+kubeProxy:
+  config:
+{{- if .KubeProxyModeConfig }}
+    mode: "{{ .KubeProxyModeConfig }}"
+{{- end }}
+{{- if .Rootless }}
+    conntrack:
+      maxPerCore: 0
+      tcpEstablishedTimeout: 0s
+      tcpCloseWaitTimeout: 0s
+{{- end }}
+{{- if .FeatureGates }}
+    featureGates:
+{{- range $name, $enabled := .FeatureGates }}
+      {{ $name }}: {{ $enabled }}
+{{- end }}
+{{- end }}
+{{- end }}
+` + nodeRegistrationTemplate + `
+{{ if .ExternalEtcdEndpoints -}}
+# use the external etcd cluster, the certificates are provisioned by kind
+etcd:
+  external:
+    endpoints:{{ range .ExternalEtcdEndpoints }}
+    - {{ . }}{{ end }}
+    caFile: {{ .ExternalEtcdCAFile }}
+    certFile: {{ .ExternalEtcdCertFile }}
+    keyFile: {{ .ExternalEtcdKeyFile }}
+{{ end -}}
 `
 
 // ConfigTemplateAlphaV3 is the kubadm config template for API version v1alpha3
@@ -93,6 +466,11 @@ apiVersion: kubeadm.k8s.io/v1alpha3
 kind: ClusterConfiguration
 kubernetesVersion: {{.KubernetesVersion}}
 clusterName: "{{.ClusterName}}"
+` + networkingTemplate + `
+{{- if .ControlPlaneEndpoint }}
+# the API server is reached through the external load balancer
+controlPlaneEndpoint: "{{ .ControlPlaneEndpoint }}"
+{{- end }}
 # we need nsswitch.conf so we use /etc/hosts
 # https://github.com/kubernetes/kubernetes/issues/69195
 apiServerExtraVolumes:
@@ -101,10 +479,22 @@ apiServerExtraVolumes:
   hostPath: /etc/nsswitch.conf
   writeable: false
   pathType: FileOrCreate
+` + extraVolumesTemplateAlpha + `
 # on docker for mac we have to expose the api server via port forward,
 # so we need to ensure the cert is valid for localhost so we can talk
 # to the cluster after rewriting the kubeconfig to point to localhost
-apiServerCertSANs: [localhost]
+apiServerCertSANs: [localhost{{ if .APIServerAddress }}, "{{ .APIServerAddress }}"{{ end }}{{ range .APIServerCertSANs }}, "{{ . }}"{{ end }}]
+` + extraArgsTemplateAlpha + `
+{{ if .ExternalEtcdEndpoints -}}
+# use the external etcd cluster, the certificates are provisioned by kind
+etcd:
+  external:
+    endpoints:{{ range .ExternalEtcdEndpoints }}
+    - {{ . }}{{ end }}
+    caFile: {{ .ExternalEtcdCAFile }}
+    certFile: {{ .ExternalEtcdCertFile }}
+    keyFile: {{ .ExternalEtcdKeyFile }}
+{{ end -}}
 ---
 apiVersion: kubeadm.k8s.io/v1alpha3
 kind: InitConfiguration
@@ -115,18 +505,28 @@ bootstrapTokens:
 # from the host machine such port will be accessible via a random local port instead.
 apiEndpoint:
   bindPort: {{.APIBindPort}}
+` + nodeRegistrationTemplate + `
 ---
-# no-op entry that exists soley so it can be patched
 apiVersion: kubeadm.k8s.io/v1alpha3
 kind: JoinConfiguration
+{{- if .JoinEndpoint }}
+# we use a well know token for TLS bootstrap, skipping CA verification
+token: "{{ .Token }}"
+discoveryTokenAPIServers:
+- "{{ .JoinEndpoint }}"
+discoveryTokenUnsafeSkipCAVerification: true
+{{- end }}
+` + nodeRegistrationTemplate + `
 ---
-# no-op entry that exists soley so it can be patched
+# this entry also exists so it can be patched
 apiVersion: kubelet.config.k8s.io/v1beta1
 kind: KubeletConfiguration
+` + featureGatesTemplate + `
 ---
-# no-op entry that exists soley so it can be patched
+# this entry also exists so it can be patched
 apiVersion: kubeproxy.config.k8s.io/v1alpha1
 kind: KubeProxyConfiguration
+` + kubeProxyModeTemplate + kubeProxyConntrackTemplate + featureGatesTemplate + `
 `
 
 // ConfigTemplateBetaV1 is the kubadm config template for API version v1beta1
@@ -135,11 +535,105 @@ apiVersion: kubeadm.k8s.io/v1beta1
 kind: ClusterConfiguration
 kubernetesVersion: {{.KubernetesVersion}}
 clusterName: "{{.ClusterName}}"
+` + networkingTemplate + `
+{{- if .ControlPlaneEndpoint }}
+# the API server is reached through the external load balancer
+controlPlaneEndpoint: "{{ .ControlPlaneEndpoint }}"
+{{- end }}
 # on docker for mac we have to expose the api server via port forward,
 # so we need to ensure the cert is valid for localhost so we can talk
 # to the cluster after rewriting the kubeconfig to point to localhost
 apiServer:
-  certSANs: [localhost]
+  certSANs: [localhost{{ if .APIServerAddress }}, "{{ .APIServerAddress }}"{{ end }}{{ range .APIServerCertSANs }}, "{{ . }}"{{ end }}]
+{{- if or .FeatureGatesFlag .RuntimeConfigFlag .EnableAdmissionPluginsFlag .DisableAdmissionPluginsFlag .IPv6 .Audit .Encryption .OIDCArgs }}
+  extraArgs:
+{{- if .IPv6 }}
+    bind-address: "::"
+{{- end }}
+{{- if .FeatureGatesFlag }}
+    feature-gates: "{{ .FeatureGatesFlag }}"
+{{- end }}
+{{- if .RuntimeConfigFlag }}
+    runtime-config: "{{ .RuntimeConfigFlag }}"
+{{- end }}
+{{- if .EnableAdmissionPluginsFlag }}
+    enable-admission-plugins: "{{ .EnableAdmissionPluginsFlag }}"
+{{- end }}
+{{- if .DisableAdmissionPluginsFlag }}
+    disable-admission-plugins: "{{ .DisableAdmissionPluginsFlag }}"
+{{- end }}
+{{- if .Audit }}
+    audit-policy-file: "` + AuditPolicyFile + `"
+    audit-log-path: "` + AuditLogDir + `/audit.log"
+    audit-log-maxage: "7"
+    audit-log-maxbackup: "3"
+    audit-log-maxsize: "100"
+{{- end }}
+{{- if .Encryption }}
+    {{ .EncryptionProviderConfigFlag }}: "` + EncryptionConfigFile + `"
+{{- end }}
+{{- range $flag, $value := .OIDCArgs }}
+    {{ $flag }}: "{{ $value }}"
+{{- end }}
+{{- end }}
+{{- if or .Audit .Encryption }}
+  extraVolumes:
+{{- end }}
+{{- if .Audit }}
+  - name: audit-policy
+    hostPath: "` + AuditPolicyFile + `"
+    mountPath: "` + AuditPolicyFile + `"
+    readOnly: true
+    pathType: File
+  - name: audit-log
+    hostPath: "` + AuditLogDir + `"
+    mountPath: "` + AuditLogDir + `"
+    pathType: DirectoryOrCreate
+{{- end }}
+{{- if .Encryption }}
+  - name: encryption-config
+    hostPath: "` + EncryptionConfigFile + `"
+    mountPath: "` + EncryptionConfigFile + `"
+    readOnly: true
+    pathType: File
+  - name: kms-plugin
+    hostPath: "` + KMSPluginDir + `"
+    mountPath: "` + KMSPluginDir + `"
+    pathType: DirectoryOrCreate
+{{- end }}
+{{- if or .FeatureGatesFlag .IPv6 .DualStack .ClusterSigningDuration }}
+controllerManager:
+  extraArgs:
+{{- if .FeatureGatesFlag }}
+    feature-gates: "{{ .FeatureGatesFlag }}"
+{{- end }}
+{{- if .ClusterSigningDuration }}
+    {{ .ClusterSigningDurationFlag }}: "{{ .ClusterSigningDuration }}"
+{{- end }}
+{{- /* the default node subnet size is only suitable for IPv4 */}}
+{{- if .IPv6 }}
+    node-cidr-mask-size: "80"
+{{- end }}
+{{- if .DualStack }}
+    node-cidr-mask-size-ipv4: "24"
+    node-cidr-mask-size-ipv6: "80"
+{{- end }}
+{{- end }}
+{{- if .FeatureGatesFlag }}
+scheduler:
+  extraArgs:
+    feature-gates: "{{ .FeatureGatesFlag }}"
+{{- end }}
+{{ if .ExternalEtcdEndpoints -}}
+# use the external etcd cluster, the certificates are provisioned by kind
+etcd:
+  external:
+    endpoints:{{ range .ExternalEtcdEndpoints }}
+    - {{ . }}{{ end }}
+    caFile: {{ .ExternalEtcdCAFile }}
+    certFile: {{ .ExternalEtcdCertFile }}
+    keyFile: {{ .ExternalEtcdKeyFile }}
+{{ end -}}
 ---
 apiVersion: kubeadm.k8s.io/v1beta1
 kind: InitConfiguration
@@ -150,20 +644,58 @@ bootstrapTokens:
 # from the host machine such port will be accessible via a random local port instead.
 localAPIEndpoint:
   bindPort: {{.APIBindPort}}
+{{- if .NodeAddress }}
+  advertiseAddress: "{{ .NodeAddress }}"
+{{- end }}
+` + nodeRegistrationTemplate + `
 ---
-# no-op entry that exists soley so it can be patched
 apiVersion: kubeadm.k8s.io/v1beta1
 kind: JoinConfiguration
+{{- if .JoinEndpoint }}
+# we use a well know token for TLS bootstrap, skipping CA verification
+discovery:
+  bootstrapToken:
+    token: "{{ .Token }}"
+    apiServerEndpoint: "{{ .JoinEndpoint }}"
+    unsafeSkipCAVerification: true
+{{- end }}
+` + nodeRegistrationTemplate + `
 ---
-# no-op entry that exists soley so it can be patched
+# this entry also exists so it can be patched
 apiVersion: kubelet.config.k8s.io/v1beta1
 kind: KubeletConfiguration
+` + featureGatesTemplate + `
 ---
-# no-op entry that exists soley so it can be patched
+# this entry also exists so it can be patched
 apiVersion: kubeproxy.config.k8s.io/v1alpha1
 kind: KubeProxyConfiguration
+{{- if .IPv6 }}
+bindAddress: "::"
+{{- end }}
+` + kubeProxyModeTemplate + kubeProxyConntrackTemplate + featureGatesTemplate + `
 `
 
+// SupportsJoinConfig returns true if kubeadm join can be configured with the
+// JoinConfiguration in the generated kubeadm config for the kubernetes version,
+// older versions are configured with flags instead
+func SupportsJoinConfig(kubernetesVersion string) (bool, error) {
+	ver, err := version.ParseGeneric(kubernetesVersion)
+	if err != nil {
+		return false, err
+	}
+	return ver.AtLeast(version.MustParseSemantic("v1.12.0")), nil
+}
+
+// SupportsSkipPhases returns true if kubeadm init can skip phases, e.g. the
+// kube-proxy addon, for the kubernetes version
+func SupportsSkipPhases(kubernetesVersion string) (bool, error) {
+	ver, err := version.ParseGeneric(kubernetesVersion)
+	if err != nil {
+		return false, err
+	}
+	return ver.AtLeast(version.MustParseSemantic("v1.13.0")), nil
+}
+
 // Config returns a kubeadm config generated from config data, in particular
 // the kubernetes version
 func Config(data ConfigData) (config string, err error) {
@@ -172,6 +704,17 @@ func Config(data ConfigData) (config string, err error) {
 		return "", err
 	}
 
+	// IPv6 is supported only by the v1beta1 config, and dual-stack and the
+	// kube-proxy nftables mode only by later versions
+	switch {
+	case data.IPFamily == "ipv6" && ver.LessThan(version.MustParseSemantic("v1.13.0")):
+		return "", errors.Errorf("IPv6 clusters require Kubernetes v1.13.0 or later, got %s", data.KubernetesVersion)
+	case data.IPFamily == "dual" && ver.LessThan(version.MustParseSemantic("v1.17.0")):
+		return "", errors.Errorf("dual-stack clusters require Kubernetes v1.17.0 or later, got %s", data.KubernetesVersion)
+	case data.KubeProxyMode == "nftables" && ver.LessThan(version.MustParseSemantic("v1.29.0")):
+		return "", errors.Errorf("the kube-proxy nftables mode requires Kubernetes v1.29.0 or later, got %s", data.KubernetesVersion)
+	}
+
 	// assume the latest API version, then fallback if the k8s version is too low
 	templateSource := ConfigTemplateBetaV1
 	if ver.LessThan(version.MustParseSemantic("v1.12.0")) {
@@ -22,3 +22,58 @@ const APIServerPort = 6443
 
 // Token defines a dummy, well known token for automating TLS bootstrap process
 const Token = "abcdef.0123456789abcdef"
+
+// CACertFile and CAKeyFile are the paths on the control plane nodes of the
+// cluster CA certificate and key, kubeadm uses an existing CA rather than
+// generating one
+const (
+	CACertFile = "/etc/kubernetes/pki/ca.crt"
+	CAKeyFile  = "/etc/kubernetes/pki/ca.key"
+)
+
+// OIDCCAFile is the path on the control plane nodes of the CA certificate of
+// the OpenID issuer, this is in the certificates directory mounted by kubeadm
+// in the API server
+const OIDCCAFile = "/etc/kubernetes/pki/oidc-ca.crt"
+
+// ExternalEtcdCAFile is the path on the control plane nodes of the CA
+// certificate of the external etcd cluster, if any
+const ExternalEtcdCAFile = "/etc/kubernetes/pki/etcd/ca.crt"
+
+// ExternalEtcdCertFile is the path on the control plane nodes of the client
+// certificate used by the API server to connect to the external etcd cluster
+const ExternalEtcdCertFile = "/etc/kubernetes/pki/apiserver-etcd-client.crt"
+
+// ExternalEtcdKeyFile is the path on the control plane nodes of the key of
+// ExternalEtcdCertFile
+const ExternalEtcdKeyFile = "/etc/kubernetes/pki/apiserver-etcd-client.key"
+
+// AuditPolicyFile is the path on the control plane nodes of the API server
+// audit policy, if audit logging is enabled
+const AuditPolicyFile = "/etc/kubernetes/audit/policy.yaml"
+
+// AuditLogDir is the directory on the control plane nodes of the API server
+// audit log, under /var/log so that it is collected with the node logs
+const AuditLogDir = "/var/log/kubernetes/audit"
+
+// EncryptionConfigFile is the path on the control plane nodes of the API
+// server encryption provider config, if encryption at rest is enabled
+const EncryptionConfigFile = "/etc/kubernetes/encryption/config.yaml"
+
+// KMSPluginDir is the directory on the control plane nodes of the socket of
+// the KMS plugin, if any, this is mounted into the API server along with
+// EncryptionConfigFile
+const KMSPluginDir = "/var/run/kmsplugin"
+
+// ServiceSubnet is the kubeadm default service subnet
+const ServiceSubnet = "10.96.0.0/12"
+
+// IPv6PodSubnet and IPv6ServiceSubnet are the default pod and service
+// subnets of IPv6 clusters, as the kubeadm defaults are IPv4 only
+const (
+	IPv6PodSubnet     = "fd00:10:244::/64"
+	IPv6ServiceSubnet = "fd00:10:96::/112"
+)
+
+// DualStackPodSubnet is the default pod subnet of dual-stack clusters
+const DualStackPodSubnet = "10.244.0.0/16," + IPv6PodSubnet
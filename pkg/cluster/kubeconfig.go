@@ -0,0 +1,361 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+)
+
+// kubeConfigSections are the named entries of a kubeconfig
+var kubeConfigSections = []string{"clusters", "users", "contexts"}
+
+// kubeConfigLockTimeout is how long MergeKubeConfig and UnmergeKubeConfig
+// wait for another kind process to release the kubeconfig lock
+const kubeConfigLockTimeout = 30 * time.Second
+
+// KubeConfig returns the kubeconfig of the cluster as written by Create to
+// KubeConfigPath, reaching the API server published on the host.
+// If internal is set, the kubeconfig reaches the API server inside the docker
+// network instead, for use from other containers on the network
+func (c *Context) KubeConfig(internal bool) ([]byte, error) {
+	if !internal {
+		kubeConfig, err := ioutil.ReadFile(c.KubeConfigPath())
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no kubeconfig found for cluster %q at %s", c.Name(), c.KubeConfigPath())
+		}
+		return kubeConfig, err
+	}
+
+	n, err := c.ListNodes()
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %v", err)
+	}
+	if len(n) == 0 {
+		return nil, fmt.Errorf("no nodes found for cluster %q", c.Name())
+	}
+	cfg, derived, nodeList, err := c.deriveInfoFromNodes(n)
+	if err != nil {
+		return nil, err
+	}
+	bootstrap := derived.BootStrapControlPlane()
+	if bootstrap == nil {
+		return nil, fmt.Errorf("no control-plane node found for cluster %q", c.Name())
+	}
+	controlPlane, ok := nodeList[bootstrap.Name]
+	if !ok {
+		return nil, fmt.Errorf("unable to get the handle for operating on node: %s", bootstrap.Name)
+	}
+	// the API server is reached through the external load balancer, if any
+	apiServer, err := apiServerNode(derived, nodeList)
+	if err != nil {
+		return nil, err
+	}
+	ip, err := nodeIP(apiServer, cfg.Networking.IPFamily)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get IP for node")
+	}
+	return controlPlane.KubeConfig("https://" + net.JoinHostPort(ip, strconv.Itoa(kubeadm.APIServerPort)))
+}
+
+// writeInternalKubeConfig writes the internal kubeconfig of the cluster, see
+// KubeConfig, to InternalKubeConfigPath
+func (c *Context) writeInternalKubeConfig() error {
+	kubeConfig, err := c.KubeConfig(true)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(c.InternalKubeConfigPath(), kubeConfig, 0600); err != nil {
+		return errors.Wrap(err, "failed to write the internal kubeconfig")
+	}
+	return nil
+}
+
+// DefaultMergeKubeConfigPath returns the path of the kubeconfig the clusters
+// are merged into, the first path in $KUBECONFIG, or ~/.kube/config like
+// kubectl if it is not set
+func DefaultMergeKubeConfigPath() string {
+	if paths := filepath.SplitList(os.Getenv("KUBECONFIG")); len(paths) > 0 && paths[0] != "" {
+		return paths[0]
+	}
+	return filepath.Join(os.Getenv("HOME"), ".kube", "config")
+}
+
+// MergeKubeConfig merges the clusters, users and contexts of the cluster
+// kubeconfig (see KubeConfig) into the kubeconfig at path, replacing any
+// entries of a previous cluster with the same name, and switches its current
+// context to the cluster. The main cluster, user and context are named after
+// ClusterName, so that they do not collide with those of other clusters.
+// The kubeconfig is locked while it is updated, as kubectl does, so that
+// clusters created in parallel can be merged into the same file
+func (c *Context) MergeKubeConfig(path string) error {
+	kubeConfig, err := c.KubeConfig(false)
+	if err != nil {
+		return err
+	}
+	entries, err := renameKubeConfig(kubeConfig, c.ClusterName())
+	if err != nil {
+		return err
+	}
+	return updateKubeConfig(path, func(cfg map[string]interface{}) bool {
+		mergeKubeConfig(cfg, entries)
+		return true
+	})
+}
+
+// UnmergeKubeConfig removes the clusters, users and contexts merged by
+// MergeKubeConfig from the kubeconfig at path, if any, and unsets its
+// current context if it is the cluster context
+func (c *Context) UnmergeKubeConfig(path string) error {
+	// the entries are named after the cluster kubeconfig, or only after
+	// ClusterName if it was deleted
+	entries := map[string]interface{}{}
+	for _, section := range kubeConfigSections {
+		entries[section] = []interface{}{map[string]interface{}{"name": c.ClusterName()}}
+	}
+	if kubeConfig, err := c.KubeConfig(false); err == nil {
+		if entries, err = renameKubeConfig(kubeConfig, c.ClusterName()); err != nil {
+			return err
+		}
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	return updateKubeConfig(path, func(cfg map[string]interface{}) bool {
+		return unmergeKubeConfig(cfg, entries)
+	})
+}
+
+// updateMergedKubeConfig merges the cluster kubeconfig again into the
+// kubeconfig at DefaultMergeKubeConfigPath if the cluster was merged into it,
+// e.g. after Recreate published the API server on a new host port
+func (c *Context) updateMergedKubeConfig() {
+	path := DefaultMergeKubeConfigPath()
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+	cfg := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &cfg); err != nil || kubeConfigEntry(cfg, "contexts", c.ClusterName()) < 0 {
+		return
+	}
+	if err := c.MergeKubeConfig(path); err != nil {
+		c.Logger().Warnf("Failed to update the cluster in %s: %v", path, err)
+	}
+}
+
+// renameKubeConfig parses the cluster kubeConfig, renaming its current
+// context and the cluster and user of the context to name
+func renameKubeConfig(kubeConfig []byte, name string) (map[string]interface{}, error) {
+	cfg := map[string]interface{}{}
+	if err := yaml.Unmarshal(kubeConfig, &cfg); err != nil {
+		return nil, errors.Wrap(err, "failed to parse kubeconfig")
+	}
+	current, _ := cfg["current-context"].(string)
+	i := kubeConfigEntry(cfg, "contexts", current)
+	if i < 0 {
+		return nil, fmt.Errorf("kubeconfig current context %q not found", current)
+	}
+	contexts, _ := cfg["contexts"].([]interface{})
+	contextConfig, _ := contexts[i].(map[string]interface{})["context"].(map[string]interface{})
+	if contextConfig == nil {
+		return nil, errors.New("failed to parse kubeconfig context")
+	}
+
+	renames := map[string]map[string]string{
+		"contexts": {current: name},
+		"clusters": {},
+		"users":    {},
+	}
+	if cluster, ok := contextConfig["cluster"].(string); ok {
+		renames["clusters"][cluster] = name
+	}
+	if user, ok := contextConfig["user"].(string); ok {
+		renames["users"][user] = name
+	}
+	for _, section := range kubeConfigSections {
+		entries, _ := cfg[section].([]interface{})
+		for _, entry := range entries {
+			entry, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if newName, ok := renames[section][fmt.Sprint(entry["name"])]; ok {
+				entry["name"] = newName
+			}
+			// the contexts refer to the clusters and users by name
+			if context, ok := entry["context"].(map[string]interface{}); ok && section == "contexts" {
+				for key, refSection := range map[string]string{"cluster": "clusters", "user": "users"} {
+					if newName, ok := renames[refSection][fmt.Sprint(context[key])]; ok {
+						context[key] = newName
+					}
+				}
+			}
+		}
+	}
+	cfg["current-context"] = name
+	return cfg, nil
+}
+
+// kubeConfigEntry returns the index of the entry named name in the section
+// of cfg, or -1 if there is none
+func kubeConfigEntry(cfg map[string]interface{}, section, name string) int {
+	entries, _ := cfg[section].([]interface{})
+	for i, entry := range entries {
+		if entry, ok := entry.(map[string]interface{}); ok && entry["name"] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeKubeConfig merges the entries of src into cfg, replacing the entries
+// with the same names, and sets the current context of cfg to that of src
+func mergeKubeConfig(cfg, src map[string]interface{}) {
+	for _, section := range kubeConfigSections {
+		entries, _ := cfg[section].([]interface{})
+		srcEntries, _ := src[section].([]interface{})
+		for _, entry := range srcEntries {
+			name, _ := entry.(map[string]interface{})["name"].(string)
+			if i := kubeConfigEntry(cfg, section, name); i >= 0 {
+				entries[i] = entry
+			} else {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) > 0 {
+			cfg[section] = entries
+		}
+	}
+	if cfg["apiVersion"] == nil {
+		cfg["apiVersion"] = "v1"
+	}
+	if cfg["kind"] == nil {
+		cfg["kind"] = "Config"
+	}
+	cfg["current-context"] = src["current-context"]
+}
+
+// unmergeKubeConfig removes the entries named like those of src from cfg,
+// and unsets the current context of cfg if it is removed, returning true if
+// cfg changed
+func unmergeKubeConfig(cfg, src map[string]interface{}) bool {
+	changed := false
+	for _, section := range kubeConfigSections {
+		srcEntries, _ := src[section].([]interface{})
+		for _, entry := range srcEntries {
+			name, _ := entry.(map[string]interface{})["name"].(string)
+			i := kubeConfigEntry(cfg, section, name)
+			if i < 0 {
+				continue
+			}
+			entries := cfg[section].([]interface{})
+			cfg[section] = append(entries[:i], entries[i+1:]...)
+			changed = true
+			if section == "contexts" && cfg["current-context"] == name {
+				cfg["current-context"] = ""
+			}
+		}
+	}
+	return changed
+}
+
+// updateKubeConfig updates the kubeconfig at path with update, which returns
+// true if it changed the kubeconfig, an empty kubeconfig is updated if the
+// file does not exist. The kubeconfig is locked while it is updated, and is
+// replaced atomically so that it is never partially written
+func updateKubeConfig(path string, update func(cfg map[string]interface{}) bool) error {
+	// 0755 is taken from client-go's config handling logic, see WriteKubeConfig
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "failed to create kubeconfig directory")
+	}
+	unlock, err := lockKubeConfig(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cfg := map[string]interface{}{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to read kubeconfig")
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return errors.Wrapf(err, "failed to parse kubeconfig %s", path)
+	}
+	if cfg == nil {
+		cfg = map[string]interface{}{}
+	}
+	if !update(cfg) {
+		return nil
+	}
+	data, err = yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	// write a temporary file in the same directory and rename it, which
+	// replaces the kubeconfig atomically
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".kind-")
+	if err != nil {
+		return errors.Wrap(err, "failed to write kubeconfig")
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "failed to write kubeconfig")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write kubeconfig")
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.Wrap(err, "failed to write kubeconfig")
+	}
+	return nil
+}
+
+// lockKubeConfig locks the kubeconfig at path by creating path.lock, the lock
+// file used by kubectl, waiting up to kubeConfigLockTimeout for a concurrent
+// lock to be released, it returns a function that releases the lock
+func lockKubeConfig(path string) (unlock func(), err error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(kubeConfigLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL, 0)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, errors.Wrap(err, "failed to lock kubeconfig")
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for the kubeconfig lock %s, remove it if no other process is using the kubeconfig", lockPath)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+const testKindKubeConfig = `apiVersion: v1
+clusters:
+- cluster:
+    server: https://localhost:32768
+  name: kind
+- cluster:
+    server: https://kind.example.com:32768
+  name: kind-kind.example.com
+contexts:
+- context:
+    cluster: kind
+    user: kubernetes-admin
+  name: kubernetes-admin@kind
+- context:
+    cluster: kind-kind.example.com
+    user: kubernetes-admin
+  name: kubernetes-admin@kind-kind.example.com
+current-context: kubernetes-admin@kind
+kind: Config
+users:
+- name: kubernetes-admin
+  user:
+    client-certificate-data: Y2VydA==
+`
+
+// kubeConfigNames returns the names of the entries of each section of cfg
+func kubeConfigNames(cfg map[string]interface{}) map[string][]string {
+	names := map[string][]string{}
+	for _, section := range kubeConfigSections {
+		entries, _ := cfg[section].([]interface{})
+		for _, entry := range entries {
+			names[section] = append(names[section], fmt.Sprint(entry.(map[string]interface{})["name"]))
+		}
+	}
+	return names
+}
+
+func TestRenameKubeConfig(t *testing.T) {
+	cfg, err := renameKubeConfig([]byte(testKindKubeConfig), "kind-foo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := kubeConfigNames(cfg)
+	expected := map[string][]string{
+		"clusters": {"kind-foo", "kind-kind.example.com"},
+		"users":    {"kind-foo"},
+		"contexts": {"kind-foo", "kubernetes-admin@kind-kind.example.com"},
+	}
+	for section, expectedNames := range expected {
+		if fmt.Sprint(names[section]) != fmt.Sprint(expectedNames) {
+			t.Errorf("expected %s %v but got %v", section, expectedNames, names[section])
+		}
+	}
+	if cfg["current-context"] != "kind-foo" {
+		t.Errorf("expected the current context to be renamed, got %v", cfg["current-context"])
+	}
+	contexts := cfg["contexts"].([]interface{})
+	hostContext := contexts[1].(map[string]interface{})["context"].(map[string]interface{})
+	if hostContext["cluster"] != "kind-kind.example.com" || hostContext["user"] != "kind-foo" {
+		t.Errorf("expected the context references to be renamed, got %v", hostContext)
+	}
+}
+
+func TestMergeAndUnmergeKubeConfig(t *testing.T) {
+	cfg := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: other
+contexts:
+- name: other
+users:
+- name: other
+current-context: other
+`), &cfg); err != nil {
+		t.Fatalf("failed to parse kubeconfig: %v", err)
+	}
+	src, err := renameKubeConfig([]byte(testKindKubeConfig), "kind-foo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// merging twice replaces the entries
+	mergeKubeConfig(cfg, src)
+	mergeKubeConfig(cfg, src)
+	names := kubeConfigNames(cfg)
+	if len(names["clusters"]) != 3 || len(names["users"]) != 2 || len(names["contexts"]) != 3 {
+		t.Errorf("unexpected merged entries %v", names)
+	}
+	if cfg["current-context"] != "kind-foo" {
+		t.Errorf("expected to switch to the cluster context, got %v", cfg["current-context"])
+	}
+
+	if !unmergeKubeConfig(cfg, src) {
+		t.Error("expected the kubeconfig to change")
+	}
+	names = kubeConfigNames(cfg)
+	for _, section := range kubeConfigSections {
+		if fmt.Sprint(names[section]) != "[other]" {
+			t.Errorf("expected only the other %s to remain, got %v", section, names[section])
+		}
+	}
+	if cfg["current-context"] != "" {
+		t.Errorf("expected the current context to be unset, got %v", cfg["current-context"])
+	}
+	if unmergeKubeConfig(cfg, src) {
+		t.Error("expected the kubeconfig not to change")
+	}
+}
+
+func TestUpdateKubeConfigConcurrently(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kind-kubeconfig")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ".kube", "config")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			src, err := renameKubeConfig([]byte(testKindKubeConfig), fmt.Sprintf("kind-%d", i))
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if err := updateKubeConfig(path, func(cfg map[string]interface{}) bool {
+				mergeKubeConfig(cfg, src)
+				return true
+			}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read kubeconfig: %v", err)
+	}
+	cfg := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("failed to parse kubeconfig: %v", err)
+	}
+	// the host cluster and context are shared by the test clusters
+	if names := kubeConfigNames(cfg); len(names["users"]) != 10 || len(names["contexts"]) != 11 {
+		t.Errorf("expected the entries of all the clusters, got %v", names)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Error("expected the lock to be released")
+	}
+}
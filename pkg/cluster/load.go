@@ -0,0 +1,332 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/docker"
+	"sigs.k8s.io/kind/pkg/fs"
+	"sigs.k8s.io/kind/pkg/util"
+)
+
+// LoadDockerImages saves the images from the local docker daemon and loads
+// them into docker on the nodes of the cluster, or only on the nodes with the
+// given container names if any, so that locally built images can be used
+// without pushing them to a registry.
+// The images are saved once and streamed to all the nodes concurrently, only
+// the images missing from at least one node are saved, and only into the
+// nodes missing any of them.
+// The nodes must be running
+func (c *Context) LoadDockerImages(images []string, nodeNames ...string) error {
+	selected, err := c.imageNodes(nodeNames)
+	if err != nil {
+		return err
+	}
+
+	wanted := make([]docker.ArchiveImage, 0, len(images))
+	for _, image := range images {
+		id, err := docker.ImageID(image)
+		if err != nil {
+			return errors.Wrapf(err, "image %s is not present locally", image)
+		}
+		wanted = append(wanted, docker.ArchiveImage{ID: id, RepoTags: []string{image}})
+	}
+	missing, targets := missingImages(wanted, selected)
+	if len(targets) == 0 {
+		c.Logger().Infof("The images are already present on all the nodes")
+		return nil
+	}
+	names := []string{}
+	for _, image := range missing {
+		names = append(names, image.RepoTags...)
+	}
+	return loadImages(func(w io.Writer) error {
+		if err := docker.SaveImagesTo(w, names...); err != nil {
+			return errors.Wrap(err, "failed to save images")
+		}
+		return nil
+	}, missing, targets)
+}
+
+// LoadImageArchive loads the images in the image archive at path into
+// docker on the nodes of the cluster, or only on the nodes with the given
+// container names if any. The archive is either a docker image archive, as
+// written by `docker save`, or an OCI image layout, a directory or a tarball,
+// whose images are named by the io.containerd.image.name annotation.
+// The nodes must be running
+func (c *Context) LoadImageArchive(path string, nodeNames ...string) error {
+	selected, err := c.imageNodes(nodeNames)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	oci := info.IsDir()
+	if !oci {
+		if oci, err = docker.IsOCIArchive(path); err != nil {
+			return errors.Wrapf(err, "failed to read image archive %s", path)
+		}
+	}
+	if !oci {
+		return c.loadImageArchive(path, selected)
+	}
+
+	// docker on the nodes only loads docker image archives
+	dir, err := fs.TempDir("", "kind-load")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	archive := filepath.Join(dir, "images.tar")
+	if err := docker.WriteArchiveFromOCILayout(path, archive, runtime.GOARCH); err != nil {
+		return errors.Wrapf(err, "failed to convert OCI layout %s", path)
+	}
+	return c.loadImageArchive(archive, selected)
+}
+
+// imageNodes returns the nodes of the cluster running pods, the control plane
+// and Linux worker nodes, or only the ones with the given container names if
+// any. Images can not be loaded into Windows nodes
+func (c *Context) imageNodes(nodeNames []string) ([]nodes.Node, error) {
+	n, err := c.ListNodes()
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %v", err)
+	}
+	byName := map[string]nodes.Node{}
+	for _, node := range n {
+		role, err := node.Role()
+		if err != nil {
+			return nil, err
+		}
+		if (config.NodeRole(role) == config.ControlPlaneRole || config.NodeRole(role) == config.WorkerRole) && !node.IsWindows() {
+			byName[node.String()] = node
+		}
+	}
+	if len(byName) == 0 {
+		return nil, fmt.Errorf("no nodes found for cluster %q", c.Name())
+	}
+
+	if len(nodeNames) == 0 {
+		selected := []nodes.Node{}
+		for _, node := range n {
+			if _, ok := byName[node.String()]; ok {
+				selected = append(selected, node)
+			}
+		}
+		return selected, nil
+	}
+	selected := []nodes.Node{}
+	for _, name := range nodeNames {
+		node, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("no control-plane or Linux worker node named %q found in cluster %q", name, c.Name())
+		}
+		selected = append(selected, node)
+	}
+	return selected, nil
+}
+
+// loadImageArchive loads the images in the docker image archive at path into
+// docker on the nodes missing any of them, see loadImages
+func (c *Context) loadImageArchive(path string, n []nodes.Node) error {
+	images, err := docker.GetArchiveImages(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read image archive")
+	}
+	_, targets := missingImages(images, n)
+	if len(targets) == 0 {
+		c.Logger().Infof("The images are already present on all the nodes")
+		return nil
+	}
+	return loadImages(func(w io.Writer) error {
+		f, err := os.Open(path)
+		if err != nil {
+			return errors.Wrap(err, "failed to open image archive")
+		}
+		defer f.Close()
+		_, err = io.Copy(w, f)
+		return err
+	}, images, targets)
+}
+
+// missingImages returns the images missing from at least one of the nodes,
+// missing or with another ID under any of their names, and the nodes missing
+// any of the images
+func missingImages(images []docker.ArchiveImage, n []nodes.Node) (missing []docker.ArchiveImage, targets []nodes.Node) {
+	// the nodes are inspected concurrently
+	present := make([][]bool, len(n))
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			present[i] = make([]bool, len(images))
+			for j, image := range images {
+				present[i][j] = nodeHasImage(&n[i], image)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for j, image := range images {
+		for i := range n {
+			if !present[i][j] {
+				missing = append(missing, image)
+				break
+			}
+		}
+	}
+	for i := range n {
+		for j := range images {
+			if !present[i][j] {
+				targets = append(targets, n[i])
+				break
+			}
+		}
+	}
+	return missing, targets
+}
+
+// nodeHasImage returns true if the image is present on the node with the
+// same ID under all of its names
+func nodeHasImage(node *nodes.Node, image docker.ArchiveImage) bool {
+	for _, name := range append([]string{image.ID}, image.RepoTags...) {
+		if id, err := node.ImageID(name); err != nil || id != image.ID {
+			return false
+		}
+	}
+	return true
+}
+
+// loadImages streams the image archive written by save into docker on each
+// of the nodes concurrently, the archive is written once and never stored,
+// and verifies that the nodes have the images afterwards. The nodes read the
+// archive in lockstep, a node failing to load it is dropped from the stream
+func loadImages(save func(io.Writer) error, images []docker.ArchiveImage, n []nodes.Node) error {
+	writers := make([]*io.PipeWriter, len(n))
+	errs := make([]error, len(n))
+	var wg sync.WaitGroup
+	for i := range n {
+		r, w := io.Pipe()
+		writers[i] = w
+		wg.Add(1)
+		go func(i int, r *io.PipeReader) {
+			defer wg.Done()
+			errs[i] = loadImagesIntoNode(r, images, &n[i])
+			// unblocks the stream if the node stopped reading early
+			r.Close()
+		}(i, r)
+	}
+	saveErr := save(newFanOutWriter(writers))
+	for _, w := range writers {
+		w.CloseWithError(saveErr)
+	}
+	wg.Wait()
+
+	if saveErr != nil {
+		return saveErr
+	}
+	failed := []error{}
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(failed) == 1 {
+		return failed[0]
+	} else if len(failed) > 1 {
+		return util.Flatten(failed)
+	}
+	return nil
+}
+
+// loadImageArchiveIntoNode loads the images in the docker image archive at
+// path into docker on the node, unless the node already has them
+func loadImageArchiveIntoNode(path string, images []docker.ArchiveImage, node *nodes.Node) error {
+	if _, targets := missingImages(images, []nodes.Node{*node}); len(targets) == 0 {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open image archive")
+	}
+	defer f.Close()
+	return loadImagesIntoNode(f, images, node)
+}
+
+// loadImagesIntoNode implements loadImages for a single node
+func loadImagesIntoNode(r io.Reader, images []docker.ArchiveImage, node *nodes.Node) error {
+	if err := node.LoadImageArchive(r); err != nil {
+		return errors.Wrapf(err, "failed to load images into node %s", node.String())
+	}
+
+	// the image IDs are the digests of the image configs, and the layers are
+	// verified by docker against the configs while loading
+	for _, image := range images {
+		for _, name := range append([]string{image.ID}, image.RepoTags...) {
+			id, err := node.ImageID(name)
+			if err != nil {
+				return errors.Wrapf(err, "failed to verify images on node %s", node.String())
+			}
+			if id != image.ID {
+				return fmt.Errorf("image %s on node %s has ID %s, expected %s", name, node.String(), id, image.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// fanOutWriter writes to all of its writers, the writers failing are dropped
+// and writing never fails, so that the other writers keep receiving the data
+type fanOutWriter struct {
+	writers []io.Writer
+}
+
+var _ io.Writer = &fanOutWriter{}
+
+// newFanOutWriter returns a fanOutWriter writing to the pipes
+func newFanOutWriter(pipes []*io.PipeWriter) *fanOutWriter {
+	writers := make([]io.Writer, 0, len(pipes))
+	for _, w := range pipes {
+		writers = append(writers, w)
+	}
+	return &fanOutWriter{writers: writers}
+}
+
+func (f *fanOutWriter) Write(p []byte) (int, error) {
+	live := f.writers[:0]
+	for _, w := range f.writers {
+		if _, err := w.Write(p); err == nil {
+			live = append(live, w)
+		}
+	}
+	f.writers = live
+	return len(p), nil
+}
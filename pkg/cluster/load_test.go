@@ -0,0 +1,51 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func TestFanOutWriter(t *testing.T) {
+	r1, w1 := io.Pipe()
+	r2, w2 := io.Pipe()
+	// the first reader stops early, the second reads everything
+	r1.Close()
+	read := make(chan []byte)
+	go func() {
+		b, _ := ioutil.ReadAll(r2)
+		read <- b
+	}()
+
+	f := newFanOutWriter([]*io.PipeWriter{w1, w2})
+	for _, chunk := range []string{"foo", "bar"} {
+		n, err := f.Write([]byte(chunk))
+		if err != nil || n != len(chunk) {
+			t.Fatalf("expected %d bytes written without error, got %d, %v", len(chunk), n, err)
+		}
+	}
+	if len(f.writers) != 1 {
+		t.Errorf("expected the failed writer to be dropped, got %d writers", len(f.writers))
+	}
+	w2.Close()
+	if b := <-read; !bytes.Equal(b, []byte("foobar")) {
+		t.Errorf("expected %q but got %q", "foobar", b)
+	}
+}
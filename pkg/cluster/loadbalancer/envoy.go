@@ -0,0 +1,79 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/docker"
+)
+
+// envoy implements LoadBalancer using envoy with a static config
+type envoy struct{}
+
+const envoyConfigTemplate = `# config generated by kind
+admin:
+  access_log_path: /dev/null
+  address:
+    socket_address: { address: 127.0.0.1, port_value: 9901 }
+static_resources:
+  listeners:
+  - name: control-plane
+    address:
+      {{- if .IPv6 }}
+      socket_address: { address: "::", port_value: {{ .ControlPlanePort }}, ipv4_compat: true }
+      {{- else }}
+      socket_address: { address: 0.0.0.0, port_value: {{ .ControlPlanePort }} }
+      {{- end }}
+    filter_chains:
+    - filters:
+      - name: envoy.tcp_proxy
+        config:
+          stat_prefix: control-plane
+          cluster: kube-apiservers
+  clusters:
+  - name: kube-apiservers
+    connect_timeout: 5s
+    type: STATIC
+    lb_policy: ROUND_ROBIN
+    hosts:
+    {{- range .BackendServers }}
+    - socket_address: { address: "{{ .IP }}", port_value: {{ .Port }} }
+    {{- end }}
+`
+
+func (e *envoy) Image() string {
+	return "envoyproxy/envoy-alpine:v1.8.0"
+}
+
+func (e *envoy) ConfigPath() string {
+	return "/etc/envoy/envoy.yaml"
+}
+
+func (e *envoy) Config(data *ConfigData) (string, error) {
+	return executeTemplate("envoy", envoyConfigTemplate, data)
+}
+
+// Reload restarts the load balancer node container, as the static config
+// is only read at startup
+func (e *envoy) Reload(node *nodes.Node) error {
+	return docker.Restart(node.String())
+}
+
+func (e *envoy) HealthCheck(node *nodes.Node) error {
+	return checkListening(node, kubeadm.APIServerPort)
+}
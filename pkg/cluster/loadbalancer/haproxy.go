@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/docker"
+)
+
+// haproxy implements LoadBalancer using haproxy
+type haproxy struct{}
+
+const haproxyConfigTemplate = `# config generated by kind
+defaults
+  mode tcp
+  timeout connect 5s
+  timeout client 30s
+  timeout server 30s
+
+frontend control-plane
+  bind *:{{ .ControlPlanePort }}
+  {{- if .IPv6 }}
+  bind :::{{ .ControlPlanePort }} v6only
+  {{- end }}
+  default_backend kube-apiservers
+
+backend kube-apiservers
+  option httpchk GET /healthz
+  {{- range .BackendServers }}
+  server {{ .Name }} {{ .Address }} check check-ssl verify none
+  {{- end }}
+`
+
+func (h *haproxy) Image() string {
+	return "haproxy:1.8-alpine"
+}
+
+func (h *haproxy) ConfigPath() string {
+	return "/usr/local/etc/haproxy/haproxy.cfg"
+}
+
+func (h *haproxy) Config(data *ConfigData) (string, error) {
+	return executeTemplate("haproxy", haproxyConfigTemplate, data)
+}
+
+// Reload signals the haproxy master process, which runs as pid 1
+// in the haproxy image, to reload the config
+func (h *haproxy) Reload(node *nodes.Node) error {
+	return docker.Kill("USR2", node.String())
+}
+
+func (h *haproxy) HealthCheck(node *nodes.Node) error {
+	return checkListening(node, kubeadm.APIServerPort)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/docker"
+)
+
+// LoadBalancer is an implementation of the external load balancer
+type LoadBalancer interface {
+	// Image returns the image run by the load balancer node container
+	Image() string
+	// ConfigPath returns the path of the load balancer config file on the node
+	ConfigPath() string
+	// Config returns the load balancer config file contents for data
+	Config(data *ConfigData) (string, error)
+	// Reload makes the running load balancer pick up the current config file
+	Reload(node *nodes.Node) error
+	// HealthCheck returns an error if the load balancer is not serving
+	HealthCheck(node *nodes.Node) error
+}
+
+// ConfigData is supplied to the load balancer config templates
+type ConfigData struct {
+	// ControlPlanePort is the port the load balancer listens on
+	ControlPlanePort int
+	// IPv6 is true if the load balancer should listen on IPv6 as well,
+	// this is the case for IPv6 clusters
+	IPv6 bool
+	// BackendServers are the API servers traffic is balanced to
+	BackendServers []Backend
+}
+
+// Backend is an API server the load balancer balances traffic to
+type Backend struct {
+	// Name is the name of the node hosting the API server
+	Name string
+	// IP is the address of the node hosting the API server
+	IP string
+	// Port is the port the API server listens on
+	Port int
+}
+
+// Address returns the host:port address of the backend
+func (b Backend) Address() string {
+	return net.JoinHostPort(b.IP, strconv.Itoa(b.Port))
+}
+
+// Get returns the load balancer implementation for the load balancer type,
+// the default implementation is returned for the empty type
+func Get(t config.LoadBalancerType) (LoadBalancer, error) {
+	switch t {
+	case "", config.HAProxyLoadBalancer:
+		return &haproxy{}, nil
+	case config.NginxLoadBalancer:
+		return &nginx{}, nil
+	case config.EnvoyLoadBalancer:
+		return &envoy{}, nil
+	}
+	return nil, fmt.Errorf("unknown load balancer type: %q", t)
+}
+
+// executeTemplate runs data through the config template source
+func executeTemplate(name, source string, data *ConfigData) (string, error) {
+	t, err := template.New(name).Parse(source)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse config template")
+	}
+	var buff bytes.Buffer
+	if err := t.Execute(&buff, data); err != nil {
+		return "", errors.Wrap(err, "error executing config template")
+	}
+	return buff.String(), nil
+}
+
+// checkListening returns an error if nothing accepts connections on the
+// host port the load balancer port is published to
+func checkListening(node *nodes.Node, port int) error {
+	hostPort, err := node.Ports(port)
+	if err != nil {
+		return errors.Wrap(err, "failed to get load balancer port")
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(docker.PublishedHost(), strconv.Itoa(hostPort)), 5*time.Second)
+	if err != nil {
+		return errors.Wrap(err, "load balancer is not serving")
+	}
+	return conn.Close()
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestConfig(t *testing.T) {
+	data := &ConfigData{
+		ControlPlanePort: 6443,
+		BackendServers: []Backend{
+			{Name: "kind-1-control-plane1", IP: "172.17.0.3", Port: 6443},
+			{Name: "kind-1-control-plane2", IP: "172.17.0.4", Port: 6443},
+		},
+	}
+	for _, lbType := range []config.LoadBalancerType{"", config.HAProxyLoadBalancer, config.NginxLoadBalancer, config.EnvoyLoadBalancer} {
+		t.Run(string(lbType), func(t *testing.T) {
+			lb, err := Get(lbType)
+			if err != nil {
+				t.Fatalf("unexpected error getting load balancer: %v", err)
+			}
+			contents, err := lb.Config(data)
+			if err != nil {
+				t.Fatalf("unexpected error generating config: %v", err)
+			}
+			// all the backends should be in the config
+			for _, backend := range data.BackendServers {
+				if !strings.Contains(contents, backend.IP) {
+					t.Errorf("expected backend %s in config:\n%s", backend.IP, contents)
+				}
+			}
+		})
+	}
+
+	if _, err := Get("unknown"); err == nil {
+		t.Errorf("expected error for unknown load balancer type")
+	}
+}
+
+func TestConfigIPv6(t *testing.T) {
+	data := &ConfigData{
+		ControlPlanePort: 6443,
+		IPv6:             true,
+		BackendServers: []Backend{
+			{Name: "kind-1-control-plane1", IP: "fc00:f853:ccd:e793::3", Port: 6443},
+		},
+	}
+	// the backend addresses must be bracketed or quoted
+	expected := map[config.LoadBalancerType]string{
+		config.HAProxyLoadBalancer: "[fc00:f853:ccd:e793::3]:6443",
+		config.NginxLoadBalancer:   "[fc00:f853:ccd:e793::3]:6443",
+		config.EnvoyLoadBalancer:   `"fc00:f853:ccd:e793::3"`,
+	}
+	for lbType, backend := range expected {
+		t.Run(string(lbType), func(t *testing.T) {
+			lb, err := Get(lbType)
+			if err != nil {
+				t.Fatalf("unexpected error getting load balancer: %v", err)
+			}
+			contents, err := lb.Config(data)
+			if err != nil {
+				t.Fatalf("unexpected error generating config: %v", err)
+			}
+			if !strings.Contains(contents, backend) {
+				t.Errorf("expected backend %s in config:\n%s", backend, contents)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadbalancer
+
+import (
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+)
+
+// nginx implements LoadBalancer using the nginx stream module
+type nginx struct{}
+
+const nginxConfigTemplate = `# config generated by kind
+events {
+  worker_connections 1024;
+}
+
+stream {
+  upstream kube_apiservers {
+    {{- range .BackendServers }}
+    server {{ .Address }};
+    {{- end }}
+  }
+
+  server {
+    listen {{ .ControlPlanePort }};
+    {{- if .IPv6 }}
+    listen [::]:{{ .ControlPlanePort }};
+    {{- end }}
+    proxy_pass kube_apiservers;
+  }
+}
+`
+
+func (n *nginx) Image() string {
+	return "nginx:1.15-alpine"
+}
+
+func (n *nginx) ConfigPath() string {
+	return "/etc/nginx/nginx.conf"
+}
+
+func (n *nginx) Config(data *ConfigData) (string, error) {
+	return executeTemplate("nginx", nginxConfigTemplate, data)
+}
+
+func (n *nginx) Reload(node *nodes.Node) error {
+	return node.Command("nginx", "-s", "reload").Run()
+}
+
+func (n *nginx) HealthCheck(node *nodes.Node) error {
+	return checkListening(node, kubeadm.APIServerPort)
+}
@@ -17,12 +17,17 @@ limitations under the License.
 package logs
 
 import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 
+	"sigs.k8s.io/kind/pkg/cluster/config"
 	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/docker"
 	"sigs.k8s.io/kind/pkg/exec"
 	"sigs.k8s.io/kind/pkg/util"
 )
@@ -58,7 +63,7 @@ func Collect(nodes []nodes.Node, dir string) error {
 		// TODO(bentheelder): record the kind version here as well
 		// record info about the host docker
 		execToPathFn(
-			exec.Command("docker", "info"),
+			docker.Command("info"),
 			"docker-info.txt",
 		),
 	}
@@ -67,10 +72,69 @@ func Collect(nodes []nodes.Node, dir string) error {
 		node := n // https://golang.org/doc/faq#closures_and_goroutines
 		fns = append(fns, func() error {
 			name := node.String()
+			// helper to copy the files under root on the node to dir, root may be
+			// a single file or not exist at all
+			// NOTE: we cannot just docker cp these because they are mostly symlinks,
+			// so we must list and resolve the symlinks
+			copyFilesFn := func(root, dir string) func() error {
+				return func() error {
+					// collect up file names
+					files, err := exec.CombinedOutputLines(
+						node.Command("sh", "-c", "find -L "+root+" -type f 2>/dev/null || true"),
+					)
+					if err != nil {
+						return err
+					}
+					// for each file, we want to copy it out at the path
+					// we find it (symlink or not), so we pipe cat in the container
+					// to a file in our logs dir on the host
+					copyFns := []errFn{}
+					for _, f := range files {
+						file := f // https://golang.org/doc/faq#closures_and_goroutines
+						targetPath := strings.TrimPrefix(file, root)
+						copyFns = append(copyFns, execToPathFn(
+							node.Command("cat", file),
+							filepath.Join(dir, targetPath),
+						))
+					}
+					return coalesce(copyFns...)
+				}
+			}
+			// the Windows node image does not run journald, and logs to files
+			if node.IsWindows() {
+				return coalesce(
+					execToPathFn(
+						docker.Command("inspect", name),
+						filepath.Join(name, "inspect.json"),
+					),
+					execToPathFn(
+						node.Command("cmd", "/c", "type", `C:\kind\version`),
+						filepath.Join(name, "kubernetes-version.txt"),
+					),
+					execToPathFn(
+						node.Command("cmd", "/c", "type", `C:\var\log\kubelet.log`),
+						filepath.Join(name, "kubelet.log"),
+					),
+				)
+			}
+			// the load balancer node runs the load balancer image, which
+			// logs to the container output
+			if role, _ := node.Role(); config.NodeRole(role) == config.ExternalLoadBalancerRole {
+				return coalesce(
+					execToPathFn(
+						docker.Command("inspect", name),
+						filepath.Join(name, "inspect.json"),
+					),
+					execToPathFn(
+						docker.Command("logs", name),
+						filepath.Join(name, "loadbalancer.log"),
+					),
+				)
+			}
 			return coalesce(
 				// record info about the node container
 				execToPathFn(
-					exec.Command("docker", "inspect", name),
+					docker.Command("inspect", name),
 					filepath.Join(name, "inspect.json"),
 				),
 				// grab all of the node logs
@@ -90,33 +154,21 @@ func Collect(nodes []nodes.Node, dir string) error {
 					node.Command("journalctl", "--no-pager", "-u", "docker.service"),
 					filepath.Join(name, "docker.log"),
 				),
-				// grab all container / pod logs
-				// NOTE: we cannot just docker cp this directory because
-				// it is mostly symlinks, so we must list and resolve the symlinks
-				func() error {
-					// collect up file names
-					files, err := exec.CombinedOutputLines(
-						node.Command("find", "-L", "/var/log", "-type", "f"),
-					)
-					if err != nil {
-						return err
-					}
-					// for each file, we want to copy it out at the path
-					// we find it (symlink or not), so we pipe cat in the container
-					// to a file in our logs dir on the host
-					copyFns := []errFn{}
-					for _, f := range files {
-						file := f // https://golang.org/doc/faq#closures_and_goroutines
-						targetPath := strings.TrimPrefix(file, "/var/log/")
-						copyFns = append(copyFns, func() error {
-							return execToPath(
-								node.Command("cat", file),
-								filepath.Join(name, targetPath),
-							)
-						})
-					}
-					return coalesce(copyFns...)
-				},
+				execToPathFn(
+					node.Command("journalctl", "--no-pager", "-u", "containerd.service"),
+					filepath.Join(name, "containerd.log"),
+				),
+				// record the containers, including the exited ones
+				execToPathFn(
+					node.Command("docker", "ps", "--all"),
+					filepath.Join(name, "containers.txt"),
+				),
+				// grab the kubeadm config and the static pod manifests, the
+				// kubeadm output is in /var/log
+				copyFilesFn("/kind/kubeadm.conf", filepath.Join(name, "kubeadm.conf")),
+				copyFilesFn("/etc/kubernetes/manifests/", filepath.Join(name, "manifests")),
+				// grab all container / pod logs, including the static pods
+				copyFilesFn("/var/log/", name),
 			)
 		})
 	}
@@ -124,6 +176,54 @@ func Collect(nodes []nodes.Node, dir string) error {
 	return coalesce(fns...)
 }
 
+// Archive writes the logs collected to dir by Collect to a gzipped tarball at
+// dest, under a top-level directory named after dest without the extension
+func Archive(dir, dest string) error {
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+
+	topLevel := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(dest), ".tgz"), ".tar.gz")
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(topLevel, rel))
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
 // colaese runs fns concurrently, returning an Errors if there are > 1 errors
 func coalesce(fns ...errFn) error {
 	// run all fns concurrently
@@ -0,0 +1,108 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+	"sigs.k8s.io/kind/pkg/docker"
+)
+
+// nameCollisions returns the containers and the networks, other than the
+// ones of the cluster, that take the names of the node containers or of the
+// network of the cluster created from cfg
+func (c *Context) nameCollisions(cfg *config.Config, derived *derivedConfigData) (containers, networks []string, err error) {
+	// the name filter matches substrings, the names are checked below
+	listed, err := docker.ListContainers("name=" + c.nodeContainerName(""))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to list containers")
+	}
+	existing := map[string]bool{}
+	for _, name := range listed {
+		existing[name] = true
+	}
+	for _, replica := range derived.AllReplicas() {
+		if name := c.nodeContainerName(replica.Name); existing[name] {
+			containers = append(containers, name)
+		}
+	}
+
+	// networks named explicitly in the config are meant to be shared, the
+	// network named after the cluster may be left over by the cluster itself
+	if cfg.Networking.DockerNetwork.Name != "" {
+		return containers, nil, nil
+	}
+	network := c.networkName(&cfg.Networking)
+	listed, err = docker.ListNetworks("name=" + network)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to list networks")
+	}
+	for _, name := range listed {
+		if name != network {
+			continue
+		}
+		lines, err := docker.InspectNetwork(network, fmt.Sprintf("{{index .Labels %q}}", consts.ClusterLabelKey))
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to inspect network %s", network)
+		}
+		if len(lines) != 1 || strings.TrimSpace(lines[0]) != c.Name() {
+			networks = append(networks, network)
+		}
+	}
+	return containers, networks, nil
+}
+
+// DeleteStale deletes the cluster, even if protected, along with the docker
+// objects taking the names of the node containers and of the network of the
+// cluster created from cfg, see NameCollisionError.
+// This is meant for replacing a stale cluster, e.g. left over by an
+// interrupted CI job, before creating it again
+func (c *Context) DeleteStale(cfg *config.Config) error {
+	// validate before deleting anything, the cluster is created next
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	derived, err := deriveInfo(cfg)
+	if err != nil {
+		return err
+	}
+	if err := c.ForceDelete(); err != nil {
+		return errors.Wrap(err, "failed to delete cluster")
+	}
+	containers, networks, err := c.nameCollisions(cfg, derived)
+	if err != nil {
+		return err
+	}
+	if len(containers) > 0 {
+		c.Logger().Warnf("Deleting the containers taking the node names of cluster %q: %v", c.Name(), containers)
+		if err := docker.Delete(containers...); err != nil {
+			return errors.Wrap(err, "failed to delete containers")
+		}
+	}
+	if len(networks) > 0 {
+		c.Logger().Warnf("Deleting the networks taking the network name of cluster %q: %v", c.Name(), networks)
+		if err := docker.DeleteNetworks(networks...); err != nil {
+			return errors.Wrap(err, "failed to delete networks")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// NetworkFaults describes degraded network conditions between the nodes of
+// a cluster, see Context.ApplyNetworkFaults
+type NetworkFaults struct {
+	// Links are the conditions of the traffic between nodes
+	Links []NetworkLink `json:"links"`
+}
+
+// NetworkLink describes the conditions of the traffic sent from nodes to
+// other nodes, the traffic sent back is not affected unless described by
+// another link
+type NetworkLink struct {
+	// From are the names of the nodes sending the traffic, e.g.
+	// "kind-1-worker"
+	// Defaults to all the nodes of the cluster
+	From []string `json:"from,omitempty"`
+	// To are the names of the nodes receiving the traffic
+	// Defaults to all the nodes of the cluster other than the sending node
+	To []string `json:"to,omitempty"`
+	// Latency is the delay added to the traffic, e.g. "100ms"
+	Latency string `json:"latency,omitempty"`
+	// Jitter is the random variation of Latency, e.g. "10ms"
+	Jitter string `json:"jitter,omitempty"`
+	// Loss is the percentage of packets dropped, e.g. "5%"
+	Loss string `json:"loss,omitempty"`
+	// Partition drops all the traffic
+	Partition bool `json:"partition,omitempty"`
+}
+
+// LoadNetworkFaults reads the network faults from the YAML file at path
+func LoadNetworkFaults(path string) (NetworkFaults, error) {
+	faults := NetworkFaults{}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return faults, errors.Wrap(err, "error reading file")
+	}
+	if err := yaml.UnmarshalStrict(contents, &faults); err != nil {
+		return faults, errors.Wrapf(err, "invalid network faults %s", path)
+	}
+	return faults, nil
+}
+
+// faultsDevice is the interface of the nodes on the docker network
+const faultsDevice = "eth0"
+
+// ApplyNetworkFaults applies the network conditions to the nodes of the
+// cluster with tc netem rules on the sending nodes, replacing the ones
+// applied before, until they are reverted with RevertNetworkFaults
+func (c *Context) ApplyNetworkFaults(faults NetworkFaults) error {
+	n, err := c.ListNodes()
+	if err != nil {
+		return fmt.Errorf("error listing nodes: %v", err)
+	}
+	names := []string{}
+	byName := map[string]*nodes.Node{}
+	for i := range n {
+		names = append(names, n[i].String())
+		byName[n[i].String()] = &n[i]
+	}
+	links, err := networkLinks(faults, names)
+	if err != nil {
+		return err
+	}
+
+	status := logutil.NewStatus(os.Stdout)
+	status.MaybeWrapLogger(c.Logger())
+	defer status.End(false)
+
+	addresses := map[string][]string{}
+	for _, name := range names {
+		ip, err := byName[name].IP()
+		if err != nil {
+			return err
+		}
+		ipv6, err := byName[name].IPv6()
+		if err != nil {
+			return err
+		}
+		for _, address := range []string{ip, ipv6} {
+			if address != "" {
+				addresses[name] = append(addresses[name], address)
+			}
+		}
+	}
+
+	for _, name := range names {
+		node := byName[name]
+		if len(links[name]) > 0 {
+			if err := checkFaultsNode(node); err != nil {
+				return err
+			}
+		} else if !faultsSupported(node) {
+			continue
+		}
+		// the receiving nodes with the same conditions share a netem qdisc
+		to := []string{}
+		for t := range links[name] {
+			to = append(to, t)
+		}
+		sort.Strings(to)
+		rules := []netemRule{}
+		ruleByArgs := map[string]int{}
+		for _, t := range to {
+			args, err := netemArgs(links[name][t])
+			if err != nil {
+				return err
+			}
+			i, ok := ruleByArgs[strings.Join(args, " ")]
+			if !ok {
+				i = len(rules)
+				ruleByArgs[strings.Join(args, " ")] = i
+				rules = append(rules, netemRule{Args: args})
+			}
+			rules[i].Addresses = append(rules[i].Addresses, addresses[t]...)
+		}
+		if len(rules) > maxNetemRules {
+			return fmt.Errorf("the traffic from node %s has more than %d different conditions", name, maxNetemRules)
+		}
+		status.Start(fmt.Sprintf("[%s] Applying network faults 🌩", name))
+		if err := node.Command("/bin/sh", "-c", netemScript(rules)).Run(); err != nil {
+			return errors.Wrapf(err, "failed to apply network faults on node %s", name)
+		}
+	}
+	status.End(true)
+	return nil
+}
+
+// RevertNetworkFaults removes the network conditions applied to the nodes of
+// the cluster with ApplyNetworkFaults
+func (c *Context) RevertNetworkFaults() error {
+	n, err := c.ListNodes()
+	if err != nil {
+		return fmt.Errorf("error listing nodes: %v", err)
+	}
+
+	status := logutil.NewStatus(os.Stdout)
+	status.MaybeWrapLogger(c.Logger())
+	defer status.End(false)
+
+	for i := range n {
+		node := &n[i]
+		if !faultsSupported(node) {
+			continue
+		}
+		status.Start(fmt.Sprintf("[%s] Reverting network faults ☀", node.String()))
+		if err := node.Command("/bin/sh", "-c", netemScript(nil)).Run(); err != nil {
+			return errors.Wrapf(err, "failed to revert network faults on node %s", node.String())
+		}
+	}
+	status.End(true)
+	return nil
+}
+
+// faultsSupported returns true if network faults can be applied on the
+// node, this needs tc of the node image
+func faultsSupported(node *nodes.Node) bool {
+	return checkFaultsNode(node) == nil
+}
+
+func checkFaultsNode(node *nodes.Node) error {
+	role, err := node.Role()
+	if err != nil {
+		return err
+	}
+	if config.NodeRole(role) == config.ExternalLoadBalancerRole || node.IsWindows() {
+		return fmt.Errorf("network faults are not supported for traffic sent from node %s", node.String())
+	}
+	return nil
+}
+
+// networkLinks resolves the links of the faults between the named nodes,
+// returning the conditions of the traffic by sending and receiving node
+func networkLinks(faults NetworkFaults, names []string) (map[string]map[string]NetworkLink, error) {
+	known := map[string]bool{}
+	for _, name := range names {
+		known[name] = true
+	}
+	resolve := func(i int, field string, list []string) ([]string, error) {
+		if len(list) == 0 {
+			return names, nil
+		}
+		for _, name := range list {
+			if !known[name] {
+				return nil, fmt.Errorf("links[%d].%s: no node named %q found", i, field, name)
+			}
+		}
+		return list, nil
+	}
+
+	links := map[string]map[string]NetworkLink{}
+	for i, link := range faults.Links {
+		if _, err := netemArgs(link); err != nil {
+			return nil, errors.Wrapf(err, "links[%d]", i)
+		}
+		from, err := resolve(i, "from", link.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := resolve(i, "to", link.To)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range from {
+			for _, t := range to {
+				if f == t {
+					continue
+				}
+				if links[f] == nil {
+					links[f] = map[string]NetworkLink{}
+				}
+				if _, ok := links[f][t]; ok {
+					return nil, fmt.Errorf("links[%d]: the traffic from %s to %s is described by more than one link", i, f, t)
+				}
+				links[f][t] = link
+			}
+		}
+	}
+	return links, nil
+}
+
+// netemArgs returns the tc netem arguments of the conditions of a link
+func netemArgs(link NetworkLink) ([]string, error) {
+	if link.Partition {
+		if link.Latency != "" || link.Jitter != "" || link.Loss != "" {
+			return nil, errors.New("partition drops all the traffic, latency, jitter and loss are not supported with it")
+		}
+		return []string{"loss", "100%"}, nil
+	}
+
+	args := []string{}
+	if link.Latency != "" {
+		latency, err := time.ParseDuration(link.Latency)
+		if err != nil || latency <= 0 {
+			return nil, fmt.Errorf("latency must be a positive duration, e.g. 100ms, got %q", link.Latency)
+		}
+		args = append(args, "delay", netemTime(latency))
+		if link.Jitter != "" {
+			jitter, err := time.ParseDuration(link.Jitter)
+			if err != nil || jitter < 0 {
+				return nil, fmt.Errorf("jitter must be a duration, e.g. 10ms, got %q", link.Jitter)
+			}
+			args = append(args, netemTime(jitter))
+		}
+	} else if link.Jitter != "" {
+		return nil, errors.New("jitter requires latency")
+	}
+	if link.Loss != "" {
+		loss, err := strconv.ParseFloat(strings.TrimSuffix(link.Loss, "%"), 64)
+		if err != nil || !strings.HasSuffix(link.Loss, "%") || loss <= 0 || loss > 100 {
+			return nil, fmt.Errorf("loss must be a percentage, e.g. 5%%, got %q", link.Loss)
+		}
+		args = append(args, "loss", link.Loss)
+	}
+	if len(args) == 0 {
+		return nil, errors.New("one of latency, loss or partition is required")
+	}
+	return args, nil
+}
+
+// netemTime returns the duration in the time format of tc
+func netemTime(d time.Duration) string {
+	return fmt.Sprintf("%dus", int64(d/time.Microsecond))
+}
+
+// netemRule is the netem qdisc of the traffic sent to the addresses of
+// nodes
+type netemRule struct {
+	Addresses []string
+	Args      []string
+}
+
+// maxNetemRules is the maximum number of rules of a node, the prio qdisc
+// has up to 16 bands, 3 of which are used by the traffic not affected
+const maxNetemRules = 13
+
+// netemScript returns the script replacing the root qdisc of faultsDevice
+// with a prio qdisc sending the traffic to the addresses of each rule through
+// a netem qdisc, the other traffic is not affected, without rules the script
+// only removes the qdiscs
+func netemScript(rules []netemRule) string {
+	script := []string{
+		fmt.Sprintf("tc qdisc del dev %s root 2>/dev/null || true", faultsDevice),
+	}
+	if len(rules) == 0 {
+		return strings.Join(script, "\n") + "\n"
+	}
+	// the bands of the default priomap are 1:1 to 1:3, and each rule gets
+	// its own band after them
+	script = append(script,
+		"set -e",
+		fmt.Sprintf("tc qdisc add dev %s root handle 1: prio bands %d priomap 1 2 2 2 1 2 0 0 1 1 1 1 1 1 1 1", faultsDevice, len(rules)+3),
+	)
+	for i, rule := range rules {
+		band := i + 4
+		script = append(script, fmt.Sprintf("tc qdisc add dev %s parent 1:%d handle %d: netem %s", faultsDevice, band, band*10, strings.Join(rule.Args, " ")))
+		for _, address := range rule.Addresses {
+			if strings.Contains(address, ":") {
+				script = append(script, fmt.Sprintf("tc filter add dev %s parent 1: protocol ipv6 prio 2 u32 match ip6 dst %s/128 flowid 1:%d", faultsDevice, address, band))
+			} else {
+				script = append(script, fmt.Sprintf("tc filter add dev %s parent 1: protocol ip prio 1 u32 match ip dst %s/32 flowid 1:%d", faultsDevice, address, band))
+			}
+		}
+	}
+	return strings.Join(script, "\n") + "\n"
+}
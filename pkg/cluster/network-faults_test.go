@@ -0,0 +1,171 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNetworkLinks(t *testing.T) {
+	names := []string{"kind-control-plane", "kind-worker", "kind-worker2"}
+	cases := []struct {
+		Name     string
+		Links    []NetworkLink
+		Expected map[string][]string
+		Error    bool
+	}{
+		{
+			Name:  "latency between all the nodes",
+			Links: []NetworkLink{{Latency: "100ms"}},
+			Expected: map[string][]string{
+				"kind-control-plane": {"kind-worker", "kind-worker2"},
+				"kind-worker":        {"kind-control-plane", "kind-worker2"},
+				"kind-worker2":       {"kind-control-plane", "kind-worker"},
+			},
+		},
+		{
+			Name: "partition of a node",
+			Links: []NetworkLink{
+				{From: []string{"kind-worker2"}, Partition: true},
+				{To: []string{"kind-worker2"}, Partition: true},
+			},
+			Expected: map[string][]string{
+				"kind-control-plane": {"kind-worker2"},
+				"kind-worker":        {"kind-worker2"},
+				"kind-worker2":       {"kind-control-plane", "kind-worker"},
+			},
+		},
+		{
+			Name:  "unknown node",
+			Links: []NetworkLink{{From: []string{"kind-worker3"}, Loss: "5%"}},
+			Error: true,
+		},
+		{
+			Name: "overlapping links",
+			Links: []NetworkLink{
+				{From: []string{"kind-worker"}, Loss: "5%"},
+				{To: []string{"kind-worker2"}, Latency: "10ms"},
+			},
+			Error: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			links, err := networkLinks(NetworkFaults{Links: tc.Links}, names)
+			if tc.Error {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := map[string][]string{}
+			for _, from := range names {
+				for _, to := range names {
+					if _, ok := links[from][to]; ok {
+						got[from] = append(got[from], to)
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, tc.Expected) {
+				t.Errorf("expected %v but got %v", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestNetemArgs(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Link     NetworkLink
+		Expected string
+		Error    bool
+	}{
+		{
+			Name:     "latency and loss",
+			Link:     NetworkLink{Latency: "1.5s", Jitter: "10ms", Loss: "0.5%"},
+			Expected: "delay 1500000us 10000us loss 0.5%",
+		},
+		{
+			Name:     "partition",
+			Link:     NetworkLink{Partition: true},
+			Expected: "loss 100%",
+		},
+		{
+			Name:  "partition and latency",
+			Link:  NetworkLink{Partition: true, Latency: "10ms"},
+			Error: true,
+		},
+		{
+			Name:  "jitter without latency",
+			Link:  NetworkLink{Jitter: "10ms"},
+			Error: true,
+		},
+		{
+			Name:  "loss without percent",
+			Link:  NetworkLink{Loss: "5"},
+			Error: true,
+		},
+		{
+			Name:  "no conditions",
+			Error: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			args, err := netemArgs(tc.Link)
+			if tc.Error {
+				if err == nil {
+					t.Errorf("expected an error but got %v", args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := strings.Join(args, " "); got != tc.Expected {
+				t.Errorf("expected %q but got %q", tc.Expected, got)
+			}
+		})
+	}
+}
+
+func TestNetemScript(t *testing.T) {
+	script := netemScript([]netemRule{
+		{Addresses: []string{"172.17.0.3", "fc00::3"}, Args: []string{"delay", "100000us"}},
+		{Addresses: []string{"172.17.0.4"}, Args: []string{"loss", "100%"}},
+	})
+	expected := `tc qdisc del dev eth0 root 2>/dev/null || true
+set -e
+tc qdisc add dev eth0 root handle 1: prio bands 5 priomap 1 2 2 2 1 2 0 0 1 1 1 1 1 1 1 1
+tc qdisc add dev eth0 parent 1:4 handle 40: netem delay 100000us
+tc filter add dev eth0 parent 1: protocol ip prio 1 u32 match ip dst 172.17.0.3/32 flowid 1:4
+tc filter add dev eth0 parent 1: protocol ipv6 prio 2 u32 match ip6 dst fc00::3/128 flowid 1:4
+tc qdisc add dev eth0 parent 1:5 handle 50: netem loss 100%
+tc filter add dev eth0 parent 1: protocol ip prio 1 u32 match ip dst 172.17.0.4/32 flowid 1:5
+`
+	if script != expected {
+		t.Errorf("expected:\n%s\nbut got:\n%s", expected, script)
+	}
+	if script := netemScript(nil); script != "tc qdisc del dev eth0 root 2>/dev/null || true\n" {
+		t.Errorf("unexpected revert script:\n%s", script)
+	}
+}
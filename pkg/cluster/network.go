@@ -0,0 +1,155 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/docker"
+)
+
+// defaultMTU is the docker default MTU of bridge networks
+const defaultMTU = 1500
+
+// networkName returns the name of the docker network the nodes of the
+// cluster are attached to, by default this is named after the cluster
+func (c *Context) networkName(networking *config.Networking) string {
+	if networking.DockerNetwork.Name != "" {
+		return networking.DockerNetwork.Name
+	}
+	return c.ClusterName()
+}
+
+// ipFamilyLabel returns the docker object label recording the IP family of
+// IPv6 and dual-stack clusters
+func ipFamilyLabel(ipFamily config.IPFamily) string {
+	return fmt.Sprintf("%s=%s", consts.IPFamilyKey, ipFamily)
+}
+
+// ensureNetwork creates the docker network of the cluster, unless it already
+// exists, and returns its name. Networks created by kind are labeled with the
+// cluster label, and deleted along with the cluster, see deleteNetworks
+func (c *Context) ensureNetwork(networking *config.Networking) (string, error) {
+	network := c.networkName(networking)
+	existing, err := docker.ListNetworks("name=" + network)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list networks")
+	}
+	for _, name := range existing {
+		if name != network {
+			continue
+		}
+		if networking.MTU != 0 {
+			mtu, err := networkMTU(network)
+			if err != nil {
+				return "", err
+			}
+			if mtu != networking.MTU {
+				c.Logger().Warnf("The MTU of the existing network %s is %d, not %d", network, mtu, networking.MTU)
+			}
+		}
+		return network, nil
+	}
+	ipv6 := ""
+	if networking.IPFamily == config.IPv6Family || networking.IPFamily == config.DualStackFamily {
+		ipv6 = networking.DockerNetwork.IPv6Subnet
+		if ipv6 == "" {
+			ipv6 = ipv6Subnet(c.Name())
+		}
+	}
+	if err := docker.CreateNetwork(
+		network, networking.DockerNetwork.Subnet, networking.DockerNetwork.Gateway, ipv6, networking.MTU, c.ClusterLabel(),
+	); err != nil {
+		return "", errors.Wrapf(err, "failed to create network %s", network)
+	}
+	return network, nil
+}
+
+// inspectNetwork returns the settings of the existing docker network with
+// the given name, so that an equivalent network can be created again
+func inspectNetwork(name string) (config.DockerNetwork, error) {
+	network := config.DockerNetwork{Name: name}
+	subnets, err := docker.NetworkSubnets(name)
+	if err != nil {
+		return network, errors.Wrapf(err, "failed to inspect network %s", name)
+	}
+	for _, subnet := range subnets {
+		if subnet.IPv6() {
+			network.IPv6Subnet = subnet.Subnet
+			continue
+		}
+		network.Subnet = subnet.Subnet
+		network.Gateway = subnet.Gateway
+	}
+	return network, nil
+}
+
+// networkMTU returns the MTU of the docker network with the given name
+func networkMTU(name string) (int32, error) {
+	mtu, err := docker.NetworkMTU(name)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to inspect network %s", name)
+	}
+	if mtu == 0 {
+		return defaultMTU, nil
+	}
+	return mtu, nil
+}
+
+// deleteNetworks deletes the docker networks of the cluster, if any
+func (c *Context) deleteNetworks() error {
+	networks, err := docker.ListNetworks("label=" + c.ClusterLabel())
+	if err != nil {
+		return errors.Wrap(err, "failed to list networks")
+	}
+	if len(networks) == 0 {
+		return nil
+	}
+	return docker.DeleteNetworks(networks...)
+}
+
+// ipv6Subnet returns the IPv6 subnet of the network of the cluster with the
+// given name, this is a unique local /64 derived from the name so that
+// the networks of different clusters are unlikely to overlap
+func ipv6Subnet(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return fmt.Sprintf("fc00:f853:ccd:%x::/64", h.Sum32()&0xffff)
+}
+
+// nodeIP returns the address of the node in the docker network the cluster
+// components use, the IPv6 address for IPv6 clusters, the IPv4 address
+// otherwise
+func nodeIP(node *nodes.Node, ipFamily config.IPFamily) (string, error) {
+	if ipFamily != config.IPv6Family {
+		return node.IP()
+	}
+	ip, err := node.IPv6()
+	if err != nil {
+		return "", err
+	}
+	if ip == "" {
+		return "", fmt.Errorf("node %s has no IPv6 address", node.String())
+	}
+	return ip, nil
+}
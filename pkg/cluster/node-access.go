@@ -0,0 +1,101 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+)
+
+// Node returns the node of the cluster named name, which is either the node
+// replica name e.g. worker2, the node container name e.g. kind-1-worker2, or
+// a role e.g. worker, selecting the first node with the role
+func (c *Context) Node(name string) (*nodes.Node, error) {
+	n, err := c.ListNodes()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+	for i := range n {
+		if n[i].String() == name || n[i].String() == c.nodeContainerName(name) {
+			return &n[i], nil
+		}
+	}
+	switch role := config.NodeRole(name); role {
+	case config.ControlPlaneRole, config.WorkerRole, config.ExternalEtcdRole, config.ExternalLoadBalancerRole:
+		return c.NodeByRole(role, 1)
+	}
+	return nil, fmt.Errorf("no node %q in cluster %q, the nodes are: %s", name, c.name, strings.Join(c.replicaNames(n), ", "))
+}
+
+// NodeByRole returns the node of the cluster with role and the 1-based
+// index among the nodes with that role, e.g. 2 for worker2
+func (c *Context) NodeByRole(role config.NodeRole, index int) (*nodes.Node, error) {
+	n, err := c.ListNodes()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+	withRole := []nodes.Node{}
+	for i := range n {
+		nodeRole, err := n[i].Role()
+		if err != nil {
+			return nil, err
+		}
+		if config.NodeRole(nodeRole) == role {
+			withRole = append(withRole, n[i])
+		}
+	}
+	// the replicas of a role are named with their index, if more than one
+	sort.SliceStable(withRole, func(i, j int) bool {
+		return replicaIndex(c.replicaName(&withRole[i])) < replicaIndex(c.replicaName(&withRole[j]))
+	})
+	if index < 1 || index > len(withRole) {
+		return nil, fmt.Errorf("no %s node %d in cluster %q, it has %d %s nodes", role, index, c.name, len(withRole), role)
+	}
+	return &withRole[index-1], nil
+}
+
+// replicaName returns the node replica name of node, e.g. worker2
+func (c *Context) replicaName(node *nodes.Node) string {
+	return strings.TrimPrefix(node.String(), c.nodeContainerName(""))
+}
+
+// replicaNames returns the sorted node replica names of n
+func (c *Context) replicaNames(n []nodes.Node) []string {
+	names := []string{}
+	for i := range n {
+		names = append(names, c.replicaName(&n[i]))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// replicaIndex returns the index a node replica name ends with, or 0
+func replicaIndex(replicaName string) int {
+	i := len(replicaName)
+	for i > 0 && replicaName[i-1] >= '0' && replicaName[i-1] <= '9' {
+		i--
+	}
+	index, _ := strconv.Atoi(replicaName[i:])
+	return index
+}
@@ -16,16 +16,22 @@ limitations under the License.
 
 package cluster
 
-// a default storage class
-// we need this for e2es (StatefulSet)
-const defaultStorageClassManifest = `# host-path based default storage class
-apiVersion: storage.k8s.io/v1
-kind: StorageClass
-metadata:
-  namespace: kube-system
-  name: standard
-  annotations:
-    storageclass.beta.kubernetes.io/is-default-class: "true"
-  labels:
-    addonmanager.kubernetes.io/mode: EnsureExists
-provisioner: kubernetes.io/host-path`
+import (
+	"testing"
+)
+
+func TestReplicaIndex(t *testing.T) {
+	cases := map[string]int{
+		"worker":         0,
+		"worker1":        1,
+		"worker10":       10,
+		"control-plane2": 2,
+		"lb":             0,
+		"":               0,
+	}
+	for name, expected := range cases {
+		if index := replicaIndex(name); index != expected {
+			t.Errorf("expected index %d for %q but got %d", expected, name, index)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// nodeSystemReserved returns the value of the kubelet --system-reserved flag
+// for a node limited to resources, if any
+// The kubelet detects the capacity of the host rather than the limits of the
+// node container, so the difference is reserved for the system in order for
+// the node allocatable to match the limits
+func nodeSystemReserved(node *nodes.Node, resources config.NodeResources) (string, error) {
+	if resources.CPUs == "" && resources.Memory == "" {
+		return "", nil
+	}
+	cpus, memory, err := nodeCapacity(node)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the capacity of node %s", node)
+	}
+	return systemReserved(resources, cpus, memory)
+}
+
+// nodeCapacity returns the number of CPUs and the bytes of memory detected
+// by the kubelet on the node, these are the ones of the host
+func nodeCapacity(node *nodes.Node) (cpus int64, memory int64, err error) {
+	lines, err := exec.CombinedOutputLines(node.Command("getconf", "_NPROCESSORS_ONLN"))
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(lines) != 1 {
+		return 0, 0, fmt.Errorf("invalid getconf output: %v", lines)
+	}
+	cpus, err = strconv.ParseInt(strings.TrimSpace(lines[0]), 10, 64)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid number of CPUs")
+	}
+
+	lines, err = exec.CombinedOutputLines(node.Command("cat", "/proc/meminfo"))
+	if err != nil {
+		return 0, 0, err
+	}
+	memory, err = memTotal(lines)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cpus, memory, nil
+}
+
+// memTotal returns the MemTotal of the /proc/meminfo lines in bytes
+func memTotal(meminfo []string) (int64, error) {
+	for _, line := range meminfo {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+		kb, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, errors.Wrap(err, "invalid MemTotal")
+		}
+		return kb * 1024, nil
+	}
+	return 0, errors.New("MemTotal not found in /proc/meminfo")
+}
+
+// systemReserved returns the resources of the host capacity beyond the
+// node limits in the format of the kubelet --system-reserved flag, limits
+// exceeding the capacity do not reserve anything
+func systemReserved(resources config.NodeResources, capacityCPUs, capacityMemory int64) (string, error) {
+	reserved := []string{}
+	if resources.CPUs != "" {
+		cpus, err := resource.ParseQuantity(resources.CPUs)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid cpus %q", resources.CPUs)
+		}
+		if milliCPUs := capacityCPUs*1000 - cpus.MilliValue(); milliCPUs > 0 {
+			reserved = append(reserved, "cpu="+resource.NewMilliQuantity(milliCPUs, resource.DecimalSI).String())
+		}
+	}
+	if resources.Memory != "" {
+		memory, err := resource.ParseQuantity(resources.Memory)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid memory %q", resources.Memory)
+		}
+		if reservedMemory := capacityMemory - memory.Value(); reservedMemory > 0 {
+			reserved = append(reserved, "memory="+resource.NewQuantity(reservedMemory, resource.BinarySI).String())
+		}
+	}
+	return strings.Join(reserved, ","), nil
+}
+
+// nodeReservedCPUs returns the value of the kubelet --reserved-cpus flag for
+// a node with the static CPU manager policy, if any
+// The kubelet detects the CPUs of the host rather than the cpuset of the node
+// container, so the host CPUs out of the cpuset are reserved too, in order
+// for the CPU manager to assign exclusive CPUs of the node only
+func nodeReservedCPUs(node *nodes.Node, cpuManager config.CPUManager) (string, error) {
+	if cpuManager.Policy != config.StaticCPUManagerPolicy {
+		return "", nil
+	}
+	cpus, _, hostCPUs, err := node.CPUs()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the CPUs of node %s", node)
+	}
+	return reservedCPUs(cpuManager.ReservedCPUs, cpus, hostCPUs)
+}
+
+// reservedCPUs returns the list of the reserved CPUs of a node running on
+// the cpus of the host, the reserved CPUs default to the first CPU of the node
+func reservedCPUs(reserved, cpus, hostCPUs string) (string, error) {
+	nodeCPUs, err := parseCPUList(cpus)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid node CPUs %q", cpus)
+	}
+	if len(nodeCPUs) == 0 {
+		return "", errors.New("the node has no CPUs")
+	}
+	allCPUs, err := parseCPUList(hostCPUs)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid host CPUs %q", hostCPUs)
+	}
+	reservedList := []int{nodeCPUs[0]}
+	if reserved != "" {
+		if reservedList, err = parseCPUList(reserved); err != nil {
+			return "", errors.Wrapf(err, "invalid reserved CPUs %q", reserved)
+		}
+	}
+
+	isNodeCPU := map[int]bool{}
+	for _, cpu := range nodeCPUs {
+		isNodeCPU[cpu] = true
+	}
+	isReserved := map[int]bool{}
+	for _, cpu := range reservedList {
+		if !isNodeCPU[cpu] {
+			return "", fmt.Errorf("reserved CPU %d is not a CPU of the node (%s)", cpu, cpus)
+		}
+		isReserved[cpu] = true
+	}
+	for _, cpu := range allCPUs {
+		if !isNodeCPU[cpu] {
+			isReserved[cpu] = true
+		}
+	}
+	// the static policy needs CPUs to assign
+	if len(isReserved) >= len(allCPUs) {
+		return "", fmt.Errorf("all the CPUs of the node (%s) are reserved", cpus)
+	}
+	list := []int{}
+	for cpu := range isReserved {
+		list = append(list, cpu)
+	}
+	return formatCPUList(list), nil
+}
+
+// parseCPUList returns the sorted CPUs of a list in the format of the cpuset
+// cgroup, e.g. "0-3,8"
+func parseCPUList(list string) ([]int, error) {
+	set := map[int]bool{}
+	for _, part := range strings.Split(list, ",") {
+		if part == "" {
+			continue
+		}
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, err
+			}
+		}
+		if first < 0 || last < first {
+			return nil, fmt.Errorf("invalid range %q", part)
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			set[cpu] = true
+		}
+	}
+	cpus := []int{}
+	for cpu := range set {
+		cpus = append(cpus, cpu)
+	}
+	sort.Ints(cpus)
+	return cpus, nil
+}
+
+// formatCPUList returns the list of CPUs in the format of the cpuset cgroup,
+// with ranges of consecutive CPUs, e.g. "0-3,8"
+func formatCPUList(cpus []int) string {
+	sorted := append([]int{}, cpus...)
+	sort.Ints(sorted)
+	parts := []string{}
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+		if j == i {
+			parts = append(parts, strconv.Itoa(sorted[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", sorted[i], sorted[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestSystemReserved(t *testing.T) {
+	cases := []struct {
+		Name      string
+		Resources config.NodeResources
+		Expected  string
+	}{
+		{
+			Name:     "no limits",
+			Expected: "",
+		},
+		{
+			Name:      "cpu and memory limits",
+			Resources: config.NodeResources{CPUs: "1.5", Memory: "2Gi"},
+			Expected:  "cpu=2500m,memory=6Gi",
+		},
+		{
+			Name:      "memory limit",
+			Resources: config.NodeResources{Memory: "512Mi", PIDs: 100},
+			Expected:  "memory=7680Mi",
+		},
+		{
+			Name:      "limits beyond the capacity",
+			Resources: config.NodeResources{CPUs: "8", Memory: "16Gi"},
+			Expected:  "",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			reserved, err := systemReserved(tc.Resources, 4, 8*1024*1024*1024)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if reserved != tc.Expected {
+				t.Errorf("expected %q but got %q", tc.Expected, reserved)
+			}
+		})
+	}
+}
+
+func TestMemTotal(t *testing.T) {
+	memory, err := memTotal([]string{
+		"MemTotal:        8046068 kB",
+		"MemFree:          245164 kB",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if memory != 8046068*1024 {
+		t.Errorf("expected %d but got %d", 8046068*1024, memory)
+	}
+	if _, err := memTotal([]string{"MemFree: 245164 kB"}); err == nil {
+		t.Error("expected an error without MemTotal")
+	}
+}
+
+func TestReservedCPUs(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Reserved string
+		CPUs     string
+		Expected string
+		Error    bool
+	}{
+		{
+			Name:     "all the host CPUs",
+			CPUs:     "0-7",
+			Expected: "0",
+		},
+		{
+			Name:     "reserved CPUs",
+			Reserved: "0,1",
+			CPUs:     "0-7",
+			Expected: "0-1",
+		},
+		{
+			Name:     "cpuset",
+			CPUs:     "2-3,6",
+			Expected: "0-2,4-5,7",
+		},
+		{
+			Name:     "reserved CPUs out of the cpuset",
+			Reserved: "0",
+			CPUs:     "2-3",
+			Error:    true,
+		},
+		{
+			Name:     "all the CPUs reserved",
+			Reserved: "4",
+			CPUs:     "4",
+			Error:    true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			reserved, err := reservedCPUs(tc.Reserved, tc.CPUs, "0-7")
+			if tc.Error {
+				if err == nil {
+					t.Errorf("expected an error but got %q", reserved)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if reserved != tc.Expected {
+				t.Errorf("expected %q but got %q", tc.Expected, reserved)
+			}
+		})
+	}
+}
+
+func TestFormatCPUList(t *testing.T) {
+	cpus, err := parseCPUList("8,0-3,2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if list := formatCPUList(cpus); list != "0-3,8" {
+		t.Errorf("expected %q but got %q", "0-3,8", list)
+	}
+	if _, err := parseCPUList("3-1"); err == nil {
+		t.Error("expected an error with an invalid range")
+	}
+}
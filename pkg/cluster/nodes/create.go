@@ -19,10 +19,19 @@ package nodes
 import (
 	"fmt"
 	"net"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/consts"
 	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
 	"sigs.k8s.io/kind/pkg/docker"
+	"sigs.k8s.io/kind/pkg/exec"
 )
 
 // FromID creates a node handle from the node (container's) ID
@@ -43,20 +52,100 @@ func getPort() (int, error) {
 	return port, nil
 }
 
+// apiServerArgs returns the docker run arguments for publishing the API
+// server on apiServerAddress:apiServerPort, or on all the host interfaces
+// and a random port if they are not set, along with the host port
+func apiServerArgs(apiServerAddress string, apiServerPort int32) ([]string, int, error) {
+	port := int(apiServerPort)
+	if port == 0 {
+		// gets a random host port for the API server
+		var err error
+		port, err = getPort()
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "failed to get port for API server")
+		}
+	}
+	listenAddress := apiServerAddress
+	if strings.Contains(listenAddress, ":") {
+		listenAddress = "[" + listenAddress + "]"
+	}
+	if listenAddress != "" {
+		listenAddress += ":"
+	}
+	return []string{
+		// publish selected port for the API server, publishing also exposes
+		// it, nerdctl does not support --expose
+		"-p", fmt.Sprintf("%s%d:%d", listenAddress, port, kubeadm.APIServerPort),
+	}, port, nil
+}
+
+// NodeOptions are the settings of the container of a control-plane or worker
+// node, see CreateControlPlaneNode, CreateWorkerNode and RecreateNode
+type NodeOptions struct {
+	// ExtraMounts are mounted into the node container
+	ExtraMounts []config.Mount
+	// ExtraPortMappings are published on the host
+	ExtraPortMappings []config.PortMapping
+	// Resources limit the node container, see resourceArgs
+	Resources config.NodeResources
+	// Sysctls (name to value) are set in the node container
+	Sysctls map[string]string
+	// ExtraDevices are exposed in the node container
+	ExtraDevices []config.Device
+	// Tmpfs (container path to mount options) are mounted in the node container
+	Tmpfs map[string]string
+	// ImageStore is the volume backing the node image store, if set, see
+	// imageStoreArgs
+	ImageStore string
+	// DataPath is where the node data volume is mounted, DataVolumePath if
+	// empty
+	DataPath string
+	// Env (of the form "KEY=value") is set in the node container
+	Env []string
+	// ExtraLabels (of the form "key=value") are applied to the node container
+	ExtraLabels []string
+}
+
+// args returns the docker run arguments for the options, other than the
+// data volume mounted by runNode
+func (o *NodeOptions) args() ([]string, error) {
+	args, err := extraArgs(o.ExtraMounts, o.ExtraPortMappings)
+	if err != nil {
+		return nil, err
+	}
+	limitArgs, err := resourceArgs(o.Resources)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, limitArgs...)
+	args = append(args, sysctlArgs(o.Sysctls)...)
+	args = append(args, deviceArgs(o.ExtraDevices)...)
+	args = append(args, tmpfsArgs(o.Tmpfs)...)
+	storeArgs, err := imageStoreArgs(o.ImageStore)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, storeArgs...)
+	args = append(args, envArgs(o.Env)...)
+	return append(args, labelArgs(o.ExtraLabels)...), nil
+}
+
 // CreateControlPlaneNode creates a contol-plane node
-// and gets ready for exposing the the API server
-func CreateControlPlaneNode(name, image, clusterLabel string) (node *Node, err error) {
-	// gets a random host port for the API server
-	port, err := getPort()
+// and gets ready for exposing the the API server on
+// apiServerAddress:apiServerPort, see apiServerArgs
+// The node container is configured with opts, see NodeOptions
+// The node is attached to network, see Network
+func CreateControlPlaneNode(name, image, clusterLabel string, network Network, apiServerAddress string, apiServerPort int32, opts NodeOptions) (node *Node, err error) {
+	publishArgs, port, err := apiServerArgs(apiServerAddress, apiServerPort)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get port for API server")
+		return nil, err
 	}
 
-	node, err = createNode(name, image, clusterLabel,
-		// publish selected port for the API server
-		"--expose", fmt.Sprintf("%d", port),
-		"-p", fmt.Sprintf("%d:%d", port, kubeadm.APIServerPort),
-	)
+	args, err := opts.args()
+	if err != nil {
+		return nil, err
+	}
+	node, err = createNode(name, image, clusterLabel, network, config.ControlPlaneRole, opts.DataPath, append(args, publishArgs...)...)
 	if err != nil {
 		return node, err
 	}
@@ -68,18 +157,498 @@ func CreateControlPlaneNode(name, image, clusterLabel string) (node *Node, err e
 }
 
 // CreateWorkerNode creates a worker node
-func CreateWorkerNode(name, image, clusterLabel string) (node *Node, err error) {
-	node, err = createNode(name, image, clusterLabel)
+// The node container is configured with opts, see NodeOptions
+// The node is attached to network, see Network
+func CreateWorkerNode(name, image, clusterLabel string, network Network, opts NodeOptions) (node *Node, err error) {
+	args, err := opts.args()
+	if err != nil {
+		return nil, err
+	}
+	node, err = createNode(name, image, clusterLabel, network, config.WorkerRole, opts.DataPath, args...)
+	if err != nil {
+		return node, err
+	}
+	return node, nil
+}
+
+// CreateWindowsWorkerNode creates a Windows worker node, running the Windows
+// node image in a process isolated Windows container. Unlike the Linux nodes
+// the node image entrypoint starts the container runtime right away, and
+// there is no systemd to signal, see SignalStart
+// Any extraPortMappings are published on the host
+// Any env (of the form "KEY=value") is set in the node container
+// Any extraLabels (of the form "key=value") are applied to the node container
+// The node is attached to network, see Network, Windows containers do not
+// support IPv6 addresses
+func CreateWindowsWorkerNode(name, image, clusterLabel string, network Network, extraPortMappings []config.PortMapping, env []string, extraLabels ...string) (node *Node, err error) {
+	args := []string{
+		"-d", // run the container detached
+		// the kubelet manages the host containers, which requires host
+		// process isolation rather than a Hyper-V utility VM
+		"--isolation", "process",
+		"--hostname", name, // make hostname match container name
+		"--name", name, // ... and set the container name
+		// label the node with the cluster ID
+		"--label", clusterLabel,
+		// label the node with the role ID and the OS
+		"--label", fmt.Sprintf("%s=%s", consts.ClusterRoleKey, config.WorkerRole),
+		"--label", fmt.Sprintf("%s=%s", consts.NodeOSKey, config.WindowsOS),
+	}
+	args = append(args, dnsArgs(network.DNS)...)
+	if network.Name != "" {
+		args = append(args, "--network", network.Name)
+		if network.IP != "" {
+			args = append(args, "--ip", network.IP)
+		}
+	}
+	args = append(args, portMappingArgs(extraPortMappings)...)
+	args = append(args, envArgs(env)...)
+	args = append(args, labelArgs(extraLabels)...)
+
+	// the node image entrypoint runs the container runtime
+	id, err := docker.Run(image, args, nil)
+	if id != "" {
+		node = &Node{
+			nameOrID: name,
+			nodeCache: nodeCache{
+				os: string(config.WindowsOS),
+			},
+		}
+	}
+	if err != nil {
+		return node, errors.Wrap(err, "docker run error")
+	}
+	return node, nil
+}
+
+// CreateExternalEtcdNode creates a node hosting a member of an external
+// etcd cluster, this is not a Kubernetes node
+// Any extraMounts are mounted into the node container
+// Any extraPortMappings are published on the host
+// Any tmpfs (container path to mount options) are mounted in the node container
+// Any env (of the form "KEY=value") is set in the node container
+// Any extraLabels (of the form "key=value") are applied to the node container
+// The node is attached to network, see Network
+func CreateExternalEtcdNode(name, image, clusterLabel string, network Network, extraMounts []config.Mount, extraPortMappings []config.PortMapping, tmpfs map[string]string, env []string, extraLabels ...string) (node *Node, err error) {
+	args, err := extraArgs(extraMounts, extraPortMappings)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, tmpfsArgs(tmpfs)...)
+	args = append(args, envArgs(env)...)
+	node, err = createNode(name, image, clusterLabel, network, config.ExternalEtcdRole, DataVolumePath, append(args, labelArgs(extraLabels)...)...)
 	if err != nil {
 		return node, err
 	}
 	return node, nil
 }
 
+// CreateExternalLoadBalancerNode creates a node hosting an external load
+// balancer for the API server, running the load balancer image, and publishes
+// the API server port on apiServerAddress:apiServerPort, see apiServerArgs.
+// This is not a Kubernetes node.
+// Unlike the other nodes the container is created but not started, as the
+// load balancer config must be copied to the node first, see Start
+// Any extraLabels (of the form "key=value") are applied to the node container
+// The node is attached to network, see Network
+func CreateExternalLoadBalancerNode(name, image, clusterLabel string, network Network, apiServerAddress string, apiServerPort int32, extraLabels ...string) (node *Node, err error) {
+	return newLoadBalancerNode(docker.Create, name, image, clusterLabel, network, apiServerAddress, apiServerPort, extraLabels...)
+}
+
+// newLoadBalancerNode creates the load balancer node container with either
+// docker.Create or docker.Run
+func newLoadBalancerNode(
+	newContainer func(image string, args []string, containerArgs []string) (string, error),
+	name, image, clusterLabel string, network Network, apiServerAddress string, apiServerPort int32, extraLabels ...string,
+) (node *Node, err error) {
+	publishArgs, port, err := apiServerArgs(apiServerAddress, apiServerPort)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{
+		"--hostname", name, // make hostname match container name
+		"--name", name, // ... and set the container name
+		// label the node with the cluster ID
+		"--label", clusterLabel,
+		// label the node with the role ID
+		"--label", fmt.Sprintf("%s=%s", consts.ClusterRoleKey, config.ExternalLoadBalancerRole),
+	}
+	netArgs, err := networkArgs(network)
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, netArgs...)
+	args = append(args, publishArgs...)
+	args = append(args, labelArgs(extraLabels)...)
+
+	// the image entrypoint and command run the load balancer
+	id, err := newContainer(image, args, nil)
+	if id != "" {
+		node = &Node{
+			nameOrID: name,
+			ports:    map[int]int{kubeadm.APIServerPort: port},
+		}
+	}
+	if err != nil {
+		return node, errors.Wrap(err, "failed to create load balancer container")
+	}
+	return node, nil
+}
+
+// Network describes the docker network a node container is attached to
+type Network struct {
+	// Name is the name of the network, the default bridge network if empty
+	Name string
+	// IP is the static IPv4 address of the node in the network, if any
+	IP string
+	// IPv6 is the static IPv6 address of the node in the network, if any
+	IPv6 string
+	// DNS configures the DNS resolver of the node, docker derives it from
+	// the host by default
+	DNS config.DNS
+}
+
+// networkArgs returns the docker run arguments for attaching the node to
+// network, the default bridge network is used if the name is empty, and for
+// configuring the node DNS resolver. IPv6 is enabled and forwarded in the node
+// if the network is IPv6 enabled
+func networkArgs(network Network) ([]string, error) {
+	args := dnsArgs(network.DNS)
+	if network.Name == "" {
+		return args, nil
+	}
+	args = append(args, "--network", network.Name)
+	if network.IP != "" {
+		args = append(args, "--ip", network.IP)
+	}
+	if network.IPv6 != "" {
+		args = append(args, "--ip6", network.IPv6)
+	}
+	subnets, err := docker.NetworkSubnets(network.Name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to inspect network %s", network.Name)
+	}
+	ipv6 := false
+	for _, subnet := range subnets {
+		ipv6 = ipv6 || subnet.IPv6()
+	}
+	if ipv6 {
+		args = append(args,
+			"--sysctl", "net.ipv6.conf.all.disable_ipv6=0",
+			"--sysctl", "net.ipv6.conf.all.forwarding=1",
+		)
+	}
+	return args, nil
+}
+
+// dnsArgs returns the docker run arguments for configuring the node DNS resolver
+func dnsArgs(dns config.DNS) []string {
+	args := []string{}
+	for _, nameserver := range dns.Nameservers {
+		args = append(args, "--dns", nameserver)
+	}
+	for _, search := range dns.Search {
+		args = append(args, "--dns-search", search)
+	}
+	for _, option := range dns.Options {
+		args = append(args, "--dns-option", option)
+	}
+	return args
+}
+
+// resourceArgs returns the docker run arguments for limiting the node
+// container to resources, the swap limit is the memory limit so that the
+// node can not exceed it, for pinning it to host CPUs and NUMA nodes, and
+// for passing through the host GPUs
+func resourceArgs(resources config.NodeResources) ([]string, error) {
+	args := []string{}
+	if resources.CPUs != "" {
+		cpus, err := resource.ParseQuantity(resources.CPUs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cpus %q", resources.CPUs)
+		}
+		args = append(args, "--cpus", strconv.FormatFloat(float64(cpus.MilliValue())/1000, 'f', -1, 64))
+	}
+	if resources.Memory != "" {
+		memory, err := resource.ParseQuantity(resources.Memory)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid memory %q", resources.Memory)
+		}
+		args = append(args,
+			"--memory", fmt.Sprintf("%d", memory.Value()),
+			"--memory-swap", fmt.Sprintf("%d", memory.Value()),
+		)
+	}
+	if resources.PIDs > 0 {
+		args = append(args, "--pids-limit", fmt.Sprintf("%d", resources.PIDs))
+	}
+	if resources.CPUSet != "" {
+		args = append(args, "--cpuset-cpus", resources.CPUSet)
+	}
+	if resources.NUMANodes != "" {
+		args = append(args, "--cpuset-mems", resources.NUMANodes)
+	}
+	if resources.GPUs != "" {
+		// the --gpus value is parsed as CSV, so a list of devices is quoted
+		gpus := resources.GPUs
+		if strings.Contains(gpus, ",") {
+			gpus = fmt.Sprintf("%q", gpus)
+		}
+		args = append(args,
+			"--gpus", gpus,
+			// the NVIDIA runtime hook injects only some of the driver
+			// libraries by default, the workloads of the node may need any
+			"--env", "NVIDIA_DRIVER_CAPABILITIES=all",
+		)
+	}
+	return args, nil
+}
+
+// sysctlArgs returns the docker run arguments for setting the sysctls in the
+// node container, sorted for predictable results
+func sysctlArgs(sysctls map[string]string) []string {
+	names := make([]string, 0, len(sysctls))
+	for name := range sysctls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	args := []string{}
+	for _, name := range names {
+		args = append(args, "--sysctl", fmt.Sprintf("%s=%s", name, sysctls[name]))
+	}
+	return args
+}
+
+// deviceArgs returns the docker run arguments for exposing the devices in
+// the node container
+func deviceArgs(devices []config.Device) []string {
+	args := []string{}
+	for _, d := range devices {
+		device := d.HostPath
+		if d.ContainerPath != "" || d.CgroupPermissions != "" {
+			containerPath := d.ContainerPath
+			if containerPath == "" {
+				containerPath = d.HostPath
+			}
+			device += ":" + containerPath
+			if d.CgroupPermissions != "" {
+				device += ":" + d.CgroupPermissions
+			}
+		}
+		args = append(args, "--device", device)
+	}
+	return args
+}
+
+// tmpfsArgs returns the docker run arguments for mounting the tmpfs, in
+// order of their paths
+func tmpfsArgs(tmpfs map[string]string) []string {
+	paths := make([]string, 0, len(tmpfs))
+	for path := range tmpfs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	args := []string{}
+	for _, path := range paths {
+		args = append(args, "--tmpfs", fmt.Sprintf("%s:%s", path, tmpfs[path]))
+	}
+	return args
+}
+
+func envArgs(env []string) []string {
+	args := []string{}
+	for _, e := range env {
+		args = append(args, "--env", e)
+	}
+	return args
+}
+
+func labelArgs(labels []string) []string {
+	args := []string{}
+	for _, label := range labels {
+		args = append(args, "--label", label)
+	}
+	return args
+}
+
+// extraArgs returns the docker run arguments for mounting extraMounts and
+// publishing extraPortMappings
+func extraArgs(extraMounts []config.Mount, extraPortMappings []config.PortMapping) ([]string, error) {
+	args, err := mountArgs(extraMounts)
+	if err != nil {
+		return nil, err
+	}
+	return append(args, portMappingArgs(extraPortMappings)...), nil
+}
+
+// portMappingArgs returns the docker run arguments for publishing extraPortMappings
+func portMappingArgs(extraPortMappings []config.PortMapping) []string {
+	args := []string{}
+	for _, pm := range extraPortMappings {
+		// docker uses a random host port if not set
+		hostPort := ""
+		if pm.HostPort != 0 {
+			hostPort = fmt.Sprintf("%d", pm.HostPort)
+		}
+		listenAddress := pm.ListenAddress
+		if strings.Contains(listenAddress, ":") {
+			listenAddress = "[" + listenAddress + "]"
+		}
+		protocol := pm.Protocol
+		if protocol == "" {
+			protocol = config.PortMappingProtocolTCP
+		}
+		args = append(args, "--publish", fmt.Sprintf(
+			"%s:%s:%d/%s", listenAddress, hostPort, pm.ContainerPort, strings.ToLower(string(protocol)),
+		))
+	}
+	return args
+}
+
+// mountArgs returns the docker run arguments for mounting extraMounts
+func mountArgs(extraMounts []config.Mount) ([]string, error) {
+	args := []string{}
+	for _, m := range extraMounts {
+		hostPath, err := filepath.Abs(m.HostPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to resolve host path %q", m.HostPath)
+		}
+		options := []string{}
+		if m.ReadOnly {
+			options = append(options, "ro")
+		}
+		switch m.Propagation {
+		case config.MountPropagationHostToContainer:
+			options = append(options, "rslave")
+		case config.MountPropagationBidirectional:
+			options = append(options, "rshared")
+		}
+		volume := fmt.Sprintf("%s:%s", hostPath, m.ContainerPath)
+		if len(options) > 0 {
+			volume += ":" + strings.Join(options, ",")
+		}
+		args = append(args, "--volume", volume)
+	}
+	return args, nil
+}
+
+// RecreateNode creates a node container from an image previously committed
+// from an existing node (see Node.Commit), preserving the node's role and
+// identity (e.g. machine-id) instead of initializing a fresh node
+// The node container is configured with opts as when it was created, see
+// NodeOptions, as these are not part of the committed image
+// The API server of control-plane and load balancer nodes is published on
+// apiServerAddress:apiServerPort, see apiServerArgs
+// The node is attached to network, see Network
+func RecreateNode(name, image, clusterLabel string, network Network, role config.NodeRole, apiServerAddress string, apiServerPort int32, opts NodeOptions) (node *Node, err error) {
+	// the load balancer config is part of the committed image, so the
+	// load balancer can be started right away
+	if role == config.ExternalLoadBalancerRole {
+		runDetached := func(image string, args []string, containerArgs []string) (string, error) {
+			return docker.Run(image, append([]string{"-d"}, args...), containerArgs)
+		}
+		return newLoadBalancerNode(runDetached, name, image, clusterLabel, network, apiServerAddress, apiServerPort)
+	}
+	args, err := opts.args()
+	if err != nil {
+		return nil, err
+	}
+	if role != config.ControlPlaneRole {
+		return runNode(name, image, clusterLabel, network, role, opts.DataPath, args...)
+	}
+
+	publishArgs, port, err := apiServerArgs(apiServerAddress, apiServerPort)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err = runNode(name, image, clusterLabel, network, role, opts.DataPath, append(args, publishArgs...)...)
+	if err != nil {
+		return node, err
+	}
+
+	// stores the port mapping into the node internal state
+	node.ports = map[int]int{kubeadm.APIServerPort: port}
+
+	return node, nil
+}
+
+// ImageStorePath is the path of the docker data directory on each node, the
+// image store, see imageStoreArgs
+const ImageStorePath = "/var/lib/docker"
+
+// imageStoreArgs returns the docker run arguments for backing the node image
+// store with the volume imageStore, if set, creating it if it does not exist.
+// The docker daemon of a node owns its data directory, so the volume may only
+// back one running node at a time
+func imageStoreArgs(imageStore string) ([]string, error) {
+	if imageStore == "" {
+		return nil, nil
+	}
+	users, err := exec.CombinedOutputLines(docker.Command(
+		"ps", "-a", "-q", "--filter", "volume="+imageStore,
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check the image store volume")
+	}
+	if len(users) > 0 {
+		return nil, fmt.Errorf("the image store volume %s is in use by container %s, it may only back one node at a time", imageStore, users[0])
+	}
+	// the volume is not labeled with the cluster, so that it is kept when
+	// the cluster is deleted
+	if err := docker.CreateVolume(imageStore, fmt.Sprintf("%s=true", consts.ImageStoreKey)); err != nil {
+		return nil, errors.Wrap(err, "failed to create the image store volume")
+	}
+	return []string{"-v", fmt.Sprintf("%s:%s", imageStore, ImageStorePath)}, nil
+}
+
+// DataVolumePath is the default path on each node where the node's data
+// volume is mounted, data stored in the volume (e.g. backing hostPath
+// PersistentVolumes) outlives the node container, see DataVolumeName
+const DataVolumePath = "/var/lib/kind-data"
+
+// DataVolumeName returns the name of the docker volume mounted at the data
+// path, see DataVolumePath, of the node container with the given name
+func DataVolumeName(name string) string {
+	return name + "-data"
+}
+
 // createNode `docker run`s the node image, note that due to
 // images/node/entrypoint being the entrypoint, this container will
 // effectively be paused until we call actuallyStartNode(...)
-func createNode(name, image, clusterLabel string, extraArgs ...string) (handle *Node, err error) {
+func createNode(name, image, clusterLabel string, network Network, role config.NodeRole, dataPath string, extraArgs ...string) (handle *Node, err error) {
+	handle, err = runNode(name, image, clusterLabel, network, role, dataPath, extraArgs...)
+	if err != nil {
+		return handle, err
+	}
+
+	// Deletes the machine-id embedded in the node image and regenerate a new one.
+	// This is necessary because both kubelet and other components like weave net
+	// use machine-id internally to distinguish nodes.
+	if err := handle.Command("rm", "-f", "/etc/machine-id").Run(); err != nil {
+		return handle, errors.Wrap(err, "machine-id-setup error")
+	}
+
+	if err := handle.Command("systemd-machine-id-setup").Run(); err != nil {
+		return handle, errors.Wrap(err, "machine-id-setup error")
+	}
+
+	return handle, nil
+}
+
+// runNode does the actual `docker run` for createNode and RecreateNode,
+// mounting the node data volume at dataPath, DataVolumePath if empty
+func runNode(name, image, clusterLabel string, network Network, role config.NodeRole, dataPath string, extraArgs ...string) (handle *Node, err error) {
+	if dataPath == "" {
+		dataPath = DataVolumePath
+	}
+	// ensure the node data volume exists, this re-uses the existing volume
+	// if a node with the same name previously existed and kept its volume
+	if err := docker.CreateVolume(DataVolumeName(name), clusterLabel); err != nil {
+		return nil, errors.Wrap(err, "failed to create node data volume")
+	}
+
 	runArgs := []string{
 		"-d", // run the container detached
 		// running containers in a container requires privileged
@@ -93,14 +662,25 @@ func createNode(name, image, clusterLabel string, extraArgs ...string) (handle *
 		"--tmpfs", "/run", // systemd wants a writable /run
 		// some k8s things want /lib/modules
 		"-v", "/lib/modules:/lib/modules:ro",
+		// persistent node data, see DataVolumePath
+		"-v", fmt.Sprintf("%s:%s", DataVolumeName(name), dataPath),
 		"--hostname", name, // make hostname match container name
 		"--name", name, // ... and set the container name
 		// label the node with the cluster ID
 		"--label", clusterLabel,
+		// label the node with the role ID
+		"--label", fmt.Sprintf("%s=%s", consts.ClusterRoleKey, role),
 		// explicitly set the entrypoint
 		"--entrypoint=/usr/local/bin/entrypoint",
 	}
 
+	// attach the node to the cluster network, if any
+	netArgs, err := networkArgs(network)
+	if err != nil {
+		return nil, err
+	}
+	runArgs = append(runArgs, netArgs...)
+
 	// adds node specific args
 	runArgs = append(runArgs, extraArgs...)
 
@@ -109,6 +689,11 @@ func createNode(name, image, clusterLabel string, extraArgs ...string) (handle *
 		// in systems that have userns-remap enabled on the docker daemon
 		runArgs = append(runArgs, "--userns=host")
 	}
+	if docker.CurrentProvider().Rootless() {
+		// systemd in the node manages the cgroups delegated to the runtime,
+		// which requires its own cgroup namespace
+		runArgs = append(runArgs, "--cgroupns=private")
+	}
 
 	id, err := docker.Run(
 		image,
@@ -131,16 +716,5 @@ func createNode(name, image, clusterLabel string, extraArgs ...string) (handle *
 		return handle, errors.Wrap(err, "docker run error")
 	}
 
-	// Deletes the machine-id embedded in the node image and regenerate a new one.
-	// This is necessary because both kubelet and other components like weave net
-	// use machine-id internally to distinguish nodes.
-	if err := handle.Command("rm", "-f", "/etc/machine-id").Run(); err != nil {
-		return handle, errors.Wrap(err, "machine-id-setup error")
-	}
-
-	if err := handle.Command("systemd-machine-id-setup").Run(); err != nil {
-		return handle, errors.Wrap(err, "machine-id-setup error")
-	}
-
 	return handle, nil
 }
@@ -18,22 +18,31 @@ package nodes
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/yaml"
 
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
 	"sigs.k8s.io/kind/pkg/docker"
 	"sigs.k8s.io/kind/pkg/exec"
+	logutil "sigs.k8s.io/kind/pkg/log"
 )
 
 // Node represents a handle to a kind node
@@ -63,14 +72,32 @@ func (n *Node) Command(command string, args ...string) exec.Cmd {
 	return n.Cmder().Command(command, args...)
 }
 
+// InteractiveCommand returns a command running command with args on the
+// node, reading the stdin set on the command, and attached to a TTY if tty is
+// set, e.g. for running a shell
+func (n *Node) InteractiveCommand(tty bool, command string, args ...string) exec.Cmd {
+	dockerArgs := []string{"exec", "--privileged", "-i"}
+	if tty {
+		dockerArgs = append(dockerArgs, "-t")
+	}
+	dockerArgs = append(dockerArgs, n.nameOrID, command)
+	return docker.Command(append(dockerArgs, args...)...)
+}
+
 // this is a seperate struct so we can clearly the whole thing at once
 // it contains lazily initialized fields
 // like node.nodeCache = nodeCache{}
 type nodeCache struct {
 	kubernetesVersion string
 	ip                string
+	ipv6              string
+	role              string
+	os                string
+	image             string
 	ports             map[int]int
 	containerCmder    exec.Cmder
+	// the labels of the cluster that claimed the pool node, see Claim
+	claim map[string]string
 }
 
 func (n *Node) String() string {
@@ -88,6 +115,24 @@ func (n *Node) CopyTo(source, dest string) error {
 	return docker.CopyTo(source, n.nameOrID, dest)
 }
 
+// CopyFrom copies the source file or dir on the node to dest on the host
+func (n *Node) CopyFrom(source, dest string) error {
+	return docker.CopyFrom(n.nameOrID, source, dest)
+}
+
+// FileExists returns true if the file or dir at path exists on the node,
+// the node does not need to be running
+func (n *Node) FileExists(path string) (bool, error) {
+	return docker.FileExists(n.nameOrID, path)
+}
+
+// Commit creates a new image named image from the node container's
+// filesystem, as in `docker commit`
+// Note that volumes such as /var/lib/docker are not included in the image
+func (n *Node) Commit(image string) error {
+	return docker.Commit(n.nameOrID, image)
+}
+
 // WaitForDocker waits for Docker to be ready on the node
 // it returns true on success, and false on a timeout
 func (n *Node) WaitForDocker(until time.Time) bool {
@@ -121,7 +166,7 @@ func (n *Node) LoadImages() {
 		"-name", "*.tar",
 		"-exec", "docker", "load", "-i", "{}", ";",
 	).Run(); err != nil {
-		log.Warningf("Failed to preload docker images: %v", err)
+		logutil.Warnf("Failed to preload docker images: %v", err)
 		return
 	}
 
@@ -147,10 +192,38 @@ func (n *Node) LoadImages() {
 		"/bin/bash", "-c",
 		`docker images --format='{{.Repository}}:{{.Tag}}' | grep -v amd64 | xargs -L 1 -I '{}' /bin/bash -c 'docker tag "{}" "$(echo "{}" | sed s/:/-amd64:/)"'`,
 	).Run(); err != nil {
-		log.Warningf("Failed to re-tag docker images: %v", err)
+		logutil.Warnf("Failed to re-tag docker images: %v", err)
 	}
 }
 
+// RemoveContainers removes all the containers of docker on the node, e.g.
+// the stopped containers of a previous node in a reused image store
+func (n *Node) RemoveContainers() error {
+	return n.Command("sh", "-c", "docker ps -a -q | xargs -r docker rm -f -v").Run()
+}
+
+// LoadImageArchive loads the images in the image archive read from r into
+// docker on the node, as in `docker load`
+func (n *Node) LoadImageArchive(r io.Reader) error {
+	cmd := n.Command("docker", "load")
+	cmd.SetStdin(r)
+	return cmd.Run()
+}
+
+// ImageID returns the ID of the image with the given name or ID in docker on
+// the node
+func (n *Node) ImageID(image string) (string, error) {
+	cmd := n.Command("docker", "image", "inspect", "-f", "{{.Id}}", image)
+	lines, err := exec.CombinedOutputLines(cmd)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to inspect image %s", image)
+	}
+	if len(lines) != 1 {
+		return "", fmt.Errorf("image ID should only be one line, got %d lines", len(lines))
+	}
+	return lines[0], nil
+}
+
 // FixMounts will correct mounts in the node container to meet the right
 // sharing and permissions for systemd and Docker / Kubernetes
 func (n *Node) FixMounts() error {
@@ -184,9 +257,98 @@ func (n *Node) FixMounts() error {
 	if err := n.Command("mount", "--make-shared", "/var/lib/docker").Run(); err != nil {
 		return err
 	}
+	// the kubelet reads the kernel messages, which are not readable in the
+	// user namespace of rootless runtimes, read the console instead then
+	if err := n.Command("sh", "-c", "test -r /dev/kmsg || ln -sf /dev/console /dev/kmsg").Run(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// fixDNSScript makes the docker embedded DNS server reachable from the pods.
+// On user-defined networks the node resolves names with the embedded DNS
+// server at 127.0.0.11, which is only reachable in the node network namespace,
+// so the pods using the node DNS config can not resolve names. The DNS
+// traffic to the node default gateway is redirected to the embedded DNS server
+// instead, also for traffic from the pods, and the node DNS config is updated
+// to use the gateway. This is a no-op for the default bridge network.
+const fixDNSScript = `set -e
+embedded_dns=127.0.0.11
+grep -q "nameserver ${embedded_dns}" /etc/resolv.conf || exit 0
+gateway=$(ip -4 route show default | cut -d' ' -f3)
+iptables-save \
+  | sed \
+    -e "s/-d ${embedded_dns}/-d ${gateway}/g" \
+    -e 's/-A OUTPUT \(.*\) -j DOCKER_OUTPUT/\0\n-A PREROUTING \1 -j DOCKER_OUTPUT/' \
+    -e "s/--to-source :53/--to-source ${gateway}:53/g" \
+  | iptables-restore
+cp /etc/resolv.conf /etc/resolv.conf.original
+sed -e "s/${embedded_dns}/${gateway}/g" /etc/resolv.conf.original > /etc/resolv.conf
+`
+
+// FixDNS makes the DNS servers of the node container reachable from the
+// pods, see fixDNSScript. This should be called every time the node
+// container is started, before SignalStart
+func (n *Node) FixDNS() error {
+	return n.Command("/bin/sh", "-c", fixDNSScript).Run()
+}
+
+// ProxyEnvKeys are the proxy environment variables set in the node
+// containers, both the upper and lower case variants are set as tools
+// differ on which one they read
+var ProxyEnvKeys = []string{
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY",
+	"http_proxy", "https_proxy", "no_proxy",
+}
+
+// ProxyEnv returns the proxy environment variables set in the node container,
+// of the form "KEY=value", see ProxyEnvKeys
+func (n *Node) ProxyEnv() ([]string, error) {
+	lines, err := docker.Inspect(n.nameOrID, "{{json .Config.Env}}")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get node environment")
+	}
+	if len(lines) != 1 {
+		return nil, fmt.Errorf("environment should only be one line, got %d lines", len(lines))
+	}
+	env := []string{}
+	if err := json.Unmarshal([]byte(strings.Trim(lines[0], "'")), &env); err != nil {
+		return nil, errors.Wrap(err, "failed to parse node environment")
+	}
+	proxyEnv := []string{}
+	for _, e := range env {
+		for _, key := range ProxyEnvKeys {
+			if strings.HasPrefix(e, key+"=") {
+				proxyEnv = append(proxyEnv, e)
+			}
+		}
+	}
+	return proxyEnv, nil
+}
+
+// proxyConfigPath is the systemd config drop-in setting the proxy environment
+// variables for all the services in the node, e.g. docker and the kubelet
+const proxyConfigPath = "/etc/systemd/system.conf.d/proxy.conf"
+
+// ConfigureProxy sets the proxy environment variables of the node container
+// for all the services in the node, as systemd does not pass its environment
+// to the services. This should be called before SignalStart
+func (n *Node) ConfigureProxy() error {
+	env, err := n.ProxyEnv()
+	if err != nil {
+		return err
+	}
+	if len(env) == 0 {
+		return nil
+	}
+	quoted := make([]string, 0, len(env))
+	for _, e := range env {
+		quoted = append(quoted, strconv.Quote(e))
+	}
+	contents := fmt.Sprintf("[Manager]\nDefaultEnvironment=%s\n", strings.Join(quoted, " "))
+	return n.WriteFile(proxyConfigPath, []byte(contents))
+}
+
 // KubeVersion returns the Kubernetes version installed on the node
 func (n *Node) KubeVersion() (version string, err error) {
 	// use the cached version first
@@ -195,6 +357,9 @@ func (n *Node) KubeVersion() (version string, err error) {
 	}
 	// grab kubernetes version from the node image
 	cmd := n.Command("cat", "/kind/version")
+	if n.IsWindows() {
+		cmd = n.Command("cmd", "/c", "type", windowsVersionPath)
+	}
 	lines, err := exec.CombinedOutputLines(cmd)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to get file")
@@ -206,6 +371,104 @@ func (n *Node) KubeVersion() (version string, err error) {
 	return n.nodeCache.kubernetesVersion, nil
 }
 
+// CPUs returns the host CPUs and NUMA nodes the node container runs on, as
+// lists, e.g. "0-3", and all the online CPUs of the host, which are the ones
+// the kubelet detects
+func (n *Node) CPUs() (cpus, numaNodes, hostCPUs string, err error) {
+	// the effective cpuset of the node cgroup, with cgroup v2 or v1
+	lines, err := exec.CombinedOutputLines(n.Command(
+		"/bin/sh", "-c",
+		"{ cat /sys/fs/cgroup/cpuset.cpus.effective /sys/fs/cgroup/cpuset.mems.effective 2>/dev/null"+
+			" || cat /sys/fs/cgroup/cpuset/cpuset.effective_cpus /sys/fs/cgroup/cpuset/cpuset.effective_mems; }"+
+			" && cat /sys/devices/system/cpu/online",
+	))
+	if err != nil {
+		return "", "", "", errors.Wrap(err, "failed to get node cpuset")
+	}
+	if len(lines) != 3 {
+		return "", "", "", fmt.Errorf("cpuset should be three lines, got %d lines", len(lines))
+	}
+	return strings.TrimSpace(lines[0]), strings.TrimSpace(lines[1]), strings.TrimSpace(lines[2]), nil
+}
+
+// WriteFile writes contents to the file dest on the node, replacing it
+func (n *Node) WriteFile(dest string, contents []byte) error {
+	cmd := n.Command("/bin/sh", "-c", fmt.Sprintf("mkdir -p \"$(dirname %[1]s)\" && cat > %[1]s", dest))
+	cmd.SetStdin(bytes.NewReader(contents))
+	return cmd.Run()
+}
+
+// ReadFile returns the contents of the file path on the node
+func (n *Node) ReadFile(path string) ([]byte, error) {
+	var buff bytes.Buffer
+	cmd := n.Command("cat", path)
+	cmd.SetStdout(&buff)
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", path)
+	}
+	return buff.Bytes(), nil
+}
+
+// completedTasksPath is the file on the node recording the completed tasks
+const completedTasksPath = "/kind/completed-tasks"
+
+// windowsCompletedTasksPath is the file recording the completed tasks on
+// Windows nodes, see completedTasksPath
+const windowsCompletedTasksPath = `C:\kind\completed-tasks`
+
+// RecordCompletedTask records that the task identified by key completed on the node
+func (n *Node) RecordCompletedTask(key string) error {
+	if n.IsWindows() {
+		return n.Command(
+			"powershell", "-Command",
+			fmt.Sprintf("Add-Content -Path %s -Value '%s'", windowsCompletedTasksPath, key),
+		).Run()
+	}
+	cmd := n.Command("/bin/sh", "-c", fmt.Sprintf("mkdir -p \"$(dirname %[1]s)\" && cat >> %[1]s", completedTasksPath))
+	cmd.SetStdin(strings.NewReader(key + "\n"))
+	return cmd.Run()
+}
+
+// CompletedTasks returns the keys of the tasks recorded as completed on the node
+func (n *Node) CompletedTasks() ([]string, error) {
+	// the file does not exist until the first task completes
+	cmd := n.Command("/bin/sh", "-c", fmt.Sprintf("cat %s 2>/dev/null || true", completedTasksPath))
+	if n.IsWindows() {
+		cmd = n.Command(
+			"powershell", "-Command",
+			fmt.Sprintf("if (Test-Path %[1]s) { Get-Content %[1]s }", windowsCompletedTasksPath),
+		)
+	}
+	lines, err := exec.CombinedOutputLines(cmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get completed tasks")
+	}
+	return lines, nil
+}
+
+// State returns the state of the node container, e.g. "running" or "exited"
+func (n *Node) State() (string, error) {
+	lines, err := docker.Inspect(n.nameOrID, "{{.State.Status}}")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get node state")
+	}
+	if len(lines) != 1 {
+		return "", fmt.Errorf("state should only be one line, got %d lines", len(lines))
+	}
+	return strings.Trim(lines[0], "'"), nil
+}
+
+// KubeletHealthy returns true if the kubelet on the node reports healthy
+func (n *Node) KubeletHealthy() bool {
+	curl := "curl"
+	if n.IsWindows() {
+		curl = "curl.exe"
+	}
+	cmd := n.Command(curl, "-sSL", "--max-time", "5", "http://localhost:10248/healthz")
+	lines, err := exec.CombinedOutputLines(cmd)
+	return err == nil && len(lines) == 1 && lines[0] == "ok"
+}
+
 // IP returns the IP address of the node
 func (n *Node) IP() (ip string, err error) {
 	// use the cached version first
@@ -224,6 +487,171 @@ func (n *Node) IP() (ip string, err error) {
 	return n.nodeCache.ip, nil
 }
 
+// IPv6 returns the global IPv6 address of the node, this is empty for nodes
+// that are not attached to an IPv6 enabled network
+func (n *Node) IPv6() (ip string, err error) {
+	// use the cached version first
+	if n.nodeCache.ipv6 != "" {
+		return n.nodeCache.ipv6, nil
+	}
+	// retrive the IPv6 address of the node using docker inspect
+	lines, err := docker.Inspect(n.nameOrID, "{{range .NetworkSettings.Networks}}{{.GlobalIPv6Address}}{{end}}")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get IPv6 address")
+	}
+	if len(lines) != 1 {
+		return "", fmt.Errorf("IPv6 address should only be one line, got %d lines", len(lines))
+	}
+	n.nodeCache.ipv6 = strings.Trim(lines[0], "'")
+	return n.nodeCache.ipv6, nil
+}
+
+// Network returns the name of the docker network the node is attached to
+func (n *Node) Network() (network string, err error) {
+	lines, err := docker.Inspect(n.nameOrID, "{{range $name, $_ := .NetworkSettings.Networks}}{{$name}}{{end}}")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get network")
+	}
+	if len(lines) != 1 {
+		return "", fmt.Errorf("network should only be one line, got %d lines", len(lines))
+	}
+	return strings.Trim(lines[0], "'"), nil
+}
+
+// StaticAddresses returns the static IPv4 and IPv6 addresses the node was
+// assigned in its docker network, these are empty if the addresses were
+// allocated by docker, see Network
+func (n *Node) StaticAddresses() (ip, ipv6 string, err error) {
+	lines, err := docker.Inspect(n.nameOrID, "{{range .NetworkSettings.Networks}}{{with .IPAMConfig}}{{.IPv4Address}},{{.IPv6Address}}{{end}}{{end}}")
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to get static addresses")
+	}
+	if len(lines) != 1 {
+		return "", "", fmt.Errorf("static addresses should only be one line, got %d lines", len(lines))
+	}
+	addresses := strings.Split(strings.Trim(lines[0], "'"), ",")
+	if len(addresses) != 2 {
+		return "", "", nil
+	}
+	return addresses[0], addresses[1], nil
+}
+
+// DNS returns the DNS resolver settings of the node container, these are
+// empty unless set when creating the node, see Network
+func (n *Node) DNS() (config.DNS, error) {
+	lines, err := docker.Inspect(n.nameOrID, "{{json .HostConfig}}")
+	if err != nil {
+		return config.DNS{}, errors.Wrap(err, "failed to get node DNS config")
+	}
+	if len(lines) != 1 {
+		return config.DNS{}, fmt.Errorf("host config should only be one line, got %d lines", len(lines))
+	}
+	hostConfig := struct {
+		DNS        []string `json:"Dns"`
+		DNSSearch  []string `json:"DnsSearch"`
+		DNSOptions []string `json:"DnsOptions"`
+	}{}
+	if err := json.Unmarshal([]byte(strings.Trim(lines[0], "'")), &hostConfig); err != nil {
+		return config.DNS{}, errors.Wrap(err, "failed to parse node DNS config")
+	}
+	dns := config.DNS{}
+	if len(hostConfig.DNS) > 0 {
+		dns.Nameservers = hostConfig.DNS
+	}
+	if len(hostConfig.DNSSearch) > 0 {
+		dns.Search = hostConfig.DNSSearch
+	}
+	if len(hostConfig.DNSOptions) > 0 {
+		dns.Options = hostConfig.DNSOptions
+	}
+	return dns, nil
+}
+
+// Role returns the role of the node, as recorded in the node container
+// labels at creation time. Nodes created by older versions of kind may not
+// have this label, in which case the role will be empty
+func (n *Node) Role() (role string, err error) {
+	// use the cached version first
+	if n.nodeCache.role != "" {
+		return n.nodeCache.role, nil
+	}
+	n.nodeCache.role, err = n.Label(consts.ClusterRoleKey)
+	return n.nodeCache.role, err
+}
+
+// OS returns the operating system of the node, as recorded in the node
+// container labels at creation time, see consts.NodeOSKey
+func (n *Node) OS() (nodeOS string, err error) {
+	// use the cached version first
+	if n.nodeCache.os != "" {
+		return n.nodeCache.os, nil
+	}
+	nodeOS, err = n.Label(consts.NodeOSKey)
+	if err != nil {
+		return "", err
+	}
+	if nodeOS == "" {
+		nodeOS = string(config.LinuxOS)
+	}
+	n.nodeCache.os = nodeOS
+	return nodeOS, nil
+}
+
+// IsWindows returns true if the node runs Windows, see OS
+func (n *Node) IsWindows() bool {
+	nodeOS, err := n.OS()
+	return err == nil && nodeOS == string(config.WindowsOS)
+}
+
+// windowsVersionPath is the file recording the Kubernetes version in the
+// Windows node image, like /kind/version in the Linux node image
+const windowsVersionPath = `C:\kind\version`
+
+// Label returns the value of the node container label key, or "" if the
+// label is not set
+func (n *Node) Label(key string) (value string, err error) {
+	// retrive the label and the pool of the node using docker inspect
+	lines, err := docker.Inspect(n.nameOrID, fmt.Sprintf("{{index .Config.Labels %q}}\t{{index .Config.Labels %q}}", key, consts.PoolKey))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get node label %s", key)
+	}
+	if len(lines) != 1 {
+		return "", fmt.Errorf("label should only be one line, got %d lines", len(lines))
+	}
+	parts := strings.SplitN(strings.Trim(lines[0], "'"), "\t", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return parts[0], nil
+	}
+	// the labels of the cluster are recorded on claimed pool nodes
+	if n.nodeCache.claim == nil {
+		if n.nodeCache.claim, err = n.claimLabels(); err != nil {
+			return "", err
+		}
+	}
+	if claimed, ok := n.nodeCache.claim[key]; ok {
+		return claimed, nil
+	}
+	return parts[0], nil
+}
+
+// Image returns the image the node container was created from
+func (n *Node) Image() (image string, err error) {
+	// use the cached version first
+	if n.nodeCache.image != "" {
+		return n.nodeCache.image, nil
+	}
+	// retrive the image using docker inspect
+	lines, err := docker.Inspect(n.nameOrID, "{{.Config.Image}}")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get node image")
+	}
+	if len(lines) != 1 {
+		return "", fmt.Errorf("image should only be one line, got %d lines", len(lines))
+	}
+	n.nodeCache.image = strings.Trim(lines[0], "'")
+	return n.nodeCache.image, nil
+}
+
 // Ports returns a specific port mapping for the node
 // Node by convention use well known ports internally, while random port
 // are used for making the `kind` cluster accessible from the host machine
@@ -261,32 +689,383 @@ var serverAddressRE = regexp.MustCompile(`^(\s+server:) https://.*:\d+$`)
 // WriteKubeConfig writes a fixed KUBECONFIG to dest
 // this should only be called on a control plane node
 // While copyng to the host machine the control plane address
-// is replaced with local host and the control plane port with
-// a randomly generated port reserved during node creation.
-func (n *Node) WriteKubeConfig(dest string, hostPort int) error {
+// is replaced with hostAddress, or the published host if it is empty or
+// unspecified, see docker.PublishedHost,
+// and the control plane port with hostPort, the port reserved during node creation.
+// A cluster and a context are added for each of extraHosts, see addKubeConfigHosts
+func (n *Node) WriteKubeConfig(dest, hostAddress string, hostPort int, extraHosts ...string) error {
+	// fix the config file, swapping out the server for the forwarded localhost:port
+	kubeConfig, err := n.KubeConfig("https://" + net.JoinHostPort(kubeConfigHost(hostAddress), strconv.Itoa(hostPort)))
+	if err != nil {
+		return err
+	}
+
+	// create the directory to contain the KUBECONFIG file.
+	// 0755 is taken from client-go's config handling logic: https://github.com/kubernetes/client-go/blob/5d107d4ebc00ee0ea606ad7e39fd6ce4b0d9bf9e/tools/clientcmd/loader.go#L412
+	err = os.MkdirAll(filepath.Dir(dest), 0755)
+	if err != nil {
+		return errors.Wrap(err, "failed to create kubeconfig output directory")
+	}
+
+	if len(extraHosts) > 0 {
+		kubeConfig, err = addKubeConfigHosts(kubeConfig, hostPort, extraHosts)
+		if err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(dest, kubeConfig, 0600)
+}
+
+// KubeConfig returns the admin KUBECONFIG of the cluster with the API server
+// address replaced by server, e.g. https://172.17.0.2:6443
+// this should only be called on a control plane node
+func (n *Node) KubeConfig(server string) ([]byte, error) {
 	cmd := n.Command("cat", "/etc/kubernetes/admin.conf")
 	lines, err := exec.CombinedOutputLines(cmd)
 	if err != nil {
-		return errors.Wrap(err, "failed to get kubeconfig from node")
+		return nil, errors.Wrap(err, "failed to get kubeconfig from node")
 	}
 
-	// fix the config file, swapping out the server for the forwarded localhost:port
 	var buff bytes.Buffer
 	for _, line := range lines {
 		match := serverAddressRE.FindStringSubmatch(line)
 		if len(match) > 1 {
-			line = fmt.Sprintf("%s https://localhost:%d", match[1], hostPort)
+			line = fmt.Sprintf("%s %s", match[1], server)
 		}
 		buff.WriteString(line)
 		buff.WriteString("\n")
 	}
+	return buff.Bytes(), nil
+}
 
-	// create the directory to contain the KUBECONFIG file.
-	// 0755 is taken from client-go's config handling logic: https://github.com/kubernetes/client-go/blob/5d107d4ebc00ee0ea606ad7e39fd6ce4b0d9bf9e/tools/clientcmd/loader.go#L412
-	err = os.MkdirAll(filepath.Dir(dest), 0755)
+// addKubeConfigHosts adds a cluster and a context to kubeConfig for each of
+// hosts, reaching the API server at the host and hostPort, these are named
+// after the cluster and context of kubeConfig with the host appended.
+// Wildcard hosts are skipped, and the current context is not changed
+func addKubeConfigHosts(kubeConfig []byte, hostPort int, hosts []string) ([]byte, error) {
+	cfg := map[string]interface{}{}
+	if err := yaml.Unmarshal(kubeConfig, &cfg); err != nil {
+		return nil, errors.Wrap(err, "failed to parse kubeconfig")
+	}
+	clusters, _ := cfg["clusters"].([]interface{})
+	contexts, _ := cfg["contexts"].([]interface{})
+	if len(clusters) != 1 || len(contexts) != 1 {
+		return nil, fmt.Errorf("kubeconfig should have one cluster and one context, got %d and %d", len(clusters), len(contexts))
+	}
+	cluster, _ := clusters[0].(map[string]interface{})
+	context, _ := contexts[0].(map[string]interface{})
+	clusterConfig, _ := cluster["cluster"].(map[string]interface{})
+	contextConfig, _ := context["context"].(map[string]interface{})
+	if clusterConfig == nil || contextConfig == nil {
+		return nil, fmt.Errorf("failed to parse kubeconfig cluster and context")
+	}
+
+	for _, host := range hosts {
+		if strings.HasPrefix(host, "*.") {
+			continue
+		}
+		clusterName := fmt.Sprintf("%v-%s", cluster["name"], host)
+		hostClusterConfig := map[string]interface{}{}
+		for key, value := range clusterConfig {
+			hostClusterConfig[key] = value
+		}
+		hostClusterConfig["server"] = "https://" + net.JoinHostPort(host, strconv.Itoa(hostPort))
+		clusters = append(clusters, map[string]interface{}{
+			"name":    clusterName,
+			"cluster": hostClusterConfig,
+		})
+		hostContextConfig := map[string]interface{}{}
+		for key, value := range contextConfig {
+			hostContextConfig[key] = value
+		}
+		hostContextConfig["cluster"] = clusterName
+		contexts = append(contexts, map[string]interface{}{
+			"name":    fmt.Sprintf("%v-%s", context["name"], host),
+			"context": hostContextConfig,
+		})
+	}
+	cfg["clusters"] = clusters
+	cfg["contexts"] = contexts
+	return yaml.Marshal(cfg)
+}
+
+// kubeConfigHost returns the host to reach the API server published on
+// hostAddress at, this is the published host if the address is empty or
+// unspecified, localhost unless the docker daemon is remote
+func kubeConfigHost(hostAddress string) string {
+	if ip := net.ParseIP(hostAddress); ip == nil || ip.IsUnspecified() {
+		return docker.PublishedHost()
+	}
+	return hostAddress
+}
+
+// ExtraMounts returns the host paths mounted into the node container,
+// other than the ones mounted by kind for all the nodes, see config.Node
+func (n *Node) ExtraMounts() ([]config.Mount, error) {
+	lines, err := docker.Inspect(n.nameOrID, "{{json .Mounts}}")
 	if err != nil {
-		return errors.Wrap(err, "failed to create kubeconfig output directory")
+		return nil, errors.Wrap(err, "failed to get node mounts")
+	}
+	if len(lines) != 1 {
+		return nil, fmt.Errorf("mounts should only be one line, got %d lines", len(lines))
+	}
+	mounts := []struct {
+		Type        string
+		Source      string
+		Destination string
+		RW          bool
+		Propagation string
+	}{}
+	if err := json.Unmarshal([]byte(strings.Trim(lines[0], "'")), &mounts); err != nil {
+		return nil, errors.Wrap(err, "failed to parse node mounts")
+	}
+
+	extraMounts := []config.Mount{}
+	for _, m := range mounts {
+		// the data volume is a volume, and /lib/modules is mounted on all nodes
+		if m.Type != "bind" || m.Destination == "/lib/modules" {
+			continue
+		}
+		mount := config.Mount{
+			ContainerPath: m.Destination,
+			HostPath:      m.Source,
+			ReadOnly:      !m.RW,
+		}
+		switch m.Propagation {
+		case "rslave":
+			mount.Propagation = config.MountPropagationHostToContainer
+		case "rshared":
+			mount.Propagation = config.MountPropagationBidirectional
+		}
+		extraMounts = append(extraMounts, mount)
+	}
+	return extraMounts, nil
+}
+
+// ExtraDevices returns the host devices exposed in the node container, see
+// config.Node
+func (n *Node) ExtraDevices() ([]config.Device, error) {
+	lines, err := docker.Inspect(n.nameOrID, "{{json .HostConfig.Devices}}")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get node devices")
+	}
+	if len(lines) != 1 {
+		return nil, fmt.Errorf("devices should only be one line, got %d lines", len(lines))
+	}
+	devices := []struct {
+		PathOnHost        string
+		PathInContainer   string
+		CgroupPermissions string
+	}{}
+	if err := json.Unmarshal([]byte(strings.Trim(lines[0], "'")), &devices); err != nil {
+		return nil, errors.Wrap(err, "failed to parse node devices")
+	}
+
+	extraDevices := []config.Device{}
+	for _, d := range devices {
+		extraDevices = append(extraDevices, config.Device{
+			HostPath:          d.PathOnHost,
+			ContainerPath:     d.PathInContainer,
+			CgroupPermissions: d.CgroupPermissions,
+		})
+	}
+	return extraDevices, nil
+}
+
+// ExtraPortMappings returns the port mappings requested for the node
+// container, other than the API server port published by kind, see config.Node
+func (n *Node) ExtraPortMappings() ([]config.PortMapping, error) {
+	portMappings, err := n.portMappings()
+	if err != nil {
+		return nil, err
+	}
+	role, err := n.Role()
+	if err != nil {
+		return nil, err
+	}
+	extraPortMappings := []config.PortMapping{}
+	for _, pm := range portMappings {
+		// the API server port is published on the control-plane and load balancer
+		if pm.ContainerPort == kubeadm.APIServerPort &&
+			(role == string(config.ControlPlaneRole) || role == string(config.ExternalLoadBalancerRole)) {
+			continue
+		}
+		extraPortMappings = append(extraPortMappings, pm)
+	}
+	return extraPortMappings, nil
+}
+
+// APIServerBinding returns the host address and port the API server port of
+// the node is published on, as requested when creating the node container.
+// The address is empty if the port is published on all the host interfaces,
+// and both are zero valued if the port is not published at all
+func (n *Node) APIServerBinding() (address string, port int32, err error) {
+	portMappings, err := n.portMappings()
+	if err != nil {
+		return "", 0, err
+	}
+	for _, pm := range portMappings {
+		if pm.ContainerPort == kubeadm.APIServerPort && pm.Protocol == config.PortMappingProtocolTCP {
+			return pm.ListenAddress, pm.HostPort, nil
+		}
+	}
+	return "", 0, nil
+}
+
+// portMappings returns all the port mappings requested for the node container
+func (n *Node) portMappings() ([]config.PortMapping, error) {
+	lines, err := docker.Inspect(n.nameOrID, "{{json .HostConfig.PortBindings}}")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get node port bindings")
+	}
+	if len(lines) != 1 {
+		return nil, fmt.Errorf("port bindings should only be one line, got %d lines", len(lines))
+	}
+	bindings := map[string][]struct {
+		HostIP   string `json:"HostIp"`
+		HostPort string
+	}{}
+	if err := json.Unmarshal([]byte(strings.Trim(lines[0], "'")), &bindings); err != nil {
+		return nil, errors.Wrap(err, "failed to parse node port bindings")
+	}
+
+	portMappings := []config.PortMapping{}
+	for port, portBindings := range bindings {
+		// the port is of the form <port>/<protocol>
+		parts := strings.Split(port, "/")
+		containerPort, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid port binding %q", port)
+		}
+		protocol := config.PortMappingProtocolTCP
+		if len(parts) > 1 {
+			protocol = config.PortMappingProtocol(strings.ToUpper(parts[1]))
+		}
+		for _, binding := range portBindings {
+			pm := config.PortMapping{
+				ContainerPort: int32(containerPort),
+				ListenAddress: binding.HostIP,
+				Protocol:      protocol,
+			}
+			if binding.HostPort != "" {
+				hostPort, err := strconv.Atoi(binding.HostPort)
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid host port for port binding %q", port)
+				}
+				pm.HostPort = int32(hostPort)
+			}
+			portMappings = append(portMappings, pm)
+		}
+	}
+	// sort for predictable results, the bindings are a map
+	sort.Slice(portMappings, func(i, j int) bool {
+		return portMappings[i].ContainerPort < portMappings[j].ContainerPort
+	})
+	return portMappings, nil
+}
+
+// Options returns the settings of the node container as requested when
+// creating it, see NodeOptions. The extra labels are not returned, as the
+// labels of the node container are kept when committing it, see Commit
+func (n *Node) Options() (NodeOptions, error) {
+	opts := NodeOptions{}
+	lines, err := docker.Inspect(n.nameOrID, "{{json .HostConfig}}")
+	if err != nil {
+		return opts, errors.Wrap(err, "failed to get node host config")
+	}
+	if len(lines) != 1 {
+		return opts, fmt.Errorf("host config should only be one line, got %d lines", len(lines))
+	}
+	hostConfig := struct {
+		NanoCpus       int64
+		Memory         int64
+		PidsLimit      *int64
+		CpusetCpus     string
+		CpusetMems     string
+		DeviceRequests []struct {
+			Count     int
+			DeviceIDs []string
+		}
+		Sysctls map[string]string
+		Tmpfs   map[string]string
+	}{}
+	if err := json.Unmarshal([]byte(strings.Trim(lines[0], "'")), &hostConfig); err != nil {
+		return opts, errors.Wrap(err, "failed to parse node host config")
+	}
+
+	// the resources in the format of config.NodeResources, see resourceArgs
+	if hostConfig.NanoCpus > 0 {
+		opts.Resources.CPUs = resource.NewMilliQuantity(hostConfig.NanoCpus/1000000, resource.DecimalSI).String()
+	}
+	if hostConfig.Memory > 0 {
+		opts.Resources.Memory = resource.NewQuantity(hostConfig.Memory, resource.BinarySI).String()
+	}
+	if hostConfig.PidsLimit != nil && *hostConfig.PidsLimit > 0 {
+		opts.Resources.PIDs = *hostConfig.PidsLimit
+	}
+	opts.Resources.CPUSet = hostConfig.CpusetCpus
+	opts.Resources.NUMANodes = hostConfig.CpusetMems
+	for _, r := range hostConfig.DeviceRequests {
+		switch {
+		case len(r.DeviceIDs) > 0:
+			opts.Resources.GPUs = "device=" + strings.Join(r.DeviceIDs, ",")
+		case r.Count < 0:
+			opts.Resources.GPUs = "all"
+		default:
+			opts.Resources.GPUs = strconv.Itoa(r.Count)
+		}
+	}
+
+	opts.Sysctls = hostConfig.Sysctls
+	// /tmp and /run are mounted as tmpfs on all the nodes, see runNode
+	for path, options := range hostConfig.Tmpfs {
+		if path == "/tmp" || path == "/run" {
+			continue
+		}
+		if opts.Tmpfs == nil {
+			opts.Tmpfs = map[string]string{}
+		}
+		opts.Tmpfs[path] = options
+	}
+
+	// the image store and data volumes, see imageStoreArgs and runNode
+	lines, err = docker.Inspect(n.nameOrID, "{{json .Mounts}}")
+	if err != nil {
+		return opts, errors.Wrap(err, "failed to get node mounts")
+	}
+	if len(lines) != 1 {
+		return opts, fmt.Errorf("mounts should only be one line, got %d lines", len(lines))
+	}
+	mounts := []struct {
+		Type        string
+		Name        string
+		Destination string
+	}{}
+	if err := json.Unmarshal([]byte(strings.Trim(lines[0], "'")), &mounts); err != nil {
+		return opts, errors.Wrap(err, "failed to parse node mounts")
+	}
+	for _, m := range mounts {
+		if m.Type != "volume" {
+			continue
+		}
+		if m.Name == DataVolumeName(n.nameOrID) {
+			opts.DataPath = m.Destination
+		} else if m.Destination == ImageStorePath {
+			opts.ImageStore = m.Name
+		}
 	}
 
-	return ioutil.WriteFile(dest, buff.Bytes(), 0600)
+	if opts.ExtraMounts, err = n.ExtraMounts(); err != nil {
+		return opts, err
+	}
+	if opts.ExtraPortMappings, err = n.ExtraPortMappings(); err != nil {
+		return opts, err
+	}
+	if opts.ExtraDevices, err = n.ExtraDevices(); err != nil {
+		return opts, err
+	}
+	if opts.Env, err = n.ProxyEnv(); err != nil {
+		return opts, err
+	}
+	return opts, nil
 }
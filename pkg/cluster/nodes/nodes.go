@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kind/pkg/cluster/consts"
 
+	"sigs.k8s.io/kind/pkg/docker"
 	"sigs.k8s.io/kind/pkg/exec"
 )
 
@@ -36,8 +37,7 @@ func Delete(nodes ...Node) error {
 	for _, node := range nodes {
 		ids = append(ids, node.nameOrID)
 	}
-	cmd := exec.Command(
-		"docker",
+	cmd := docker.Command(
 		append(
 			[]string{
 				"rm",
@@ -50,6 +50,64 @@ func Delete(nodes ...Node) error {
 	return cmd.Run()
 }
 
+// DeleteVolumes deletes the data volumes (see DataVolumeName) matching the
+// docker volume filters, typically the label of the cluster
+// https://docs.docker.com/engine/reference/commandline/volume_ls/#filtering
+func DeleteVolumes(filters ...string) error {
+	volumes, err := docker.ListVolumes(filters...)
+	if err != nil {
+		return errors.Wrap(err, "failed to list volumes")
+	}
+	if len(volumes) == 0 {
+		return nil
+	}
+	return docker.DeleteVolumes(volumes...)
+}
+
+// Stop stops nodes by name / ID (see Node.String()), preserving their
+// filesystem and volumes so that they may be started again with Start
+func Stop(nodes ...Node) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	return docker.Stop(nameOrIDs(nodes)...)
+}
+
+// Start starts previously stopped nodes by name / ID (see Node.String())
+// Note that the node entrypoint will wait for SignalStart before booting,
+// exactly as it does when the node is first created
+func Start(nodes ...Node) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	return docker.Start(nameOrIDs(nodes)...)
+}
+
+// Pause freezes nodes by name / ID (see Node.String()), their processes are
+// suspended until Unpause, as if the nodes hung
+func Pause(nodes ...Node) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	return docker.Pause(nameOrIDs(nodes)...)
+}
+
+// Unpause resumes nodes previously frozen with Pause
+func Unpause(nodes ...Node) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	return docker.Unpause(nameOrIDs(nodes)...)
+}
+
+func nameOrIDs(nodes []Node) []string {
+	ids := []string{}
+	for _, node := range nodes {
+		ids = append(ids, node.nameOrID)
+	}
+	return ids
+}
+
 // List returns the list of container IDs for the kind "nodes", optionally
 // filtered by docker ps filters
 // https://docs.docker.com/engine/reference/commandline/ps/#filtering
@@ -84,7 +142,7 @@ func list(visit func(string, *Node), filters ...string) error {
 	for _, filter := range filters {
 		args = append(args, "--filter", filter)
 	}
-	cmd := exec.Command("docker", args...)
+	cmd := docker.Command(args...)
 	lines, err := exec.CombinedOutputLines(cmd)
 	if err != nil {
 		return errors.Wrap(err, "failed to list nodes")
@@ -98,7 +156,8 @@ func list(visit func(string, *Node), filters ...string) error {
 		cluster := parts[1]
 		visit(cluster, FromID(names[0]))
 	}
-	return nil
+	// the cluster of claimed pool nodes is recorded on the node
+	return listClaimed(visit, filters...)
 }
 
 // WaitForReady uses kubectl inside the "node" container to check if the
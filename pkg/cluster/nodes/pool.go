@@ -0,0 +1,230 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodes
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+	"sigs.k8s.io/kind/pkg/docker"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// claimPath is the path of the claim of a pool node on the node, the labels
+// of the cluster that claimed it one "key=value" per line, see Claim
+const claimPath = "/kind/claim"
+
+// PoolLabel returns the docker object label of the node pool named pool
+func PoolLabel(pool string) string {
+	return fmt.Sprintf("%s=%s", consts.PoolKey, pool)
+}
+
+// poolNodePrefix is the prefix of the names of the unclaimed nodes of the
+// node pool named pool
+func poolNodePrefix(pool string) string {
+	return fmt.Sprintf("kind-pool-%s-", pool)
+}
+
+// PoolNodeName returns a new name for an unclaimed node of the node pool
+// named pool
+func PoolNodeName(pool string) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return poolNodePrefix(pool) + hex.EncodeToString(suffix), nil
+}
+
+// CreatePoolNode creates an unclaimed node of the node pool named pool, a
+// node not part of any cluster yet, which a cluster may claim as a
+// control-plane or worker node, see Claim. The node is attached to the
+// default bridge network, and the API server port is published on a random
+// host port in case it implements the control plane
+func CreatePoolNode(name, image, pool string) (node *Node, err error) {
+	publishArgs, port, err := apiServerArgs("", 0)
+	if err != nil {
+		return nil, err
+	}
+	node, err = createNode(name, image, PoolLabel(pool), Network{}, config.NodeRole(""), DataVolumePath, publishArgs...)
+	if err != nil {
+		return node, err
+	}
+	// stores the port mapping into the node internal state
+	node.ports = map[int]int{kubeadm.APIServerPort: port}
+	return node, nil
+}
+
+// ListPool returns the unclaimed nodes of the node pool named pool, or of all
+// the pools by pool name if pool is empty
+func ListPool(pool string) (map[string][]Node, error) {
+	filter := "label=" + consts.PoolKey
+	if pool != "" {
+		filter = "label=" + PoolLabel(pool)
+	}
+	containers, err := listPoolContainers(filter)
+	if err != nil {
+		return nil, err
+	}
+	res := map[string][]Node{}
+	for _, c := range containers {
+		if c.claimed() {
+			continue
+		}
+		res[c.pool] = append(res[c.pool], *FromID(c.name))
+	}
+	return res, nil
+}
+
+// poolContainer is a node container created for a node pool
+type poolContainer struct {
+	name string
+	pool string
+}
+
+// claimed returns true if the container was claimed by a cluster, claimed
+// nodes are renamed after their cluster
+func (c *poolContainer) claimed() bool {
+	return !strings.HasPrefix(c.name, poolNodePrefix(c.pool))
+}
+
+// listPoolContainers lists the node containers created for node pools
+// matching the docker ps filter
+func listPoolContainers(filter string) ([]poolContainer, error) {
+	lines, err := exec.CombinedOutputLines(docker.Command(
+		"ps", "-a",
+		"--filter", filter,
+		"--format", fmt.Sprintf(`{{.Names}}\t{{.Label "%s"}}`, consts.PoolKey),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pool nodes")
+	}
+	containers := []poolContainer{}
+	for _, line := range lines {
+		parts := strings.Split(line, "\t")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid output when listing pool nodes: %s", line)
+		}
+		containers = append(containers, poolContainer{
+			name: strings.Split(parts[0], ",")[0],
+			pool: parts[1],
+		})
+	}
+	return containers, nil
+}
+
+// Claim claims the unclaimed pool node for a cluster, the node container is
+// renamed to name and the labels (of the form "key=value") of the cluster,
+// including its cluster and role labels, are recorded on the node for Label.
+// Claim returns false if the node no longer exists or was claimed by another
+// cluster concurrently, renaming the container only succeeds once
+func (n *Node) Claim(name string, labels []string) (bool, error) {
+	if err := docker.Rename(n.nameOrID, name); err != nil {
+		return false, nil
+	}
+	n.nameOrID = name
+	n.nodeCache = nodeCache{}
+	if err := n.WriteFile(claimPath, []byte(strings.Join(labels, "\n")+"\n")); err != nil {
+		return true, errors.Wrap(err, "failed to record the claim")
+	}
+	return true, nil
+}
+
+// SetHostname sets the hostname of the node, e.g. to the new name of a
+// claimed pool node, the Kubernetes node is named after the hostname
+func (n *Node) SetHostname(name string) error {
+	ip, err := n.IP()
+	if err != nil {
+		return err
+	}
+	return n.Command("/bin/sh", "-c", fmt.Sprintf(
+		`hostname %[1]s && echo %[1]s > /etc/hostname && echo "%[2]s	%[1]s" >> /etc/hosts`,
+		name, ip,
+	)).Run()
+}
+
+// claimLabels returns the labels recorded on the node by Claim, or nil if
+// the node was not claimed, the node does not need to be running
+func (n *Node) claimLabels() (map[string]string, error) {
+	contents, err := docker.ReadFile(n.nameOrID, claimPath)
+	if err != nil {
+		// the claim does not exist until the node is claimed
+		return nil, nil
+	}
+	return parseClaim(contents), nil
+}
+
+// parseClaim parses the labels of a claim, see Claim
+func parseClaim(contents []byte) map[string]string {
+	labels := map[string]string{}
+	for _, line := range strings.Split(string(contents), "\n") {
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		labels[parts[0]] = parts[1]
+	}
+	return labels
+}
+
+// matchesLabelFilters returns true if the labels match all of the docker
+// label filters, of the form "label=key" or "label=key=value", other
+// filters never match
+func matchesLabelFilters(labels map[string]string, filters []string) bool {
+	for _, filter := range filters {
+		if !strings.HasPrefix(filter, "label=") {
+			return false
+		}
+		parts := strings.SplitN(strings.TrimPrefix(filter, "label="), "=", 2)
+		value, ok := labels[parts[0]]
+		if !ok || (len(parts) == 2 && value != parts[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// listClaimed visits the pool nodes claimed by clusters whose claims match
+// the label filters, see list
+func listClaimed(visit func(string, *Node), filters ...string) error {
+	containers, err := listPoolContainers("label=" + consts.PoolKey)
+	if err != nil {
+		return err
+	}
+	for _, c := range containers {
+		if !c.claimed() {
+			continue
+		}
+		node := FromID(c.name)
+		labels, err := node.claimLabels()
+		if err != nil {
+			return err
+		}
+		cluster, ok := labels[consts.ClusterLabelKey]
+		if !ok || !matchesLabelFilters(labels, filters) {
+			continue
+		}
+		visit(cluster, node)
+	}
+	return nil
+}
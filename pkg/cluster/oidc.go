@@ -0,0 +1,182 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+)
+
+// oidcCA returns the PEM encoded CA certificate of the OpenID issuer of oidc,
+// or nil if the node CA certificates are used
+func oidcCA(oidc *config.OIDC) ([]byte, error) {
+	if !oidc.Enabled() || oidc.CAFile == "" {
+		return nil, nil
+	}
+	cert, err := ioutil.ReadFile(oidc.CAFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the OIDC CA file")
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(cert) {
+		return nil, errors.Errorf("no PEM encoded certificates in the OIDC CA file %s", oidc.CAFile)
+	}
+	return cert, nil
+}
+
+// oidcArgs returns the API server flags of the OpenID Connect authentication
+// configured by oidc, if any
+func oidcArgs(oidc *config.OIDC) map[string]string {
+	if !oidc.Enabled() {
+		return nil
+	}
+	args := map[string]string{
+		"oidc-issuer-url": oidc.IssuerURL,
+		"oidc-client-id":  oidc.ClientID,
+	}
+	if oidc.CAFile != "" {
+		args["oidc-ca-file"] = kubeadm.OIDCCAFile
+	}
+	for flag, value := range map[string]string{
+		"oidc-username-claim":  oidc.UsernameClaim,
+		"oidc-username-prefix": oidc.UsernamePrefix,
+		"oidc-groups-claim":    oidc.GroupsClaim,
+		"oidc-groups-prefix":   oidc.GroupsPrefix,
+	} {
+		if value != "" {
+			args[flag] = value
+		}
+	}
+	if len(oidc.RequiredClaims) > 0 {
+		claims := []string{}
+		for claim, value := range oidc.RequiredClaims {
+			claims = append(claims, fmt.Sprintf("%s=%s", claim, value))
+		}
+		sort.Strings(claims)
+		args["oidc-required-claim"] = strings.Join(claims, ",")
+	}
+	return args
+}
+
+// writeOIDCCA writes the CA certificate of the OpenID issuer of the cluster,
+// if any, to the control-plane node, where the API server verifies the
+// issuer with it
+func (ec *execContext) writeOIDCCA(configNode *nodeReplica) error {
+	cert, err := oidcCA(&ec.config.OIDC)
+	if err != nil || cert == nil {
+		return err
+	}
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return errors.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+	if err := node.WriteFile(kubeadm.OIDCCAFile, cert); err != nil {
+		return errors.Wrap(err, "failed to write the OIDC CA file to the node")
+	}
+	return nil
+}
+
+// addOIDCContext adds a context authenticating with the ID tokens of the
+// OpenID issuer of oidc, if any, to the kubeconfig at kubeConfigPath
+func addOIDCContext(kubeConfigPath string, oidc *config.OIDC) error {
+	if !oidc.Enabled() {
+		return nil
+	}
+	kubeConfig, err := ioutil.ReadFile(kubeConfigPath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read kubeconfig")
+	}
+	caFile := ""
+	if oidc.CAFile != "" {
+		if caFile, err = filepath.Abs(oidc.CAFile); err != nil {
+			return errors.Wrap(err, "failed to get the OIDC CA file path")
+		}
+	}
+	kubeConfig, err = oidcKubeConfig(kubeConfig, oidc, caFile)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(kubeConfigPath, kubeConfig, 0600)
+}
+
+// oidcKubeConfig adds a user getting the ID tokens with kubelogin, the
+// `kubectl oidc-login` plugin, and an oidc@<cluster> context of the user and
+// the current cluster of kubeConfig, the current context is not changed.
+// caFile is the host path of the CA certificate of the issuer, if any
+func oidcKubeConfig(kubeConfig []byte, oidc *config.OIDC, caFile string) ([]byte, error) {
+	cfg := map[string]interface{}{}
+	if err := yaml.Unmarshal(kubeConfig, &cfg); err != nil {
+		return nil, errors.Wrap(err, "failed to parse kubeconfig")
+	}
+	contexts, _ := cfg["contexts"].([]interface{})
+	users, _ := cfg["users"].([]interface{})
+	if len(contexts) == 0 {
+		return nil, errors.New("kubeconfig should have a context")
+	}
+	context, _ := contexts[0].(map[string]interface{})
+	contextConfig, _ := context["context"].(map[string]interface{})
+	if contextConfig == nil {
+		return nil, errors.New("failed to parse kubeconfig context")
+	}
+	clusterName, _ := contextConfig["cluster"].(string)
+
+	args := []interface{}{
+		"oidc-login",
+		"get-token",
+		"--oidc-issuer-url=" + oidc.IssuerURL,
+		"--oidc-client-id=" + oidc.ClientID,
+	}
+	if oidc.ClientSecret != "" {
+		args = append(args, "--oidc-client-secret="+oidc.ClientSecret)
+	}
+	for _, scope := range oidc.ExtraScopes {
+		args = append(args, "--oidc-extra-scope="+scope)
+	}
+	if caFile != "" {
+		args = append(args, "--certificate-authority="+caFile)
+	}
+	userName := "oidc@" + clusterName
+	users = append(users, map[string]interface{}{
+		"name": userName,
+		"user": map[string]interface{}{
+			"exec": map[string]interface{}{
+				"apiVersion": "client.authentication.k8s.io/v1beta1",
+				"command":    "kubectl",
+				"args":       args,
+			},
+		},
+	})
+	contexts = append(contexts, map[string]interface{}{
+		"name": userName,
+		"context": map[string]interface{}{
+			"cluster": clusterName,
+			"user":    userName,
+		},
+	})
+	cfg["users"] = users
+	cfg["contexts"] = contexts
+	return yaml.Marshal(cfg)
+}
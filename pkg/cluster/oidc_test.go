@@ -0,0 +1,151 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+)
+
+func TestOIDCArgs(t *testing.T) {
+	cases := []struct {
+		Name     string
+		OIDC     config.OIDC
+		Expected map[string]string
+	}{
+		{
+			Name: "disabled",
+		},
+		{
+			Name: "issuer and client",
+			OIDC: config.OIDC{IssuerURL: "https://dex.example.com", ClientID: "kubernetes"},
+			Expected: map[string]string{
+				"oidc-issuer-url": "https://dex.example.com",
+				"oidc-client-id":  "kubernetes",
+			},
+		},
+		{
+			Name: "claims and CA",
+			OIDC: config.OIDC{
+				IssuerURL:      "https://dex.example.com",
+				ClientID:       "kubernetes",
+				ClientSecret:   "secret",
+				CAFile:         "dex-ca.crt",
+				UsernameClaim:  "email",
+				GroupsClaim:    "groups",
+				GroupsPrefix:   "oidc:",
+				RequiredClaims: map[string]string{"org": "kind", "hd": "example.com"},
+			},
+			Expected: map[string]string{
+				"oidc-issuer-url":     "https://dex.example.com",
+				"oidc-client-id":      "kubernetes",
+				"oidc-ca-file":        kubeadm.OIDCCAFile,
+				"oidc-username-claim": "email",
+				"oidc-groups-claim":   "groups",
+				"oidc-groups-prefix":  "oidc:",
+				"oidc-required-claim": "hd=example.com,org=kind",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if args := oidcArgs(&tc.OIDC); !reflect.DeepEqual(args, tc.Expected) {
+				t.Errorf("expected %v but got %v", tc.Expected, args)
+			}
+		})
+	}
+}
+
+func TestOIDCKubeConfig(t *testing.T) {
+	kubeConfig := []byte(`apiVersion: v1
+clusters:
+- cluster:
+    server: https://localhost:32768
+  name: kind
+contexts:
+- context:
+    cluster: kind
+    user: kubernetes-admin
+  name: kubernetes-admin@kind
+current-context: kubernetes-admin@kind
+kind: Config
+users:
+- name: kubernetes-admin
+  user:
+    client-certificate-data: Y2VydA==
+`)
+	oidc := &config.OIDC{
+		IssuerURL:   "https://dex.example.com",
+		ClientID:    "kubernetes",
+		ExtraScopes: []string{"email", "groups"},
+	}
+	out, err := oidcKubeConfig(kubeConfig, oidc, "/tmp/dex-ca.crt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parsed := struct {
+		CurrentContext string `json:"current-context"`
+		Contexts       []struct {
+			Name    string `json:"name"`
+			Context struct {
+				Cluster string `json:"cluster"`
+				User    string `json:"user"`
+			} `json:"context"`
+		} `json:"contexts"`
+		Users []struct {
+			Name string `json:"name"`
+			User struct {
+				Exec struct {
+					Command string   `json:"command"`
+					Args    []string `json:"args"`
+				} `json:"exec"`
+			} `json:"user"`
+		} `json:"users"`
+	}{}
+	if err := yaml.Unmarshal(out, &parsed); err != nil {
+		t.Fatalf("failed to parse kubeconfig: %v", err)
+	}
+	if parsed.CurrentContext != "kubernetes-admin@kind" {
+		t.Errorf("expected the current context to be unchanged, got %q", parsed.CurrentContext)
+	}
+	if len(parsed.Contexts) != 2 || len(parsed.Users) != 2 {
+		t.Fatalf("expected two contexts and users, got %d and %d", len(parsed.Contexts), len(parsed.Users))
+	}
+	context := parsed.Contexts[1]
+	if context.Name != "oidc@kind" || context.Context.Cluster != "kind" || context.Context.User != "oidc@kind" {
+		t.Errorf("unexpected OIDC context %+v", context)
+	}
+	user := parsed.Users[1]
+	expectedArgs := []string{
+		"oidc-login",
+		"get-token",
+		"--oidc-issuer-url=https://dex.example.com",
+		"--oidc-client-id=kubernetes",
+		"--oidc-extra-scope=email",
+		"--oidc-extra-scope=groups",
+		"--certificate-authority=/tmp/dex-ca.crt",
+	}
+	if user.Name != "oidc@kind" || user.User.Exec.Command != "kubectl" || !reflect.DeepEqual(user.User.Exec.Args, expectedArgs) {
+		t.Errorf("unexpected OIDC user %+v", user)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+)
+
+// OwnerEnv is the environment variable setting the owner identity recorded
+// on the clusters created, e.g. a CI job ID, see DefaultOwner.
+// When set, Delete also refuses to delete the clusters of other owners
+const OwnerEnv = "KIND_OWNER"
+
+// DefaultOwner returns the owner identity recorded on the clusters created:
+// the OwnerEnv environment variable if set, and otherwise the name of the
+// current user, or "" if unknown
+func DefaultOwner() string {
+	if owner := os.Getenv(OwnerEnv); owner != "" {
+		return owner
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+// ownerLabel returns the docker object label recording the owner of the
+// cluster
+func ownerLabel(owner string) string {
+	return fmt.Sprintf("%s=%s", consts.OwnerKey, owner)
+}
+
+// Owner returns the owner identity recorded on the cluster, or "" if the
+// cluster was created without one, see DefaultOwner
+func (c *Context) Owner() (string, error) {
+	n, err := c.ListNodes()
+	if err != nil {
+		return "", fmt.Errorf("error listing nodes: %v", err)
+	}
+	if len(n) == 0 {
+		return "", nil
+	}
+	// all the nodes are created with the same owner
+	return n[0].Label(consts.OwnerKey)
+}
+
+// ListOwnedBy returns the clusters owned by owner, see Context.Owner
+func ListOwnedBy(owner string) ([]Context, error) {
+	clusters, err := List()
+	if err != nil {
+		return nil, err
+	}
+	owned := []Context{}
+	for _, c := range clusters {
+		clusterOwner, err := c.Owner()
+		if err != nil {
+			return nil, err
+		}
+		if clusterOwner == owner {
+			owned = append(owned, c)
+		}
+	}
+	return owned, nil
+}
+
+// checkOwner returns an error if OwnerEnv is set and the cluster is owned by
+// someone else, clusters created without an owner are not checked
+func (c *Context) checkOwner() error {
+	owner := os.Getenv(OwnerEnv)
+	if owner == "" {
+		return nil
+	}
+	clusterOwner, err := c.Owner()
+	if err != nil {
+		return err
+	}
+	if clusterOwner != "" && clusterOwner != owner {
+		return fmt.Errorf("cluster %q is owned by %q, not by %s=%q", c.Name(), clusterOwner, OwnerEnv, owner)
+	}
+	return nil
+}
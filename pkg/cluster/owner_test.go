@@ -0,0 +1,46 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"os"
+	"os/user"
+	"testing"
+)
+
+func TestDefaultOwner(t *testing.T) {
+	if value, ok := os.LookupEnv(OwnerEnv); ok {
+		defer os.Setenv(OwnerEnv, value)
+	} else {
+		defer os.Unsetenv(OwnerEnv)
+	}
+
+	// the owner is the current user unless set in the environment
+	os.Unsetenv(OwnerEnv)
+	expected := ""
+	if u, err := user.Current(); err == nil {
+		expected = u.Username
+	}
+	if owner := DefaultOwner(); owner != expected {
+		t.Errorf("expected the owner to be the current user %q, got %q", expected, owner)
+	}
+
+	os.Setenv(OwnerEnv, "ci-job-1234")
+	if owner := DefaultOwner(); owner != "ci-job-1234" {
+		t.Errorf("expected the owner to be %q, got %q", "ci-job-1234", owner)
+	}
+}
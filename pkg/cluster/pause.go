@@ -0,0 +1,197 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// Pause stops all of the cluster's node containers, including external etcd
+// and load balancer nodes, freeing their CPU and memory while preserving
+// their state so that the cluster can later be brought back with Resume
+func (c *Context) Pause() error {
+	n, err := c.ListNodes()
+	if err != nil {
+		return fmt.Errorf("error listing nodes: %v", err)
+	}
+	if len(n) == 0 {
+		return fmt.Errorf("no nodes found for cluster %q", c.Name())
+	}
+
+	// the node data kept in tmpfs would be lost, see config.Tmpfs
+	tmpfs, err := n[0].Label(consts.TmpfsKey)
+	if err != nil {
+		return err
+	}
+	if tmpfs != "" {
+		return fmt.Errorf("cluster %q keeps node data in tmpfs (%s), which stopping the nodes would lose", c.Name(), tmpfs)
+	}
+
+	// stop nodes in the reverse of the provisioning order, so that workers
+	// go away before the control plane and its dependencies
+	if err := sortNodesByProvisioningOrder(n); err != nil {
+		return err
+	}
+	for i, j := 0, len(n)-1; i < j; i, j = i+1, j-1 {
+		n[i], n[j] = n[j], n[i]
+	}
+
+	status := logutil.NewStatus(os.Stdout)
+	status.MaybeWrapLogger(c.Logger())
+	defer status.End(false)
+
+	for _, node := range n {
+		status.Start(fmt.Sprintf("[%s] Stopping node container ⏸", node.String()))
+		if err := nodes.Stop(node); err != nil {
+			return errors.Wrapf(err, "failed to stop node %s", node.String())
+		}
+	}
+	status.End(true)
+
+	return nil
+}
+
+// Resume restarts all of the cluster's node containers previously stopped
+// with Pause, boots them, and then waits up to wait for the control plane
+// to report Ready again, returning an error if it does not
+func (c *Context) Resume(wait time.Duration) error {
+	n, err := c.ListNodes()
+	if err != nil {
+		return fmt.Errorf("error listing nodes: %v", err)
+	}
+	if len(n) == 0 {
+		return fmt.Errorf("no nodes found for cluster %q", c.Name())
+	}
+
+	// start nodes in the provisioning order, so that external dependencies
+	// are available before the control plane, and the control plane before workers
+	if err := sortNodesByProvisioningOrder(n); err != nil {
+		return err
+	}
+
+	status := logutil.NewStatus(os.Stdout)
+	status.MaybeWrapLogger(c.Logger())
+	defer status.End(false)
+
+	controlPlanes := []*nodes.Node{}
+	for i := range n {
+		node := &n[i]
+
+		status.Start(fmt.Sprintf("[%s] Starting node container ▶", node.String()))
+		if err := nodes.Start(*node); err != nil {
+			return errors.Wrapf(err, "failed to start node %s", node.String())
+		}
+
+		// the node container is back in the same state as after creation,
+		// so we need to repeat the same steps used for booting a new node
+		if err := bootNode(status, node); err != nil {
+			return err
+		}
+
+		if role, _ := node.Role(); config.NodeRole(role) == config.ControlPlaneRole {
+			controlPlanes = append(controlPlanes, node)
+		}
+	}
+	status.End(true)
+
+	// the node IPs may have changed, so the load balancer backends and the
+	// internal kubeconfig must be updated
+	if err := c.ReconfigureLoadBalancer(); err != nil {
+		return errors.Wrap(err, "failed to reconfigure the external load balancer")
+	}
+	if err := c.writeInternalKubeConfig(); err != nil {
+		return err
+	}
+
+	// re-validate the control plane is healthy before returning
+	status = logutil.NewStatus(os.Stdout)
+	status.MaybeWrapLogger(c.Logger())
+	defer status.End(false)
+	for _, node := range controlPlanes {
+		status.Start(fmt.Sprintf("[%s] Waiting for the control plane to be ready ☸", node.String()))
+		if !nodes.WaitForReady(node, time.Now().Add(wait)) {
+			return &NodeNotReadyError{Node: node.String(), Condition: "the control plane to be ready"}
+		}
+	}
+	status.End(true)
+
+	return nil
+}
+
+// bootNode takes an existing node container that is waiting in the entrypoint
+// (either newly started or restarted) and boots it into systemd
+// The external load balancer and Windows nodes do not need booting
+func bootNode(status *logutil.Status, node *nodes.Node) error {
+	role, err := node.Role()
+	if err != nil {
+		return err
+	}
+	if config.NodeRole(role) == config.ExternalLoadBalancerRole || node.IsWindows() {
+		return nil
+	}
+
+	status.Start(fmt.Sprintf("[%s] Fixing mounts 🗻", node.String()))
+	if err := node.FixMounts(); err != nil {
+		return errors.Wrapf(err, "failed to fix mounts on node %s", node.String())
+	}
+	if err := node.FixDNS(); err != nil {
+		return errors.Wrapf(err, "failed to fix DNS on node %s", node.String())
+	}
+	if err := node.ConfigureProxy(); err != nil {
+		return errors.Wrapf(err, "failed to configure proxy on node %s", node.String())
+	}
+
+	status.Start(fmt.Sprintf("[%s] Starting systemd 🖥", node.String()))
+	if err := node.SignalStart(); err != nil {
+		return errors.Wrapf(err, "failed to start systemd on node %s", node.String())
+	}
+
+	status.Start(fmt.Sprintf("[%s] Waiting for docker to be ready 🐋", node.String()))
+	if !node.WaitForDocker(time.Now().Add(time.Second * 30)) {
+		return &NodeNotReadyError{Node: node.String(), Condition: "docker to be ready"}
+	}
+	return nil
+}
+
+// sortNodesByProvisioningOrder sorts existing node containers according
+// to the provisioning order of their role (see nodeReplica.ProvisioningOrder)
+func sortNodesByProvisioningOrder(n []nodes.Node) error {
+	orders := make(map[string]int, len(n))
+	for i := range n {
+		role, err := n[i].Role()
+		if err != nil {
+			return err
+		}
+		orders[n[i].String()] = provisioningOrder(config.NodeRole(role))
+	}
+	sort.SliceStable(n, func(i, j int) bool {
+		return orders[n[i].String()] < orders[n[j].String()] ||
+			// In case of same provisioning order, the name is used to get predictable/repeatable results
+			(orders[n[i].String()] == orders[n[j].String()] && n[i].String() < n[j].String())
+	})
+	return nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/docker"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// DefaultPool is the default node pool name
+const DefaultPool = "default"
+
+// CreatePool adds size booted but unjoined nodes running image to the node
+// pool named pool, creating the pool if needed. Clusters created with
+// SetNodePool claim their nodes from the pool instead of creating them, see
+// claimPoolNode
+func CreatePool(pool, image string, size int) error {
+	if !validNameRE.MatchString(pool) {
+		return fmt.Errorf(
+			"'%s' is not a valid pool name, pool names must match `%s`",
+			pool, validNameRE.String(),
+		)
+	}
+	status := logutil.NewStatus(os.Stdout)
+	defer status.End(false)
+
+	status.Start(fmt.Sprintf("Ensuring node image (%s) 🖼", image))
+	_, _ = docker.PullIfNotPresent(image, 4)
+
+	for i := 0; i < size; i++ {
+		name, err := nodes.PoolNodeName(pool)
+		if err != nil {
+			return errors.Wrap(err, "failed to name the pool node")
+		}
+		status.Start(fmt.Sprintf("[%s] Creating node container 📦", name))
+		node, err := nodes.CreatePoolNode(name, image, pool)
+		if err == nil {
+			err = bootNode(status, node)
+		}
+		if err != nil {
+			if node != nil {
+				_ = nodes.Delete(*node)
+			}
+			return errors.Wrapf(err, "failed to create pool node %s", name)
+		}
+		status.Start(fmt.Sprintf("[%s] Pre-loading images 🐋", name))
+		node.LoadImages()
+	}
+	status.End(true)
+	return nil
+}
+
+// ListPools returns the unclaimed nodes of all the node pools by pool name,
+// and the sorted pool names
+func ListPools() (map[string][]nodes.Node, []string, error) {
+	pools, err := nodes.ListPool("")
+	if err != nil {
+		return nil, nil, err
+	}
+	names := make([]string, 0, len(pools))
+	for name := range pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return pools, names, nil
+}
+
+// DeletePool deletes the unclaimed nodes of the node pool named pool, the
+// nodes already claimed are deleted with their cluster
+func DeletePool(pool string) error {
+	pools, err := nodes.ListPool(pool)
+	if err != nil {
+		return err
+	}
+	if err := nodes.Delete(pools[pool]...); err != nil {
+		return err
+	}
+	return nodes.DeleteVolumes("label="+nodes.PoolLabel(pool), "dangling=true")
+}
+
+// SetNodePool sets the node pool the nodes are claimed from when creating
+// the cluster, if "" (the default) the nodes are always created
+func (c *Context) SetNodePool(pool string) {
+	c.nodePool = pool
+}
+
+// claimPoolNode claims an unclaimed node of the node pool for configNode,
+// if the node does not need anything the pool nodes are not created with
+// and a running node with the same image is available, and returns nil
+// otherwise so that the node is created instead.
+// The claimed node is renamed to name, moved to the cluster network and
+// recorded as part of the cluster with labels, only the kubeadm actions
+// remain to be executed on it
+func (cc *createContext) claimPoolNode(configNode *nodeReplica, name, network string, registryConfig *imageRegistryConfig, env, labels []string) (*nodes.Node, error) {
+	if reason := poolIneligible(cc.config, configNode, cc.apiServerPort(configNode), registryConfig, env); reason != "" {
+		cc.Logger().Infof("Creating node %s instead of claiming it from pool %q: %s", configNode.Name, cc.nodePool, reason)
+		return nil, nil
+	}
+	pools, err := nodes.ListPool(cc.nodePool)
+	if err != nil {
+		return nil, err
+	}
+	labels = append([]string{
+		cc.ClusterLabel(),
+		fmt.Sprintf("%s=%s", consts.ClusterRoleKey, configNode.Role),
+	}, labels...)
+	for i := range pools[cc.nodePool] {
+		node := &pools[cc.nodePool][i]
+		if image, err := node.Image(); err != nil || image != configNode.Image {
+			continue
+		}
+		if state, err := node.State(); err != nil || state != "running" {
+			continue
+		}
+		claimed, err := node.Claim(name, labels)
+		if err != nil {
+			return node, err
+		}
+		if !claimed {
+			// another cluster claimed it first
+			continue
+		}
+		cc.Logger().Debugf("[%s] Claimed node %s from pool %q", configNode.Name, node.String(), cc.nodePool)
+		return node, cc.joinPoolNode(configNode, node, network, registryConfig)
+	}
+	cc.Logger().Infof("Creating node %s instead of claiming it from pool %q: no node running %s is available", configNode.Name, cc.nodePool, configNode.Image)
+	return nil, nil
+}
+
+// joinPoolNode moves the claimed pool node from the default bridge network
+// to the cluster network, and configures it like provisionNodes would
+func (cc *createContext) joinPoolNode(configNode *nodeReplica, node *nodes.Node, network string, registryConfig *imageRegistryConfig) error {
+	if err := docker.ConnectNetwork(network, node.String()); err != nil {
+		return errors.Wrapf(err, "failed to connect node %s to network %s", node.String(), network)
+	}
+	if err := docker.DisconnectNetwork("bridge", node.String()); err != nil {
+		return errors.Wrapf(err, "failed to disconnect node %s from the default network", node.String())
+	}
+	if err := node.FixDNS(); err != nil {
+		return fmt.Errorf("failed to fix DNS: %v", err)
+	}
+	if err := node.SetHostname(node.String()); err != nil {
+		return errors.Wrapf(err, "failed to set the hostname of node %s", node.String())
+	}
+	if err := registryConfig.Write(node); err != nil {
+		return err
+	}
+	if cc.cniManifest != nil {
+		if err := node.WriteFile(defaultCNIManifestPath, cc.cniManifest); err != nil {
+			return fmt.Errorf("failed to write the default CNI manifest: %v", err)
+		}
+	}
+	return nil
+}
+
+// poolIneligible returns why configNode cannot be claimed from a node pool,
+// or "" if it can. The pool nodes are created without any node or network
+// configuration, on the default bridge network, so only the nodes that do
+// not need any can be claimed
+func poolIneligible(cfg *config.Config, configNode *nodeReplica, apiServerPort int32, registryConfig *imageRegistryConfig, env []string) string {
+	switch {
+	case configNode.Role != config.ControlPlaneRole && configNode.Role != config.WorkerRole:
+		return fmt.Sprintf("%s nodes are not pooled", configNode.Role)
+	case configNode.IsWindows():
+		return "Windows nodes are not pooled"
+	case len(nodeMounts(cfg, configNode)) > 0:
+		return "the node has extra mounts"
+	case len(nodePortMappings(configNode)) > 0:
+		return "the node has port mappings"
+	case configNode.Resources != config.NodeResources{}:
+		return "the node has resource limits"
+	case len(configNode.Sysctls) > 0:
+		return "the node has sysctls"
+	case len(configNode.ExtraDevices) > 0:
+		return "the node has extra devices"
+	case configNode.IPAddress != "" || configNode.IPv6Address != "":
+		return "the node has a static IP address"
+	case cfg.ImageStore.Volume != "":
+		return "the cluster has an image store"
+	case len(nodeTmpfs(cfg, configNode)) > 0:
+		return "the cluster keeps node data in tmpfs"
+	case len(env) > 0:
+		return "the cluster uses a proxy"
+	case registryConfig.DockerDaemonConfig != nil:
+		return "the cluster configures the docker daemon of the nodes"
+	case cfg.Networking.IPFamily != "" && cfg.Networking.IPFamily != config.IPv4Family:
+		return "the cluster is not IPv4 only"
+	case len(cfg.Networking.DNS.Nameservers) > 0 || len(cfg.Networking.DNS.Search) > 0 || len(cfg.Networking.DNS.Options) > 0:
+		return "the cluster configures DNS"
+	case apiServerPort != 0 || cfg.Networking.APIServerAddress != "":
+		return "the cluster configures the API server address"
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestPoolIneligible(t *testing.T) {
+	cases := []struct {
+		TestName       string
+		Config         config.Config
+		Node           config.Node
+		APIServerPort  int32
+		RegistryConfig imageRegistryConfig
+		Env            []string
+		ExpectEligible bool
+	}{
+		{
+			TestName:       "control-plane",
+			Node:           config.Node{Role: config.ControlPlaneRole},
+			ExpectEligible: true,
+		},
+		{
+			TestName:       "worker pulling with credentials",
+			Node:           config.Node{Role: config.WorkerRole},
+			RegistryConfig: imageRegistryConfig{KubeletDockerConfig: []byte("{}")},
+			ExpectEligible: true,
+		},
+		{
+			TestName: "external load balancer",
+			Node:     config.Node{Role: config.ExternalLoadBalancerRole},
+		},
+		{
+			TestName: "Windows worker",
+			Node:     config.Node{Role: config.WorkerRole, OS: config.WindowsOS},
+		},
+		{
+			TestName: "extra mounts",
+			Node: config.Node{
+				Role:        config.WorkerRole,
+				ExtraMounts: []config.Mount{{HostPath: "/tmp", ContainerPath: "/tmp"}},
+			},
+		},
+		{
+			TestName: "ingress ready",
+			Node:     config.Node{Role: config.ControlPlaneRole, IngressReady: true},
+		},
+		{
+			TestName: "resource limits",
+			Node: config.Node{
+				Role:      config.WorkerRole,
+				Resources: config.NodeResources{Memory: "2Gi"},
+			},
+		},
+		{
+			TestName: "image store",
+			Config:   config.Config{ImageStore: config.ImageStore{Volume: "images"}},
+			Node:     config.Node{Role: config.WorkerRole},
+		},
+		{
+			TestName: "proxy",
+			Node:     config.Node{Role: config.WorkerRole},
+			Env:      []string{"HTTP_PROXY=http://proxy.example.com:3128"},
+		},
+		{
+			TestName:       "docker daemon config",
+			Node:           config.Node{Role: config.WorkerRole},
+			RegistryConfig: imageRegistryConfig{DockerDaemonConfig: []byte("{}")},
+		},
+		{
+			TestName: "IPv6",
+			Config: config.Config{
+				Networking: config.Networking{IPFamily: config.IPv6Family},
+			},
+			Node: config.Node{Role: config.WorkerRole},
+		},
+		{
+			TestName:      "API server port",
+			Node:          config.Node{Role: config.ControlPlaneRole},
+			APIServerPort: 6443,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.TestName, func(t *testing.T) {
+			configNode := &nodeReplica{Node: tc.Node, Name: "node"}
+			reason := poolIneligible(&tc.Config, configNode, tc.APIServerPort, &tc.RegistryConfig, tc.Env)
+			if eligible := reason == ""; eligible != tc.ExpectEligible {
+				t.Errorf("expected eligible %v but got %v (%q)", tc.ExpectEligible, eligible, reason)
+			}
+		})
+	}
+}
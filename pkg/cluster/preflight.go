@@ -0,0 +1,413 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	osexec "os/exec"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/docker"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// PreflightStatus is the outcome of a preflight check
+type PreflightStatus string
+
+const (
+	// PreflightPass means the host meets the prerequisite
+	PreflightPass PreflightStatus = "pass"
+	// PreflightWarn means the host does not meet the prerequisite, which
+	// may cause problems with the cluster but does not prevent creating it
+	PreflightWarn PreflightStatus = "warn"
+	// PreflightFail means the host does not meet the prerequisite, which
+	// prevents creating the cluster
+	PreflightFail PreflightStatus = "fail"
+	// PreflightSkip means the prerequisite could not be checked, e.g. on
+	// the remote host of the provider
+	PreflightSkip PreflightStatus = "skip"
+)
+
+// PreflightCheck is the result of checking a host prerequisite, see Preflight
+type PreflightCheck struct {
+	// Name is the name of the prerequisite, e.g. "inotify"
+	Name string
+	// Status is the outcome of the check
+	Status PreflightStatus
+	// Message describes what was found on the host
+	Message string
+	// Remediation explains how to meet the prerequisite, if not met
+	Remediation string
+}
+
+// preflight minimums, the recommended inotify limits allow running the
+// kubelet and the containers of several clusters
+const (
+	minInotifyMaxUserWatches   = 524288
+	minInotifyMaxUserInstances = 512
+	// minNodeMemoryBytes is the memory recommended per Kubernetes node
+	minNodeMemoryBytes = 1 << 30
+	// minStorageFreeBytes is the free disk space recommended for the
+	// images and the containers of a cluster
+	minStorageFreeBytes = 10 << 30
+)
+
+// minProviderVersions are the minimum versions of the runtimes of the
+// providers, see docker.ProviderInfo, nerdctl reports the containerd version
+var minProviderVersions = map[string]string{
+	docker.DockerProviderName:  "18.09.0",
+	docker.PodmanProviderName:  "3.0.0",
+	docker.NerdctlProviderName: "1.4.0",
+}
+
+// Preflight checks the host prerequisites of creating a cluster for cfg:
+// the provider runtime and its version, the cgroups, the inotify limits, the
+// available memory and disk space, the kernel modules, the host ports and
+// the NVIDIA container toolkit for the nodes with GPUs.
+// It returns the results of all the checks, the cluster can not be created
+// if any of them failed, see PreflightPassed
+func Preflight(cfg *config.Config) ([]PreflightCheck, error) {
+	derived, err := validateForCreate(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Provider != "" {
+		if err := docker.SetProvider(string(cfg.Provider)); err != nil {
+			return nil, err
+		}
+	}
+	provider := docker.CurrentProvider()
+	info, infoErr := provider.Info()
+	checks := []PreflightCheck{checkProvider(provider, info, infoErr)}
+
+	// the other prerequisites are those of the host running the nodes
+	remote := provider.RemoteHost()
+	localChecks := []func() PreflightCheck{
+		func() PreflightCheck { return checkCgroups(provider) },
+		checkInotify,
+		func() PreflightCheck { return checkMemory(derived) },
+		func() PreflightCheck { return checkStorage(info) },
+		func() PreflightCheck { return checkKernelModules(provider) },
+		func() PreflightCheck { return checkHostPorts(cfg, derived, provider) },
+		func() PreflightCheck { return checkGPUs(derived) },
+	}
+	for _, check := range localChecks {
+		result := check()
+		if remote != "" {
+			result = PreflightCheck{
+				Name:    result.Name,
+				Status:  PreflightSkip,
+				Message: fmt.Sprintf("the nodes run on the remote host %s", remote),
+			}
+		}
+		checks = append(checks, result)
+	}
+	return checks, nil
+}
+
+// PreflightPassed returns true unless any of the checks failed
+func PreflightPassed(checks []PreflightCheck) bool {
+	for _, check := range checks {
+		if check.Status == PreflightFail {
+			return false
+		}
+	}
+	return true
+}
+
+// checkProvider checks that the provider runtime is available and recent
+func checkProvider(provider docker.Provider, info docker.ProviderInfo, infoErr error) PreflightCheck {
+	check := PreflightCheck{Name: "provider"}
+	if infoErr != nil {
+		check.Status = PreflightFail
+		check.Message = infoErr.Error()
+		check.Remediation = fmt.Sprintf("install and start %s, or select another provider with %s", provider.Name(), docker.ProviderEnv)
+		return check
+	}
+	check.Message = fmt.Sprintf("%s %s", provider.Name(), info.Version)
+	if provider.Rootless() {
+		check.Message += ", rootless"
+	}
+	if host := provider.RemoteHost(); host != "" {
+		check.Message += ", on " + host
+	}
+	ver, err := version.ParseGeneric(info.Version)
+	if err != nil {
+		check.Status = PreflightWarn
+		check.Message += ", failed to parse the version"
+		return check
+	}
+	minVersion := version.MustParseGeneric(minProviderVersions[provider.Name()])
+	if !ver.AtLeast(minVersion) {
+		check.Status = PreflightFail
+		check.Message += fmt.Sprintf(", older than %s", minVersion)
+		check.Remediation = fmt.Sprintf("upgrade %s to %s or later", provider.Name(), minVersion)
+		return check
+	}
+	check.Status = PreflightPass
+	return check
+}
+
+// checkCgroups checks the cgroup version, and the cgroup controllers
+// delegated to the user for rootless providers
+func checkCgroups(provider docker.Provider) PreflightCheck {
+	check := PreflightCheck{Name: "cgroups", Status: PreflightPass, Message: "cgroup v1"}
+	if cgroupV2() {
+		check.Message = "cgroup v2"
+	}
+	if !provider.Rootless() {
+		return check
+	}
+	if err := validateCgroupDelegation(os.Getuid()); err != nil {
+		check.Status = PreflightFail
+		check.Message = err.Error()
+		check.Remediation = cgroupDelegationCommand
+		if !cgroupV2() {
+			check.Remediation = "boot the host with the kernel parameter systemd.unified_cgroup_hierarchy=1"
+		}
+	}
+	return check
+}
+
+// checkInotify checks the inotify limits, the kubelet and many containers
+// watch files, which fails with too many open files when they are too low
+func checkInotify() PreflightCheck {
+	check := PreflightCheck{Name: "inotify"}
+	watches, err := readSysctlInt("/proc/sys/fs/inotify/max_user_watches")
+	if err != nil {
+		check.Status = PreflightSkip
+		check.Message = err.Error()
+		return check
+	}
+	instances, err := readSysctlInt("/proc/sys/fs/inotify/max_user_instances")
+	if err != nil {
+		check.Status = PreflightSkip
+		check.Message = err.Error()
+		return check
+	}
+	check.Message = fmt.Sprintf("max_user_watches %d, max_user_instances %d", watches, instances)
+	check.Status = PreflightPass
+	if watches < minInotifyMaxUserWatches || instances < minInotifyMaxUserInstances {
+		check.Status = PreflightWarn
+		check.Remediation = fmt.Sprintf(
+			"sudo sysctl fs.inotify.max_user_watches=%d fs.inotify.max_user_instances=%d",
+			maxInt(watches, minInotifyMaxUserWatches), maxInt(instances, minInotifyMaxUserInstances),
+		)
+	}
+	return check
+}
+
+// checkMemory checks the available memory for the Kubernetes nodes
+func checkMemory(derived *derivedConfigData) PreflightCheck {
+	check := PreflightCheck{Name: "memory"}
+	available, err := availableMemory()
+	if err != nil {
+		check.Status = PreflightSkip
+		check.Message = err.Error()
+		return check
+	}
+	nodes := len(derived.ControlPlanes()) + len(derived.Workers())
+	required := int64(nodes) * minNodeMemoryBytes
+	check.Message = fmt.Sprintf("%s available, %s recommended for %d nodes", formatBytes(available), formatBytes(required), nodes)
+	check.Status = PreflightPass
+	if available < required {
+		check.Status = PreflightWarn
+		check.Remediation = "free memory, or create fewer nodes"
+	}
+	return check
+}
+
+// checkStorage checks the free disk space for the images and containers
+func checkStorage(info docker.ProviderInfo) PreflightCheck {
+	check := PreflightCheck{Name: "disk"}
+	if info.StorageRoot == "" {
+		check.Status = PreflightSkip
+		check.Message = "the provider storage directory is not known"
+		return check
+	}
+	free, err := freeDiskSpace(info.StorageRoot)
+	if err != nil {
+		check.Status = PreflightSkip
+		check.Message = err.Error()
+		return check
+	}
+	check.Message = fmt.Sprintf("%s free in %s", formatBytes(free), info.StorageRoot)
+	check.Status = PreflightPass
+	if free < minStorageFreeBytes {
+		check.Status = PreflightWarn
+		check.Message += fmt.Sprintf(", %s recommended", formatBytes(minStorageFreeBytes))
+		check.Remediation = "free disk space, e.g. remove unused images and containers"
+	}
+	return check
+}
+
+// checkKernelModules checks the kernel modules the nodes can not load with
+// rootless providers
+func checkKernelModules(provider docker.Provider) PreflightCheck {
+	check := PreflightCheck{Name: "kernel modules", Status: PreflightPass}
+	if !provider.Rootless() {
+		check.Message = "loaded by the nodes as needed"
+		return check
+	}
+	missing := missingKernelModules()
+	if len(missing) == 0 {
+		check.Message = fmt.Sprintf("%v loaded", rootlessKernelModules)
+		return check
+	}
+	check.Status = PreflightWarn
+	check.Message = fmt.Sprintf("%v may not be loaded", missing)
+	check.Remediation = modprobeCommand(missing)
+	return check
+}
+
+// checkGPUs checks that the NVIDIA container toolkit, which passes through
+// the GPUs to the node containers, is installed if any node requests GPUs
+func checkGPUs(derived *derivedConfigData) PreflightCheck {
+	check := PreflightCheck{Name: "gpus", Status: PreflightPass}
+	gpuNodes := 0
+	for _, replica := range derived.AllReplicas() {
+		if replica.Resources.GPUs != "" {
+			gpuNodes++
+		}
+	}
+	if gpuNodes == 0 {
+		check.Message = "no nodes with GPUs"
+		return check
+	}
+	if _, err := osexec.LookPath("nvidia-container-cli"); err != nil {
+		check.Status = PreflightFail
+		check.Message = fmt.Sprintf("%d nodes with GPUs, but the NVIDIA container toolkit is not installed", gpuNodes)
+		check.Remediation = "install the NVIDIA container toolkit, see https://github.com/NVIDIA/nvidia-container-toolkit"
+		return check
+	}
+	check.Message = fmt.Sprintf("NVIDIA container toolkit installed for %d nodes with GPUs", gpuNodes)
+	return check
+}
+
+// checkHostPorts checks that the fixed host ports of the cluster are free,
+// and can be published by rootless providers
+func checkHostPorts(cfg *config.Config, derived *derivedConfigData, provider docker.Provider) PreflightCheck {
+	check := PreflightCheck{Name: "host ports", Status: PreflightPass}
+	ports := hostPorts(cfg, derived)
+	if len(ports) == 0 {
+		check.Message = "no fixed host ports"
+		return check
+	}
+	if provider.Rootless() {
+		if privileged, portStart := privilegedHostPorts(cfg, derived); len(privileged) > 0 {
+			check.Status = PreflightFail
+			check.Message = fmt.Sprintf("%v are below %d, which rootless providers can not publish", privileged, portStart)
+			check.Remediation = unprivilegedPortCommand(privileged)
+			return check
+		}
+	}
+	inUse := []int32{}
+	for _, port := range ports {
+		l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			inUse = append(inUse, port)
+			continue
+		}
+		l.Close()
+	}
+	if len(inUse) > 0 {
+		check.Status = PreflightFail
+		check.Message = fmt.Sprintf("%v are in use", inUse)
+		check.Remediation = "stop the processes using the ports, or publish other ports"
+		return check
+	}
+	check.Message = fmt.Sprintf("%v are free", ports)
+	return check
+}
+
+// readSysctlInt reads an integer sysctl at path
+func readSysctlInt(path string) (int, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(contents)))
+}
+
+// availableMemory returns the memory available on the host, from /proc/meminfo
+func availableMemory() (int64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// e.g. "MemAvailable:    8048576 kB"
+		if len(fields) == 3 && fields[0] == "MemAvailable:" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			return kb * 1024, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("available memory not found in /proc/meminfo")
+}
+
+// freeDiskSpace returns the free disk space of the filesystem of path
+func freeDiskSpace(path string) (int64, error) {
+	lines, err := exec.CombinedOutputLines(exec.Command("df", "-Pk", path))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get the free disk space of %s", path)
+	}
+	// the second line is the filesystem, the fourth field the available kB
+	if len(lines) != 2 {
+		return 0, fmt.Errorf("unexpected df output: %v", lines)
+	}
+	fields := strings.Fields(lines[1])
+	if len(fields) < 4 {
+		return 0, fmt.Errorf("unexpected df output: %v", lines)
+	}
+	kb, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected df output: %v", lines)
+	}
+	return kb * 1024, nil
+}
+
+// formatBytes formats a number of bytes in GiB, or MiB below a GiB
+func formatBytes(bytes int64) string {
+	if bytes < 1<<30 {
+		return fmt.Sprintf("%dMiB", bytes>>20)
+	}
+	return fmt.Sprintf("%.1fGiB", float64(bytes)/(1<<30))
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+)
+
+func TestPreflightPassed(t *testing.T) {
+	cases := []struct {
+		TestName string
+		Checks   []PreflightCheck
+		Expected bool
+	}{
+		{
+			TestName: "no checks",
+			Expected: true,
+		},
+		{
+			TestName: "warnings and skipped checks",
+			Checks: []PreflightCheck{
+				{Name: "provider", Status: PreflightPass},
+				{Name: "inotify", Status: PreflightWarn},
+				{Name: "disk", Status: PreflightSkip},
+			},
+			Expected: true,
+		},
+		{
+			TestName: "failed check",
+			Checks: []PreflightCheck{
+				{Name: "provider", Status: PreflightPass},
+				{Name: "host ports", Status: PreflightFail},
+			},
+			Expected: false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.TestName, func(t *testing.T) {
+			if passed := PreflightPassed(tc.Checks); passed != tc.Expected {
+				t.Errorf("expected passed %t, got %t", tc.Expected, passed)
+			}
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	cases := []struct {
+		Bytes    int64
+		Expected string
+	}{
+		{Bytes: 512 << 20, Expected: "512MiB"},
+		{Bytes: 1 << 30, Expected: "1.0GiB"},
+		{Bytes: 10<<30 + 512<<20, Expected: "10.5GiB"},
+	}
+	for _, tc := range cases {
+		if formatted := formatBytes(tc.Bytes); formatted != tc.Expected {
+			t.Errorf("expected %d bytes formatted as %q, got %q", tc.Bytes, tc.Expected, formatted)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/docker"
+)
+
+// preloadImagesAction implements action for loading the images of the config
+// into the nodes
+type preloadImagesAction struct{}
+
+func init() {
+	registerAction("preload", newPreloadImagesAction)
+}
+
+// newPreloadImagesAction returns a new preloadImagesAction
+func newPreloadImagesAction() action {
+	return &preloadImagesAction{}
+}
+
+// Tasks returns the list of action tasks
+func (b *preloadImagesAction) Tasks() []task {
+	return []task{
+		{
+			// Load the images into each node running pods
+			Description: "Loading images 🖼",
+			TargetNodes: selectKubernetesNodes,
+			Run:         runPreloadImages,
+		},
+	}
+}
+
+// runPreloadImages loads the images of the config into the node, from the
+// archive saved on the host once per execution, see preloadArchive
+func runPreloadImages(ec *execContext, configNode *nodeReplica) error {
+	// get the target node for this task
+	node, ok := ec.NodeFor(configNode)
+	if !ok {
+		return fmt.Errorf("unable to get the handle for operating on node: %s", configNode.Name)
+	}
+
+	archive, images, err := ec.preloadArchive()
+	if err != nil {
+		return err
+	}
+	return loadImageArchiveIntoNode(archive, images, node)
+}
+
+// preloadArchive pulls the images of the config on the host if needed, and
+// saves them to an archive in the temporary directory of the execution, the
+// first time it is called
+func (ec *execContext) preloadArchive() (string, []docker.ArchiveImage, error) {
+	if ec.preloadImages != nil {
+		return ec.preloadImagesArchive, ec.preloadImages, nil
+	}
+	for _, image := range ec.config.PreloadImages {
+		if _, err := docker.PullIfNotPresent(image, 4); err != nil {
+			return "", nil, &ImagePullError{Image: image, Err: err}
+		}
+	}
+	dir, err := ec.TempDir()
+	if err != nil {
+		return "", nil, err
+	}
+	archive := filepath.Join(dir, "preload-images.tar")
+	if err := docker.SaveImages(archive, ec.config.PreloadImages...); err != nil {
+		return "", nil, errors.Wrap(err, "failed to save images")
+	}
+	images, err := docker.GetArchiveImages(archive)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to read image archive")
+	}
+	ec.preloadImagesArchive, ec.preloadImages = archive, images
+	return archive, images, nil
+}
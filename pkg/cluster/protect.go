@@ -0,0 +1,136 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/docker"
+	"sigs.k8s.io/kind/pkg/fs"
+)
+
+// protectedMarkerPath is the file on the nodes recording if a cluster is
+// protected
+// NOTE: docker does not support changing the labels of existing containers,
+// so unlike other cluster metadata this is recorded as a file on the nodes,
+// which is written and read with docker cp so that this also works for
+// stopped and paused nodes. Unprotect overwrites the file, as docker cp can
+// not remove it
+const protectedMarkerPath = "/kind/protected"
+
+// Protect marks the cluster as protected, so that Delete will refuse to
+// delete it, see also Unprotect and ForceDelete
+func (c *Context) Protect() error {
+	return c.writeProtectedMarker("protected")
+}
+
+// Unprotect removes the protection added by Protect
+func (c *Context) Unprotect() error {
+	return c.writeProtectedMarker("unprotected")
+}
+
+// writeProtectedMarker records the protection state on all the nodes
+func (c *Context) writeProtectedMarker(state string) error {
+	n, err := c.markerNodes()
+	if err != nil {
+		return err
+	}
+	if len(n) == 0 {
+		return fmt.Errorf("no nodes found for cluster %q", c.Name())
+	}
+
+	// create the marker locally, recording when the state was changed
+	dir, err := fs.TempDir("", "")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	marker := filepath.Join(dir, "protected")
+	contents := fmt.Sprintf("%s at %s\n", state, time.Now().UTC().Format(time.RFC3339))
+	if err := ioutil.WriteFile(marker, []byte(contents), 0644); err != nil {
+		return err
+	}
+
+	// and copy it to all the nodes
+	for _, node := range n {
+		if err := node.CopyTo(marker, protectedMarkerPath); err != nil {
+			return errors.Wrapf(err, "failed to mark node %s as %s", node.String(), state)
+		}
+	}
+	return nil
+}
+
+// IsProtected returns true if the cluster has been marked as protected, and
+// an error if this can not be determined for any of the nodes
+func (c *Context) IsProtected() (bool, error) {
+	n, err := c.markerNodes()
+	if err != nil {
+		return false, err
+	}
+
+	// the cluster is protected if any of the nodes has a protected marker
+	for _, node := range n {
+		exists, err := node.FileExists(protectedMarkerPath)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to check if node %s is protected", node.String())
+		}
+		if !exists {
+			continue
+		}
+		contents, err := docker.ReadFile(node.String(), protectedMarkerPath)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to check if node %s is protected", node.String())
+		}
+		if strings.HasPrefix(string(contents), "protected ") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// markerNodes returns the nodes recording the protection state, these are
+// the Linux control-plane and worker nodes, as the external load balancer
+// and etcd images and the Windows node image have no /kind directory
+func (c *Context) markerNodes() ([]nodes.Node, error) {
+	n, err := c.ListNodes()
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %v", err)
+	}
+	markerNodes := []nodes.Node{}
+	for _, node := range n {
+		role, err := node.Role()
+		if err != nil {
+			return nil, err
+		}
+		switch config.NodeRole(role) {
+		case config.ControlPlaneRole, config.WorkerRole:
+			if !node.IsWindows() {
+				markerNodes = append(markerNodes, node)
+			}
+		}
+	}
+	return markerNodes, nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/config/encoding"
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/version"
+)
+
+// Provenance is the metadata recorded on a node container about where it
+// comes from, see NodeProvenance. This allows tools to tell apart the nodes
+// of the clusters sharing a host, e.g. to garbage collect them.
+// The fields other than Cluster and Role are empty for the nodes created by
+// earlier versions of kind
+type Provenance struct {
+	// Cluster is the cluster context name
+	Cluster string `json:"cluster"`
+	// Role is the node role
+	Role config.NodeRole `json:"role"`
+	// ReplicaIndex is the index of the node among the nodes with the same
+	// role, starting at 1
+	ReplicaIndex int `json:"replicaIndex,omitempty"`
+	// ConfigHash is the digest of the config the cluster was created from,
+	// see ConfigHash
+	ConfigHash string `json:"configHash,omitempty"`
+	// KindVersion is the version of kind that created the node
+	KindVersion string `json:"kindVersion,omitempty"`
+	// ImageDigest is the digest of the image the node was created from
+	ImageDigest string `json:"imageDigest,omitempty"`
+}
+
+// ConfigHash returns the SHA-256 digest of cfg, encoded in the latest config
+// API version, as recorded on the nodes of the clusters created from cfg.
+// This allows checking whether a cluster was created from a config
+func ConfigHash(cfg *config.Config) (string, error) {
+	encoded, err := encoding.Encode(cfg)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode config")
+	}
+	sum := sha256.Sum256(encoded)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// NodeProvenance returns the provenance recorded on the node container
+func NodeProvenance(node *nodes.Node) (*Provenance, error) {
+	p := &Provenance{}
+	var err error
+	if p.Cluster, err = node.Label(consts.ClusterLabelKey); err != nil {
+		return nil, err
+	}
+	role, err := node.Role()
+	if err != nil {
+		return nil, err
+	}
+	p.Role = config.NodeRole(role)
+	index, err := node.Label(consts.ReplicaIndexKey)
+	if err != nil {
+		return nil, err
+	}
+	if index != "" {
+		if p.ReplicaIndex, err = strconv.Atoi(index); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s label", consts.ReplicaIndexKey)
+		}
+	}
+	if p.ConfigHash, err = node.Label(consts.ConfigHashKey); err != nil {
+		return nil, err
+	}
+	if p.KindVersion, err = node.Label(consts.KindVersionKey); err != nil {
+		return nil, err
+	}
+	if p.ImageDigest, err = node.Label(consts.NodeImageDigestKey); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Provenance returns the provenance recorded on the node containers of the
+// cluster, by node container name
+func (c *Context) Provenance() (map[string]*Provenance, error) {
+	n, err := c.ListNodes()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+	provenance := map[string]*Provenance{}
+	for i := range n {
+		p, err := NodeProvenance(&n[i])
+		if err != nil {
+			return nil, err
+		}
+		provenance[n[i].String()] = p
+	}
+	return provenance, nil
+}
+
+// provenanceLabels returns the docker object labels recording the provenance
+// of the node container implementing replica, in addition to the cluster and
+// role labels, see Provenance. The config hash and the image digest are
+// left out if empty
+func provenanceLabels(configHash, imageDigest string, derived *derivedConfigData, replica *nodeReplica) []string {
+	labels := []string{
+		fmt.Sprintf("%s=%d", consts.ReplicaIndexKey, derived.replicaIndex(replica)),
+		fmt.Sprintf("%s=%s", consts.KindVersionKey, version.Version),
+	}
+	if configHash != "" {
+		labels = append(labels, fmt.Sprintf("%s=%s", consts.ConfigHashKey, configHash))
+	}
+	if imageDigest != "" {
+		labels = append(labels, fmt.Sprintf("%s=%s", consts.NodeImageDigestKey, imageDigest))
+	}
+	return labels
+}
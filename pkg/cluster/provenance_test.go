@@ -0,0 +1,116 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	utilpointer "k8s.io/utils/pointer"
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/version"
+)
+
+func TestConfigHash(t *testing.T) {
+	cfg := &config.Config{
+		Nodes: []config.Node{
+			{Role: config.ControlPlaneRole, Image: "node:a"},
+		},
+	}
+	hash, err := ConfigHash(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error while hashing config: %v", err)
+	}
+	if !strings.HasPrefix(hash, "sha256:") || len(hash) != len("sha256:")+64 {
+		t.Errorf("expected a sha256 digest, got %q", hash)
+	}
+	again, err := ConfigHash(cfg.DeepCopy())
+	if err != nil {
+		t.Fatalf("unexpected error while hashing config: %v", err)
+	}
+	if again != hash {
+		t.Errorf("expected the same config to hash to %q, got %q", hash, again)
+	}
+	cfg.Nodes[0].Image = "node:b"
+	changed, err := ConfigHash(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error while hashing config: %v", err)
+	}
+	if changed == hash {
+		t.Errorf("expected a different config to hash differently, got %q", changed)
+	}
+}
+
+func TestProvenanceLabels(t *testing.T) {
+	derived, err := deriveInfo(&config.Config{
+		Nodes: []config.Node{
+			{Role: config.ExternalLoadBalancerRole},
+			{Role: config.ControlPlaneRole, Replicas: utilpointer.Int32Ptr(2)},
+			{Role: config.WorkerRole},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error while deriving info: %v", err)
+	}
+	cases := []struct {
+		TestName     string
+		Replica      *nodeReplica
+		ConfigHash   string
+		ImageDigest  string
+		ExpectLabels []string
+	}{
+		{
+			TestName:    "Second control plane",
+			Replica:     derived.ControlPlanes()[1],
+			ConfigHash:  "sha256:aaaa",
+			ImageDigest: "sha256:bbbb",
+			ExpectLabels: []string{
+				"io.k8s.sigs.kind.replica=2",
+				"io.k8s.sigs.kind.version=" + version.Version,
+				"io.k8s.sigs.kind.config-hash=sha256:aaaa",
+				"io.k8s.sigs.kind.image-digest=sha256:bbbb",
+			},
+		},
+		{
+			TestName: "Load balancer without config hash or image digest",
+			Replica:  derived.ExternalLoadBalancer(),
+			ExpectLabels: []string{
+				"io.k8s.sigs.kind.replica=1",
+				"io.k8s.sigs.kind.version=" + version.Version,
+			},
+		},
+		{
+			TestName:   "Single worker",
+			Replica:    derived.Workers()[0],
+			ConfigHash: "sha256:aaaa",
+			ExpectLabels: []string{
+				"io.k8s.sigs.kind.replica=1",
+				"io.k8s.sigs.kind.version=" + version.Version,
+				"io.k8s.sigs.kind.config-hash=sha256:aaaa",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.TestName, func(t *testing.T) {
+			labels := provenanceLabels(c.ConfigHash, c.ImageDigest, derived, c.Replica)
+			if !reflect.DeepEqual(labels, c.ExpectLabels) {
+				t.Errorf("expected labels %v, got %v", c.ExpectLabels, labels)
+			}
+		})
+	}
+}
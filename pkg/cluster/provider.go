@@ -0,0 +1,58 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"net"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/docker"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// selectProvider selects the provider the cluster is configured with, if
+// any, and validates that the provider can run the cluster, e.g. that the
+// API server is reachable when the provider runs the nodes on a remote host
+func selectProvider(cfg *config.Config, derived *derivedConfigData) error {
+	if cfg.Provider != "" {
+		if err := docker.SetProvider(string(cfg.Provider)); err != nil {
+			return err
+		}
+	}
+	provider := docker.CurrentProvider()
+
+	// the loopback addresses of a remote host are not reachable from here
+	if host := provider.RemoteHost(); host != "" {
+		if ip := net.ParseIP(cfg.Networking.APIServerAddress); ip != nil && ip.IsLoopback() {
+			return fmt.Errorf("the API server can not be reached at %s on the remote host %s, unset networking.apiServerAddress", ip, host)
+		}
+		for _, configNode := range derived.AllReplicas() {
+			for _, pm := range configNode.ExtraPortMappings {
+				if ip := net.ParseIP(pm.ListenAddress); ip != nil && ip.IsLoopback() {
+					logutil.Warnf("The port %d of node %s is published on %s on the remote host %s, it is not reachable from here", pm.ContainerPort, configNode.Name, ip, host)
+				}
+			}
+		}
+	}
+
+	// the host prerequisites can only be checked for local providers
+	if provider.Rootless() && provider.RemoteHost() == "" {
+		return validateRootless(cfg, derived, provider)
+	}
+	return nil
+}
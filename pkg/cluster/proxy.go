@@ -0,0 +1,142 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"os"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+)
+
+// proxyEnv returns the proxy environment variables of the node containers,
+// of the form "KEY=value", see nodes.ProxyEnvKeys. The proxies of the config
+// are used if set, the ones of the host environment otherwise. NO_PROXY is
+// extended with the nodes, the local registry, the docker network and the
+// cluster subnets, so that the cluster components reach each other without
+// the proxy
+func (c *Context) proxyEnv(cfg *config.Config, d *derivedConfigData, network string) ([]string, error) {
+	httpProxy := cfg.Proxy.HTTPProxy
+	if httpProxy == "" {
+		httpProxy = hostEnv("HTTP_PROXY")
+	}
+	httpsProxy := cfg.Proxy.HTTPSProxy
+	if httpsProxy == "" {
+		httpsProxy = hostEnv("HTTPS_PROXY")
+	}
+	if httpProxy == "" && httpsProxy == "" {
+		return nil, nil
+	}
+
+	noProxy := cfg.Proxy.NoProxy
+	if noProxy == "" {
+		noProxy = hostEnv("NO_PROXY")
+	}
+	noProxyList := []string{}
+	if noProxy != "" {
+		noProxyList = strings.Split(noProxy, ",")
+	}
+	noProxyList = append(noProxyList, "localhost", "127.0.0.1", "::1", ".svc", ".svc.cluster.local")
+	for _, replica := range d.AllReplicas() {
+		noProxyList = append(noProxyList, c.nodeContainerName(replica.Name))
+	}
+	if cfg.Registry.Enabled {
+		noProxyList = append(noProxyList, c.registryName())
+	}
+	if network != "" {
+		dockerNetwork, err := inspectNetwork(network)
+		if err != nil {
+			return nil, err
+		}
+		noProxyList = append(noProxyList, dockerNetwork.Subnet, dockerNetwork.IPv6Subnet)
+	}
+	noProxyList = append(noProxyList, clusterSubnets(&cfg.Networking)...)
+
+	env := []string{}
+	for _, proxy := range []struct{ key, value string }{
+		{"HTTP_PROXY", httpProxy},
+		{"HTTPS_PROXY", httpsProxy},
+		{"NO_PROXY", strings.Join(uniqueNonEmpty(noProxyList), ",")},
+	} {
+		if proxy.value == "" {
+			continue
+		}
+		env = append(env,
+			proxy.key+"="+proxy.value,
+			strings.ToLower(proxy.key)+"="+proxy.value,
+		)
+	}
+	return env, nil
+}
+
+// hostEnv returns the value of the host environment variable key, or of the
+// lower case variant if not set
+func hostEnv(key string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return os.Getenv(strings.ToLower(key))
+}
+
+// clusterSubnets returns the pod and service subnets of the cluster, these
+// are the defaults for the IP family unless configured
+func clusterSubnets(networking *config.Networking) []string {
+	podSubnet, serviceSubnet := networking.PodSubnet, networking.ServiceSubnet
+	switch networking.IPFamily {
+	case config.IPv6Family:
+		if podSubnet == "" {
+			podSubnet = kubeadm.IPv6PodSubnet
+		}
+		if serviceSubnet == "" {
+			serviceSubnet = kubeadm.IPv6ServiceSubnet
+		}
+	case config.DualStackFamily:
+		if podSubnet == "" {
+			podSubnet = kubeadm.DualStackPodSubnet
+		}
+	default:
+		if podSubnet == "" && !networking.DisableDefaultCNI {
+			podSubnet = defaultCNIPodSubnet
+		}
+	}
+	if serviceSubnet == "" {
+		serviceSubnet = kubeadm.ServiceSubnet
+	}
+	subnets := []string{}
+	for _, subnet := range []string{podSubnet, serviceSubnet} {
+		if subnet != "" {
+			subnets = append(subnets, strings.Split(subnet, ",")...)
+		}
+	}
+	return subnets
+}
+
+// uniqueNonEmpty returns the non empty values, without duplicates, in order
+func uniqueNonEmpty(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		unique = append(unique, value)
+	}
+	return unique
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestProxyEnv(t *testing.T) {
+	// the proxies of the host environment are used if not configured
+	for _, key := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"} {
+		if value, ok := os.LookupEnv(key); ok {
+			os.Unsetenv(key)
+			defer os.Setenv(key, value)
+		}
+	}
+
+	cases := []struct {
+		TestName  string
+		Config    config.Config
+		ExpectEnv []string
+	}{
+		{
+			TestName: "no proxy",
+			Config: config.Config{
+				Proxy: config.Proxy{NoProxy: "example.com"},
+			},
+			ExpectEnv: nil,
+		},
+		{
+			TestName: "HTTP proxy",
+			Config: config.Config{
+				Proxy: config.Proxy{
+					HTTPProxy:  "http://proxy.example.com:3128",
+					HTTPSProxy: "http://proxy.example.com:3129",
+					NoProxy:    "example.com,localhost",
+				},
+			},
+			ExpectEnv: []string{
+				"HTTP_PROXY=http://proxy.example.com:3128",
+				"http_proxy=http://proxy.example.com:3128",
+				"HTTPS_PROXY=http://proxy.example.com:3129",
+				"https_proxy=http://proxy.example.com:3129",
+				"NO_PROXY=example.com,localhost,127.0.0.1,::1,.svc,.svc.cluster.local,kind-test-control-plane,10.32.0.0/12,10.96.0.0/12",
+				"no_proxy=example.com,localhost,127.0.0.1,::1,.svc,.svc.cluster.local,kind-test-control-plane,10.32.0.0/12,10.96.0.0/12",
+			},
+		},
+		{
+			TestName: "dual-stack",
+			Config: config.Config{
+				Networking: config.Networking{
+					IPFamily:      config.DualStackFamily,
+					ServiceSubnet: "10.96.0.0/16",
+				},
+				Proxy: config.Proxy{
+					HTTPSProxy: "http://proxy.example.com:3128",
+					NoProxy:    "example.com",
+				},
+			},
+			ExpectEnv: []string{
+				"HTTPS_PROXY=http://proxy.example.com:3128",
+				"https_proxy=http://proxy.example.com:3128",
+				"NO_PROXY=example.com,localhost,127.0.0.1,::1,.svc,.svc.cluster.local,kind-test-control-plane,10.244.0.0/16,fd00:10:244::/64,10.96.0.0/16",
+				"no_proxy=example.com,localhost,127.0.0.1,::1,.svc,.svc.cluster.local,kind-test-control-plane,10.244.0.0/16,fd00:10:244::/64,10.96.0.0/16",
+			},
+		},
+		{
+			TestName: "local registry",
+			Config: config.Config{
+				Proxy:    config.Proxy{HTTPProxy: "http://proxy.example.com:3128"},
+				Registry: config.Registry{Enabled: true},
+			},
+			ExpectEnv: []string{
+				"HTTP_PROXY=http://proxy.example.com:3128",
+				"http_proxy=http://proxy.example.com:3128",
+				"NO_PROXY=localhost,127.0.0.1,::1,.svc,.svc.cluster.local,kind-test-control-plane,kind-test-registry,10.32.0.0/12,10.96.0.0/12",
+				"no_proxy=localhost,127.0.0.1,::1,.svc,.svc.cluster.local,kind-test-control-plane,kind-test-registry,10.32.0.0/12,10.96.0.0/12",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.TestName, func(t *testing.T) {
+			cfg := tc.Config
+			cfg.Nodes = []config.Node{{Role: config.ControlPlaneRole, Image: "node"}}
+			d, err := deriveInfo(&cfg)
+			if err != nil {
+				t.Fatalf("unexpected error deriving info: %v", err)
+			}
+			env, err := NewContext("test").proxyEnv(&cfg, d, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(env, tc.ExpectEnv) {
+				t.Errorf("expected env %v but got %v", tc.ExpectEnv, env)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+// Recreate deletes the cluster nodes and creates the cluster again from cfg,
+// preserving the cluster expiry (see Expiry)
+// If keepVolumes is set the node data volumes (see nodeDataPath) are not
+// deleted, and are re-attached to the new nodes with the same names, so that
+// the data of the PersistentVolumes provisioned on the nodes survives
+// If the cluster was merged into the default kubeconfig (see MergeKubeConfig)
+// it is updated there with the new API server port and certificates
+// Protected clusters (see Protect) are not recreated, nor are the clusters of
+// other owners if OwnerEnv is set (see Owner)
+func (c *Context) Recreate(cfg *config.Config, keepVolumes bool, wait time.Duration) error {
+	// validate before deleting anything, so that an invalid config
+	// does not leave us without a cluster
+	if _, err := validateForCreate(cfg); err != nil {
+		return err
+	}
+
+	protected, err := c.IsProtected()
+	if err != nil {
+		return err
+	}
+	if protected {
+		return fmt.Errorf("cluster %q is protected, unprotect it before recreating it", c.Name())
+	}
+	if err := c.checkOwner(); err != nil {
+		return err
+	}
+
+	// the expiry is the zero time if the cluster does not expire
+	expiry, _, err := c.Expiry()
+	if err != nil {
+		return err
+	}
+
+	if err := c.deleteNodes(keepVolumes); err != nil {
+		return errors.Wrap(err, "failed to delete cluster nodes")
+	}
+
+	if err := c.create(cfg, false, keepVolumes, wait, expiry, nil); err != nil {
+		return err
+	}
+	c.updateMergedKubeConfig()
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+	"sigs.k8s.io/kind/pkg/docker"
+)
+
+// registryImage is the image of the local registry container
+const registryImage = "registry:2"
+
+// registryPort is the port the local registry listens on in the docker
+// network of the cluster
+const registryPort = 5000
+
+// registryName returns the name of the local registry container of the
+// cluster, this is also its host name in the docker network of the cluster
+func (c *Context) registryName() string {
+	return c.nodeContainerName("registry")
+}
+
+// registryAddress returns the address of the local registry in the docker
+// network of the cluster, the nodes pull images from it at this address
+func (c *Context) registryAddress() string {
+	return fmt.Sprintf("%s:%d", c.registryName(), registryPort)
+}
+
+// registryLabel returns the docker object label recording the address of
+// the local registry on the nodes
+func (c *Context) registryLabel() string {
+	return fmt.Sprintf("%s=%s", consts.RegistryKey, c.registryAddress())
+}
+
+// registryClusterLabel returns the docker object label of the local registry
+// container of the cluster
+func (c *Context) registryClusterLabel() string {
+	return fmt.Sprintf("%s=%s", consts.RegistryClusterKey, c.Name())
+}
+
+// ensureRegistry starts the local registry container of the cluster in the
+// given docker network, unless it already exists, publishing it on the host
+// loopback address. The registry is deleted along with the cluster, see
+// deleteRegistry
+func (c *Context) ensureRegistry(registry *config.Registry, network string) error {
+	existing, err := docker.ListContainers("label=" + c.registryClusterLabel())
+	if err != nil {
+		return errors.Wrap(err, "failed to list registry containers")
+	}
+	if len(existing) > 0 {
+		return docker.Start(existing...)
+	}
+	hostPort := registry.HostPort
+	if hostPort == 0 {
+		hostPort = config.DefaultRegistryHostPort
+	}
+	if _, err := docker.Run(
+		registryImage,
+		[]string{
+			"-d", // run the registry in the background
+			"--name", c.registryName(),
+			"--network", network,
+			"--publish", fmt.Sprintf("127.0.0.1:%d:%d", hostPort, registryPort),
+			"--label", c.registryClusterLabel(),
+		},
+		[]string{},
+	); err != nil {
+		return errors.Wrap(err, "failed to start the local registry")
+	}
+	return nil
+}
+
+// deleteRegistry deletes the local registry container of the cluster, if any,
+// along with the pushed images
+func (c *Context) deleteRegistry() error {
+	containers, err := docker.ListContainers("label=" + c.registryClusterLabel())
+	if err != nil {
+		return errors.Wrap(err, "failed to list registry containers")
+	}
+	if len(containers) == 0 {
+		return nil
+	}
+	return docker.Delete(containers...)
+}
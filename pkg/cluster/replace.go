@@ -0,0 +1,250 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/docker"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// ContextForNode returns the Context for the cluster owning the node
+// container with the given name
+func ContextForNode(name string) (*Context, error) {
+	n, err := nodes.ListByCluster()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not list clusters, failed to list nodes")
+	}
+	for cluster, clusterNodes := range n {
+		for _, node := range clusterNodes {
+			if node.String() == name {
+				return NewContext(cluster), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no node named %q found", name)
+}
+
+// ReplaceNode deletes the node container with the given name, creates a fresh
+// one with the same name, role and image, and joins it to the cluster again.
+// Only worker nodes can currently be replaced.
+func (c *Context) ReplaceNode(name string) error {
+	n, err := c.ListNodes()
+	if err != nil {
+		return fmt.Errorf("error listing nodes: %v", err)
+	}
+	cfg, derived, nodeList, err := c.deriveInfoFromNodes(n)
+	if err != nil {
+		return err
+	}
+
+	// find the replica implemented by the node to replace
+	var replica *nodeReplica
+	for _, r := range derived.AllReplicas() {
+		if c.nodeContainerName(r.Name) == name {
+			replica = r
+		}
+	}
+	if replica == nil {
+		return fmt.Errorf("no node named %q found in cluster %q", name, c.Name())
+	}
+	if replica.Role != config.WorkerRole {
+		return fmt.Errorf("replacing nodes with role %q is not supported, only %q nodes can be replaced", replica.Role, config.WorkerRole)
+	}
+	if derived.BootStrapControlPlane() == nil {
+		return fmt.Errorf("unable to find the control plane for cluster %q", c.Name())
+	}
+	controlPlane := nodeList[derived.BootStrapControlPlane().Name]
+
+	fmt.Printf("Replacing node '%s' ...\n", name)
+
+	status := logutil.NewStatus(os.Stdout)
+	status.MaybeWrapLogger(c.Logger())
+	defer status.End(false)
+
+	// the Kubernetes node will register again once the new node joins
+	status.Start(fmt.Sprintf("[%s] Removing node from Kubernetes ☸", replica.Name))
+	if err := controlPlane.Command(
+		"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf",
+		"delete", "node", name, "--ignore-not-found",
+	).Run(); err != nil {
+		return errors.Wrap(err, "failed to delete node from Kubernetes")
+	}
+
+	// the bootstrap token used for joining expires, see refreshBootstrapToken
+	status.Start(fmt.Sprintf("[%s] Refreshing the bootstrap token 🔑", derived.BootStrapControlPlane().Name))
+	if err := refreshBootstrapToken(controlPlane); err != nil {
+		return err
+	}
+
+	// preserve the cluster expiry and owner, if any, and the config labels
+	extraLabels := []string{}
+	expiry, ok, err := c.Expiry()
+	if err != nil {
+		return err
+	}
+	if ok {
+		extraLabels = append(extraLabels, expiryLabel(expiry))
+	}
+	owner, err := c.Owner()
+	if err != nil {
+		return err
+	}
+	if owner != "" {
+		extraLabels = append(extraLabels, ownerLabel(owner))
+	}
+	extraLabels = append(extraLabels, configLabels(cfg)...)
+	if cfg.Registry.Enabled {
+		extraLabels = append(extraLabels, c.registryLabel())
+	}
+	if replica.IngressReady {
+		extraLabels = append(extraLabels, fmt.Sprintf("%s=true", consts.IngressReadyKey))
+	}
+
+	// the new node keeps the config hash of the cluster, the kind version
+	// and the image digest are the ones it is created with
+	configHash, err := nodeList[replica.Name].Label(consts.ConfigHashKey)
+	if err != nil {
+		return err
+	}
+	imageDigest, _ := docker.ImageDigest(replica.Image)
+	extraLabels = append(extraLabels, provenanceLabels(configHash, imageDigest, derived, replica)...)
+
+	// preserve the proxy environment variables of the node, if any
+	env, err := nodeList[replica.Name].ProxyEnv()
+	if err != nil {
+		return err
+	}
+
+	// preserve the kubeadm config of the node, the patches from the original
+	// config are not otherwise available, if the node is not running the
+	// kubeadm config is generated again without patches instead
+	var kubeadmConfig []byte
+	if !replica.IsWindows() {
+		kubeadmConfig, err = nodeList[replica.Name].ReadFile(kubeadmConfigPath)
+		if err != nil {
+			c.Logger().Warnf("Failed to read the kubeadm config from node %s, kubeadm config patches will not be applied: %v", name, err)
+		}
+	}
+
+	// the image registry config is the same for all the nodes
+	registryConfig, err := readImageRegistryConfig(controlPlane)
+	if err != nil {
+		return err
+	}
+
+	status.Start(fmt.Sprintf("[%s] Deleting node container 🔥", replica.Name))
+	if err := nodes.Delete(*nodeList[replica.Name]); err != nil {
+		return errors.Wrap(err, "failed to delete node container")
+	}
+
+	status.Start(fmt.Sprintf("[%s] Creating node container 📦", replica.Name))
+	network := nodes.Network{
+		Name: cfg.Networking.DockerNetwork.Name,
+		IP:   replica.IPAddress,
+		IPv6: replica.IPv6Address,
+		DNS:  cfg.Networking.DNS,
+	}
+	if replica.IsWindows() {
+		// the Windows node image is not configured by kind, see provisionNodes
+		node, err := nodes.CreateWindowsWorkerNode(name, replica.Image, c.ClusterLabel(), network, replica.ExtraPortMappings, env, extraLabels...)
+		if err != nil {
+			return err
+		}
+		nodeList[replica.Name] = node
+		status.End(true)
+		return c.exec(cfg, derived, nodeList, []string{"join"}, replica.Name)
+	}
+	node, err := nodes.CreateWorkerNode(name, replica.Image, c.ClusterLabel(), network, nodeOptions(cfg, replica, env, extraLabels))
+	if err != nil {
+		return err
+	}
+	nodeList[replica.Name] = node
+
+	if err := registryConfig.Write(node); err != nil {
+		return err
+	}
+	if err := bootNode(status, node); err != nil {
+		return err
+	}
+	if cfg.ImageStore.Volume != "" {
+		if err := node.RemoveContainers(); err != nil {
+			return errors.Wrap(err, "failed to remove the containers of the replaced node from the image store")
+		}
+	}
+
+	status.Start(fmt.Sprintf("[%s] Pre-loading images 🐋", replica.Name))
+	node.LoadImages()
+	status.End(true)
+
+	// run only the config and join tasks for the replaced node, restoring
+	// the preserved kubeadm config instead of running the config tasks, and
+	// the preload tasks if there are images to preload
+	actions := []string{"config", "join"}
+	if kubeadmConfig != nil {
+		if err := restoreKubeadmConfig(derived, replica, node, kubeadmConfig); err != nil {
+			return err
+		}
+		actions = []string{"join"}
+	}
+	if len(cfg.PreloadImages) > 0 {
+		actions = append([]string{"preload"}, actions...)
+	}
+	// only workers are replaced, which are not backends of the external
+	// load balancer, so it does not need to be reconfigured
+	return c.exec(cfg, derived, nodeList, actions, replica.Name)
+}
+
+// refreshBootstrapToken recreates the well known bootstrap token the nodes
+// join with on the control plane, kubeadm creates it with a 24h TTL so
+// nodes could not otherwise join clusters older than that
+func refreshBootstrapToken(controlPlane *nodes.Node) error {
+	// the token is removed by kubeadm once expired, so it may not exist
+	_ = controlPlane.Command("kubeadm", "token", "delete", kubeadm.Token).Run()
+	if err := controlPlane.Command("kubeadm", "token", "create", kubeadm.Token).Run(); err != nil {
+		return errors.Wrap(err, "failed to create the bootstrap token")
+	}
+	return nil
+}
+
+// restoreKubeadmConfig writes the kubeadm config preserved from a replaced
+// node to the new node, recording the config tasks as completed
+func restoreKubeadmConfig(derived *derivedConfigData, replica *nodeReplica, node *nodes.Node, kubeadmConfig []byte) error {
+	if err := node.WriteFile(kubeadmConfigPath, kubeadmConfig); err != nil {
+		return errors.Wrap(err, "failed to copy kubeadm config to node")
+	}
+	plan, err := newExecutionPlan(derived, []string{"config"})
+	if err != nil {
+		return err
+	}
+	for _, plannedTask := range plan {
+		if plannedTask.Node.Name == replica.Name {
+			if err := node.RecordCompletedTask(plannedTask.Key()); err != nil {
+				logutil.Warnf("Failed to record completed task on node %s: %v", node.String(), err)
+			}
+		}
+	}
+	return nil
+}
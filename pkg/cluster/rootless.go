@@ -0,0 +1,170 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/docker"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// unprivilegedPortStartPath is the sysctl of the lowest port unprivileged
+// users can bind to, which rootless providers publish host ports as
+const unprivilegedPortStartPath = "/proc/sys/net/ipv4/ip_unprivileged_port_start"
+
+// rootlessCgroupControllers are the cgroup v2 controllers that must be
+// delegated to the user running a rootless provider, for the resources of
+// the node containers and of the containers in the nodes
+var rootlessCgroupControllers = []string{"cpu", "memory", "pids"}
+
+// rootlessKernelModules are the kernel modules the nodes need, which can not
+// be loaded from the user namespace of a rootless provider
+var rootlessKernelModules = []string{"ip_tables", "iptable_nat", "br_netfilter"}
+
+// validateRootless returns an error if the host lacks a prerequisite of
+// running the cluster with a rootless provider, explaining how to fix it
+func validateRootless(cfg *config.Config, derived *derivedConfigData, provider docker.Provider) error {
+	if err := validateCgroupDelegation(os.Getuid()); err != nil {
+		return err
+	}
+	if missing := missingKernelModules(); len(missing) > 0 {
+		logutil.Warnf("The kernel modules %v may not be loaded, the nodes can not load them with the rootless %s provider, load them with: %s", missing, provider.Name(), modprobeCommand(missing))
+	}
+	// rootless providers can not publish privileged host ports
+	if ports, portStart := privilegedHostPorts(cfg, derived); len(ports) > 0 {
+		return fmt.Errorf("host port %d can not be published by the rootless %s provider, ports below %d are privileged, allow publishing them with: %s", ports[0], provider.Name(), portStart, unprivilegedPortCommand(ports))
+	}
+	return nil
+}
+
+// missingKernelModules returns the rootlessKernelModules not loaded on the host
+func missingKernelModules() []string {
+	missing := []string{}
+	for _, module := range rootlessKernelModules {
+		if _, err := os.Stat("/sys/module/" + module); err != nil {
+			missing = append(missing, module)
+		}
+	}
+	return missing
+}
+
+// modprobeCommand returns the command loading the kernel modules
+func modprobeCommand(modules []string) string {
+	return "sudo modprobe -a " + strings.Join(modules, " ")
+}
+
+// privilegedHostPorts returns the host ports of the cluster below the lowest
+// port unprivileged users can bind to, in order, and the lowest port
+func privilegedHostPorts(cfg *config.Config, derived *derivedConfigData) (ports []int32, portStart int32) {
+	portStart = 1024
+	if contents, err := ioutil.ReadFile(unprivilegedPortStartPath); err == nil {
+		if port, err := strconv.Atoi(strings.TrimSpace(string(contents))); err == nil {
+			portStart = int32(port)
+		}
+	}
+	for _, port := range hostPorts(cfg, derived) {
+		if port < portStart {
+			ports = append(ports, port)
+		}
+	}
+	return ports, portStart
+}
+
+// unprivilegedPortCommand returns the command allowing unprivileged users
+// to bind to the ports
+func unprivilegedPortCommand(ports []int32) string {
+	lowest := ports[0]
+	for _, port := range ports {
+		if port < lowest {
+			lowest = port
+		}
+	}
+	return fmt.Sprintf("sudo sysctl net.ipv4.ip_unprivileged_port_start=%d", lowest)
+}
+
+// hostPorts returns the fixed host ports the cluster publishes
+func hostPorts(cfg *config.Config, derived *derivedConfigData) []int32 {
+	ports := []int32{}
+	if cfg.Networking.APIServerPort != 0 {
+		ports = append(ports, cfg.Networking.APIServerPort)
+	}
+	if cfg.Registry.Enabled {
+		port := cfg.Registry.HostPort
+		if port == 0 {
+			port = config.DefaultRegistryHostPort
+		}
+		ports = append(ports, port)
+	}
+	for _, configNode := range derived.AllReplicas() {
+		for _, pm := range nodePortMappings(configNode) {
+			if pm.HostPort != 0 {
+				ports = append(ports, pm.HostPort)
+			}
+		}
+	}
+	return ports
+}
+
+// cgroupV2 returns true if the host uses the unified cgroup v2 hierarchy
+func cgroupV2() bool {
+	_, err := os.Stat("/sys/fs/cgroup/cgroup.controllers")
+	return err == nil
+}
+
+// cgroupDelegationCommand delegates all the cgroup controllers to the users
+const cgroupDelegationCommand = `sudo mkdir -p /etc/systemd/system/user@.service.d && printf "[Service]\nDelegate=yes\n" | sudo tee /etc/systemd/system/user@.service.d/delegate.conf && sudo systemctl daemon-reload, and log in again`
+
+// validateCgroupDelegation returns an error unless the host uses cgroup v2
+// and the rootlessCgroupControllers are delegated to the user with uid
+func validateCgroupDelegation(uid int) error {
+	if !cgroupV2() {
+		return fmt.Errorf("rootless providers require cgroup v2, boot the host with the kernel parameter systemd.unified_cgroup_hierarchy=1")
+	}
+	path := fmt.Sprintf("/sys/fs/cgroup/user.slice/user-%d.slice/user@%d.service/cgroup.controllers", uid, uid)
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		// the delegation can only be checked on hosts running systemd
+		logutil.Warnf("Failed to check the cgroup controllers delegated to user %d: %v", uid, err)
+		return nil
+	}
+	if missing := missingCgroupControllers(string(contents), rootlessCgroupControllers); len(missing) > 0 {
+		return fmt.Errorf("the cgroup controllers %v are not delegated to user %d, which rootless providers require, delegate them with: %s", missing, uid, cgroupDelegationCommand)
+	}
+	return nil
+}
+
+// missingCgroupControllers returns the required cgroup controllers missing
+// from the controllers, the contents of a cgroup.controllers file
+func missingCgroupControllers(controllers string, required []string) []string {
+	available := map[string]bool{}
+	for _, controller := range strings.Fields(controllers) {
+		available[controller] = true
+	}
+	missing := []string{}
+	for _, controller := range required {
+		if !available[controller] {
+			missing = append(missing, controller)
+		}
+	}
+	return missing
+}
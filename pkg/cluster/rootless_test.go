@@ -0,0 +1,53 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMissingCgroupControllers(t *testing.T) {
+	cases := []struct {
+		TestName    string
+		Controllers string
+		Expected    []string
+	}{
+		{
+			TestName:    "all delegated",
+			Controllers: "cpuset cpu io memory pids\n",
+			Expected:    []string{},
+		},
+		{
+			TestName:    "systemd default",
+			Controllers: "memory pids\n",
+			Expected:    []string{"cpu"},
+		},
+		{
+			TestName: "none delegated",
+			Expected: []string{"cpu", "memory", "pids"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.TestName, func(t *testing.T) {
+			missing := missingCgroupControllers(tc.Controllers, rootlessCgroupControllers)
+			if !reflect.DeepEqual(missing, tc.Expected) {
+				t.Errorf("expected missing controllers %v, got %v", tc.Expected, missing)
+			}
+		})
+	}
+}
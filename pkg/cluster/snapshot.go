@@ -0,0 +1,397 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/docker"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// snapshotManifestFile is the name of the file describing a snapshot
+// within the snapshot directory
+const snapshotManifestFile = "snapshot.json"
+
+// snapshotImageRepository is the repository used to tag node images
+// committed while taking a snapshot, the tag is the node name
+const snapshotImageRepository = "kind-snapshot"
+
+// snapshotManifest describes a cluster snapshot, see ExportSnapshot
+type snapshotManifest struct {
+	// Cluster is the context name of the cluster the snapshot was taken from
+	Cluster string `json:"cluster"`
+	// IPFamily is the IP family of the cluster, empty for IPv4 clusters
+	IPFamily config.IPFamily `json:"ipFamily,omitempty"`
+	// Network is the docker network the nodes are attached to, this is
+	// created again on import unless it exists
+	Network config.DockerNetwork `json:"network,omitempty"`
+	// DNS is the DNS resolver config of the nodes, if any
+	DNS config.DNS `json:"dns,omitempty"`
+	// MTU is the MTU of the network, if not the default
+	MTU int32 `json:"mtu,omitempty"`
+	// Nodes contains one entry per node container in the cluster
+	Nodes []snapshotNode `json:"nodes"`
+}
+
+// snapshotNode describes a single node in a cluster snapshot
+type snapshotNode struct {
+	// Name is the node container name
+	Name string `json:"name"`
+	// Role is the node role
+	Role config.NodeRole `json:"role"`
+	// Image is the image the node container filesystem was committed to
+	Image string `json:"image"`
+	// Archive is the image archive file, relative to the snapshot directory
+	Archive string `json:"archive"`
+	// ExtraMounts are the host paths mounted into the node container,
+	// these are not part of the image and are mounted again on import
+	ExtraMounts []config.Mount `json:"extraMounts,omitempty"`
+	// ExtraPortMappings are the ports published for the node container,
+	// other than the API server port
+	ExtraPortMappings []config.PortMapping `json:"extraPortMappings,omitempty"`
+	// Resources, Sysctls, ExtraDevices, Tmpfs, ImageStore, DataPath and Env
+	// are the other settings of the node container, which are not part of
+	// the image either, see nodes.NodeOptions
+	Resources    config.NodeResources `json:"resources,omitempty"`
+	Sysctls      map[string]string    `json:"sysctls,omitempty"`
+	ExtraDevices []config.Device      `json:"extraDevices,omitempty"`
+	Tmpfs        map[string]string    `json:"tmpfs,omitempty"`
+	ImageStore   string               `json:"imageStore,omitempty"`
+	DataPath     string               `json:"dataPath,omitempty"`
+	Env          []string             `json:"env,omitempty"`
+	// APIServerAddress is the host address the API server port is published
+	// on, if any, the port itself is a new random port on import
+	APIServerAddress string `json:"apiServerAddress,omitempty"`
+	// IPAddress and IPv6Address are the static addresses of the node
+	// container in the network, if any
+	IPAddress   string `json:"ipAddress,omitempty"`
+	IPv6Address string `json:"ipv6Address,omitempty"`
+}
+
+// ExportSnapshot captures the state of the cluster into dir, so that it can
+// be restored later with ImportSnapshot.
+// The cluster is paused while taking the snapshot, so that etcd and all
+// other node state is cleanly written to disk, and resumed afterwards
+// waiting up to wait for the control plane to be ready, also when taking the
+// snapshot fails.
+// The snapshot contains the node container filesystems, including etcd data,
+// but not the node docker volumes; preloaded images are loaded again on import.
+func (c *Context) ExportSnapshot(dir string, wait time.Duration) (err error) {
+	n, err := c.ListNodes()
+	if err != nil {
+		return fmt.Errorf("error listing nodes: %v", err)
+	}
+	if len(n) == 0 {
+		return fmt.Errorf("no nodes found for cluster %q", c.Name())
+	}
+	// the Windows nodes can not be recreated from their committed image
+	for i := range n {
+		if n[i].IsWindows() {
+			return fmt.Errorf("snapshots of clusters with Windows nodes are not supported, cluster %q has Windows node %s", c.Name(), n[i].String())
+		}
+	}
+	if err := sortNodesByProvisioningOrder(n); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return errors.Wrap(err, "failed to create snapshot directory")
+	}
+
+	// the IP family is recorded on all the nodes of IPv6 and dual-stack clusters
+	ipFamily, err := n[0].Label(consts.IPFamilyKey)
+	if err != nil {
+		return err
+	}
+	networkName, err := n[0].Network()
+	if err != nil {
+		return err
+	}
+	network, err := inspectNetwork(networkName)
+	if err != nil {
+		return err
+	}
+	dns, err := n[0].DNS()
+	if err != nil {
+		return err
+	}
+	mtu, err := networkMTU(networkName)
+	if err != nil {
+		return err
+	}
+	if mtu == defaultMTU {
+		mtu = 0
+	}
+
+	if err := c.Pause(); err != nil {
+		return err
+	}
+	defer func() {
+		if resumeErr := c.Resume(wait); err == nil {
+			err = resumeErr
+		}
+	}()
+
+	status := logutil.NewStatus(os.Stdout)
+	status.MaybeWrapLogger(c.Logger())
+
+	manifest := snapshotManifest{
+		Cluster:  c.Name(),
+		IPFamily: config.IPFamily(ipFamily),
+		Network:  network,
+		DNS:      dns,
+		MTU:      mtu,
+	}
+	for i := range n {
+		node := &n[i]
+		role, err := node.Role()
+		if err != nil {
+			status.End(false)
+			return err
+		}
+		// the load balancer is recreated from its image only
+		opts := nodes.NodeOptions{}
+		if config.NodeRole(role) != config.ExternalLoadBalancerRole {
+			opts, err = node.Options()
+			if err != nil {
+				status.End(false)
+				return err
+			}
+		}
+		apiServerAddress, _, err := node.APIServerBinding()
+		if err != nil {
+			status.End(false)
+			return err
+		}
+		ip, ipv6, err := node.StaticAddresses()
+		if err != nil {
+			status.End(false)
+			return err
+		}
+		snapshotNode := snapshotNode{
+			Name:              node.String(),
+			Role:              config.NodeRole(role),
+			Image:             fmt.Sprintf("%s:%s", snapshotImageRepository, node.String()),
+			Archive:           node.String() + ".tar",
+			ExtraMounts:       opts.ExtraMounts,
+			ExtraPortMappings: opts.ExtraPortMappings,
+			Resources:         opts.Resources,
+			Sysctls:           opts.Sysctls,
+			ExtraDevices:      opts.ExtraDevices,
+			Tmpfs:             opts.Tmpfs,
+			ImageStore:        opts.ImageStore,
+			DataPath:          opts.DataPath,
+			Env:               opts.Env,
+			APIServerAddress:  apiServerAddress,
+			IPAddress:         ip,
+			IPv6Address:       ipv6,
+		}
+
+		status.Start(fmt.Sprintf("[%s] Committing node filesystem 📸", node.String()))
+		if err := node.Commit(snapshotNode.Image); err != nil {
+			status.End(false)
+			return errors.Wrapf(err, "failed to commit node %s", node.String())
+		}
+
+		status.Start(fmt.Sprintf("[%s] Saving node image 💾", node.String()))
+		saveErr := docker.Save(snapshotNode.Image, filepath.Join(dir, snapshotNode.Archive))
+		// the committed image is only needed to write the archive
+		if err := docker.DeleteImages(snapshotNode.Image); err != nil {
+			c.Logger().Warnf("Failed to remove image %s: %v", snapshotNode.Image, err)
+		}
+		if saveErr != nil {
+			status.End(false)
+			return errors.Wrapf(saveErr, "failed to save image for node %s", node.String())
+		}
+
+		manifest.Nodes = append(manifest.Nodes, snapshotNode)
+	}
+
+	b, err := json.MarshalIndent(&manifest, "", "  ")
+	if err != nil {
+		status.End(false)
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, snapshotManifestFile), b, 0644); err != nil {
+		status.End(false)
+		return errors.Wrap(err, "failed to write snapshot manifest")
+	}
+	status.End(true)
+
+	return nil
+}
+
+// ImportSnapshot restores a cluster from a snapshot previously written to dir
+// by ExportSnapshot, waiting up to wait for the control plane to be ready.
+// The cluster is restored with the same name it had when the snapshot was
+// taken, which must not currently exist.
+func ImportSnapshot(dir string, wait time.Duration) (*Context, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, snapshotManifestFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read snapshot manifest")
+	}
+	manifest := &snapshotManifest{}
+	if err := json.Unmarshal(b, manifest); err != nil {
+		return nil, errors.Wrap(err, "failed to parse snapshot manifest")
+	}
+	if len(manifest.Nodes) == 0 {
+		return nil, fmt.Errorf("snapshot in %s does not contain any nodes", dir)
+	}
+
+	c := NewContext(manifest.Cluster)
+	existing, err := c.ListNodes()
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %v", err)
+	}
+	if len(existing) > 0 {
+		return nil, errors.Wrap(&ClusterExistsError{Name: c.Name()}, "delete the cluster before importing a snapshot")
+	}
+
+	// restore nodes in the provisioning order
+	sort.SliceStable(manifest.Nodes, func(i, j int) bool {
+		return provisioningOrder(manifest.Nodes[i].Role) < provisioningOrder(manifest.Nodes[j].Role)
+	})
+
+	fmt.Printf("Restoring cluster '%s' ...\n", c.ClusterName())
+
+	status := logutil.NewStatus(os.Stdout)
+	status.MaybeWrapLogger(c.Logger())
+	defer status.End(false)
+
+	if err := c.importSnapshotNodes(status, dir, manifest, wait); err != nil {
+		// In case of errors restored nodes are deleted
+		c.Logger().Errorf("%v", err)
+		c.delete()
+		return nil, err
+	}
+	status.End(true)
+
+	return c, nil
+}
+
+func (c *Context) importSnapshotNodes(status *logutil.Status, dir string, manifest *snapshotManifest, wait time.Duration) error {
+	controlPlanes := []*nodes.Node{}
+	// the node the API server is published on, the external load balancer
+	// if any, or the first control plane
+	var apiServer *nodes.Node
+	apiServerAddress := ""
+	network, err := c.ensureNetwork(&config.Networking{
+		IPFamily:      manifest.IPFamily,
+		DockerNetwork: manifest.Network,
+		MTU:           manifest.MTU,
+	})
+	if err != nil {
+		return err
+	}
+	for _, snapshotNode := range manifest.Nodes {
+		status.Start(fmt.Sprintf("[%s] Loading node image 💾", snapshotNode.Name))
+		if err := docker.Load(filepath.Join(dir, snapshotNode.Archive)); err != nil {
+			return errors.Wrapf(err, "failed to load image for node %s", snapshotNode.Name)
+		}
+
+		status.Start(fmt.Sprintf("[%s] Creating node container 📦", snapshotNode.Name))
+		node, err := nodes.RecreateNode(snapshotNode.Name, snapshotNode.Image, c.ClusterLabel(), nodes.Network{
+			Name: network,
+			IP:   snapshotNode.IPAddress,
+			IPv6: snapshotNode.IPv6Address,
+			DNS:  manifest.DNS,
+		}, snapshotNode.Role, snapshotNode.APIServerAddress, 0, nodes.NodeOptions{
+			ExtraMounts:       snapshotNode.ExtraMounts,
+			ExtraPortMappings: snapshotNode.ExtraPortMappings,
+			Resources:         snapshotNode.Resources,
+			Sysctls:           snapshotNode.Sysctls,
+			ExtraDevices:      snapshotNode.ExtraDevices,
+			Tmpfs:             snapshotNode.Tmpfs,
+			ImageStore:        snapshotNode.ImageStore,
+			DataPath:          snapshotNode.DataPath,
+			Env:               snapshotNode.Env,
+		})
+		if err != nil {
+			return err
+		}
+
+		// the load balancer node is ready as soon as it is running
+		if snapshotNode.Role == config.ExternalLoadBalancerRole {
+			apiServer = node
+			apiServerAddress = snapshotNode.APIServerAddress
+			continue
+		}
+
+		if err := bootNode(status, node); err != nil {
+			return err
+		}
+
+		// the node docker volume is not part of the snapshot
+		status.Start(fmt.Sprintf("[%s] Pre-loading images 🐋", snapshotNode.Name))
+		node.LoadImages()
+
+		if snapshotNode.Role == config.ControlPlaneRole {
+			if len(controlPlanes) == 0 && apiServer == nil {
+				apiServerAddress = snapshotNode.APIServerAddress
+			}
+			controlPlanes = append(controlPlanes, node)
+		}
+	}
+
+	// the node IPs may have changed, so the load balancer backends must be updated
+	if err := c.ReconfigureLoadBalancer(); err != nil {
+		return errors.Wrap(err, "failed to reconfigure the external load balancer")
+	}
+
+	for _, node := range controlPlanes {
+		status.Start(fmt.Sprintf("[%s] Waiting for the control plane to be ready ☸", node.String()))
+		if !nodes.WaitForReady(node, time.Now().Add(wait)) {
+			return &NodeNotReadyError{Node: node.String(), Condition: "the control plane to be ready"}
+		}
+	}
+
+	// the API server is published on a new random host port
+	if apiServer == nil && len(controlPlanes) > 0 {
+		apiServer = controlPlanes[0]
+	}
+	if apiServer != nil && len(controlPlanes) > 0 {
+		hostPort, err := apiServer.Ports(kubeadm.APIServerPort)
+		if err != nil {
+			return errors.Wrap(err, "failed to get kubeconfig from node")
+		}
+		// the config labels are part of the committed node images
+		certSANs, err := controlPlanes[0].Label(consts.APIServerCertSANsKey)
+		if err != nil {
+			return err
+		}
+		if err := controlPlanes[0].WriteKubeConfig(c.KubeConfigPath(), apiServerAddress, hostPort, splitLabelList(certSANs)...); err != nil {
+			return errors.Wrap(err, "failed to get kubeconfig from node")
+		}
+		if err := c.writeInternalKubeConfig(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/kubeadm"
+	"sigs.k8s.io/kind/pkg/docker"
+)
+
+// Status reports the health of a cluster, see Context.Status
+type Status struct {
+	// Name is the cluster context name
+	Name string
+	// APIServerReachable is true if the API server responded healthy on the
+	// host port it is published on
+	APIServerReachable bool
+	// Nodes contains the status of each node in the cluster
+	Nodes []NodeStatus
+}
+
+// NodeStatus reports the health of a single node in a cluster
+type NodeStatus struct {
+	// Name is the node container name
+	Name string
+	// Role is the node role
+	Role config.NodeRole
+	// State is the node container state, e.g. "running" or "exited"
+	State string
+	// KubeletHealthy is true if the kubelet on the node reports healthy
+	// This is always false for nodes that are not Kubernetes nodes
+	KubeletHealthy bool
+	// PendingTasks lists the tasks planned for the node at creation time
+	// that are not recorded as completed
+	PendingTasks []string
+}
+
+// Healthy returns true if the node is running, the kubelet is healthy
+// (for Kubernetes nodes) and all the planned tasks completed
+func (s *NodeStatus) Healthy() bool {
+	if s.State != "running" || len(s.PendingTasks) > 0 {
+		return false
+	}
+	if s.Role == config.ControlPlaneRole || s.Role == config.WorkerRole {
+		return s.KubeletHealthy
+	}
+	return true
+}
+
+// Healthy returns true if the cluster has nodes, the API server is reachable
+// and all the nodes are healthy
+func (s *Status) Healthy() bool {
+	if len(s.Nodes) == 0 || !s.APIServerReachable {
+		return false
+	}
+	for i := range s.Nodes {
+		if !s.Nodes[i].Healthy() {
+			return false
+		}
+	}
+	return true
+}
+
+// Status checks the health of the cluster, reporting the state of each node
+// container, kubelet health, API server reachability, and whether all the
+// actions planned at creation time completed
+func (c *Context) Status() (*Status, error) {
+	n, err := c.ListNodes()
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %v", err)
+	}
+	status := &Status{
+		Name: c.Name(),
+	}
+	if len(n) == 0 {
+		return status, nil
+	}
+
+	// compute the tasks that should have been executed on each node
+	cfg, derived, nodeList, err := c.deriveInfoFromNodes(n)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := newExecutionPlan(derived, createActions(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, replica := range derived.AllReplicas() {
+		node := nodeList[replica.Name]
+		nodeStatus := NodeStatus{
+			Name: node.String(),
+			Role: replica.Role,
+		}
+		nodeStatus.State, err = node.State()
+		if err != nil {
+			return nil, err
+		}
+
+		// only running nodes can be inspected any further
+		completed := map[string]bool{}
+		if nodeStatus.State == "running" {
+			nodeStatus.KubeletHealthy = node.KubeletHealthy()
+			keys, err := node.CompletedTasks()
+			if err != nil {
+				return nil, err
+			}
+			for _, key := range keys {
+				completed[key] = true
+			}
+		}
+		for _, plannedTask := range plan {
+			if plannedTask.Node.Name == replica.Name && !completed[plannedTask.Key()] {
+				nodeStatus.PendingTasks = append(nodeStatus.PendingTasks, plannedTask.Task.Description)
+			}
+		}
+
+		status.Nodes = append(status.Nodes, nodeStatus)
+	}
+
+	// the API server is published on the external load balancer if any,
+	// or on the bootstrap control plane
+	if apiServer, err := apiServerNode(derived, nodeList); err == nil {
+		if hostPort, err := apiServer.Ports(kubeadm.APIServerPort); err == nil {
+			status.APIServerReachable = apiServerHealthy(hostPort)
+		}
+	}
+
+	return status, nil
+}
+
+// apiServerHealthy returns true if the API server published on the host at
+// hostPort reports healthy
+func apiServerHealthy(hostPort int) bool {
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			// we only care about reachability here, the serving certificate
+			// is signed by the cluster CA, which is not trusted by the host
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+	resp, err := client.Get(fmt.Sprintf("https://%s/healthz", net.JoinHostPort(docker.PublishedHost(), strconv.Itoa(hostPort))))
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false
+	}
+	return resp.StatusCode == http.StatusOK && strings.TrimSpace(string(body)) == "ok"
+}
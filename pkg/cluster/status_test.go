@@ -0,0 +1,87 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestStatusHealthy(t *testing.T) {
+	healthyControlPlane := NodeStatus{Role: config.ControlPlaneRole, State: "running", KubeletHealthy: true}
+	healthyWorker := NodeStatus{Role: config.WorkerRole, State: "running", KubeletHealthy: true}
+	cases := []struct {
+		TestName      string
+		Status        Status
+		ExpectHealthy bool
+	}{
+		{
+			TestName:      "No nodes",
+			Status:        Status{APIServerReachable: true},
+			ExpectHealthy: false,
+		},
+		{
+			TestName:      "All healthy",
+			Status:        Status{APIServerReachable: true, Nodes: []NodeStatus{healthyControlPlane, healthyWorker}},
+			ExpectHealthy: true,
+		},
+		{
+			TestName:      "API server unreachable",
+			Status:        Status{APIServerReachable: false, Nodes: []NodeStatus{healthyControlPlane}},
+			ExpectHealthy: false,
+		},
+		{
+			TestName: "Stopped node",
+			Status: Status{APIServerReachable: true, Nodes: []NodeStatus{
+				healthyControlPlane,
+				{Role: config.WorkerRole, State: "exited"},
+			}},
+			ExpectHealthy: false,
+		},
+		{
+			TestName: "Unhealthy kubelet",
+			Status: Status{APIServerReachable: true, Nodes: []NodeStatus{
+				healthyControlPlane,
+				{Role: config.WorkerRole, State: "running"},
+			}},
+			ExpectHealthy: false,
+		},
+		{
+			TestName: "Pending tasks",
+			Status: Status{APIServerReachable: true, Nodes: []NodeStatus{
+				healthyControlPlane,
+				{Role: config.WorkerRole, State: "running", KubeletHealthy: true, PendingTasks: []string{"join"}},
+			}},
+			ExpectHealthy: false,
+		},
+		{
+			TestName: "External load balancer does not run a kubelet",
+			Status: Status{APIServerReachable: true, Nodes: []NodeStatus{
+				{Role: config.ExternalLoadBalancerRole, State: "running"},
+				healthyControlPlane,
+			}},
+			ExpectHealthy: true,
+		},
+	}
+
+	for _, tc := range cases {
+		if healthy := tc.Status.Healthy(); healthy != tc.ExpectHealthy {
+			t.Errorf("case: '%s' expected healthy to be %t but got %t", tc.TestName, tc.ExpectHealthy, healthy)
+		}
+	}
+}
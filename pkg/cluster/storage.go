@@ -0,0 +1,246 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+)
+
+// defaultStorageClassName is the name of the default storage class, see
+// config.Storage
+const defaultStorageClassName = "standard"
+
+// defaultStorageNodePath is the directory of the nodes the volumes are
+// provisioned in, see config.Storage
+const defaultStorageNodePath = "/var/local-path-provisioner"
+
+// localPathProvisionerImage is the image of the local-path-provisioner
+const localPathProvisionerImage = "docker.io/rancher/local-path-provisioner:v0.0.24"
+
+// localPathHelperImage is the image of the helper pods creating and deleting
+// the volume directories on the nodes
+const localPathHelperImage = "docker.io/library/busybox:1.36"
+
+// storageNodePath returns the directory of the nodes the volumes are
+// provisioned in
+func storageNodePath(storage *config.Storage) string {
+	if storage.NodePath == "" {
+		return defaultStorageNodePath
+	}
+	return path.Clean(storage.NodePath)
+}
+
+// nodeMounts returns the mounts of the node container, the extra mounts of
+// the node, and the storage host directory for the control-plane and worker
+// nodes unless the node already mounts the storage node directory
+func nodeMounts(cfg *config.Config, configNode *nodeReplica) []config.Mount {
+	storage := &cfg.Storage
+	if storage.Disabled || storage.HostPath == "" || configNode.IsWindows() ||
+		(configNode.Role != config.ControlPlaneRole && configNode.Role != config.WorkerRole) {
+		return configNode.ExtraMounts
+	}
+	nodePath := storageNodePath(storage)
+	for _, m := range configNode.ExtraMounts {
+		if path.Clean(m.ContainerPath) == nodePath {
+			return configNode.ExtraMounts
+		}
+	}
+	return append(append([]config.Mount{}, configNode.ExtraMounts...), config.Mount{
+		ContainerPath: nodePath,
+		HostPath:      storage.HostPath,
+	})
+}
+
+// nodeDataPath returns the path the node data volume is mounted at, see
+// nodes.DataVolumeName, which is the storage node directory of the
+// control-plane and worker nodes so that the provisioned volumes are kept
+// when recreating the cluster with keepVolumes (see Recreate), unless a host
+// directory is already mounted there
+func nodeDataPath(cfg *config.Config, configNode *nodeReplica) string {
+	storage := &cfg.Storage
+	if storage.Disabled || configNode.IsWindows() ||
+		(configNode.Role != config.ControlPlaneRole && configNode.Role != config.WorkerRole) {
+		return nodes.DataVolumePath
+	}
+	nodePath := storageNodePath(storage)
+	for _, m := range nodeMounts(cfg, configNode) {
+		if path.Clean(m.ContainerPath) == nodePath {
+			return nodes.DataVolumePath
+		}
+	}
+	return nodePath
+}
+
+// installStorage installs the local-path-provisioner and the default storage
+// class, unless disabled
+func installStorage(controlPlane *nodes.Node, storage *config.Storage) error {
+	if storage.Disabled {
+		return nil
+	}
+	cmd := controlPlane.Command(
+		"kubectl",
+		"--kubeconfig=/etc/kubernetes/admin.conf", "apply", "-f", "-",
+	)
+	cmd.SetStdin(strings.NewReader(localPathStorageManifest(storage)))
+	return cmd.Run()
+}
+
+// localPathStorageManifest returns the local-path-provisioner manifest, with
+// the default storage class, provisioning the volumes in a
+// <namespace>/<claim name> directory of the storage node directory, so that
+// the claims find their data again if the directory persists
+func localPathStorageManifest(storage *config.Storage) string {
+	className := storage.ClassName
+	if className == "" {
+		className = defaultStorageClassName
+	}
+	nodePathMap := `{"nodePathMap":[{"node":"DEFAULT_PATH_FOR_NON_LISTED_NODES","paths":[` +
+		strconv.Quote(storageNodePath(storage)) + `]}]}`
+	return `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: local-path-storage
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: local-path-provisioner-service-account
+  namespace: local-path-storage
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: local-path-provisioner-role
+rules:
+  - apiGroups: [""]
+    resources: ["nodes", "persistentvolumeclaims", "configmaps"]
+    verbs: ["get", "list", "watch"]
+  - apiGroups: [""]
+    resources: ["endpoints", "persistentvolumes", "pods"]
+    verbs: ["*"]
+  - apiGroups: [""]
+    resources: ["events"]
+    verbs: ["create", "patch"]
+  - apiGroups: ["storage.k8s.io"]
+    resources: ["storageclasses"]
+    verbs: ["get", "list", "watch"]
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: local-path-provisioner-bind
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: local-path-provisioner-role
+subjects:
+  - kind: ServiceAccount
+    name: local-path-provisioner-service-account
+    namespace: local-path-storage
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: local-path-provisioner
+  namespace: local-path-storage
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: local-path-provisioner
+  template:
+    metadata:
+      labels:
+        app: local-path-provisioner
+    spec:
+      serviceAccountName: local-path-provisioner-service-account
+      containers:
+        - name: local-path-provisioner
+          image: ` + localPathProvisionerImage + `
+          imagePullPolicy: IfNotPresent
+          command:
+            - local-path-provisioner
+            - --debug
+            - start
+            - --config
+            - /etc/config/config.json
+          volumeMounts:
+            - name: config-volume
+              mountPath: /etc/config/
+          env:
+            - name: POD_NAMESPACE
+              valueFrom:
+                fieldRef:
+                  fieldPath: metadata.namespace
+      volumes:
+        - name: config-volume
+          configMap:
+            name: local-path-config
+      nodeSelector:
+        kubernetes.io/os: linux
+      tolerations:
+        - key: node-role.kubernetes.io/master
+          effect: NoSchedule
+---
+apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: ` + className + `
+  annotations:
+    storageclass.kubernetes.io/is-default-class: "true"
+  labels:
+    addonmanager.kubernetes.io/mode: EnsureExists
+provisioner: rancher.io/local-path
+parameters:
+  pathPattern: "{{ .PVC.Namespace }}/{{ .PVC.Name }}"
+volumeBindingMode: WaitForFirstConsumer
+reclaimPolicy: Delete
+---
+kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: local-path-config
+  namespace: local-path-storage
+data:
+  config.json: |-
+    ` + nodePathMap + `
+  setup: |-
+    #!/bin/sh
+    set -eu
+    mkdir -m 0777 -p "$VOL_DIR"
+  teardown: |-
+    #!/bin/sh
+    set -eu
+    rm -rf "$VOL_DIR"
+  helperPod.yaml: |-
+    apiVersion: v1
+    kind: Pod
+    metadata:
+      name: helper-pod
+    spec:
+      containers:
+        - name: helper-pod
+          image: ` + localPathHelperImage + `
+          imagePullPolicy: IfNotPresent
+`
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+)
+
+func TestNodeMounts(t *testing.T) {
+	userMount := config.Mount{ContainerPath: "/data", HostPath: "/tmp/data"}
+	storageMount := config.Mount{ContainerPath: "/var/local-path-provisioner", HostPath: "/tmp/volumes"}
+	cases := []struct {
+		Name     string
+		Storage  config.Storage
+		Node     config.Node
+		Expected []config.Mount
+	}{
+		{
+			Name:     "no host path",
+			Node:     config.Node{Role: config.WorkerRole, ExtraMounts: []config.Mount{userMount}},
+			Expected: []config.Mount{userMount},
+		},
+		{
+			Name:     "host path",
+			Storage:  config.Storage{HostPath: "/tmp/volumes"},
+			Node:     config.Node{Role: config.WorkerRole, ExtraMounts: []config.Mount{userMount}},
+			Expected: []config.Mount{userMount, storageMount},
+		},
+		{
+			Name:     "host path and node path",
+			Storage:  config.Storage{HostPath: "/tmp/volumes", NodePath: "/volumes/"},
+			Node:     config.Node{Role: config.ControlPlaneRole},
+			Expected: []config.Mount{{ContainerPath: "/volumes", HostPath: "/tmp/volumes"}},
+		},
+		{
+			Name:     "node path already mounted",
+			Storage:  config.Storage{HostPath: "/tmp/volumes"},
+			Node:     config.Node{Role: config.WorkerRole, ExtraMounts: []config.Mount{{ContainerPath: "/var/local-path-provisioner/", HostPath: "/tmp/other"}}},
+			Expected: []config.Mount{{ContainerPath: "/var/local-path-provisioner/", HostPath: "/tmp/other"}},
+		},
+		{
+			Name:    "external etcd",
+			Storage: config.Storage{HostPath: "/tmp/volumes"},
+			Node:    config.Node{Role: config.ExternalEtcdRole},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			mounts := nodeMounts(&config.Config{Storage: tc.Storage}, &nodeReplica{Node: tc.Node})
+			if !reflect.DeepEqual(mounts, tc.Expected) {
+				t.Errorf("expected %v, got %v", tc.Expected, mounts)
+			}
+		})
+	}
+}
+
+func TestNodeDataPath(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Storage  config.Storage
+		Node     config.Node
+		Expected string
+	}{
+		{
+			Name:     "worker",
+			Node:     config.Node{Role: config.WorkerRole},
+			Expected: "/var/local-path-provisioner",
+		},
+		{
+			Name:     "control plane with node path",
+			Storage:  config.Storage{NodePath: "/volumes/"},
+			Node:     config.Node{Role: config.ControlPlaneRole},
+			Expected: "/volumes",
+		},
+		{
+			Name:     "host path",
+			Storage:  config.Storage{HostPath: "/tmp/volumes"},
+			Node:     config.Node{Role: config.WorkerRole},
+			Expected: nodes.DataVolumePath,
+		},
+		{
+			Name:     "node path already mounted",
+			Node:     config.Node{Role: config.WorkerRole, ExtraMounts: []config.Mount{{ContainerPath: "/var/local-path-provisioner", HostPath: "/tmp/other"}}},
+			Expected: nodes.DataVolumePath,
+		},
+		{
+			Name:     "storage disabled",
+			Storage:  config.Storage{Disabled: true},
+			Node:     config.Node{Role: config.WorkerRole},
+			Expected: nodes.DataVolumePath,
+		},
+		{
+			Name:     "external etcd",
+			Node:     config.Node{Role: config.ExternalEtcdRole},
+			Expected: nodes.DataVolumePath,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			dataPath := nodeDataPath(&config.Config{Storage: tc.Storage}, &nodeReplica{Node: tc.Node})
+			if dataPath != tc.Expected {
+				t.Errorf("expected %q, got %q", tc.Expected, dataPath)
+			}
+		})
+	}
+}
+
+// the data volume kept by a recreated worker must be mounted where the
+// volumes of the new worker are provisioned
+func TestRecreatedWorkerDataPath(t *testing.T) {
+	cfg := &config.Config{Nodes: []config.Node{
+		{Role: config.ControlPlaneRole},
+		{Role: config.WorkerRole},
+	}}
+	derived, err := deriveInfo(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, worker := range derived.Workers() {
+		if dataPath := nodeDataPath(cfg, worker); dataPath != storageNodePath(&cfg.Storage) {
+			t.Errorf("expected the data volume of %s to be mounted at %q, got %q", worker.Name, storageNodePath(&cfg.Storage), dataPath)
+		}
+	}
+}
+
+func TestLocalPathStorageManifest(t *testing.T) {
+	manifest := localPathStorageManifest(&config.Storage{})
+	for _, expected := range []string{
+		"\n  name: standard\n",
+		`"paths":["/var/local-path-provisioner"]`,
+	} {
+		if !strings.Contains(manifest, expected) {
+			t.Errorf("expected the default manifest to contain %q", expected)
+		}
+	}
+
+	manifest = localPathStorageManifest(&config.Storage{ClassName: "local", NodePath: "/volumes"})
+	for _, expected := range []string{
+		"\n  name: local\n",
+		`"paths":["/volumes"]`,
+	} {
+		if !strings.Contains(manifest, expected) {
+			t.Errorf("expected the manifest to contain %q", expected)
+		}
+	}
+}
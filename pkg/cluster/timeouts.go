@@ -0,0 +1,54 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+// timeouts are the durations of config.Timeouts, with the defaults applied
+type timeouts struct {
+	NodeBoot         time.Duration
+	KubeadmInit      time.Duration // no limit if 0
+	NodeRegistration time.Duration
+	CNIRollout       time.Duration // not waited for if 0
+	Ready            time.Duration // not waited for if 0
+	PollInterval     time.Duration
+}
+
+// newTimeouts returns the timeouts of the config, the durations are checked
+// by config validation, the invalid ones are defaulted
+func newTimeouts(cfg *config.Timeouts) timeouts {
+	return timeouts{
+		NodeBoot:         parseTimeout(cfg.NodeBoot, 30*time.Second),
+		KubeadmInit:      parseTimeout(cfg.KubeadmInit, 0),
+		NodeRegistration: parseTimeout(cfg.NodeRegistration, time.Minute),
+		CNIRollout:       parseTimeout(cfg.CNIRollout, 0),
+		Ready:            parseTimeout(cfg.Ready, 0),
+		PollInterval:     parseTimeout(cfg.PollInterval, time.Second),
+	}
+}
+
+// parseTimeout parses the duration value, or returns def if not set
+func parseTimeout(value string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d
+	}
+	return def
+}
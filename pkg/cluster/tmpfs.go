@@ -0,0 +1,66 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/etcd"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+)
+
+// nodeTmpfs returns the tmpfs mounts of the node container, as container
+// path to mount options, see config.Tmpfs
+func nodeTmpfs(cfg *config.Config, configNode *nodeReplica) map[string]string {
+	tmpfs := map[string]string{}
+	if cfg.Tmpfs.Etcd && (configNode.Role == config.ControlPlaneRole || configNode.Role == config.ExternalEtcdRole) {
+		tmpfs[etcd.DataDir] = "rw" + tmpfsSizeOption(cfg.Tmpfs.EtcdSize)
+	}
+	if cfg.Tmpfs.ContainerRuntime && (configNode.Role == config.ControlPlaneRole || configNode.Role == config.WorkerRole) && !configNode.IsWindows() {
+		// docker mounts a tmpfs noexec, nosuid and nodev by default, which
+		// the images and the containers of the runtime can not be
+		tmpfs[nodes.ImageStorePath] = "rw,exec,suid,dev" + tmpfsSizeOption(cfg.Tmpfs.ContainerRuntimeSize)
+	}
+	return tmpfs
+}
+
+// tmpfsData returns the data kept in tmpfs, as the names of the fields of
+// config.Tmpfs enabling them
+func tmpfsData(tmpfs *config.Tmpfs) []string {
+	data := []string{}
+	if tmpfs.Etcd {
+		data = append(data, "etcd")
+	}
+	if tmpfs.ContainerRuntime {
+		data = append(data, "containerRuntime")
+	}
+	return data
+}
+
+// tmpfsSizeOption returns the tmpfs mount option limiting its size to the
+// size quantity, or "" if not set
+func tmpfsSizeOption(size string) string {
+	if size == "" {
+		return ""
+	}
+	// validated, see config.Tmpfs
+	q, _ := resource.ParseQuantity(size)
+	return fmt.Sprintf(",size=%d", q.Value())
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestNodeTmpfs(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Tmpfs    config.Tmpfs
+		Role     config.NodeRole
+		Expected map[string]string
+	}{
+		{
+			Name:     "disabled",
+			Role:     config.ControlPlaneRole,
+			Expected: map[string]string{},
+		},
+		{
+			Name:  "control plane",
+			Tmpfs: config.Tmpfs{Etcd: true, EtcdSize: "512Mi", ContainerRuntime: true},
+			Role:  config.ControlPlaneRole,
+			Expected: map[string]string{
+				"/var/lib/etcd":   "rw,size=536870912",
+				"/var/lib/docker": "rw,exec,suid,dev",
+			},
+		},
+		{
+			Name:     "worker",
+			Tmpfs:    config.Tmpfs{Etcd: true, ContainerRuntime: true, ContainerRuntimeSize: "4Gi"},
+			Role:     config.WorkerRole,
+			Expected: map[string]string{"/var/lib/docker": "rw,exec,suid,dev,size=4294967296"},
+		},
+		{
+			Name:     "external etcd",
+			Tmpfs:    config.Tmpfs{Etcd: true, ContainerRuntime: true},
+			Role:     config.ExternalEtcdRole,
+			Expected: map[string]string{"/var/lib/etcd": "rw"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tmpfs := nodeTmpfs(&config.Config{Tmpfs: tc.Tmpfs}, &nodeReplica{Node: config.Node{Role: tc.Role}})
+			if !reflect.DeepEqual(tmpfs, tc.Expected) {
+				t.Errorf("expected %v, got %v", tc.Expected, tmpfs)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/cluster/consts"
+)
+
+// expiryLabel returns the docker object label recording the cluster expiry
+func expiryLabel(expiry time.Time) string {
+	return fmt.Sprintf("%s=%s", consts.ClusterExpiryKey, expiry.UTC().Format(time.RFC3339))
+}
+
+// Expiry returns the time after which the cluster may be garbage collected,
+// and false if the cluster was not created with a TTL
+func (c *Context) Expiry() (expiry time.Time, ok bool, err error) {
+	n, err := c.ListNodes()
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("error listing nodes: %v", err)
+	}
+	if len(n) == 0 {
+		return time.Time{}, false, nil
+	}
+	// all the nodes are created with the same expiry
+	value, err := n[0].Label(consts.ClusterExpiryKey)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if value == "" {
+		return time.Time{}, false, nil
+	}
+	expiry, err = time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, errors.Wrapf(err, "invalid expiry for cluster %q", c.Name())
+	}
+	return expiry, true, nil
+}
+
+// GarbageCollect deletes all the clusters that expired before now,
+// except protected clusters, returning the deleted clusters
+func GarbageCollect(now time.Time) ([]Context, error) {
+	clusters, err := List()
+	if err != nil {
+		return nil, err
+	}
+	deleted := []Context{}
+	for _, c := range clusters {
+		expiry, ok, err := c.Expiry()
+		if err != nil {
+			return deleted, err
+		}
+		if !ok || expiry.After(now) {
+			continue
+		}
+		// protected clusters are never garbage collected
+		protected, err := c.IsProtected()
+		if err != nil {
+			return deleted, err
+		}
+		if protected {
+			continue
+		}
+		// expired clusters are deleted regardless of their owner
+		if err := c.delete(); err != nil {
+			return deleted, errors.Wrapf(err, "failed to delete expired cluster %q", c.Name())
+		}
+		deleted = append(deleted, c)
+	}
+	return deleted, nil
+}
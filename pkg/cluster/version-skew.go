@@ -0,0 +1,168 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/util"
+)
+
+// maxKubeletSkew is the maximum number of minor versions the kubelet
+// may be older than the API server, as per the version skew policy
+// https://kubernetes.io/docs/setup/version-skew-policy/
+const maxKubeletSkew = 2
+
+// validateVersionSkew checks the Kubernetes versions of the node images,
+// which can be set per node, against the version skew policy
+func validateVersionSkew(derived *derivedConfigData, nodeList map[string]*nodes.Node) error {
+	controlPlanes, err := kubeVersions(derived.ControlPlanes(), nodeList)
+	if err != nil {
+		return err
+	}
+	workers, err := kubeVersions(derived.Workers(), nodeList)
+	if err != nil {
+		return err
+	}
+	if err := checkVersionSkew(controlPlanes, workers); err != nil {
+		return err
+	}
+	windowsWorkers, err := kubeVersions(derived.WindowsWorkers(), nodeList)
+	if err != nil {
+		return err
+	}
+	return checkWindowsVersions(controlPlanes, windowsWorkers)
+}
+
+// minWindowsVersion is the first Kubernetes version supporting Windows nodes
+const minWindowsVersion = "1.14.0"
+
+// checkWindowsVersions checks the Kubernetes versions of the Windows worker
+// nodes by name, and of the control-plane nodes if there are any Windows
+// workers, support for Windows nodes requires at least minWindowsVersion
+func checkWindowsVersions(controlPlanes, windowsWorkers map[string]string) error {
+	if len(windowsWorkers) == 0 {
+		return nil
+	}
+	minVersion := version.MustParseGeneric(minWindowsVersion)
+	errs := []error{}
+	for _, versions := range []map[string]string{controlPlanes, windowsWorkers} {
+		for _, name := range sortedKeys(versions) {
+			v, err := version.ParseGeneric(versions[name])
+			if err != nil {
+				return errors.Wrapf(err, "invalid kubernetes version for node %s", name)
+			}
+			if v.LessThan(minVersion) {
+				errs = append(errs, fmt.Errorf(
+					"node %s is at version %s, but Windows worker nodes require at least version %s",
+					name, versions[name], minVersion,
+				))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return util.NewErrors(errs)
+	}
+	return nil
+}
+
+// kubeVersions returns the Kubernetes version of each replica by name
+func kubeVersions(replicas replicaList, nodeList map[string]*nodes.Node) (map[string]string, error) {
+	versions := map[string]string{}
+	for _, replica := range replicas {
+		node, ok := nodeList[replica.Name]
+		if !ok {
+			return nil, fmt.Errorf("unable to get the handle for operating on node: %s", replica.Name)
+		}
+		kubeVersion, err := node.KubeVersion()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get kubernetes version from node %s", node.String())
+		}
+		versions[replica.Name] = kubeVersion
+	}
+	return versions, nil
+}
+
+// checkVersionSkew checks the Kubernetes versions of the control-plane
+// and worker nodes by name. All the control-plane nodes must run the same
+// minor version, and the workers can not be at a newer minor version than the
+// control-plane nor older by more than maxKubeletSkew minor versions.
+func checkVersionSkew(controlPlanes, workers map[string]string) error {
+	errs := []error{}
+
+	var controlPlaneVersion *version.Version
+	for _, name := range sortedKeys(controlPlanes) {
+		v, err := version.ParseGeneric(controlPlanes[name])
+		if err != nil {
+			return errors.Wrapf(err, "invalid kubernetes version for node %s", name)
+		}
+		if controlPlaneVersion == nil {
+			controlPlaneVersion = v
+		} else if v.Major() != controlPlaneVersion.Major() || v.Minor() != controlPlaneVersion.Minor() {
+			errs = append(errs, fmt.Errorf(
+				"control-plane node %s is at version %s, but all control-plane nodes must be at the same minor version as %s",
+				name, controlPlanes[name], controlPlaneVersion,
+			))
+		}
+	}
+	// there is nothing to check the workers against
+	if controlPlaneVersion == nil {
+		return nil
+	}
+
+	for _, name := range sortedKeys(workers) {
+		v, err := version.ParseGeneric(workers[name])
+		if err != nil {
+			return errors.Wrapf(err, "invalid kubernetes version for node %s", name)
+		}
+		// only the minor versions matter, patch releases may be skewed
+		if v.Major() > controlPlaneVersion.Major() ||
+			(v.Major() == controlPlaneVersion.Major() && v.Minor() > controlPlaneVersion.Minor()) {
+			errs = append(errs, fmt.Errorf(
+				"worker node %s is at version %s, which is a newer minor version than the control-plane version %s",
+				name, workers[name], controlPlaneVersion,
+			))
+			continue
+		}
+		if v.Major() != controlPlaneVersion.Major() || controlPlaneVersion.Minor()-v.Minor() > maxKubeletSkew {
+			errs = append(errs, fmt.Errorf(
+				"worker node %s is at version %s, which is more than %d minor versions older than the control-plane version %s",
+				name, workers[name], maxKubeletSkew, controlPlaneVersion,
+			))
+		}
+	}
+
+	if len(errs) > 0 {
+		return util.NewErrors(errs)
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of m sorted, for predictable error ordering
+func sortedKeys(m map[string]string) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
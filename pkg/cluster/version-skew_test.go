@@ -0,0 +1,149 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/util"
+)
+
+func TestCheckVersionSkew(t *testing.T) {
+	cases := []struct {
+		TestName      string
+		ControlPlanes map[string]string
+		Workers       map[string]string
+		ExpectErrors  int
+	}{
+		{
+			TestName:      "Same versions are valid",
+			ControlPlanes: map[string]string{"control-plane": "v1.12.3"},
+			Workers:       map[string]string{"worker1": "v1.12.3", "worker2": "v1.12.3"},
+			ExpectErrors:  0,
+		},
+		{
+			TestName:      "Workers older than the control-plane are valid",
+			ControlPlanes: map[string]string{"control-plane": "v1.12.3"},
+			Workers:       map[string]string{"worker1": "v1.11.5", "worker2": "v1.10.11"},
+			ExpectErrors:  0,
+		},
+		{
+			TestName:      "Workers at a newer patch version than the control-plane are valid",
+			ControlPlanes: map[string]string{"control-plane": "v1.12.1"},
+			Workers:       map[string]string{"worker1": "v1.12.3"},
+			ExpectErrors:  0,
+		},
+		{
+			TestName:     "No control-plane is valid",
+			Workers:      map[string]string{"worker1": "v1.12.3"},
+			ExpectErrors: 0,
+		},
+		{
+			TestName:      "Control-planes at different patch versions are valid",
+			ControlPlanes: map[string]string{"control-plane1": "v1.12.3", "control-plane2": "v1.12.1"},
+			ExpectErrors:  0,
+		},
+		{
+			TestName:      "Control-planes at different minor versions are invalid",
+			ControlPlanes: map[string]string{"control-plane1": "v1.12.3", "control-plane2": "v1.11.5"},
+			ExpectErrors:  1,
+		},
+		{
+			TestName:      "Workers newer than the control-plane are invalid",
+			ControlPlanes: map[string]string{"control-plane": "v1.11.5"},
+			Workers:       map[string]string{"worker1": "v1.12.3", "worker2": "v1.11.5"},
+			ExpectErrors:  1,
+		},
+		{
+			TestName:      "Workers too old for the control-plane are invalid",
+			ControlPlanes: map[string]string{"control-plane": "v1.12.3"},
+			Workers:       map[string]string{"worker1": "v1.9.11", "worker2": "v1.8.15"},
+			ExpectErrors:  2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.TestName, func(t2 *testing.T) {
+			err := checkVersionSkew(c.ControlPlanes, c.Workers)
+			if c.ExpectErrors == 0 {
+				if err != nil {
+					t2.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			errs, ok := err.(util.Errors)
+			if !ok {
+				t2.Fatalf("expected util.Errors, saw: %v", err)
+			}
+			if len(errs) != c.ExpectErrors {
+				t2.Errorf("expected %d errors, saw %d: %v", c.ExpectErrors, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestCheckWindowsVersions(t *testing.T) {
+	cases := []struct {
+		TestName       string
+		ControlPlanes  map[string]string
+		WindowsWorkers map[string]string
+		ExpectErrors   int
+	}{
+		{
+			TestName:      "Old control-planes are valid without Windows workers",
+			ControlPlanes: map[string]string{"control-plane": "v1.12.3"},
+			ExpectErrors:  0,
+		},
+		{
+			TestName:       "Recent versions are valid",
+			ControlPlanes:  map[string]string{"control-plane": "v1.15.0"},
+			WindowsWorkers: map[string]string{"worker1": "v1.14.3"},
+			ExpectErrors:   0,
+		},
+		{
+			TestName:       "Old control-planes are invalid with Windows workers",
+			ControlPlanes:  map[string]string{"control-plane": "v1.13.4"},
+			WindowsWorkers: map[string]string{"worker1": "v1.14.3"},
+			ExpectErrors:   1,
+		},
+		{
+			TestName:       "Old Windows workers are invalid",
+			ControlPlanes:  map[string]string{"control-plane": "v1.14.3"},
+			WindowsWorkers: map[string]string{"worker1": "v1.13.4", "worker2": "v1.12.3"},
+			ExpectErrors:   2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.TestName, func(t2 *testing.T) {
+			err := checkWindowsVersions(c.ControlPlanes, c.WindowsWorkers)
+			if c.ExpectErrors == 0 {
+				if err != nil {
+					t2.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			errs, ok := err.(util.Errors)
+			if !ok {
+				t2.Fatalf("expected util.Errors, saw: %v", err)
+			}
+			if len(errs) != c.ExpectErrors {
+				t2.Errorf("expected %d errors, saw %d: %v", c.ExpectErrors, len(errs), errs)
+			}
+		})
+	}
+}
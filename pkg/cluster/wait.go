@@ -0,0 +1,263 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+	"sigs.k8s.io/kind/pkg/cluster/nodes"
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// waitForCluster waits up to cc.waitForReady for the API server to be healthy,
+// all the Kubernetes nodes to be Ready and CoreDNS to be available. The nodes
+// are not Ready without a CNI network plugin, so only the API server is
+// waited for if the default one is not installed. The readiness checks of the
+// config are checked last
+func (cc *createContext) waitForCluster(nodeList map[string]*nodes.Node) error {
+	if cc.waitForReady <= 0 {
+		if len(cc.config.ReadinessChecks) > 0 {
+			cc.Logger().Warnf("The readiness checks are not checked as the cluster is not waited for, use --wait or timeouts.ready")
+		}
+		return nil
+	}
+	node, ok := nodeList[cc.derived.BootStrapControlPlane().Name]
+	if !ok {
+		return fmt.Errorf("unable to get the handle for operating on node: %s", cc.derived.BootStrapControlPlane().Name)
+	}
+	withCNI := installsDefaultCNI(cc.config)
+	expectedNodes := len(cc.derived.ControlPlanes()) + len(cc.derived.Workers())
+
+	status := cc.newStatus()
+	defer status.End(false)
+	if withCNI {
+		status.Start(fmt.Sprintf("Waiting ≤ %s for the nodes and CoreDNS to be ready ⏳", cc.waitForReady))
+	} else {
+		status.Start(fmt.Sprintf("Waiting ≤ %s for the API server to be ready ⏳", cc.waitForReady))
+	}
+
+	var pending []string
+	for until := time.Now().Add(cc.waitForReady); ; time.Sleep(cc.timeouts.PollInterval) {
+		pending = []string{}
+		if !apiServerReady(node) {
+			pending = append(pending, "the API server is not healthy")
+		} else {
+			if withCNI {
+				pending = append(pending, nodesNotReady(node, expectedNodes)...)
+				pending = append(pending, coreDNSNotReady(node)...)
+			}
+			if len(pending) == 0 {
+				for i := range cc.config.ReadinessChecks {
+					pending = append(pending, readinessCheckPending(node, &cc.config.ReadinessChecks[i])...)
+				}
+			}
+		}
+		if len(pending) == 0 {
+			status.End(true)
+			return nil
+		}
+		if time.Now().After(until) {
+			break
+		}
+	}
+
+	return &ClusterNotReadyError{
+		Wait:        cc.waitForReady,
+		Pending:     pending,
+		Diagnostics: readinessDiagnostics(node),
+	}
+}
+
+// apiServerReady returns true if the API server reports healthy
+func apiServerReady(node *nodes.Node) bool {
+	lines, err := exec.CombinedOutputLines(node.Command(
+		"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf", "get", "--raw=/healthz",
+	))
+	return err == nil && len(lines) == 1 && lines[0] == "ok"
+}
+
+// nodesNotReady returns the unmet node readiness conditions, checking that
+// expected nodes are registered and Ready
+func nodesNotReady(node *nodes.Node, expected int) []string {
+	lines, err := exec.CombinedOutputLines(node.Command(
+		"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf", "get", "nodes",
+		`-o=jsonpath={range .items[*]}{.metadata.name}{" "}{.status.conditions[?(@.type=="Ready")].status}{"\n"}{end}`,
+	))
+	if err != nil {
+		return []string{fmt.Sprintf("failed to get the nodes: %v", err)}
+	}
+	return parseNodesNotReady(lines, expected)
+}
+
+// parseNodesNotReady parses the name and Ready condition status of each node,
+// one per line, returning the unmet readiness conditions
+func parseNodesNotReady(lines []string, expected int) []string {
+	pending := []string{}
+	registered := 0
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		registered++
+		if len(fields) < 2 || fields[1] != "True" {
+			pending = append(pending, fmt.Sprintf("node %s is not Ready", fields[0]))
+		}
+	}
+	if registered < expected {
+		pending = append(pending, fmt.Sprintf("%d of %d nodes are registered", registered, expected))
+	}
+	return pending
+}
+
+// coreDNSNotReady returns the unmet CoreDNS readiness condition, if any
+func coreDNSNotReady(node *nodes.Node) []string {
+	lines, err := exec.CombinedOutputLines(node.Command(
+		"kubectl", "--kubeconfig=/etc/kubernetes/admin.conf", "-n", "kube-system",
+		"get", "deployment", "coredns",
+		"-o=jsonpath={.status.availableReplicas}/{.spec.replicas}",
+	))
+	if err != nil || len(lines) != 1 {
+		return []string{"the CoreDNS deployment is not found"}
+	}
+	return parseCoreDNSNotReady(lines[0])
+}
+
+// parseCoreDNSNotReady parses the available and desired replicas of CoreDNS,
+// as available/desired, returning the unmet readiness condition, if any
+func parseCoreDNSNotReady(replicas string) []string {
+	parts := strings.SplitN(replicas, "/", 2)
+	if len(parts) != 2 {
+		return []string{fmt.Sprintf("unexpected CoreDNS replicas %q", replicas)}
+	}
+	// availableReplicas is omitted while none are available
+	available, _ := strconv.Atoi(parts[0])
+	desired, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return []string{fmt.Sprintf("unexpected CoreDNS replicas %q", replicas)}
+	}
+	if available < desired {
+		return []string{fmt.Sprintf("%d of %d CoreDNS replicas are available", available, desired)}
+	}
+	return nil
+}
+
+// readinessCheckPending returns the unmet condition of the readiness check,
+// if any, see config.ReadinessCheck
+func readinessCheckPending(node *nodes.Node, check *config.ReadinessCheck) []string {
+	args := []string{"--kubeconfig=/etc/kubernetes/admin.conf"}
+	if check.Namespace != "" {
+		args = append(args, "-n", check.Namespace)
+	}
+	if check.Rollout {
+		args = append(args, "rollout", "status", check.Resource, "--watch=false")
+	} else {
+		jsonpath := `{.metadata.name}{" "}{.status.conditions[?(@.type=="` + check.Condition + `")].status}{"\n"}`
+		if !strings.Contains(check.Resource, "/") {
+			jsonpath = "{range .items[*]}" + jsonpath + "{end}"
+		}
+		args = append(args, "get", check.Resource, "-o=jsonpath="+jsonpath)
+		if check.Selector != "" {
+			args = append(args, "-l", check.Selector)
+		}
+	}
+	lines, err := exec.CombinedOutputLines(node.Command("kubectl", args...))
+	if err != nil {
+		return []string{fmt.Sprintf("%s is not found", readinessCheckTarget(check))}
+	}
+	if check.Rollout {
+		return parseRolloutPending(check, lines)
+	}
+	return parseConditionPending(check, lines)
+}
+
+// readinessCheckTarget describes the resources of the readiness check
+func readinessCheckTarget(check *config.ReadinessCheck) string {
+	target := check.Resource
+	if check.Selector != "" {
+		target += " -l " + check.Selector
+	}
+	if check.Namespace != "" {
+		target += " in namespace " + check.Namespace
+	}
+	return target
+}
+
+// parseRolloutPending parses the output of kubectl rollout status, returning
+// the unmet rollout, if any
+func parseRolloutPending(check *config.ReadinessCheck, lines []string) []string {
+	for _, line := range lines {
+		if strings.Contains(line, "successfully rolled out") {
+			return nil
+		}
+	}
+	status := "no status"
+	if len(lines) > 0 {
+		status = strings.TrimSpace(lines[len(lines)-1])
+	}
+	return []string{fmt.Sprintf("%s is not rolled out: %s", readinessCheckTarget(check), status)}
+}
+
+// parseConditionPending parses the name and condition status of each of the
+// resources, one per line, returning the unmet conditions
+func parseConditionPending(check *config.ReadinessCheck, lines []string) []string {
+	pending := []string{}
+	resourceType := strings.SplitN(check.Resource, "/", 2)[0]
+	found := 0
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		found++
+		if len(fields) < 2 || fields[1] != "True" {
+			pending = append(pending, fmt.Sprintf("%s/%s is not %s", resourceType, fields[0], check.Condition))
+		}
+	}
+	if found == 0 {
+		pending = append(pending, fmt.Sprintf("no %s found", readinessCheckTarget(check)))
+	}
+	return pending
+}
+
+// readinessDiagnostics returns the state of the nodes and of the system
+// pods, to explain why the cluster is not ready
+func readinessDiagnostics(node *nodes.Node) string {
+	var b strings.Builder
+	for _, args := range [][]string{
+		{"get", "nodes", "-o", "wide"},
+		{"-n", "kube-system", "get", "pods", "-o", "wide"},
+		{"get", "events", "--all-namespaces", "--field-selector=type=Warning"},
+	} {
+		fmt.Fprintf(&b, "$ kubectl %s\n", strings.Join(args, " "))
+		lines, err := exec.CombinedOutputLines(node.Command(
+			"kubectl", append([]string{"--kubeconfig=/etc/kubernetes/admin.conf"}, args...)...,
+		))
+		for _, line := range lines {
+			fmt.Fprintln(&b, line)
+		}
+		if err != nil {
+			fmt.Fprintf(&b, "error: %v\n", err)
+		}
+		fmt.Fprintln(&b)
+	}
+	return strings.TrimSpace(b.String())
+}
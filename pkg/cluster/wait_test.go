@@ -0,0 +1,115 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/kind/pkg/cluster/config"
+)
+
+func TestParseNodesNotReady(t *testing.T) {
+	cases := []struct {
+		TestName string
+		Lines    []string
+		Expected []string
+	}{
+		{
+			TestName: "All nodes Ready",
+			Lines:    []string{"kind-1-control-plane True", "kind-1-worker True"},
+			Expected: []string{},
+		},
+		{
+			TestName: "Node not Ready",
+			Lines:    []string{"kind-1-control-plane True", "kind-1-worker False", ""},
+			Expected: []string{"node kind-1-worker is not Ready"},
+		},
+		{
+			TestName: "Node without conditions and missing node",
+			Lines:    []string{"kind-1-control-plane"},
+			Expected: []string{"node kind-1-control-plane is not Ready", "1 of 2 nodes are registered"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.TestName, func(t2 *testing.T) {
+			if pending := parseNodesNotReady(tc.Lines, 2); !reflect.DeepEqual(pending, tc.Expected) {
+				t2.Errorf("expected %q but got %q", tc.Expected, pending)
+			}
+		})
+	}
+}
+
+func TestParseCoreDNSNotReady(t *testing.T) {
+	cases := map[string][]string{
+		"2/2": nil,
+		"1/2": {"1 of 2 CoreDNS replicas are available"},
+		"/2":  {"0 of 2 CoreDNS replicas are available"},
+		"2":   {`unexpected CoreDNS replicas "2"`},
+	}
+	for replicas, expected := range cases {
+		if pending := parseCoreDNSNotReady(replicas); !reflect.DeepEqual(pending, expected) {
+			t.Errorf("expected %q for %s but got %q", expected, replicas, pending)
+		}
+	}
+}
+
+func TestParseConditionPending(t *testing.T) {
+	cases := []struct {
+		TestName string
+		Check    config.ReadinessCheck
+		Lines    []string
+		Expected []string
+	}{
+		{
+			TestName: "CRD established",
+			Check:    config.ReadinessCheck{Resource: "crd/foos.example.com", Condition: "Established"},
+			Lines:    []string{"foos.example.com True"},
+			Expected: []string{},
+		},
+		{
+			TestName: "Pods not Ready",
+			Check:    config.ReadinessCheck{Resource: "pods", Namespace: "operators", Selector: "app=operator", Condition: "Ready"},
+			Lines:    []string{"operator-1 True", "operator-2 False", "operator-3"},
+			Expected: []string{"pods/operator-2 is not Ready", "pods/operator-3 is not Ready"},
+		},
+		{
+			TestName: "No pods",
+			Check:    config.ReadinessCheck{Resource: "pods", Namespace: "operators", Selector: "app=operator", Condition: "Ready"},
+			Lines:    []string{""},
+			Expected: []string{"no pods -l app=operator in namespace operators found"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.TestName, func(t2 *testing.T) {
+			if pending := parseConditionPending(&tc.Check, tc.Lines); !reflect.DeepEqual(pending, tc.Expected) {
+				t2.Errorf("expected %q but got %q", tc.Expected, pending)
+			}
+		})
+	}
+}
+
+func TestParseRolloutPending(t *testing.T) {
+	check := &config.ReadinessCheck{Resource: "daemonset/operator", Namespace: "operators", Rollout: true}
+	if pending := parseRolloutPending(check, []string{`daemon set "operator" successfully rolled out`}); pending != nil {
+		t.Errorf("expected no pending rollout but got %q", pending)
+	}
+	expected := []string{`daemonset/operator in namespace operators is not rolled out: Waiting for daemon set "operator" rollout to finish: 1 of 3 updated pods are available...`}
+	if pending := parseRolloutPending(check, []string{`Waiting for daemon set "operator" rollout to finish: 1 of 3 updated pods are available...`}); !reflect.DeepEqual(pending, expected) {
+		t.Errorf("expected %q but got %q", expected, pending)
+	}
+}
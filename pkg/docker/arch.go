@@ -0,0 +1,85 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// SupportedArchs are the architectures node images can be built for
+var SupportedArchs = []string{"amd64", "arm64", "ppc64le", "s390x"}
+
+// SupportedArch returns true if arch is one of SupportedArchs
+func SupportedArch(arch string) bool {
+	for _, supported := range SupportedArchs {
+		if arch == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeArch returns the image architecture name of arch, the runtimes
+// report the kernel names of some architectures, e.g. "x86_64" for "amd64"
+func NormalizeArch(arch string) string {
+	switch arch {
+	case "x86_64":
+		return "amd64"
+	case "aarch64", "armv8", "armv8l":
+		return "arm64"
+	}
+	return arch
+}
+
+// HostArch returns the architecture of the host running the containers, as
+// reported by the provider, or of the kind binary if not available
+func HostArch() string {
+	info, err := CurrentProvider().Info()
+	if err != nil || info.Architecture == "" {
+		return runtime.GOARCH
+	}
+	return info.Architecture
+}
+
+// ImageArch returns the architecture of the local image
+func ImageArch(image string) (string, error) {
+	lines, err := exec.CombinedOutputLines(Command(
+		"image", "inspect", "--format", "{{.Architecture}}", image,
+	))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to inspect image %s", image)
+	}
+	if len(lines) != 1 {
+		return "", fmt.Errorf("image architecture should only be one line, got %d lines", len(lines))
+	}
+	return NormalizeArch(strings.TrimSpace(lines[0])), nil
+}
+
+// PlatformArgs returns the pull, run and build arguments selecting the linux
+// platform of arch, which is not needed for the host architecture, see HostArch
+func PlatformArgs(arch string) []string {
+	if arch == "" || arch == HostArch() {
+		return nil
+	}
+	return []string{"--platform", "linux/" + arch}
+}
@@ -25,6 +25,8 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 // GetArchiveTags obtains a list of "repo:tag" docker image tags from a
@@ -71,3 +73,235 @@ func GetArchiveTags(path string) ([]string, error) {
 	}
 	return res, nil
 }
+
+// ArchiveImage is an image in a docker image archive
+type ArchiveImage struct {
+	// ID is the image ID, the digest of the image config
+	ID string
+	// RepoTags are the "repo:tag" tags of the image, if any
+	RepoTags []string
+}
+
+// GetArchiveImages obtains the images in a given docker image archive
+// (tarball) path from its manifest.json, this is only compatible with the
+// v1.2 spec, as written by `docker save` since docker 1.10
+// https://github.com/moby/moby/blob/master/image/spec/v1.2.md
+func GetArchiveImages(path string) ([]ArchiveImage, error) {
+	contents, err := readArchiveFile(path, "manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	if contents == nil {
+		return nil, fmt.Errorf("could not find image manifest")
+	}
+	return ParseArchiveManifest(contents)
+}
+
+// ParseArchiveManifest parses the images in the contents of the
+// manifest.json of a docker image archive, see GetArchiveImages
+func ParseArchiveManifest(contents []byte) ([]ArchiveImage, error) {
+	var manifests []archiveManifest
+	if err := json.Unmarshal(contents, &manifests); err != nil {
+		return nil, err
+	}
+	res := []ArchiveImage{}
+	for _, m := range manifests {
+		// the config is named after its digest, either <hex>.json or
+		// blobs/sha256/<hex>
+		id := strings.TrimSuffix(filepath.Base(m.Config), ".json")
+		res = append(res, ArchiveImage{
+			ID:       "sha256:" + id,
+			RepoTags: m.RepoTags,
+		})
+	}
+	return res, nil
+}
+
+// IsOCIArchive returns true if path is an OCI image layout tarball, rather
+// than a docker image archive. Archives in both formats are docker archives
+func IsOCIArchive(path string) (bool, error) {
+	manifest, err := readArchiveFile(path, "manifest.json")
+	if err != nil || manifest != nil {
+		return false, err
+	}
+	layout, err := readArchiveFile(path, ociLayoutFile)
+	if err != nil {
+		return false, err
+	}
+	return layout != nil, nil
+}
+
+// readArchiveFile returns the contents of the file name in the tarball at
+// path, or nil if there is no such file
+func readArchiveFile(path, name string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if filepath.Clean(hdr.Name) == name {
+			return ioutil.ReadAll(tr)
+		}
+	}
+}
+
+// ExtractArchive extracts the regular files and directories of the tarball at
+// path into dir
+func ExtractArchive(path, dir string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// do not write outside of dir
+		name := filepath.Clean(hdr.Name)
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("invalid archive entry %s", hdr.Name)
+		}
+		target := filepath.Join(dir, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			out, err := os.Create(target)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(out, tr)
+			out.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// AddArchiveTags adds the "repo:tag" tags to the single image in the docker
+// image archive (tarball) at path, in both its manifest.json and repositories
+func AddArchiveTags(path string, tags ...string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp := path + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	if err := addArchiveTags(tar.NewReader(in), tar.NewWriter(out), tags); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// addArchiveTags copies the archive in tr to tw, adding tags to its metadata
+func addArchiveTags(tr *tar.Reader, tw *tar.Writer, tags []string) error {
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var contents []byte
+		switch filepath.Clean(hdr.Name) {
+		case "manifest.json":
+			if contents, err = addManifestTags(tr, tags); err != nil {
+				return err
+			}
+		case "repositories":
+			if contents, err = addRepositoriesTags(tr, tags); err != nil {
+				return err
+			}
+		}
+		if contents != nil {
+			hdr.Size = int64(len(contents))
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := tw.Write(contents); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func addManifestTags(r io.Reader, tags []string) ([]byte, error) {
+	var manifests []map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&manifests); err != nil {
+		return nil, err
+	}
+	if len(manifests) != 1 {
+		return nil, fmt.Errorf("expected one image in the archive, found %d", len(manifests))
+	}
+	repoTags, _ := manifests[0]["RepoTags"].([]interface{})
+	for _, tag := range tags {
+		repoTags = append(repoTags, tag)
+	}
+	manifests[0]["RepoTags"] = repoTags
+	return json.Marshal(manifests)
+}
+
+func addRepositoriesTags(r io.Reader, tags []string) ([]byte, error) {
+	var repoTags map[string]map[string]string
+	if err := json.NewDecoder(r).Decode(&repoTags); err != nil {
+		return nil, err
+	}
+	// all the tags refer to the same top layer
+	layer := ""
+	for _, tagLayers := range repoTags {
+		for _, l := range tagLayers {
+			layer = l
+		}
+	}
+	for _, tag := range tags {
+		i := strings.LastIndex(tag, ":")
+		if i < 0 || i < strings.LastIndex(tag, "/") {
+			return nil, fmt.Errorf("invalid image tag %q", tag)
+		}
+		repo := tag[:i]
+		if repoTags[repo] == nil {
+			repoTags[repo] = map[string]string{}
+		}
+		repoTags[repo][tag[i+1:]] = layer
+	}
+	return json.Marshal(repoTags)
+}
@@ -17,13 +17,20 @@ limitations under the License.
 package docker
 
 import (
-	"sigs.k8s.io/kind/pkg/exec"
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // CopyTo copies the file at hostPath to the container at destPath
 func CopyTo(hostPath, containerNameOrID, destPath string) error {
-	cmd := exec.Command(
-		"docker", "cp",
+	cmd := Command(
+		"cp",
 		hostPath,                       // from the source file
 		containerNameOrID+":"+destPath, // to the node, at dest
 	)
@@ -32,10 +39,55 @@ func CopyTo(hostPath, containerNameOrID, destPath string) error {
 
 // CopyFrom copies the file or dir in the container at srcPath to the host at hostPath
 func CopyFrom(containerNameOrID, srcPath, hostPath string) error {
-	cmd := exec.Command(
-		"docker", "cp",
+	cmd := Command(
+		"cp",
 		containerNameOrID+":"+srcPath, // from the node, at src
 		hostPath,                      // to the host
 	)
 	return cmd.Run()
 }
+
+// FileExists returns true if the file or dir at path exists in the container,
+// which does not need to be running, and an error if this can not be determined
+func FileExists(containerNameOrID, path string) (bool, error) {
+	var stderr bytes.Buffer
+	cmd := Command("cp", containerNameOrID+":"+path, "-")
+	cmd.SetStdout(ioutil.Discard)
+	cmd.SetStderr(&stderr)
+	if err := cmd.Run(); err != nil {
+		// docker reports a missing path, unlike a missing container, as
+		// "No such container:path" or "Could not find the file" depending
+		// on the version
+		output := stderr.String()
+		if strings.Contains(output, "No such container:path") || strings.Contains(output, "Could not find the file") {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to check for %s in %s: %s", path, containerNameOrID, strings.TrimSpace(output))
+	}
+	return true, nil
+}
+
+// ReadFile returns the contents of the file in the container at srcPath, the
+// container does not need to be running, as in `docker cp <container>:<src> -`
+func ReadFile(containerNameOrID, srcPath string) ([]byte, error) {
+	var buff bytes.Buffer
+	cmd := Command("cp", containerNameOrID+":"+srcPath, "-")
+	cmd.SetStdout(&buff)
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+	// the file is written to stdout as a tarball
+	tr := tar.NewReader(&buff)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s is not a file", srcPath)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeReg || hdr.Typeflag == tar.TypeRegA {
+			return ioutil.ReadAll(tr)
+		}
+	}
+}
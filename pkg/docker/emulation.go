@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// BinfmtImage is the image registering the QEMU emulators of other
+// architectures with the host kernel, see InstallEmulation
+const BinfmtImage = "tonistiigi/binfmt"
+
+// CheckEmulation returns an error if the containers of image for the linux
+// platform of arch can not run on the host, as for other architectures than
+// the host architecture without QEMU emulation
+func CheckEmulation(image, arch string) error {
+	if arch == "" || arch == HostArch() {
+		return nil
+	}
+	args := append([]string{"run", "--rm", "--entrypoint=/bin/sh"}, PlatformArgs(arch)...)
+	lines, err := exec.CombinedOutputLines(Command(append(args, image, "-c", "uname -m")...))
+	if err == nil && len(lines) == 1 && NormalizeArch(strings.TrimSpace(lines[0])) == arch {
+		return nil
+	}
+	return fmt.Errorf(
+		"can not run %s containers on this %s host, QEMU emulation is required, install it with: docker run --privileged --rm %s --install %s",
+		arch, HostArch(), BinfmtImage, arch,
+	)
+}
+
+// InstallEmulation registers the QEMU emulators of the architectures archs
+// with the host kernel, by running the privileged BinfmtImage container
+func InstallEmulation(archs ...string) error {
+	return Command("run", "--privileged", "--rm", BinfmtImage, "--install", strings.Join(archs, ",")).Run()
+}
+
+// ForeignArchs returns the architectures of archs other than the host
+// architecture, which require emulation
+func ForeignArchs(archs []string) []string {
+	host := HostArch()
+	foreign := []string{}
+	for _, arch := range archs {
+		if arch != host {
+			foreign = append(foreign, arch)
+		}
+	}
+	return foreign
+}
@@ -18,6 +18,7 @@ package docker
 
 import (
 	"io"
+	"strings"
 
 	"sigs.k8s.io/kind/pkg/exec"
 )
@@ -87,7 +88,9 @@ func (c *containerCmd) Run() error {
 		// finally, with the caller args
 		c.args...,
 	)
-	cmd := exec.Command("docker", args...)
+	cmd := Command(args...)
+	exec.SetSpanAttribute(cmd, "kind.node", c.nameOrID)
+	exec.SetSpanAttribute(cmd, "kind.node.command", strings.Join(append([]string{c.command}, c.args...), " "))
 	if c.stdin != nil {
 		cmd.SetStdin(c.stdin)
 	}
@@ -16,14 +16,10 @@ limitations under the License.
 
 package docker
 
-import (
-	"sigs.k8s.io/kind/pkg/exec"
-)
-
 // Kill sends the named signal to the container
 func Kill(signal, containerNameOrID string) error {
-	cmd := exec.Command(
-		"docker", "kill",
+	cmd := Command(
+		"kill",
 		"-s", signal,
 		containerNameOrID,
 	)
@@ -0,0 +1,64 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CreateManifestList creates the local manifest list named list referencing
+// images, replacing any existing one, as in `docker manifest create`.
+// The images must have been pushed, the platform of each image is read from
+// its registry
+func CreateManifestList(list string, images ...string) error {
+	return Command(append([]string{"manifest", "create", "--amend", list}, images...)...).Run()
+}
+
+// PushManifestList pushes the local manifest list named list to its
+// registry and removes it locally, as in `docker manifest push`
+func PushManifestList(list string) error {
+	return Command("manifest", "push", "--purge", list).Run()
+}
+
+// ArchImage returns the name of the image built for arch when building for
+// more than one architecture, the tag of image suffixed with the
+// architecture, e.g. kindest/node:latest-arm64
+func ArchImage(image, arch string) string {
+	// the tag follows the last colon after the last slash, if any
+	if strings.LastIndex(image, ":") > strings.LastIndex(image, "/") {
+		return image + "-" + arch
+	}
+	return image + ":latest-" + arch
+}
+
+// PushMultiArch pushes the images of each architecture, and the manifest list
+// named list referencing them
+func PushMultiArch(list string, images ...string) error {
+	for _, image := range images {
+		if err := Push(image); err != nil {
+			return fmt.Errorf("error pushing image %s: %v", image, err)
+		}
+	}
+	if err := CreateManifestList(list, images...); err != nil {
+		return fmt.Errorf("error creating manifest list %s: %v", list, err)
+	}
+	if err := PushManifestList(list); err != nil {
+		return fmt.Errorf("error pushing manifest list %s: %v", list, err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// NetworkMTUOption is the bridge network driver option setting the MTU
+const NetworkMTUOption = "com.docker.network.driver.mtu"
+
+// CreateNetwork creates a bridge network with the given labels, as in
+// `docker network create`, the IPv4 subnet and gateway are allocated by docker
+// if not set, and if ipv6Subnet is set IPv6 is enabled on the network.
+// The MTU is the docker default if mtu is 0
+func CreateNetwork(name, subnet, gateway, ipv6Subnet string, mtu int32, labels ...string) error {
+	args := []string{"network", "create", "--driver=bridge"}
+	if mtu != 0 {
+		args = append(args, "--opt", fmt.Sprintf("%s=%d", CurrentProvider().NetworkMTUOption(), mtu))
+	}
+	if subnet != "" {
+		args = append(args, "--subnet", subnet)
+	}
+	if gateway != "" {
+		args = append(args, "--gateway", gateway)
+	}
+	if ipv6Subnet != "" {
+		args = append(args, "--ipv6", "--subnet", ipv6Subnet)
+	}
+	for _, label := range labels {
+		args = append(args, "--label", label)
+	}
+	args = append(args, name)
+	cmd := Command(args...)
+	return cmd.Run()
+}
+
+// InspectNetwork return low-level information on a network, as in
+// `docker network inspect`
+func InspectNetwork(name, format string) ([]string, error) {
+	cmd := Command("network", "inspect",
+		"-f", // format
+		format,
+		name,
+	)
+	return exec.CombinedOutputLines(cmd)
+}
+
+// NetworkSubnet is a subnet of a network
+type NetworkSubnet struct {
+	// Subnet is the CIDR of the subnet
+	Subnet string
+	// Gateway is the gateway address of the subnet, if any
+	Gateway string
+}
+
+// IPv6 returns true if this is an IPv6 subnet
+func (s NetworkSubnet) IPv6() bool {
+	return strings.Contains(s.Subnet, ":")
+}
+
+// NetworkSubnets returns the subnets of the network with the given name
+func NetworkSubnets(name string) ([]NetworkSubnet, error) {
+	lines, err := InspectNetwork(name, CurrentProvider().NetworkFormats().Subnets)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) != 1 {
+		return nil, fmt.Errorf("network subnets should only be one line, got %d lines", len(lines))
+	}
+	subnets := []NetworkSubnet{}
+	for _, subnet := range strings.Split(lines[0], ";") {
+		fields := strings.Fields(subnet)
+		if len(fields) == 0 {
+			continue
+		}
+		s := NetworkSubnet{Subnet: fields[0]}
+		// unset gateways are printed as <nil> by some providers
+		if len(fields) > 1 && fields[1] != "<nil>" {
+			s.Gateway = fields[1]
+		}
+		subnets = append(subnets, s)
+	}
+	return subnets, nil
+}
+
+// NetworkMTU returns the MTU of the network with the given name, or 0 if it
+// is the default MTU or the provider does not report it
+func NetworkMTU(name string) (int32, error) {
+	format := CurrentProvider().NetworkFormats().MTU
+	if format == "" {
+		return 0, nil
+	}
+	lines, err := InspectNetwork(name, format)
+	if err != nil {
+		return 0, err
+	}
+	if len(lines) != 1 {
+		return 0, fmt.Errorf("network MTU should only be one line, got %d lines", len(lines))
+	}
+	// the MTU option is not set for networks with the default MTU
+	if lines[0] == "" || lines[0] == "<no value>" {
+		return 0, nil
+	}
+	mtu, err := strconv.ParseInt(lines[0], 10, 32)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to parse the network MTU")
+	}
+	return int32(mtu), nil
+}
+
+// ListNetworks returns the names of the networks matching all of the given
+// filters, as in `docker network ls`
+// https://docs.docker.com/engine/reference/commandline/network_ls/#filtering
+func ListNetworks(filters ...string) ([]string, error) {
+	args := []string{"network", "ls", "--format={{.Name}}"}
+	for _, filter := range filters {
+		args = append(args, "--filter", filter)
+	}
+	cmd := Command(args...)
+	return exec.CombinedOutputLines(cmd)
+}
+
+// DeleteNetworks deletes one or more networks, as in `docker network rm`
+func DeleteNetworks(names ...string) error {
+	cmd := Command(
+		append([]string{"network", "rm"}, names...)...,
+	)
+	return cmd.Run()
+}
+
+// ConnectNetwork connects the container to the network, as in
+// `docker network connect`
+func ConnectNetwork(network, containerNameOrID string) error {
+	return Command("network", "connect", network, containerNameOrID).Run()
+}
+
+// DisconnectNetwork disconnects the container from the network, as in
+// `docker network disconnect`
+func DisconnectNetwork(network, containerNameOrID string) error {
+	return Command("network", "disconnect", network, containerNameOrID).Run()
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// https://github.com/opencontainers/image-spec/blob/master/image-layout.md
+const (
+	ociLayoutFile = "oci-layout"
+	ociIndexFile  = "index.json"
+
+	ociImageIndexMediaType      = "application/vnd.oci.image.index.v1+json"
+	dockerManifestListMediaType = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+	// the annotations naming the images in the layout index, the containerd
+	// one is the full image reference, the OCI one may be only the tag
+	containerdImageNameAnnotation = "io.containerd.image.name"
+	ociRefNameAnnotation          = "org.opencontainers.image.ref.name"
+)
+
+// ociDescriptor is an OCI content descriptor
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Platform    *struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+	} `json:"platform,omitempty"`
+}
+
+// ociIndex is an OCI image index, or a docker manifest list
+type ociIndex struct {
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+// ociManifest is an OCI image manifest, or a docker image manifest
+type ociManifest struct {
+	Config ociDescriptor   `json:"config"`
+	Layers []ociDescriptor `json:"layers"`
+}
+
+// archiveManifest is an entry of the manifest.json of docker image archives
+// https://github.com/moby/moby/blob/master/image/spec/v1.2.md
+type archiveManifest struct {
+	Config   string
+	RepoTags []string
+	Layers   []string
+}
+
+// IsOCILayout returns true if dir is an OCI image layout directory
+func IsOCILayout(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ociLayoutFile))
+	return err == nil
+}
+
+// WriteArchiveFromOCILayout writes the named images of the OCI image layout
+// at src, a directory or a tarball, to a docker image archive at dest, which
+// `docker load` supports unlike OCI layouts. Image indexes are resolved to
+// the image for the linux platform with the given architecture, e.g. amd64.
+// The digests of all the blobs are verified while copying them
+func WriteArchiveFromOCILayout(src, dest, arch string) error {
+	dir := src
+	if !IsOCILayout(src) {
+		tmp, err := ioutil.TempDir("", "oci-layout")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+		if err := ExtractArchive(src, tmp); err != nil {
+			return errors.Wrapf(err, "failed to extract OCI layout %s", src)
+		}
+		dir = tmp
+	}
+
+	var index ociIndex
+	if err := readOCIBlobJSON(filepath.Join(dir, ociIndexFile), "", &index); err != nil {
+		return err
+	}
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	tw := tar.NewWriter(out)
+
+	manifests := []*archiveManifest{}
+	byConfig := map[string]*archiveManifest{}
+	written := map[string]bool{}
+	for _, desc := range index.Manifests {
+		name := ociImageName(desc.Annotations)
+		if name == "" {
+			return fmt.Errorf("image %s in OCI layout %s has no name, the %s annotation must be set", desc.Digest, src, containerdImageNameAnnotation)
+		}
+		manifest, err := resolveOCIManifest(dir, desc, arch)
+		if err != nil {
+			return err
+		}
+
+		// the same image may be named more than once
+		if m, ok := byConfig[manifest.Config.Digest]; ok {
+			m.RepoTags = append(m.RepoTags, name)
+			continue
+		}
+		m := &archiveManifest{
+			Config:   digestHex(manifest.Config.Digest) + ".json",
+			RepoTags: []string{name},
+		}
+		if err := writeOCIBlob(tw, dir, manifest.Config.Digest, m.Config); err != nil {
+			return err
+		}
+		for _, layer := range manifest.Layers {
+			// docker load decompresses the layers as needed
+			path := digestHex(layer.Digest) + "/layer.tar"
+			m.Layers = append(m.Layers, path)
+			if written[path] {
+				continue
+			}
+			if err := writeOCIBlob(tw, dir, layer.Digest, path); err != nil {
+				return err
+			}
+			written[path] = true
+		}
+		manifests = append(manifests, m)
+		byConfig[manifest.Config.Digest] = m
+	}
+	if len(manifests) == 0 {
+		return fmt.Errorf("no images found in OCI layout %s", src)
+	}
+
+	contents, err := json.Marshal(manifests)
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name: "manifest.json",
+		Mode: 0644,
+		Size: int64(len(contents)),
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(contents); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+// ociImageName returns the image reference of an OCI layout index entry
+// from its annotations, or "" if there is none
+func ociImageName(annotations map[string]string) string {
+	if name := annotations[containerdImageNameAnnotation]; name != "" {
+		return name
+	}
+	// the OCI ref name is only usable if it is a full image reference,
+	// not just a tag
+	if name := annotations[ociRefNameAnnotation]; strings.ContainsAny(name, "/:") {
+		return name
+	}
+	return ""
+}
+
+// resolveOCIManifest returns the image manifest desc refers to, resolving
+// image indexes to the image for the linux platform with the given
+// architecture
+func resolveOCIManifest(dir string, desc ociDescriptor, arch string) (*ociManifest, error) {
+	if desc.MediaType == ociImageIndexMediaType || desc.MediaType == dockerManifestListMediaType {
+		var index ociIndex
+		if err := readOCIBlobJSON(ociBlobPath(dir, desc.Digest), desc.Digest, &index); err != nil {
+			return nil, err
+		}
+		for _, m := range index.Manifests {
+			if m.Platform != nil && m.Platform.OS == "linux" && m.Platform.Architecture == arch {
+				return resolveOCIManifest(dir, m, arch)
+			}
+		}
+		return nil, fmt.Errorf("image index %s has no image for linux/%s", desc.Digest, arch)
+	}
+	var manifest ociManifest
+	if err := readOCIBlobJSON(ociBlobPath(dir, desc.Digest), desc.Digest, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// ociBlobPath returns the path of the blob with the given digest in the OCI
+// layout directory dir
+func ociBlobPath(dir, digest string) string {
+	algorithm := strings.SplitN(digest, ":", 2)[0]
+	return filepath.Join(dir, "blobs", algorithm, digestHex(digest))
+}
+
+// digestHex returns the encoded part of digest, e.g. the hex of sha256 digests
+func digestHex(digest string) string {
+	parts := strings.SplitN(digest, ":", 2)
+	return parts[len(parts)-1]
+}
+
+// readOCIBlobJSON parses the JSON file at path into v, verifying its digest
+// unless empty
+func readOCIBlobJSON(path, digest string, v interface{}) error {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if digest != "" {
+		if err := verifyDigest(digest, sha256.Sum256(contents)); err != nil {
+			return err
+		}
+	}
+	if err := json.Unmarshal(contents, v); err != nil {
+		return errors.Wrapf(err, "failed to parse %s", path)
+	}
+	return nil
+}
+
+// writeOCIBlob copies the blob with the given digest from the OCI layout
+// directory dir to the tar entry name, verifying its digest
+func writeOCIBlob(tw *tar.Writer, dir, digest, name string) error {
+	f, err := os.Open(ociBlobPath(dir, digest))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name: name,
+		Mode: 0644,
+		Size: info.Size(),
+	}); err != nil {
+		return err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tw, h), f); err != nil {
+		return err
+	}
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return errors.Wrapf(verifyDigest(digest, sum), "failed to verify blob %s", name)
+}
+
+// verifyDigest returns an error unless digest is the sha256 digest sum
+func verifyDigest(digest string, sum [sha256.Size]byte) error {
+	if !strings.HasPrefix(digest, "sha256:") {
+		return fmt.Errorf("unsupported digest algorithm in %s", digest)
+	}
+	if actual := "sha256:" + hex.EncodeToString(sum[:]); actual != digest {
+		return fmt.Errorf("digest mismatch, expected %s but got %s", digest, actual)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+// Pause suspends all the processes of one or more running containers, as in
+// `docker pause`
+func Pause(containerNameOrIDs ...string) error {
+	cmd := Command(
+		append([]string{"pause"}, containerNameOrIDs...)...,
+	)
+	return cmd.Run()
+}
+
+// Unpause resumes the processes of one or more paused containers, as in
+// `docker unpause`
+func Unpause(containerNameOrIDs ...string) error {
+	cmd := Command(
+		append([]string{"unpause"}, containerNameOrIDs...)...,
+	)
+	return cmd.Run()
+}
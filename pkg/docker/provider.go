@@ -0,0 +1,329 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/kind/pkg/exec"
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// ProviderEnv is the environment variable selecting the provider, see
+// CurrentProvider
+const ProviderEnv = "KIND_EXPERIMENTAL_PROVIDER"
+
+// the supported providers
+const (
+	// DockerProviderName is the name of the docker provider
+	DockerProviderName = "docker"
+	// PodmanProviderName is the name of the podman provider
+	PodmanProviderName = "podman"
+	// NerdctlProviderName is the name of the nerdctl provider
+	NerdctlProviderName = "nerdctl"
+)
+
+// Provider is a container runtime the node containers are run with, the
+// functions of this package run its CLI which must be compatible with the
+// docker CLI
+type Provider interface {
+	// Name returns the name of the provider, e.g. "docker"
+	Name() string
+	// Command returns a new command running the provider CLI with args
+	Command(args ...string) exec.Cmd
+	// Rootless returns true if the containers are run by an unprivileged
+	// user, in which case host ports below 1024 can not be published
+	Rootless() bool
+	// NetworkMTUOption returns the bridge network driver option setting the MTU
+	NetworkMTUOption() string
+	// NetworkFormats returns the network inspect formats of the network
+	// settings, which differ between the providers
+	NetworkFormats() NetworkFormats
+	// RemoteHost returns the host running the containers if the provider
+	// drives a remote daemon, e.g. with DOCKER_HOST, and empty otherwise
+	RemoteHost() string
+	// Info returns information on the runtime, it fails if the runtime is
+	// not available
+	Info() (ProviderInfo, error)
+}
+
+// ProviderInfo is information on the runtime of a provider, see Provider.Info
+type ProviderInfo struct {
+	// Version is the version of the runtime, e.g. of the docker daemon
+	Version string
+	// StorageRoot is the directory the runtime stores the images and the
+	// containers in, if known
+	StorageRoot string
+	// Architecture is the architecture of the host running the containers,
+	// normalized to the architecture names of images, e.g. "amd64"
+	Architecture string
+}
+
+// NetworkFormats are the network inspect formats of a provider, see
+// NetworkSubnets and NetworkMTU
+type NetworkFormats struct {
+	// Subnets prints "<subnet> <gateway>;" for each subnet of the network
+	Subnets string
+	// MTU prints the MTU of the network, or nothing for the default MTU, it
+	// is empty if the provider does not report the MTU
+	MTU string
+}
+
+// dockerProvider implements Provider for docker
+type dockerProvider struct{}
+
+func (dockerProvider) Name() string {
+	return DockerProviderName
+}
+
+func (dockerProvider) Command(args ...string) exec.Cmd {
+	return exec.Command("docker", args...)
+}
+
+func (dockerProvider) Rootless() bool {
+	// the rootless docker daemon reports it as a security option
+	lines, err := exec.CombinedOutputLines(exec.Command(
+		"docker", "info", "--format", "{{json .SecurityOptions}}",
+	))
+	return err == nil && len(lines) > 0 && strings.Contains(lines[0], "name=rootless")
+}
+
+func (dockerProvider) NetworkMTUOption() string {
+	return NetworkMTUOption
+}
+
+func (dockerProvider) NetworkFormats() NetworkFormats {
+	return NetworkFormats{
+		Subnets: "{{range .IPAM.Config}}{{.Subnet}} {{.Gateway}};{{end}}",
+		MTU:     fmt.Sprintf("{{index .Options %q}}", NetworkMTUOption),
+	}
+}
+
+func (dockerProvider) RemoteHost() string {
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" {
+		// otherwise the endpoint of the current docker context is used
+		lines, err := exec.CombinedOutputLines(exec.Command(
+			"docker", "context", "inspect", "--format", "{{.Endpoints.docker.Host}}",
+		))
+		if err == nil && len(lines) == 1 {
+			host = lines[0]
+		}
+	}
+	return remoteHost(host)
+}
+
+func (p dockerProvider) Info() (ProviderInfo, error) {
+	return providerInfo(p, "{{.ServerVersion}}\t{{.DockerRootDir}}\t{{.Architecture}}")
+}
+
+// podmanProvider implements Provider for podman, rootful or rootless
+type podmanProvider struct{}
+
+func (podmanProvider) Name() string {
+	return PodmanProviderName
+}
+
+func (podmanProvider) Command(args ...string) exec.Cmd {
+	return exec.Command("podman", args...)
+}
+
+func (podmanProvider) Rootless() bool {
+	// podman runs the containers of the user running it
+	return os.Geteuid() != 0
+}
+
+func (podmanProvider) NetworkMTUOption() string {
+	return "mtu"
+}
+
+func (podmanProvider) NetworkFormats() NetworkFormats {
+	return NetworkFormats{
+		Subnets: "{{range .Subnets}}{{.Subnet}} {{.Gateway}};{{end}}",
+		MTU:     `{{index .Options "mtu"}}`,
+	}
+}
+
+func (podmanProvider) RemoteHost() string {
+	return remoteHost(os.Getenv("CONTAINER_HOST"))
+}
+
+func (p podmanProvider) Info() (ProviderInfo, error) {
+	return providerInfo(p, "{{.Version.Version}}\t{{.Store.GraphRoot}}\t{{.Host.Arch}}")
+}
+
+// nerdctlProvider implements Provider for containerd, with the nerdctl CLI
+type nerdctlProvider struct{}
+
+func (nerdctlProvider) Name() string {
+	return NerdctlProviderName
+}
+
+func (nerdctlProvider) Command(args ...string) exec.Cmd {
+	return exec.Command("nerdctl", args...)
+}
+
+func (nerdctlProvider) Rootless() bool {
+	// nerdctl drives the rootless containerd of the user running it
+	return os.Geteuid() != 0
+}
+
+func (nerdctlProvider) NetworkMTUOption() string {
+	return NetworkMTUOption
+}
+
+func (nerdctlProvider) NetworkFormats() NetworkFormats {
+	// the docker compatible network inspect output does not include the
+	// network options
+	return NetworkFormats{
+		Subnets: "{{range .IPAM.Config}}{{.Subnet}} {{.Gateway}};{{end}}",
+	}
+}
+
+func (nerdctlProvider) RemoteHost() string {
+	// nerdctl only drives the local containerd
+	return ""
+}
+
+func (p nerdctlProvider) Info() (ProviderInfo, error) {
+	// this is the containerd version, the docker compatible info does not
+	// include the containerd root
+	return providerInfo(p, "{{.ServerVersion}}\t\t{{.Architecture}}")
+}
+
+// providerInfo implements Provider.Info with the provider info command and
+// a format printing the version, the storage root and the architecture,
+// separated by tabs
+func providerInfo(p Provider, format string) (ProviderInfo, error) {
+	lines, err := exec.CombinedOutputLines(p.Command("info", "--format", format))
+	if err != nil {
+		return ProviderInfo{}, errors.Wrapf(err, "%s is not available", p.Name())
+	}
+	if len(lines) != 1 {
+		return ProviderInfo{}, fmt.Errorf("%s info should only be one line, got %d lines", p.Name(), len(lines))
+	}
+	fields := strings.Split(lines[0], "\t")
+	info := ProviderInfo{Version: strings.TrimPrefix(fields[0], "v")}
+	if len(fields) > 1 {
+		info.StorageRoot = fields[1]
+	}
+	if len(fields) > 2 {
+		info.Architecture = NormalizeArch(fields[2])
+	}
+	return info, nil
+}
+
+// remoteHost returns the host of a daemon endpoint URL if the daemon is
+// reached over the network, i.e. with tcp:// or ssh://, and empty otherwise
+func remoteHost(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	switch u.Scheme {
+	case "tcp", "ssh":
+		return u.Hostname()
+	}
+	return ""
+}
+
+// PublishedHost returns the host the container ports published on all the
+// addresses are reached at, this is localhost unless the current provider
+// runs the containers on a remote host, see Provider.RemoteHost
+func PublishedHost() string {
+	if host := CurrentProvider().RemoteHost(); host != "" {
+		return host
+	}
+	return "localhost"
+}
+
+var (
+	providerMu sync.Mutex
+	provider   Provider
+)
+
+// NewProvider returns the provider with the given name
+func NewProvider(name string) (Provider, error) {
+	switch name {
+	case DockerProviderName:
+		return dockerProvider{}, nil
+	case PodmanProviderName:
+		return podmanProvider{}, nil
+	case NerdctlProviderName:
+		return nerdctlProvider{}, nil
+	}
+	return nil, fmt.Errorf("unknown provider %q, must be one of [%s, %s, %s]", name, DockerProviderName, PodmanProviderName, NerdctlProviderName)
+}
+
+// SetProvider selects the provider with the given name for all the
+// following calls, see CurrentProvider
+func SetProvider(name string) error {
+	p, err := NewProvider(name)
+	if err != nil {
+		return err
+	}
+	providerMu.Lock()
+	defer providerMu.Unlock()
+	provider = p
+	return nil
+}
+
+// CurrentProvider returns the selected provider, unless selected with
+// SetProvider this is the provider named by ProviderEnv if set, and otherwise
+// the first available on the host of docker, podman and nerdctl, defaulting
+// to docker
+func CurrentProvider() Provider {
+	providerMu.Lock()
+	defer providerMu.Unlock()
+	if provider == nil {
+		provider = detectProvider()
+	}
+	return provider
+}
+
+// detectProvider implements CurrentProvider
+func detectProvider() Provider {
+	if name := os.Getenv(ProviderEnv); name != "" {
+		p, err := NewProvider(name)
+		if err == nil {
+			return p
+		}
+		logutil.Warnf("Ignoring %s: %v", ProviderEnv, err)
+	}
+	for _, p := range []Provider{dockerProvider{}, podmanProvider{}, nerdctlProvider{}} {
+		if p.Command("info").Run() == nil {
+			if p.Name() != DockerProviderName {
+				logutil.Infof("Using the %s provider, docker is not available", p.Name())
+			}
+			return p
+		}
+	}
+	// default to docker, so that errors are reported for it
+	return dockerProvider{}
+}
+
+// Command returns a new command running the CLI of the current provider
+// with args, see CurrentProvider
+func Command(args ...string) exec.Cmd {
+	return CurrentProvider().Command(args...)
+}
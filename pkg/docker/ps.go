@@ -0,0 +1,33 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// ListContainers returns the names of the containers, including stopped
+// containers, matching all of the given filters, as in `docker ps -a`
+// https://docs.docker.com/engine/reference/commandline/ps/#filtering
+func ListContainers(filters ...string) ([]string, error) {
+	args := []string{"ps", "-a", "--format={{.Names}}"}
+	for _, filter := range filters {
+		args = append(args, "--filter", filter)
+	}
+	cmd := Command(args...)
+	return exec.CombinedOutputLines(cmd)
+}
@@ -17,11 +17,12 @@ limitations under the License.
 package docker
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
-	log "github.com/sirupsen/logrus"
-
 	"sigs.k8s.io/kind/pkg/exec"
+	logutil "sigs.k8s.io/kind/pkg/log"
 )
 
 // PullIfNotPresent will pull an image if it is not present locally
@@ -30,34 +31,92 @@ import (
 func PullIfNotPresent(image string, retries int) (pulled bool, err error) {
 	// TODO(bentheelder): switch most (all) of the logging here to debug level
 	// once we have configurable log levels
-	// if this did not return an error, then the image exists locally
-	cmd := exec.Command("docker", "inspect", "--type=image", image)
-	if err := cmd.Run(); err == nil {
-		log.Infof("Image: %s present locally", image)
+	if ImageExists(image) {
+		logutil.Infof("Image: %s present locally", image)
 		return false, nil
 	}
 	// otherwise try to pull it
 	return true, Pull(image, retries)
 }
 
+// ImageExists returns true if the image is present locally
+func ImageExists(image string) bool {
+	// if this did not return an error, then the image exists locally
+	cmd := Command("inspect", "--type=image", image)
+	return cmd.Run() == nil
+}
+
+// ImageID returns the ID of the local image with the given name or ID, the
+// digest of the image config
+func ImageID(image string) (string, error) {
+	lines, err := exec.CombinedOutputLines(Command("inspect", "--type=image", "-f", "{{.Id}}", image))
+	if err != nil {
+		return "", err
+	}
+	if len(lines) != 1 {
+		return "", fmt.Errorf("image ID should only be one line, got %d lines", len(lines))
+	}
+	return lines[0], nil
+}
+
+// ImageDigest returns the digest identifying the local image with the given
+// name or ID: the digest it is referenced by if any, else its repository
+// digest if it was pulled or pushed, else its ID
+func ImageDigest(image string) (string, error) {
+	if i := strings.LastIndex(image, "@"); i >= 0 {
+		return image[i+1:], nil
+	}
+	lines, err := exec.CombinedOutputLines(Command("inspect", "--type=image", "-f", "{{join .RepoDigests \" \"}}", image))
+	if err != nil {
+		return "", err
+	}
+	if len(lines) != 1 {
+		return "", fmt.Errorf("image digests should only be one line, got %d lines", len(lines))
+	}
+	if digests := strings.Fields(lines[0]); len(digests) > 0 {
+		return digests[0][strings.LastIndex(digests[0], "@")+1:], nil
+	}
+	return ImageID(image)
+}
+
+// PullIfNotPresentForArch is like PullIfNotPresent, but pulls the image for
+// arch unless it is present locally for arch, see PullForArch
+func PullIfNotPresentForArch(image, arch string, retries int) (pulled bool, err error) {
+	if ImageExists(image) {
+		if imageArch, err := ImageArch(image); err == nil && (arch == "" || imageArch == arch) {
+			logutil.Infof("Image: %s present locally", image)
+			return false, nil
+		}
+	}
+	return true, PullForArch(image, arch, retries)
+}
+
 // Pull pulls an image, retrying up to retries times
 func Pull(image string, retries int) error {
-	log.Infof("Pulling image: %s ...", image)
-	err := exec.Command("docker", "pull", image).Run()
+	return PullForArch(image, "", retries)
+}
+
+// PullForArch pulls an image for the linux platform of arch, or of the host
+// if empty, retrying up to retries times
+func PullForArch(image, arch string, retries int) error {
+	logutil.Infof("Pulling image: %s ...", image)
+	args := append([]string{"pull"}, PlatformArgs(arch)...)
+	args = append(args, image)
+	err := Command(args...).Run()
 	// retry pulling up to retries times if necessary
 	if err != nil {
 		for i := 0; i < retries; i++ {
 			time.Sleep(time.Second * time.Duration(i+1))
-			log.WithError(err).Infof("Trying again to pull image: %s ...", image)
+			logutil.Infof("Trying again to pull image: %s ... (%v)", image, err)
 			// TODO(bentheelder): add some backoff / sleep?
-			err = exec.Command("docker", "pull", image).Run()
+			err = Command(args...).Run()
 			if err == nil {
 				break
 			}
 		}
 	}
 	if err != nil {
-		log.WithError(err).Infof("Failed to pull image: %s", image)
+		logutil.Infof("Failed to pull image: %s: %v", image, err)
 	}
 	return err
 }
@@ -0,0 +1,160 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// NormalizeArchive rewrites the config of the single image in the docker
+// image archive (tarball) at path for reproducible builds: the times the
+// image and its history were created are set to created, and the container
+// it was committed from is removed. The image ID, the digest of the config,
+// then only depends on the contents of the image
+func NormalizeArchive(path string, created time.Time) error {
+	contents, err := readArchiveFile(path, "manifest.json")
+	if err != nil {
+		return err
+	}
+	if contents == nil {
+		return fmt.Errorf("could not find image manifest")
+	}
+	var manifests []map[string]interface{}
+	if err := json.Unmarshal(contents, &manifests); err != nil {
+		return err
+	}
+	if len(manifests) != 1 {
+		return fmt.Errorf("expected one image in the archive, found %d", len(manifests))
+	}
+	configName, _ := manifests[0]["Config"].(string)
+	config, err := readArchiveFile(path, configName)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return fmt.Errorf("could not find image config %s", configName)
+	}
+	config, err = normalizeConfig(config, created)
+	if err != nil {
+		return err
+	}
+	// the config is named after its digest, either <hex>.json or
+	// blobs/sha256/<hex>
+	digest := sha256.Sum256(config)
+	newConfigName := filepath.Join(filepath.Dir(configName), hex.EncodeToString(digest[:])+filepath.Ext(configName))
+	manifests[0]["Config"] = newConfigName
+	manifest, err := json.Marshal(manifests)
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp := path + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	replace := map[string]archiveFile{
+		"manifest.json":            {"manifest.json", manifest},
+		filepath.Clean(configName): {newConfigName, config},
+	}
+	if err := replaceArchiveFiles(tar.NewReader(in), tar.NewWriter(out), replace); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// normalizeConfig sets the created times of the image config and its
+// history and removes the fields recording the container it was committed
+// from, which differ between builds
+func normalizeConfig(contents []byte, created time.Time) ([]byte, error) {
+	var config map[string]interface{}
+	if err := json.Unmarshal(contents, &config); err != nil {
+		return nil, err
+	}
+	timestamp := created.UTC().Format(time.RFC3339Nano)
+	config["created"] = timestamp
+	if history, ok := config["history"].([]interface{}); ok {
+		for _, h := range history {
+			if entry, ok := h.(map[string]interface{}); ok {
+				entry["created"] = timestamp
+			}
+		}
+	}
+	delete(config, "container")
+	delete(config, "container_config")
+	// the hostname defaults to the ID of the committed container
+	if c, ok := config["config"].(map[string]interface{}); ok {
+		c["Hostname"] = ""
+	}
+	return json.Marshal(config)
+}
+
+// archiveFile is a file to write to an archive
+type archiveFile struct {
+	Name     string
+	Contents []byte
+}
+
+// replaceArchiveFiles copies the archive in tr to tw, replacing the files
+// named by the keys of replace
+func replaceArchiveFiles(tr *tar.Reader, tw *tar.Writer, replace map[string]archiveFile) error {
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if file, ok := replace[filepath.Clean(hdr.Name)]; ok {
+			hdr.Name = file.Name
+			hdr.Size = int64(len(file.Contents))
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := tw.Write(file.Contents); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+// Delete deletes one or more containers and their anonymous volumes, even if
+// running, as in `docker rm -f -v`
+func Delete(containerNameOrIDs ...string) error {
+	cmd := Command(
+		append([]string{"rm", "-f", "-v"}, containerNameOrIDs...)...,
+	)
+	return cmd.Run()
+}
+
+// DeleteImages deletes one or more images, as in `docker rmi`
+func DeleteImages(images ...string) error {
+	cmd := Command(
+		append([]string{"rmi"}, images...)...,
+	)
+	return cmd.Run()
+}
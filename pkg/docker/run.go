@@ -20,9 +20,8 @@ import (
 	"fmt"
 	"regexp"
 
-	log "github.com/sirupsen/logrus"
-
 	"sigs.k8s.io/kind/pkg/exec"
+	logutil "sigs.k8s.io/kind/pkg/log"
 )
 
 // Docker container IDs are hex, more than one character, and on their own line
@@ -31,23 +30,34 @@ var containerIDRegex = regexp.MustCompile("^[a-f0-9]+$")
 // Run creates a container with "docker run", with some error handling
 // it will return the ID of the created container if any, even on error
 func Run(image string, runArgs []string, containerArgs []string) (id string, err error) {
-	args := []string{"run"}
-	args = append(args, runArgs...)
+	return newContainer("run", image, runArgs, containerArgs)
+}
+
+// Create creates a container with "docker create", without starting it,
+// with the same error handling as Run
+func Create(image string, createArgs []string, containerArgs []string) (id string, err error) {
+	return newContainer("create", image, createArgs, containerArgs)
+}
+
+// newContainer implements Run and Create
+func newContainer(command, image string, commandArgs []string, containerArgs []string) (id string, err error) {
+	args := []string{command}
+	args = append(args, commandArgs...)
 	args = append(args, image)
 	args = append(args, containerArgs...)
-	cmd := exec.Command("docker", args...)
+	cmd := Command(args...)
 	output, err := exec.CombinedOutputLines(cmd)
 	if err != nil {
 		// log error output if there was any
 		for _, line := range output {
-			log.Error(line)
+			logutil.Errorf("%s", line)
 		}
 		return "", err
 	}
 	// if docker created a container the id will be the first line and match
 	// validate the output and get the id
 	if len(output) < 1 {
-		return "", fmt.Errorf("failed to get container id, received no output from docker run")
+		return "", fmt.Errorf("failed to get container id, received no output from docker %s", command)
 	}
 	if !containerIDRegex.MatchString(output[0]) {
 		return "", fmt.Errorf("failed to get container id, output did not match: %v", output)
@@ -16,11 +16,23 @@ limitations under the License.
 
 package docker
 
-import (
-	"sigs.k8s.io/kind/pkg/exec"
-)
+import "io"
 
 // Save saves image to dest, as in `docker save`
 func Save(image, dest string) error {
-	return exec.Command("docker", "save", "-o", dest, image).Run()
+	return Command("save", "-o", dest, image).Run()
+}
+
+// SaveImages saves one or more images to a single archive at dest, as in
+// `docker save`
+func SaveImages(dest string, images ...string) error {
+	return Command(append([]string{"save", "-o", dest}, images...)...).Run()
+}
+
+// SaveImagesTo streams an archive of one or more images to w, as in
+// `docker save`, without writing it to disk
+func SaveImagesTo(w io.Writer, images ...string) error {
+	cmd := Command(append([]string{"save"}, images...)...)
+	cmd.SetStdout(w)
+	return cmd.Run()
 }
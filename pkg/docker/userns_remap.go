@@ -24,7 +24,7 @@ import (
 
 // UsernsRemap checks if userns-remap is enabled in dockerd
 func UsernsRemap() bool {
-	cmd := exec.Command("docker", "info", "--format", "'{{json .SecurityOptions}}'")
+	cmd := Command("info", "--format", "'{{json .SecurityOptions}}'")
 	lines, err := exec.CombinedOutputLines(cmd)
 	if err != nil {
 		return false
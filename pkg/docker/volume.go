@@ -0,0 +1,53 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"sigs.k8s.io/kind/pkg/exec"
+)
+
+// CreateVolume creates a named volume with the given labels, as in
+// `docker volume create`, this is a no-op if the volume already exists
+func CreateVolume(name string, labels ...string) error {
+	args := []string{"volume", "create"}
+	for _, label := range labels {
+		args = append(args, "--label", label)
+	}
+	args = append(args, name)
+	cmd := Command(args...)
+	return cmd.Run()
+}
+
+// ListVolumes returns the names of the volumes matching all of the given
+// filters, as in `docker volume ls`
+// https://docs.docker.com/engine/reference/commandline/volume_ls/#filtering
+func ListVolumes(filters ...string) ([]string, error) {
+	args := []string{"volume", "ls", "-q"}
+	for _, filter := range filters {
+		args = append(args, "--filter", filter)
+	}
+	cmd := Command(args...)
+	return exec.CombinedOutputLines(cmd)
+}
+
+// DeleteVolumes deletes one or more named volumes, as in `docker volume rm`
+func DeleteVolumes(names ...string) error {
+	cmd := Command(
+		append([]string{"volume", "rm", "-f"}, names...)...,
+	)
+	return cmd.Run()
+}
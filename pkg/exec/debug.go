@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"unicode/utf8"
+
+	logutil "sigs.k8s.io/kind/pkg/log"
+)
+
+// debugWriter logs the lines written to it at debug level, prefixed with the
+// command, binary output such as image archives is not logged
+type debugWriter struct {
+	mu      sync.Mutex
+	logger  logutil.Logger
+	command string
+	buff    bytes.Buffer
+	binary  bool
+}
+
+var _ io.Writer = &debugWriter{}
+
+func newDebugWriter(logger logutil.Logger, command string) *debugWriter {
+	return &debugWriter{
+		logger:  logger,
+		command: command,
+	}
+}
+
+// Write logs the complete lines of p, buffering the remainder
+func (w *debugWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.binary {
+		return len(p), nil
+	}
+	w.buff.Write(p)
+	for {
+		i := bytes.IndexByte(w.buff.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+		w.logLine(w.buff.Next(i + 1))
+	}
+	return len(p), nil
+}
+
+// Flush logs the remainder of the output, if any
+func (w *debugWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.buff.Len() > 0 && !w.binary {
+		w.logLine(w.buff.Next(w.buff.Len()))
+	}
+}
+
+func (w *debugWriter) logLine(line []byte) {
+	if !utf8.Valid(line) {
+		w.binary = true
+		w.buff.Reset()
+		w.logger.Debugf("[%s] (binary output not logged)", w.command)
+		return
+	}
+	w.logger.Debugf("[%s] %s", w.command, bytes.TrimRight(line, "\r\n"))
+}
+
+// teeWriter returns a writer writing to both w, if set, and debug
+func teeWriter(w io.Writer, debug io.Writer) io.Writer {
+	if w == nil {
+		return debug
+	}
+	return io.MultiWriter(w, debug)
+}
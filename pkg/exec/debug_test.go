@@ -0,0 +1,73 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type fakeLogger struct {
+	lines []string
+}
+
+func (l *fakeLogger) Debugf(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+func (l *fakeLogger) Infof(format string, args ...interface{})  {}
+func (l *fakeLogger) Warnf(format string, args ...interface{})  {}
+func (l *fakeLogger) Errorf(format string, args ...interface{}) {}
+
+func TestDebugWriter(t *testing.T) {
+	cases := []struct {
+		TestName string
+		Writes   []string
+		Expected []string
+	}{
+		{
+			TestName: "Lines split across writes",
+			Writes:   []string{"hello wo", "rld\nsecond", " line\r\nlast"},
+			Expected: []string{"[docker] hello world", "[docker] second line", "[docker] last"},
+		},
+		{
+			TestName: "Binary output is logged once",
+			Writes:   []string{"start\n", "\xff\xfe\n", "more\n"},
+			Expected: []string{"[docker] start", "[docker] (binary output not logged)"},
+		},
+		{
+			TestName: "No output",
+			Writes:   nil,
+			Expected: nil,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.TestName, func(t2 *testing.T) {
+			logger := &fakeLogger{}
+			w := newDebugWriter(logger, "docker")
+			for _, s := range tc.Writes {
+				if n, err := w.Write([]byte(s)); err != nil || n != len(s) {
+					t2.Fatalf("unexpected result writing %q: %d, %v", s, n, err)
+				}
+			}
+			w.Flush()
+			if !reflect.DeepEqual(logger.lines, tc.Expected) {
+				t2.Errorf("expected %q but got %q", tc.Expected, logger.lines)
+			}
+		})
+	}
+}
@@ -25,7 +25,7 @@ import (
 	"io"
 	"os"
 
-	log "github.com/sirupsen/logrus"
+	logutil "sigs.k8s.io/kind/pkg/log"
 )
 
 // Cmd abstracts over running a command somewhere, this is useful for testing
@@ -77,6 +77,14 @@ func InheritOutput(cmd Cmd) {
 	cmd.SetStdout(os.Stdout)
 }
 
+// SetSpanAttribute sets an attribute of the tracing span of cmd, if cmd is
+// traced, e.g. the node a command is run on
+func SetSpanAttribute(cmd Cmd, key, value string) {
+	if c, ok := cmd.(interface{ SetSpanAttribute(key, value string) }); ok {
+		c.SetSpanAttribute(key, value)
+	}
+}
+
 // RunLoggingOutputOnFail runs the cmd, logging error output if Run returns an error
 func RunLoggingOutputOnFail(cmd Cmd) error {
 	var buff bytes.Buffer
@@ -84,10 +92,10 @@ func RunLoggingOutputOnFail(cmd Cmd) error {
 	cmd.SetStderr(&buff)
 	err := cmd.Run()
 	if err != nil {
-		log.Error("failed with:")
+		logutil.Errorf("failed with:")
 		scanner := bufio.NewScanner(&buff)
 		for scanner.Scan() {
-			log.Error(scanner.Text())
+			logutil.Errorf("%s", scanner.Text())
 		}
 	}
 	return err
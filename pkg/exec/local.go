@@ -19,13 +19,16 @@ package exec
 import (
 	"io"
 	osexec "os/exec"
+	"strings"
 
-	log "github.com/sirupsen/logrus"
+	logutil "sigs.k8s.io/kind/pkg/log"
+	"sigs.k8s.io/kind/pkg/tracing"
 )
 
 // LocalCmd wraps os/exec.Cmd, implementing the kind/pkg/exec.Cmd interface
 type LocalCmd struct {
 	*osexec.Cmd
+	spanAttributes map[string]string
 }
 
 var _ Cmd = &LocalCmd{}
@@ -62,8 +65,32 @@ func (cmd *LocalCmd) SetStderr(w io.Writer) {
 	cmd.Stderr = w
 }
 
-// Run runs
-func (cmd *LocalCmd) Run() error {
-	log.Debugf("Running: %v %v", cmd.Path, cmd.Args)
-	return cmd.Cmd.Run()
+// SetSpanAttribute sets an attribute of the tracing span of the command
+func (cmd *LocalCmd) SetSpanAttribute(key, value string) {
+	if cmd.spanAttributes == nil {
+		cmd.spanAttributes = map[string]string{}
+	}
+	cmd.spanAttributes[key] = value
+}
+
+// Run runs, at debug level the command and its output are logged, and the
+// command is traced to the default tracer, if any
+func (cmd *LocalCmd) Run() (err error) {
+	span := tracing.Default().StartLeafSpan("exec", cmd.spanAttributes)
+	span.SetAttribute("kind.command", strings.Join(cmd.Args, " "))
+	defer func() { span.Finish(err) }()
+
+	logger := logutil.Default()
+	logger.Debugf("Running: %v %v", cmd.Path, cmd.Args)
+	if !logutil.DebugEnabled(logger) {
+		return cmd.Cmd.Run()
+	}
+	stdout := newDebugWriter(logger, cmd.Args[0])
+	stderr := newDebugWriter(logger, cmd.Args[0])
+	cmd.Stdout = teeWriter(cmd.Stdout, stdout)
+	cmd.Stderr = teeWriter(cmd.Stderr, stderr)
+	err = cmd.Cmd.Run()
+	stdout.Flush()
+	stderr.Flush()
+	return err
 }
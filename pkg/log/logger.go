@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package log
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Logger is a leveled logger, kind logs to the Default logger, and
+// cluster.Context logs its operations to its own logger, see
+// cluster.Context.SetLogger.
+// *logrus.Logger and *logrus.Entry implement Logger, other loggers can be
+// injected with a thin wrapper
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+var (
+	defaultLoggerMu sync.RWMutex
+	defaultLogger   Logger = logrus.StandardLogger()
+)
+
+// Default returns the default Logger, this is the logrus standard logger
+// unless set with SetDefault
+func Default() Logger {
+	defaultLoggerMu.RLock()
+	defer defaultLoggerMu.RUnlock()
+	return defaultLogger
+}
+
+// SetDefault sets the default Logger, which the packages not bound to a
+// cluster.Context log to, e.g. pkg/exec logs the commands kind runs to it
+func SetDefault(logger Logger) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	defaultLogger = logger
+}
+
+// DebugEnabled returns true if logger logs debug messages, this is used to
+// skip expensive debug logging. Loggers can report this by implementing
+// DebugEnabled() bool, the level of a *logrus.Logger is checked, and other
+// loggers are assumed to log debug messages
+func DebugEnabled(logger Logger) bool {
+	switch l := logger.(type) {
+	case interface{ DebugEnabled() bool }:
+		return l.DebugEnabled()
+	case *logrus.Logger:
+		return l.Level >= logrus.DebugLevel
+	}
+	return true
+}
+
+// Debugf logs a message at debug level to the Default logger
+func Debugf(format string, args ...interface{}) {
+	Default().Debugf(format, args...)
+}
+
+// Infof logs a message at info level to the Default logger
+func Infof(format string, args ...interface{}) {
+	Default().Infof(format, args...)
+}
+
+// Warnf logs a message at warning level to the Default logger
+func Warnf(format string, args ...interface{}) {
+	Default().Warnf(format, args...)
+}
+
+// Errorf logs a message at error level to the Default logger
+func Errorf(format string, args ...interface{}) {
+	Default().Errorf(format, args...)
+}
@@ -90,6 +90,14 @@ func (s *Status) MaybeWrapLogrus(logger *logrus.Logger) {
 	logger.SetOutput(s.MaybeWrapWriter(logger.Out))
 }
 
+// MaybeWrapLogger behaves like MaybeWrapLogrus if logger is a logrus logger,
+// other loggers are not wrapped
+func (s *Status) MaybeWrapLogger(logger Logger) {
+	if l, ok := logger.(*logrus.Logger); ok {
+		s.MaybeWrapLogrus(l)
+	}
+}
+
 // IsTerminal returns true if the writer w is a terminal
 func IsTerminal(w io.Writer) bool {
 	if v, ok := (w).(*os.File); ok {
@@ -0,0 +1,199 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Exporter exports the ended spans
+type Exporter interface {
+	Export(spans []*Span) error
+}
+
+// Tracer records spans, the span started last with StartSpan and not ended
+// yet is the parent of the spans started after it.
+// A nil *Tracer is valid and records nothing
+type Tracer struct {
+	mu       sync.Mutex
+	exporter Exporter
+	traceID  [16]byte
+	current  *Span
+	ended    []*Span
+}
+
+// Span is a timed step, e.g. an action, a task or a command
+type Span struct {
+	tracer     *Tracer
+	parent     *Span
+	Name       string
+	TraceID    [16]byte
+	SpanID     [8]byte
+	ParentID   [8]byte
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]string
+	// Error is the error the span ended with, if any
+	Error string
+}
+
+// NewTracer returns a new Tracer, exporting the spans of a single trace to
+// exporter on Flush
+func NewTracer(exporter Exporter) *Tracer {
+	t := &Tracer{
+		exporter: exporter,
+	}
+	rand.Read(t.traceID[:])
+	return t
+}
+
+var (
+	defaultTracerMu sync.RWMutex
+	defaultTracer   *Tracer
+)
+
+// Default returns the default Tracer, this is nil unless set with SetDefault
+func Default() *Tracer {
+	defaultTracerMu.RLock()
+	defer defaultTracerMu.RUnlock()
+	return defaultTracer
+}
+
+// SetDefault sets the default Tracer, which kind records its spans to
+func SetDefault(t *Tracer) {
+	defaultTracerMu.Lock()
+	defer defaultTracerMu.Unlock()
+	defaultTracer = t
+}
+
+// StartSpan starts a span, which is the parent of the spans started until
+// it is ended. This is meant for sequential steps, e.g. actions and tasks
+func (t *Tracer) StartSpan(name string, attributes map[string]string) *Span {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.newSpan(name, attributes)
+	t.current = s
+	return s
+}
+
+// StartLeafSpan starts a span which is never the parent of other spans, this
+// is safe to call concurrently e.g. for the commands kind runs
+func (t *Tracer) StartLeafSpan(name string, attributes map[string]string) *Span {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.newSpan(name, attributes)
+}
+
+func (t *Tracer) newSpan(name string, attributes map[string]string) *Span {
+	s := &Span{
+		tracer:     t,
+		parent:     t.current,
+		Name:       name,
+		TraceID:    t.traceID,
+		Start:      time.Now(),
+		Attributes: map[string]string{},
+	}
+	rand.Read(s.SpanID[:])
+	if s.parent != nil {
+		s.ParentID = s.parent.SpanID
+	}
+	for k, v := range attributes {
+		s.Attributes[k] = v
+	}
+	return s
+}
+
+// SetAttribute sets an attribute of the span
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+// Finish ends the span, recording err if not nil, if the span is already
+// ended this does nothing
+func (s *Span) Finish(err error) {
+	if s == nil {
+		return
+	}
+	t := s.tracer
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !s.End.IsZero() {
+		return
+	}
+	s.End = time.Now()
+	if err != nil {
+		s.Error = err.Error()
+	}
+	// the spans started in between and not ended are ended with their parent
+	for t.current != nil && t.current.isWithin(s) {
+		t.current = t.current.parent
+	}
+	t.ended = append(t.ended, s)
+}
+
+// isWithin returns true if s is ancestor or a descendant of ancestor
+func (s *Span) isWithin(ancestor *Span) bool {
+	for ; s != nil; s = s.parent {
+		if s == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// Flush exports the ended spans, in the order they started
+func (t *Tracer) Flush() error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	spans := t.ended
+	t.ended = nil
+	t.mu.Unlock()
+	if len(spans) == 0 {
+		return nil
+	}
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].Start.Before(spans[j].Start)
+	})
+	return t.exporter.Export(spans)
+}
+
+// hexID returns the OTLP encoding of a trace or span ID, empty if unset
+func hexID(id []byte) string {
+	for _, b := range id {
+		if b != 0 {
+			return hex.EncodeToString(id)
+		}
+	}
+	return ""
+}
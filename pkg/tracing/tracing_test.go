@@ -0,0 +1,170 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeExporter struct {
+	spans []*Span
+}
+
+func (e *fakeExporter) Export(spans []*Span) error {
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func TestTracerParents(t *testing.T) {
+	exporter := &fakeExporter{}
+	tracer := NewTracer(exporter)
+
+	root := tracer.StartSpan("root", nil)
+	action := tracer.StartSpan("action", map[string]string{"kind.action": "init"})
+	leaf := tracer.StartLeafSpan("exec", nil)
+	task := tracer.StartSpan("task", nil)
+	leaf.Finish(nil)
+	// the action is ended while the task is not, the task must not remain
+	// the parent of the following spans
+	action.Finish(errors.New("failed"))
+	next := tracer.StartSpan("next", nil)
+	next.Finish(nil)
+	root.Finish(nil)
+	root.Finish(errors.New("ended twice"))
+
+	if err := tracer.Flush(); err != nil {
+		t.Fatalf("unexpected error flushing: %v", err)
+	}
+	parents := map[string]*Span{
+		"root":   nil,
+		"action": root,
+		"exec":   action,
+		"next":   root,
+	}
+	if len(exporter.spans) != len(parents) {
+		t.Fatalf("expected %d spans but got %d", len(parents), len(exporter.spans))
+	}
+	for _, s := range exporter.spans {
+		parent, ok := parents[s.Name]
+		if !ok {
+			t.Errorf("unexpected span %s", s.Name)
+			continue
+		}
+		expected := [8]byte{}
+		if parent != nil {
+			expected = parent.SpanID
+		}
+		if s.ParentID != expected {
+			t.Errorf("unexpected parent of span %s", s.Name)
+		}
+		if s.TraceID != root.TraceID {
+			t.Errorf("span %s is not in the trace", s.Name)
+		}
+	}
+	if action.Error != "failed" || root.Error != "" {
+		t.Errorf("unexpected errors %q and %q", action.Error, root.Error)
+	}
+	if !task.End.IsZero() {
+		t.Errorf("the task span should not be ended")
+	}
+}
+
+func TestNilTracer(t *testing.T) {
+	var tracer *Tracer
+	span := tracer.StartSpan("span", nil)
+	span.SetAttribute("key", "value")
+	span.Finish(nil)
+	if err := tracer.Flush(); err != nil {
+		t.Errorf("unexpected error flushing: %v", err)
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var received otlpRequest
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	tracer := NewTracer(&OTLPExporter{
+		Endpoint:    server.URL + "/v1/traces",
+		Headers:     map[string]string{"Authorization": "Bearer token"},
+		ServiceName: "kind",
+	})
+	span := tracer.StartSpan("create cluster", map[string]string{"kind.cluster": "1"})
+	span.Finish(errors.New("boom"))
+	if err := tracer.Flush(); err != nil {
+		t.Fatalf("unexpected error exporting: %v", err)
+	}
+
+	if header != "Bearer token" {
+		t.Errorf("expected the configured header but got %q", header)
+	}
+	if len(received.ResourceSpans) != 1 || len(received.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected request %+v", received)
+	}
+	spans := received.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span but got %d", len(spans))
+	}
+	s := spans[0]
+	if s.Name != "create cluster" || len(s.TraceID) != 32 || len(s.SpanID) != 16 || s.ParentSpanID != "" {
+		t.Errorf("unexpected span %+v", s)
+	}
+	if s.Status.Code != otlpStatusCodeError || s.Status.Message != "boom" {
+		t.Errorf("unexpected status %+v", s.Status)
+	}
+	if len(s.Attributes) != 1 || s.Attributes[0].Key != "kind.cluster" || s.Attributes[0].Value.StringValue != "1" {
+		t.Errorf("unexpected attributes %+v", s.Attributes)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	for _, key := range []string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT", "OTEL_EXPORTER_OTLP_PROTOCOL", "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "OTEL_EXPORTER_OTLP_HEADERS", "OTEL_SERVICE_NAME"} {
+		t.Setenv(key, "")
+	}
+	if tracer, err := FromEnv(); tracer != nil || err != nil {
+		t.Errorf("expected no tracer without an endpoint, got %v, %v", tracer, err)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318/")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "a=b, c = d")
+	tracer, err := FromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exporter := tracer.exporter.(*OTLPExporter)
+	if exporter.Endpoint != "http://collector:4318/v1/traces" {
+		t.Errorf("unexpected endpoint %s", exporter.Endpoint)
+	}
+	if len(exporter.Headers) != 2 || exporter.Headers["c"] != "d" {
+		t.Errorf("unexpected headers %v", exporter.Headers)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "grpc")
+	if _, err := FromEnv(); err == nil {
+		t.Errorf("expected an error for the grpc protocol")
+	}
+}
@@ -50,7 +50,7 @@ func Flatten(errors Errors) Errors {
 	flat := []error{}
 	for _, err := range errors {
 		if v, ok := err.(Errors); ok {
-			flat = append(flat, Flatten(v))
+			flat = append(flat, Flatten(v)...)
 		} else {
 			flat = append(flat, err)
 		}
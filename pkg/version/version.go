@@ -0,0 +1,69 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package version contains the version of kind and of the things it defaults
+// to, for tools embedding or scripting kind to check compatibility against
+package version
+
+import (
+	"fmt"
+	"runtime"
+
+	"sigs.k8s.io/kind/pkg/cluster/config/encoding"
+	"sigs.k8s.io/kind/pkg/cluster/config/v1alpha3"
+)
+
+// Version is the kind version
+const Version = "0.1.0-alpha"
+
+// GitCommit is the git commit kind was built from, it is set at build time
+// with -ldflags "-X sigs.k8s.io/kind/pkg/version.GitCommit=$(git rev-parse HEAD)"
+var GitCommit = ""
+
+// DefaultNodeImage is the node image used when none is configured
+const DefaultNodeImage = v1alpha3.DefaultImage
+
+// ConfigAPIVersions returns the supported config API versions, most preferred
+// first, e.g. kind.sigs.k8s.io/v1alpha3
+func ConfigAPIVersions() []string {
+	versions := []string{}
+	for _, gv := range encoding.Scheme.PrioritizedVersionsForGroup(v1alpha3.GroupName) {
+		versions = append(versions, gv.String())
+	}
+	return versions
+}
+
+// Info is the build metadata of kind
+type Info struct {
+	Version           string   `json:"version"`
+	GitCommit         string   `json:"gitCommit"`
+	DefaultNodeImage  string   `json:"defaultNodeImage"`
+	ConfigAPIVersions []string `json:"configAPIVersions"`
+	GoVersion         string   `json:"goVersion"`
+	Platform          string   `json:"platform"`
+}
+
+// Get returns the build metadata of this kind binary
+func Get() Info {
+	return Info{
+		Version:           Version,
+		GitCommit:         GitCommit,
+		DefaultNodeImage:  DefaultNodeImage,
+		ConfigAPIVersions: ConfigAPIVersions(),
+		GoVersion:         runtime.Version(),
+		Platform:          fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+	}
+}
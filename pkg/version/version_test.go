@@ -0,0 +1,44 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"testing"
+)
+
+func TestConfigAPIVersions(t *testing.T) {
+	versions := ConfigAPIVersions()
+	expected := []string{"kind.sigs.k8s.io/v1alpha3", "kind.sigs.k8s.io/v1alpha2", "kind.sigs.k8s.io/v1alpha1"}
+	if len(versions) < len(expected) {
+		t.Fatalf("expected at least %v, got %v", expected, versions)
+	}
+	for i := range expected {
+		if versions[i] != expected[i] {
+			t.Errorf("expected %v first, got %v", expected, versions)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	info := Get()
+	if info.Version != Version {
+		t.Errorf("expected version %q, got %q", Version, info.Version)
+	}
+	if info.DefaultNodeImage == "" {
+		t.Error("expected a default node image")
+	}
+}